@@ -3,55 +3,120 @@
 // @description Digital Library API migrated from FastAPI to Go using Gin.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
 
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"maps"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	_ "github.com/iamdebopriya/fastapi-digital-library/digital-library-go/docs"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/accesslog"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/chaos"
 	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/delivery/http"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/delivery/sip2"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/email"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/federation"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/ipaccess"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/kafka"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/lock"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/lti"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/nats"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/oidc"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/openlibrary"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/redis"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/resilience"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/s3"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/sentry"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/shadow"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/stripe"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/twilio"
 	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/webpush"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-/*  GLOBAL TASK STATE  */
-var taskRunning = false
+/*  MIDDLEWARE: MAINTENANCE MODE  */
+// maintenanceExempt lists the writes allowed during maintenance, so that
+// admins can still sign in and turn it off.
+var maintenanceExempt = map[string]bool{
+	"/auth/login":        true,
+	"/auth/logout":       true,
+	"/auth/2fa/verify":   true,
+	"/admin/maintenance": true,
+}
 
-/*  MIDDLEWARE: WAIT IF TASK RUNNING  */
-func waitForTaskMiddleware() gin.HandlerFunc {
+func maintenanceMiddleware(uc *usecase.MaintenanceUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		for {
-			http.TaskMu.Lock()
-			running := taskRunning
-			http.TaskMu.Unlock()
-
-			if !running {
-				break
-			}
-			time.Sleep(200 * time.Millisecond)
+		switch c.Request.Method {
+		case "GET", "HEAD", "OPTIONS":
+			c.Next()
+			return
 		}
-		c.Next()
+		state := uc.Status()
+		if !state.Enabled || maintenanceExempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+		c.Header("Retry-After", strconv.Itoa(state.RetryAfter))
+		body := gin.H{"error": "the library is in maintenance mode; changes are not possible right now"}
+		if state.Message != "" {
+			body["message"] = state.Message
+		}
+		c.AbortWithStatusJSON(503, body)
 	}
 }
 
 /*  MIDDLEWARE: TIMING + USER-AGENT LOGGING  */
+// timingWriter sets X-Process-Time as a duration, e.g. 1.2ms, or in
+// seconds as the FastAPI service did, e.g. 0.0012.
 type timingWriter struct {
 	gin.ResponseWriter
-	start time.Time
+	start   time.Time
+	seconds bool
 }
 
 func (w timingWriter) WriteHeader(code int) {
 	duration := time.Since(w.start)
-	w.ResponseWriter.Header().Set("X-Process-Time", duration.String())
+	if w.seconds {
+		w.ResponseWriter.Header().Set("X-Process-Time", strconv.FormatFloat(duration.Seconds(), 'f', -1, 64))
+	} else {
+		w.ResponseWriter.Header().Set("X-Process-Time", duration.String())
+	}
 	w.ResponseWriter.WriteHeader(code)
 }
 
-func timingAndUserAgentMiddleware() gin.HandlerFunc {
+func timingAndUserAgentMiddleware(seconds bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
@@ -60,19 +125,130 @@ func timingAndUserAgentMiddleware() gin.HandlerFunc {
 		log.Printf("[LOG] Request received from: %s", userAgent)
 
 		// Wrap writer for X-Process-Time
-		c.Writer = timingWriter{ResponseWriter: c.Writer, start: start}
+		c.Writer = timingWriter{ResponseWriter: c.Writer, start: start, seconds: seconds}
 
 		c.Next()
 	}
 }
 
+/*  MIDDLEWARE: SHADOW TRAFFIC  */
+// recordingWriter keeps a copy of the response body, up to one byte past
+// shadow.MaxBody so that larger bodies are known to be too large.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w recordingWriter) Write(b []byte) (int, error) {
+	if room := shadow.MaxBody + 1 - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w recordingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// shadowMiddleware mirrors a sample of reads to the upstream of mirror,
+// once they are answered, to compare the answers. Without a mirror it
+// does nothing.
+func shadowMiddleware(mirror *shadow.Mirror) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mirror == nil || (c.Request.Method != "GET" && c.Request.Method != "HEAD") || !mirror.Sample() {
+			c.Next()
+			return
+		}
+		req := shadow.Request{Method: c.Request.Method, URL: c.Request.URL.RequestURI(), Header: c.Request.Header.Clone()}
+		w := recordingWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = w
+		c.Next()
+		mirror.Go(req, shadow.Response{Status: w.Status(), Body: w.body.Bytes()})
+	}
+}
+
+/*  MIDDLEWARE: FAULT INJECTION  */
+// chaosMiddleware delays and fails requests as the fault injection rules
+// say, marking each fault with X-Injected-Fault so clients can tell it
+// from a real one. Dropping a connection needs HTTP/1, which can hand the
+// connection over; otherwise the request is answered with 502.
+func chaosMiddleware(store *chaos.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !store.Enabled() {
+			c.Next()
+			return
+		}
+		action := store.Rules().Decide(c.Request.Method, c.Request.URL.Path)
+		if action.Delay > 0 {
+			c.Header("X-Injected-Fault", "latency")
+			select {
+			case <-time.After(action.Delay):
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+		}
+		switch {
+		case action.Drop:
+			if conn, _, err := c.Writer.Hijack(); err == nil {
+				conn.Close()
+				c.Abort()
+				return
+			}
+			c.Header("X-Injected-Fault", "drop")
+			c.AbortWithStatusJSON(502, gin.H{"error": "injected fault: connection dropped"})
+		case action.Status != 0:
+			c.Header("X-Injected-Fault", "status")
+			c.AbortWithStatusJSON(action.Status, gin.H{"error": fmt.Sprintf("injected fault: %d", action.Status)})
+		default:
+			c.Next()
+		}
+	}
+}
+
+/*  IP ACCESS CONTROL  */
+func ipAccessMiddleware(store *ipaccess.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !store.Rules().Allowed(c.Request.URL.Path, c.ClientIP()) {
+			c.AbortWithStatusJSON(403, gin.H{"error": "access denied from this address"})
+			return
+		}
+		c.Next()
+	}
+}
+
 /*  CORS  */
-func corsMiddleware() gin.HandlerFunc {
+// corsOrigins are the origins browsers may call the API from, "*" for
+// any. They are swapped when the configuration is reloaded.
+type corsOrigins struct {
+	origins atomic.Pointer[[]string]
+}
+
+func (o *corsOrigins) get() []string {
+	if origins := o.origins.Load(); origins != nil {
+		return *origins
+	}
+	return []string{"*"}
+}
+
+func (o *corsOrigins) set(origins []string) {
+	o.origins.Store(&origins)
+}
+
+func corsMiddleware(allowed *corsOrigins) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
+		if origins := allowed.get(); slices.Contains(origins, "*") {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			// Answers differ by origin, so caches must keep them apart.
+			c.Header("Vary", "Origin")
+			if origin := c.GetHeader("Origin"); slices.Contains(origins, origin) {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type")
-		c.Header("Access-Control-Expose-Headers", "X-Process-Time")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+		c.Header("Access-Control-Expose-Headers", "X-Process-Time, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-RateLimit-Resource, Retry-After")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -82,24 +258,1344 @@ func corsMiddleware() gin.HandlerFunc {
 	}
 }
 
+/*  REQUEST LOG  */
+// requestLog logs requests as gin.Logger does, leaving out those below
+// the log level in force: warn logs only 4xx and 5xx answers, error only
+// 5xx.
+func requestLog(level *atomic.Value) gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{Skip: func(c *gin.Context) bool {
+		switch level.Load() {
+		case domain.LogLevelWarn:
+			return c.Writer.Status() < 400
+		case domain.LogLevelError:
+			return c.Writer.Status() < 500
+		}
+		return false
+	}})
+}
+
+/*  ACCESS LOG  */
+// accessLogFromEnv opens the access log ACCESS_LOG names: stdout, or a
+// file rotated once it reaches ACCESS_LOG_MAX_SIZE megabytes (100 by
+// default), keeping ACCESS_LOG_MAX_BACKUPS old files (5). Successful
+// reads are logged at the rate ACCESS_LOG_SAMPLE (1, all of them) or
+// the rate of their route in ACCESS_LOG_SAMPLE_ROUTES, e.g.
+// "GET /books=0.1". Health checks are left out unless
+// ACCESS_LOG_EXCLUDE_HEALTH is false. There is no access log when
+// ACCESS_LOG is not set; the file, if any, is returned to be closed.
+func accessLogFromEnv() (*accesslog.Logger, io.Closer) {
+	target := os.Getenv("ACCESS_LOG")
+	if target == "" {
+		return nil, nil
+	}
+	sample, err := accesslog.ParseRate(getenv("ACCESS_LOG_SAMPLE", "1"))
+	if err != nil {
+		log.Fatal("Invalid ACCESS_LOG_SAMPLE: ", err)
+	}
+	routes, err := accesslog.ParseRoutes(os.Getenv("ACCESS_LOG_SAMPLE_ROUTES"))
+	if err != nil {
+		log.Fatal("Invalid ACCESS_LOG_SAMPLE_ROUTES: ", err)
+	}
+	excludeHealth, err := strconv.ParseBool(getenv("ACCESS_LOG_EXCLUDE_HEALTH", "true"))
+	if err != nil {
+		log.Fatal("Invalid ACCESS_LOG_EXCLUDE_HEALTH: ", os.Getenv("ACCESS_LOG_EXCLUDE_HEALTH"))
+	}
+	config := accesslog.Config{Sample: sample, Routes: routes}
+	if excludeHealth {
+		config.Exclude = []string{"/healthz", "/readyz"}
+	}
+
+	if target == "stdout" {
+		return accesslog.New(os.Stdout, config), nil
+	}
+	maxSize, err := strconv.Atoi(getenv("ACCESS_LOG_MAX_SIZE", "100"))
+	if err != nil || maxSize < 1 {
+		log.Fatal("Invalid ACCESS_LOG_MAX_SIZE: ", os.Getenv("ACCESS_LOG_MAX_SIZE"))
+	}
+	backups, err := strconv.Atoi(getenv("ACCESS_LOG_MAX_BACKUPS", "5"))
+	if err != nil || backups < 0 {
+		log.Fatal("Invalid ACCESS_LOG_MAX_BACKUPS: ", os.Getenv("ACCESS_LOG_MAX_BACKUPS"))
+	}
+	f, err := accesslog.OpenFile(target, int64(maxSize)<<20, backups)
+	if err != nil {
+		log.Fatal("Invalid ACCESS_LOG: ", err)
+	}
+	return accesslog.New(f, config), f
+}
+
+/*  CONFIG  */
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// splitList splits a comma-separated env value, dropping empty items.
+func splitList(v string) []string {
+	items := []string{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// oidcProvidersFromEnv reads OIDC_PROVIDERS (e.g. "google,campus") and,
+// for each name, OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and
+// _REDIRECT_URL.
+func oidcProvidersFromEnv(breakers *resilience.Registry) []usecase.IdentityProvider {
+	providers := []usecase.IdentityProvider{}
+	for _, name := range splitList(os.Getenv("OIDC_PROVIDERS")) {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Client:       breakers.Breaker("oidc:"+name, resilience.DefaultPolicy).Client(),
+		}))
+	}
+	return providers
+}
+
+// ltiPlatformsFromEnv reads LTI_PLATFORMS (e.g. "moodle,canvas") and, for
+// each name, LTI_<NAME>_ISSUER, _CLIENT_ID, _AUTH_URL, _JWKS_URL and
+// optionally _DEPLOYMENT_IDS, as shown on the platform's tool registration.
+func ltiPlatformsFromEnv(breakers *resilience.Registry) []usecase.LTIPlatform {
+	platforms := []usecase.LTIPlatform{}
+	for _, name := range splitList(os.Getenv("LTI_PLATFORMS")) {
+		prefix := "LTI_" + strings.ToUpper(name) + "_"
+		platforms = append(platforms, lti.NewPlatform(lti.Config{
+			Name:          name,
+			Issuer:        os.Getenv(prefix + "ISSUER"),
+			ClientID:      os.Getenv(prefix + "CLIENT_ID"),
+			AuthURL:       os.Getenv(prefix + "AUTH_URL"),
+			JWKSURL:       os.Getenv(prefix + "JWKS_URL"),
+			DeploymentIDs: splitList(os.Getenv(prefix + "DEPLOYMENT_IDS")),
+			Client:        breakers.Breaker("lti:"+name, resilience.DefaultPolicy).Client(),
+		}))
+	}
+	return platforms
+}
+
+// integrationsFromEnv reads INTEGRATIONS (e.g. "lobby_kiosk,branch_kiosk")
+// and, for each name, the shared secret in INTEGRATION_<NAME>_SECRET.
+func integrationsFromEnv() map[string]string {
+	secrets := map[string]string{}
+	for _, name := range splitList(os.Getenv("INTEGRATIONS")) {
+		secret := os.Getenv("INTEGRATION_" + strings.ToUpper(name) + "_SECRET")
+		if secret == "" {
+			log.Fatal("Missing secret for integration ", name)
+		}
+		secrets[name] = secret
+	}
+	return secrets
+}
+
+// ncipPartnersFromEnv reads NCIP_PARTNERS, the agency IDs of consortium
+// partners (e.g. "cityuni,county"), and for each the secret it sends as
+// FromAgencyAuthentication, in NCIP_PARTNER_<AGENCY>_SECRET.
+func ncipPartnersFromEnv() map[string]string {
+	partners := map[string]string{}
+	for _, agency := range splitList(os.Getenv("NCIP_PARTNERS")) {
+		secret := os.Getenv("NCIP_PARTNER_" + strings.ToUpper(agency) + "_SECRET")
+		if secret == "" {
+			log.Fatal("Missing secret for NCIP partner ", agency)
+		}
+		partners[agency] = secret
+	}
+	return partners
+}
+
+// federationFromEnv reads FEDERATION_PARTNERS, the names of consortium
+// partners running this server (e.g. "county,cityuni"), and for each
+// FEDERATION_<NAME>_URL, the NCIP secret it expects in _SECRET, this
+// library's card number there in _ACCOUNT and optionally its NCIP agency
+// ID in _AGENCY_ID, the name by default. FEDERATION_TIMEOUT bounds how
+// long searches wait for partners.
+func federationFromEnv(breakers *resilience.Registry, agencyID string, books *usecase.BookUsecase, members *usecase.MemberUsecase) *usecase.FederationUsecase {
+	partners := []usecase.FederationPartner{}
+	for _, name := range splitList(os.Getenv("FEDERATION_PARTNERS")) {
+		prefix := "FEDERATION_" + strings.ToUpper(name) + "_"
+		for _, key := range []string{"URL", "SECRET", "ACCOUNT"} {
+			if os.Getenv(prefix+key) == "" {
+				log.Fatal("Missing ", prefix+key, " for federation partner ", name)
+			}
+		}
+		partners = append(partners, federation.NewPartner(federation.Config{
+			Name:            name,
+			BaseURL:         os.Getenv(prefix + "URL"),
+			AgencyID:        agencyID,
+			PartnerAgencyID: getenv(prefix+"AGENCY_ID", name),
+			Secret:          os.Getenv(prefix + "SECRET"),
+			Account:         os.Getenv(prefix + "ACCOUNT"),
+			Client:          breakers.Breaker("federation:"+name, resilience.DefaultPolicy).Client(),
+		}))
+	}
+	timeout, err := time.ParseDuration(getenv("FEDERATION_TIMEOUT", usecase.DefaultFederationTimeout.String()))
+	if err != nil || timeout <= 0 {
+		log.Fatal("Invalid FEDERATION_TIMEOUT: ", os.Getenv("FEDERATION_TIMEOUT"))
+	}
+	return usecase.NewFederationUsecase(partners, books, members, timeout)
+}
+
+// sip2ConfigFromEnv describes the library to self-check machines: the
+// institution ID in SIP2_INSTITUTION, the name shown in SIP2_LIBRARY_NAME
+// and the login machines must use, SIP2_LOGIN_USER and SIP2_LOGIN_PASSWORD,
+// without which the server does not start.
+func sip2ConfigFromEnv() sip2.Config {
+	if os.Getenv("SIP2_LOGIN_USER") == "" || os.Getenv("SIP2_LOGIN_PASSWORD") == "" {
+		log.Fatal("SIP2_ADDR requires SIP2_LOGIN_USER and SIP2_LOGIN_PASSWORD")
+	}
+	return sip2.Config{
+		Institution:   getenv("SIP2_INSTITUTION", "library"),
+		LibraryName:   getenv("SIP2_LIBRARY_NAME", "Digital Library"),
+		LoginUser:     os.Getenv("SIP2_LOGIN_USER"),
+		LoginPassword: os.Getenv("SIP2_LOGIN_PASSWORD"),
+	}
+}
+
+// notificationChannelsFromEnv configures email delivery when SMTP_ADDR
+// is set, with SMTP_FROM and optionally SMTP_USERNAME and SMTP_PASSWORD.
+func notificationChannelsFromEnv(templates *usecase.TemplateUsecase) []usecase.Channel {
+	channels := []usecase.Channel{}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		channels = append(channels, email.NewSender(addr, getenv("SMTP_FROM", "library@localhost"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), templates))
+	}
+	return channels
+}
+
+// smsFromEnv configures text messages through Twilio when
+// TWILIO_ACCOUNT_SID is set, with TWILIO_AUTH_TOKEN, the sender in
+// TWILIO_FROM (a number or messaging service ID) and the public URL of
+// the status webhook in TWILIO_STATUS_CALLBACK_URL.
+func smsFromEnv(breakers *resilience.Registry, templates *usecase.TemplateUsecase) (*usecase.SMSUsecase, *twilio.Client) {
+	sid := os.Getenv("TWILIO_ACCOUNT_SID")
+	if sid == "" {
+		return nil, nil
+	}
+	settings := domain.SMSSettings{From: os.Getenv("TWILIO_FROM")}
+	if err := settings.Validate(); err != nil {
+		log.Fatal("Invalid TWILIO_FROM: ", err)
+	}
+	client := twilio.NewClient(
+		getenv("TWILIO_URL", twilio.DefaultBaseURL),
+		sid,
+		os.Getenv("TWILIO_AUTH_TOKEN"),
+		os.Getenv("TWILIO_STATUS_CALLBACK_URL"),
+		breakers.Breaker("twilio", resilience.DefaultPolicy).Client(),
+	)
+	return usecase.NewSMSUsecase(client, templates, settings), client
+}
+
+// paymentsFromEnv takes fine payments through Stripe when
+// STRIPE_SECRET_KEY is set, checking webhook events against
+// STRIPE_WEBHOOK_SECRET, without which the server does not start. For
+// development, PAYMENTS_MOCK=true puts a mock in Stripe's place, whose
+// payments are settled by posting events signed with
+// STRIPE_WEBHOOK_SECRET to the webhook. With neither, fines are not paid
+// online. PAYMENTS_CURRENCY is the currency fines are charged in.
+func paymentsFromEnv(breakers *resilience.Registry, fines *usecase.FineUsecase) (*usecase.PaymentUsecase, http.PaymentEventParser) {
+	mock, err := strconv.ParseBool(getenv("PAYMENTS_MOCK", "false"))
+	if err != nil {
+		log.Fatal("Invalid PAYMENTS_MOCK: ", os.Getenv("PAYMENTS_MOCK"))
+	}
+	key := os.Getenv("STRIPE_SECRET_KEY")
+	if key == "" && !mock {
+		return nil, nil
+	}
+	currency := strings.ToLower(getenv("PAYMENTS_CURRENCY", "usd"))
+	secret := os.Getenv("STRIPE_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("STRIPE_WEBHOOK_SECRET is required to take payments")
+	}
+	if mock {
+		log.Println("PAYMENTS_MOCK set: fine payments go through a mock provider and nothing is charged")
+		provider := stripe.NewMock(secret)
+		return usecase.NewPaymentUsecase(provider, fines, currency), provider
+	}
+	client := stripe.NewClient(
+		getenv("STRIPE_URL", stripe.DefaultBaseURL),
+		key,
+		secret,
+		breakers.Breaker("stripe", resilience.DefaultPolicy).Client(),
+	)
+	return usecase.NewPaymentUsecase(client, fines, currency), client
+}
+
+// reporterFromEnv reports panics and server errors to Sentry when
+// SENTRY_DSN is set, tagged with SENTRY_ENVIRONMENT (production by
+// default), the instance and SENTRY_RELEASE, which defaults to the
+// version the server was built from.
+func reporterFromEnv(breakers *resilience.Registry, instance string) http.ErrorReporter {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return nil
+	}
+	client, err := sentry.NewClient(
+		dsn,
+		getenv("SENTRY_RELEASE", buildVersion()),
+		getenv("SENTRY_ENVIRONMENT", "production"),
+		instance,
+		breakers.Breaker("sentry", resilience.DefaultPolicy).Client(),
+	)
+	if err != nil {
+		log.Fatal("Invalid SENTRY_DSN: ", err)
+	}
+	return client
+}
+
+// The version, commit and build date of the server, set when it is built
+// with e.g. -ldflags "-X main.version=1.4.0 -X main.commit=$(git rev-parse
+// HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)".
+var (
+	version   = devVersion
+	commit    string
+	buildDate string
+)
+
+// devVersion is the version of builds that were not given one.
+const devVersion = "0.0.0-dev"
+
+// buildInfo describes the build of the server. Without ldflags, the
+// commit and its date come from the git checkout it was built from, if
+// any, and the version from the module when it was installed at one.
+func buildInfo() domain.BuildInfo {
+	b := domain.BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	if b.Version == devVersion && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		b.Version = strings.TrimPrefix(info.Main.Version, "v")
+	}
+	for _, s := range info.Settings {
+		switch {
+		case s.Key == "vcs.revision" && b.Commit == "":
+			b.Commit = s.Value
+		case s.Key == "vcs.time" && b.BuildDate == "":
+			b.BuildDate = s.Value
+		}
+	}
+	return b
+}
+
+// buildVersion is the version the server was built as, or the commit of
+// a development build.
+func buildVersion() string {
+	b := buildInfo()
+	if b.Version != devVersion || b.Commit == "" {
+		return b.Version
+	}
+	return b.Commit
+}
+
+// mirrorFromEnv mirrors SHADOW_PERCENT (1 by default) percent of reads
+// to SHADOW_UPSTREAM when it is set, e.g. the FastAPI service, ignoring
+// the fields in SHADOW_IGNORE_FIELDS when comparing the answers. The
+// upstream gets no circuit breaker: its failures are differences to log,
+// and retries would mirror a request twice.
+func mirrorFromEnv() *shadow.Mirror {
+	upstream := os.Getenv("SHADOW_UPSTREAM")
+	if upstream == "" {
+		return nil
+	}
+	percent, err := strconv.ParseFloat(getenv("SHADOW_PERCENT", "1"), 64)
+	if err != nil {
+		log.Fatal("Invalid SHADOW_PERCENT: ", os.Getenv("SHADOW_PERCENT"))
+	}
+	mirror, err := shadow.NewMirror(upstream, percent, splitList(os.Getenv("SHADOW_IGNORE_FIELDS")), &stdhttp.Client{Timeout: 10 * time.Second})
+	if err != nil {
+		log.Fatal("Invalid SHADOW_UPSTREAM or SHADOW_PERCENT: ", err)
+	}
+	return mirror
+}
+
+// fastapiCompatFromEnv reports whether FASTAPI_COMPAT asks for the
+// routes of the FastAPI service to answer as it did, for its clients.
+func fastapiCompatFromEnv() bool {
+	v := getenv("FASTAPI_COMPAT", "false")
+	compat, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatal("Invalid FASTAPI_COMPAT: ", v)
+	}
+	return compat
+}
+
+// tasksFromEnv configures the task queue:
+//   - TASK_WORKERS workers, 4 by default;
+//   - for each kind of task, TASK_CONCURRENCY_<KIND> of them at once,
+//     e.g. TASK_CONCURRENCY_CATALOG_IMPORT=2, 1 by default;
+//   - TASK_MAX_WAIT, 5m by default, after which a scheduled task goes
+//     before those users started;
+//   - TASK_TIMEOUT, 30m by default, or TASK_TIMEOUT_<KIND>, after which
+//     the watchdog gives up on a task, and TASK_RETRIES_<KIND>, how many
+//     times it is then queued again, 0 by default.
+//
+// Tasks the watchdog gives up on are logged and, if configured, reported
+// to Sentry.
+func tasksFromEnv(reporter http.ErrorReporter) *usecase.TaskUsecase {
+	config := usecase.TaskConfig{
+		Concurrency: map[string]int{},
+		Timeouts:    maps.Clone(usecase.DefaultTaskTimeouts),
+		Retries:     map[string]int{},
+	}
+	count := func(key, fallback string, least int) int {
+		v := getenv(key, fallback)
+		n, err := strconv.Atoi(v)
+		if err != nil || n < least {
+			log.Fatal("Invalid "+key+": ", v)
+		}
+		return n
+	}
+	duration := func(key, fallback string) time.Duration {
+		v := getenv(key, fallback)
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatal("Invalid "+key+": ", v)
+		}
+		return d
+	}
+
+	config.Workers = count("TASK_WORKERS", "4", 1)
+	config.MaxWait = duration("TASK_MAX_WAIT", "5m")
+	config.Timeout = duration("TASK_TIMEOUT", "30m")
+	for _, kind := range domain.TaskKinds {
+		suffix := "_" + strings.ToUpper(kind)
+		if os.Getenv("TASK_CONCURRENCY"+suffix) != "" {
+			config.Concurrency[kind] = count("TASK_CONCURRENCY"+suffix, "", 1)
+		}
+		if os.Getenv("TASK_TIMEOUT"+suffix) != "" {
+			config.Timeouts[kind] = duration("TASK_TIMEOUT"+suffix, "")
+		}
+		if os.Getenv("TASK_RETRIES"+suffix) != "" {
+			config.Retries[kind] = count("TASK_RETRIES"+suffix, "", 0)
+		}
+	}
+
+	config.Alert = func(task domain.Task) {
+		message := fmt.Sprintf("Task %d (%s) %s on attempt %d", task.ID, task.Kind, task.Error, task.Attempts)
+		if task.Status == domain.TaskQueued {
+			message += ", queued again"
+		}
+		log.Println("[ALERT]", message)
+		if reporter == nil {
+			return
+		}
+		kind := "TaskTimeout"
+		if strings.HasPrefix(task.Error, "panicked") {
+			kind = "TaskPanic"
+		}
+		report := domain.ErrorReport{Level: domain.ReportError, Message: message, Type: kind, Route: "task " + task.Kind, Time: time.Now()}
+		go func() {
+			if _, err := reporter.Report(context.Background(), report); err != nil {
+				log.Printf("error report for task %d failed: %v", task.ID, err)
+			}
+		}()
+	}
+	return usecase.NewTaskUsecase(config)
+}
+
+// triggerLimitsFromEnv reads how often external systems may trigger each
+// task type, as a count per period such as "10/1h": TASK_TRIGGER_LIMIT for
+// every type, 10/1h by default, and TASK_TRIGGER_LIMIT_<TYPE> for one.
+func triggerLimitsFromEnv() (usecase.TriggerLimit, map[string]usecase.TriggerLimit) {
+	parse := func(key, fallback string) usecase.TriggerLimit {
+		v := getenv(key, fallback)
+		count, per, _ := strings.Cut(v, "/")
+		n, err := strconv.Atoi(count)
+		d, derr := time.ParseDuration(per)
+		if err != nil || derr != nil || n < 1 || d <= 0 {
+			log.Fatal("Invalid "+key+": ", v)
+		}
+		return usecase.TriggerLimit{Count: n, Per: d}
+	}
+
+	limits := map[string]usecase.TriggerLimit{}
+	for _, kind := range domain.TaskKinds {
+		key := "TASK_TRIGGER_LIMIT_" + strings.ToUpper(kind)
+		if os.Getenv(key) != "" {
+			limits[kind] = parse(key, "")
+		}
+	}
+	return parse("TASK_TRIGGER_LIMIT", "10/1h"), limits
+}
+
+// notifierFromEnv starts NOTIFY_WORKERS workers, 4 by default, to send the
+// notifications for new books, queueing up to NOTIFY_QUEUE_SIZE, 256 by
+// default. NOTIFY_OVERFLOW says what to do when the queue is full: drop
+// the new notification (drop, the default), the oldest (drop_oldest), or
+// make the request wait (block).
+func notifierFromEnv() *usecase.BookNotifier {
+	workers, err := strconv.Atoi(getenv("NOTIFY_WORKERS", "4"))
+	if err != nil || workers < 1 {
+		log.Fatal("Invalid NOTIFY_WORKERS: ", os.Getenv("NOTIFY_WORKERS"))
+	}
+	size, err := strconv.Atoi(getenv("NOTIFY_QUEUE_SIZE", "256"))
+	if err != nil || size < 1 {
+		log.Fatal("Invalid NOTIFY_QUEUE_SIZE: ", os.Getenv("NOTIFY_QUEUE_SIZE"))
+	}
+	overflow := getenv("NOTIFY_OVERFLOW", domain.OverflowDrop)
+	switch overflow {
+	case domain.OverflowDrop, domain.OverflowDropOldest, domain.OverflowBlock:
+	default:
+		log.Fatal("Invalid NOTIFY_OVERFLOW: ", overflow)
+	}
+	return usecase.NewBookNotifier(workers, size, overflow)
+}
+
+// snapshotDirFromEnv is where heap snapshots are written: DEBUG_SNAPSHOT_DIR,
+// or the temporary directory.
+func snapshotDirFromEnv() string {
+	dir := getenv("DEBUG_SNAPSHOT_DIR", os.TempDir())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		log.Fatal("Invalid DEBUG_SNAPSHOT_DIR: ", err)
+	}
+	return dir
+}
+
+// slosFromEnv reads the latency objectives per route from the JSON file
+// at SLO_FILE, if set, and the window they are measured over from
+// SLO_WINDOW, 30 days by default.
+func slosFromEnv() ([]domain.SLO, time.Duration) {
+	window, err := time.ParseDuration(getenv("SLO_WINDOW", "720h"))
+	if err != nil || window < time.Hour {
+		log.Fatal("Invalid SLO_WINDOW: ", os.Getenv("SLO_WINDOW"))
+	}
+	path := os.Getenv("SLO_FILE")
+	if path == "" {
+		return nil, window
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("Invalid SLO_FILE: ", err)
+	}
+	slos, err := domain.ParseSLOs(data)
+	if err != nil {
+		log.Fatal("Invalid SLO_FILE: ", err)
+	}
+	return slos, window
+}
+
+// lockerFromEnv shares job locks between instances through Redis when
+// LOCK_REDIS_URL is set, e.g. redis://:secret@redis:6379/0, and keeps
+// them in this process otherwise.
+func lockerFromEnv() lock.Locker {
+	addr := os.Getenv("LOCK_REDIS_URL")
+	if addr == "" {
+		return lock.NewMemory()
+	}
+	client, err := redis.NewClient(addr)
+	if err != nil {
+		log.Fatal("Invalid LOCK_REDIS_URL: ", err)
+	}
+	return lock.NewRedis(client)
+}
+
+// publisherFromEnv sends catalog events to a broker when EVENTS_BROKER is
+// nats or kafka. EVENTS_URL is the NATS server (nats://host:4222) or
+// the Kafka REST Proxy (http://host:8082). EVENTS_TOPIC is the subject
+// prefix for NATS and the topic for Kafka.
+func publisherFromEnv(breakers *resilience.Registry) usecase.EventPublisher {
+	switch broker := os.Getenv("EVENTS_BROKER"); broker {
+	case "":
+		return nil
+	case "nats":
+		client, err := nats.NewClient(getenv("EVENTS_URL", "nats://localhost:4222"))
+		if err != nil {
+			log.Fatal("Invalid EVENTS_URL: ", err)
+		}
+		return usecase.NewNATSPublisher(client, getenv("EVENTS_TOPIC", "library"))
+	case "kafka":
+		client := kafka.NewClient(
+			getenv("EVENTS_URL", "http://localhost:8082"),
+			breakers.Breaker("kafka", resilience.DefaultPolicy).Client(),
+		)
+		return usecase.NewKafkaPublisher(client, getenv("EVENTS_TOPIC", "library.catalog"))
+	default:
+		log.Fatal("EVENTS_BROKER must be nats or kafka, not ", broker)
+		return nil
+	}
+}
+
+// commandQueueFromEnv consumes book commands from COMMANDS_TOPIC on the
+// EVENTS_BROKER at EVENTS_URL when both are set. Messages that cannot be
+// applied go to COMMANDS_DEAD_LETTER_TOPIC. Every instance keeps its own
+// catalog, so each reads all commands: on Kafka in a consumer group named
+// after the instance unless COMMANDS_GROUP is set.
+func commandQueueFromEnv(breakers *resilience.Registry, instance string) usecase.CommandQueue {
+	topic := os.Getenv("COMMANDS_TOPIC")
+	if topic == "" {
+		return nil
+	}
+	deadLetters := getenv("COMMANDS_DEAD_LETTER_TOPIC", topic+".dead")
+	switch broker := os.Getenv("EVENTS_BROKER"); broker {
+	case "nats":
+		client, err := nats.NewClient(getenv("EVENTS_URL", "nats://localhost:4222"))
+		if err != nil {
+			log.Fatal("Invalid EVENTS_URL: ", err)
+		}
+		return usecase.NewNATSCommandQueue(client, topic, deadLetters)
+	case "kafka":
+		client := kafka.NewClient(
+			getenv("EVENTS_URL", "http://localhost:8082"),
+			breakers.Breaker("kafka-commands", resilience.DefaultPolicy).Client(),
+		)
+		group := getenv("COMMANDS_GROUP", "digital-library-"+instance)
+		return usecase.NewKafkaCommandQueue(client, group, instance, topic, deadLetters)
+	default:
+		log.Fatal("COMMANDS_TOPIC needs EVENTS_BROKER set to nats or kafka")
+		return nil
+	}
+}
+
+// bootstrapAdmin creates the first admin account when ADMIN_PASSWORD is
+// set, since roles can only be granted by an existing admin.
+func bootstrapAdmin(members *usecase.MemberUsecase, plans *usecase.PlanUsecase) {
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		return
+	}
+	plan, err := plans.GetPlanByName("adult")
+	if err != nil {
+		log.Fatal("Admin bootstrap failed: ", err)
+	}
+	admin := domain.Member{Name: "Administrator", PlanID: plan.ID, Password: password}
+	if err := admin.Validate(); err != nil {
+		log.Fatal("Admin bootstrap failed: ", err)
+	}
+	admin, err = members.CreateMember(admin)
+	if err != nil {
+		log.Fatal("Admin bootstrap failed: ", err)
+	}
+	if _, err := members.SetRole(admin.ID, domain.RoleAdmin); err != nil {
+		log.Fatal("Admin bootstrap failed: ", err)
+	}
+	log.Println("Admin account created with card number", admin.CardNumber)
+}
+
+/*  ACCOUNT DELETION SWEEP  */
+func purgeDeletedAccounts(uc *usecase.AccountUsecase) {
+	for now := range time.Tick(time.Hour) {
+		uc.PurgeExpired(now)
+	}
+}
+
+/*  LOGIN FAILURE SWEEP  */
+func expireLoginFailures(uc *usecase.LoginGuardUsecase) {
+	for now := range time.Tick(time.Minute) {
+		uc.Expire(now)
+	}
+}
+
+/*  QUOTA COUNTER SWEEP  */
+func expireQuotaCounters(uc *usecase.APIKeyUsecase) {
+	for now := range time.Tick(time.Hour) {
+		uc.Expire(now)
+	}
+}
+
+/*  SAVED SEARCH MATCHING  */
+func matchSavedSearches(uc *usecase.SavedSearchUsecase, elector *lock.Elector, locker lock.Locker) {
+	for range time.Tick(time.Minute) {
+		exclusive(elector, locker, "saved-searches", func() {
+			if n := uc.CheckNewBooks(); n > 0 {
+				log.Printf("Saved searches: sent %d new-book notifications", n)
+			}
+		})
+	}
+}
+
+/*  TRENDING RANKING  */
+func rankTrending(uc *usecase.PopularityUsecase) {
+	for now := range time.Tick(time.Minute) {
+		uc.Rank(now)
+	}
+}
+
+/*  VIEW STATISTICS RETENTION  */
+func pruneViewStats(uc *usecase.ViewStatsUsecase) {
+	for now := range time.Tick(24 * time.Hour) {
+		uc.Prune(now)
+	}
+}
+
+/*  SEARCH ANALYTICS RETENTION  */
+func pruneSearchAnalytics(uc *usecase.SearchAnalyticsUsecase) {
+	for now := range time.Tick(24 * time.Hour) {
+		uc.Prune(now)
+	}
+}
+
+// searchAnalyticsFromEnv counts searches, reporting the terms searched
+// for at least SEARCH_ANALYTICS_MIN_SEARCHES times (3 by default).
+func searchAnalyticsFromEnv() *usecase.SearchAnalyticsUsecase {
+	minSearches, err := strconv.Atoi(getenv("SEARCH_ANALYTICS_MIN_SEARCHES", strconv.Itoa(usecase.DefaultMinTermSearches)))
+	if err != nil || minSearches < 1 {
+		log.Fatal("Invalid SEARCH_ANALYTICS_MIN_SEARCHES: ", os.Getenv("SEARCH_ANALYTICS_MIN_SEARCHES"))
+	}
+	return usecase.NewSearchAnalyticsUsecase(minSearches)
+}
+
+/*  JOB LOCKS  */
+// jobLockTTL is how long a job's lock outlives an instance that crashed
+// while holding it. Live holders renew it.
+const jobLockTTL = time.Minute
+
+// leaderLockTTL is how long leadership outlives a leader that crashed.
+const leaderLockTTL = 30 * time.Second
+
+// exclusive runs a job that reaches outside the instance, such as
+// sending reminders, on the elected leader only, and under the job's own
+// lock so a leader that just lost office cannot overlap its successor.
+// It skips the run otherwise.
+func exclusive(elector *lock.Elector, locker lock.Locker, name string, job func()) {
+	if !elector.Leader() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	l, err := locker.TryLock(ctx, name, jobLockTTL)
+	cancel()
+	if err != nil {
+		if !errors.Is(err, lock.ErrHeld) {
+			log.Printf("Job %s skipped: %v", name, err)
+		}
+		return
+	}
+	defer l.Unlock()
+	job()
+}
+
+/*  EVENT REMINDERS  */
+func remindEvents(uc *usecase.EventUsecase, elector *lock.Elector, locker lock.Locker) {
+	for now := range time.Tick(15 * time.Minute) {
+		exclusive(elector, locker, "event-reminders", func() {
+			if n := uc.SendReminders(now); n > 0 {
+				log.Printf("Events: sent %d reminders", n)
+			}
+		})
+	}
+}
+
+/*  DUE DATE REMINDERS  */
+func remindDueLoans(uc *usecase.LoanUsecase, elector *lock.Elector, locker lock.Locker) {
+	for now := range time.Tick(time.Hour) {
+		exclusive(elector, locker, "due-reminders", func() {
+			if n := uc.SendDueReminders(now); n > 0 {
+				log.Printf("Loans: sent %d due date reminders", n)
+			}
+		})
+	}
+}
+
+/*  PAYMENT RECONCILIATION  */
+func reconcilePayments(uc *usecase.PaymentUsecase, elector *lock.Elector, locker lock.Locker) {
+	for now := range time.Tick(time.Hour) {
+		exclusive(elector, locker, "payment-reconciliation", func() {
+			if r := uc.Reconcile(context.Background(), now); r.Checked > 0 {
+				log.Printf("Payments: reconciled %d pending, %d succeeded, %d failed, %d canceled, %d errors",
+					r.Checked, r.Succeeded, r.Failed, r.Canceled, r.Errors)
+			}
+		})
+	}
+}
+
+/*  SERIAL ISSUE PREDICTION  */
+func predictIssues(uc *usecase.SerialUsecase) {
+	for now := range time.Tick(24 * time.Hour) {
+		if n := uc.Predict(now); n > 0 {
+			log.Printf("Serials: predicted %d issues", n)
+		}
+	}
+}
+
+/*  HOLD PICKUP EXPIRY  */
+// expireHolds runs on the task queue, where its own queue keeps it from
+// waiting behind imports.
+func expireHolds(uc *usecase.HoldUsecase, tasks *usecase.TaskUsecase) {
+	for range time.Tick(15 * time.Minute) {
+		if tasks.Queued(domain.TaskHoldExpiry) {
+			continue
+		}
+		if _, err := tasks.Submit(domain.TaskHoldExpiry, domain.TaskPriorityScheduled, nil); err != nil {
+			log.Println("Hold expiry not queued:", err)
+		}
+	}
+}
+
+/*  TASK TYPES  */
+// runAtField is the payload field of task types that act as of a time.
+var runAtField = domain.PayloadField{Name: "at", Type: domain.FieldTypeDateTime, Description: "Act as of this time instead of now"}
+
+// registerTaskTypes registers the jobs that can be queued by name through
+// POST /tasks/types/{name}.
+func registerTaskTypes(tasks *usecase.TaskUsecase, holds *usecase.HoldUsecase, serials *usecase.SerialUsecase, authors *usecase.AuthorUsecase) {
+	usecase.RegisterTask(tasks, domain.TaskType{
+		Name:        domain.TaskHoldExpiry,
+		Description: "Expire the holds left uncollected on the hold shelf past their pickup window",
+		Payload:     []domain.PayloadField{runAtField},
+	}, func(p domain.TaskRunAt, t *usecase.TaskTracker) any {
+		n := holds.ExpireReady(p.Time())
+		t.Logf("%d holds expired on the hold shelf", n)
+		return domain.HoldExpiryResult{Expired: n}
+	})
+	usecase.RegisterTask(tasks, domain.TaskType{
+		Name:        domain.TaskSerialPrediction,
+		Description: "Predict the next issues of subscribed serials",
+		Payload:     []domain.PayloadField{runAtField},
+	}, func(p domain.TaskRunAt, t *usecase.TaskTracker) any {
+		n := serials.Predict(p.Time())
+		t.Logf("Predicted %d issues", n)
+		return gin.H{"predicted": n}
+	})
+	usecase.RegisterTask(tasks, domain.TaskType{
+		Name:        domain.TaskAuthorDuplicateScan,
+		Description: "Look for authors that are probably the same person",
+		Payload:     []domain.PayloadField{runAtField},
+	}, func(p domain.TaskRunAt, t *usecase.TaskTracker) any {
+		d := authors.ScanDuplicates(p.Time())
+		t.Logf("%d groups of possible duplicates", len(d.Groups))
+		return d
+	})
+}
+
+/*  AUTHOR DEDUPLICATION  */
+func scanAuthorDuplicates(uc *usecase.AuthorUsecase) {
+	for now := range time.Tick(10 * time.Minute) {
+		if d := uc.ScanDuplicates(now); len(d.Groups) > 0 {
+			log.Printf("Authors: %d groups of possible duplicates", len(d.Groups))
+		}
+	}
+}
+
+/*  SCHEDULED EXPORTS  */
+func runExports(uc *usecase.ExportUsecase, elector *lock.Elector, locker lock.Locker) {
+	for now := range time.Tick(5 * time.Minute) {
+		exclusive(elector, locker, "exports", func() { uc.RunDue(now) })
+	}
+}
+
+/*  EVENT PUBLISHING  */
+func publishEvents(uc *usecase.PublisherUsecase) {
+	for range time.Tick(time.Second) {
+		uc.Flush()
+	}
+}
+
+/*  RUNTIME CONFIG  */
+// loadRuntimeConfig reads the settings that can be reloaded: LOG_LEVEL
+// (info by default), CORS_ORIGINS (* by default), FEATURE_FLAGS and the
+// default API key rate limits, overridden by those set in the JSON file
+// at CONFIG_FILE, e.g. {"log_level": "warn", "cors_origins":
+// ["https://library.example.org"], "rate_limits": {"read": {"daily":
+// 5000, "monthly": 100000}}, "feature_flags": "reviews=off"}.
+func loadRuntimeConfig() (domain.RuntimeConfig, error) {
+	cfg := domain.RuntimeConfig{
+		LogLevel:     getenv("LOG_LEVEL", domain.LogLevelInfo),
+		CORSOrigins:  splitList(getenv("CORS_ORIGINS", "*")),
+		RateLimits:   map[string]domain.RateLimit{},
+		FeatureFlags: os.Getenv("FEATURE_FLAGS"),
+	}
+	for class, q := range usecase.DefaultQuotas {
+		cfg.RateLimits[class] = domain.RateLimit{Daily: q.Daily, Monthly: q.Monthly}
+	}
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// runtimeConfigApplier applies a loaded configuration to the request log,
+// CORS, the API key quotas and the feature flags. The flags are only
+// replaced when their spec changed, so that toggles made through
+// /admin/flags survive reloads that do not touch them.
+func runtimeConfigApplier(logLevel *atomic.Value, cors *corsOrigins, apiKeys *usecase.APIKeyUsecase, flags *featureflag.Store) func(old, next domain.RuntimeConfig) error {
+	return func(old, next domain.RuntimeConfig) error {
+		// Check what can fail before anything is changed.
+		replaceFlags := next.FeatureFlags != old.FeatureFlags
+		if replaceFlags {
+			if _, err := featureflag.Parse(next.FeatureFlags); err != nil {
+				return fmt.Errorf("feature_flags: %w", err)
+			}
+		}
+		quotas := map[string]usecase.Quota{}
+		for class, limit := range next.RateLimits {
+			quotas[class] = usecase.Quota{Daily: limit.Daily, Monthly: limit.Monthly}
+		}
+		if err := apiKeys.SetQuotas(quotas); err != nil {
+			return err
+		}
+
+		if replaceFlags {
+			flags.Replace(next.FeatureFlags)
+		}
+		cors.set(next.CORSOrigins)
+		logLevel.Store(next.LogLevel)
+		return nil
+	}
+}
+
+// reloadOnSIGHUP reloads the runtime configuration whenever the process
+// gets SIGHUP, e.g. from kill -HUP or systemctl reload.
+func reloadOnSIGHUP(uc *usecase.ConfigUsecase) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		changes, err := uc.Reload("SIGHUP", time.Now())
+		if err != nil {
+			log.Println("Configuration not reloaded:", err)
+			continue
+		}
+		log.Printf("Configuration reloaded, %d settings changed", len(changes))
+		for _, c := range changes {
+			log.Printf("Config %s: %q -> %q", c.Setting, c.Old, c.New)
+		}
+	}
+}
+
+/*  SELF-CHECK  */
+// selfChecksFromEnv checks what the server depends on as configured: the
+// SMTP relay, the lock store and the snapshot directory.
+func selfChecksFromEnv(locker lock.Locker, snapshotDir string) []usecase.SelfCheck {
+	checks := []usecase.SelfCheck{
+		{Name: "database", Skip: "the catalog and members are kept in memory; there is no database"},
+		{Name: "migrations", Skip: "there is no database to migrate"},
+	}
+
+	smtpCheck := usecase.SelfCheck{Name: "smtp", Skip: "SMTP_ADDR is not set; email is not sent"}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		sender := email.NewSender(addr, "", "", "", nil)
+		smtpCheck = usecase.SelfCheck{Name: "smtp", Run: func(ctx context.Context) (string, error) {
+			return "relay at " + addr + " answered", sender.Check(ctx)
+		}}
+	}
+	checks = append(checks, smtpCheck)
+
+	lockCheck := usecase.SelfCheck{Name: "lock_store", Skip: "LOCK_REDIS_URL is not set; locks are kept in this process"}
+	if r, ok := locker.(*lock.Redis); ok {
+		lockCheck = usecase.SelfCheck{Name: "lock_store", Run: func(ctx context.Context) (string, error) {
+			return "Redis answered", r.Ping(ctx)
+		}}
+	}
+	checks = append(checks, lockCheck)
+
+	return append(checks, usecase.SelfCheck{Name: "storage", Run: func(ctx context.Context) (string, error) {
+		f, err := os.CreateTemp(snapshotDir, ".selfcheck-*")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString("ok")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return snapshotDir + " is writable", err
+	}})
+}
+
+// selfCheck runs the self-checks at startup. With --check it prints the
+// report and exits, with status 1 if a check failed. Otherwise failed
+// checks are logged, and stop the server if SELFCHECK_FAIL_FAST is true.
+func selfCheck(uc *usecase.SelfCheckUsecase, only bool) {
+	failFast, err := strconv.ParseBool(getenv("SELFCHECK_FAIL_FAST", "false"))
+	if err != nil {
+		log.Fatal("Invalid SELFCHECK_FAIL_FAST: ", os.Getenv("SELFCHECK_FAIL_FAST"))
+	}
+	report := uc.Run(context.Background())
+	failed := report.Failed()
+	if only {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+		if len(failed) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	for _, c := range failed {
+		log.Printf("Self-check %s failed: %s", c.Name, c.Detail)
+	}
+	if len(failed) > 0 && failFast {
+		log.Fatal("Self-check failed and SELFCHECK_FAIL_FAST is set; not starting")
+	}
+}
+
 /*  MAIN  */
 func main() {
+	checkOnly := flag.Bool("check", false, "run the self-check, print its report and exit, with status 1 if a check failed")
+	flag.Parse()
+	startedAt := time.Now()
+	b := buildInfo()
+	log.Printf("Digital Library %s, commit %s, built %s with %s", b.Version, b.Commit, b.BuildDate, b.GoVersion)
+
+	// Dependencies are checked before anything starts; with --check they
+	// are only checked
+	locker := lockerFromEnv()
+	snapshotDir := snapshotDirFromEnv()
+	selfCheckUC := usecase.NewSelfCheckUsecase(selfChecksFromEnv(locker, snapshotDir))
+	selfCheck(selfCheckUC, *checkOnly)
+
+	// The request log level, CORS origins, API key rate limits and
+	// feature flags are applied once loaded below, and on every reload
+	logLevel := &atomic.Value{}
+	cors := &corsOrigins{}
+
 	r := gin.New()
-	r.Use(gin.Logger(), gin.Recovery())
+	r.Use(requestLog(logLevel))
+	// The access log comes before recovery, to log panics as the 500s
+	// they are answered with
+	accessLog, accessLogFile := accessLogFromEnv()
+	if accessLog != nil {
+		r.Use(http.AccessLog(accessLog))
+	}
+	r.Use(gin.Recovery())
+
+	// Faults injected for testing, if CHAOS_FILE is set. They come before
+	// error reporting, which would report injected errors as real ones.
+	faults, err := chaos.Load(os.Getenv("CHAOS_FILE"))
+	if err != nil {
+		log.Fatal("Invalid CHAOS_FILE: ", err)
+	}
+	if faults.Enabled() {
+		log.Println("CHAOS_FILE set: injecting faults into requests; do not use in production")
+		go faults.Watch(5 * time.Second)
+	}
+	r.Use(chaosMiddleware(faults))
+
+	// Only proxies listed in TRUSTED_PROXIES may set the client IP through
+	// X-Forwarded-For; otherwise the connection address is used.
+	if err := r.SetTrustedProxies(splitList(os.Getenv("TRUSTED_PROXIES"))); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+
+	// CIDR allow/deny lists per route group, e.g. /admin and /tasks
+	ipRules, err := ipaccess.Load(os.Getenv("IP_ACCESS_FILE"))
+	if err != nil {
+		log.Fatal("Invalid IP_ACCESS_FILE: ", err)
+	}
+	go ipRules.Watch(5 * time.Second)
+
+	// Writes get 503 while maintenance mode is on; toggles are audited
+	auditUC := usecase.NewAuditUsecase()
+	maintenanceUC := usecase.NewMaintenanceUsecase(auditUC)
+	hostname, _ := os.Hostname()
+	instance := getenv("INSTANCE_NAME", hostname)
+	elector := lock.NewElector(locker, "leader", instance, leaderLockTTL)
+	go elector.Run()
+
+	// Circuit breakers for external services, reported on /readyz
+	breakers := resilience.NewRegistry()
+
+	// Panics and server errors go to Sentry, if configured, from every
+	// route and middleware after this one
+	reporter := reporterFromEnv(breakers, instance)
+	if reporter != nil {
+		r.Use(http.Reporting(reporter))
+	}
+
+	// A sample of reads is mirrored, if configured, to validate this
+	// server against the service it replaces
+	mirror := mirrorFromEnv()
+
+	// Clients of the FastAPI service may need its answers unchanged
+	fastapiCompat := fastapiCompatFromEnv()
+
+	// Latency objectives per route, from SLO_FILE
+	sloHandler := http.NewSLOHandler(usecase.NewSLOUsecase(slosFromEnv()))
+
+	// Requests and server errors per route, for /admin/overview
+	requestStatsUC := usecase.NewRequestStatsUsecase()
 
 	// Middlewares
-	r.Use(waitForTaskMiddleware())        // wait if task running
-	r.Use(timingAndUserAgentMiddleware()) // X-Process-Time + log User-Agent
-	r.Use(corsMiddleware())               // CORS
+	r.Use(sloHandler.Track())                          // latency and status against the SLOs
+	r.Use(http.RequestStats(requestStatsUC))           // requests and errors per route
+	r.Use(ipAccessMiddleware(ipRules))                 // reject disallowed client IPs
+	r.Use(maintenanceMiddleware(maintenanceUC))        // refuse writes in maintenance mode
+	r.Use(shadowMiddleware(mirror))                    // compare reads with SHADOW_UPSTREAM
+	r.Use(timingAndUserAgentMiddleware(fastapiCompat)) // X-Process-Time + log User-Agent
+	r.Use(corsMiddleware(cors))                        // CORS
+	r.Use(http.Errors())                               // statuses for errors handlers record
+
+	// API key quotas, metered before any route runs
+	apiKeyUC := usecase.NewAPIKeyUsecase(usecase.DefaultQuotas)
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyUC)
+	r.Use(apiKeyHandler.Meter())
+
+	http.RegisterHealthRoutes(r, http.NewHealthHandler(breakers, maintenanceUC, elector))
+	http.RegisterSLORoutes(r, sloHandler)
+
+	// Members + Auth, which the catalog needs to recognise child accounts
+	planUC := usecase.NewPlanUsecase()
+	memberUC := usecase.NewMemberUsecase(planUC)
+	guardUC := usecase.NewLoginGuardUsecase(auditUC)
+	twoFactorUC := usecase.NewTwoFactorUsecase(memberUC, "Digital Library")
+	authUC := usecase.NewAuthUsecase(memberUC, twoFactorUC, guardUC)
+	authHandler := http.NewAuthHandler(authUC)
+
+	// Optional features, on unless FEATURE_FLAGS turns them off for every
+	// site or one host name, e.g. "reviews=off,reviews@kids.example.org=on".
+	// They and the other runtime settings are reloaded on SIGHUP
+	flags := featureflag.New()
+	configUC, err := usecase.NewConfigUsecase(loadRuntimeConfig, runtimeConfigApplier(logLevel, cors, apiKeyUC, flags), auditUC)
+	if err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+	go reloadOnSIGHUP(configUC)
+	http.RegisterConfigRoutes(r, authHandler, http.NewConfigHandler(configUC, authUC))
+	flagHandler := http.NewFeatureFlagHandler(flags)
+	http.RegisterFeatureFlagRoutes(r, authHandler, flagHandler)
+	http.RegisterVersionRoutes(r, http.NewVersionHandler(buildInfo(), flags))
+	http.RegisterMaintenanceRoutes(r, authHandler, http.NewMaintenanceHandler(maintenanceUC, authUC))
+
+	// Catalog listings for child accounts and ?audience=children only
+	// show books the content policy allows
+	contentUC := usecase.NewContentPolicyUsecase(usecase.DefaultContentPolicy)
+	contentHandler := http.NewContentPolicyHandler(contentUC, authUC)
+	r.Use(contentHandler.Audience())
 
 	// Book CRUD + Task Handlers
 	uc := usecase.NewBookUsecase()
-	bookHandler := http.NewBookHandler(uc)
-	http.RegisterRoutes(r, bookHandler, &taskRunning)
+	authorUC := usecase.NewAuthorUsecase(uc)
+	fieldUC := usecase.NewFieldUsecase(uc)
+	if publisher := publisherFromEnv(breakers); publisher != nil {
+		go publishEvents(usecase.NewPublisherUsecase(uc, publisher))
+	}
+	if queue := commandQueueFromEnv(breakers, instance); queue != nil {
+		go usecase.NewCommandUsecase(uc, authorUC, fieldUC, queue).Run()
+	}
+	halfLife, err := time.ParseDuration(getenv("TRENDING_HALF_LIFE", usecase.DefaultHalfLife.String()))
+	if err != nil || halfLife <= 0 {
+		log.Fatal("Invalid TRENDING_HALF_LIFE: ", os.Getenv("TRENDING_HALF_LIFE"))
+	}
+	popularityUC := usecase.NewPopularityUsecase(uc, halfLife)
+	go popularityUC.ProcessEvents()
+	go rankTrending(popularityUC)
+	notifier := notifierFromEnv()
+	expvar.Publish("book_notifications", expvar.Func(func() any { return notifier.Stats() }))
+	bookCache := usecase.NewBookCache(uc)
+	bookHandler := http.NewBookHandler(uc, bookCache, authorUC, fieldUC, contentUC, notifier)
+	taskUC := tasksFromEnv(reporter)
+	go taskUC.Work()
+	taskHandler := http.NewTaskHandler(maintenanceUC, locker, taskUC)
+	if fastapiCompat {
+		http.RegisterFastAPIRoutes(r, bookHandler, http.NewFastAPIHandler(uc, authorUC, contentUC), taskHandler)
+	} else {
+		http.RegisterRoutes(r, bookHandler, taskHandler)
+	}
+	http.RegisterSyncRoutes(r, authHandler, http.NewSyncHandler(usecase.NewSyncUsecase(uc, authorUC, fieldUC), uc))
+	openLibrary := openlibrary.NewClient(
+		getenv("OPENLIBRARY_URL", openlibrary.DefaultBaseURL),
+		breakers.Breaker("openlibrary", resilience.DefaultPolicy).Client(),
+	)
+	refreshUC := usecase.NewMetadataRefreshUsecase(uc, authorUC, openLibrary)
+	http.RegisterMetadataRoutes(r, authHandler, http.NewMetadataRefreshHandler(refreshUC))
+	http.RegisterTaskRoutes(r, authHandler, http.NewTaskStatusHandler(taskUC))
+	http.RegisterCatalogImportRoutes(r, authHandler, http.NewCatalogImportHandler(usecase.NewCatalogImportUsecase(uc, taskUC)))
+	http.RegisterAcquisitionRoutes(r, authHandler, http.NewAcquisitionHandler(usecase.NewAcquisitionUsecase(uc, openLibrary), authorUC, fieldUC))
+
+	// Members, Circulation + Admin Handlers
+	fineUC := usecase.NewFineUsecase()
+	calendarUC := usecase.NewCalendarUsecase()
+	// Emails, texts and printed receipts are worded with templates admins
+	// can edit; LIBRARY_NAME is the name they show
+	templateUC := usecase.NewTemplateUsecase(getenv("LIBRARY_NAME", "Digital Library"))
+	channels := notificationChannelsFromEnv(templateUC)
+	smsUC, twilioClient := smsFromEnv(breakers, templateUC)
+	if smsUC != nil {
+		channels = append(channels, smsUC)
+	}
+	// Without VAPID_PRIVATE_KEY, browsers must subscribe again whenever
+	// the server restarts.
+	pushSender, err := webpush.NewSender(
+		os.Getenv("VAPID_PRIVATE_KEY"),
+		getenv("VAPID_SUBJECT", "mailto:library@localhost"),
+		breakers.Breaker("webpush", resilience.DefaultPolicy).Client(),
+	)
+	if err != nil {
+		log.Fatal("Invalid VAPID_PRIVATE_KEY: ", err)
+	}
+	pushUC := usecase.NewPushUsecase(pushSender)
+	channels = append(channels, pushUC)
+	notificationUC := usecase.NewNotificationUsecase(memberUC, channels...)
+	pickupWindow, err := time.ParseDuration(getenv("HOLD_PICKUP_WINDOW", domain.DefaultPickupWindow.String()))
+	if err != nil || pickupWindow <= 0 {
+		log.Fatal("Invalid HOLD_PICKUP_WINDOW: ", os.Getenv("HOLD_PICKUP_WINDOW"))
+	}
+	branchUC := usecase.NewBranchUsecase()
+	copyUC := usecase.NewCopyUsecase(uc, branchUC)
+	holdUC := usecase.NewHoldUsecase(uc, memberUC, branchUC, copyUC, calendarUC, notificationUC, pickupWindow)
+	go expireHolds(holdUC, taskUC)
+	minCoBorrowers, err := strconv.Atoi(getenv("RELATED_MIN_READERS", strconv.Itoa(usecase.DefaultMinCoBorrowers)))
+	if err != nil || minCoBorrowers < 1 {
+		log.Fatal("Invalid RELATED_MIN_READERS: ", os.Getenv("RELATED_MIN_READERS"))
+	}
+	relatedUC := usecase.NewRelatedUsecase(uc, minCoBorrowers)
+	courseUC := usecase.NewCourseUsecase(uc, copyUC)
+	loanUC := usecase.NewLoanUsecase(uc, memberUC, fineUC, popularityUC, relatedUC, calendarUC, holdUC, copyUC, notificationUC, courseUC)
+	go remindDueLoans(loanUC, elector, locker)
+	listUC := usecase.NewReadingListUsecase(uc)
+	savedSearchUC := usecase.NewSavedSearchUsecase(uc, notificationUC)
+	bookingUC := usecase.NewBookingUsecase(memberUC, calendarUC, notificationUC)
+	eventUC := usecase.NewEventUsecase(memberUC, notificationUC)
+	moderation := usecase.DefaultModeration
+	moderation.BannedWords = splitList(os.Getenv("BANNED_WORDS"))
+	reviewUC := usecase.NewReviewUsecase(uc, notificationUC, moderation)
+	memberHandler := http.NewMemberHandler(memberUC)
+	http.RegisterMemberRoutes(r, authHandler, memberHandler)
+	http.RegisterCirculationRoutes(r, authHandler, flagHandler, http.NewLoanHandler(loanUC), http.NewHoldHandler(holdUC))
+	http.RegisterTemplateRoutes(r, authHandler, http.NewTemplateHandler(templateUC), http.NewReceiptHandler(templateUC, loanUC, holdUC, uc, memberUC))
+	availabilityUC := usecase.NewAvailabilityUsecase(uc, branchUC, copyUC, loanUC, holdUC)
+	http.RegisterAvailabilityRoutes(r, http.NewAvailabilityHandler(availabilityUC, uc, contentUC))
+	http.RegisterRelatedRoutes(r, http.NewRelatedHandler(relatedUC, uc, contentUC))
+	suggestUC := usecase.NewSuggestUsecase(uc, authorUC)
+	searchAnalyticsUC := searchAnalyticsFromEnv()
+	rankingUC := usecase.NewSearchRankingUsecase(popularityUC, usecase.DefaultRankingWeights)
+	// Queries are in SEARCH_LANGUAGE unless the language facet is selected
+	searchLanguage := getenv("SEARCH_LANGUAGE", "en")
+	if !domain.ValidLanguage(searchLanguage) {
+		log.Fatal("Invalid SEARCH_LANGUAGE: ", searchLanguage)
+	}
+	vocabularyUC := usecase.NewSearchVocabularyUsecase(searchLanguage)
+	browseUC := usecase.NewBrowseUsecase(uc, authorUC, loanUC, suggestUC, contentUC, searchAnalyticsUC, rankingUC, vocabularyUC)
+	ncipAgencyID := getenv("NCIP_AGENCY_ID", "library")
+	federationUC := federationFromEnv(breakers, ncipAgencyID, uc, memberUC)
+	http.RegisterBrowseRoutes(r, http.NewBrowseHandler(browseUC, federationUC))
+	http.RegisterFederationRoutes(r, authHandler, http.NewFederationHandler(federationUC))
+	http.RegisterSearchRankingRoutes(r, authHandler, http.NewSearchRankingHandler(rankingUC, browseUC))
+	http.RegisterSearchVocabularyRoutes(r, authHandler, http.NewSearchVocabularyHandler(vocabularyUC))
+	http.RegisterSearchAnalyticsRoutes(r, authHandler, http.NewSearchAnalyticsHandler(searchAnalyticsUC))
+	go pruneSearchAnalytics(searchAnalyticsUC)
+
+	// Auth + Self-service Portal
+	oidcUC := usecase.NewOIDCUsecase(memberUC, planUC, authUC, twoFactorUC, getenv("OIDC_DEFAULT_PLAN", "adult"), oidcProvidersFromEnv(breakers)...)
+	twoFactorHandler := http.NewTwoFactorHandler(twoFactorUC, authUC)
+	meHandler := http.NewMeHandler(memberUC, loanUC, holdUC, fineUC, listUC)
+	accountUC := usecase.NewAccountUsecase(memberUC, authUC, loanUC, holdUC, fineUC, listUC, savedSearchUC, notificationUC, bookingUC, eventUC, reviewUC, pushUC)
+	http.RegisterMeRoutes(r, authHandler, http.NewOIDCHandler(oidcUC), twoFactorHandler, meHandler, http.NewAccountHandler(accountUC))
+	http.RegisterImportRoutes(r, authHandler, http.NewImportHandler(usecase.NewImportUsecase(uc, listUC, reviewUC, flags)))
+	http.RegisterAPIKeyRoutes(r, authHandler, apiKeyHandler)
+	http.RegisterShelfRoutes(r, authHandler, bookHandler)
+	http.RegisterCatalogingRoutes(r, authHandler, bookHandler)
+	http.RegisterTrendingRoutes(r, http.NewTrendingHandler(popularityUC, contentUC))
+	viewStatsUC := usecase.NewViewStatsUsecase(uc)
+	http.RegisterStatsRoutes(r, authHandler, http.NewStatsHandler(uc, viewStatsUC, popularityUC, usecase.NewWeedingUsecase(uc, copyUC, loanUC)))
+	go pruneViewStats(viewStatsUC)
+	http.RegisterCopyRoutes(r, authHandler, http.NewCopyHandler(copyUC, holdUC))
+	http.RegisterBranchRoutes(r, authHandler, http.NewBranchHandler(branchUC, copyUC, availabilityUC, uc, contentUC))
+	http.RegisterIntegrationRoutes(r, http.NewIntegrationHandler(usecase.NewIntegrationUsecase(integrationsFromEnv(), memberUC, copyUC, loanUC)))
+	http.RegisterNCIPRoutes(r, http.NewNCIPHandler(ncipAgencyID, ncipPartnersFromEnv(), memberUC, planUC, uc, copyUC, loanUC, holdUC, guardUC))
+
+	// SIP2 for self-check machines, on SIP2_ADDR (e.g. ":6001") when set
+	if addr := os.Getenv("SIP2_ADDR"); addr != "" {
+		sipServer := sip2.NewServer(sip2ConfigFromEnv(), memberUC, uc, copyUC, loanUC, holdUC, fineUC, guardUC)
+		go func() { log.Fatal("SIP2 server failed: ", sipServer.ListenAndServe(addr)) }()
+	}
+	http.RegisterBudgetRoutes(r, authHandler, http.NewBudgetHandler(usecase.NewBudgetUsecase(copyUC)))
+	purchasingUC := usecase.NewPurchasingUsecase(uc, copyUC)
+	http.RegisterPurchasingRoutes(r, authHandler, http.NewVendorHandler(purchasingUC), http.NewPurchaseOrderHandler(purchasingUC))
+	serialUC := usecase.NewSerialUsecase(purchasingUC)
+	http.RegisterSerialRoutes(r, authHandler, http.NewSerialHandler(serialUC))
+	go predictIssues(serialUC)
+	registerTaskTypes(taskUC, holdUC, serialUC, authorUC)
+	limit, limits := triggerLimitsFromEnv()
+	http.RegisterTaskTriggerRoutes(r, http.NewTaskTriggerHandler(usecase.NewTaskTriggerUsecase(taskUC, memberUC, limit, limits)))
+	http.RegisterInventoryRoutes(r, authHandler, http.NewInventoryHandler(usecase.NewInventoryUsecase(copyUC, loanUC)))
+	http.RegisterCalendarRoutes(r, authHandler, http.NewCalendarHandler(calendarUC))
+	http.RegisterBookingRoutes(r, authHandler, flagHandler, http.NewBookingHandler(bookingUC, memberUC))
+	http.RegisterEventRoutes(r, authHandler, http.NewEventHandler(eventUC))
+	go remindEvents(eventUC, elector, locker)
+	// Calendar apps subscribe with signed URLs; without CALENDAR_FEED_SECRET
+	// they stop working whenever the server restarts.
+	feedUC, err := usecase.NewCalendarFeedUsecase(memberUC, []byte(os.Getenv("CALENDAR_FEED_SECRET")))
+	if err != nil {
+		log.Fatal("Calendar feed setup failed: ", err)
+	}
+	http.RegisterCalendarFeedRoutes(r, authHandler, http.NewCalendarFeedHandler(feedUC, uc, loanUC, eventUC))
+	worklistUC := usecase.NewWorklistUsecase(uc, copyUC, holdUC, loanUC, memberUC)
+	http.RegisterWorklistRoutes(r, authHandler, http.NewWorklistHandler(worklistUC))
+	http.RegisterAuthorRoutes(r, authHandler, http.NewAuthorHandler(authorUC))
+	go scanAuthorDuplicates(authorUC)
+	http.RegisterFieldRoutes(r, authHandler, http.NewFieldHandler(fieldUC))
+	editionUC := usecase.NewEditionUsecase(uc)
+	http.RegisterEditionRoutes(r, authHandler, http.NewPublisherHandler(editionUC), http.NewEditionHandler(editionUC))
+	http.RegisterSeriesRoutes(r, authHandler, http.NewSeriesHandler(usecase.NewSeriesUsecase(uc)))
+	// Course reserves, also embedded in learning management systems at
+	// LTI_LAUNCH_URL, the launch URL registered with each platform
+	ltiUC := usecase.NewLTIUsecase(courseUC, uc, getenv("LTI_LAUNCH_URL", "http://localhost:8080/lti/launch"), ltiPlatformsFromEnv(breakers)...)
+	http.RegisterCourseRoutes(r, authHandler, http.NewCourseHandler(courseUC), http.NewLTIHandler(ltiUC, courseUC))
+	http.RegisterSavedSearchRoutes(r, authHandler, http.NewSavedSearchHandler(savedSearchUC, notificationUC))
+	go matchSavedSearches(savedSearchUC, elector, locker)
+	securityHandler := http.NewSecurityHandler(guardUC, auditUC, authUC)
+	http.RegisterAdminRoutes(r, authHandler, http.NewPlanHandler(planUC, memberUC), memberHandler, twoFactorHandler, securityHandler)
+	http.RegisterDebugRoutes(r, authHandler, http.NewDebugHandler(snapshotDir))
+	http.RegisterSelfCheckRoutes(r, authHandler, http.NewSelfCheckHandler(selfCheckUC))
+	overviewUC := usecase.NewOverviewUsecase(buildVersion(), instance, startedAt, requestStatsUC, taskUC, notifier, bookCache, maintenanceUC)
+	http.RegisterOverviewRoutes(r, authHandler, http.NewOverviewHandler(overviewUC))
+	http.RegisterContentPolicyRoutes(r, authHandler, contentHandler)
+	http.RegisterReviewRoutes(r, authHandler, flagHandler, http.NewReviewHandler(reviewUC, memberUC))
+	http.RegisterPushRoutes(r, authHandler, http.NewPushHandler(pushUC, pushSender.PublicKey()))
+	if smsUC != nil {
+		http.RegisterSMSRoutes(r, authHandler, http.NewSMSHandler(smsUC, twilioClient))
+	}
+	go purgeDeletedAccounts(accountUC)
+	go expireLoginFailures(guardUC)
+	go expireQuotaCounters(apiKeyUC)
+	if paymentUC, paymentEvents := paymentsFromEnv(breakers, fineUC); paymentUC != nil {
+		http.RegisterPaymentRoutes(r, authHandler, http.NewPaymentHandler(paymentUC, paymentEvents))
+		go reconcilePayments(paymentUC, elector, locker)
+	}
+	// Whole catalogs take longer to upload than the default timeout allows
+	s3Policy := resilience.DefaultPolicy
+	s3Policy.Timeout = 10 * time.Minute
+	exportUC := usecase.NewExportUsecase(uc, memberUC, notificationUC, s3.NewClient(breakers.Breaker("s3", s3Policy).Client()))
+	http.RegisterExportRoutes(r, authHandler, http.NewExportJobHandler(exportUC))
+	go runExports(exportUC, elector, locker)
+	bootstrapAdmin(memberUC, planUC)
 
 	// Swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	log.Println("Server running on port 8080")
-	r.Run(":8080")
+	srv := &stdhttp.Server{Addr: ":8080", Handler: r}
+	go func() {
+		log.Println("Server running on port 8080")
+		if err := srv.ListenAndServe(); !errors.Is(err, stdhttp.ErrServerClosed) {
+			log.Fatal("Server failed: ", err)
+		}
+	}()
+
+	// On SIGINT or SIGTERM, finish the requests in flight, then send the
+	// notifications still queued.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Requests cut off at shutdown:", err)
+	}
+	if err := notifier.Drain(shutdownCtx); err != nil {
+		log.Println("Notifications not sent at shutdown:", notifier.Stats().Depth)
+	}
+	if accessLogFile != nil {
+		accessLogFile.Close()
+	}
 }
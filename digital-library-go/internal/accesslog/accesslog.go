@@ -0,0 +1,135 @@
+// Package accesslog writes one JSON line per request, to stdout or to a
+// file rotated by size, for log pipelines to collect. Successful reads
+// can be sampled, since busy catalog routes would otherwise drown out
+// the rest.
+package accesslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is the access log line of one request. Route is the route as gin
+// names it, e.g. /books/:id, empty when no route matched. MemberID is
+// the signed-in member, and APIKeyID the API key the request was made
+// with, if any. SampleRate is set on sampled requests: the share of
+// requests like it that were logged.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Route      string    `json:"route,omitempty"`
+	Status     int       `json:"status"`
+	LatencyMs  float64   `json:"latency_ms"`
+	Bytes      int       `json:"bytes"`
+	ClientIP   string    `json:"client_ip"`
+	MemberID   int       `json:"member_id,omitempty"`
+	APIKeyID   int       `json:"api_key_id,omitempty"`
+	Tenant     string    `json:"tenant"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	SampleRate float64   `json:"sample_rate,omitempty"`
+}
+
+// Config says which requests are logged. Reads answered without an
+// error are logged at the rate of their route in Routes, keyed e.g.
+// "GET /books/:id", or at Sample otherwise; every other request is
+// logged. Requests to Exclude paths, such as health checks, are not
+// logged at all.
+type Config struct {
+	Sample  float64
+	Routes  map[string]float64
+	Exclude []string
+}
+
+// Logger writes access log entries. It is safe for concurrent use.
+type Logger struct {
+	config Config
+
+	mu     sync.Mutex
+	out    io.Writer
+	failed bool
+}
+
+func New(out io.Writer, config Config) *Logger {
+	return &Logger{out: out, config: config}
+}
+
+// Log writes the entry, unless its path is excluded or it is sampled
+// out.
+func (l *Logger) Log(e Entry) {
+	if slices.Contains(l.config.Exclude, e.Path) {
+		return
+	}
+	if rate := l.rate(e); rate < 1 {
+		if rand.Float64() >= rate {
+			return
+		}
+		e.SampleRate = rate
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.out.Write(line)
+	// Log the first failure of a run of them, not every line lost.
+	if err != nil && !l.failed {
+		log.Println("Access log: write failed, entries are lost until it recovers:", err)
+	}
+	l.failed = err != nil
+}
+
+// rate is the share of requests like e that are logged.
+func (l *Logger) rate(e Entry) float64 {
+	if (e.Method != http.MethodGet && e.Method != http.MethodHead) || e.Status >= 400 {
+		return 1
+	}
+	if rate, ok := l.config.Routes[e.Method+" "+e.Route]; ok {
+		return rate
+	}
+	return l.config.Sample
+}
+
+// ParseRate reads a sample rate between 0 and 1.
+func ParseRate(v string) (float64, error) {
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("%q must be a rate between 0 and 1", v)
+	}
+	return rate, nil
+}
+
+// ParseRoutes reads sample rates per route from a comma-separated list
+// of route=rate, e.g. "GET /books=0.1,GET /books/:id=0.25".
+func ParseRoutes(spec string) (map[string]float64, error) {
+	routes := map[string]float64{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		route, v, ok := strings.Cut(item, "=")
+		method, path, _ := strings.Cut(route, " ")
+		if !ok || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("%q must be a method and route with a rate, e.g. GET /books=0.1", item)
+		}
+		rate, err := ParseRate(v)
+		if err != nil {
+			return nil, err
+		}
+		routes[route] = rate
+	}
+	return routes, nil
+}
@@ -0,0 +1,63 @@
+// Package bookexport writes the catalog as CSV, JSON or MARC 21 for
+// other library systems and union catalogs to load.
+package bookexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var ErrUnknownFormat = errors.New("format must be csv, json or marc")
+
+// csvHeader lists the CSV columns. Its names are ones the catalog import
+// recognises, so an export can be imported elsewhere without a mapping.
+var csvHeader = []string{"id", "title", "author", "year", "isbn", "language", "age_rating", "added_at", "description"}
+
+// Write writes books to w in format.
+func Write(w io.Writer, format string, books []domain.Book) error {
+	switch format {
+	case domain.ExportCSV:
+		return writeCSV(w, books)
+	case domain.ExportJSON:
+		return json.NewEncoder(w).Encode(books)
+	case domain.ExportMARC:
+		for _, b := range books {
+			if _, err := w.Write(Record(b)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ErrUnknownFormat
+}
+
+func writeCSV(w io.Writer, books []domain.Book) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, b := range books {
+		year, rating := "", ""
+		if b.Year > 0 {
+			year = strconv.Itoa(b.Year)
+		}
+		if b.AgeRating > 0 {
+			rating = strconv.Itoa(b.AgeRating)
+		}
+		err := cw.Write([]string{
+			strconv.Itoa(b.ID), b.Title, b.Author, year, b.ISBN, b.Language, rating,
+			b.AddedAt.UTC().Format(time.RFC3339), b.Description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,149 @@
+package bookexport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// ISO 2709 delimiters.
+const (
+	subfieldDelimiter = 0x1f
+	fieldTerminator   = 0x1e
+	recordTerminator  = 0x1d
+)
+
+// maxFieldData keeps a field under the 9999 bytes its directory entry
+// can describe, leaving room for indicators and subfield codes.
+const maxFieldData = 9000
+
+type field struct {
+	tag  string
+	data []byte
+}
+
+// Record encodes a book as a minimal MARC 21 bibliographic record in
+// UTF-8: control number (001), fixed data (008), ISBN (020), main author
+// (100), title (245), publication date (264), playing time (306),
+// narrator (511) and summary (520). Of a book with several linked
+// authors, only the first goes in 100.
+func Record(b domain.Book) []byte {
+	fields := []field{
+		{"001", []byte(strconv.Itoa(b.ID))},
+		{"008", []byte(fixedData(b))},
+	}
+	if b.ISBN != "" {
+		fields = append(fields, dataField("020", "  ", 'a', b.ISBN))
+	}
+	author := b.Author
+	if len(b.AuthorIDs) > 1 {
+		// The display string joins the names with ", ".
+		author, _, _ = strings.Cut(author, ", ")
+	}
+	if author = strings.TrimSpace(author); author != "" {
+		fields = append(fields, dataField("100", "1 ", 'a', author))
+	}
+	// The first indicator says whether a 1XX main entry exists.
+	titleIndicators := "00"
+	if author != "" {
+		titleIndicators = "10"
+	}
+	fields = append(fields, dataField("245", titleIndicators, 'a', b.Title))
+	if b.Year > 0 {
+		fields = append(fields, dataField("264", " 1", 'c', strconv.Itoa(b.Year)))
+	}
+	if b.DurationMinutes > 0 {
+		fields = append(fields, dataField("306", "  ", 'a', fmt.Sprintf("%02d%02d00", b.DurationMinutes/60, b.DurationMinutes%60)))
+	}
+	if b.Narrator != "" {
+		fields = append(fields, dataField("511", "0 ", 'a', "Read by "+b.Narrator))
+	}
+	if b.Description != "" {
+		fields = append(fields, dataField("520", "  ", 'a', b.Description))
+	}
+
+	var directory, body []byte
+	for _, f := range fields {
+		data := append(f.data, fieldTerminator)
+		directory = fmt.Appendf(directory, "%s%04d%05d", f.tag, len(data), len(body))
+		body = append(body, data...)
+	}
+	directory = append(directory, fieldTerminator)
+
+	base := 24 + len(directory)
+	length := base + len(body) + 1
+	// Record status new, the type and level of the format, UTF-8,
+	// encoding level 3 (abbreviated), unknown cataloging form.
+	record := fmt.Appendf(nil, "%05dn%s a22%05d3u 4500", length, recordType(b), base)
+	record = append(record, directory...)
+	record = append(record, body...)
+	return append(record, recordTerminator)
+}
+
+// recordType is the type of record and bibliographic level in the leader:
+// language material, nonmusical sound recording or projected medium, as
+// a monograph, or a serial for magazines.
+func recordType(b domain.Book) string {
+	switch b.MediaFormat() {
+	case domain.FormatAudiobook:
+		return "im"
+	case domain.FormatDVD:
+		return "gm"
+	case domain.FormatMagazine:
+		return "as"
+	}
+	return "am"
+}
+
+// fixedData builds the 40-character 008 field: date entered, a single
+// known date, and the language. Everything else is left blank.
+func fixedData(b domain.Book) string {
+	entered := "000000"
+	if !b.AddedAt.IsZero() {
+		entered = b.AddedAt.UTC().Format("060102")
+	}
+	date := "uuuu"
+	if b.Year > 0 && b.Year <= 9999 {
+		date = fmt.Sprintf("%04d", b.Year)
+	}
+	lang := domain.MARCLanguage(b.Language)
+	if len(lang) != 3 {
+		lang = "und"
+	}
+	return entered + "s" + date + "    " + "xx " + strings.Repeat(" ", 17) + lang + " d"
+}
+
+func dataField(tag, indicators string, code byte, value string) field {
+	data := []byte(indicators)
+	data = append(data, subfieldDelimiter, code)
+	data = append(data, truncate(clean(value), maxFieldData)...)
+	return field{tag, data}
+}
+
+// clean drops the ISO 2709 delimiters and other control characters,
+// which would corrupt the record, and folds line breaks into spaces.
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// truncate cuts s to at most n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
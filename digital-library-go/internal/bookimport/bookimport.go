@@ -0,0 +1,213 @@
+// Package bookimport reads the library exports of reading sites into
+// rows the catalog can match against its books, and catalog files of any
+// layout into books.
+package bookimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// MaxRows is the most books one import may hold.
+const MaxRows = 10000
+
+var (
+	ErrUnknownSource = errors.New("source must be goodreads or librarything")
+	ErrTooManyRows   = fmt.Errorf("an import may hold at most %d books", MaxRows)
+)
+
+// columns names the export columns each field is read from, in order of
+// preference. Shelves are read from every column listed, each holding a
+// comma-separated list.
+type columns struct {
+	title, author, rating, review []string
+	isbns, shelves                []string
+}
+
+var sources = map[string]columns{
+	domain.ImportGoodreads: {
+		title:   []string{"Title"},
+		author:  []string{"Author"},
+		isbns:   []string{"ISBN13", "ISBN"},
+		rating:  []string{"My Rating"},
+		review:  []string{"My Review"},
+		shelves: []string{"Exclusive Shelf", "Bookshelves"},
+	},
+	domain.ImportLibraryThing: {
+		title:   []string{"Title", "TITLE"},
+		author:  []string{"Primary Author", "Author (First, Last)", "Author (Last, First)", "AUTHOR (first, last)", "AUTHOR (last, first)"},
+		isbns:   []string{"ISBNs", "ISBN"},
+		rating:  []string{"Rating", "RATING"},
+		review:  []string{"Review", "REVIEW"},
+		shelves: []string{"Collections", "COLLECTIONS"},
+	},
+}
+
+// Parse reads an export file from source. Goodreads exports are CSV;
+// LibraryThing ones may be CSV or tab-separated, which is detected from
+// the header line. Rows without a title are skipped.
+func Parse(source string, r io.Reader) ([]domain.ImportRow, error) {
+	cols, ok := sources[source]
+	if !ok {
+		return nil, ErrUnknownSource
+	}
+
+	t, err := ReadTable(r)
+	if err != nil {
+		return nil, err
+	}
+	index := t.index()
+	if _, ok := lookup(index, cols.title); !ok {
+		return nil, fmt.Errorf("not a %s export: no Title column", source)
+	}
+
+	rows := []domain.ImportRow{}
+	for n, record := range t.Records {
+		field := func(names []string) string {
+			if i, ok := lookup(index, names); ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		title := field(cols.title)
+		if title == "" {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		row := domain.ImportRow{
+			Line:   t.Lines[n],
+			Title:  title,
+			Author: field(cols.author),
+			ISBNs:  isbns(field(cols.isbns)),
+			Rating: rating(field(cols.rating)),
+			Review: cleanReview(field(cols.review)),
+		}
+		seen := map[string]bool{}
+		for _, name := range cols.shelves {
+			for _, shelf := range strings.Split(field([]string{name}), ",") {
+				shelf = strings.TrimSpace(shelf)
+				if shelf != "" && !seen[strings.ToLower(shelf)] {
+					seen[strings.ToLower(shelf)] = true
+					row.Shelves = append(row.Shelves, shelf)
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Table is a delimited file read whole: its header, its records and the
+// line each record starts on.
+type Table struct {
+	Delimiter rune
+	Header    []string
+	Records   [][]string
+	Lines     []int
+}
+
+// ReadTable reads a CSV or tab-separated file, detecting which from the
+// header line. A byte order mark and spaces around column names are
+// dropped, and records may have fewer or more fields than the header.
+func ReadTable(r io.Reader) (Table, error) {
+	br := bufio.NewReader(r)
+	cr := csv.NewReader(br)
+	head, _ := br.Peek(4096)
+	if line, _, _ := bytes.Cut(head, []byte("\n")); bytes.Count(line, []byte("\t")) > bytes.Count(line, []byte(",")) {
+		cr.Comma = '\t'
+	}
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return Table{}, errors.New("file is empty")
+	}
+	if err != nil {
+		return Table{}, fmt.Errorf("invalid file: %w", err)
+	}
+	t := Table{Delimiter: cr.Comma, Records: [][]string{}, Lines: []int{}}
+	for _, name := range header {
+		t.Header = append(t.Header, strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return t, nil
+		}
+		if err != nil {
+			return Table{}, fmt.Errorf("invalid file: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		t.Records = append(t.Records, record)
+		t.Lines = append(t.Lines, line)
+	}
+}
+
+func (t Table) index() map[string]int {
+	index := map[string]int{}
+	for i, name := range t.Header {
+		index[name] = i
+	}
+	return index
+}
+
+func lookup(index map[string]int, names []string) (int, bool) {
+	for _, name := range names {
+		if i, ok := index[name]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// isbns splits an ISBN field, which may hold several ISBNs in brackets or
+// in Goodreads' ="..." spreadsheet quoting, and keeps the ones of a valid
+// length.
+func isbns(field string) []string {
+	found := []string{}
+	for _, part := range strings.FieldsFunc(field, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == 'x' || r == 'X' || r == '-')
+	}) {
+		if isbn := domain.NormalizeISBN(part); len(isbn) == 10 || len(isbn) == 13 {
+			found = append(found, isbn)
+		}
+	}
+	return found
+}
+
+// rating reads a 1 to 5 star rating. LibraryThing allows half stars,
+// which are rounded up; anything else unreadable counts as unrated.
+func rating(field string) int {
+	f, err := strconv.ParseFloat(field, 64)
+	if err != nil || f < 0 || f > 5 {
+		return 0
+	}
+	return int(math.Round(f))
+}
+
+var (
+	lineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</p>`)
+	tag       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// cleanReview turns the HTML of an exported review into plain text.
+func cleanReview(s string) string {
+	s = lineBreak.ReplaceAllString(s, "\n")
+	s = tag.ReplaceAllString(s, "")
+	return strings.TrimSpace(html.UnescapeString(s))
+}
@@ -0,0 +1,142 @@
+package bookimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// previewSamples is how many rows a preview shows.
+const previewSamples = 5
+
+// synonyms lists the column names each book field is guessed from, after
+// lowercasing and dropping everything but letters and digits, in order
+// of preference.
+var synonyms = map[string][]string{
+	"id":          {"id", "bookid"},
+	"title":       {"title", "booktitle", "name"},
+	"author":      {"author", "authors", "primaryauthor", "creator", "writer"},
+	"isbn":        {"isbn13", "isbn", "isbn10", "isbns"},
+	"year":        {"year", "yearpublished", "publicationyear", "originalpublicationyear", "published", "pubyear", "date"},
+	"language":    {"language", "lang", "languages"},
+	"age_rating":  {"agerating", "minage", "age"},
+	"description": {"description", "summary", "synopsis", "abstract"},
+	"status":      {"status"},
+}
+
+// Preview describes a table for the column-mapping step of an import.
+func Preview(t Table) domain.ImportPreview {
+	delimiter := ","
+	if t.Delimiter == '\t' {
+		delimiter = "tab"
+	}
+	return domain.ImportPreview{
+		Delimiter: delimiter,
+		Columns:   t.Header,
+		Rows:      len(t.Records),
+		Samples:   t.Records[:min(previewSamples, len(t.Records))],
+		Suggested: Suggest(t.Header),
+	}
+}
+
+// Suggest guesses which column each book field is in from the column
+// names. Each column is used for one field at most.
+func Suggest(columns []string) domain.ColumnMapping {
+	keys := map[string]string{}
+	for _, c := range columns {
+		if k := key(c); keys[k] == "" {
+			keys[k] = c
+		}
+	}
+	mapping := domain.ColumnMapping{}
+	used := map[string]bool{}
+	for _, field := range domain.ImportFields {
+		for _, name := range synonyms[field] {
+			if c, ok := keys[name]; ok && !used[c] {
+				mapping[field] = c
+				used[c] = true
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+func key(column string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, column)
+}
+
+// Catalog reads the books in a table through a validated mapping. Rows
+// without a title are skipped. A row whose values cannot be read is
+// returned with an Error; whether a book is valid is for the caller to
+// check.
+func Catalog(t Table, mapping domain.ColumnMapping) ([]domain.CatalogRow, error) {
+	index := t.index()
+	rows := []domain.CatalogRow{}
+	for n, record := range t.Records {
+		field := func(name string) string {
+			if i, ok := index[mapping[name]]; ok && mapping[name] != "" && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if field("title") == "" {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+
+		row := domain.CatalogRow{Line: t.Lines[n]}
+		row.Book = domain.Book{
+			Title:       field("title"),
+			Author:      field("author"),
+			Language:    field("language"),
+			Description: field("description"),
+			Status:      strings.ToLower(field("status")),
+		}
+		if found := isbns(field("isbn")); len(found) > 0 {
+			row.Book.ISBN = found[0]
+		} else if v := field("isbn"); v != "" {
+			row.Error = fmt.Sprintf("isbn %q is not 10 or 13 digits", v)
+		}
+		read := func(name string, dst *int, parse func(string) (int, bool)) {
+			v := field(name)
+			if v == "" || row.Error != "" {
+				return
+			}
+			if n, ok := parse(v); ok {
+				*dst = n
+			} else {
+				row.Error = fmt.Sprintf("%s %q is not a number", name, v)
+			}
+		}
+		read("id", &row.Book.ID, number)
+		read("year", &row.Book.Year, year)
+		read("age_rating", &row.Book.AgeRating, number)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func number(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// year reads a year on its own or at the start of a date such as
+// 1997-06-26.
+func year(s string) (int, bool) {
+	if len(s) > 4 && (s[4] == '-' || s[4] == '/') {
+		s = s[:4]
+	}
+	return number(s)
+}
@@ -0,0 +1,227 @@
+// Package chaos holds faults to inject into requests per route, for
+// client teams to test their retries and timeouts against, loaded from a
+// JSON file and reloaded when the file changes. It is meant for test
+// environments only.
+package chaos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Fault is one fault to inject into percent of the requests to a route:
+// a delay of Latency plus up to Jitter, an answer with Status instead of
+// the route's own, or, with Drop, a connection closed without an answer.
+// A fault may delay and then fail a request.
+type Fault struct {
+	Percent float64 `json:"percent"`
+	Latency string  `json:"latency,omitempty"`
+	Jitter  string  `json:"jitter,omitempty"`
+	Status  int     `json:"status,omitempty"`
+	Drop    bool    `json:"drop,omitempty"`
+}
+
+type fault struct {
+	percent float64
+	latency time.Duration
+	jitter  time.Duration
+	status  int
+	drop    bool
+}
+
+type route struct {
+	method string
+	prefix string
+	faults []fault
+}
+
+// Action is what to do to one request: wait Delay, then answer Status or
+// drop the connection, if either is set, or else handle it as usual.
+type Action struct {
+	Delay  time.Duration
+	Status int
+	Drop   bool
+}
+
+// Rules maps routes such as "GET /books" or "/loans", any method, to
+// their faults.
+type Rules struct {
+	routes []route
+}
+
+// Parse reads rules from JSON of the form
+// {"GET /books": [{"percent": 10, "latency": "2s"}], "/loans": [{"percent": 5, "status": 503}]}.
+func Parse(data []byte) (*Rules, error) {
+	var raw map[string][]Fault
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	rules := &Rules{}
+	for key, faults := range raw {
+		r := route{prefix: key}
+		if method, prefix, ok := strings.Cut(key, " "); ok {
+			r.method, r.prefix = strings.ToUpper(method), strings.TrimSpace(prefix)
+		}
+		if !strings.HasPrefix(r.prefix, "/") {
+			return nil, fmt.Errorf("%s: route must be a path, optionally after a method", key)
+		}
+		r.prefix = strings.TrimSuffix(r.prefix, "/")
+		for _, f := range faults {
+			parsed, err := parseFault(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			r.faults = append(r.faults, parsed)
+		}
+		rules.routes = append(rules.routes, r)
+	}
+	// Longest prefix first so the most specific route wins, and of two
+	// with the same prefix the one for a method.
+	sort.Slice(rules.routes, func(i, j int) bool {
+		a, b := rules.routes[i], rules.routes[j]
+		if len(a.prefix) != len(b.prefix) {
+			return len(a.prefix) > len(b.prefix)
+		}
+		return a.method > b.method
+	})
+	return rules, nil
+}
+
+func parseFault(f Fault) (fault, error) {
+	parsed := fault{percent: f.Percent, status: f.Status, drop: f.Drop}
+	if f.Percent <= 0 || f.Percent > 100 {
+		return fault{}, errors.New("percent must be above 0 and at most 100")
+	}
+	if f.Status != 0 && (f.Status < 400 || f.Status > 599) {
+		return fault{}, errors.New("status must be an error status, 400 to 599")
+	}
+	if f.Status != 0 && f.Drop {
+		return fault{}, errors.New("a fault either answers with a status or drops the connection")
+	}
+	var err error
+	if f.Latency != "" {
+		if parsed.latency, err = time.ParseDuration(f.Latency); err != nil || parsed.latency < 0 {
+			return fault{}, fmt.Errorf("invalid latency %q", f.Latency)
+		}
+	}
+	if f.Jitter != "" {
+		if parsed.jitter, err = time.ParseDuration(f.Jitter); err != nil || parsed.jitter < 0 {
+			return fault{}, fmt.Errorf("invalid jitter %q", f.Jitter)
+		}
+	}
+	if parsed.latency == 0 && parsed.jitter == 0 && parsed.status == 0 && !parsed.drop {
+		return fault{}, errors.New("a fault needs a latency, jitter, status or drop")
+	}
+	return parsed, nil
+}
+
+// Decide rolls the faults of the route of a request. Delays of all the
+// faults that hit add up; of those that fail the request, the first one
+// that hits wins.
+func (r *Rules) Decide(method, path string) Action {
+	var a Action
+	rt, ok := r.match(method, path)
+	if !ok {
+		return a
+	}
+	for _, f := range rt.faults {
+		if rand.Float64()*100 >= f.percent {
+			continue
+		}
+		a.Delay += f.latency
+		if f.jitter > 0 {
+			a.Delay += rand.N(f.jitter)
+		}
+		if a.Status == 0 && !a.Drop {
+			a.Status, a.Drop = f.status, f.drop
+		}
+	}
+	return a
+}
+
+func (r *Rules) match(method, path string) (route, bool) {
+	for _, rt := range r.routes {
+		if rt.method != "" && rt.method != method {
+			continue
+		}
+		if path == rt.prefix || strings.HasPrefix(path, rt.prefix+"/") || rt.prefix == "" {
+			return rt, true
+		}
+	}
+	return route{}, false
+}
+
+// Store holds the current rules and swaps them atomically on reload.
+type Store struct {
+	rules atomic.Pointer[Rules]
+	path  string
+}
+
+// Load reads the rules file at path. An empty path yields a store that
+// injects nothing.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path}
+	s.rules.Store(&Rules{})
+	if path == "" {
+		return s, nil
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Enabled reports whether faults are configured at all.
+func (s *Store) Enabled() bool {
+	return s.path != ""
+}
+
+func (s *Store) Rules() *Rules {
+	return s.rules.Load()
+}
+
+// Watch polls the rules file and reloads it whenever it changes. A file
+// that fails to parse is logged and the previous rules stay in force.
+func (s *Store) Watch(interval time.Duration) {
+	if s.path == "" {
+		return
+	}
+	var last time.Time
+	if fi, err := os.Stat(s.path); err == nil {
+		last = fi.ModTime()
+	}
+	for range time.Tick(interval) {
+		fi, err := os.Stat(s.path)
+		if err != nil || !fi.ModTime().After(last) {
+			continue
+		}
+		last = fi.ModTime()
+		if err := s.reload(); err != nil {
+			log.Println("Fault injection rules not reloaded:", err)
+			continue
+		}
+		log.Println("Fault injection rules reloaded from", s.path)
+	}
+}
+
+func (s *Store) reload() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	rules, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	s.rules.Store(rules)
+	return nil
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/accesslog"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog writes an access log entry for each request once it is
+// answered, with the member signed in or the API key it was made with,
+// and the tenant of its host.
+func AccessLog(l *accesslog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		e := accesslog.Entry{
+			Time:      start.UTC(),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Route:     c.FullPath(),
+			Status:    c.Writer.Status(),
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			Bytes:     max(0, c.Writer.Size()),
+			ClientIP:  c.ClientIP(),
+			MemberID:  currentMemberID(c),
+			Tenant:    featureflag.Tenant(c.Request.Host),
+			UserAgent: c.Request.UserAgent(),
+		}
+		if value, ok := c.Get(apiKeyContextKey); ok {
+			key := value.(domain.APIKey)
+			e.APIKeyID = key.ID
+			if e.MemberID == 0 {
+				e.MemberID = key.MemberID
+			}
+		}
+		l.Log(e)
+	}
+}
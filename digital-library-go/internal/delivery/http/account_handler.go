@@ -0,0 +1,77 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	uc *usecase.AccountUsecase
+}
+
+func NewAccountHandler(uc *usecase.AccountUsecase) *AccountHandler {
+	return &AccountHandler{uc: uc}
+}
+
+// ExportData godoc
+// @Summary Export my personal data
+// @Description Download a JSON archive of all personal data held about the authenticated member
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.PersonalData
+// @Router /me/export [get]
+func (h *AccountHandler) ExportData(c *gin.Context) {
+	memberID := currentMemberID(c)
+
+	data, err := h.uc.Export(memberID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="member-%d-export.json"`, memberID))
+	c.JSON(http.StatusOK, data)
+}
+
+// DeleteAccount godoc
+// @Summary Delete my account
+// @Description Schedule the authenticated member's account for anonymization after a grace period. All of the member's sessions end; they can log in again to restore the account. Until then they cannot borrow or place holds.
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} domain.Member
+// @Failure 409 {object} map[string]string
+// @Router /me [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	member, err := h.uc.RequestDeletion(currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": member})
+}
+
+// RestoreAccount godoc
+// @Summary Undo my account deletion
+// @Description Cancel a pending account deletion during its grace period
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Member
+// @Failure 409 {object} map[string]string
+// @Router /me/restore [post]
+func (h *AccountHandler) RestoreAccount(c *gin.Context) {
+	member, err := h.uc.CancelDeletion(currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScanRequest lists the ISBNs read by a barcode scanner.
+type ScanRequest struct {
+	ISBNs []string `json:"isbns"`
+}
+
+type AcquisitionHandler struct {
+	uc      *usecase.AcquisitionUsecase
+	authors *usecase.AuthorUsecase
+	fields  *usecase.FieldUsecase
+}
+
+func NewAcquisitionHandler(uc *usecase.AcquisitionUsecase, authors *usecase.AuthorUsecase, fields *usecase.FieldUsecase) *AcquisitionHandler {
+	return &AcquisitionHandler{uc: uc, authors: authors, fields: fields}
+}
+
+// GetAcquisitions godoc
+// @Summary Get acquisitions
+// @Description Get the draft records created from scanned ISBNs, oldest first. By default only those still pending cataloging are listed. Librarians only.
+// @Tags Acquisitions
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending_cataloging (default), cataloged or all"
+// @Success 200 {array} domain.Acquisition
+// @Failure 400 {object} map[string]string
+// @Router /acquisitions [get]
+func (h *AcquisitionHandler) GetAcquisitions(c *gin.Context) {
+	status := c.DefaultQuery("status", domain.AcquisitionPending)
+	switch status {
+	case domain.AcquisitionPending, domain.AcquisitionCataloged:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending_cataloging, cataloged or all"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Acquisitions(status)})
+}
+
+// ScanISBNs godoc
+// @Summary Scan ISBNs
+// @Description Create a draft record, pending cataloging, for each scanned ISBN, filled in from the metadata providers. ISBNs already in the catalog or awaiting cataloging are reported as duplicates, and ISBNs with a wrong check digit as invalid. Librarians only.
+// @Tags Acquisitions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scan body ScanRequest true "Scanned ISBNs"
+// @Success 200 {object} domain.ScanSummary
+// @Failure 400 {object} map[string]string
+// @Router /acquisitions/scan [post]
+func (h *AcquisitionHandler) ScanISBNs(c *gin.Context) {
+	var req ScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if len(req.ISBNs) == 0 || len(req.ISBNs) > domain.MaxScanBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "isbns must list between 1 and 100 ISBNs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Scan(c.Request.Context(), req.ISBNs, time.Now())})
+}
+
+// CatalogAcquisition godoc
+// @Summary Catalog an acquisition
+// @Description Add a draft to the catalog as a book. Fields left out of the request are taken from the draft; the book ID must be given, as for POST /books. Librarians only.
+// @Tags Acquisitions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Acquisition ID"
+// @Param book body domain.Book true "Corrections to the draft"
+// @Success 201 {object} domain.Book
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /acquisitions/{id}/catalog [post]
+func (h *AcquisitionHandler) CatalogAcquisition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	draft, err := h.uc.GetAcquisitionByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	var book domain.Book
+	if err := c.ShouldBindJSON(&book); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	draft.Fill(&book)
+
+	if err := book.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	book.Attributes, err = h.fields.ValidateAttributes(c.Request.Host, book.Attributes, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = h.authors.SaveBook(&book, func(b domain.Book) error {
+		book, err = h.uc.Catalog(id, b)
+		return err
+	})
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": book})
+}
+
+// DiscardAcquisition godoc
+// @Summary Discard an acquisition
+// @Description Delete a draft that is still pending cataloging. Librarians only.
+// @Tags Acquisitions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Acquisition ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /acquisitions/{id} [delete]
+func (h *AcquisitionHandler) DiscardAcquisition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.Discard(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "acquisition discarded"})
+}
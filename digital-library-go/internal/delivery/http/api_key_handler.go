@@ -0,0 +1,213 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiKeyContextKey = "apiKey"
+
+// APIKeyRequest is the body accepted when creating an API key.
+type APIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type APIKeyHandler struct {
+	uc *usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(uc *usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{uc: uc}
+}
+
+// Meter counts requests that carry an X-API-Key header against the key's
+// quotas and reports the quota closest to running out in X-RateLimit-*
+// headers. Requests without a key are counted against the same quotas
+// per client IP, so leaving the key out does not escape them.
+func (h *APIKeyHandler) Meter() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now()
+		secret := c.GetHeader("X-API-Key")
+		if secret == "" {
+			usage, err := h.uc.ConsumeIP(c.ClientIP(), quotaClass(c.Request.Method), now)
+			if !metered(c, usage, err, now) {
+				return
+			}
+			c.Next()
+			return
+		}
+
+		key, err := h.uc.Authenticate(secret)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		usage, err := h.uc.Consume(key.ID, quotaClass(c.Request.Method), now)
+		if !metered(c, usage, err, now) {
+			return
+		}
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// metered sets the X-RateLimit-* headers and answers 429 once a quota is
+// used up. It reports whether the request may go on.
+func metered(c *gin.Context, usage domain.QuotaUsage, err error, now time.Time) bool {
+	var exceeded *usecase.QuotaExceededError
+	if errors.As(err, &exceeded) {
+		setRateLimitHeaders(c, exceeded.Usage)
+		c.Header("Retry-After", strconv.Itoa(int(exceeded.Usage.ResetsAt.Sub(now).Seconds())+1))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return false
+	}
+	setRateLimitHeaders(c, usage)
+	return true
+}
+
+func quotaClass(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return usecase.QuotaRead
+	}
+	return usecase.QuotaWrite
+}
+
+func setRateLimitHeaders(c *gin.Context, usage domain.QuotaUsage) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(usage.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(usage.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(usage.ResetsAt.Unix(), 10))
+	c.Header("X-RateLimit-Resource", usage.Class+"/"+usage.Window)
+}
+
+// GetUsage godoc
+// @Summary Get usage of the calling API key
+// @Description Get the daily and monthly read and write quotas of the API key sent in X-API-Key
+// @Tags API Keys
+// @Produce json
+// @Security APIKeyAuth
+// @Success 200 {array} domain.QuotaUsage
+// @Failure 401 {object} map[string]string
+// @Router /usage [get]
+func (h *APIKeyHandler) GetUsage(c *gin.Context) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-API-Key header required"})
+		return
+	}
+	key := value.(domain.APIKey)
+
+	usage, err := h.uc.Usage(key.MemberID, key.ID, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": usage})
+}
+
+// GetKeys godoc
+// @Summary List my API keys
+// @Description Get the authenticated member's API keys. Secrets are never returned.
+// @Tags API Keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.APIKey
+// @Router /me/api-keys [get]
+func (h *APIKeyHandler) GetKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.KeysForMember(currentMemberID(c))})
+}
+
+// CreateKey godoc
+// @Summary Create an API key
+// @Description Issue an API key for the authenticated member. The secret is only returned in this response. Scopes grant the key more than reading and writing as the member: tasks:trigger lets it trigger any task type through POST /tasks/trigger/{name}, and tasks:trigger:<name> one type only; they only work while the member is a librarian or admin.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key body APIKeyRequest true "API key name and scopes"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /me/api-keys [post]
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req APIKeyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	key, secret, err := h.uc.CreateKey(currentMemberID(c), req.Name, req.Scopes)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": key, "secret": secret})
+}
+
+// RevokeKey godoc
+// @Summary Revoke an API key
+// @Description Delete one of the authenticated member's API keys
+// @Tags API Keys
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.RevokeKey(currentMemberID(c), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+// GetKeyUsage godoc
+// @Summary Get usage of one of my API keys
+// @Description Get the daily and monthly read and write quotas of one of the authenticated member's API keys
+// @Tags API Keys
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {array} domain.QuotaUsage
+// @Failure 404 {object} map[string]string
+// @Router /me/api-keys/{id}/usage [get]
+func (h *APIKeyHandler) GetKeyUsage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	usage, err := h.uc.Usage(currentMemberID(c), id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": usage})
+}
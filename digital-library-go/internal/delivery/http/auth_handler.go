@@ -0,0 +1,120 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const memberIDKey = "memberID"
+
+// LoginRequest is the body accepted by the login endpoint.
+type LoginRequest struct {
+	CardNumber string `json:"card_number"`
+	Password   string `json:"password"`
+}
+
+type AuthHandler struct {
+	uc *usecase.AuthUsecase
+}
+
+func NewAuthHandler(uc *usecase.AuthUsecase) *AuthHandler {
+	return &AuthHandler{uc: uc}
+}
+
+// Login godoc
+// @Summary Log in as a member
+// @Description Exchange a card number and password for a bearer token. Accounts with two-factor authentication get a challenge to answer at /auth/2fa/verify instead.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Card number and password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	res, err := h.uc.Login(req.CardNumber, req.Password, c.ClientIP())
+	var throttled *usecase.ThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttled.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if res.Challenge != "" {
+		c.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge": res.Challenge})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": res.Token})
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Invalidate the bearer token used for this request
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	h.uc.Logout(bearerToken(c))
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// RequireMember rejects requests without a valid bearer token and makes
+// the authenticated member's ID available to later handlers.
+func (h *AuthHandler) RequireMember() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		memberID, err := h.uc.MemberForToken(bearerToken(c))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(memberIDKey, memberID)
+		c.Next()
+	}
+}
+
+// RequireRole is like RequireMember but additionally rejects members whose
+// role is not one of roles.
+func (h *AuthHandler) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		member, err := h.uc.Member(bearerToken(c))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": usecase.ErrInvalidSession.Error()})
+			return
+		}
+		if !slices.Contains(roles, member.Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			return
+		}
+		c.Set(memberIDKey, member.ID)
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) string {
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+func currentMemberID(c *gin.Context) int {
+	return c.GetInt(memberIDKey)
+}
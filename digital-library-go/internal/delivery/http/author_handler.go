@@ -0,0 +1,243 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MergeAuthorsRequest names the authors to fold into another one.
+type MergeAuthorsRequest struct {
+	AuthorIDs []int `json:"author_ids"`
+}
+
+type AuthorHandler struct {
+	uc *usecase.AuthorUsecase
+}
+
+func NewAuthorHandler(uc *usecase.AuthorUsecase) *AuthorHandler {
+	return &AuthorHandler{uc: uc}
+}
+
+// GetAuthors godoc
+// @Summary Get all authors
+// @Description Get list of all authors
+// @Tags Authors
+// @Produce json
+// @Success 200 {array} domain.Author
+// @Router /authors [get]
+func (h *AuthorHandler) GetAuthors(c *gin.Context) {
+	authors := h.uc.GetAuthors()
+	c.JSON(http.StatusOK, gin.H{"data": authors})
+}
+
+// GetAuthorByID godoc
+// @Summary Get an author by ID
+// @Description Get author details by ID
+// @Tags Authors
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {object} domain.Author
+// @Failure 404 {object} map[string]string
+// @Router /authors/{id} [get]
+func (h *AuthorHandler) GetAuthorByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	author, err := h.uc.GetAuthorByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": author})
+}
+
+// GetAuthorBooks godoc
+// @Summary Get an author's books
+// @Description Get all books linked to an author
+// @Tags Authors
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {array} domain.Book
+// @Failure 404 {object} map[string]string
+// @Router /authors/{id}/books [get]
+func (h *AuthorHandler) GetAuthorBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	books, err := h.uc.BooksByAuthor(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": books})
+}
+
+// CreateAuthor godoc
+// @Summary Create a new author
+// @Description Add an author with an optional biography and birth year. Librarians only.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param author body domain.Author true "Author data"
+// @Success 201 {object} domain.Author
+// @Failure 400 {object} map[string]string
+// @Router /authors [post]
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var author domain.Author
+
+	if err := c.ShouldBindJSON(&author); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := author.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreateAuthor(author)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateAuthor godoc
+// @Summary Update an author
+// @Description Update author details by ID. Linked books show the new name. Librarians only.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Author ID"
+// @Param author body domain.Author true "Updated author data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var author domain.Author
+	if err := c.ShouldBindJSON(&author); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := author.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdateAuthor(id, author)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "author updated"})
+}
+
+// DeleteAuthor godoc
+// @Summary Delete an author
+// @Description Delete an author who has no linked books. Librarians only.
+// @Tags Authors
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Author ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteAuthor(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "author deleted"})
+}
+
+// MigrateAuthors godoc
+// @Summary Link legacy author strings to authors
+// @Description Link every book that only has an author string to the author of that name, creating authors as needed
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Router /admin/authors/migrate [post]
+func (h *AuthorHandler) MigrateAuthors(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"linked": h.uc.Migrate()})
+}
+
+// GetAuthorDuplicates godoc
+// @Summary Get proposed author merges
+// @Description Get groups of authors whose names probably belong to the same person, such as "J.K. Rowling" and "Rowling, J. K.", as found by the last background scan. Librarians only.
+// @Tags Authors
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.AuthorDuplicates
+// @Router /authors/duplicates [get]
+func (h *AuthorHandler) GetAuthorDuplicates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Duplicates()})
+}
+
+// MergeAuthors godoc
+// @Summary Merge authors
+// @Description Fold the given authors into this one: their books are linked to it instead and they are deleted. Librarians only.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Author ID to keep"
+// @Param merge body MergeAuthorsRequest true "Authors to merge into it"
+// @Success 200 {object} domain.Author
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /authors/{id}/merge [post]
+func (h *AuthorHandler) MergeAuthors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req MergeAuthorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if len(req.AuthorIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "author_ids must not be empty"})
+		return
+	}
+
+	author, err := h.uc.Merge(id, req.AuthorIDs)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": author})
+}
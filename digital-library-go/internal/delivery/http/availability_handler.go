@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+	uc     *usecase.AvailabilityUsecase
+	books  *usecase.BookUsecase
+	policy *usecase.ContentPolicyUsecase
+}
+
+func NewAvailabilityHandler(uc *usecase.AvailabilityUsecase, books *usecase.BookUsecase, policy *usecase.ContentPolicyUsecase) *AvailabilityHandler {
+	return &AvailabilityHandler{uc: uc, books: books, policy: policy}
+}
+
+// GetBookAvailability godoc
+// @Summary Get a book's availability
+// @Description Get whether a book can be borrowed now and, if not, when it is expected to be free for a member joining the hold queue, based on its due date, how often loans come back late and the length of the queue
+// @Tags Circulation
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {object} domain.Availability
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/availability [get]
+func (h *AvailabilityHandler) GetBookAvailability(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	book, err := h.books.GetBookByID(id)
+	if err != nil || !h.policy.Allows(book, audienceOf(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+		return
+	}
+
+	availability, err := h.uc.Predict(id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": availability})
+}
+
+// GetBookBranchAvailability godoc
+// @Summary Get a book's availability at each branch
+// @Description Get for every branch how many copies of a book it has in circulation and how many are in transit there, to choose where to pick up a hold. Loans are for the book rather than a copy, so no status is given per branch; see /books/{id}/availability for whether the book can be borrowed now.
+// @Tags Circulation
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} domain.AvailabilityAtBranch
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/availability/branches [get]
+func (h *AvailabilityHandler) GetBookBranchAvailability(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	book, err := h.books.GetBookByID(id)
+	if err != nil || !h.policy.Allows(book, audienceOf(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+		return
+	}
+
+	branches, err := h.uc.ByBranch(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": branches})
+}
@@ -1,52 +1,95 @@
 package http
 
 import (
-	"log"
+	"errors"
 	"net/http"
-	"sync"
 	"time"
 
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/lock"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
 	"github.com/gin-gonic/gin"
 )
 
-/* SHARED LOCK */
-var TaskMu sync.Mutex
+// heavyTaskLockTTL is how long the task's lock outlives an instance that
+// crashed while running it.
+const heavyTaskLockTTL = 30 * time.Second
 
 type TaskHandler struct {
-	taskRunning *bool
+	maintenance *usecase.MaintenanceUsecase
+	locker      lock.Locker
+	tasks       *usecase.TaskUsecase
 }
 
-func NewTaskHandler(flag *bool) *TaskHandler {
-	return &TaskHandler{taskRunning: flag}
+func NewTaskHandler(maintenance *usecase.MaintenanceUsecase, locker lock.Locker, tasks *usecase.TaskUsecase) *TaskHandler {
+	return &TaskHandler{maintenance: maintenance, locker: locker, tasks: tasks}
 }
 
 // RunHeavyTask godoc
 // @Summary Run blocking background task
-// @Description Runs a critical update task. Other requests wait until completion.
+// @Description Runs a critical update task in maintenance mode: until it completes, requests that change data get 503 while reads carry on. Only one instance runs it at a time. The task runs on the task queue under a timeout; if it hangs past it, maintenance mode is turned off and the request fails with 500.
 // @Tags Background Task
 // @Produce json
 // @Success 200 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /tasks/process [post]
 func (h *TaskHandler) RunHeavyTask(c *gin.Context) {
-	TaskMu.Lock()
-	if *h.taskRunning {
-		TaskMu.Unlock()
-		c.JSON(http.StatusConflict, gin.H{"error": "task already running"})
+	l, err := h.locker.TryLock(c.Request.Context(), "heavy-task", heavyTaskLockTTL)
+	if errors.Is(err, lock.ErrHeld) {
+		c.JSON(http.StatusConflict, gin.H{"error": "task is already running"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
-	*h.taskRunning = true
-	TaskMu.Unlock()
 
-	log.Println("Task started")
+	if _, started := h.maintenance.Enable("task", "A critical update is running", 10, time.Now()); !started {
+		l.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "maintenance mode is already on"})
+		return
+	}
+	release := func() {
+		// An admin may have taken over maintenance mode meanwhile; leave
+		// it on for them.
+		if h.maintenance.Status().By == "task" {
+			h.maintenance.Disable("task", time.Now())
+		}
+		l.Unlock()
+	}
+
+	task, err := h.tasks.Enqueue(domain.TaskCriticalUpdate, domain.TaskPriorityUser, 0, func(t *usecase.TaskTracker) any {
+		t.OnRelease(release)
+		t.Logf("Updating the database")
 
-	// Simulate heavy DB update
-	time.Sleep(8 * time.Second)
+		// Simulate heavy DB update
+		select {
+		case <-time.After(8 * time.Second):
+		case <-t.Context().Done():
+			return nil
+		}
 
-	log.Println("Task finished")
+		t.Logf("Database updated")
+		return nil
+	})
+	if err != nil {
+		release()
+		abort(c, err)
+		return
+	}
 
-	TaskMu.Lock()
-	*h.taskRunning = false
-	TaskMu.Unlock()
+	task, err = h.tasks.Wait(c.Request.Context(), task.ID)
+	if err != nil {
+		// The client has gone; the task carries on without it.
+		return
+	}
+	if task.Status == domain.TaskFailed {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "critical update " + task.Error})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task completed successfully",
@@ -0,0 +1,279 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookingHandler struct {
+	uc      *usecase.BookingUsecase
+	members *usecase.MemberUsecase
+}
+
+func NewBookingHandler(uc *usecase.BookingUsecase, members *usecase.MemberUsecase) *BookingHandler {
+	return &BookingHandler{uc: uc, members: members}
+}
+
+// GetResources godoc
+// @Summary Get all bookable resources
+// @Description Get list of all rooms and equipment that can be booked
+// @Tags Bookings
+// @Produce json
+// @Success 200 {array} domain.Resource
+// @Router /bookings/resources [get]
+func (h *BookingHandler) GetResources(c *gin.Context) {
+	resources := h.uc.GetResources()
+	c.JSON(http.StatusOK, gin.H{"data": resources})
+}
+
+// GetResourceByID godoc
+// @Summary Get a bookable resource by ID
+// @Description Get resource details by ID
+// @Tags Bookings
+// @Produce json
+// @Param id path int true "Resource ID"
+// @Success 200 {object} domain.Resource
+// @Failure 404 {object} map[string]string
+// @Router /bookings/resources/{id} [get]
+func (h *BookingHandler) GetResourceByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	resource, err := h.uc.GetResourceByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resource})
+}
+
+// GetAvailability godoc
+// @Summary Get a resource's availability
+// @Description Get the library's hours on a date and the times the resource is already booked
+// @Tags Bookings
+// @Produce json
+// @Param id path int true "Resource ID"
+// @Param date query string false "Date, YYYY-MM-DD (default today)"
+// @Success 200 {object} domain.ResourceAvailability
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /bookings/resources/{id}/availability [get]
+func (h *BookingHandler) GetAvailability(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	day := time.Now()
+	if v := c.Query("date"); v != "" {
+		day, err = time.ParseInLocation(time.DateOnly, v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be a date like 2024-01-31"})
+			return
+		}
+	}
+
+	availability, err := h.uc.Availability(id, day)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": availability})
+}
+
+// CreateResource godoc
+// @Summary Create a bookable resource
+// @Description Add a room or piece of equipment members can book. Librarians only.
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param resource body domain.Resource true "Resource data"
+// @Success 201 {object} domain.Resource
+// @Failure 400 {object} map[string]string
+// @Router /bookings/resources [post]
+func (h *BookingHandler) CreateResource(c *gin.Context) {
+	var resource domain.Resource
+
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := resource.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreateResource(resource)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateResource godoc
+// @Summary Update a bookable resource
+// @Description Update resource details by ID. Librarians only.
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Resource ID"
+// @Param resource body domain.Resource true "Updated resource data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /bookings/resources/{id} [put]
+func (h *BookingHandler) UpdateResource(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var resource domain.Resource
+	if err := c.ShouldBindJSON(&resource); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := resource.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdateResource(id, resource)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "resource updated"})
+}
+
+// DeleteResource godoc
+// @Summary Delete a bookable resource
+// @Description Delete a resource without upcoming bookings. Librarians only.
+// @Tags Bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Resource ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /bookings/resources/{id} [delete]
+func (h *BookingHandler) DeleteResource(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteResource(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "resource deleted"})
+}
+
+// GetBookings godoc
+// @Summary Get my bookings
+// @Description Get the authenticated member's bookings, including cancelled ones
+// @Tags Bookings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Booking
+// @Router /bookings [get]
+func (h *BookingHandler) GetBookings(c *gin.Context) {
+	bookings := h.uc.BookingsForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": bookings})
+}
+
+// CreateBooking godoc
+// @Summary Book a resource
+// @Description Book a room or device for the authenticated member. Start and end are on the quarter hour, at most 4 hours apart, within the opening hours of one day. A confirmation is sent to /me/notifications.
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param booking body domain.Booking true "Resource and time slot"
+// @Success 201 {object} domain.Booking
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /bookings [post]
+func (h *BookingHandler) CreateBooking(c *gin.Context) {
+	var booking domain.Booking
+
+	if err := c.ShouldBindJSON(&booking); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := booking.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.Book(currentMemberID(c), booking)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// CancelBooking godoc
+// @Summary Cancel a booking
+// @Description Cancel one of the authenticated member's bookings. Staff may cancel any booking; the member is notified.
+// @Tags Bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} domain.Booking
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /bookings/{id} [delete]
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	booking, err := h.uc.GetBookingByID(id)
+	if err != nil || !h.canManage(c, booking) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+
+	booking, err = h.uc.Cancel(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": booking})
+}
+
+// canManage reports whether the authenticated member owns the booking or
+// is staff.
+func (h *BookingHandler) canManage(c *gin.Context, booking domain.Booking) bool {
+	if booking.MemberID == currentMemberID(c) {
+		return true
+	}
+	member, err := h.members.GetMemberByID(currentMemberID(c))
+	return err == nil && member.IsStaff()
+}
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxNearbyRadius is the widest search for nearby branches, in
+// kilometres.
+const maxNearbyRadius = 500
+
+type BranchHandler struct {
+	uc           *usecase.BranchUsecase
+	copies       *usecase.CopyUsecase
+	availability *usecase.AvailabilityUsecase
+	books        *usecase.BookUsecase
+	policy       *usecase.ContentPolicyUsecase
+}
+
+func NewBranchHandler(uc *usecase.BranchUsecase, copies *usecase.CopyUsecase, availability *usecase.AvailabilityUsecase, books *usecase.BookUsecase, policy *usecase.ContentPolicyUsecase) *BranchHandler {
+	return &BranchHandler{uc: uc, copies: copies, availability: availability, books: books, policy: policy}
+}
+
+// GetBranches godoc
+// @Summary Get all branches
+// @Description Get list of the library's branches with their addresses and locations
+// @Tags Branches
+// @Produce json
+// @Success 200 {array} domain.Branch
+// @Router /branches [get]
+func (h *BranchHandler) GetBranches(c *gin.Context) {
+	branches := h.uc.GetBranches()
+	c.JSON(http.StatusOK, gin.H{"data": branches})
+}
+
+// GetBranchByID godoc
+// @Summary Get a branch by ID
+// @Description Get branch details by ID
+// @Tags Branches
+// @Produce json
+// @Param id path int true "Branch ID"
+// @Success 200 {object} domain.Branch
+// @Failure 404 {object} map[string]string
+// @Router /branches/{id} [get]
+func (h *BranchHandler) GetBranchByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	branch, err := h.uc.GetBranchByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": branch})
+}
+
+// GetNearbyBranches godoc
+// @Summary Find branches near a point
+// @Description Get the branches within a radius of a point, nearest first, with their distance in kilometres. With book_id each branch says how many copies of the book it has in circulation and whether the book can be borrowed, or not_held when it has none.
+// @Tags Branches
+// @Produce json
+// @Param lat query number true "Latitude in decimal degrees"
+// @Param lng query number true "Longitude in decimal degrees"
+// @Param radius query number false "Radius in kilometres (default 10, max 500)"
+// @Param book_id query int false "Book to show the availability of"
+// @Param audience query string false "all or children"
+// @Success 200 {array} domain.NearbyBranch
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /branches/nearby [get]
+func (h *BranchHandler) GetNearbyBranches(c *gin.Context) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng must be numbers"})
+		return
+	}
+	if err := domain.ValidateCoordinates(lat, lng); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	radius := domain.DefaultNearbyRadius
+	if v := c.Query("radius"); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil || !(r > 0 && r <= maxNearbyRadius) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "radius must be more than 0 and at most 500"})
+			return
+		}
+		radius = r
+	}
+
+	bookID := 0
+	if v := c.Query("book_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book_id"})
+			return
+		}
+		book, err := h.books.GetBookByID(id)
+		if err != nil || !h.policy.Allows(book, audienceOf(c)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+			return
+		}
+		bookID = id
+	}
+
+	nearby, err := h.availability.NearbyBranches(lat, lng, radius, bookID, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": nearby})
+}
+
+// CreateBranch godoc
+// @Summary Create a branch
+// @Description Add a branch with its address and its location in decimal degrees. Admins only.
+// @Tags Branches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param branch body domain.Branch true "Branch data"
+// @Success 201 {object} domain.Branch
+// @Failure 400 {object} map[string]string
+// @Router /branches [post]
+func (h *BranchHandler) CreateBranch(c *gin.Context) {
+	var branch domain.Branch
+
+	if err := c.ShouldBindJSON(&branch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := branch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreateBranch(branch)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateBranch godoc
+// @Summary Update a branch
+// @Description Update branch details by ID, e.g. when it moves. Admins only.
+// @Tags Branches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Branch ID"
+// @Param branch body domain.Branch true "Updated branch data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /branches/{id} [put]
+func (h *BranchHandler) UpdateBranch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var branch domain.Branch
+	if err := c.ShouldBindJSON(&branch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := branch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.uc.UpdateBranch(id, branch); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "branch updated"})
+}
+
+// DeleteBranch godoc
+// @Summary Delete a branch
+// @Description Delete a branch no copy is kept at. Admins only.
+// @Tags Branches
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /branches/{id} [delete]
+func (h *BranchHandler) DeleteBranch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.copies.DeleteBranch(id); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "branch deleted"})
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BrowseHandler struct {
+	uc         *usecase.BrowseUsecase
+	federation *usecase.FederationUsecase
+}
+
+func NewBrowseHandler(uc *usecase.BrowseUsecase, federation *usecase.FederationUsecase) *BrowseHandler {
+	return &BrowseHandler{uc: uc, federation: federation}
+}
+
+// GetFacets godoc
+// @Summary Faceted browse
+// @Description Search books by title or author and get counts by author, genre, decade, language, format and availability. Passing a facet name as a parameter narrows the results to that value. Books found by q are ranked by the search ranking weights of the host the request was sent to, best first. Titles found by q in consortium partners' catalogs that the library lacks are listed under remote, each with its source partner, unless partners=false.
+// @Tags Library
+// @Produce json
+// @Param q query string false "Text to find in title or author"
+// @Param author query string false "Selected author"
+// @Param genre query string false "Selected genre"
+// @Param decade query string false "Selected decade, e.g. 1960s"
+// @Param language query string false "Selected language, an ISO 639 code such as es or spa"
+// @Param format query string false "print, audiobook, dvd or magazine"
+// @Param availability query string false "available or on_loan"
+// @Param audience query string false "all or children"
+// @Param partners query bool false "false to leave out partners' titles"
+// @Success 200 {object} domain.BrowseResult
+// @Router /books/facets [get]
+func (h *BrowseHandler) GetFacets(c *gin.Context) {
+	q, audience := c.Query("q"), audienceOf(c)
+	result, err := h.uc.Browse(q, selectedFacets(c), audience, c.Request.Host)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if q != "" && c.Query("partners") != "false" {
+		result.Remote = h.federation.Search(c.Request.Context(), q, audience)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// selectedFacets reads the facet values a search is narrowed to.
+func selectedFacets(c *gin.Context) map[string]string {
+	selected := map[string]string{}
+	for _, facet := range []string{domain.FacetAuthor, domain.FacetGenre, domain.FacetDecade, domain.FacetLanguage, domain.FacetFormat, domain.FacetAvailability} {
+		if v := c.Query(facet); v != "" {
+			selected[facet] = v
+		}
+	}
+	if lang, ok := selected[domain.FacetLanguage]; ok {
+		selected[domain.FacetLanguage] = domain.NormalizeLanguage(lang)
+	}
+	return selected
+}
+
+// Suggest godoc
+// @Summary Autocomplete titles and authors
+// @Description Get ranked title and author completions for a partial query. Prefix matches come first; close misspellings fill the rest.
+// @Tags Library
+// @Produce json
+// @Param q query string true "Partial query, e.g. har"
+// @Param limit query int false "Maximum completions (default 10, max 25)"
+// @Param audience query string false "all or children"
+// @Success 200 {array} domain.Suggestion
+// @Failure 400 {object} map[string]string
+// @Router /books/suggest [get]
+func (h *BrowseHandler) Suggest(c *gin.Context) {
+	limit := 10
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 25 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 25"})
+			return
+		}
+		limit = n
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Suggest(c.Query("q"), limit, audienceOf(c))})
+}
+
+// GetLanguages godoc
+// @Summary Count books by language
+// @Description Get how many books there are in each language, most common first. Books with no language are not counted.
+// @Tags Library
+// @Produce json
+// @Param audience query string false "all or children"
+// @Success 200 {array} domain.FacetCount
+// @Router /books/languages [get]
+func (h *BrowseHandler) GetLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Languages(audienceOf(c))})
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetHandler struct {
+	uc *usecase.BudgetUsecase
+}
+
+func NewBudgetHandler(uc *usecase.BudgetUsecase) *BudgetHandler {
+	return &BudgetHandler{uc: uc}
+}
+
+// GetBudget godoc
+// @Summary Get acquisition spending
+// @Description Sum the purchase prices of the copies bought in a date range, per fund and per month, quarter or year. Amounts are in cents. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day, YYYY-MM-DD (default 1 January this year)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param period query string false "month, quarter or year (default month)"
+// @Success 200 {object} domain.BudgetSummary
+// @Failure 400 {object} map[string]string
+// @Router /admin/budget [get]
+func (h *BudgetHandler) GetBudget(c *gin.Context) {
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date like 2024-12-31"})
+			return
+		}
+		to = t
+	}
+	from := time.Date(to.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date like 2024-01-01"})
+			return
+		}
+		from = t
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	period := c.DefaultQuery("period", domain.PeriodMonth)
+	summary, err := h.uc.Summary(from, to, period)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": summary})
+}
+
+// GetFundCopies godoc
+// @Summary Get the copies bought from a fund
+// @Description Get the copies charged to a fund code, most recently bought first. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Fund code"
+// @Success 200 {array} domain.Copy
+// @Router /admin/budget/funds/{code}/copies [get]
+func (h *BudgetHandler) GetFundCopies(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.FundCopies(c.Param("code"))})
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/ical"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedToken is a member's calendar feed token and the path to subscribe to.
+type FeedToken struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+// CalendarFeedHandler serves members' due dates and event registrations
+// as an iCalendar feed.
+type CalendarFeedHandler struct {
+	feeds  *usecase.CalendarFeedUsecase
+	books  *usecase.BookUsecase
+	loans  *usecase.LoanUsecase
+	events *usecase.EventUsecase
+}
+
+func NewCalendarFeedHandler(feeds *usecase.CalendarFeedUsecase, books *usecase.BookUsecase, loans *usecase.LoanUsecase, events *usecase.EventUsecase) *CalendarFeedHandler {
+	return &CalendarFeedHandler{feeds: feeds, books: books, loans: loans, events: events}
+}
+
+// RequireFeedAccess accepts a feed token in the token query parameter,
+// for calendar apps, and otherwise falls back to the bearer token.
+func (h *CalendarFeedHandler) RequireFeedAccess(ah *AuthHandler) gin.HandlerFunc {
+	requireMember := ah.RequireMember()
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			requireMember(c)
+			return
+		}
+		memberID, err := h.feeds.MemberForToken(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(memberIDKey, memberID)
+		c.Next()
+	}
+}
+
+// GetDueDatesICS godoc
+// @Summary Get my calendar feed
+// @Description Get the authenticated member's loan due dates and event registrations as an iCalendar feed. Calendar apps that cannot send an Authorization header pass the token from /me/due-dates/token instead.
+// @Tags Me
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param token query string false "Feed token"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string
+// @Router /me/due-dates.ics [get]
+func (h *CalendarFeedHandler) GetDueDatesICS(c *gin.Context) {
+	memberID := currentMemberID(c)
+	cal := ical.Calendar{Name: "Library due dates", Events: []ical.Event{}}
+
+	for _, l := range h.loans.ActiveLoansForMember(memberID) {
+		title := fmt.Sprintf("book #%d", l.BookID)
+		if book, err := h.books.GetBookByID(l.BookID); err == nil {
+			title = book.Title
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			UID:     fmt.Sprintf("loan-%d@digital-library", l.ID),
+			Summary: "Due: " + title,
+			Start:   l.DueAt.Local(),
+			AllDay:  true,
+		})
+	}
+
+	for _, r := range h.events.RegistrationsForMember(memberID) {
+		event, err := h.events.GetEventByID(r.EventID)
+		if err != nil {
+			continue
+		}
+		e := calendarEvent(event)
+		if r.Status == domain.RegistrationWaitlisted {
+			e.Summary = "Waitlisted: " + e.Summary
+		}
+		cal.Events = append(cal.Events, e)
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", cal.Bytes())
+}
+
+// GetFeedToken godoc
+// @Summary Get my calendar feed token
+// @Description Get the token that lets a calendar app subscribe to /me/due-dates.ics without logging in
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} FeedToken
+// @Router /me/due-dates/token [get]
+func (h *CalendarFeedHandler) GetFeedToken(c *gin.Context) {
+	token, err := h.feeds.Token(currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": feedToken(token)})
+}
+
+// RotateFeedToken godoc
+// @Summary Rotate my calendar feed token
+// @Description Replace the calendar feed token, so subscriptions using the old one stop working
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} FeedToken
+// @Router /me/due-dates/token [post]
+func (h *CalendarFeedHandler) RotateFeedToken(c *gin.Context) {
+	token, err := h.feeds.RotateToken(currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": feedToken(token)})
+}
+
+func feedToken(token string) FeedToken {
+	return FeedToken{Token: token, Path: "/me/due-dates.ics?token=" + token}
+}
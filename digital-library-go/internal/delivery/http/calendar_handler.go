@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	uc *usecase.CalendarUsecase
+}
+
+func NewCalendarHandler(uc *usecase.CalendarUsecase) *CalendarHandler {
+	return &CalendarHandler{uc: uc}
+}
+
+// GetCalendar godoc
+// @Summary Get the library calendar
+// @Description Get whether the library is open on each date, and its hours
+// @Tags Calendar
+// @Produce json
+// @Param from query string false "First day, YYYY-MM-DD (default today)"
+// @Param to query string false "Last day, YYYY-MM-DD (default 30 days after from, at most 366)"
+// @Success 200 {array} domain.CalendarDay
+// @Failure 400 {object} map[string]string
+// @Router /calendar [get]
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	from := time.Now()
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date like 2024-01-01"})
+			return
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, 30)
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date like 2024-01-31"})
+			return
+		}
+		to = t
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, 366)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be on or after from, and at most 366 days later"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Days(from, to)})
+}
+
+// GetHours godoc
+// @Summary Get the opening hours
+// @Description Get the regular opening hours per weekday (0 is Sunday). Missing weekdays are closed.
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.OpeningHours
+// @Router /admin/calendar/hours [get]
+func (h *CalendarHandler) GetHours(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetHours()})
+}
+
+// SetHours godoc
+// @Summary Set the opening hours
+// @Description Replace the weekly opening hours. Weekdays left out are closed. Existing loans keep their due dates.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hours body []domain.OpeningHours true "Opening hours per weekday"
+// @Success 200 {array} domain.OpeningHours
+// @Failure 400 {object} map[string]string
+// @Router /admin/calendar/hours [put]
+func (h *CalendarHandler) SetHours(c *gin.Context) {
+	var hours []domain.OpeningHours
+
+	if err := c.ShouldBindJSON(&hours); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	for _, oh := range hours {
+		if err := oh.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.uc.SetHours(hours); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetHours()})
+}
+
+// GetClosedDays godoc
+// @Summary Get the closed days
+// @Description Get the dates the library is closed on besides its regular closing days
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.ClosedDay
+// @Router /admin/calendar/closed [get]
+func (h *CalendarHandler) GetClosedDays(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetClosedDays()})
+}
+
+// AddClosedDay godoc
+// @Summary Add a closed day
+// @Description Close the library on a date, e.g. a public holiday. Existing loans keep their due dates.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param day body domain.ClosedDay true "Closed day"
+// @Success 201 {object} domain.ClosedDay
+// @Failure 400 {object} map[string]string
+// @Router /admin/calendar/closed [post]
+func (h *CalendarHandler) AddClosedDay(c *gin.Context) {
+	var day domain.ClosedDay
+
+	if err := c.ShouldBindJSON(&day); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := day.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.uc.AddClosedDay(day)
+	c.JSON(http.StatusCreated, gin.H{"data": day})
+}
+
+// RemoveClosedDay godoc
+// @Summary Remove a closed day
+// @Description Reopen the library on a date closed with POST /admin/calendar/closed
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Param date path string true "Date, YYYY-MM-DD"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/calendar/closed/{date} [delete]
+func (h *CalendarHandler) RemoveClosedDay(c *gin.Context) {
+	if err := h.uc.RemoveClosedDay(c.Param("date")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "closed day not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "closed day removed"})
+}
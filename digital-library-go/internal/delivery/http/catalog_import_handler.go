@@ -0,0 +1,104 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/bookimport"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogImportHandler struct {
+	uc *usecase.CatalogImportUsecase
+}
+
+func NewCatalogImportHandler(uc *usecase.CatalogImportUsecase) *CatalogImportHandler {
+	return &CatalogImportHandler{uc: uc}
+}
+
+// PreviewCatalogImport godoc
+// @Summary Preview a catalog import
+// @Description Read a CSV or tab-separated file of books without importing it, and return its columns, the first rows and the column mapping guessed from the column names. Send the file as the request body, or as the "file" field of a multipart form. Librarians only.
+// @Tags Books
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.ImportPreview
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /books/import/preview [post]
+func (h *CatalogImportHandler) PreviewCatalogImport(c *gin.Context) {
+	file, err := importFile(c)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	defer file.Close()
+
+	table, err := bookimport.ReadTable(file)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": bookimport.Preview(table)})
+}
+
+// ImportCatalog godoc
+// @Summary Import books
+// @Description Queue the import of the books in a CSV or tab-separated file. Send a multipart form with the "file" and a "mapping" field holding a JSON object from book field to column name, e.g. {"title": "Book Title", "year": "Published"}. Without a mapping, the one suggested by the preview is used. The file is checked before the task is queued; follow its progress with GET /tasks/{id} and download the rows that failed from GET /tasks/{id}/errors. Librarians only.
+// @Tags Books
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} domain.Task
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /books/import [post]
+func (h *CatalogImportHandler) ImportCatalog(c *gin.Context) {
+	file, err := importFile(c)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	defer file.Close()
+
+	table, err := bookimport.ReadTable(file)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	mapping := bookimport.Suggest(table.Header)
+	if v := c.PostForm("mapping"); v != "" {
+		mapping = domain.ColumnMapping{}
+		if err := json.Unmarshal([]byte(v), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of field to column"})
+			return
+		}
+	}
+	if err := mapping.Validate(table.Header); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := bookimport.Catalog(table, mapping)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	task, err := h.uc.Start(rows)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": task})
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigHandler struct {
+	uc   *usecase.ConfigUsecase
+	auth *usecase.AuthUsecase
+}
+
+func NewConfigHandler(uc *usecase.ConfigUsecase, auth *usecase.AuthUsecase) *ConfigHandler {
+	return &ConfigHandler{uc: uc, auth: auth}
+}
+
+// GetConfig godoc
+// @Summary Get the runtime configuration
+// @Description Get the settings in force that can be reloaded without a restart: the request log level, the CORS origins, the API key rate limits and the feature flags spec. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.RuntimeConfig
+// @Router /admin/config [get]
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Current()})
+}
+
+// ReloadConfig godoc
+// @Summary Reload the runtime configuration
+// @Description Read CONFIG_FILE and the environment again and apply the settings that changed, as SIGHUP does. Each change is written to the audit log as config_changed. A configuration that is invalid is refused and the current one stays in force. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.ConfigChange
+// @Failure 400 {object} map[string]string
+// @Router /admin/config/reload [post]
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	admin, _ := h.auth.Member(bearerToken(c))
+	changes, err := h.uc.Reload(admin.CardNumber, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": changes})
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const audienceKey = "audience"
+
+// TenantAudienceRequest is the body accepted when setting a tenant's
+// default audience.
+type TenantAudienceRequest struct {
+	DefaultAudience string `json:"default_audience"`
+}
+
+type ContentPolicyHandler struct {
+	uc   *usecase.ContentPolicyUsecase
+	auth *usecase.AuthUsecase
+}
+
+func NewContentPolicyHandler(uc *usecase.ContentPolicyUsecase, auth *usecase.AuthUsecase) *ContentPolicyHandler {
+	return &ContentPolicyHandler{uc: uc, auth: auth}
+}
+
+// Audience decides who a request's catalog listings are for. Anyone can
+// ask for ?audience=children; child accounts always get it, whatever
+// they ask for. Requests that do not ask get the default audience of
+// the tenant they were sent to. Requests without a valid bearer token
+// are not rejected.
+func (h *ContentPolicyHandler) Audience() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		audience := c.Query("audience")
+		if audience != "" && !domain.ValidAudience(audience) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "audience must be all or children"})
+			return
+		}
+		if audience == "" {
+			audience = h.uc.DefaultAudience(c.Request.Host)
+		}
+		if token := bearerToken(c); token != "" {
+			if member, err := h.auth.Member(token); err == nil && member.Audience == domain.AudienceChildren {
+				audience = domain.AudienceChildren
+			}
+		}
+		c.Set(audienceKey, audience)
+		c.Next()
+	}
+}
+
+// GetPolicy godoc
+// @Summary Get the content policy
+// @Description Get which age ratings are shown to children, and in tenant_audiences the default audience of each tenant that has one. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.ContentPolicy
+// @Router /admin/content-policy [get]
+func (h *ContentPolicyHandler) GetPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetPolicy(), "tenant_audiences": h.uc.TenantAudiences()})
+}
+
+// UpdatePolicy godoc
+// @Summary Update the content policy
+// @Description Set the highest age rating shown to children and whether unrated books are hidden from them. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param policy body domain.ContentPolicy true "Content policy"
+// @Success 200 {object} domain.ContentPolicy
+// @Failure 400 {object} map[string]string
+// @Router /admin/content-policy [put]
+func (h *ContentPolicyHandler) UpdatePolicy(c *gin.Context) {
+	var policy domain.ContentPolicy
+
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := policy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.uc.SetPolicy(policy)
+	c.JSON(http.StatusOK, gin.H{"data": policy})
+}
+
+// SetTenantAudience godoc
+// @Summary Set a tenant's default audience
+// @Description Set the audience, all or children, of catalog listings on one tenant, the host name a site is served on, for requests that do not ask for one. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant host name"
+// @Param audience body TenantAudienceRequest true "Default audience"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /admin/content-policy/tenants/{tenant} [put]
+func (h *ContentPolicyHandler) SetTenantAudience(c *gin.Context) {
+	var req TenantAudienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := h.uc.SetTenantAudience(c.Param("tenant"), req.DefaultAudience); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.TenantAudiences()})
+}
+
+// ClearTenantAudience godoc
+// @Summary Remove a tenant's default audience
+// @Description Let a tenant's listings be for everyone again unless a request asks for children. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant host name"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/content-policy/tenants/{tenant} [delete]
+func (h *ContentPolicyHandler) ClearTenantAudience(c *gin.Context) {
+	if err := h.uc.ClearTenantAudience(c.Param("tenant")); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.TenantAudiences()})
+}
+
+// audienceOf returns the audience the Audience middleware chose.
+func audienceOf(c *gin.Context) string {
+	return c.GetString(audienceKey)
+}
@@ -0,0 +1,339 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WithdrawCopyRequest gives the reason code for withdrawing a copy and an
+// optional note.
+type WithdrawCopyRequest struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+// TransferCopyRequest names the branch a copy is sent to.
+type TransferCopyRequest struct {
+	BranchID int `json:"branch_id"`
+}
+
+type CopyHandler struct {
+	uc    *usecase.CopyUsecase
+	holds *usecase.HoldUsecase
+}
+
+func NewCopyHandler(uc *usecase.CopyUsecase, holds *usecase.HoldUsecase) *CopyHandler {
+	return &CopyHandler{uc: uc, holds: holds}
+}
+
+// GetCopies godoc
+// @Summary Get all copies
+// @Description Get list of physical copies, optionally only those within a location such as 2 or 2/Fiction, and in shelf order with sort=call_number
+// @Tags Copies
+// @Produce json
+// @Param location query string false "Floor, floor/section or floor/section/shelf"
+// @Param sort query string false "call_number for shelf order"
+// @Success 200 {array} domain.Copy
+// @Failure 400 {object} map[string]string
+// @Router /copies [get]
+func (h *CopyHandler) GetCopies(c *gin.Context) {
+	sort := c.Query("sort")
+	if sort != "" && sort != "call_number" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be call_number"})
+		return
+	}
+
+	copies := h.uc.FindCopies(c.Query("location"), sort == "call_number")
+	c.JSON(http.StatusOK, gin.H{"data": copies})
+}
+
+// GetCopyByID godoc
+// @Summary Get a copy by ID
+// @Description Get copy details by ID
+// @Tags Copies
+// @Produce json
+// @Param id path int true "Copy ID"
+// @Success 200 {object} domain.Copy
+// @Failure 404 {object} map[string]string
+// @Router /copies/{id} [get]
+func (h *CopyHandler) GetCopyByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	copy, err := h.uc.GetCopyByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
+
+// GetBookCopies godoc
+// @Summary Get the copies of a book
+// @Description Get every physical copy of a book
+// @Tags Copies
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} domain.Copy
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/copies [get]
+func (h *CopyHandler) GetBookCopies(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	copies, err := h.uc.CopiesForBook(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copies})
+}
+
+// CreateCopy godoc
+// @Summary Create a copy
+// @Description Add a physical copy of a book with its barcode, shelf location and call number. Librarians only.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param copy body domain.Copy true "Copy data"
+// @Success 201 {object} domain.Copy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /copies [post]
+func (h *CopyHandler) CreateCopy(c *gin.Context) {
+	var copy domain.Copy
+
+	if err := c.ShouldBindJSON(&copy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := copy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.CreateCopy(copy)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateCopy godoc
+// @Summary Update a copy
+// @Description Update copy details by ID, e.g. to move it to another shelf. Librarians only.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Param copy body domain.Copy true "Updated copy data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /copies/{id} [put]
+func (h *CopyHandler) UpdateCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var copy domain.Copy
+	if err := c.ShouldBindJSON(&copy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := copy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdateCopy(id, copy)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "copy updated"})
+}
+
+// DeleteCopy godoc
+// @Summary Delete a copy
+// @Description Delete copy by ID, e.g. when it was added by mistake. Weeded copies should be withdrawn instead, which keeps them on record. Librarians only.
+// @Tags Copies
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /copies/{id} [delete]
+func (h *CopyHandler) DeleteCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteCopy(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "copy deleted"})
+}
+
+// WithdrawCopy godoc
+// @Summary Withdraw a copy
+// @Description Take a copy out of circulation with a reason code: damaged, lost, outdated, superseded, low_circulation or duplicate. The copy stays on record. Librarians only.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Param withdrawal body WithdrawCopyRequest true "Reason code and note"
+// @Success 200 {object} domain.Copy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /copies/{id}/withdraw [post]
+func (h *CopyHandler) WithdrawCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req WithdrawCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if !domain.ValidWithdrawalReason(req.Reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason must be one of damaged, lost, outdated, superseded, low_circulation, duplicate"})
+		return
+	}
+
+	copy, err := h.uc.Withdraw(id, req.Reason, req.Note, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
+
+// ReinstateCopy godoc
+// @Summary Reinstate a copy
+// @Description Put a withdrawn copy back into circulation, e.g. when a lost copy turns up. Librarians only.
+// @Tags Copies
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Success 200 {object} domain.Copy
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /copies/{id}/reinstate [post]
+func (h *CopyHandler) ReinstateCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	copy, err := h.uc.Reinstate(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
+
+// TransferCopy godoc
+// @Summary Send a copy to another branch
+// @Description Send a copy from its branch to another one. It is in transit, and still belongs to its branch, until it is received. Librarians only.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Param transfer body TransferCopyRequest true "Branch to send the copy to"
+// @Success 200 {object} domain.Copy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /copies/{id}/transfer [post]
+func (h *CopyHandler) TransferCopy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req TransferCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if req.BranchID < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "branch_id is required"})
+		return
+	}
+
+	copy, err := h.uc.Transfer(id, req.BranchID, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
+
+// ReceiveCopyTransfer godoc
+// @Summary Receive a copy in transit
+// @Description Record that a copy in transit arrived at the branch it was sent to, which it then belongs to. A hold the copy was sent for becomes ready for pickup and its member is told. Librarians only.
+// @Tags Copies
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Success 200 {object} domain.Copy
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /copies/{id}/transfer/receive [post]
+func (h *CopyHandler) ReceiveCopyTransfer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	copy, err := h.holds.ReceiveTransfer(id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReserveCopyRequest struct {
+	LoanRule string `json:"loan_rule"`
+}
+
+type CourseHandler struct {
+	uc *usecase.CourseUsecase
+}
+
+func NewCourseHandler(uc *usecase.CourseUsecase) *CourseHandler {
+	return &CourseHandler{uc: uc}
+}
+
+// GetCourses godoc
+// @Summary Get courses
+// @Description Get the courses that have books on reserve
+// @Tags Course Reserves
+// @Produce json
+// @Success 200 {array} domain.Course
+// @Router /courses [get]
+func (h *CourseHandler) GetCourses(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetCourses()})
+}
+
+// GetCourseReserves godoc
+// @Summary Get a course's reserves
+// @Description Get a course with the books on reserve for it and the copies set aside on short-loan rules
+// @Tags Course Reserves
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /courses/{id} [get]
+func (h *CourseHandler) GetCourseReserves(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	course, err := h.uc.GetCourseByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	books, _ := h.uc.Reserves(id)
+	c.JSON(http.StatusOK, gin.H{"data": course, "reserves": books, "copies": h.uc.ReserveCopies(id)})
+}
+
+// CreateCourse godoc
+// @Summary Create a course
+// @Description Add a course to put books on reserve for. Setting lti links it to a course in a learning management system. Librarians only.
+// @Tags Course Reserves
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course body domain.Course true "Course data"
+// @Success 201 {object} domain.Course
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /courses [post]
+func (h *CourseHandler) CreateCourse(c *gin.Context) {
+	var course domain.Course
+	if err := c.ShouldBindJSON(&course); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := course.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.CreateCourse(course)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateCourse godoc
+// @Summary Update a course
+// @Description Change a course's code, title, dates or learning management system link. Its reserves are kept. Librarians only.
+// @Tags Course Reserves
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param course body domain.Course true "Course data"
+// @Success 200 {object} domain.Course
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /courses/{id} [put]
+func (h *CourseHandler) UpdateCourse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var course domain.Course
+	if err := c.ShouldBindJSON(&course); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := course.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.uc.UpdateCourse(id, course)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// DeleteCourse godoc
+// @Summary Delete a course
+// @Description Delete a course and its reserve list, returning its copies to normal loans. Librarians only.
+// @Tags Course Reserves
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /courses/{id} [delete]
+func (h *CourseHandler) DeleteCourse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.DeleteCourse(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddReserve godoc
+// @Summary Put a book on reserve
+// @Description Put a book on reserve for a course. Librarians only.
+// @Tags Course Reserves
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param bookId path int true "Book ID"
+// @Success 200 {object} domain.Course
+// @Failure 404 {object} map[string]string
+// @Router /courses/{id}/reserves/{bookId} [put]
+func (h *CourseHandler) AddReserve(c *gin.Context) {
+	id, errID := strconv.Atoi(c.Param("id"))
+	bookID, errBook := strconv.Atoi(c.Param("bookId"))
+	if errID != nil || errBook != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	course, err := h.uc.AddReserve(id, bookID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": course})
+}
+
+// RemoveReserve godoc
+// @Summary Take a book off reserve
+// @Description Take a book off reserve for a course. Librarians only.
+// @Tags Course Reserves
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param bookId path int true "Book ID"
+// @Success 200 {object} domain.Course
+// @Failure 404 {object} map[string]string
+// @Router /courses/{id}/reserves/{bookId} [delete]
+func (h *CourseHandler) RemoveReserve(c *gin.Context) {
+	id, errID := strconv.Atoi(c.Param("id"))
+	bookID, errBook := strconv.Atoi(c.Param("bookId"))
+	if errID != nil || errBook != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	course, err := h.uc.RemoveReserve(id, bookID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": course})
+}
+
+// PlaceCopyOnReserve godoc
+// @Summary Put a copy on reserve
+// @Description Set a copy aside for a course on a short-loan rule: two_hour (due two hours after checkout, or at closing time if sooner) or overnight (due when the library next opens). While the course runs, its book is lent on that rule and loans cannot be renewed. The book joins the course's reserve list. Librarians only.
+// @Tags Course Reserves
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param copyId path int true "Copy ID"
+// @Param reserve body ReserveCopyRequest true "Loan rule"
+// @Success 200 {object} domain.Copy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /courses/{id}/copies/{copyId} [put]
+func (h *CourseHandler) PlaceCopyOnReserve(c *gin.Context) {
+	id, errID := strconv.Atoi(c.Param("id"))
+	copyID, errCopy := strconv.Atoi(c.Param("copyId"))
+	if errID != nil || errCopy != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ReserveCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if !domain.ValidReserveLoanRule(req.LoanRule) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "loan_rule must be two_hour or overnight"})
+		return
+	}
+
+	copy, err := h.uc.PlaceOnReserve(id, copyID, req.LoanRule)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
+
+// TakeCopyOffReserve godoc
+// @Summary Take a copy off reserve
+// @Description Return a copy on reserve for a course to normal loans. Its book stays on the course's reserve list. Librarians only.
+// @Tags Course Reserves
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param copyId path int true "Copy ID"
+// @Success 200 {object} domain.Copy
+// @Failure 404 {object} map[string]string
+// @Router /courses/{id}/copies/{copyId} [delete]
+func (h *CourseHandler) TakeCopyOffReserve(c *gin.Context) {
+	id, errID := strconv.Atoi(c.Param("id"))
+	copyID, errCopy := strconv.Atoi(c.Param("copyId"))
+	if errID != nil || errCopy != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	copy, err := h.uc.TakeOffReserve(id, copyID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": copy})
+}
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoroutineGroup counts the goroutines that are in the same function and
+// were started by the same one, with the states they are in, e.g.
+// "chan receive" or "sleep".
+type GoroutineGroup struct {
+	Count     int            `json:"count"`
+	Function  string         `json:"function"`
+	CreatedBy string         `json:"created_by,omitempty"`
+	States    map[string]int `json:"states"`
+}
+
+// HeapSnapshot is a heap profile written to the snapshot directory, with
+// the heap figures at the time.
+type HeapSnapshot struct {
+	File        string    `json:"file"`
+	Size        int64     `json:"size"`
+	TakenAt     time.Time `json:"taken_at"`
+	HeapAlloc   uint64    `json:"heap_alloc"`
+	HeapObjects uint64    `json:"heap_objects"`
+	HeapInuse   uint64    `json:"heap_inuse"`
+	NumGC       uint32    `json:"num_gc"`
+	Goroutines  int       `json:"goroutines"`
+}
+
+// DebugHandler serves runtime diagnostics: the pprof profiles, the expvar
+// variables, a summary of the running goroutines and heap snapshots
+// written to dir.
+type DebugHandler struct {
+	dir string
+}
+
+func NewDebugHandler(dir string) *DebugHandler {
+	if expvar.Get("goroutines") == nil {
+		expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+	}
+	return &DebugHandler{dir: dir}
+}
+
+// Pprof serves the profiles of net/http/pprof under /debug/pprof/, e.g.
+// /debug/pprof/heap or /debug/pprof/profile?seconds=30, and their index.
+func (h *DebugHandler) Pprof(c *gin.Context) {
+	switch strings.TrimPrefix(c.Param("name"), "/") {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Index(c.Writer, c.Request)
+	}
+}
+
+// Vars serves the expvar variables: memstats, cmdline and goroutines.
+func (h *DebugHandler) Vars(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// GetGoroutines godoc
+// @Summary Summarise the running goroutines
+// @Description Count the running goroutines, by state and grouped by the function they are in and the one that started them, largest groups first. Admin only.
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Number of groups to list (default 20)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /debug/goroutines [get]
+func (h *DebugHandler) GetGoroutines(c *gin.Context) {
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = n
+	}
+
+	var buf bytes.Buffer
+	if err := runtimepprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		abort(c, err)
+		return
+	}
+	groups, states, total := summariseGoroutines(buf.Bytes())
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"total":  total,
+		"states": states,
+		"groups": groups[:min(limit, len(groups))],
+	}})
+}
+
+// TakeHeapSnapshot godoc
+// @Summary Take a heap snapshot
+// @Description Run a garbage collection and write a heap profile to the snapshot directory, for go tool pprof. Admin only.
+// @Tags Admin
+// @Produce json
+// @Success 201 {object} HeapSnapshot
+// @Router /debug/heap-snapshots [post]
+func (h *DebugHandler) TakeHeapSnapshot(c *gin.Context) {
+	runtime.GC()
+	now := time.Now().UTC()
+	name := fmt.Sprintf("heap-%s.pb.gz", now.Format("20060102T150405.000Z"))
+	f, err := os.Create(filepath.Join(h.dir, name))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	defer f.Close()
+	if err := runtimepprof.Lookup("heap").WriteTo(f, 0); err != nil {
+		abort(c, err)
+		return
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	c.JSON(http.StatusCreated, gin.H{"data": HeapSnapshot{
+		File:        name,
+		Size:        fi.Size(),
+		TakenAt:     now,
+		HeapAlloc:   m.HeapAlloc,
+		HeapObjects: m.HeapObjects,
+		HeapInuse:   m.HeapInuse,
+		NumGC:       m.NumGC,
+		Goroutines:  runtime.NumGoroutine(),
+	}})
+}
+
+// GetHeapSnapshot godoc
+// @Summary Download a heap snapshot
+// @Description Download a heap profile taken with POST /debug/heap-snapshots. Admin only.
+// @Tags Admin
+// @Produce octet-stream
+// @Param file path string true "Snapshot file"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /debug/heap-snapshots/{file} [get]
+func (h *DebugHandler) GetHeapSnapshot(c *gin.Context) {
+	name := c.Param("file")
+	path := filepath.Join(h.dir, name)
+	if filepath.Base(name) != name || !strings.HasPrefix(name, "heap-") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "heap snapshot not found"})
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "heap snapshot not found"})
+		return
+	}
+	c.FileAttachment(path, name)
+}
+
+// summariseGoroutines groups the goroutines of a goroutine profile in the
+// debug=2 format, the same as a panic prints, largest groups first. It
+// also counts them by state.
+func summariseGoroutines(dump []byte) ([]GoroutineGroup, map[string]int, int) {
+	byKey := map[string]*GoroutineGroup{}
+	states := map[string]int{}
+	total := 0
+
+	var state, function, createdBy string
+	flush := func() {
+		if function == "" {
+			return
+		}
+		key := function + "\x00" + createdBy
+		group, ok := byKey[key]
+		if !ok {
+			group = &GoroutineGroup{Function: function, CreatedBy: createdBy, States: map[string]int{}}
+			byKey[key] = group
+		}
+		group.Count++
+		group.States[state]++
+		states[state]++
+		total++
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(dump))
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			flush()
+			function, createdBy = "", ""
+			// goroutine 7 [chan receive, 3 minutes]:
+			_, rest, _ := strings.Cut(line, "[")
+			state, _, _ = strings.Cut(strings.TrimSuffix(rest, "]:"), ",")
+		case state == "" || line == "" || strings.HasPrefix(line, "\t"):
+		case strings.HasPrefix(line, "created by "):
+			createdBy, _, _ = strings.Cut(strings.TrimPrefix(line, "created by "), " in goroutine")
+		case function == "":
+			// The arguments follow the function name in parentheses.
+			if i := strings.LastIndex(line, "("); i > 0 {
+				line = line[:i]
+			}
+			function = line
+		}
+	}
+	flush()
+
+	groups := make([]GoroutineGroup, 0, len(byKey))
+	for _, group := range byKey {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Function < groups[j].Function
+	})
+	return groups, states, total
+}
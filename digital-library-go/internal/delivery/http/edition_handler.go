@@ -0,0 +1,180 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EditionHandler struct {
+	uc *usecase.EditionUsecase
+}
+
+func NewEditionHandler(uc *usecase.EditionUsecase) *EditionHandler {
+	return &EditionHandler{uc: uc}
+}
+
+// GetEditions godoc
+// @Summary Get all editions
+// @Description Get list of all editions
+// @Tags Editions
+// @Produce json
+// @Success 200 {array} domain.Edition
+// @Router /editions [get]
+func (h *EditionHandler) GetEditions(c *gin.Context) {
+	editions := h.uc.GetEditions()
+	c.JSON(http.StatusOK, gin.H{"data": editions})
+}
+
+// GetEditionByID godoc
+// @Summary Get an edition by ID
+// @Description Get edition details by ID
+// @Tags Editions
+// @Produce json
+// @Param id path int true "Edition ID"
+// @Success 200 {object} domain.Edition
+// @Failure 404 {object} map[string]string
+// @Router /editions/{id} [get]
+func (h *EditionHandler) GetEditionByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	edition, err := h.uc.GetEditionByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": edition})
+}
+
+// GetBookEditions godoc
+// @Summary Get all editions of a title
+// @Description Get every edition of a book, oldest first
+// @Tags Editions
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} domain.Edition
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/editions [get]
+func (h *EditionHandler) GetBookEditions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	editions, err := h.uc.EditionsForBook(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": editions})
+}
+
+// CreateEdition godoc
+// @Summary Create an edition
+// @Description Add an edition of a book from a publisher. Librarians only.
+// @Tags Editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param edition body domain.Edition true "Edition data"
+// @Success 201 {object} domain.Edition
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /editions [post]
+func (h *EditionHandler) CreateEdition(c *gin.Context) {
+	var edition domain.Edition
+
+	if err := c.ShouldBindJSON(&edition); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := edition.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.CreateEdition(edition)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateEdition godoc
+// @Summary Update an edition
+// @Description Update edition details by ID. Librarians only.
+// @Tags Editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Edition ID"
+// @Param edition body domain.Edition true "Updated edition data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /editions/{id} [put]
+func (h *EditionHandler) UpdateEdition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var edition domain.Edition
+	if err := c.ShouldBindJSON(&edition); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := edition.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdateEdition(id, edition)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "edition updated"})
+}
+
+// DeleteEdition godoc
+// @Summary Delete an edition
+// @Description Delete edition by ID. Librarians only.
+// @Tags Editions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Edition ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /editions/{id} [delete]
+func (h *EditionHandler) DeleteEdition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteEdition(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "edition deleted"})
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Errors answers a request whose handler recorded an error with c.Error
+// instead of writing a response, with the status for the error's kind
+// and {"error": message}. Errors of no kind are unexpected: they are
+// logged and answered with 500, without the message, which may give away
+// internals.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status := StatusOf(err)
+		if status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			c.JSON(status, gin.H{"error": "internal server error"})
+			return
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+	}
+}
+
+// StatusOf is the HTTP status an error is answered with, by its kind.
+// An error wrapped as another kind gets the status of the outer one.
+func StatusOf(err error) int {
+	switch domain.KindOf(err) {
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrInvalid:
+		return http.StatusBadRequest
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrGone:
+		return http.StatusGone
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// abort records err for Errors to answer and stops the request. Handlers
+// use it for errors from the usecases, so the same error always gets the
+// same status.
+func abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,282 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/ical"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	uc *usecase.EventUsecase
+}
+
+func NewEventHandler(uc *usecase.EventUsecase) *EventHandler {
+	return &EventHandler{uc: uc}
+}
+
+// GetEvents godoc
+// @Summary Get library events
+// @Description Get upcoming programs such as story time and author talks in start order, with how many places are taken
+// @Tags Events
+// @Produce json
+// @Param past query bool false "Include events that have ended"
+// @Success 200 {array} domain.Event
+// @Router /events [get]
+func (h *EventHandler) GetEvents(c *gin.Context) {
+	events := h.uc.GetEvents(c.Query("past") == "true")
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+// GetEventByID godoc
+// @Summary Get an event by ID
+// @Description Get event details by ID
+// @Tags Events
+// @Produce json
+// @Param id path int true "Event ID"
+// @Success 200 {object} domain.Event
+// @Failure 404 {object} map[string]string
+// @Router /events/{id} [get]
+func (h *EventHandler) GetEventByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	event, err := h.uc.GetEventByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": event})
+}
+
+// GetEventICS godoc
+// @Summary Export an event to a calendar
+// @Description Get an event as an iCalendar file to add to a calendar app
+// @Tags Events
+// @Produce text/calendar
+// @Param id path int true "Event ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /events/{id}/event.ics [get]
+func (h *EventHandler) GetEventICS(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	event, err := h.uc.GetEventByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	cal := ical.Calendar{Events: []ical.Event{calendarEvent(event)}}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, id))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", cal.Bytes())
+}
+
+// CreateEvent godoc
+// @Summary Create an event
+// @Description Add a library program with a capacity. Librarians only.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param event body domain.Event true "Event data"
+// @Success 201 {object} domain.Event
+// @Failure 400 {object} map[string]string
+// @Router /events [post]
+func (h *EventHandler) CreateEvent(c *gin.Context) {
+	var event domain.Event
+
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := event.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreateEvent(event)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateEvent godoc
+// @Summary Update an event
+// @Description Update event details by ID. Raising the capacity registers members from the waitlist. Librarians only.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Param event body domain.Event true "Updated event data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /events/{id} [put]
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var event domain.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := event.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdateEvent(id, event)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "event updated"})
+}
+
+// DeleteEvent godoc
+// @Summary Cancel an event
+// @Description Delete an event by ID. Registered and waitlisted members are notified. Librarians only.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /events/{id} [delete]
+func (h *EventHandler) DeleteEvent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteEvent(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "event deleted"})
+}
+
+// GetEventRegistrations godoc
+// @Summary Get an event's registrations
+// @Description Get who is registered for an event and who is on its waitlist, in the order they signed up. Librarians only.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {array} domain.Registration
+// @Failure 404 {object} map[string]string
+// @Router /events/{id}/registrations [get]
+func (h *EventHandler) GetEventRegistrations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	regs, err := h.uc.RegistrationsForEvent(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": regs})
+}
+
+// Register godoc
+// @Summary Register for an event
+// @Description Register the authenticated member for an event. When it is full, the member joins the waitlist and is notified if a place opens up.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 201 {object} domain.Registration
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /events/{id}/registration [post]
+func (h *EventHandler) Register(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	reg, err := h.uc.Register(id, currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": reg})
+}
+
+// Unregister godoc
+// @Summary Cancel an event registration
+// @Description Withdraw the authenticated member from an event or its waitlist
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /events/{id}/registration [delete]
+func (h *EventHandler) Unregister(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.Unregister(id, currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "registration cancelled"})
+}
+
+// GetMyEvents godoc
+// @Summary Get my event registrations
+// @Description Get the events the authenticated member is registered or waitlisted for
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Registration
+// @Router /me/events [get]
+func (h *EventHandler) GetMyEvents(c *gin.Context) {
+	regs := h.uc.RegistrationsForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": regs})
+}
+
+func calendarEvent(e domain.Event) ical.Event {
+	return ical.Event{
+		UID:         fmt.Sprintf("event-%d@digital-library", e.ID),
+		Summary:     e.Title,
+		Description: e.Description,
+		Location:    e.Location,
+		Start:       e.Start,
+		End:         e.End,
+	}
+}
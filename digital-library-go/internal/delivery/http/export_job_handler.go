@@ -0,0 +1,177 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportJobHandler struct {
+	uc *usecase.ExportUsecase
+}
+
+func NewExportJobHandler(uc *usecase.ExportUsecase) *ExportJobHandler {
+	return &ExportJobHandler{uc: uc}
+}
+
+// GetExportJobs godoc
+// @Summary Get scheduled exports
+// @Description List the nightly catalog exports with their last run. Passwords, private keys and secret access keys are never returned. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.ExportJob
+// @Router /admin/exports [get]
+func (h *ExportJobHandler) GetExportJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetJobs()})
+}
+
+// GetExportJob godoc
+// @Summary Get a scheduled export
+// @Description Get a nightly catalog export by ID. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export job ID"
+// @Success 200 {object} domain.ExportJob
+// @Failure 404 {object} map[string]string
+// @Router /admin/exports/{id} [get]
+func (h *ExportJobHandler) GetExportJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	job, err := h.uc.GetJobByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// CreateExportJob godoc
+// @Summary Schedule an export
+// @Description Push the published catalog as csv, json or marc to an sftp server or s3 bucket every night at hour UTC. SFTP destinations must give the server's host_key. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param job body domain.ExportJob true "Export job"
+// @Success 201 {object} domain.ExportJob
+// @Failure 400 {object} map[string]string
+// @Router /admin/exports [post]
+func (h *ExportJobHandler) CreateExportJob(c *gin.Context) {
+	var job domain.ExportJob
+	if err := c.ShouldBindJSON(&job); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	created, err := h.uc.CreateJob(job, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateExportJob godoc
+// @Summary Update a scheduled export
+// @Description Replace an export's settings. Secrets left empty keep their old values unless the destination type changes. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export job ID"
+// @Param job body domain.ExportJob true "Export job"
+// @Success 200 {object} domain.ExportJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/exports/{id} [put]
+func (h *ExportJobHandler) UpdateExportJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var job domain.ExportJob
+	if err := c.ShouldBindJSON(&job); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	updated, err := h.uc.UpdateJob(id, job, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// DeleteExportJob godoc
+// @Summary Delete a scheduled export
+// @Description Stop and remove a nightly export. Files already uploaded are kept. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export job ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/exports/{id} [delete]
+func (h *ExportJobHandler) DeleteExportJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.DeleteJob(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "export job deleted"})
+}
+
+// RunExportJob godoc
+// @Summary Run a scheduled export now
+// @Description Export and upload the catalog immediately, e.g. to test a destination. Disabled jobs can be run too; the nightly schedule is unchanged. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export job ID"
+// @Success 200 {object} domain.ExportRun
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /admin/exports/{id}/run [post]
+func (h *ExportJobHandler) RunExportJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	run, err := h.uc.Run(id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if run.Error != "" {
+		c.JSON(http.StatusBadGateway, gin.H{"error": run.Error, "data": run})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
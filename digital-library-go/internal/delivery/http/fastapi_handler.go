@@ -0,0 +1,380 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FastAPIBook is a book as the FastAPI service had it.
+type FastAPIBook struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Year   int    `json:"year"`
+	ISBN   string `json:"isbn"`
+}
+
+// FastAPIValidationError is one entry in the detail of a 422, as FastAPI
+// reports a request that fails validation. Loc is where the error is,
+// e.g. ["body", "year"], and Input the value found there.
+type FastAPIValidationError struct {
+	Type  string `json:"type"`
+	Loc   []any  `json:"loc"`
+	Msg   string `json:"msg"`
+	Input any    `json:"input"`
+	Ctx   any    `json:"ctx,omitempty"`
+}
+
+// FastAPIHandler answers the routes of the FastAPI service this server
+// replaces exactly as it did, for clients written against it: bare books
+// instead of {"data": ...}, {"detail": ...} errors, 422 with FastAPI's
+// validation errors and 200 for created books. Its routes are left out
+// of the Swagger docs, which describe the native API.
+type FastAPIHandler struct {
+	uc      *usecase.BookUsecase
+	authors *usecase.AuthorUsecase
+	policy  *usecase.ContentPolicyUsecase
+}
+
+func NewFastAPIHandler(uc *usecase.BookUsecase, authors *usecase.AuthorUsecase, policy *usecase.ContentPolicyUsecase) *FastAPIHandler {
+	return &FastAPIHandler{uc: uc, authors: authors, policy: policy}
+}
+
+// Root answers GET /.
+func (h *FastAPIHandler) Root(c *gin.Context) {
+	fastapiJSON(c, http.StatusOK, gin.H{"message": "Digital Library API is running"})
+}
+
+// GetBooks lists every book the audience may see.
+func (h *FastAPIHandler) GetBooks(c *gin.Context) {
+	books := []FastAPIBook{}
+	for _, b := range h.policy.Filter(h.uc.GetBooks(), audienceOf(c)) {
+		books = append(books, fastapiBookOf(b))
+	}
+	fastapiJSON(c, http.StatusOK, books)
+}
+
+func (h *FastAPIHandler) GetBook(c *gin.Context) {
+	id, errs := fastapiBookID(c)
+	if len(errs) > 0 {
+		fastapiInvalid(c, errs)
+		return
+	}
+	book, err := h.uc.GetBookByID(id)
+	if err != nil || !h.policy.Allows(book, audienceOf(c)) {
+		fastapiDetail(c, http.StatusNotFound, "Resource Not Found: Book with this ID does not exist")
+		return
+	}
+	fastapiJSON(c, http.StatusOK, fastapiBookOf(book))
+}
+
+// CreateBook adds a book and answers it as it was sent.
+func (h *FastAPIHandler) CreateBook(c *gin.Context) {
+	req, errs := fastapiBookBody(c)
+	if len(errs) > 0 {
+		fastapiInvalid(c, errs)
+		return
+	}
+
+	book := domain.Book{ID: req.ID, Title: req.Title, Author: req.Author, Year: req.Year, ISBN: req.ISBN}
+	if err := h.save(book, func(b domain.Book) error { return h.uc.CreateBook(b) }); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			fastapiDetail(c, http.StatusBadRequest, "Duplicate ID Error: Book with this ID already exists")
+			return
+		}
+		fastapiError(c, err)
+		return
+	}
+	fastapiJSON(c, http.StatusOK, req)
+}
+
+// UpdateBook replaces the fields a FastAPI book has, keeping the others,
+// and answers the book as it was sent. The book keeps the ID in the path
+// even if the body gives another.
+func (h *FastAPIHandler) UpdateBook(c *gin.Context) {
+	id, errs := fastapiBookID(c)
+	req, bodyErrs := fastapiBookBody(c)
+	if errs = append(errs, bodyErrs...); len(errs) > 0 {
+		fastapiInvalid(c, errs)
+		return
+	}
+
+	book, err := h.uc.GetBookByID(id)
+	if err == nil {
+		book.Title, book.Author, book.AuthorIDs, book.Year, book.ISBN = req.Title, req.Author, nil, req.Year, req.ISBN
+		err = h.save(book, func(b domain.Book) error { return h.uc.UpdateBook(id, b) })
+	}
+	if errors.Is(err, usecase.ErrBookNotFound) {
+		fastapiDetail(c, http.StatusNotFound, "Resource Not Found: Cannot update non-existent book")
+		return
+	}
+	if err != nil {
+		fastapiError(c, err)
+		return
+	}
+	fastapiJSON(c, http.StatusOK, req)
+}
+
+func (h *FastAPIHandler) DeleteBook(c *gin.Context) {
+	id, errs := fastapiBookID(c)
+	if len(errs) > 0 {
+		fastapiInvalid(c, errs)
+		return
+	}
+	if err := h.uc.DeleteBook(id); err != nil {
+		fastapiDetail(c, http.StatusNotFound, "Resource Not Found: Cannot delete non-existent book")
+		return
+	}
+	fastapiJSON(c, http.StatusOK, gin.H{"message": "Book removed successfully"})
+}
+
+// NotFound answers a path no route has.
+func (h *FastAPIHandler) NotFound(c *gin.Context) {
+	fastapiDetail(c, http.StatusNotFound, "Not Found")
+}
+
+// MethodNotAllowed answers a method the route of a path does not have.
+func (h *FastAPIHandler) MethodNotAllowed(c *gin.Context) {
+	fastapiDetail(c, http.StatusMethodNotAllowed, "Method Not Allowed")
+}
+
+// save checks a book the way the native handlers do before store saves
+// it.
+func (h *FastAPIHandler) save(book domain.Book, store func(domain.Book) error) error {
+	if err := book.Validate(); err != nil {
+		return domain.Wrap(domain.ErrInvalid, err)
+	}
+	return h.authors.SaveBook(&book, store)
+}
+
+func fastapiBookOf(b domain.Book) FastAPIBook {
+	return FastAPIBook{ID: b.ID, Title: b.Title, Author: b.Author, Year: b.Year, ISBN: b.ISBN}
+}
+
+// fastapiJSON writes v as FastAPI did: unlike c.JSON, without a trailing
+// newline and with <, > and & left unescaped, as Python's json module
+// does.
+func fastapiJSON(c *gin.Context, status int, v any) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		abort(c, err)
+		return
+	}
+	c.Data(status, "application/json", bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// fastapiDetail answers as FastAPI answered an HTTPException.
+func fastapiDetail(c *gin.Context, status int, detail string) {
+	fastapiJSON(c, status, gin.H{"detail": detail})
+}
+
+// fastapiError answers an error of the usecases with its status.
+// Unexpected errors are left to Errors, like those of other routes.
+func fastapiError(c *gin.Context, err error) {
+	status := StatusOf(err)
+	if status == http.StatusInternalServerError {
+		abort(c, err)
+		return
+	}
+	fastapiDetail(c, status, err.Error())
+}
+
+func fastapiInvalid(c *gin.Context, errs []FastAPIValidationError) {
+	fastapiJSON(c, http.StatusUnprocessableEntity, gin.H{"detail": errs})
+}
+
+// fastapiBookID parses the book_id path parameter.
+func fastapiBookID(c *gin.Context) (int, []FastAPIValidationError) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return 0, []FastAPIValidationError{{
+			Type:  "int_parsing",
+			Loc:   []any{"path", "book_id"},
+			Msg:   "Input should be a valid integer, unable to parse string as an integer",
+			Input: c.Param("id"),
+		}}
+	}
+	return id, nil
+}
+
+// fastapiBookBody reads a book from the body the way FastAPI validated
+// its Book model, returning every error found, field by field.
+func fastapiBookBody(c *gin.Context) (FastAPIBook, []FastAPIValidationError) {
+	var book FastAPIBook
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(body) == 0 {
+		return book, []FastAPIValidationError{{Type: "missing", Loc: []any{"body"}, Msg: "Field required"}}
+	}
+
+	// FastAPI parsed the body as JSON only when the request said it was,
+	// or said nothing.
+	if ct := c.GetHeader("Content-Type"); ct != "" {
+		if media, _, err := mime.ParseMediaType(ct); err != nil || (media != "application/json" && !strings.HasSuffix(media, "+json")) {
+			return book, []FastAPIValidationError{fastapiNotObject(string(body))}
+		}
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return book, []FastAPIValidationError{fastapiJSONError(body, err)}
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return book, []FastAPIValidationError{fastapiNotObject(json.RawMessage(body))}
+	}
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(body, &fields)
+
+	var errs []FastAPIValidationError
+	field := func(name string, parse func(json.RawMessage) (string, string)) {
+		raw, ok := fields[name]
+		if !ok {
+			errs = append(errs, FastAPIValidationError{Type: "missing", Loc: []any{"body", name}, Msg: "Field required", Input: json.RawMessage(body)})
+			return
+		}
+		if typ, msg := parse(raw); typ != "" {
+			e := FastAPIValidationError{Type: typ, Loc: []any{"body", name}, Msg: msg, Input: raw}
+			switch typ {
+			case "string_too_short":
+				e.Ctx = gin.H{"min_length": 1}
+			case "value_error":
+				e.Ctx = gin.H{"error": gin.H{}}
+			}
+			errs = append(errs, e)
+		}
+	}
+
+	field("id", func(raw json.RawMessage) (string, string) { return fastapiInt(raw, &book.ID) })
+	field("title", func(raw json.RawMessage) (string, string) {
+		if typ, msg := fastapiString(raw, &book.Title); typ != "" {
+			return typ, msg
+		}
+		if book.Title == "" {
+			return "string_too_short", "String should have at least 1 character"
+		}
+		return "", ""
+	})
+	field("author", func(raw json.RawMessage) (string, string) { return fastapiString(raw, &book.Author) })
+	field("year", func(raw json.RawMessage) (string, string) {
+		if typ, msg := fastapiInt(raw, &book.Year); typ != "" {
+			return typ, msg
+		}
+		if book.Year < 1000 || book.Year > 2026 {
+			return "value_error", "Value error, Year must be between 1000 and 2026"
+		}
+		return "", ""
+	})
+	field("isbn", func(raw json.RawMessage) (string, string) {
+		if typ, msg := fastapiString(raw, &book.ISBN); typ != "" {
+			return typ, msg
+		}
+		if n := utf8.RuneCountInString(book.ISBN); n != 10 && n != 13 {
+			return "value_error", "Value error, ISBN must be 10 or 13 characters long"
+		}
+		return "", ""
+	})
+	return book, errs
+}
+
+func fastapiNotObject(input any) FastAPIValidationError {
+	return FastAPIValidationError{
+		Type:  "model_attributes_type",
+		Loc:   []any{"body"},
+		Msg:   "Input should be a valid dictionary or object to extract fields from",
+		Input: input,
+	}
+}
+
+// fastapiInt reads an integer as pydantic did: from a number without a
+// fractional part, a string of digits or a boolean. It returns the error
+// type and message if raw is none of those.
+func fastapiInt(raw json.RawMessage, n *int) (string, string) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	_ = dec.Decode(&v)
+
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			*n = int(i)
+			return "", ""
+		}
+		f, err := v.Float64()
+		if err != nil || f != math.Trunc(f) {
+			return "int_from_float", "Input should be a valid integer, got a number with a fractional part"
+		}
+		*n = int(f)
+		return "", ""
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return "int_parsing", "Input should be a valid integer, unable to parse string as an integer"
+		}
+		*n = i
+		return "", ""
+	case bool:
+		if v {
+			*n = 1
+		}
+		return "", ""
+	}
+	return "int_type", "Input should be a valid integer"
+}
+
+// fastapiString reads a string; pydantic turned nothing else into one.
+func fastapiString(raw json.RawMessage, s *string) (string, string) {
+	if err := json.Unmarshal(raw, s); err != nil || bytes.Equal(raw, []byte("null")) {
+		return "string_type", "Input should be a valid string"
+	}
+	return "", ""
+}
+
+// fastapiJSONMessages translate the errors of encoding/json to those of
+// Python's json module, which FastAPI passed on.
+var fastapiJSONMessages = []struct{ goMsg, pyMsg string }{
+	{"after object key:value pair", "Expecting ',' delimiter"},
+	{"after array element", "Expecting ',' delimiter"},
+	{"after object key", "Expecting ':' delimiter"},
+	{"looking for beginning of object key string", "Expecting property name enclosed in double quotes"},
+	{"after top-level value", "Extra data"},
+	{"in string literal", "Invalid control character at"},
+	{"in string escape code", "Invalid \\escape"},
+}
+
+// fastapiJSONError describes a body that is not JSON, located by the
+// character where parsing failed.
+func fastapiJSONError(body []byte, err error) FastAPIValidationError {
+	pos, msg := len(body), "Expecting value"
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) && int(syntax.Offset) <= len(body) && !strings.Contains(syntax.Error(), "unexpected end") {
+		pos = int(syntax.Offset) - 1
+		for _, m := range fastapiJSONMessages {
+			if strings.Contains(syntax.Error(), m.goMsg) {
+				msg = m.pyMsg
+				break
+			}
+		}
+	}
+	return FastAPIValidationError{
+		Type:  "json_invalid",
+		Loc:   []any{"body", utf8.RuneCount(body[:max(pos, 0)])},
+		Msg:   "JSON decode error",
+		Input: gin.H{},
+		Ctx:   gin.H{"error": msg},
+	}
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureFlagRequest turns a flag on or off.
+type FeatureFlagRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type FeatureFlagHandler struct {
+	flags *featureflag.Store
+}
+
+func NewFeatureFlagHandler(flags *featureflag.Store) *FeatureFlagHandler {
+	return &FeatureFlagHandler{flags: flags}
+}
+
+// Require answers 404 for routes of a feature that is off for the
+// request's tenant, as if they did not exist.
+func (h *FeatureFlagHandler) Require(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.flags.Enabled(name, c.Request.Host) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": name + " are not available"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetFeatures godoc
+// @Summary Get the features offered
+// @Description Report which optional features are on for the host the request was sent to, so clients can hide the others
+// @Tags Features
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Router /features [get]
+func (h *FeatureFlagHandler) GetFeatures(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.flags.For(c.Request.Host)})
+}
+
+// GetFlags godoc
+// @Summary Get the feature flags
+// @Description List every feature flag with its default and per-tenant overrides. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} featureflag.Flag
+// @Router /admin/flags [get]
+func (h *FeatureFlagHandler) GetFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.flags.Flags()})
+}
+
+// SetFlag godoc
+// @Summary Set a feature flag
+// @Description Turn a feature on or off for every tenant without an override. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Flag name"
+// @Param flag body FeatureFlagRequest true "New state"
+// @Success 200 {array} featureflag.Flag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/flags/{name} [put]
+func (h *FeatureFlagHandler) SetFlag(c *gin.Context) {
+	enabled, ok := bindFlag(c)
+	if !ok {
+		return
+	}
+	h.respond(c, h.flags.Set(c.Param("name"), enabled))
+}
+
+// SetTenantFlag godoc
+// @Summary Set a feature flag for a tenant
+// @Description Turn a feature on or off for one tenant, the host name a site is served on. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Flag name"
+// @Param tenant path string true "Tenant host name"
+// @Param flag body FeatureFlagRequest true "New state"
+// @Success 200 {array} featureflag.Flag
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/flags/{name}/tenants/{tenant} [put]
+func (h *FeatureFlagHandler) SetTenantFlag(c *gin.Context) {
+	enabled, ok := bindFlag(c)
+	if !ok {
+		return
+	}
+	h.respond(c, h.flags.SetTenant(c.Param("name"), c.Param("tenant"), enabled))
+}
+
+// ClearTenantFlag godoc
+// @Summary Remove a tenant's feature flag override
+// @Description Let a tenant follow the flag's default again. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Flag name"
+// @Param tenant path string true "Tenant host name"
+// @Success 200 {array} featureflag.Flag
+// @Failure 404 {object} map[string]string
+// @Router /admin/flags/{name}/tenants/{tenant} [delete]
+func (h *FeatureFlagHandler) ClearTenantFlag(c *gin.Context) {
+	h.respond(c, h.flags.ClearTenant(c.Param("name"), c.Param("tenant")))
+}
+
+func bindFlag(c *gin.Context) (bool, bool) {
+	var req FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return false, false
+	}
+	if req.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enabled is required"})
+		return false, false
+	}
+	return *req.Enabled, true
+}
+
+// respond reports the outcome of a change with the flags as they now
+// stand.
+func (h *FeatureFlagHandler) respond(c *gin.Context, err error) {
+	if errors.Is(err, featureflag.ErrUnknownFlag) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.flags.Flags()})
+}
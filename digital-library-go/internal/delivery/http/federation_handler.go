@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ILLRequestBody names a partner's book to request, as listed under
+// remote in search results.
+type ILLRequestBody struct {
+	Partner string `json:"partner"`
+	BookID  int    `json:"book_id"`
+}
+
+type FederationHandler struct {
+	uc *usecase.FederationUsecase
+}
+
+func NewFederationHandler(uc *usecase.FederationUsecase) *FederationHandler {
+	return &FederationHandler{uc: uc}
+}
+
+// RequestFromPartner godoc
+// @Summary Request a book from a partner
+// @Description Ask a consortium partner to lend the authenticated member one of its books, as found under remote in search results. The book is requested over NCIP under the library's account at the partner. Titles the library has are refused.
+// @Tags Circulation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ILLRequestBody true "Partner and its book ID"
+// @Success 201 {object} domain.ILLRequest
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /me/ill-requests [post]
+func (h *FederationHandler) RequestFromPartner(c *gin.Context) {
+	var req ILLRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if req.Partner == "" || req.BookID < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "partner and book_id are required"})
+		return
+	}
+
+	request, err := h.uc.Request(c.Request.Context(), currentMemberID(c), req.Partner, req.BookID, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": request})
+}
+
+// GetMyILLRequests godoc
+// @Summary Get my interlibrary loan requests
+// @Description Get the books the authenticated member requested from partners
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.ILLRequest
+// @Router /me/ill-requests [get]
+func (h *FederationHandler) GetMyILLRequests(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetRequests(currentMemberID(c))})
+}
+
+// GetILLRequests godoc
+// @Summary Get all interlibrary loan requests
+// @Description Get the books members requested from partners, to collect and hand out. Librarians only.
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.ILLRequest
+// @Router /ill-requests [get]
+func (h *FederationHandler) GetILLRequests(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetRequests(0)})
+}
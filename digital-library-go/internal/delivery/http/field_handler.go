@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FieldHandler struct {
+	uc *usecase.FieldUsecase
+}
+
+func NewFieldHandler(uc *usecase.FieldUsecase) *FieldHandler {
+	return &FieldHandler{uc: uc}
+}
+
+// GetFields godoc
+// @Summary Get all custom fields
+// @Description Get the custom metadata fields books may carry in their attributes, for every tenant
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} domain.FieldDefinition
+// @Router /admin/fields [get]
+func (h *FieldHandler) GetFields(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetFields()})
+}
+
+// GetField godoc
+// @Summary Get a custom field
+// @Description Get a custom field definition by name
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Field name"
+// @Param tenant query string false "Tenant host name; fields every tenant has when empty"
+// @Success 200 {object} domain.FieldDefinition
+// @Failure 404 {object} map[string]string
+// @Router /admin/fields/{name} [get]
+func (h *FieldHandler) GetField(c *gin.Context) {
+	field, err := h.uc.GetField(c.Query("tenant"), c.Param("name"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": field})
+}
+
+// CreateField godoc
+// @Summary Define a custom field
+// @Description Add a text, number or enum field that books may carry in their attributes, for one tenant or for every tenant. Names are unique across tenants, since the catalog is shared.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param tenant query string false "Tenant host name; a field every tenant has when empty"
+// @Param field body domain.FieldDefinition true "Field definition"
+// @Success 201 {object} domain.FieldDefinition
+// @Failure 400 {object} map[string]string
+// @Router /admin/fields [post]
+func (h *FieldHandler) CreateField(c *gin.Context) {
+	var field domain.FieldDefinition
+
+	if err := c.ShouldBindJSON(&field); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	field.Tenant = featureflag.Tenant(c.Query("tenant"))
+	if err := field.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.uc.CreateField(field); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": field})
+}
+
+// UpdateField godoc
+// @Summary Update a custom field
+// @Description Replace a custom field definition. Books are checked against it the next time they are saved.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "Field name"
+// @Param tenant query string false "Tenant host name; fields every tenant has when empty"
+// @Param field body domain.FieldDefinition true "Field definition"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/fields/{name} [put]
+func (h *FieldHandler) UpdateField(c *gin.Context) {
+	var field domain.FieldDefinition
+
+	if err := c.ShouldBindJSON(&field); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	field.Name, field.Tenant = c.Param("name"), c.Query("tenant")
+	if err := field.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.uc.UpdateField(field.Name, field); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "field updated"})
+}
+
+// DeleteField godoc
+// @Summary Delete a custom field
+// @Description Delete a custom field definition and its values on all books
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Field name"
+// @Param tenant query string false "Tenant host name; fields every tenant has when empty"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/fields/{name} [delete]
+func (h *FieldHandler) DeleteField(c *gin.Context) {
+	if err := h.uc.DeleteField(c.Query("tenant"), c.Param("name")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "field deleted"})
+}
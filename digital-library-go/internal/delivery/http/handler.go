@@ -1,43 +1,183 @@
 package http
 
 import (
-	"log"
+	"encoding/json"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/markdown"
 	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
+// LanguageMigrationRequest names the language to give books that have
+// none, if any.
+type LanguageMigrationRequest struct {
+	Default string `json:"default"`
+}
+
 type BookHandler struct {
-	uc *usecase.BookUsecase
+	uc       *usecase.BookUsecase
+	cache    *usecase.BookCache
+	authors  *usecase.AuthorUsecase
+	fields   *usecase.FieldUsecase
+	policy   *usecase.ContentPolicyUsecase
+	notifier *usecase.BookNotifier
+	listing  listingCache
 }
 
-func NewBookHandler(uc *usecase.BookUsecase) *BookHandler {
-	return &BookHandler{uc: uc}
+func NewBookHandler(uc *usecase.BookUsecase, cache *usecase.BookCache, authors *usecase.AuthorUsecase, fields *usecase.FieldUsecase, policy *usecase.ContentPolicyUsecase, notifier *usecase.BookNotifier) *BookHandler {
+	return &BookHandler{uc: uc, cache: cache, authors: authors, fields: fields, policy: policy, notifier: notifier}
 }
 
 // GetBooks godoc
 // @Summary Get all books
-// @Description Get list of all books
+// @Description Get list of all books. Filter by language with an ISO 639 code, e.g. ?language=es, and on custom fields with attr.<name>=<value>, e.g. ?attr.genre=fantasy. Child accounts and ?audience=children only see books suitable for children. With ?render=html descriptions and tables of contents are sanitized HTML.
 // @Tags Library
 // @Produce json
+// @Param language query string false "ISO 639-1 or 639-2 language code"
+// @Param audience query string false "all or children"
+// @Param render query string false "markdown (default) or html"
 // @Success 200 {array} domain.Book
+// @Failure 400 {object} map[string]string
 // @Router /books [get]
 func (h *BookHandler) GetBooks(c *gin.Context) {
-	books := h.uc.GetBooks()
+	asHTML, ok := wantHTML(c)
+	if !ok {
+		return
+	}
+
+	filters := map[string]string{}
+	for key, values := range c.Request.URL.Query() {
+		if name, ok := strings.CutPrefix(key, "attr."); ok {
+			filters[name] = values[0]
+		}
+	}
+	if c.Query("language") == "" && len(filters) == 0 {
+		body, err := h.listingBody(audienceOf(c), asHTML)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+		return
+	}
+
+	books := h.policy.Filter(h.uc.GetBooks(), audienceOf(c))
+	if lang := c.Query("language"); lang != "" {
+		if !domain.ValidLanguage(lang) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidLanguage.Error()})
+			return
+		}
+		lang = domain.NormalizeLanguage(lang)
+		books = slices.DeleteFunc(books, func(b domain.Book) bool { return b.Language != lang })
+	}
+
+	if len(filters) > 0 {
+		var err error
+		books, err = h.fields.Filter(c.Request.Host, books, filters)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if asHTML {
+		for i := range books {
+			renderHTML(&books[i])
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{"data": books})
 }
 
+// streamFlushEvery is how many books StreamBooks writes between flushes.
+const streamFlushEvery = 100
+
+// StreamBooks godoc
+// @Summary Stream the catalog
+// @Description Stream every book the audience may see as newline-delimited JSON, one book per line, for bulk consumers. Books are written as fast as the client reads them, and the stream stops if the client goes away. The catalog is read once when the request starts.
+// @Tags Library
+// @Produce application/x-ndjson
+// @Param audience query string false "all or children"
+// @Param render query string false "markdown (default) or html"
+// @Success 200 {object} domain.Book
+// @Failure 400 {object} map[string]string
+// @Router /books/stream [get]
+func (h *BookHandler) StreamBooks(c *gin.Context) {
+	asHTML, ok := wantHTML(c)
+	if !ok {
+		return
+	}
+	books := h.policy.Filter(h.uc.GetBooks(), audienceOf(c))
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	// Each write blocks while the client is behind, so a slow reader
+	// slows the stream down instead of it piling up in memory.
+	enc := json.NewEncoder(c.Writer)
+	ctx := c.Request.Context()
+	for i, b := range books {
+		if ctx.Err() != nil {
+			return
+		}
+		if asHTML {
+			renderHTML(&b)
+		}
+		if err := enc.Encode(b); err != nil {
+			return
+		}
+		if (i+1)%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+	c.Writer.Flush()
+}
+
+// GetBookChanges godoc
+// @Summary Get catalog changes since a cursor
+// @Description Get the books created, updated and deleted since a sync cursor, for clients that keep an offline copy of the catalog. Without a cursor the whole catalog is returned as created. Pass the returned cursor next time. Books the audience may no longer see, e.g. withdrawn ones, are reported as deleted. A cursor that is too old, or from before a server restart, is answered with 410, after which the client fetches the whole catalog again.
+// @Tags Library
+// @Produce json
+// @Param since query string false "Cursor returned by the previous call"
+// @Param audience query string false "all or children"
+// @Success 200 {object} domain.BookChanges
+// @Failure 400 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /books/changes [get]
+func (h *BookHandler) GetBookChanges(c *gin.Context) {
+	changes, err := h.uc.Changes(c.Query("since"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	audience := audienceOf(c)
+	changes.Created = h.policy.Filter(changes.Created, audience)
+	updated := []domain.Book{}
+	for _, b := range changes.Updated {
+		if h.policy.Allows(b, audience) {
+			updated = append(updated, b)
+		} else {
+			changes.Deleted = append(changes.Deleted, b.ID)
+		}
+	}
+	changes.Updated = updated
+	c.JSON(http.StatusOK, gin.H{"data": changes})
+}
+
 // GetBookByID godoc
 // @Summary Get a book by ID
-// @Description Get book details by ID
+// @Description Get book details by ID. With ?render=html the description and table of contents are returned as sanitized HTML instead of Markdown.
 // @Tags Library
 // @Produce json
 // @Param id path int true "Book ID"
+// @Param render query string false "markdown (default) or html"
 // @Success 200 {object} domain.Book
 // @Failure 404 {object} map[string]string
 // @Router /books/{id} [get]
@@ -48,12 +188,21 @@ func (h *BookHandler) GetBookByID(c *gin.Context) {
 		return
 	}
 
-	book, err := h.uc.GetBookByID(id)
-	if err != nil {
+	asHTML, ok := wantHTML(c)
+	if !ok {
+		return
+	}
+
+	book, err := h.cache.GetBookByID(id)
+	if err != nil || !h.policy.Allows(book, audienceOf(c)) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
 		return
 	}
 
+	if asHTML {
+		renderHTML(&book)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": book})
 }
 
@@ -66,6 +215,7 @@ func (h *BookHandler) GetBookByID(c *gin.Context) {
 // @Param book body domain.Book true "Book data"
 // @Success 201 {object} domain.Book
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /books [post]
 func (h *BookHandler) CreateBook(c *gin.Context) {
 	var book domain.Book
@@ -80,29 +230,33 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		return
 	}
 
-	err := h.uc.CreateBook(book)
+	attrs, err := h.fields.ValidateAttributes(c.Request.Host, book.Attributes, nil)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, err)
+		return
+	}
+	book.Attributes = attrs
+
+	if err := h.authors.SaveBook(&book, h.uc.CreateBook); err != nil {
+		abort(c, err)
 		return
 	}
 
-	go func(b domain.Book) {
-		time.Sleep(2 * time.Second)
-		log.Println("Notification sent for new book:", b.Title)
-	}(book)
+	h.notifier.Notify(c.Request.Context(), book)
 
 	c.JSON(http.StatusCreated, gin.H{"message": "book created"})
 }
 
 // UpdateBook godoc
 // @Summary Update a book
-// @Description Update book details by ID
+// @Description Update book details by ID. The status is kept; publish or withdraw the book to change it.
 // @Tags Library
 // @Accept json
 // @Produce json
 // @Param id path int true "Book ID"
 // @Param book body domain.Book true "Updated book data"
 // @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /books/{id} [put]
 func (h *BookHandler) UpdateBook(c *gin.Context) {
@@ -118,14 +272,26 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
+	// The book keeps its status, which it is validated against, so
+	// drafts can be edited while they are still incomplete.
+	current, _ := h.uc.GetBookByID(id)
+	book.Status = current.Status
+
 	if err := book.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	err = h.uc.UpdateBook(id, book)
+	attrs, err := h.fields.ValidateAttributes(c.Request.Host, book.Attributes, current.Attributes)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+		abort(c, err)
+		return
+	}
+	book.Attributes = attrs
+
+	err = h.authors.SaveBook(&book, func(b domain.Book) error { return h.uc.UpdateBook(id, b) })
+	if err != nil {
+		abort(c, err)
 		return
 	}
 
@@ -148,11 +314,256 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 		return
 	}
 
-	err = h.uc.DeleteBook(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+	if err := h.uc.DeleteBook(id); err != nil {
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "book deleted"})
 }
+
+// GetNewArrivals godoc
+// @Summary Get the new-arrival shelf
+// @Description Get featured books, then books added in the last days days, each newest first
+// @Tags Library
+// @Produce json
+// @Param days query int false "Window in days (default 30, max 365)"
+// @Success 200 {array} domain.Book
+// @Failure 400 {object} map[string]string
+// @Router /books/new [get]
+func (h *BookHandler) GetNewArrivals(c *gin.Context) {
+	days := 30
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+		days = n
+	}
+
+	books := h.policy.Filter(h.uc.NewArrivals(time.Now().AddDate(0, 0, -days)), audienceOf(c))
+	c.JSON(http.StatusOK, gin.H{"data": books})
+}
+
+// FeatureBook godoc
+// @Summary Feature a book
+// @Description Pin a book to the top of the new-arrival shelf. Librarians only.
+// @Tags Library
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/feature [post]
+func (h *BookHandler) FeatureBook(c *gin.Context) {
+	h.setFeatured(c, true)
+}
+
+// UnfeatureBook godoc
+// @Summary Stop featuring a book
+// @Description Unpin a book from the new-arrival shelf. Librarians only.
+// @Tags Library
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/feature [delete]
+func (h *BookHandler) UnfeatureBook(c *gin.Context) {
+	h.setFeatured(c, false)
+}
+
+func (h *BookHandler) setFeatured(c *gin.Context, featured bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.SetFeatured(id, featured); err != nil {
+		abort(c, err)
+		return
+	}
+
+	if featured {
+		c.JSON(http.StatusOK, gin.H{"message": "book featured"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "book no longer featured"})
+}
+
+// MigrateLanguage godoc
+// @Summary Fill in book languages
+// @Description Set the language of books that have none from their old "language" custom field, or else to the given default. Books that get neither are listed.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body LanguageMigrationRequest false "Default language"
+// @Success 200 {object} domain.LanguageMigration
+// @Failure 400 {object} map[string]string
+// @Router /admin/books/migrate-language [post]
+func (h *BookHandler) MigrateLanguage(c *gin.Context) {
+	var req LanguageMigrationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+			return
+		}
+	}
+	if req.Default != "" && !domain.ValidLanguage(req.Default) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidLanguage.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.MigrateLanguage(req.Default)})
+}
+
+// listingCache keeps the encoded body of the unfiltered GET /books
+// response, which is most of the traffic, for each audience, render mode
+// and content policy. Bodies are built at most once per catalog version
+// and written out as they are.
+type listingCache struct {
+	mu      sync.Mutex
+	version uint64
+	bodies  map[listingKey][]byte
+}
+
+type listingKey struct {
+	audience string
+	html     bool
+	policy   domain.ContentPolicy
+}
+
+// listingBody returns the same bytes c.JSON would write for the whole
+// catalog as seen by the audience.
+func (h *BookHandler) listingBody(audience string, asHTML bool) ([]byte, error) {
+	version := h.uc.Version()
+	policy := h.policy.GetPolicy()
+	key := listingKey{audience: audience, html: asHTML, policy: policy}
+
+	h.listing.mu.Lock()
+	body, ok := h.listing.bodies[key]
+	fresh := ok && h.listing.version == version
+	h.listing.mu.Unlock()
+	if fresh {
+		return body, nil
+	}
+
+	books := []domain.Book{}
+	for _, b := range h.uc.GetBooks() {
+		if policy.Allows(b, audience) {
+			if asHTML {
+				renderHTML(&b)
+			}
+			books = append(books, b)
+		}
+	}
+	body, err := json.Marshal(gin.H{"data": books})
+	if err != nil {
+		return nil, err
+	}
+
+	// The books may be newer than version but never older, so a body
+	// is only kept while no later write has been seen.
+	h.listing.mu.Lock()
+	defer h.listing.mu.Unlock()
+	if version > h.listing.version || h.listing.bodies == nil {
+		h.listing.bodies = map[listingKey][]byte{}
+		h.listing.version = version
+	}
+	if version == h.listing.version {
+		h.listing.bodies[key] = body
+	}
+	return body, nil
+}
+
+// wantHTML reports whether the client asked for the Markdown fields of
+// books as HTML with ?render=html. It answers 400 itself for an unknown
+// render value, in which case ok is false.
+func wantHTML(c *gin.Context) (asHTML, ok bool) {
+	switch c.Query("render") {
+	case "", "markdown":
+		return false, true
+	case "html":
+		return true, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "render must be markdown or html"})
+	return false, false
+}
+
+// renderHTML replaces the Markdown fields of a book with sanitized HTML.
+func renderHTML(b *domain.Book) {
+	if b.Description != "" {
+		b.Description = markdown.ToHTML(b.Description)
+	}
+	if b.TableOfContents != "" {
+		b.TableOfContents = markdown.ToHTML(b.TableOfContents)
+	}
+}
+
+// GetBooksByStatus godoc
+// @Summary Get books by record status
+// @Description Get the books with a record status, draft by default, to work through the cataloging queue. Patron listings only ever show published books. Librarians only.
+// @Tags Library
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "draft (default), published or withdrawn"
+// @Success 200 {array} domain.Book
+// @Failure 400 {object} map[string]string
+// @Router /admin/books [get]
+func (h *BookHandler) GetBooksByStatus(c *gin.Context) {
+	status := c.DefaultQuery("status", domain.BookDraft)
+	if status != domain.BookDraft && status != domain.BookPublished && status != domain.BookWithdrawn {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be draft, published or withdrawn"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.BooksWithStatus(status)})
+}
+
+// PublishBook godoc
+// @Summary Publish a book
+// @Description Show a draft or withdrawn book to patrons. The record must be complete, with a year and ISBN. Librarians only.
+// @Tags Library
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} domain.Book
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/publish [post]
+func (h *BookHandler) PublishBook(c *gin.Context) {
+	h.setStatus(c, domain.BookPublished)
+}
+
+// WithdrawBook godoc
+// @Summary Withdraw a book
+// @Description Hide a book from patrons because it has left the collection. Its loan history is kept. Librarians only.
+// @Tags Library
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} domain.Book
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/withdraw [post]
+func (h *BookHandler) WithdrawBook(c *gin.Context) {
+	h.setStatus(c, domain.BookWithdrawn)
+}
+
+func (h *BookHandler) setStatus(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	book, err := h.uc.SetStatus(id, status)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": book})
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// benchmarkListing is the size of the catalog the listing benchmarks
+// encode.
+const benchmarkListing = 10_000
+
+func newBenchmarkHandler(b *testing.B) *BookHandler {
+	b.Helper()
+	gin.SetMode(gin.TestMode)
+	books := usecase.NewBookUsecase()
+	for id := 1; id <= benchmarkListing; id++ {
+		book := domain.Book{ID: id, Title: fmt.Sprintf("Book %d", id), Author: "Author", Year: 2000, Description: "A *book* about things."}
+		if err := books.CreateBook(book); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return &BookHandler{uc: books, policy: usecase.NewContentPolicyUsecase(usecase.DefaultContentPolicy)}
+}
+
+func benchmarkGetBooks(b *testing.B, serve func(h *BookHandler, c *gin.Context)) {
+	h := newBenchmarkHandler(b)
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		serve(h, c)
+		if w.Code != http.StatusOK {
+			b.Fatalf("status %d", w.Code)
+		}
+	}
+}
+
+// BenchmarkGetBooks lists the catalog as GET /books does, from the body
+// encoded once per catalog version.
+func BenchmarkGetBooks(b *testing.B) {
+	benchmarkGetBooks(b, (*BookHandler).GetBooks)
+}
+
+// BenchmarkGetBooksJSON lists the catalog by encoding it with c.JSON on
+// every request, as GET /books did before the listing was cached.
+func BenchmarkGetBooksJSON(b *testing.B) {
+	benchmarkGetBooks(b, func(h *BookHandler, c *gin.Context) {
+		books := h.policy.Filter(h.uc.GetBooks(), audienceOf(c))
+		c.JSON(http.StatusOK, gin.H{"data": books})
+	})
+}
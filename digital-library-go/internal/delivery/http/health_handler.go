@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/lock"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/resilience"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	breakers    *resilience.Registry
+	maintenance *usecase.MaintenanceUsecase
+	elector     *lock.Elector
+}
+
+func NewHealthHandler(breakers *resilience.Registry, maintenance *usecase.MaintenanceUsecase, elector *lock.Elector) *HealthHandler {
+	return &HealthHandler{breakers: breakers, maintenance: maintenance, elector: elector}
+}
+
+// Live godoc
+// @Summary Liveness and leadership
+// @Description Report that the server is up, and whether this instance is the elected leader that runs the scheduled jobs which reach outside it, with how often it was elected and lost leadership.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /healthz [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "leadership": h.elector.Status()})
+}
+
+// Ready godoc
+// @Summary Readiness and dependency health
+// @Description Report whether the server can take traffic, whether maintenance mode is on, and the circuit breaker state of each external dependency. An open breaker marks the server degraded but still ready, since only features using that dependency are affected. Maintenance mode does not make the server unready either, since reads still work.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	deps := h.breakers.Health()
+	status := "ok"
+	for _, d := range deps {
+		if d.State != domain.BreakerClosed {
+			status = "degraded"
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status, "maintenance": h.maintenance.Status().Enabled, "dependencies": deps})
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HoldRequest is the body accepted when placing a hold. PickupBranchID
+// is optional.
+type HoldRequest struct {
+	MemberID       int `json:"member_id"`
+	BookID         int `json:"book_id"`
+	PickupBranchID int `json:"pickup_branch_id"`
+}
+
+// MyHoldRequest is the body accepted when members place a hold for
+// themselves.
+type MyHoldRequest struct {
+	BookID         int `json:"book_id"`
+	PickupBranchID int `json:"pickup_branch_id"`
+}
+
+type HoldHandler struct {
+	uc *usecase.HoldUsecase
+}
+
+func NewHoldHandler(uc *usecase.HoldUsecase) *HoldHandler {
+	return &HoldHandler{uc: uc}
+}
+
+// GetHolds godoc
+// @Summary Get all holds
+// @Description Get list of all holds in placement order
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Hold
+// @Router /holds [get]
+func (h *HoldHandler) GetHolds(c *gin.Context) {
+	holds := h.uc.GetHolds()
+	c.JSON(http.StatusOK, gin.H{"data": holds})
+}
+
+// PlaceHold godoc
+// @Summary Place a hold on a book
+// @Description Queue a member for a book, optionally to pick up at a branch; the hold limit comes from the member's plan. When the hold's turn comes and the pickup branch has no copy, one is sent there and the hold is in_transit until it arrives.
+// @Tags Circulation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hold body HoldRequest true "Member, book and pickup branch"
+// @Success 201 {object} domain.Hold
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /holds [post]
+func (h *HoldHandler) PlaceHold(c *gin.Context) {
+	var req HoldRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if req.PickupBranchID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pickup_branch_id"})
+		return
+	}
+
+	hold, err := h.uc.PlaceHold(req.MemberID, req.BookID, req.PickupBranchID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": hold})
+}
+
+// PlaceMyHold godoc
+// @Summary Place a hold for myself
+// @Description Queue the authenticated member for a book, optionally to pick up at a branch. The same rules apply as for POST /holds.
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param hold body MyHoldRequest true "Book and pickup branch"
+// @Success 201 {object} domain.Hold
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/holds [post]
+func (h *HoldHandler) PlaceMyHold(c *gin.Context) {
+	var req MyHoldRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if req.PickupBranchID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pickup_branch_id"})
+		return
+	}
+
+	hold, err := h.uc.PlaceHold(currentMemberID(c), req.BookID, req.PickupBranchID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": hold})
+}
+
+// CancelHold godoc
+// @Summary Cancel a hold
+// @Description Remove a hold by ID
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Hold ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /holds/{id} [delete]
+func (h *HoldHandler) CancelHold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.CancelHold(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "hold cancelled"})
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/bookimport"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize is the largest export file accepted, 5 MB.
+const maxImportSize = 5 << 20
+
+type ImportHandler struct {
+	uc *usecase.ImportUsecase
+}
+
+func NewImportHandler(uc *usecase.ImportUsecase) *ImportHandler {
+	return &ImportHandler{uc: uc}
+}
+
+// ImportReadingHistory godoc
+// @Summary Import reading history
+// @Description Import a Goodreads or LibraryThing export into the authenticated member's reading lists and reviews. Send the file as the request body, or as the "file" field of a multipart form. Books are matched to the catalog by ISBN, then by title and author; rows that match no book are listed in the report.
+// @Tags Me
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param source query string true "goodreads or librarything"
+// @Success 200 {object} domain.ImportReport
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /me/import [post]
+func (h *ImportHandler) ImportReadingHistory(c *gin.Context) {
+	source := c.Query("source")
+	file, err := importFile(c)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	defer file.Close()
+
+	rows, err := bookimport.Parse(source, file)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Import(currentMemberID(c), c.Request.Host, source, rows)})
+}
+
+// importFile returns the uploaded file: the request body, or the "file"
+// field of a multipart form. Either is limited to maxImportSize.
+func importFile(c *gin.Context) (io.ReadCloser, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		f, _, err := c.Request.FormFile("file")
+		return f, err
+	}
+	return c.Request.Body, nil
+}
+
+func importError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file must be at most 5 MB"})
+	case errors.Is(err, http.ErrMissingFile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxKioskMessageSize bounds a kiosk message, which is a few fields.
+const maxKioskMessageSize = 64 << 10
+
+type IntegrationHandler struct {
+	uc *usecase.IntegrationUsecase
+}
+
+func NewIntegrationHandler(uc *usecase.IntegrationUsecase) *IntegrationHandler {
+	return &IntegrationHandler{uc: uc}
+}
+
+// ReceiveKioskMessage godoc
+// @Summary Receive a checkout or checkin from a kiosk
+// @Description Apply a circulation message from a self-service kiosk or other external loan system. The request must carry X-Integration-Timestamp (Unix seconds, within 5 minutes of the server's clock) and X-Integration-Signature, the hex HMAC-SHA256 of the timestamp, a dot and the body, keyed with the integration's shared secret. A message with an ID already handled returns the same loan.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param name path string true "Integration name"
+// @Param message body domain.KioskMessage true "Circulation message"
+// @Success 200 {object} domain.Loan
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /integrations/{name} [post]
+func (h *IntegrationHandler) ReceiveKioskMessage(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxKioskMessageSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message too large"})
+		return
+	}
+
+	now := time.Now()
+	err = h.uc.Verify(c.Param("name"), c.GetHeader("X-Integration-Timestamp"), c.GetHeader("X-Integration-Signature"), body, now)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	var msg domain.KioskMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := msg.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loan, err := h.uc.Handle(c.Param("name"), msg, now)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": loan})
+}
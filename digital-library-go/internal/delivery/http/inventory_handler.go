@@ -0,0 +1,182 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryAuditRequest starts an audit of the copies kept within the
+// Scope location.
+type InventoryAuditRequest struct {
+	Scope string `json:"scope"`
+}
+
+type InventoryHandler struct {
+	uc *usecase.InventoryUsecase
+}
+
+func NewInventoryHandler(uc *usecase.InventoryUsecase) *InventoryHandler {
+	return &InventoryHandler{uc: uc}
+}
+
+// GetAudits godoc
+// @Summary Get all inventory audits
+// @Description Get list of all inventory audits, running and closed. Librarians only.
+// @Tags Inventory
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.InventoryAudit
+// @Router /inventory/audits [get]
+func (h *InventoryHandler) GetAudits(c *gin.Context) {
+	audits := h.uc.GetAudits()
+	c.JSON(http.StatusOK, gin.H{"data": audits})
+}
+
+// GetAuditByID godoc
+// @Summary Get an inventory audit by ID
+// @Description Get inventory audit details by ID. Librarians only.
+// @Tags Inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Audit ID"
+// @Success 200 {object} domain.InventoryAudit
+// @Failure 404 {object} map[string]string
+// @Router /inventory/audits/{id} [get]
+func (h *InventoryHandler) GetAuditByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	audit, err := h.uc.GetAuditByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": audit})
+}
+
+// StartAudit godoc
+// @Summary Start an inventory audit
+// @Description Open an audit session for the copies kept within the scope location, e.g. 2 or 2/Fiction, or all copies if it is empty. Librarians only.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param audit body InventoryAuditRequest false "Audit scope"
+// @Success 201 {object} domain.InventoryAudit
+// @Router /inventory/audits [post]
+func (h *InventoryHandler) StartAudit(c *gin.Context) {
+	var req InventoryAuditRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+			return
+		}
+	}
+
+	audit := h.uc.StartAudit(req.Scope)
+	c.JSON(http.StatusCreated, gin.H{"data": audit})
+}
+
+// AddScans godoc
+// @Summary Add scanned barcodes to an audit
+// @Description Record a batch of barcodes scanned at one location. Librarians only.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Audit ID"
+// @Param scans body domain.ScanBatch true "Scanned barcodes"
+// @Success 200 {object} domain.InventoryAudit
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /inventory/audits/{id}/scans [post]
+func (h *InventoryHandler) AddScans(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var batch domain.ScanBatch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := batch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	audit, err := h.uc.AddScans(id, batch)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": audit})
+}
+
+// CloseAudit godoc
+// @Summary Close an inventory audit
+// @Description End an audit session and return its reconciliation report. Librarians only.
+// @Tags Inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Audit ID"
+// @Success 200 {object} domain.InventoryReport
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /inventory/audits/{id}/close [post]
+func (h *InventoryHandler) CloseAudit(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	_, err = h.uc.CloseAudit(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	h.GetReport(c)
+}
+
+// GetReport godoc
+// @Summary Get an audit's reconciliation report
+// @Description Compare the scans so far with the catalog: missing, on-loan, misplaced and unknown copies. Librarians only.
+// @Tags Inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Audit ID"
+// @Success 200 {object} domain.InventoryReport
+// @Failure 404 {object} map[string]string
+// @Router /inventory/audits/{id}/report [get]
+func (h *InventoryHandler) GetReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	report, err := h.uc.Report(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
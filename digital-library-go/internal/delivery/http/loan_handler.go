@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoanRequest is the body accepted when lending a book.
+type LoanRequest struct {
+	MemberID int `json:"member_id"`
+	BookID   int `json:"book_id"`
+}
+
+type LoanHandler struct {
+	uc *usecase.LoanUsecase
+}
+
+func NewLoanHandler(uc *usecase.LoanUsecase) *LoanHandler {
+	return &LoanHandler{uc: uc}
+}
+
+// GetLoans godoc
+// @Summary Get all loans
+// @Description Get list of all loans, including returned ones
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Loan
+// @Router /loans [get]
+func (h *LoanHandler) GetLoans(c *gin.Context) {
+	loans := h.uc.GetLoans()
+	c.JSON(http.StatusOK, gin.H{"data": loans})
+}
+
+// GetLoanByID godoc
+// @Summary Get a loan by ID
+// @Description Get loan details by ID
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Loan ID"
+// @Success 200 {object} domain.Loan
+// @Failure 404 {object} map[string]string
+// @Router /loans/{id} [get]
+func (h *LoanHandler) GetLoanByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	loan, err := h.uc.GetLoanByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": loan})
+}
+
+// Checkout godoc
+// @Summary Lend a book to a member
+// @Description Check out a book; the due date and loan limit come from the member's plan. A book on the hold shelf only goes to the member it is waiting for.
+// @Tags Circulation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param loan body LoanRequest true "Member and book"
+// @Success 201 {object} domain.Loan
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /loans [post]
+func (h *LoanHandler) Checkout(c *gin.Context) {
+	var req LoanRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	loan, err := h.uc.Checkout(req.MemberID, req.BookID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": loan})
+}
+
+// ReturnLoan godoc
+// @Summary Return a loaned book
+// @Description Mark a loan as returned
+// @Tags Circulation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Loan ID"
+// @Success 200 {object} domain.Loan
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /loans/{id}/return [post]
+func (h *LoanHandler) ReturnLoan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	loan, err := h.uc.Return(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": loan})
+}
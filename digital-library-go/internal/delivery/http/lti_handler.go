@@ -0,0 +1,224 @@
+package http
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reservesPage is the reserve list shown inside the learning management
+// system. Instructors also get forms to add and remove books; the session
+// token rides along in every link and form.
+var reservesPage = template.Must(template.New("reserves").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{with .Course}}{{.Code}} reserves{{else}}Course reserves{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 1rem; }
+li { margin: 0.4rem 0; }
+.error { color: #a00; }
+form.inline { display: inline; }
+</style>
+</head>
+<body>
+{{with .Course}}<h1>{{.Title}} <small>({{.Code}})</small></h1>{{end}}
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+{{if .Course}}
+{{if .Books}}
+<ul>
+{{range .Books}}<li><strong>{{.Title}}</strong>{{with .Author}} by {{.}}{{end}}{{with .Year}} ({{.}}){{end}}{{with .ISBN}}, ISBN {{.}}{{end}}
+{{if $.Instructor}}<form class="inline" method="post" action="/lti/reserves/remove"><input type="hidden" name="session" value="{{$.Session}}"><input type="hidden" name="book_id" value="{{.ID}}"><button>Remove</button></form>{{end}}</li>
+{{end}}</ul>
+{{else}}
+<p>No books are on reserve for this course yet.</p>
+{{end}}
+{{if .Instructor}}
+<form method="post" action="/lti/reserves">
+<input type="hidden" name="session" value="{{.Session}}">
+<label>ISBN <input name="isbn" required></label>
+<button>Put on reserve</button>
+</form>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+type reservesView struct {
+	Course     *domain.Course
+	Books      []domain.Book
+	Instructor bool
+	Session    string
+	Error      string
+}
+
+type LTIHandler struct {
+	uc      *usecase.LTIUsecase
+	courses *usecase.CourseUsecase
+}
+
+func NewLTIHandler(uc *usecase.LTIUsecase, courses *usecase.CourseUsecase) *LTIHandler {
+	return &LTIHandler{uc: uc, courses: courses}
+}
+
+// Login godoc
+// @Summary Start an LTI launch
+// @Description OpenID Connect login initiation from a learning management system. Redirects to the platform's authorization endpoint, which posts the launch to /lti/launch.
+// @Tags LTI
+// @Accept x-www-form-urlencoded
+// @Param iss formData string true "Platform issuer"
+// @Param login_hint formData string true "Login hint"
+// @Param client_id formData string false "Tool's client ID at the platform"
+// @Param lti_message_hint formData string false "Message hint"
+// @Success 302
+// @Failure 400 {object} map[string]string
+// @Router /lti/login [post]
+func (h *LTIHandler) Login(c *gin.Context) {
+	target, err := h.uc.BeginLaunch(
+		c.Request.FormValue("iss"),
+		c.Request.FormValue("client_id"),
+		c.Request.FormValue("login_hint"),
+		c.Request.FormValue("lti_message_hint"),
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Redirect(http.StatusFound, target)
+}
+
+// Launch godoc
+// @Summary Complete an LTI launch
+// @Description Verify the launch a learning management system posts and show the course's reserve list. An instructor launching from a course the library does not know yet sets it up.
+// @Tags LTI
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id_token formData string true "Signed launch"
+// @Param state formData string true "Login state"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Router /lti/launch [post]
+func (h *LTIHandler) Launch(c *gin.Context) {
+	token, session, err := h.uc.CompleteLaunch(c.Request.Context(), c.PostForm("state"), c.PostForm("id_token"))
+	switch {
+	case errors.Is(err, usecase.ErrInvalidState):
+		h.render(c, http.StatusBadRequest, reservesView{Error: err.Error()})
+	case errors.Is(err, usecase.ErrCourseNotSetUp):
+		h.render(c, http.StatusOK, reservesView{Error: err.Error()})
+	case err != nil:
+		h.render(c, http.StatusUnauthorized, reservesView{Error: err.Error()})
+	default:
+		h.show(c, http.StatusOK, token, session, "")
+	}
+}
+
+// GetReserves godoc
+// @Summary Show an LTI reserve list
+// @Description Show the reserve list of an LTI session again, e.g. after an instructor changed it
+// @Tags LTI
+// @Produce html
+// @Param session query string true "LTI session token"
+// @Success 200 {string} string
+// @Failure 401 {string} string
+// @Router /lti/reserves [get]
+func (h *LTIHandler) GetReserves(c *gin.Context) {
+	token := c.Query("session")
+	session, err := h.uc.Session(token)
+	if err != nil {
+		h.render(c, http.StatusUnauthorized, reservesView{Error: err.Error()})
+		return
+	}
+	h.show(c, http.StatusOK, token, session, "")
+}
+
+// AddReserve godoc
+// @Summary Put a book on reserve from an LMS
+// @Description Put the book with an ISBN on reserve for an LTI session's course. Instructors only.
+// @Tags LTI
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param session formData string true "LTI session token"
+// @Param isbn formData string true "ISBN"
+// @Success 303
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 403 {string} string
+// @Router /lti/reserves [post]
+func (h *LTIHandler) AddReserve(c *gin.Context) {
+	token := c.PostForm("session")
+	h.change(c, token, h.uc.AddReserve(token, c.PostForm("isbn")))
+}
+
+// RemoveReserve godoc
+// @Summary Take a book off reserve from an LMS
+// @Description Take a book off reserve for an LTI session's course. Instructors only.
+// @Tags LTI
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param session formData string true "LTI session token"
+// @Param book_id formData int true "Book ID"
+// @Success 303
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 403 {string} string
+// @Router /lti/reserves/remove [post]
+func (h *LTIHandler) RemoveReserve(c *gin.Context) {
+	token := c.PostForm("session")
+	bookID, err := strconv.Atoi(c.PostForm("book_id"))
+	if err != nil {
+		h.change(c, token, errors.New("invalid id"))
+		return
+	}
+	h.change(c, token, h.uc.RemoveReserve(token, bookID))
+}
+
+// change answers an instructor's form: back to the list when it worked,
+// else the list with the error.
+func (h *LTIHandler) change(c *gin.Context, token string, err error) {
+	if err == nil {
+		c.Redirect(http.StatusSeeOther, "/lti/reserves?session="+url.QueryEscape(token))
+		return
+	}
+	session, sessionErr := h.uc.Session(token)
+	switch {
+	case sessionErr != nil:
+		h.render(c, http.StatusUnauthorized, reservesView{Error: sessionErr.Error()})
+	case errors.Is(err, usecase.ErrNotCourseInstructor):
+		h.show(c, http.StatusForbidden, token, session, err.Error())
+	default:
+		h.show(c, http.StatusBadRequest, token, session, err.Error())
+	}
+}
+
+func (h *LTIHandler) show(c *gin.Context, status int, token string, session usecase.LTISession, msg string) {
+	course, err := h.courses.GetCourseByID(session.CourseID)
+	if err != nil {
+		h.render(c, http.StatusNotFound, reservesView{Error: err.Error()})
+		return
+	}
+	books, _ := h.courses.Reserves(course.ID)
+	h.render(c, status, reservesView{
+		Course:     &course,
+		Books:      books,
+		Instructor: session.Role == domain.CourseInstructor,
+		Session:    token,
+		Error:      msg,
+	})
+}
+
+func (h *LTIHandler) render(c *gin.Context, status int, view reservesView) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := reservesPage.Execute(c.Writer, view); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceRequest turns maintenance mode on or off. Message is shown
+// to refused clients.
+type MaintenanceRequest struct {
+	Enabled    *bool  `json:"enabled"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after"`
+}
+
+type MaintenanceHandler struct {
+	uc   *usecase.MaintenanceUsecase
+	auth *usecase.AuthUsecase
+}
+
+func NewMaintenanceHandler(uc *usecase.MaintenanceUsecase, auth *usecase.AuthUsecase) *MaintenanceHandler {
+	return &MaintenanceHandler{uc: uc, auth: auth}
+}
+
+// GetMaintenance godoc
+// @Summary Get maintenance mode
+// @Description Report whether maintenance mode is on, since when and who turned it on. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Maintenance
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Status()})
+}
+
+// SetMaintenance godoc
+// @Summary Turn maintenance mode on or off
+// @Description While maintenance mode is on, requests that change data get 503 with Retry-After (retry_after seconds, default 300) and reads carry on. Signing in and this endpoint keep working. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param maintenance body MaintenanceRequest true "New state"
+// @Success 200 {object} domain.Maintenance
+// @Failure 400 {object} map[string]string
+// @Router /admin/maintenance [post]
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	var req MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if req.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enabled is required"})
+		return
+	}
+	if req.RetryAfter < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "retry_after must not be negative"})
+		return
+	}
+
+	admin, _ := h.auth.Member(bearerToken(c))
+	if !*req.Enabled {
+		c.JSON(http.StatusOK, gin.H{"data": h.uc.Disable(admin.CardNumber, time.Now())})
+		return
+	}
+	state, _ := h.uc.Enable(admin.CardNumber, req.Message, req.RetryAfter, time.Now())
+	c.JSON(http.StatusOK, gin.H{"data": state})
+}
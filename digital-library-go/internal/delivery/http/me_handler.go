@@ -0,0 +1,317 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileRequest is the body accepted when a member edits their profile.
+type ProfileRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// ListBookRequest is the body accepted when adding a book to a list.
+type ListBookRequest struct {
+	BookID int `json:"book_id"`
+}
+
+// MeHandler serves the self-service portal. Every route acts on the
+// authenticated member only; staff use the /members routes instead.
+type MeHandler struct {
+	members *usecase.MemberUsecase
+	loans   *usecase.LoanUsecase
+	holds   *usecase.HoldUsecase
+	fines   *usecase.FineUsecase
+	lists   *usecase.ReadingListUsecase
+}
+
+func NewMeHandler(
+	members *usecase.MemberUsecase,
+	loans *usecase.LoanUsecase,
+	holds *usecase.HoldUsecase,
+	fines *usecase.FineUsecase,
+	lists *usecase.ReadingListUsecase,
+) *MeHandler {
+	return &MeHandler{members: members, loans: loans, holds: holds, fines: fines, lists: lists}
+}
+
+// GetProfile godoc
+// @Summary Get my profile
+// @Description Get the authenticated member's profile
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.Member
+// @Router /me [get]
+func (h *MeHandler) GetProfile(c *gin.Context) {
+	member, err := h.members.GetMemberByID(currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// UpdateProfile godoc
+// @Summary Update my profile
+// @Description Update the authenticated member's name, email and phone number for SMS notifications
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param profile body ProfileRequest true "Profile data"
+// @Success 200 {object} domain.Member
+// @Failure 400 {object} map[string]string
+// @Router /me [put]
+func (h *MeHandler) UpdateProfile(c *gin.Context) {
+	var req ProfileRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+		return
+	}
+
+	if req.Phone != "" && !domain.ValidPhone(req.Phone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidPhone.Error()})
+		return
+	}
+
+	member, err := h.members.UpdateProfile(currentMemberID(c), req.Name, req.Email, req.Phone)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// UpdatePrivacy godoc
+// @Summary Update my privacy settings
+// @Description Choose which profile fields appear on the public profile
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param privacy body domain.Privacy true "Privacy settings"
+// @Success 200 {object} domain.Member
+// @Router /me/privacy [put]
+func (h *MeHandler) UpdatePrivacy(c *gin.Context) {
+	var privacy domain.Privacy
+
+	if err := c.ShouldBindJSON(&privacy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	member, err := h.members.SetPrivacy(currentMemberID(c), privacy)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// GetLoans godoc
+// @Summary Get my current loans
+// @Description Get the authenticated member's loans that have not been returned
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Loan
+// @Router /me/loans [get]
+func (h *MeHandler) GetLoans(c *gin.Context) {
+	loans := h.loans.ActiveLoansForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": loans})
+}
+
+// GetHolds godoc
+// @Summary Get my holds
+// @Description Get the authenticated member's holds with their position in each queue and an estimate of when they will be ready, based on the average loan duration
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.QueuedHold
+// @Router /me/holds [get]
+func (h *MeHandler) GetHolds(c *gin.Context) {
+	queue := h.holds.QueueForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": h.loans.EstimateHolds(queue, time.Now())})
+}
+
+// CancelHold godoc
+// @Summary Cancel one of my holds
+// @Description Cancel one of the authenticated member's holds. A book ready for pickup passes to the next member in the queue.
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Hold ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/holds/{id} [delete]
+func (h *MeHandler) CancelHold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.holds.CancelMemberHold(currentMemberID(c), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hold not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "hold cancelled"})
+}
+
+// GetFines godoc
+// @Summary Get my fines
+// @Description Get the authenticated member's fines
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Fine
+// @Router /me/fines [get]
+func (h *MeHandler) GetFines(c *gin.Context) {
+	fines := h.fines.FinesForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": fines})
+}
+
+// GetReadingLists godoc
+// @Summary Get my reading lists
+// @Description Get the authenticated member's reading lists
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.ReadingList
+// @Router /me/lists [get]
+func (h *MeHandler) GetReadingLists(c *gin.Context) {
+	lists := h.lists.ListsForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": lists})
+}
+
+// CreateReadingList godoc
+// @Summary Create a reading list
+// @Description Create an empty reading list for the authenticated member
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list body domain.ReadingList true "Reading list"
+// @Success 201 {object} domain.ReadingList
+// @Failure 400 {object} map[string]string
+// @Router /me/lists [post]
+func (h *MeHandler) CreateReadingList(c *gin.Context) {
+	var list domain.ReadingList
+
+	if err := c.ShouldBindJSON(&list); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := list.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.lists.CreateList(currentMemberID(c), list)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// AddToReadingList godoc
+// @Summary Add a book to a reading list
+// @Description Add a book to one of the authenticated member's reading lists
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reading list ID"
+// @Param book body ListBookRequest true "Book to add"
+// @Success 200 {object} domain.ReadingList
+// @Failure 404 {object} map[string]string
+// @Router /me/lists/{id}/books [post]
+func (h *MeHandler) AddToReadingList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ListBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	list, err := h.lists.AddBook(currentMemberID(c), id, req.BookID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// DeleteReadingList godoc
+// @Summary Delete a reading list
+// @Description Delete one of the authenticated member's reading lists
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reading list ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/lists/{id} [delete]
+func (h *MeHandler) DeleteReadingList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.lists.DeleteList(currentMemberID(c), id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reading list deleted"})
+}
+
+// GetPublicProfile godoc
+// @Summary Get a member's public profile
+// @Description Get the fields a member has chosen to share
+// @Tags Members
+// @Produce json
+// @Param id path int true "Member ID"
+// @Success 200 {object} domain.PublicProfile
+// @Failure 404 {object} map[string]string
+// @Router /members/{id}/profile [get]
+func (h *MeHandler) GetPublicProfile(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	member, err := h.members.GetMemberByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member.Public(h.lists.ListsForMember(id))})
+}
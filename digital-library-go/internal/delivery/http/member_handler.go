@@ -0,0 +1,279 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MemberHandler struct {
+	uc *usecase.MemberUsecase
+}
+
+func NewMemberHandler(uc *usecase.MemberUsecase) *MemberHandler {
+	return &MemberHandler{uc: uc}
+}
+
+// errStaffAccount keeps librarians from taking over librarian and admin
+// accounts, for example by setting their password.
+var errStaffAccount = domain.Forbidden("only admins can change staff accounts")
+
+// checkAccount refuses to let a librarian change the account with the
+// given ID when it belongs to staff. Admins may change any account.
+func (h *MemberHandler) checkAccount(c *gin.Context, id int) error {
+	target, err := h.uc.GetMemberByID(id)
+	if err != nil {
+		return err
+	}
+	caller, err := h.uc.GetMemberByID(currentMemberID(c))
+	if err != nil {
+		return err
+	}
+	if target.IsStaff() && caller.Role != domain.RoleAdmin {
+		return errStaffAccount
+	}
+	return nil
+}
+
+// GetMembers godoc
+// @Summary Get all members
+// @Description Get list of all library members
+// @Tags Members
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Member
+// @Router /members [get]
+func (h *MemberHandler) GetMembers(c *gin.Context) {
+	members := h.uc.GetMembers()
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// GetMemberByID godoc
+// @Summary Get a member by ID
+// @Description Get member details by ID
+// @Tags Members
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Member ID"
+// @Success 200 {object} domain.Member
+// @Failure 404 {object} map[string]string
+// @Router /members/{id} [get]
+func (h *MemberHandler) GetMemberByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	member, err := h.uc.GetMemberByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// CreateMember godoc
+// @Summary Create a new member
+// @Description Register a new library member on a membership plan. The member belongs to the tenant given, or else to the host name the request was sent to.
+// @Tags Members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param member body domain.Member true "Member data"
+// @Success 201 {object} domain.Member
+// @Failure 400 {object} map[string]string
+// @Router /members [post]
+func (h *MemberHandler) CreateMember(c *gin.Context) {
+	var member domain.Member
+
+	if err := c.ShouldBindJSON(&member); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := member.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if member.Tenant == "" {
+		member.Tenant = c.Request.Host
+	}
+	created, err := h.uc.CreateMember(member)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateMember godoc
+// @Summary Update a member
+// @Description Update member details by ID. Librarians and admins only; only admins can update staff accounts.
+// @Tags Members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Member ID"
+// @Param member body domain.Member true "Updated member data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /members/{id} [put]
+func (h *MemberHandler) UpdateMember(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var member domain.Member
+	if err := c.ShouldBindJSON(&member); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := member.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.checkAccount(c, id); err != nil {
+		abort(c, err)
+		return
+	}
+	if err := h.uc.UpdateMember(id, member); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member updated"})
+}
+
+// DeleteMember godoc
+// @Summary Delete a member
+// @Description Delete member by ID. Librarians and admins only; only admins can delete staff accounts.
+// @Tags Members
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Member ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /members/{id} [delete]
+func (h *MemberHandler) DeleteMember(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.checkAccount(c, id); err != nil {
+		abort(c, err)
+		return
+	}
+	if err := h.uc.DeleteMember(id); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member deleted"})
+}
+
+// GetMemberByCard godoc
+// @Summary Look up a member by card number
+// @Description Resolve a scanned library card to its member
+// @Tags Members
+// @Produce json
+// @Security BearerAuth
+// @Param number path string true "Card number"
+// @Success 200 {object} domain.Member
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /members/card/{number} [get]
+func (h *MemberHandler) GetMemberByCard(c *gin.Context) {
+	member, err := h.uc.GetMemberByCard(c.Param("number"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// ReplaceCard godoc
+// @Summary Replace a member's library card
+// @Description Issue a new card number; the old number is invalidated but kept in the card history. Librarians and admins only; only admins can replace staff cards.
+// @Tags Members
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Member ID"
+// @Success 200 {object} domain.Member
+// @Failure 404 {object} map[string]string
+// @Router /members/{id}/card [post]
+func (h *MemberHandler) ReplaceCard(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.checkAccount(c, id); err != nil {
+		abort(c, err)
+		return
+	}
+	member, err := h.uc.ReplaceCard(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// RoleRequest is the body accepted when changing a member's role.
+type RoleRequest struct {
+	Role string `json:"role"`
+}
+
+// SetRole godoc
+// @Summary Change a member's role
+// @Description Grant or revoke librarian and admin rights
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Member ID"
+// @Param role body RoleRequest true "New role"
+// @Success 200 {object} domain.Member
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/members/{id}/role [put]
+func (h *MemberHandler) SetRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	member, err := h.uc.SetRole(id, req.Role)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetadataRefreshHandler struct {
+	uc *usecase.MetadataRefreshUsecase
+}
+
+func NewMetadataRefreshHandler(uc *usecase.MetadataRefreshUsecase) *MetadataRefreshHandler {
+	return &MetadataRefreshHandler{uc: uc}
+}
+
+// StartRefresh godoc
+// @Summary Refresh missing book metadata
+// @Description Start a background job that looks up books with missing fields in external catalogs and fills them in. Librarians only.
+// @Tags Background Task
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} domain.MetadataRefreshReport
+// @Failure 409 {object} map[string]string
+// @Router /tasks/refresh-metadata [post]
+func (h *MetadataRefreshHandler) StartRefresh(c *gin.Context) {
+	report, err := h.uc.Start()
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": report})
+}
+
+// GetRefreshReport godoc
+// @Summary Get the metadata refresh report
+// @Description Get the per-book outcomes of the current or latest metadata refresh. Librarians only.
+// @Tags Background Task
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.MetadataRefreshReport
+// @Failure 404 {object} map[string]string
+// @Router /tasks/refresh-metadata [get]
+func (h *MetadataRefreshHandler) GetRefreshReport(c *gin.Context) {
+	report, err := h.uc.Report()
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -0,0 +1,317 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/ncip"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxNCIPMessageSize bounds an NCIP request.
+const maxNCIPMessageSize = 256 << 10
+
+// NCIPHandler answers consortium partners speaking NCIP. Each partner
+// agency proves who it is with the secret it shares with this library.
+type NCIPHandler struct {
+	agencyID string
+	partners map[string]string
+	members  *usecase.MemberUsecase
+	plans    *usecase.PlanUsecase
+	books    *usecase.BookUsecase
+	copies   *usecase.CopyUsecase
+	loans    *usecase.LoanUsecase
+	holds    *usecase.HoldUsecase
+	guard    *usecase.LoginGuardUsecase
+}
+
+// NewNCIPHandler answers as agencyID to the partners, a map from each
+// partner's agency ID to its shared secret.
+func NewNCIPHandler(agencyID string, partners map[string]string, members *usecase.MemberUsecase, plans *usecase.PlanUsecase, books *usecase.BookUsecase, copies *usecase.CopyUsecase, loans *usecase.LoanUsecase, holds *usecase.HoldUsecase, guard *usecase.LoginGuardUsecase) *NCIPHandler {
+	return &NCIPHandler{
+		agencyID: agencyID,
+		partners: partners,
+		members:  members,
+		plans:    plans,
+		books:    books,
+		copies:   copies,
+		loans:    loans,
+		holds:    holds,
+		guard:    guard,
+	}
+}
+
+// HandleNCIP godoc
+// @Summary Handle an NCIP message
+// @Description Answer an NCIP 2.02 LookupUser, RequestItem or CheckOutItem message from a consortium partner. The InitiationHeader must name a known partner in FromAgencyId and carry its shared secret in FromAgencyAuthentication. As NCIP requires, problems are reported in the response message with 200 OK.
+// @Tags Circulation
+// @Accept xml
+// @Produce xml
+// @Success 200 {object} ncip.Message
+// @Router /ncip [post]
+func (h *NCIPHandler) HandleNCIP(c *gin.Context) {
+	resp := ncip.Message{Version: ncip.Version}
+	var req ncip.Message
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxNCIPMessageSize))
+	if err == nil {
+		err = xml.Unmarshal(body, &req)
+	}
+
+	switch {
+	case err != nil:
+		resp.Problem = []ncip.Problem{{ProblemType: ncip.InvalidMessageSyntaxError, ProblemDetail: err.Error()}}
+	case req.LookupUser != nil:
+		resp.LookupUserResponse = h.lookupUser(c, req.LookupUser)
+	case req.RequestItem != nil:
+		resp.RequestItemResponse = h.requestItem(c, req.RequestItem)
+	case req.CheckOutItem != nil:
+		resp.CheckOutItemResponse = h.checkOutItem(c, req.CheckOutItem)
+	default:
+		resp.Problem = []ncip.Problem{{ProblemType: ncip.UnsupportedService, ProblemDetail: "supported services are LookupUser, RequestItem and CheckOutItem"}}
+	}
+
+	out, err := xml.Marshal(resp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), out...))
+}
+
+func (h *NCIPHandler) lookupUser(c *gin.Context, req *ncip.LookupUser) *ncip.LookupUserResponse {
+	resp := &ncip.LookupUserResponse{}
+	var problem *ncip.Problem
+	if resp.ResponseHeader, problem = h.authenticate(req.InitiationHeader); problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+	member, problem := h.user(c, req.UserID, req.AuthenticationInput)
+	if problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+	resp.UserID = &ncip.UserID{AgencyID: h.agencyID, UserIdentifierValue: member.CardNumber}
+	resp.UserOptionalFields = h.userFields(member, req.UserElementType)
+	return resp
+}
+
+// userFields returns the user elements asked for. The email address is
+// only shared if the member made it public.
+func (h *NCIPHandler) userFields(member domain.Member, types []string) *ncip.UserOptionalFields {
+	fields := &ncip.UserOptionalFields{}
+	for _, t := range types {
+		switch t {
+		case ncip.NameInformation:
+			fields.NameInformation = &ncip.NameInfo{}
+			fields.NameInformation.PersonalNameInformation.UnstructuredPersonalUserName = member.Name
+		case ncip.UserAddressInformation:
+			if member.Privacy.ShowEmail && member.Email != "" {
+				address := ncip.UserAddress{UserAddressRoleType: "Home"}
+				address.ElectronicAddress.ElectronicAddressType = ncip.ElectronicAddressMailto
+				address.ElectronicAddress.ElectronicAddressData = member.Email
+				fields.UserAddressInformation = append(fields.UserAddressInformation, address)
+			}
+		case ncip.UserPrivilege:
+			if plan, err := h.plans.GetPlanByID(member.PlanID); err == nil {
+				fields.UserPrivilege = append(fields.UserPrivilege, ncip.Privilege{
+					AgencyID:                 h.agencyID,
+					AgencyUserPrivilegeType:  ncip.MembershipPlan,
+					UserPrivilegeDescription: plan.Name,
+				})
+			}
+		}
+	}
+	return fields
+}
+
+func (h *NCIPHandler) requestItem(c *gin.Context, req *ncip.RequestItem) *ncip.RequestItemResponse {
+	resp := &ncip.RequestItemResponse{}
+	var problem *ncip.Problem
+	if resp.ResponseHeader, problem = h.authenticate(req.InitiationHeader); problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+	if req.RequestType != "" && !strings.EqualFold(req.RequestType, ncip.RequestTypeHold) {
+		resp.Problem = []ncip.Problem{{ProblemType: ncip.UnknownValueFromKnownScheme, ProblemDetail: "only holds can be requested", ProblemElement: "RequestType", ProblemValue: req.RequestType}}
+		return resp
+	}
+	member, problem := h.user(c, req.UserID, nil)
+	if problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+	bookID, problem := h.title(req.ItemID, req.BibliographicID)
+	if problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+
+	hold, err := h.holds.PlaceHold(member.ID, bookID, 0)
+	switch {
+	case errors.Is(err, usecase.ErrDuplicateHold):
+		problem = &ncip.Problem{ProblemType: ncip.DuplicateRequest, ProblemDetail: err.Error()}
+	case errors.Is(err, usecase.ErrHoldLimitReached):
+		problem = &ncip.Problem{ProblemType: ncip.UserIneligibleToRequest, ProblemDetail: err.Error()}
+	case err != nil:
+		problem = &ncip.Problem{ProblemType: ncip.UnknownItem, ProblemDetail: err.Error()}
+	}
+	if problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+
+	resp.RequestID = &ncip.RequestID{AgencyID: h.agencyID, RequestIdentifierValue: strconv.Itoa(hold.ID)}
+	resp.UserID = &ncip.UserID{AgencyID: h.agencyID, UserIdentifierValue: member.CardNumber}
+	resp.ItemID = req.ItemID
+	resp.RequestType = ncip.RequestTypeHold
+	resp.RequestScopeType = req.RequestScopeType
+	if resp.RequestScopeType == "" {
+		resp.RequestScopeType = ncip.RequestScopeBibliographic
+	}
+	return resp
+}
+
+func (h *NCIPHandler) checkOutItem(c *gin.Context, req *ncip.CheckOutItem) *ncip.CheckOutItemResponse {
+	resp := &ncip.CheckOutItemResponse{}
+	var problem *ncip.Problem
+	if resp.ResponseHeader, problem = h.authenticate(req.InitiationHeader); problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+	member, problem := h.user(c, req.UserID, nil)
+	if problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+	if req.ItemID == nil || req.ItemID.ItemIdentifierValue == "" {
+		resp.Problem = []ncip.Problem{{ProblemType: ncip.NeededDataMissing, ProblemElement: "ItemId"}}
+		return resp
+	}
+	copy, err := h.copies.GetCopyByBarcode(req.ItemID.ItemIdentifierValue)
+	if err != nil {
+		resp.Problem = []ncip.Problem{{ProblemType: ncip.UnknownItem, ProblemElement: "ItemIdentifierValue", ProblemValue: req.ItemID.ItemIdentifierValue}}
+		return resp
+	}
+	if copy.Withdrawn() {
+		resp.Problem = []ncip.Problem{{ProblemType: ncip.ItemDoesNotCirculate, ProblemDetail: "copy is withdrawn"}}
+		return resp
+	}
+
+	loan, err := h.loans.Checkout(member.ID, copy.BookID)
+	switch {
+	case errors.Is(err, usecase.ErrLoanLimitReached):
+		problem = &ncip.Problem{ProblemType: ncip.MaximumCheckOutsExceeded, ProblemDetail: err.Error()}
+	case errors.Is(err, usecase.ErrBookOnLoan), errors.Is(err, usecase.ErrBookOnHold):
+		problem = &ncip.Problem{ProblemType: ncip.ResourceCannotBeProvided, ProblemDetail: err.Error()}
+	case err != nil:
+		problem = &ncip.Problem{ProblemType: ncip.UnknownItem, ProblemDetail: err.Error()}
+	}
+	if problem != nil {
+		resp.Problem = []ncip.Problem{*problem}
+		return resp
+	}
+
+	resp.ItemID = req.ItemID
+	resp.UserID = &ncip.UserID{AgencyID: h.agencyID, UserIdentifierValue: member.CardNumber}
+	resp.DateDue = &loan.DueAt
+	return resp
+}
+
+// authenticate checks that the request comes from a partner with the
+// right secret and is meant for this library, and addresses the
+// response back to the partner.
+func (h *NCIPHandler) authenticate(header ncip.InitiationHeader) (ncip.ResponseHeader, *ncip.Problem) {
+	from := header.FromAgencyID.AgencyID
+	resp := ncip.ResponseHeader{
+		FromAgencyID: ncip.AgencyRef{AgencyID: h.agencyID},
+		ToAgencyID:   ncip.AgencyRef{AgencyID: from},
+	}
+	if to := header.ToAgencyID.AgencyID; to != "" && to != h.agencyID {
+		return resp, &ncip.Problem{ProblemType: ncip.UnknownAgency, ProblemElement: "ToAgencyId", ProblemValue: to}
+	}
+	secret, ok := h.partners[from]
+	if !ok {
+		return resp, &ncip.Problem{ProblemType: ncip.UnknownAgency, ProblemElement: "FromAgencyId", ProblemValue: from}
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(header.FromAgencyAuthentication)) != 1 {
+		return resp, &ncip.Problem{ProblemType: ncip.AgencyAuthenticationFailed, ProblemElement: "FromAgencyAuthentication"}
+	}
+	return resp, nil
+}
+
+// user resolves the member by card number, from UserId or a Barcode Id
+// input. When a PIN or password input is present it must match the
+// account's password, with the same lockout as web logins.
+func (h *NCIPHandler) user(c *gin.Context, id *ncip.UserID, inputs []ncip.AuthenticationInput) (domain.Member, *ncip.Problem) {
+	card, password, hasPassword := "", "", false
+	if id != nil {
+		card = id.UserIdentifierValue
+	}
+	for _, in := range inputs {
+		switch in.AuthenticationInputType {
+		case ncip.BarcodeID, ncip.UserIDKey:
+			card = in.AuthenticationInputData
+		case ncip.PIN, ncip.Password:
+			password, hasPassword = in.AuthenticationInputData, true
+		}
+	}
+	if card == "" {
+		return domain.Member{}, &ncip.Problem{ProblemType: ncip.NeededDataMissing, ProblemElement: "UserId"}
+	}
+
+	if !hasPassword {
+		member, err := h.members.GetMemberByCard(card)
+		if err != nil {
+			return domain.Member{}, &ncip.Problem{ProblemType: ncip.UnknownUser, ProblemElement: "UserIdentifierValue", ProblemValue: card}
+		}
+		return member, nil
+	}
+	if err := h.guard.Reserve(card, c.ClientIP()); err != nil {
+		return domain.Member{}, &ncip.Problem{ProblemType: ncip.UserAuthenticationFailed, ProblemDetail: err.Error()}
+	}
+	member, err := h.members.Authenticate(card, password)
+	if err != nil {
+		h.guard.RecordFailure(card, c.ClientIP())
+		return domain.Member{}, &ncip.Problem{ProblemType: ncip.UserAuthenticationFailed, ProblemElement: "AuthenticationInput"}
+	}
+	h.guard.RecordSuccess(card, c.ClientIP())
+	return member, nil
+}
+
+// title resolves the book requested: by an item's barcode, an ISBN, or
+// this library's record ID.
+func (h *NCIPHandler) title(item *ncip.ItemID, bib *ncip.BibliographicID) (int, *ncip.Problem) {
+	switch {
+	case item != nil && item.ItemIdentifierValue != "":
+		if copy, err := h.copies.GetCopyByBarcode(item.ItemIdentifierValue); err == nil {
+			return copy.BookID, nil
+		}
+		return 0, &ncip.Problem{ProblemType: ncip.UnknownItem, ProblemElement: "ItemIdentifierValue", ProblemValue: item.ItemIdentifierValue}
+	case bib != nil && bib.BibliographicRecordID != nil:
+		value := bib.BibliographicRecordID.BibliographicRecordIdentifier
+		if id, err := strconv.Atoi(value); err == nil {
+			if _, err := h.books.GetBookByID(id); err == nil {
+				return id, nil
+			}
+		}
+		return 0, &ncip.Problem{ProblemType: ncip.UnknownItem, ProblemElement: "BibliographicRecordIdentifier", ProblemValue: value}
+	case bib != nil && bib.BibliographicItemID != nil:
+		value := bib.BibliographicItemID.BibliographicItemIdentifier
+		if code := bib.BibliographicItemID.BibliographicItemIdentifierCode; code != "" && code != ncip.ItemIdentifierCodeISBN {
+			return 0, &ncip.Problem{ProblemType: ncip.UnknownValueFromKnownScheme, ProblemElement: "BibliographicItemIdentifierCode", ProblemValue: code}
+		}
+		if book, ok := h.books.BookByISBN(value); ok {
+			return book.ID, nil
+		}
+		return 0, &ncip.Problem{ProblemType: ncip.UnknownItem, ProblemElement: "BibliographicItemIdentifier", ProblemValue: value}
+	}
+	return 0, &ncip.Problem{ProblemType: ncip.NeededDataMissing, ProblemElement: "ItemId"}
+}
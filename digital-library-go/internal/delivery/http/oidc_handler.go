@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	uc *usecase.OIDCUsecase
+}
+
+func NewOIDCHandler(uc *usecase.OIDCUsecase) *OIDCHandler {
+	return &OIDCHandler{uc: uc}
+}
+
+// GetProviders godoc
+// @Summary List external login providers
+// @Description Get the names of the configured OpenID Connect providers
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} string
+// @Router /auth/providers [get]
+func (h *OIDCHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Providers()})
+}
+
+// Login godoc
+// @Summary Start an external login
+// @Description Redirect to the OpenID Connect provider's login page
+// @Tags Auth
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	url, err := h.uc.BeginLogin(c.Request.Context(), c.Param("provider"))
+	if errors.Is(err, usecase.ErrUnknownProvider) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// Callback godoc
+// @Summary Complete an external login
+// @Description Handle the provider redirect, link or provision the member and issue a bearer token. Staff accounts get a challenge to answer at /auth/2fa/verify instead, and cannot log in this way without two-factor authentication. Verified emails are never linked to staff accounts.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param state query string true "Login state"
+// @Param code query string true "Authorization code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	if msg := c.Query("error"); msg != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": msg})
+		return
+	}
+
+	res, member, err := h.uc.CompleteLogin(c.Request.Context(), c.Request.Host, c.Param("provider"), c.Query("state"), c.Query("code"))
+	if errors.Is(err, usecase.ErrUnknownProvider) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidState) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrStaffNeedsTwoFactor) || errors.Is(err, usecase.ErrStaffEmailLink) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if res.Challenge != "" {
+		c.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge": res.Challenge})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": res.Token, "data": member})
+}
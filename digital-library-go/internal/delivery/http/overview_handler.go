@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestStats counts every request by route, and remembers those
+// answered with a server error, with the error a handler recorded.
+func RequestStats(uc *usecase.RequestStatsUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		e := domain.RecentError{
+			At:     start,
+			Method: c.Request.Method,
+			Path:   c.Request.URL.Path,
+			Route:  c.FullPath(),
+			Status: c.Writer.Status(),
+		}
+		if err := c.Errors.Last(); err != nil {
+			e.Message = err.Error()
+		}
+		uc.Record(e, time.Since(start))
+	}
+}
+
+type OverviewHandler struct {
+	uc *usecase.OverviewUsecase
+}
+
+func NewOverviewHandler(uc *usecase.OverviewUsecase) *OverviewHandler {
+	return &OverviewHandler{uc: uc}
+}
+
+// GetOverview godoc
+// @Summary Get an overview of the server
+// @Description Get the state of the server for an operations dashboard: version, uptime, maintenance mode, tasks by status and the depth of each task queue, the new book notification queue, cache hit ratios, request and server error totals since start, the busiest routes and the latest server errors. Admin only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param top query int false "Number of busiest routes to list (default 10)"
+// @Success 200 {object} domain.Overview
+// @Failure 400 {object} map[string]string
+// @Router /admin/overview [get]
+func (h *OverviewHandler) GetOverview(c *gin.Context) {
+	top := 10
+	if v := c.Query("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "top must be a positive number"})
+			return
+		}
+		top = n
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Overview(time.Now(), top)})
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PayFinesRequest is the body accepted when a member pays fines. Without
+// fine_ids, all their unpaid fines are paid.
+type PayFinesRequest struct {
+	FineIDs []int `json:"fine_ids"`
+}
+
+// PaymentEventParser reads a payment provider's webhook event, checking
+// its signature.
+type PaymentEventParser interface {
+	ParseEvent(r *http.Request) (domain.PaymentEvent, error)
+}
+
+type PaymentHandler struct {
+	uc     *usecase.PaymentUsecase
+	parser PaymentEventParser
+}
+
+func NewPaymentHandler(uc *usecase.PaymentUsecase, parser PaymentEventParser) *PaymentHandler {
+	return &PaymentHandler{uc: uc, parser: parser}
+}
+
+// PayFines godoc
+// @Summary Pay my fines
+// @Description Start an online payment of the authenticated member's fines, or of all their unpaid fines when fine_ids is empty. The front-end completes it with the payment provider using client_secret; the fines are marked paid once the provider confirms the payment.
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param payment body PayFinesRequest false "Fines to pay"
+// @Success 201 {object} domain.Payment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /me/payments [post]
+func (h *PaymentHandler) PayFines(c *gin.Context) {
+	var req PayFinesRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+			return
+		}
+	}
+
+	payment, err := h.uc.PayFines(c.Request.Context(), currentMemberID(c), req.FineIDs)
+	switch {
+	case err != nil && domain.KindOf(err) == nil:
+		// Errors of no kind come from the payment provider.
+		c.JSON(http.StatusBadGateway, gin.H{"error": "payment provider unavailable"})
+	case err != nil:
+		abort(c, err)
+	default:
+		c.JSON(http.StatusCreated, gin.H{"data": payment})
+	}
+}
+
+// GetMyPayments godoc
+// @Summary Get my payments
+// @Description Get the authenticated member's online payments of fines
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Payment
+// @Router /me/payments [get]
+func (h *PaymentHandler) GetMyPayments(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.PaymentsForMember(currentMemberID(c))})
+}
+
+// GetPayments godoc
+// @Summary Get payments
+// @Description Get every online payment of fines. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Payment
+// @Router /admin/payments [get]
+func (h *PaymentHandler) GetPayments(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetPayments()})
+}
+
+// ReconcilePayments godoc
+// @Summary Reconcile payments
+// @Description Ask the payment provider about every pending payment now, settling those whose webhook events were missed and canceling those left unfinished for a day. This also runs hourly. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.PaymentReconciliation
+// @Router /admin/payments/reconcile [post]
+func (h *PaymentHandler) ReconcilePayments(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Reconcile(c.Request.Context(), time.Now())})
+}
+
+// PaymentWebhook godoc
+// @Summary Payment provider webhook
+// @Description Receives signed events from the payment provider about payments succeeding, failing or being canceled. Events about payments the library did not start are acknowledged and ignored.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /webhooks/payments [post]
+func (h *PaymentHandler) PaymentWebhook(c *gin.Context) {
+	event, err := h.parser.ParseEvent(c.Request)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	// Events about payments that are not ours still get a 2xx, or the
+	// provider would keep retrying them.
+	h.uc.HandleEvent(event)
+	c.JSON(http.StatusOK, gin.H{"message": "event received"})
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlanHandler struct {
+	uc      *usecase.PlanUsecase
+	members *usecase.MemberUsecase
+}
+
+func NewPlanHandler(uc *usecase.PlanUsecase, members *usecase.MemberUsecase) *PlanHandler {
+	return &PlanHandler{uc: uc, members: members}
+}
+
+// GetPlans godoc
+// @Summary Get all membership plans
+// @Description Get list of all membership plans and their limits
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} domain.Plan
+// @Router /admin/plans [get]
+func (h *PlanHandler) GetPlans(c *gin.Context) {
+	plans := h.uc.GetPlans()
+	c.JSON(http.StatusOK, gin.H{"data": plans})
+}
+
+// GetPlanByID godoc
+// @Summary Get a membership plan by ID
+// @Description Get membership plan details by ID
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Success 200 {object} domain.Plan
+// @Failure 404 {object} map[string]string
+// @Router /admin/plans/{id} [get]
+func (h *PlanHandler) GetPlanByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	plan, err := h.uc.GetPlanByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": plan})
+}
+
+// CreatePlan godoc
+// @Summary Create a membership plan
+// @Description Add a membership plan with loan and hold limits
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param plan body domain.Plan true "Plan data"
+// @Success 201 {object} domain.Plan
+// @Failure 400 {object} map[string]string
+// @Router /admin/plans [post]
+func (h *PlanHandler) CreatePlan(c *gin.Context) {
+	var plan domain.Plan
+
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := plan.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.CreatePlan(plan)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdatePlan godoc
+// @Summary Update a membership plan
+// @Description Update membership plan limits by ID
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Param plan body domain.Plan true "Updated plan data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/plans/{id} [put]
+func (h *PlanHandler) UpdatePlan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var plan domain.Plan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := plan.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdatePlan(id, plan)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "plan updated"})
+}
+
+// DeletePlan godoc
+// @Summary Delete a membership plan
+// @Description Delete membership plan by ID. Plans members are on cannot be deleted.
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/plans/{id} [delete]
+func (h *PlanHandler) DeletePlan(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.members.DeletePlan(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "plan deleted"})
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PublisherHandler struct {
+	uc *usecase.EditionUsecase
+}
+
+func NewPublisherHandler(uc *usecase.EditionUsecase) *PublisherHandler {
+	return &PublisherHandler{uc: uc}
+}
+
+// GetPublishers godoc
+// @Summary Get all publishers
+// @Description Get list of all publishers
+// @Tags Editions
+// @Produce json
+// @Success 200 {array} domain.Publisher
+// @Router /publishers [get]
+func (h *PublisherHandler) GetPublishers(c *gin.Context) {
+	publishers := h.uc.GetPublishers()
+	c.JSON(http.StatusOK, gin.H{"data": publishers})
+}
+
+// GetPublisherByID godoc
+// @Summary Get a publisher by ID
+// @Description Get publisher details by ID
+// @Tags Editions
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Success 200 {object} domain.Publisher
+// @Failure 404 {object} map[string]string
+// @Router /publishers/{id} [get]
+func (h *PublisherHandler) GetPublisherByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	publisher, err := h.uc.GetPublisherByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": publisher})
+}
+
+// CreatePublisher godoc
+// @Summary Create a publisher
+// @Description Add a publisher. Librarians only.
+// @Tags Editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param publisher body domain.Publisher true "Publisher data"
+// @Success 201 {object} domain.Publisher
+// @Failure 400 {object} map[string]string
+// @Router /publishers [post]
+func (h *PublisherHandler) CreatePublisher(c *gin.Context) {
+	var publisher domain.Publisher
+
+	if err := c.ShouldBindJSON(&publisher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := publisher.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreatePublisher(publisher)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdatePublisher godoc
+// @Summary Update a publisher
+// @Description Update publisher details by ID. Librarians only.
+// @Tags Editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Publisher ID"
+// @Param publisher body domain.Publisher true "Updated publisher data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /publishers/{id} [put]
+func (h *PublisherHandler) UpdatePublisher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var publisher domain.Publisher
+	if err := c.ShouldBindJSON(&publisher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := publisher.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdatePublisher(id, publisher)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "publisher updated"})
+}
+
+// DeletePublisher godoc
+// @Summary Delete a publisher
+// @Description Delete a publisher that has no editions. Librarians only.
+// @Tags Editions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Publisher ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /publishers/{id} [delete]
+func (h *PublisherHandler) DeletePublisher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeletePublisher(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "publisher deleted"})
+}
+
+// GetPublisherEditions godoc
+// @Summary Get a publisher's editions
+// @Description Get every edition a publisher has published, oldest first
+// @Tags Editions
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Success 200 {array} domain.Edition
+// @Failure 404 {object} map[string]string
+// @Router /publishers/{id}/editions [get]
+func (h *PublisherHandler) GetPublisherEditions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	editions, err := h.uc.EditionsByPublisher(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": editions})
+}
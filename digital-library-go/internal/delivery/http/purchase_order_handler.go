@@ -0,0 +1,242 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceiveOrderRequest says where the copies of a received order are
+// shelved until they are processed.
+type ReceiveOrderRequest struct {
+	Floor   string `json:"floor"`
+	Section string `json:"section"`
+	Shelf   string `json:"shelf"`
+}
+
+type PurchaseOrderHandler struct {
+	uc *usecase.PurchasingUsecase
+}
+
+func NewPurchaseOrderHandler(uc *usecase.PurchasingUsecase) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{uc: uc}
+}
+
+// GetPurchaseOrders godoc
+// @Summary Get purchase orders
+// @Description Get all purchase orders, or those with a status. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "draft, sent or received"
+// @Success 200 {array} domain.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrders(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", domain.OrderDraft, domain.OrderSent, domain.OrderReceived:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be draft, sent or received"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetOrders(status)})
+}
+
+// GetPurchaseOrderByID godoc
+// @Summary Get a purchase order by ID
+// @Description Get a purchase order with its items. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} domain.PurchaseOrder
+// @Failure 404 {object} map[string]string
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrderByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	order, err := h.uc.GetOrderByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create a purchase order
+// @Description Draft an order from a vendor. Each item orders copies of a book in the catalog; titles not yet cataloged can be added as draft books first. Librarians only.
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param order body domain.PurchaseOrder true "Purchase order data"
+// @Success 201 {object} domain.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	var order domain.PurchaseOrder
+
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := order.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.CreateOrder(order, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update a purchase order
+// @Description Replace the vendor, fund and items of a draft order. Librarians only.
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Param order body domain.PurchaseOrder true "Updated purchase order data"
+// @Success 200 {object} domain.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var order domain.PurchaseOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := order.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.uc.UpdateOrder(id, order)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// DeletePurchaseOrder godoc
+// @Summary Delete a purchase order
+// @Description Discard a draft order. Orders that were sent stay on record. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id} [delete]
+func (h *PurchaseOrderHandler) DeletePurchaseOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteOrder(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "purchase order deleted"})
+}
+
+// SendPurchaseOrder godoc
+// @Summary Send a purchase order
+// @Description Mark a draft order with at least one item as sent to its vendor. It can no longer be changed. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} domain.PurchaseOrder
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	order, err := h.uc.Send(id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Receive a purchase order
+// @Description Mark a sent order as received and add a copy for every item ordered, shelved at the given location, with its price, vendor and fund. Copies get provisional barcodes PO<order>-<item>-<n>. Librarians only.
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Param location body ReceiveOrderRequest true "Where the new copies are shelved"
+// @Success 200 {object} domain.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ReceiveOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	shelf := domain.Copy{Floor: req.Floor, Section: req.Section, Shelf: req.Shelf}
+	order, err := h.uc.Receive(id, shelf, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VAPIDKey is the public key browsers subscribe with.
+type VAPIDKey struct {
+	PublicKey string `json:"public_key"`
+}
+
+type PushHandler struct {
+	uc        *usecase.PushUsecase
+	publicKey string
+}
+
+func NewPushHandler(uc *usecase.PushUsecase, publicKey string) *PushHandler {
+	return &PushHandler{uc: uc, publicKey: publicKey}
+}
+
+// GetVAPIDKey godoc
+// @Summary Get the VAPID public key
+// @Description Get the applicationServerKey to pass to pushManager.subscribe() in the browser
+// @Tags Me
+// @Produce json
+// @Success 200 {object} VAPIDKey
+// @Router /push/vapid-public-key [get]
+func (h *PushHandler) GetVAPIDKey(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": VAPIDKey{PublicKey: h.publicKey}})
+}
+
+// GetPushSubscriptions godoc
+// @Summary Get my push subscriptions
+// @Description Get the browsers the authenticated member receives push notifications in
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.PushSubscription
+// @Router /me/push-subscriptions [get]
+func (h *PushHandler) GetPushSubscriptions(c *gin.Context) {
+	subs := h.uc.SubscriptionsForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": subs})
+}
+
+// CreatePushSubscription godoc
+// @Summary Subscribe a browser to push notifications
+// @Description Register the JSON of a browser PushSubscription. Notifications reach it for the kinds where the member chose the push channel.
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscription body domain.PushSubscription true "Browser push subscription"
+// @Success 201 {object} domain.PushSubscription
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /me/push-subscriptions [post]
+func (h *PushHandler) CreatePushSubscription(c *gin.Context) {
+	var sub domain.PushSubscription
+
+	if err := c.ShouldBindJSON(&sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := sub.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.Subscribe(currentMemberID(c), sub)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// DeletePushSubscription godoc
+// @Summary Unsubscribe a browser from push notifications
+// @Description Stop sending push notifications to one of the authenticated member's browsers
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Push subscription ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/push-subscriptions/{id} [delete]
+func (h *PushHandler) DeletePushSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.Unsubscribe(currentMemberID(c), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "push subscription deleted"})
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/escpos"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/pdf"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceiptHandler prints receipts and slips through the receipt
+// templates of the tenant the request was sent to.
+type ReceiptHandler struct {
+	templates *usecase.TemplateUsecase
+	loans     *usecase.LoanUsecase
+	holds     *usecase.HoldUsecase
+	books     *usecase.BookUsecase
+	members   *usecase.MemberUsecase
+}
+
+func NewReceiptHandler(templates *usecase.TemplateUsecase, loans *usecase.LoanUsecase, holds *usecase.HoldUsecase, books *usecase.BookUsecase, members *usecase.MemberUsecase) *ReceiptHandler {
+	return &ReceiptHandler{templates: templates, loans: loans, holds: holds, books: books, members: members}
+}
+
+// GetLoanReceipt godoc
+// @Summary Print a checkout receipt
+// @Description Get the receipt for a checkout, listing the loan with the member's other loans from the same visit and their due dates. format is pdf (the default), escpos for a thermal receipt printer, text or html. Librarians only.
+// @Tags Loans
+// @Produce application/pdf
+// @Produce application/octet-stream
+// @Produce plain
+// @Produce html
+// @Security BearerAuth
+// @Param id path int true "Loan ID"
+// @Param format query string false "pdf, escpos, text or html"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /loans/{id}/receipt [get]
+func (h *ReceiptHandler) GetLoanReceipt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	format := c.DefaultQuery("format", "pdf")
+	if !slices.Contains([]string{"pdf", "escpos", "text", "html"}, format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be pdf, escpos, text or html"})
+		return
+	}
+
+	loans, err := h.loans.VisitLoans(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	member, _ := h.members.GetMemberByID(loans[0].MemberID)
+	data := domain.ReceiptTemplateData{
+		Library:   h.templates.Library(),
+		Name:      member.Name,
+		Card:      cardEnding(member.CardNumber),
+		Items:     []domain.ReceiptItem{},
+		PrintedAt: time.Now(),
+	}
+	for _, loan := range loans {
+		book, _ := h.books.GetBookByID(loan.BookID)
+		data.Items = append(data.Items, domain.ReceiptItem{Title: book.Title, Author: book.Author, DueAt: loan.DueAt})
+	}
+
+	receipt, err := h.templates.Render(c.Request.Host, domain.TemplateReceipt, domain.ReceiptCheckout, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	lines := strings.Split(strings.TrimRight(receipt.Text, "\n"), "\n")
+	switch format {
+	case "pdf":
+		doc := pdf.New("Checkout receipt")
+		for _, line := range lines {
+			doc.Text(line)
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%d.pdf"`, id))
+		c.Data(http.StatusOK, "application/pdf", doc.Bytes())
+	case "escpos":
+		doc := escpos.New("")
+		for _, line := range lines {
+			doc.Text(line)
+		}
+		c.Data(http.StatusOK, "application/octet-stream", doc.Bytes())
+	default:
+		writeReceipt(c, format, receipt)
+	}
+}
+
+// GetHoldSlip godoc
+// @Summary Print a hold slip
+// @Description Get the slip to put in a book waiting on the hold shelf, as plain text or, with format=html, as a page to print. Librarians only.
+// @Tags Holds
+// @Produce plain
+// @Produce html
+// @Security BearerAuth
+// @Param id path int true "Hold ID"
+// @Param format query string false "text (the default) or html"
+// @Success 200 {string} string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /holds/{id}/slip [get]
+func (h *ReceiptHandler) GetHoldSlip(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	format := c.DefaultQuery("format", "text")
+	if format != "text" && format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be text or html"})
+		return
+	}
+
+	hold, err := h.holds.GetHoldByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if hold.Status != domain.HoldReady {
+		c.JSON(http.StatusConflict, gin.H{"error": "hold is not ready for pickup"})
+		return
+	}
+	book, _ := h.books.GetBookByID(hold.BookID)
+	member, _ := h.members.GetMemberByID(hold.MemberID)
+
+	slip, err := h.templates.Render(c.Request.Host, domain.TemplateReceipt, domain.ReceiptHoldSlip, domain.HoldSlipTemplateData{
+		Library:   h.templates.Library(),
+		Name:      member.Name,
+		Card:      cardEnding(member.CardNumber),
+		Title:     book.Title,
+		Author:    book.Author,
+		PickupBy:  *hold.ExpiresAt,
+		PrintedAt: time.Now(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeReceipt(c, format, slip)
+}
+
+// writeReceipt answers with the text or HTML version of a receipt.
+// Templates without an HTML version are shown as preformatted text.
+func writeReceipt(c *gin.Context, format string, receipt domain.RenderedMessage) {
+	if format != "html" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(receipt.Text))
+		return
+	}
+	html := receipt.HTML
+	if html == "" {
+		html = "<pre>" + template.HTMLEscapeString(receipt.Text) + "</pre>"
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+}
+
+// cardEnding is the last four characters of a card number, which is all
+// printed slips show.
+func cardEnding(card string) string {
+	if len(card) <= 4 {
+		return card
+	}
+	return card[len(card)-4:]
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RelatedHandler struct {
+	uc     *usecase.RelatedUsecase
+	books  *usecase.BookUsecase
+	policy *usecase.ContentPolicyUsecase
+}
+
+func NewRelatedHandler(uc *usecase.RelatedUsecase, books *usecase.BookUsecase, policy *usecase.ContentPolicyUsecase) *RelatedHandler {
+	return &RelatedHandler{uc: uc, books: books, policy: policy}
+}
+
+// GetRelatedBooks godoc
+// @Summary Get related books
+// @Description Get the books most often borrowed by members who also borrowed this one ("readers also borrowed"). A book is only listed once enough readers borrowed both.
+// @Tags Library
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param limit query int false "Maximum books (default 10, max 50)"
+// @Param audience query string false "all or children"
+// @Success 200 {array} domain.RelatedBook
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/related [get]
+func (h *RelatedHandler) GetRelatedBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	limit := 10
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+			return
+		}
+		limit = n
+	}
+
+	audience := audienceOf(c)
+	book, err := h.books.GetBookByID(id)
+	if err != nil || !h.policy.Allows(book, audience) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+		return
+	}
+
+	related := slices.DeleteFunc(h.uc.Related(id), func(r domain.RelatedBook) bool {
+		return !h.policy.Allows(r.Book, audience)
+	})
+	c.JSON(http.StatusOK, gin.H{"data": related[:min(limit, len(related))]})
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorReporter sends error reports to an error tracker such as Sentry,
+// returning the ID the tracker gave the report.
+type ErrorReporter interface {
+	Report(ctx context.Context, report domain.ErrorReport) (string, error)
+}
+
+// Reporting reports requests that panic or are answered with a server
+// error, with the error and the request it happened in. Reports are sent
+// in the background, so they do not hold up the response. Panics are
+// passed on for gin.Recovery to answer, so Reporting goes right after it.
+// 503s are not reported: the server answers them on purpose, in
+// maintenance mode or when a queue is full.
+func Reporting(reporter ErrorReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// A handler aborts a response the client went away from with
+			// this panic; it is not a bug.
+			if v != http.ErrAbortHandler {
+				report := newErrorReport(c, domain.ReportFatal, http.StatusInternalServerError)
+				report.Message = fmt.Sprint(v)
+				report.Type = fmt.Sprintf("%T", v)
+				report.Stack = panicStack()
+				sendReport(reporter, report)
+			}
+			panic(v)
+		}()
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError || status == http.StatusServiceUnavailable {
+			return
+		}
+		report := newErrorReport(c, domain.ReportError, status)
+		if err := c.Errors.Last(); err != nil {
+			report.Message = err.Error()
+			report.Type = fmt.Sprintf("%T", err.Err)
+		} else {
+			report.Message = fmt.Sprintf("%s %s answered %d", c.Request.Method, c.Request.URL.Path, status)
+			report.Type = http.StatusText(status)
+		}
+		sendReport(reporter, report)
+	}
+}
+
+// newErrorReport describes the request of c. The query string is left
+// out, since it may hold tokens.
+func newErrorReport(c *gin.Context, level string, status int) domain.ErrorReport {
+	return domain.ErrorReport{
+		Level:     level,
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Route:     c.FullPath(),
+		Status:    status,
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+		MemberID:  currentMemberID(c),
+		Time:      time.Now(),
+	}
+}
+
+func sendReport(reporter ErrorReporter, report domain.ErrorReport) {
+	go func() {
+		if _, err := reporter.Report(context.Background(), report); err != nil {
+			log.Printf("error report for %s %s failed: %v", report.Method, report.Path, err)
+		}
+	}()
+}
+
+// panicStack returns the stack of a panic being recovered, innermost
+// call first, from where it panicked; the runtime's own frames are left
+// out.
+func panicStack() []domain.StackFrame {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	stack := []domain.StackFrame{}
+	for {
+		f, more := frames.Next()
+		switch {
+		case f.Function == "runtime.gopanic":
+			// Frames so far are of the deferred function recovering.
+			stack = stack[:0]
+		case !strings.HasPrefix(f.Function, "runtime."):
+			stack = append(stack, domain.StackFrame{Function: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			return stack
+		}
+	}
+}
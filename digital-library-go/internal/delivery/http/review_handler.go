@@ -0,0 +1,298 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportRequest is the body accepted when a member reports a review.
+type ReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ReviewHandler struct {
+	uc      *usecase.ReviewUsecase
+	members *usecase.MemberUsecase
+}
+
+func NewReviewHandler(uc *usecase.ReviewUsecase, members *usecase.MemberUsecase) *ReviewHandler {
+	return &ReviewHandler{uc: uc, members: members}
+}
+
+// GetBookReviews godoc
+// @Summary Get a book's reviews
+// @Description Get the published reviews of a book, newest first
+// @Tags Reviews
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} domain.Review
+// @Router /books/{id}/reviews [get]
+func (h *ReviewHandler) GetBookReviews(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.ReviewsForBook(id)})
+}
+
+// CreateReview godoc
+// @Summary Review a book
+// @Description Rate a book from 1 to 5 and say why. The review is published unless moderation holds it back for approval; its status says which.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param review body domain.Review true "Rating and text"
+// @Success 201 {object} domain.Review
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /books/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var review domain.Review
+	if err := c.ShouldBindJSON(&review); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := review.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.uc.CreateReview(currentMemberID(c), id, review)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// GetMyReviews godoc
+// @Summary Get my reviews
+// @Description Get the authenticated member's reviews, including those awaiting moderation or rejected
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Review
+// @Router /me/reviews [get]
+func (h *ReviewHandler) GetMyReviews(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.ReviewsForMember(currentMemberID(c))})
+}
+
+// DeleteReview godoc
+// @Summary Delete a review
+// @Description Delete one of the authenticated member's reviews. Staff may delete any review.
+// @Tags Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	review, err := h.uc.GetReviewByID(id)
+	if err != nil || !h.canManage(c, review) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+		return
+	}
+
+	if err := h.uc.DeleteReview(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "review deleted"})
+}
+
+// ReportReview godoc
+// @Summary Report a review
+// @Description Flag a published review as abusive or spam. After enough reports it is hidden until a moderator looks at it.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Param report body ReportRequest false "Why the review is a problem"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /reviews/{id}/report [post]
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req ReportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+			return
+		}
+	}
+
+	_, err = h.uc.Report(id, currentMemberID(c), req.Reason)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "review reported"})
+}
+
+// GetModerationQueue godoc
+// @Summary Get the review moderation queue
+// @Description Get the reviews awaiting approval, oldest first, with the reasons they were held back. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Review
+// @Router /admin/reviews/queue [get]
+func (h *ReviewHandler) GetModerationQueue(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Queue()})
+}
+
+// GetReviewReports godoc
+// @Summary Get the reports about a review
+// @Description Get who reported a review and why. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {array} domain.ReviewReport
+// @Failure 404 {object} map[string]string
+// @Router /admin/reviews/{id}/reports [get]
+func (h *ReviewHandler) GetReviewReports(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	reports, err := h.uc.ReportsForReview(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reports})
+}
+
+// ApproveReview godoc
+// @Summary Approve a review
+// @Description Publish a review from the moderation queue and clear its reports. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} domain.Review
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/reviews/{id}/approve [post]
+func (h *ReviewHandler) ApproveReview(c *gin.Context) {
+	h.moderate(c, h.uc.Approve)
+}
+
+// RejectReview godoc
+// @Summary Reject a review
+// @Description Keep a review from the moderation queue unpublished. Its author is notified. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} domain.Review
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/reviews/{id}/reject [post]
+func (h *ReviewHandler) RejectReview(c *gin.Context) {
+	h.moderate(c, h.uc.Reject)
+}
+
+func (h *ReviewHandler) moderate(c *gin.Context, decide func(int) (domain.Review, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	review, err := decide(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": review})
+}
+
+// GetModerationSettings godoc
+// @Summary Get the review moderation settings
+// @Description Get the banned words and other rules that hold reviews back for approval. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.ModerationSettings
+// @Router /admin/moderation [get]
+func (h *ReviewHandler) GetModerationSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetSettings()})
+}
+
+// UpdateModerationSettings godoc
+// @Summary Update the review moderation settings
+// @Description Replace the banned words and other moderation rules. They apply to reviews written from now on. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param settings body domain.ModerationSettings true "Moderation settings"
+// @Success 200 {object} domain.ModerationSettings
+// @Failure 400 {object} map[string]string
+// @Router /admin/moderation [put]
+func (h *ReviewHandler) UpdateModerationSettings(c *gin.Context) {
+	var settings domain.ModerationSettings
+
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := settings.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.uc.SetSettings(settings)
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetSettings()})
+}
+
+// canManage reports whether the authenticated member wrote the review or
+// is staff.
+func (h *ReviewHandler) canManage(c *gin.Context, review domain.Review) bool {
+	if review.MemberID == currentMemberID(c) {
+		return true
+	}
+	member, err := h.members.GetMemberByID(currentMemberID(c))
+	return err == nil && member.IsStaff()
+}
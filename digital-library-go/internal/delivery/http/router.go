@@ -1,13 +1,675 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
 
-func RegisterRoutes(r *gin.Engine, h *BookHandler, taskRunning *bool) {
-	taskHandler := NewTaskHandler(taskRunning)
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.Engine, h *BookHandler, taskHandler *TaskHandler) {
 	r.GET("/books", h.GetBooks)
+	r.GET("/books/stream", h.StreamBooks)
+	r.GET("/books/changes", h.GetBookChanges)
 	r.GET("/books/:id", h.GetBookByID)
 	r.POST("/books", h.CreateBook)
 	r.PUT("/books/:id", h.UpdateBook)
 	r.DELETE("/books/:id", h.DeleteBook)
 	r.POST("/tasks/process", taskHandler.RunHeavyTask)
 }
+
+// RegisterFastAPIRoutes mounts the same routes as RegisterRoutes, in its
+// place, but with the routes of the FastAPI service answered as it did.
+// Unknown paths and methods are answered as FastAPI did too.
+func RegisterFastAPIRoutes(r *gin.Engine, h *BookHandler, fh *FastAPIHandler, taskHandler *TaskHandler) {
+	r.GET("/", fh.Root)
+	r.GET("/books", fh.GetBooks)
+	r.GET("/books/stream", h.StreamBooks)
+	r.GET("/books/changes", h.GetBookChanges)
+	r.GET("/books/:id", fh.GetBook)
+	r.POST("/books", fh.CreateBook)
+	r.PUT("/books/:id", fh.UpdateBook)
+	r.DELETE("/books/:id", fh.DeleteBook)
+	r.POST("/tasks/process", taskHandler.RunHeavyTask)
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(fh.NotFound)
+	r.NoMethod(fh.MethodNotAllowed)
+}
+
+// RegisterMemberRoutes mounts member administration for librarians and
+// admins. Members manage their own account under /me.
+func RegisterMemberRoutes(r *gin.Engine, ah *AuthHandler, h *MemberHandler) {
+	members := r.Group("/members", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	members.GET("", h.GetMembers)
+	members.GET("/:id", h.GetMemberByID)
+	members.POST("", h.CreateMember)
+	members.PUT("/:id", h.UpdateMember)
+	members.DELETE("/:id", h.DeleteMember)
+	members.GET("/card/:number", h.GetMemberByCard)
+	members.POST("/:id/card", h.ReplaceCard)
+}
+
+// RegisterCirculationRoutes mounts the circulation desk for librarians and
+// admins, and hold placement for members under /me.
+func RegisterCirculationRoutes(r *gin.Engine, ah *AuthHandler, fh *FeatureFlagHandler, lh *LoanHandler, hh *HoldHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.GET("/loans", staff, lh.GetLoans)
+	r.GET("/loans/:id", staff, lh.GetLoanByID)
+	r.POST("/loans", staff, lh.Checkout)
+	r.POST("/loans/:id/return", staff, lh.ReturnLoan)
+	r.GET("/holds", staff, hh.GetHolds)
+	r.POST("/holds", staff, fh.Require(featureflag.Reservations), hh.PlaceHold)
+	r.DELETE("/holds/:id", staff, hh.CancelHold)
+	r.POST("/me/holds", ah.RequireMember(), fh.Require(featureflag.Reservations), hh.PlaceMyHold)
+}
+
+// RegisterAdminRoutes mounts the /admin group, which requires an admin
+// session.
+func RegisterAdminRoutes(r *gin.Engine, ah *AuthHandler, ph *PlanHandler, mh *MemberHandler, th *TwoFactorHandler, sh *SecurityHandler) {
+	admin := r.Group("/admin", ah.RequireRole(domain.RoleAdmin))
+	admin.GET("/plans", ph.GetPlans)
+	admin.GET("/plans/:id", ph.GetPlanByID)
+	admin.POST("/plans", ph.CreatePlan)
+	admin.PUT("/plans/:id", ph.UpdatePlan)
+	admin.DELETE("/plans/:id", ph.DeletePlan)
+	admin.PUT("/members/:id/role", mh.SetRole)
+	admin.POST("/members/:id/2fa/reset", th.Reset)
+	admin.GET("/lockouts", sh.GetLockouts)
+	admin.DELETE("/lockouts/:kind/:key", sh.ClearLockout)
+	admin.GET("/audit", sh.GetAuditEvents)
+}
+
+func RegisterMeRoutes(r *gin.Engine, ah *AuthHandler, oh *OIDCHandler, th *TwoFactorHandler, mh *MeHandler, acc *AccountHandler) {
+	r.POST("/auth/login", ah.Login)
+	r.POST("/auth/logout", ah.Logout)
+	r.POST("/auth/2fa/enroll", ah.RequireMember(), th.Enroll)
+	r.POST("/auth/2fa/confirm", ah.RequireMember(), th.Confirm)
+	r.POST("/auth/2fa/verify", th.Verify)
+	r.GET("/auth/providers", oh.GetProviders)
+	r.GET("/auth/oidc/:provider/login", oh.Login)
+	r.GET("/auth/oidc/:provider/callback", oh.Callback)
+	r.GET("/members/:id/profile", mh.GetPublicProfile)
+
+	me := r.Group("/me", ah.RequireMember())
+	me.GET("", mh.GetProfile)
+	me.PUT("", mh.UpdateProfile)
+	me.PUT("/privacy", mh.UpdatePrivacy)
+	me.GET("/loans", mh.GetLoans)
+	me.GET("/holds", mh.GetHolds)
+	me.DELETE("/holds/:id", mh.CancelHold)
+	me.GET("/fines", mh.GetFines)
+	me.GET("/lists", mh.GetReadingLists)
+	me.POST("/lists", mh.CreateReadingList)
+	me.POST("/lists/:id/books", mh.AddToReadingList)
+	me.DELETE("/lists/:id", mh.DeleteReadingList)
+	me.GET("/export", acc.ExportData)
+	me.DELETE("", acc.DeleteAccount)
+	me.POST("/restore", acc.RestoreAccount)
+}
+
+// RegisterAPIKeyRoutes wires API key management under /me and the usage
+// endpoint for integrations. Metering itself is the Meter middleware,
+// which must be installed before any routes.
+func RegisterAPIKeyRoutes(r *gin.Engine, ah *AuthHandler, kh *APIKeyHandler) {
+	r.GET("/usage", kh.GetUsage)
+
+	keys := r.Group("/me/api-keys", ah.RequireMember())
+	keys.GET("", kh.GetKeys)
+	keys.POST("", kh.CreateKey)
+	keys.DELETE("/:id", kh.RevokeKey)
+	keys.GET("/:id/usage", kh.GetKeyUsage)
+}
+
+func RegisterHealthRoutes(r *gin.Engine, h *HealthHandler) {
+	r.GET("/healthz", h.Live)
+	r.GET("/readyz", h.Ready)
+}
+
+func RegisterVersionRoutes(r *gin.Engine, h *VersionHandler) {
+	r.GET("/version", h.GetVersion)
+}
+
+func RegisterDebugRoutes(r *gin.Engine, ah *AuthHandler, h *DebugHandler) {
+	debug := r.Group("/debug", ah.RequireRole(domain.RoleAdmin))
+	debug.GET("/pprof/*name", h.Pprof)
+	debug.POST("/pprof/*name", h.Pprof)
+	debug.GET("/vars", h.Vars)
+	debug.GET("/goroutines", h.GetGoroutines)
+	debug.POST("/heap-snapshots", h.TakeHeapSnapshot)
+	debug.GET("/heap-snapshots/:file", h.GetHeapSnapshot)
+}
+
+func RegisterSLORoutes(r *gin.Engine, h *SLOHandler) {
+	r.GET("/slo", h.GetStatus)
+	r.GET("/slo/metrics", h.GetMetrics)
+}
+
+// RegisterMetadataRoutes wires the catalog-wide metadata refresh for
+// librarians, since it starts requests to external catalogs.
+func RegisterMetadataRoutes(r *gin.Engine, ah *AuthHandler, h *MetadataRefreshHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/tasks/refresh-metadata", staff, h.StartRefresh)
+	r.GET("/tasks/refresh-metadata", staff, h.GetRefreshReport)
+}
+
+func RegisterAuthorRoutes(r *gin.Engine, ah *AuthHandler, h *AuthorHandler) {
+	r.GET("/authors", h.GetAuthors)
+	r.GET("/authors/:id", h.GetAuthorByID)
+	r.GET("/authors/:id/books", h.GetAuthorBooks)
+	r.POST("/admin/authors/migrate", ah.RequireRole(domain.RoleAdmin), h.MigrateAuthors)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/authors", staff, h.CreateAuthor)
+	r.PUT("/authors/:id", staff, h.UpdateAuthor)
+	r.DELETE("/authors/:id", staff, h.DeleteAuthor)
+	r.GET("/authors/duplicates", staff, h.GetAuthorDuplicates)
+	r.POST("/authors/:id/merge", staff, h.MergeAuthors)
+}
+
+func RegisterEditionRoutes(r *gin.Engine, ah *AuthHandler, ph *PublisherHandler, eh *EditionHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.GET("/publishers", ph.GetPublishers)
+	r.GET("/publishers/:id", ph.GetPublisherByID)
+	r.GET("/publishers/:id/editions", ph.GetPublisherEditions)
+	r.POST("/publishers", staff, ph.CreatePublisher)
+	r.PUT("/publishers/:id", staff, ph.UpdatePublisher)
+	r.DELETE("/publishers/:id", staff, ph.DeletePublisher)
+
+	r.GET("/editions", eh.GetEditions)
+	r.GET("/editions/:id", eh.GetEditionByID)
+	r.POST("/editions", staff, eh.CreateEdition)
+	r.PUT("/editions/:id", staff, eh.UpdateEdition)
+	r.DELETE("/editions/:id", staff, eh.DeleteEdition)
+	r.GET("/books/:id/editions", eh.GetBookEditions)
+}
+
+func RegisterSeriesRoutes(r *gin.Engine, ah *AuthHandler, h *SeriesHandler) {
+	r.GET("/series", h.GetSeries)
+	r.GET("/series/:id", h.GetSeriesByID)
+	r.GET("/series/:id/books", h.GetSeriesBooks)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/series", staff, h.CreateSeries)
+	r.PUT("/series/:id", staff, h.UpdateSeries)
+	r.DELETE("/series/:id", staff, h.DeleteSeries)
+	r.PUT("/series/:id/books/:book_id", staff, h.SetSeriesBook)
+	r.DELETE("/series/:id/books/:book_id", staff, h.RemoveSeriesBook)
+	r.PUT("/series/:id/order", staff, h.ReorderSeries)
+}
+
+func RegisterFieldRoutes(r *gin.Engine, ah *AuthHandler, h *FieldHandler) {
+	fields := r.Group("/admin/fields", ah.RequireRole(domain.RoleAdmin))
+	fields.GET("", h.GetFields)
+	fields.GET("/:name", h.GetField)
+	fields.POST("", h.CreateField)
+	fields.PUT("/:name", h.UpdateField)
+	fields.DELETE("/:name", h.DeleteField)
+}
+
+func RegisterBrowseRoutes(r *gin.Engine, h *BrowseHandler) {
+	r.GET("/books/facets", h.GetFacets)
+	r.GET("/books/suggest", h.Suggest)
+	r.GET("/books/languages", h.GetLanguages)
+}
+
+// RegisterSearchRankingRoutes wires the search ranking weights and the
+// explanation of a search's ranking for admins.
+func RegisterSearchRankingRoutes(r *gin.Engine, ah *AuthHandler, h *SearchRankingHandler) {
+	search := r.Group("/admin/search", ah.RequireRole(domain.RoleAdmin))
+	search.GET("/ranking", h.GetRanking)
+	search.PUT("/ranking", h.SetRanking)
+	search.PUT("/ranking/tenants/:tenant", h.SetTenantRanking)
+	search.DELETE("/ranking/tenants/:tenant", h.ClearTenantRanking)
+	search.GET("/explain", h.ExplainSearch)
+}
+
+// RegisterSearchVocabularyRoutes wires the synonym sets and stop words
+// searches are expanded with, and a test of the expansion, for admins.
+func RegisterSearchVocabularyRoutes(r *gin.Engine, ah *AuthHandler, h *SearchVocabularyHandler) {
+	search := r.Group("/admin/search", ah.RequireRole(domain.RoleAdmin))
+	search.GET("/synonyms", h.GetSynonyms)
+	search.POST("/synonyms", h.CreateSynonyms)
+	search.PUT("/synonyms/:id", h.UpdateSynonyms)
+	search.DELETE("/synonyms/:id", h.DeleteSynonyms)
+	search.GET("/stopwords", h.GetStopWords)
+	search.PUT("/stopwords/:language", h.SetStopWords)
+	search.DELETE("/stopwords/:language", h.ResetStopWords)
+	search.GET("/expand", h.ExpandQuery)
+}
+
+// RegisterSearchAnalyticsRoutes wires the search analytics, which are for
+// staff.
+func RegisterSearchAnalyticsRoutes(r *gin.Engine, ah *AuthHandler, h *SearchAnalyticsHandler) {
+	r.GET("/admin/analytics/search", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin), h.GetSearchAnalytics)
+}
+
+func RegisterSavedSearchRoutes(r *gin.Engine, ah *AuthHandler, h *SavedSearchHandler) {
+	me := r.Group("/me", ah.RequireMember())
+	me.GET("/searches", h.GetSavedSearches)
+	me.POST("/searches", h.SaveSearch)
+	me.DELETE("/searches/:id", h.DeleteSavedSearch)
+	me.GET("/notifications", h.GetNotifications)
+	me.POST("/notifications/:id/read", h.MarkNotificationRead)
+	me.GET("/notification-preferences", h.GetNotificationPreferences)
+	me.PUT("/notification-preferences", h.UpdateNotificationPreferences)
+}
+
+// RegisterShelfRoutes wires the new-arrival shelf. Only librarians and
+// admins choose which books are featured.
+func RegisterShelfRoutes(r *gin.Engine, ah *AuthHandler, h *BookHandler) {
+	r.GET("/books/new", h.GetNewArrivals)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/books/:id/feature", staff, h.FeatureBook)
+	r.DELETE("/books/:id/feature", staff, h.UnfeatureBook)
+	r.POST("/admin/books/migrate-language", ah.RequireRole(domain.RoleAdmin), h.MigrateLanguage)
+}
+
+// RegisterCatalogingRoutes wires the draft queue and the publishing of
+// records, for librarians and admins.
+func RegisterCatalogingRoutes(r *gin.Engine, ah *AuthHandler, h *BookHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.GET("/admin/books", staff, h.GetBooksByStatus)
+	r.POST("/books/:id/publish", staff, h.PublishBook)
+	r.POST("/books/:id/withdraw", staff, h.WithdrawBook)
+}
+
+func RegisterTrendingRoutes(r *gin.Engine, h *TrendingHandler) {
+	r.GET("/books/trending", h.GetTrending)
+}
+
+// RegisterStatsRoutes wires the view beacon, which anyone may send, and
+// the statistics, which are for staff.
+func RegisterStatsRoutes(r *gin.Engine, ah *AuthHandler, h *StatsHandler) {
+	r.POST("/books/:id/view", h.RecordView)
+
+	stats := r.Group("/stats", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	stats.GET("/views", h.GetMostViewed)
+	stats.GET("/books/:id/views", h.GetBookViews)
+	stats.GET("/weeding", h.GetWeedingReport)
+}
+
+// RegisterCopyRoutes wires the copy records. Withdrawing, reinstating
+// and transferring copies is for staff.
+func RegisterCopyRoutes(r *gin.Engine, ah *AuthHandler, h *CopyHandler) {
+	r.GET("/copies", h.GetCopies)
+	r.GET("/copies/:id", h.GetCopyByID)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/copies", staff, h.CreateCopy)
+	copies := r.Group("/copies/:id", staff)
+	copies.PUT("", h.UpdateCopy)
+	copies.DELETE("", h.DeleteCopy)
+	copies.POST("/withdraw", h.WithdrawCopy)
+	copies.POST("/reinstate", h.ReinstateCopy)
+	copies.POST("/transfer", h.TransferCopy)
+	copies.POST("/transfer/receive", h.ReceiveCopyTransfer)
+	r.GET("/books/:id/copies", h.GetBookCopies)
+}
+
+// RegisterBranchRoutes wires the branches and the finder for branches
+// near a member. Only admins set branches up.
+func RegisterBranchRoutes(r *gin.Engine, ah *AuthHandler, h *BranchHandler) {
+	r.GET("/branches", h.GetBranches)
+	r.GET("/branches/nearby", h.GetNearbyBranches)
+	r.GET("/branches/:id", h.GetBranchByID)
+
+	admin := r.Group("/branches", ah.RequireRole(domain.RoleAdmin))
+	admin.POST("", h.CreateBranch)
+	admin.PUT("/:id", h.UpdateBranch)
+	admin.DELETE("/:id", h.DeleteBranch)
+}
+
+func RegisterInventoryRoutes(r *gin.Engine, ah *AuthHandler, h *InventoryHandler) {
+	audits := r.Group("/inventory/audits", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	audits.GET("", h.GetAudits)
+	audits.POST("", h.StartAudit)
+	audits.GET("/:id", h.GetAuditByID)
+	audits.POST("/:id/scans", h.AddScans)
+	audits.POST("/:id/close", h.CloseAudit)
+	audits.GET("/:id/report", h.GetReport)
+}
+
+func RegisterWorklistRoutes(r *gin.Engine, ah *AuthHandler, h *WorklistHandler) {
+	worklists := r.Group("/worklists", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	worklists.GET("/pulls", h.GetPullList)
+	worklists.GET("/pulls.pdf", h.GetPullListPDF)
+}
+
+func RegisterCalendarRoutes(r *gin.Engine, ah *AuthHandler, h *CalendarHandler) {
+	r.GET("/calendar", h.GetCalendar)
+
+	calendar := r.Group("/admin/calendar", ah.RequireRole(domain.RoleAdmin))
+	calendar.GET("/hours", h.GetHours)
+	calendar.PUT("/hours", h.SetHours)
+	calendar.GET("/closed", h.GetClosedDays)
+	calendar.POST("/closed", h.AddClosedDay)
+	calendar.DELETE("/closed/:date", h.RemoveClosedDay)
+}
+
+func RegisterBookingRoutes(r *gin.Engine, ah *AuthHandler, fh *FeatureFlagHandler, h *BookingHandler) {
+	r.GET("/bookings/resources", h.GetResources)
+	r.GET("/bookings/resources/:id", h.GetResourceByID)
+	r.GET("/bookings/resources/:id/availability", h.GetAvailability)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/bookings/resources", staff, h.CreateResource)
+	r.PUT("/bookings/resources/:id", staff, h.UpdateResource)
+	r.DELETE("/bookings/resources/:id", staff, h.DeleteResource)
+
+	bookings := r.Group("/bookings", ah.RequireMember())
+	bookings.GET("", h.GetBookings)
+	bookings.POST("", fh.Require(featureflag.Bookings), h.CreateBooking)
+	bookings.DELETE("/:id", h.CancelBooking)
+}
+
+// RegisterEventRoutes wires library programs. Anyone may browse them,
+// members register themselves, and librarians and admins manage them.
+func RegisterEventRoutes(r *gin.Engine, ah *AuthHandler, h *EventHandler) {
+	r.GET("/events", h.GetEvents)
+	r.GET("/events/:id", h.GetEventByID)
+	r.GET("/events/:id/event.ics", h.GetEventICS)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/events", staff, h.CreateEvent)
+	r.PUT("/events/:id", staff, h.UpdateEvent)
+	r.DELETE("/events/:id", staff, h.DeleteEvent)
+	r.GET("/events/:id/registrations", staff, h.GetEventRegistrations)
+
+	member := ah.RequireMember()
+	r.POST("/events/:id/registration", member, h.Register)
+	r.DELETE("/events/:id/registration", member, h.Unregister)
+	r.GET("/me/events", member, h.GetMyEvents)
+}
+
+// RegisterCalendarFeedRoutes wires members' iCalendar feed, which also
+// accepts a signed feed token in place of the bearer token.
+func RegisterCalendarFeedRoutes(r *gin.Engine, ah *AuthHandler, h *CalendarFeedHandler) {
+	r.GET("/me/due-dates.ics", h.RequireFeedAccess(ah), h.GetDueDatesICS)
+	r.GET("/me/due-dates/token", ah.RequireMember(), h.GetFeedToken)
+	r.POST("/me/due-dates/token", ah.RequireMember(), h.RotateFeedToken)
+}
+
+// RegisterContentPolicyRoutes wires the admin settings for what child
+// accounts see. The Audience middleware that enforces them is installed
+// separately, before the catalog routes.
+func RegisterContentPolicyRoutes(r *gin.Engine, ah *AuthHandler, h *ContentPolicyHandler) {
+	admin := r.Group("/admin", ah.RequireRole(domain.RoleAdmin))
+	admin.GET("/content-policy", h.GetPolicy)
+	admin.PUT("/content-policy", h.UpdatePolicy)
+	admin.PUT("/content-policy/tenants/:tenant", h.SetTenantAudience)
+	admin.DELETE("/content-policy/tenants/:tenant", h.ClearTenantAudience)
+}
+
+// RegisterReviewRoutes wires book reviews, members' reports about them
+// and the admin moderation queue. Members' routes are hidden while the
+// reviews flag is off; moderation stays open.
+func RegisterReviewRoutes(r *gin.Engine, ah *AuthHandler, fh *FeatureFlagHandler, h *ReviewHandler) {
+	reviews := fh.Require(featureflag.Reviews)
+	r.GET("/books/:id/reviews", reviews, h.GetBookReviews)
+
+	member := ah.RequireMember()
+	r.POST("/books/:id/reviews", reviews, member, h.CreateReview)
+	r.GET("/me/reviews", reviews, member, h.GetMyReviews)
+	r.DELETE("/reviews/:id", reviews, member, h.DeleteReview)
+	r.POST("/reviews/:id/report", reviews, member, h.ReportReview)
+
+	admin := r.Group("/admin", ah.RequireRole(domain.RoleAdmin))
+	admin.GET("/reviews/queue", h.GetModerationQueue)
+	admin.GET("/reviews/:id/reports", h.GetReviewReports)
+	admin.POST("/reviews/:id/approve", h.ApproveReview)
+	admin.POST("/reviews/:id/reject", h.RejectReview)
+	admin.GET("/moderation", h.GetModerationSettings)
+	admin.PUT("/moderation", h.UpdateModerationSettings)
+}
+
+// RegisterSMSRoutes wires the SMS settings and the provider's delivery
+// status webhook, which is authenticated by its signature rather than a
+// token.
+func RegisterSMSRoutes(r *gin.Engine, ah *AuthHandler, h *SMSHandler) {
+	r.POST("/webhooks/sms/status", h.SMSStatusCallback)
+
+	admin := r.Group("/admin", ah.RequireRole(domain.RoleAdmin))
+	admin.GET("/sms", h.GetSMSSettings)
+	admin.PUT("/sms", h.UpdateSMSSettings)
+	admin.DELETE("/sms", h.ClearSMSSettings)
+	admin.GET("/sms/messages", h.GetSMSMessages)
+}
+
+// RegisterPushRoutes wires browser push subscriptions. The VAPID key is
+// public, since the front-end needs it before the member subscribes.
+func RegisterPushRoutes(r *gin.Engine, ah *AuthHandler, h *PushHandler) {
+	r.GET("/push/vapid-public-key", h.GetVAPIDKey)
+
+	me := r.Group("/me", ah.RequireMember())
+	me.GET("/push-subscriptions", h.GetPushSubscriptions)
+	me.POST("/push-subscriptions", h.CreatePushSubscription)
+	me.DELETE("/push-subscriptions/:id", h.DeletePushSubscription)
+}
+
+// RegisterAvailabilityRoutes wires the availability prediction of books.
+func RegisterAvailabilityRoutes(r *gin.Engine, h *AvailabilityHandler) {
+	r.GET("/books/:id/availability", h.GetBookAvailability)
+	r.GET("/books/:id/availability/branches", h.GetBookBranchAvailability)
+}
+
+func RegisterRelatedRoutes(r *gin.Engine, h *RelatedHandler) {
+	r.GET("/books/:id/related", h.GetRelatedBooks)
+}
+
+func RegisterImportRoutes(r *gin.Engine, ah *AuthHandler, h *ImportHandler) {
+	r.POST("/me/import", ah.RequireMember(), h.ImportReadingHistory)
+}
+
+// RegisterAcquisitionRoutes wires ISBN scan intake, which only librarians
+// and admins use.
+func RegisterAcquisitionRoutes(r *gin.Engine, ah *AuthHandler, h *AcquisitionHandler) {
+	acquisitions := r.Group("/acquisitions", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	acquisitions.GET("", h.GetAcquisitions)
+	acquisitions.POST("/scan", h.ScanISBNs)
+	acquisitions.POST("/:id/catalog", h.CatalogAcquisition)
+	acquisitions.DELETE("/:id", h.DiscardAcquisition)
+}
+
+// RegisterBudgetRoutes wires the acquisition spending reports for admins.
+func RegisterBudgetRoutes(r *gin.Engine, ah *AuthHandler, h *BudgetHandler) {
+	budget := r.Group("/admin/budget", ah.RequireRole(domain.RoleAdmin))
+	budget.GET("", h.GetBudget)
+	budget.GET("/funds/:code/copies", h.GetFundCopies)
+}
+
+// RegisterPurchasingRoutes wires vendors and purchase orders, which only
+// librarians and admins use.
+func RegisterPurchasingRoutes(r *gin.Engine, ah *AuthHandler, vh *VendorHandler, oh *PurchaseOrderHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+
+	vendors := r.Group("/vendors", staff)
+	vendors.GET("", vh.GetVendors)
+	vendors.GET("/:id", vh.GetVendorByID)
+	vendors.POST("", vh.CreateVendor)
+	vendors.PUT("/:id", vh.UpdateVendor)
+	vendors.DELETE("/:id", vh.DeleteVendor)
+
+	orders := r.Group("/purchase-orders", staff)
+	orders.GET("", oh.GetPurchaseOrders)
+	orders.GET("/:id", oh.GetPurchaseOrderByID)
+	orders.POST("", oh.CreatePurchaseOrder)
+	orders.PUT("/:id", oh.UpdatePurchaseOrder)
+	orders.DELETE("/:id", oh.DeletePurchaseOrder)
+	orders.POST("/:id/send", oh.SendPurchaseOrder)
+	orders.POST("/:id/receive", oh.ReceivePurchaseOrder)
+}
+
+// RegisterCatalogImportRoutes wires bulk imports of books, which only
+// librarians and admins run.
+func RegisterCatalogImportRoutes(r *gin.Engine, ah *AuthHandler, h *CatalogImportHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/books/import/preview", staff, h.PreviewCatalogImport)
+	r.POST("/books/import", staff, h.ImportCatalog)
+}
+
+// RegisterTaskRoutes wires the status of background tasks for staff.
+func RegisterTaskRoutes(r *gin.Engine, ah *AuthHandler, h *TaskStatusHandler) {
+	tasks := r.Group("/tasks", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	tasks.GET("/queues", h.GetQueues)
+	tasks.GET("/types", h.GetTypes)
+	tasks.POST("/types/:name", h.Submit)
+	tasks.GET("/:id", h.GetTask)
+	tasks.GET("/:id/errors", h.GetTaskErrors)
+	tasks.GET("/:id/logs", h.GetTaskLogs)
+}
+
+// RegisterTaskTriggerRoutes wires the trigger for external systems, which
+// authenticate with an API key rather than a session.
+func RegisterTaskTriggerRoutes(r *gin.Engine, h *TaskTriggerHandler) {
+	r.POST("/tasks/trigger/:name", h.Trigger)
+}
+
+// RegisterExportRoutes wires the scheduled catalog exports, which only
+// admins configure since they hold credentials for outside systems.
+func RegisterExportRoutes(r *gin.Engine, ah *AuthHandler, h *ExportJobHandler) {
+	exports := r.Group("/admin/exports", ah.RequireRole(domain.RoleAdmin))
+	exports.GET("", h.GetExportJobs)
+	exports.POST("", h.CreateExportJob)
+	exports.GET("/:id", h.GetExportJob)
+	exports.PUT("/:id", h.UpdateExportJob)
+	exports.DELETE("/:id", h.DeleteExportJob)
+	exports.POST("/:id/run", h.RunExportJob)
+}
+
+// RegisterFeatureFlagRoutes wires the feature flags: their state for the
+// current tenant, which anyone may read, and the admin toggles.
+func RegisterFeatureFlagRoutes(r *gin.Engine, ah *AuthHandler, h *FeatureFlagHandler) {
+	r.GET("/features", h.GetFeatures)
+
+	flags := r.Group("/admin/flags", ah.RequireRole(domain.RoleAdmin))
+	flags.GET("", h.GetFlags)
+	flags.PUT("/:name", h.SetFlag)
+	flags.PUT("/:name/tenants/:tenant", h.SetTenantFlag)
+	flags.DELETE("/:name/tenants/:tenant", h.ClearTenantFlag)
+}
+
+// RegisterConfigRoutes wires the runtime configuration for admins.
+func RegisterConfigRoutes(r *gin.Engine, ah *AuthHandler, h *ConfigHandler) {
+	config := r.Group("/admin/config", ah.RequireRole(domain.RoleAdmin))
+	config.GET("", h.GetConfig)
+	config.POST("/reload", h.ReloadConfig)
+}
+
+// RegisterSelfCheckRoutes wires the self-check report for admins.
+func RegisterSelfCheckRoutes(r *gin.Engine, ah *AuthHandler, h *SelfCheckHandler) {
+	selfcheck := r.Group("/admin/selfcheck", ah.RequireRole(domain.RoleAdmin))
+	selfcheck.GET("", h.GetSelfCheck)
+	selfcheck.POST("", h.RunSelfCheck)
+}
+
+// RegisterOverviewRoutes wires the operations dashboard for admins.
+func RegisterOverviewRoutes(r *gin.Engine, ah *AuthHandler, h *OverviewHandler) {
+	r.GET("/admin/overview", ah.RequireRole(domain.RoleAdmin), h.GetOverview)
+}
+
+// RegisterMaintenanceRoutes wires the maintenance mode switch for admins.
+func RegisterMaintenanceRoutes(r *gin.Engine, ah *AuthHandler, h *MaintenanceHandler) {
+	maintenance := r.Group("/admin/maintenance", ah.RequireRole(domain.RoleAdmin))
+	maintenance.GET("", h.GetMaintenance)
+	maintenance.POST("", h.SetMaintenance)
+}
+
+// RegisterSyncRoutes wires offline sync for staff.
+func RegisterSyncRoutes(r *gin.Engine, ah *AuthHandler, h *SyncHandler) {
+	r.POST("/sync", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin), h.Sync)
+}
+
+// RegisterIntegrationRoutes wires the inbox for external loan systems,
+// which authenticate by signing each message rather than with a token.
+func RegisterIntegrationRoutes(r *gin.Engine, h *IntegrationHandler) {
+	r.POST("/integrations/:name", h.ReceiveKioskMessage)
+}
+
+// RegisterFederationRoutes wires interlibrary loan requests to
+// consortium partners. Members request for themselves; librarians see
+// every request.
+func RegisterFederationRoutes(r *gin.Engine, ah *AuthHandler, h *FederationHandler) {
+	me := r.Group("/me/ill-requests", ah.RequireMember())
+	me.GET("", h.GetMyILLRequests)
+	me.POST("", h.RequestFromPartner)
+	r.GET("/ill-requests", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin), h.GetILLRequests)
+}
+
+// RegisterNCIPRoutes wires the NCIP endpoint for consortium partners,
+// which authenticate inside each message.
+func RegisterNCIPRoutes(r *gin.Engine, h *NCIPHandler) {
+	r.POST("/ncip", h.HandleNCIP)
+}
+
+// RegisterCourseRoutes wires course reserves: the JSON API librarians
+// manage them and their short-loan copies with, and the LTI pages learning management systems embed,
+// which authenticate with the signed launch and then a session token.
+func RegisterCourseRoutes(r *gin.Engine, ah *AuthHandler, ch *CourseHandler, lh *LTIHandler) {
+	r.GET("/courses", ch.GetCourses)
+	r.GET("/courses/:id", ch.GetCourseReserves)
+
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.POST("/courses", staff, ch.CreateCourse)
+	r.PUT("/courses/:id", staff, ch.UpdateCourse)
+	r.DELETE("/courses/:id", staff, ch.DeleteCourse)
+	r.PUT("/courses/:id/reserves/:bookId", staff, ch.AddReserve)
+	r.DELETE("/courses/:id/reserves/:bookId", staff, ch.RemoveReserve)
+	r.PUT("/courses/:id/copies/:copyId", staff, ch.PlaceCopyOnReserve)
+	r.DELETE("/courses/:id/copies/:copyId", staff, ch.TakeCopyOffReserve)
+
+	r.GET("/lti/login", lh.Login)
+	r.POST("/lti/login", lh.Login)
+	r.POST("/lti/launch", lh.Launch)
+	r.GET("/lti/reserves", lh.GetReserves)
+	r.POST("/lti/reserves", lh.AddReserve)
+	r.POST("/lti/reserves/remove", lh.RemoveReserve)
+}
+
+// RegisterPaymentRoutes wires online payment of fines and the payment
+// provider's webhook.
+func RegisterPaymentRoutes(r *gin.Engine, ah *AuthHandler, h *PaymentHandler) {
+	r.POST("/webhooks/payments", h.PaymentWebhook)
+
+	me := r.Group("/me", ah.RequireMember())
+	me.GET("/payments", h.GetMyPayments)
+	me.POST("/payments", h.PayFines)
+
+	admin := r.Group("/admin", ah.RequireRole(domain.RoleAdmin))
+	admin.GET("/payments", h.GetPayments)
+	admin.POST("/payments/reconcile", h.ReconcilePayments)
+}
+
+// RegisterTemplateRoutes wires the editing of message and receipt
+// templates, and the receipts and slips printed with them.
+func RegisterTemplateRoutes(r *gin.Engine, ah *AuthHandler, th *TemplateHandler, rh *ReceiptHandler) {
+	staff := ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin)
+	r.GET("/loans/:id/receipt", staff, rh.GetLoanReceipt)
+	r.GET("/holds/:id/slip", staff, rh.GetHoldSlip)
+
+	admin := r.Group("/admin", ah.RequireRole(domain.RoleAdmin))
+	admin.GET("/templates", th.GetTemplates)
+	admin.GET("/templates/:channel/:kind", th.GetTemplate)
+	admin.PUT("/templates/:channel/:kind", th.SetTemplate)
+	admin.DELETE("/templates/:channel/:kind", th.DeleteTemplate)
+	admin.POST("/templates/:channel/:kind/preview", th.PreviewTemplate)
+}
+
+// RegisterSerialRoutes wires serials, their subscriptions and issues,
+// which only librarians and admins manage.
+func RegisterSerialRoutes(r *gin.Engine, ah *AuthHandler, h *SerialHandler) {
+	serials := r.Group("/serials", ah.RequireRole(domain.RoleLibrarian, domain.RoleAdmin))
+	serials.GET("", h.GetSerials)
+	serials.GET("/claims", h.GetClaims)
+	serials.GET("/:id", h.GetSerialByID)
+	serials.POST("", h.CreateSerial)
+	serials.PUT("/:id", h.UpdateSerial)
+	serials.DELETE("/:id", h.DeleteSerial)
+	serials.GET("/:id/subscriptions", h.GetSubscriptions)
+	serials.POST("/:id/subscriptions", h.Subscribe)
+	serials.GET("/:id/issues", h.GetIssues)
+	serials.GET("/:id/holdings", h.GetHoldings)
+	serials.POST("/subscriptions/:id/cancel", h.CancelSubscription)
+	serials.POST("/issues/:id/receive", h.ReceiveIssue)
+	serials.POST("/issues/:id/claim", h.ClaimIssue)
+}
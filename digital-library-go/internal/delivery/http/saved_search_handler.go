@@ -0,0 +1,170 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedSearchHandler serves a member's saved searches, their
+// notifications and how they want to receive them. Like MeHandler, it
+// acts on the authenticated member only.
+type SavedSearchHandler struct {
+	searches      *usecase.SavedSearchUsecase
+	notifications *usecase.NotificationUsecase
+}
+
+func NewSavedSearchHandler(searches *usecase.SavedSearchUsecase, notifications *usecase.NotificationUsecase) *SavedSearchHandler {
+	return &SavedSearchHandler{searches: searches, notifications: notifications}
+}
+
+// GetSavedSearches godoc
+// @Summary Get my saved searches
+// @Description Get the authenticated member's saved searches
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.SavedSearch
+// @Router /me/searches [get]
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	searches := h.searches.SearchesForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": searches})
+}
+
+// SaveSearch godoc
+// @Summary Save a search
+// @Description Save a query; the member is notified whenever a new book's title or author matches it
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search body domain.SavedSearch true "Search to save"
+// @Success 201 {object} domain.SavedSearch
+// @Failure 400 {object} map[string]string
+// @Router /me/searches [post]
+func (h *SavedSearchHandler) SaveSearch(c *gin.Context) {
+	var search domain.SavedSearch
+
+	if err := c.ShouldBindJSON(&search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := search.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved := h.searches.SaveSearch(currentMemberID(c), search)
+	c.JSON(http.StatusCreated, gin.H{"data": saved})
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete a saved search
+// @Description Delete one of the authenticated member's saved searches
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Saved search ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.searches.DeleteSearch(currentMemberID(c), id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "saved search deleted"})
+}
+
+// GetNotifications godoc
+// @Summary Get my notifications
+// @Description Get the authenticated member's notifications, newest first
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Notification
+// @Router /me/notifications [get]
+func (h *SavedSearchHandler) GetNotifications(c *gin.Context) {
+	notifications := h.notifications.NotificationsForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": notifications})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Description Mark one of the authenticated member's notifications as read
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /me/notifications/{id}/read [post]
+func (h *SavedSearchHandler) MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.notifications.MarkRead(currentMemberID(c), id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get my notification preferences
+// @Description Get the channels (in_app, email, sms, push) the authenticated member receives each kind of notification on. channels lists those the library has configured besides in_app.
+// @Tags Me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.NotificationPreferences
+// @Router /me/notification-preferences [get]
+func (h *SavedSearchHandler) GetNotificationPreferences(c *gin.Context) {
+	prefs := h.notifications.PreferencesForMember(currentMemberID(c))
+	c.JSON(http.StatusOK, gin.H{"data": prefs, "channels": h.notifications.Channels()})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update my notification preferences
+// @Description Choose the channels for some kinds of notification, e.g. {"event_reminder": {"in_app": true, "email": true}}. Kinds left out are unchanged.
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preferences body domain.NotificationPreferences true "Channels per kind"
+// @Success 200 {object} domain.NotificationPreferences
+// @Failure 400 {object} map[string]string
+// @Router /me/notification-preferences [put]
+func (h *SavedSearchHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var prefs domain.NotificationPreferences
+
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := prefs.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated := h.notifications.SetPreferences(currentMemberID(c), prefs)
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchAnalyticsHandler struct {
+	uc *usecase.SearchAnalyticsUsecase
+}
+
+func NewSearchAnalyticsHandler(uc *usecase.SearchAnalyticsUsecase) *SearchAnalyticsHandler {
+	return &SearchAnalyticsHandler{uc: uc}
+}
+
+// GetSearchAnalytics godoc
+// @Summary Get search analytics
+// @Description Get the catalog searches over a date range for collection development: how many there were and how many found nothing, the most searched terms, the terms that most often found nothing, and the most used facet filters. Nothing about who searched is kept, and terms searched fewer than min_searches times are left out. Librarians only.
+// @Tags Stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param limit query int false "Maximum terms and filters in each list (default 20, max 500)"
+// @Success 200 {object} domain.SearchAnalytics
+// @Failure 400 {object} map[string]string
+// @Router /admin/analytics/search [get]
+func (h *SearchAnalyticsHandler) GetSearchAnalytics(c *gin.Context) {
+	from, to, err := dateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = n
+	}
+
+	report, err := h.uc.Report(from, to, limit)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
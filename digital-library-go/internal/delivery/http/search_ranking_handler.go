@@ -0,0 +1,131 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchRankingHandler struct {
+	uc     *usecase.SearchRankingUsecase
+	browse *usecase.BrowseUsecase
+}
+
+func NewSearchRankingHandler(uc *usecase.SearchRankingUsecase, browse *usecase.BrowseUsecase) *SearchRankingHandler {
+	return &SearchRankingHandler{uc: uc, browse: browse}
+}
+
+// GetRanking godoc
+// @Summary Get the search ranking weights
+// @Description Get the weights search results are ranked by: the default and those of tenants with their own. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.RankingSettings
+// @Router /admin/search/ranking [get]
+func (h *SearchRankingHandler) GetRanking(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Settings()})
+}
+
+// SetRanking godoc
+// @Summary Set the default search ranking weights
+// @Description Set the weights of title matches, author matches, recency and popularity, each from 0 to 100, for tenants without their own. Weights left out are 0. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param weights body domain.RankingWeights true "Ranking weights"
+// @Success 200 {object} domain.RankingSettings
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/ranking [put]
+func (h *SearchRankingHandler) SetRanking(c *gin.Context) {
+	var w domain.RankingWeights
+	if err := c.ShouldBindJSON(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := h.uc.SetDefault(w); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Settings()})
+}
+
+// SetTenantRanking godoc
+// @Summary Set a tenant's search ranking weights
+// @Description Give one tenant, the host name a site is served on, its own ranking weights. Weights left out are 0. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant host name"
+// @Param weights body domain.RankingWeights true "Ranking weights"
+// @Success 200 {object} domain.RankingSettings
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/ranking/tenants/{tenant} [put]
+func (h *SearchRankingHandler) SetTenantRanking(c *gin.Context) {
+	var w domain.RankingWeights
+	if err := c.ShouldBindJSON(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := h.uc.SetTenant(c.Param("tenant"), w); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Settings()})
+}
+
+// ClearTenantRanking godoc
+// @Summary Remove a tenant's search ranking weights
+// @Description Let a tenant's searches be ranked by the default weights again. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Tenant host name"
+// @Success 200 {object} domain.RankingSettings
+// @Failure 404 {object} map[string]string
+// @Router /admin/search/ranking/tenants/{tenant} [delete]
+func (h *SearchRankingHandler) ClearTenantRanking(c *gin.Context) {
+	if err := h.uc.ClearTenant(c.Param("tenant")); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Settings()})
+}
+
+// ExplainSearch godoc
+// @Summary Explain a search's ranking
+// @Description Run a search as GET /books/facets does and show, for each result, its rank, its score and how each factor made it up: the factor's value from 0 to 1, its weight and why it has that value. The search is not counted in the search analytics. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Text to find in title or author"
+// @Param tenant query string false "Tenant to rank for (default the host the request was sent to)"
+// @Param author query string false "Selected author"
+// @Param genre query string false "Selected genre"
+// @Param decade query string false "Selected decade, e.g. 1960s"
+// @Param language query string false "Selected language, an ISO 639 code such as es or spa"
+// @Param format query string false "print, audiobook, dvd or magazine"
+// @Param availability query string false "available or on_loan"
+// @Param audience query string false "all or children"
+// @Success 200 {object} domain.SearchExplanation
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/explain [get]
+func (h *SearchRankingHandler) ExplainSearch(c *gin.Context) {
+	q := c.Query("q")
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	tenant := c.DefaultQuery("tenant", c.Request.Host)
+	explanation, err := h.browse.Explain(q, selectedFacets(c), audienceOf(c), tenant)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": explanation})
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StopWordsRequest replaces the stop words of a language.
+type StopWordsRequest struct {
+	Words []string `json:"words"`
+}
+
+type SearchVocabularyHandler struct {
+	uc *usecase.SearchVocabularyUsecase
+}
+
+func NewSearchVocabularyHandler(uc *usecase.SearchVocabularyUsecase) *SearchVocabularyHandler {
+	return &SearchVocabularyHandler{uc: uc}
+}
+
+// GetSynonyms godoc
+// @Summary Get the synonym sets
+// @Description List the groups of words and phrases searches treat as the same. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.SynonymSet
+// @Router /admin/search/synonyms [get]
+func (h *SearchVocabularyHandler) GetSynonyms(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Synonyms()})
+}
+
+// CreateSynonyms godoc
+// @Summary Add a synonym set
+// @Description Add a group of at least two words or phrases searches treat as the same, e.g. {"terms": ["sci-fi", "science fiction"]}, optionally for one language only. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param set body domain.SynonymSet true "Synonym set"
+// @Success 201 {object} domain.SynonymSet
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/synonyms [post]
+func (h *SearchVocabularyHandler) CreateSynonyms(c *gin.Context) {
+	var s domain.SynonymSet
+	if err := c.ShouldBindJSON(&s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	s, err := h.uc.AddSynonyms(s)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": s})
+}
+
+// UpdateSynonyms godoc
+// @Summary Replace a synonym set
+// @Description Replace the terms and language of a synonym set. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Synonym set ID"
+// @Param set body domain.SynonymSet true "Synonym set"
+// @Success 200 {object} domain.SynonymSet
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/search/synonyms/{id} [put]
+func (h *SearchVocabularyHandler) UpdateSynonyms(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var s domain.SynonymSet
+	if err := c.ShouldBindJSON(&s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	s, err = h.uc.UpdateSynonyms(id, s)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": s})
+}
+
+// DeleteSynonyms godoc
+// @Summary Delete a synonym set
+// @Description Stop treating the words and phrases of a set as the same. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Synonym set ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/search/synonyms/{id} [delete]
+func (h *SearchVocabularyHandler) DeleteSynonyms(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := h.uc.DeleteSynonyms(id); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "synonym set deleted"})
+}
+
+// GetStopWords godoc
+// @Summary Get the stop words
+// @Description List the words searches ignore, by language. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.StopWords
+// @Router /admin/search/stopwords [get]
+func (h *SearchVocabularyHandler) GetStopWords(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.StopWords()})
+}
+
+// SetStopWords godoc
+// @Summary Set the stop words of a language
+// @Description Replace the words searches in a language ignore. An empty list turns stop words off for the language. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param language path string true "ISO 639 language code"
+// @Param words body StopWordsRequest true "Stop words"
+// @Success 200 {object} domain.StopWords
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/stopwords/{language} [put]
+func (h *SearchVocabularyHandler) SetStopWords(c *gin.Context) {
+	var req StopWordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	words, err := h.uc.SetStopWords(c.Param("language"), req.Words)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": words})
+}
+
+// ResetStopWords godoc
+// @Summary Reset the stop words of a language
+// @Description Give a language its default stop words back, or none if it has no defaults. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param language path string true "ISO 639 language code"
+// @Success 200 {object} domain.StopWords
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/stopwords/{language} [delete]
+func (h *SearchVocabularyHandler) ResetStopWords(c *gin.Context) {
+	words, err := h.uc.ResetStopWords(c.Param("language"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": words})
+}
+
+// ExpandQuery godoc
+// @Summary Test how a query is expanded
+// @Description Show the stop words a search drops from a query and the alternatives synonyms expand it into, without searching. A book matches when its title or author has every word of one alternative. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Query, e.g. the best sci-fi"
+// @Param language query string false "ISO 639 language code (default the search language)"
+// @Success 200 {object} domain.QueryExpansion
+// @Failure 400 {object} map[string]string
+// @Router /admin/search/expand [get]
+func (h *SearchVocabularyHandler) ExpandQuery(c *gin.Context) {
+	q := c.Query("q")
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	language := c.Query("language")
+	if language != "" && !domain.ValidLanguage(language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidLanguage.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Expand(q, language)})
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecurityHandler struct {
+	guard *usecase.LoginGuardUsecase
+	audit *usecase.AuditUsecase
+	auth  *usecase.AuthUsecase
+}
+
+func NewSecurityHandler(guard *usecase.LoginGuardUsecase, audit *usecase.AuditUsecase, auth *usecase.AuthUsecase) *SecurityHandler {
+	return &SecurityHandler{guard: guard, audit: audit, auth: auth}
+}
+
+// GetLockouts godoc
+// @Summary List login lockouts
+// @Description Get accounts and IPs with recent failed logins, including active lockouts
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} domain.Lockout
+// @Router /admin/lockouts [get]
+func (h *SecurityHandler) GetLockouts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.guard.Lockouts()})
+}
+
+// ClearLockout godoc
+// @Summary Clear a login lockout
+// @Description Forget the failed logins of an account (by card number) or an IP
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param kind path string true "account or ip"
+// @Param key path string true "Card number or IP address"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/lockouts/{kind}/{key} [delete]
+func (h *SecurityHandler) ClearLockout(c *gin.Context) {
+	kind := c.Param("kind")
+	if kind != usecase.LockoutAccount && kind != usecase.LockoutIP {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be account or ip"})
+		return
+	}
+
+	admin, _ := h.auth.Member(bearerToken(c))
+	if err := h.guard.Unlock(kind, c.Param("key"), admin.CardNumber); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "lockout cleared"})
+}
+
+// GetAuditEvents godoc
+// @Summary Get the audit log
+// @Description Get security events, newest first
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "Only events of this type, e.g. account_locked"
+// @Success 200 {array} domain.AuditEvent
+// @Router /admin/audit [get]
+func (h *SecurityHandler) GetAuditEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.audit.GetEvents(c.Query("type"))})
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SelfCheckHandler struct {
+	uc *usecase.SelfCheckUsecase
+}
+
+func NewSelfCheckHandler(uc *usecase.SelfCheckUsecase) *SelfCheckHandler {
+	return &SelfCheckHandler{uc: uc}
+}
+
+// GetSelfCheck godoc
+// @Summary Get the startup self-check report
+// @Description Get the report of the last self-check, run at startup: whether the SMTP relay and the lock store are reachable and the snapshot directory is writable, with checks that do not apply to this configuration marked skipped. Admin only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.SelfCheckReport
+// @Router /admin/selfcheck [get]
+func (h *SelfCheckHandler) GetSelfCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Last()})
+}
+
+// RunSelfCheck godoc
+// @Summary Run the self-check again
+// @Description Run every self-check again, e.g. after fixing a dependency, and return the new report. Admin only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.SelfCheckReport
+// @Router /admin/selfcheck [post]
+func (h *SelfCheckHandler) RunSelfCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Run(c.Request.Context())})
+}
@@ -0,0 +1,393 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubscriptionRequest is the body accepted when subscribing to a serial.
+// Dates are YYYY-MM-DD.
+type SubscriptionRequest struct {
+	VendorID        int    `json:"vendor_id"`
+	Frequency       string `json:"frequency"`
+	StartsOn        string `json:"starts_on"`
+	EndsOn          string `json:"ends_on"`
+	FirstVolume     int    `json:"first_volume"`
+	FirstIssue      int    `json:"first_issue"`
+	IssuesPerVolume int    `json:"issues_per_volume"`
+	ClaimAfterDays  int    `json:"claim_after_days"`
+	Location        string `json:"location"`
+}
+
+// IssueNoteRequest is the optional body accepted when checking in or
+// claiming an issue.
+type IssueNoteRequest struct {
+	Note string `json:"note"`
+}
+
+type SerialHandler struct {
+	uc *usecase.SerialUsecase
+}
+
+func NewSerialHandler(uc *usecase.SerialUsecase) *SerialHandler {
+	return &SerialHandler{uc: uc}
+}
+
+// GetSerials godoc
+// @Summary Get serials
+// @Description Get every serial title the library catalogs. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Serial
+// @Router /serials [get]
+func (h *SerialHandler) GetSerials(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetSerials()})
+}
+
+// GetSerialByID godoc
+// @Summary Get a serial by ID
+// @Description Get a serial title. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Success 200 {object} domain.Serial
+// @Failure 404 {object} map[string]string
+// @Router /serials/{id} [get]
+func (h *SerialHandler) GetSerialByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	serial, err := h.uc.GetSerialByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": serial})
+}
+
+// CreateSerial godoc
+// @Summary Create a serial
+// @Description Add a serial title, such as a magazine or journal, to subscribe to. Librarians only.
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param serial body domain.Serial true "Serial"
+// @Success 201 {object} domain.Serial
+// @Failure 400 {object} map[string]string
+// @Router /serials [post]
+func (h *SerialHandler) CreateSerial(c *gin.Context) {
+	var serial domain.Serial
+	if err := c.ShouldBindJSON(&serial); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	created, err := h.uc.CreateSerial(serial)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateSerial godoc
+// @Summary Update a serial
+// @Description Update a serial title. Librarians only.
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Param serial body domain.Serial true "Serial"
+// @Success 200 {object} domain.Serial
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /serials/{id} [put]
+func (h *SerialHandler) UpdateSerial(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var serial domain.Serial
+	if err := c.ShouldBindJSON(&serial); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	updated, err := h.uc.UpdateSerial(id, serial)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": updated})
+}
+
+// DeleteSerial godoc
+// @Summary Delete a serial
+// @Description Delete a serial the library never subscribed to. Librarians only.
+// @Tags Serials
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /serials/{id} [delete]
+func (h *SerialHandler) DeleteSerial(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.DeleteSerial(id); err != nil {
+		abort(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSubscriptions godoc
+// @Summary Get a serial's subscriptions
+// @Description Get the subscriptions to a serial, active and canceled. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Success 200 {array} domain.Subscription
+// @Failure 404 {object} map[string]string
+// @Router /serials/{id}/subscriptions [get]
+func (h *SerialHandler) GetSubscriptions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	subs, err := h.uc.GetSubscriptions(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subs})
+}
+
+// Subscribe godoc
+// @Summary Subscribe to a serial
+// @Description Start a subscription to a serial, predicting the issues expected in the next 60 days. Issues are numbered from first_volume and first_issue (1 by default), restarting at 1 in each new volume after issues_per_volume issues. An issue not received claim_after_days (14 by default) after it was expected is late. Librarians only.
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Param subscription body SubscriptionRequest true "Subscription"
+// @Success 201 {object} domain.Subscription
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /serials/{id}/subscriptions [post]
+func (h *SerialHandler) Subscribe(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	sub := domain.Subscription{
+		VendorID:        req.VendorID,
+		Frequency:       req.Frequency,
+		FirstVolume:     req.FirstVolume,
+		FirstIssue:      req.FirstIssue,
+		IssuesPerVolume: req.IssuesPerVolume,
+		ClaimAfterDays:  req.ClaimAfterDays,
+		Location:        req.Location,
+	}
+	if sub.StartsOn, err = time.Parse(time.DateOnly, req.StartsOn); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "starts_on must be a date like 2024-09-01"})
+		return
+	}
+	if req.EndsOn != "" {
+		endsOn, err := time.Parse(time.DateOnly, req.EndsOn)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ends_on must be a date like 2025-08-31"})
+			return
+		}
+		sub.EndsOn = &endsOn
+	}
+
+	created, err := h.uc.Subscribe(id, sub, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// CancelSubscription godoc
+// @Summary Cancel a subscription
+// @Description Stop predicting issues of a subscription. Issues expected later are dropped; those already due stay, so late ones can still be claimed. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} domain.Subscription
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /serials/subscriptions/{id}/cancel [post]
+func (h *SerialHandler) CancelSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	sub, err := h.uc.CancelSubscription(id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sub})
+}
+
+// GetIssues godoc
+// @Summary Get a serial's issues
+// @Description Get the issues of a serial predicted so far, or those with a status, by expected date. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Param status query string false "expected, received or claimed"
+// @Success 200 {array} domain.SerialIssue
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /serials/{id}/issues [get]
+func (h *SerialHandler) GetIssues(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	status := c.Query("status")
+	switch status {
+	case "", domain.IssueExpected, domain.IssueReceived, domain.IssueClaimed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be expected, received or claimed"})
+		return
+	}
+
+	issues, err := h.uc.GetIssues(id, status)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": issues})
+}
+
+// GetHoldings godoc
+// @Summary Get a serial's holdings
+// @Description Get the issues of a serial the library holds, as a statement such as "v.11:no.1-12; v.12:no.1-3,5", with the issues that are late or claimed. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Serial ID"
+// @Success 200 {object} domain.Holdings
+// @Failure 404 {object} map[string]string
+// @Router /serials/{id}/holdings [get]
+func (h *SerialHandler) GetHoldings(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	holdings, err := h.uc.Holdings(id, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": holdings})
+}
+
+// GetClaims godoc
+// @Summary Get issues to claim
+// @Description Get the issues of every serial that are late: not received within their subscription's claim period of being expected, or of being claimed last. Librarians only.
+// @Tags Serials
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.SerialIssue
+// @Router /serials/claims [get]
+func (h *SerialHandler) GetClaims(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.LateIssues(time.Now())})
+}
+
+// ReceiveIssue godoc
+// @Summary Check in an issue
+// @Description Check in an issue that arrived, whether early, late or after being claimed. Librarians only.
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Issue ID"
+// @Param note body IssueNoteRequest false "Note"
+// @Success 200 {object} domain.SerialIssue
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /serials/issues/{id}/receive [post]
+func (h *SerialHandler) ReceiveIssue(c *gin.Context) {
+	h.updateIssue(c, h.uc.ReceiveIssue)
+}
+
+// ClaimIssue godoc
+// @Summary Claim an issue
+// @Description Record that an issue not received was claimed from the vendor. An issue whose claim goes unanswered may be claimed again. Librarians only.
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Issue ID"
+// @Param note body IssueNoteRequest false "Note"
+// @Success 200 {object} domain.SerialIssue
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /serials/issues/{id}/claim [post]
+func (h *SerialHandler) ClaimIssue(c *gin.Context) {
+	h.updateIssue(c, h.uc.ClaimIssue)
+}
+
+func (h *SerialHandler) updateIssue(c *gin.Context, update func(int, string, time.Time) (domain.SerialIssue, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req IssueNoteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+			return
+		}
+	}
+
+	issue, err := update(id, req.Note, time.Now())
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": issue})
+}
@@ -0,0 +1,292 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesPositionRequest places a book in a series.
+type SeriesPositionRequest struct {
+	Position float64 `json:"position"`
+}
+
+// SeriesOrderRequest lists a series' books in their new reading order.
+type SeriesOrderRequest struct {
+	BookIDs []int `json:"book_ids"`
+}
+
+type SeriesHandler struct {
+	uc *usecase.SeriesUsecase
+}
+
+func NewSeriesHandler(uc *usecase.SeriesUsecase) *SeriesHandler {
+	return &SeriesHandler{uc: uc}
+}
+
+// GetSeries godoc
+// @Summary Get all series
+// @Description Get list of all series with their entries
+// @Tags Series
+// @Produce json
+// @Success 200 {array} domain.Series
+// @Router /series [get]
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	series := h.uc.GetSeries()
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// GetSeriesByID godoc
+// @Summary Get a series by ID
+// @Description Get series details by ID
+// @Tags Series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {object} domain.Series
+// @Failure 404 {object} map[string]string
+// @Router /series/{id} [get]
+func (h *SeriesHandler) GetSeriesByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	series, err := h.uc.GetSeriesByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// GetSeriesBooks godoc
+// @Summary Get the books of a series
+// @Description Get the books of a series in reading order
+// @Tags Series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {array} domain.SeriesBook
+// @Failure 404 {object} map[string]string
+// @Router /series/{id}/books [get]
+func (h *SeriesHandler) GetSeriesBooks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	books, err := h.uc.BooksInOrder(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": books})
+}
+
+// CreateSeries godoc
+// @Summary Create a series
+// @Description Add an empty series. Books are added with PUT /series/{id}/books/{book_id}. Librarians only.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series body domain.Series true "Series data"
+// @Success 201 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Router /series [post]
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var series domain.Series
+
+	if err := c.ShouldBindJSON(&series); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := series.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreateSeries(series)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateSeries godoc
+// @Summary Update a series
+// @Description Update the name and description of a series. Its entries are unchanged. Librarians only.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param series body domain.Series true "Updated series data"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /series/{id} [put]
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var series domain.Series
+	if err := c.ShouldBindJSON(&series); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := series.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.uc.UpdateSeries(id, series)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "series updated"})
+}
+
+// DeleteSeries godoc
+// @Summary Delete a series
+// @Description Delete series by ID. Its books stay in the catalog. Librarians only.
+// @Tags Series
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /series/{id} [delete]
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteSeries(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "series deleted"})
+}
+
+// SetSeriesBook godoc
+// @Summary Place a book in a series
+// @Description Add a book to a series at a position, or move it if it is already part of it. Librarians only.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param book_id path int true "Book ID"
+// @Param position body SeriesPositionRequest true "Position in reading order"
+// @Success 200 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /series/{id}/books/{book_id} [put]
+func (h *SeriesHandler) SetSeriesBook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book id"})
+		return
+	}
+
+	var req SeriesPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	series, err := h.uc.SetPosition(id, bookID, req.Position)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// RemoveSeriesBook godoc
+// @Summary Remove a book from a series
+// @Description Take a book out of a series. Librarians only.
+// @Tags Series
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param book_id path int true "Book ID"
+// @Success 200 {object} domain.Series
+// @Failure 404 {object} map[string]string
+// @Router /series/{id}/books/{book_id} [delete]
+func (h *SeriesHandler) RemoveSeriesBook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid book id"})
+		return
+	}
+
+	series, err := h.uc.RemoveBook(id, bookID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// ReorderSeries godoc
+// @Summary Reorder a series
+// @Description Renumber a series 1, 2, 3... in the given order, which must list each of its books once. Librarians only.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param order body SeriesOrderRequest true "Book IDs in reading order"
+// @Success 200 {object} domain.Series
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /series/{id}/order [put]
+func (h *SeriesHandler) ReorderSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req SeriesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	series, err := h.uc.Reorder(id, req.BookIDs)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SLOHandler struct {
+	uc *usecase.SLOUsecase
+}
+
+func NewSLOHandler(uc *usecase.SLOUsecase) *SLOHandler {
+	return &SLOHandler{uc: uc}
+}
+
+// Track records how long each request took and its status against the
+// objective of its route, once it is answered.
+func (h *SLOHandler) Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		if route := c.FullPath(); route != "" {
+			h.uc.Record(c.Request.Method+" "+route, time.Since(start), c.Writer.Status(), start)
+		}
+	}
+}
+
+// GetStatus godoc
+// @Summary Get the status of the latency SLOs
+// @Description Get, per route with a latency objective, the share of good requests over the SLO window, the error budget left, the burn rates over 5m, 30m, 1h, 6h and 3d, and the alert they call for: page or ticket. A request is good when it is answered within the route's latency without a server error.
+// @Tags Health
+// @Produce json
+// @Success 200 {array} domain.SLOStatus
+// @Router /slo [get]
+func (h *SLOHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Status(time.Now())})
+}
+
+// GetMetrics godoc
+// @Summary Get the SLO status for Prometheus
+// @Description Get the same figures as GET /slo in the Prometheus text format, for an alerting system to scrape.
+// @Tags Health
+// @Produce plain
+// @Success 200 {string} string
+// @Router /slo/metrics [get]
+func (h *SLOHandler) GetMetrics(c *gin.Context) {
+	statuses := h.uc.Status(time.Now())
+	var b strings.Builder
+	metric := func(name, help string, value func(s domain.SLOStatus) float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, s := range statuses {
+			fmt.Fprintf(&b, "%s{route=%q} %g\n", name, s.Route, value(s))
+		}
+	}
+	metric("slo_objective_ratio", "Share of requests that must be good.", func(s domain.SLOStatus) float64 { return s.Objective / 100 })
+	metric("slo_requests", "Requests over the SLO window.", func(s domain.SLOStatus) float64 { return float64(s.Requests) })
+	metric("slo_good_requests", "Good requests over the SLO window.", func(s domain.SLOStatus) float64 { return float64(s.Good) })
+	metric("slo_error_budget_remaining_ratio", "Share of the error budget left.", func(s domain.SLOStatus) float64 { return s.BudgetRemaining })
+
+	b.WriteString("# HELP slo_burn_rate How many times faster than allowed the error budget is spent.\n# TYPE slo_burn_rate gauge\n")
+	for _, s := range statuses {
+		for _, w := range domain.BurnRateWindows {
+			fmt.Fprintf(&b, "slo_burn_rate{route=%q,window=%q} %g\n", s.Route, w.Name, s.BurnRates[w.Name])
+		}
+	}
+	b.WriteString("# HELP slo_alert Whether the burn rates call for an alert of the severity.\n# TYPE slo_alert gauge\n")
+	for _, s := range statuses {
+		for _, severity := range []string{domain.SLOAlertPage, domain.SLOAlertTicket} {
+			firing := 0
+			if s.Alert == severity {
+				firing = 1
+			}
+			fmt.Fprintf(&b, "slo_alert{route=%q,severity=%q} %d\n", s.Route, severity, firing)
+		}
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookVerifier checks that a callback really comes from the provider.
+type WebhookVerifier interface {
+	Verify(r *http.Request) error
+}
+
+type SMSHandler struct {
+	uc       *usecase.SMSUsecase
+	verifier WebhookVerifier
+}
+
+func NewSMSHandler(uc *usecase.SMSUsecase, verifier WebhookVerifier) *SMSHandler {
+	return &SMSHandler{uc: uc, verifier: verifier}
+}
+
+// GetSMSSettings godoc
+// @Summary Get the SMS settings
+// @Description Get the sender and message templates used for text messages to a tenant's members, which are the default ones unless the tenant has its own. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param tenant query string false "Tenant host name; the default settings when empty"
+// @Success 200 {object} domain.SMSSettings
+// @Router /admin/sms [get]
+func (h *SMSHandler) GetSMSSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetSettings(c.Query("tenant"))})
+}
+
+// UpdateSMSSettings godoc
+// @Summary Update the SMS settings
+// @Description Set the sender and the template for each notification kind, e.g. "Hi {{.Name}}, {{.Message}}", for one tenant's members or as the default. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant query string false "Tenant host name; the default settings when empty"
+// @Param settings body domain.SMSSettings true "SMS settings"
+// @Success 200 {object} domain.SMSSettings
+// @Failure 400 {object} map[string]string
+// @Router /admin/sms [put]
+func (h *SMSHandler) UpdateSMSSettings(c *gin.Context) {
+	var settings domain.SMSSettings
+
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := settings.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.uc.SetSettings(c.Query("tenant"), settings)
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetSettings(c.Query("tenant"))})
+}
+
+// ClearSMSSettings godoc
+// @Summary Remove a tenant's SMS settings
+// @Description Let texts to a tenant's members be sent with the default settings again. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param tenant query string true "Tenant host name"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /admin/sms [delete]
+func (h *SMSHandler) ClearSMSSettings(c *gin.Context) {
+	if err := h.uc.ClearSettings(c.Query("tenant")); err != nil {
+		abort(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSMSMessages godoc
+// @Summary List sent text messages
+// @Description List recent text messages with their delivery status, newest first. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Maximum number of messages (1-500, default 100)"
+// @Success 200 {array} domain.SMSMessage
+// @Failure 400 {object} map[string]string
+// @Router /admin/sms/messages [get]
+func (h *SMSHandler) GetSMSMessages(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Messages(limit)})
+}
+
+// SMSStatusCallback godoc
+// @Summary Receive an SMS delivery status
+// @Description Delivery status callback from the SMS provider. Requests must carry a valid provider signature.
+// @Tags Webhooks
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param MessageSid formData string true "Message ID"
+// @Param MessageStatus formData string true "Delivery status"
+// @Param ErrorCode formData string false "Provider error code"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhooks/sms/status [post]
+func (h *SMSHandler) SMSStatusCallback(c *gin.Context) {
+	if err := h.verifier.Verify(c.Request); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	id, status := c.PostForm("MessageSid"), c.PostForm("MessageStatus")
+	if id == "" || status == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "MessageSid and MessageStatus are required"})
+		return
+	}
+
+	if err := h.uc.UpdateStatus(id, status, c.PostForm("ErrorCode")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "status recorded"})
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler records detail-page views and serves the view statistics
+// used for collection development.
+type StatsHandler struct {
+	books      *usecase.BookUsecase
+	views      *usecase.ViewStatsUsecase
+	popularity *usecase.PopularityUsecase
+	weeding    *usecase.WeedingUsecase
+}
+
+func NewStatsHandler(books *usecase.BookUsecase, views *usecase.ViewStatsUsecase, popularity *usecase.PopularityUsecase, weeding *usecase.WeedingUsecase) *StatsHandler {
+	return &StatsHandler{books: books, views: views, popularity: popularity, weeding: weeding}
+}
+
+// RecordView godoc
+// @Summary Record a book view
+// @Description Beacon sent by front-ends when a book's detail page is shown. Counts towards daily view statistics and trending; no user data is stored.
+// @Tags Library
+// @Param id path int true "Book ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /books/{id}/view [post]
+func (h *StatsHandler) RecordView(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if _, err := h.books.GetBookByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+		return
+	}
+
+	now := time.Now()
+	h.views.Record(id, now)
+	h.popularity.Record(domain.BookEvent{BookID: id, Kind: domain.BookViewed, At: now})
+	c.Status(http.StatusNoContent)
+}
+
+// GetBookViews godoc
+// @Summary Get daily views of a book
+// @Description Get a book's views per day (UTC), including days without views. Librarians only.
+// @Tags Stats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Success 200 {array} domain.DailyViews
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /stats/books/{id}/views [get]
+func (h *StatsHandler) GetBookViews(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	from, to, err := dateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := h.views.Series(id, from, to)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// GetMostViewed godoc
+// @Summary Get the most viewed books
+// @Description Get books by total views over a date range, most viewed first. Librarians only.
+// @Tags Stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param limit query int false "Maximum books (default 20, max 500)"
+// @Success 200 {array} domain.BookViews
+// @Failure 400 {object} map[string]string
+// @Router /stats/views [get]
+func (h *StatsHandler) GetMostViewed(c *gin.Context) {
+	from, to, err := dateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = n
+	}
+
+	books, err := h.views.MostViewed(from, to, limit)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": books})
+}
+
+// GetWeedingReport godoc
+// @Summary Get the weeding report
+// @Description Get the books with copies in circulation that were published at least min_age years ago and borrowed at most max_loans times in the last months months, least borrowed first. Librarians only.
+// @Tags Stats
+// @Produce json
+// @Security BearerAuth
+// @Param min_age query int false "Minimum years since publication (default 10)"
+// @Param months query int false "Months of circulation to count (default 36)"
+// @Param max_loans query int false "Maximum loans in that period (default 0)"
+// @Success 200 {object} domain.WeedingReport
+// @Failure 400 {object} map[string]string
+// @Router /stats/weeding [get]
+func (h *StatsHandler) GetWeedingReport(c *gin.Context) {
+	criteria := domain.WeedingCriteria{
+		MinAge:   domain.DefaultWeedingMinAge,
+		Months:   domain.DefaultWeedingMonths,
+		MaxLoans: domain.DefaultWeedingMaxLoans,
+	}
+	params := []struct {
+		name  string
+		field *int
+	}{{"min_age", &criteria.MinAge}, {"months", &criteria.Months}, {"max_loans", &criteria.MaxLoans}}
+	for _, p := range params {
+		if v := c.Query(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": p.name + " must be a number"})
+				return
+			}
+			*p.field = n
+		}
+	}
+	if err := criteria.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.weeding.Report(criteria, time.Now())})
+}
+
+// dateRange reads the from and to query parameters, defaulting to the
+// 30 days ending today.
+func dateRange(c *gin.Context) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date like 2024-01-31")
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -29)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date like 2024-01-01")
+		}
+		from = t
+	}
+	return from, to, nil
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncRequest carries the changes a client made offline. Since is the
+// cursor of the client's last sync; the catalog changes after it are
+// returned once the client's changes are saved.
+type SyncRequest struct {
+	Strategy string                `json:"strategy"`
+	Since    string                `json:"since"`
+	Changes  []domain.ClientChange `json:"changes"`
+}
+
+// SyncResponse holds what became of each change, in the order sent, and
+// the catalog changes for the client to apply.
+type SyncResponse struct {
+	Results []domain.SyncResult `json:"results"`
+	Changes domain.BookChanges  `json:"changes"`
+}
+
+type SyncHandler struct {
+	uc    *usecase.SyncUsecase
+	books *usecase.BookUsecase
+}
+
+func NewSyncHandler(uc *usecase.SyncUsecase, books *usecase.BookUsecase) *SyncHandler {
+	return &SyncHandler{uc: uc, books: books}
+}
+
+// Sync godoc
+// @Summary Sync offline changes
+// @Description Save the books a client created, updated and deleted while offline, then return the catalog changes since the client's last sync. Each change names the revision of the book it was based on. Changes to books that were edited on the server since are resolved with the strategy: merge (default) keeps edits to different fields from both sides and reports a conflict, with the fields concerned, when both changed the same one; last_writer_wins keeps whichever side wrote last by changed_at. Conflicting changes are not saved. Librarians only.
+// @Tags Library
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sync body SyncRequest true "Offline changes"
+// @Success 200 {object} SyncResponse
+// @Failure 400 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /sync [post]
+func (h *SyncHandler) Sync(c *gin.Context) {
+	var req SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	// Check the cursor first, so an expired one does not leave the client
+	// unsure which of its changes were saved.
+	if _, err := h.books.Changes(req.Since); err != nil {
+		abort(c, err)
+		return
+	}
+
+	results, err := h.uc.Apply(c.Request.Host, req.Strategy, req.Changes)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	changes, err := h.books.Changes(req.Since)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": SyncResponse{Results: results, Changes: changes}})
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// taskLogPollInterval is how often a stream of task logs looks for new
+// lines.
+const taskLogPollInterval = 250 * time.Millisecond
+
+// TaskStatusHandler reports on the tasks of the background queue.
+type TaskStatusHandler struct {
+	uc *usecase.TaskUsecase
+}
+
+func NewTaskStatusHandler(uc *usecase.TaskUsecase) *TaskStatusHandler {
+	return &TaskStatusHandler{uc: uc}
+}
+
+// GetQueues godoc
+// @Summary Get the task queues
+// @Description Get, for each kind of task, how many of its tasks may run at once, how many are running and how many wait for a worker. Librarians only.
+// @Tags Background Task
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.TaskQueueStatus
+// @Router /tasks/queues [get]
+func (h *TaskStatusHandler) GetQueues(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Queues()})
+}
+
+// GetTypes godoc
+// @Summary List the task types
+// @Description List the registered task types, with the fields of the JSON payload each takes. Librarians only.
+// @Tags Background Task
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.TaskType
+// @Router /tasks/types [get]
+func (h *TaskStatusHandler) GetTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.Types()})
+}
+
+// Submit godoc
+// @Summary Queue a task
+// @Description Queue a task of a registered type. The body is its JSON payload, checked against the fields of the type; it may be left empty when no field is required. Librarians only.
+// @Tags Background Task
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Task type"
+// @Success 202 {object} domain.Task
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /tasks/types/{name} [post]
+func (h *TaskStatusHandler) Submit(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	task, err := h.uc.Submit(c.Param("name"), domain.TaskPriorityUser, payload)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": task})
+}
+
+// GetTask godoc
+// @Summary Get a task's status
+// @Description Get the status and progress of a queued task: items processed out of the total and how many failed. The result is included once it has completed. Librarians only.
+// @Tags Background Task
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} domain.Task
+// @Failure 404 {object} map[string]string
+// @Router /tasks/{id} [get]
+func (h *TaskStatusHandler) GetTask(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	task, err := h.uc.GetTaskByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": task})
+}
+
+// GetTaskErrors godoc
+// @Summary Download a task's error report
+// @Description Download the items a completed task could not process as CSV, with the line of each in the input and the reason. Librarians only.
+// @Tags Background Task
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {string} string "CSV with line and error columns"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /tasks/{id}/errors [get]
+func (h *TaskStatusHandler) GetTaskErrors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	rowErrors, err := h.uc.Errors(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="task-%d-errors.csv"`, id))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"line", "error"})
+	for _, e := range rowErrors {
+		w.Write([]string{strconv.Itoa(e.Line), e.Error})
+	}
+	w.Flush()
+}
+
+// GetTaskLogs godoc
+// @Summary Get a task's log
+// @Description Get the lines a task has logged, the oldest first, e.g. how far it got and why it was retried. Pass after to get only the lines after that line number. Ask for text/event-stream to follow the log while the task runs: each line is sent as a "log" event with the line number as its ID, and an "end" event with the task follows once it has finished. EventSource resumes after the Last-Event-ID it sends. Each task keeps its last 1000 lines. Librarians only.
+// @Tags Background Task
+// @Produce json
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param after query int false "Only lines after this line number"
+// @Success 200 {array} domain.TaskLogLine
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tasks/{id}/logs [get]
+func (h *TaskStatusHandler) GetTaskLogs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	after := 0
+	if v := c.DefaultQuery("after", c.GetHeader("Last-Event-ID")); v != "" {
+		after, err = strconv.Atoi(v)
+		if err != nil || after < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "after must be a line number"})
+			return
+		}
+	}
+
+	lines, task, err := h.uc.Logs(id, after)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		c.JSON(http.StatusOK, gin.H{"data": lines})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	ticker := time.NewTicker(taskLogPollInterval)
+	defer ticker.Stop()
+	for {
+		for _, l := range lines {
+			data, _ := json.Marshal(l)
+			fmt.Fprintf(c.Writer, "id: %d\nevent: log\ndata: %s\n\n", l.Line, data)
+			after = l.Line
+		}
+		if task.Finished() {
+			data, _ := json.Marshal(task)
+			fmt.Fprintf(c.Writer, "event: end\ndata: %s\n\n", data)
+			c.Writer.Flush()
+			return
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+		if lines, task, err = h.uc.Logs(id, after); err != nil {
+			// The task has been forgotten.
+			return
+		}
+	}
+}
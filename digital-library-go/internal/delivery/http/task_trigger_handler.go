@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskTriggerHandler lets external systems trigger registered task types
+// with an API key.
+type TaskTriggerHandler struct {
+	uc *usecase.TaskTriggerUsecase
+}
+
+func NewTaskTriggerHandler(uc *usecase.TaskTriggerUsecase) *TaskTriggerHandler {
+	return &TaskTriggerHandler{uc: uc}
+}
+
+// Trigger godoc
+// @Summary Trigger a task from an external system
+// @Description Queue a task of a registered type, e.g. from an external scheduler. The API key in X-API-Key needs the tasks:trigger scope, or tasks:trigger:<name>, and must belong to a librarian or admin. The body is the task's JSON payload, checked against the fields of the type. Each type may only be triggered so many times per period, whichever key triggers it.
+// @Tags Background Task
+// @Accept json
+// @Produce json
+// @Security APIKeyAuth
+// @Param name path string true "Task type"
+// @Success 202 {object} domain.Task
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /tasks/trigger/{name} [post]
+func (h *TaskTriggerHandler) Trigger(c *gin.Context) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-API-Key header required"})
+		return
+	}
+	key := value.(domain.APIKey)
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	task, err := h.uc.Trigger(key, c.Param("name"), payload, time.Now())
+	var limited *usecase.TriggerLimitError
+	if errors.As(err, &limited) {
+		c.Header("Retry-After", strconv.Itoa(int(limited.RetryAfter.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": task})
+}
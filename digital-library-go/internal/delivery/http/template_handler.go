@@ -0,0 +1,150 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateRequest is the body accepted when setting or previewing a
+// template.
+type TemplateRequest struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+type TemplateHandler struct {
+	uc *usecase.TemplateUsecase
+}
+
+func NewTemplateHandler(uc *usecase.TemplateUsecase) *TemplateHandler {
+	return &TemplateHandler{uc: uc}
+}
+
+// GetTemplates godoc
+// @Summary Get the templates
+// @Description Get the email, SMS and receipt templates admins have set, for every tenant. Kinds not listed use the built-in wording. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.MessageTemplate
+// @Router /admin/templates [get]
+func (h *TemplateHandler) GetTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.uc.GetTemplates()})
+}
+
+// GetTemplate godoc
+// @Summary Get a template
+// @Description Get the template a tenant uses for a kind of message, which may be the default or built-in one, with sample data showing the variables it can use. Admins only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param channel path string true "email, sms or receipt"
+// @Param kind path string true "Notification kind, or checkout or hold_slip for receipts"
+// @Param tenant query string false "Tenant host name; the default template when empty"
+// @Success 200 {object} domain.MessageTemplate
+// @Failure 404 {object} map[string]string
+// @Router /admin/templates/{channel}/{kind} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	channel, kind := c.Param("channel"), c.Param("kind")
+	t, err := h.uc.Template(c.Query("tenant"), channel, kind)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": t, "variables": h.uc.Sample(channel, kind)})
+}
+
+// SetTemplate godoc
+// @Summary Set a template
+// @Description Set the template for a kind of message, for one tenant or as the default. Text is a Go text/template; emails may also have a subject and an HTML version, and receipts an HTML version. Templates that do not parse or that use variables their kind does not have are refused. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param channel path string true "email, sms or receipt"
+// @Param kind path string true "Notification kind, or checkout or hold_slip for receipts"
+// @Param tenant query string false "Tenant host name; the default template when empty"
+// @Param template body TemplateRequest true "Template"
+// @Success 200 {object} domain.MessageTemplate
+// @Failure 400 {object} map[string]string
+// @Router /admin/templates/{channel}/{kind} [put]
+func (h *TemplateHandler) SetTemplate(c *gin.Context) {
+	t, ok := bindTemplate(c)
+	if !ok {
+		return
+	}
+
+	saved, err := h.uc.SetTemplate(t)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": saved})
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a template
+// @Description Remove a template set for a tenant or as the default, so the default or built-in wording applies again. Admins only.
+// @Tags Admin
+// @Security BearerAuth
+// @Param channel path string true "email, sms or receipt"
+// @Param kind path string true "Notification kind, or checkout or hold_slip for receipts"
+// @Param tenant query string false "Tenant host name; the default template when empty"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /admin/templates/{channel}/{kind} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.uc.DeleteTemplate(c.Query("tenant"), c.Param("channel"), c.Param("kind")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewTemplate godoc
+// @Summary Preview a template
+// @Description Render a template against sample data without saving it, checking it as setting it would. Admins only.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param channel path string true "email, sms or receipt"
+// @Param kind path string true "Notification kind, or checkout or hold_slip for receipts"
+// @Param template body TemplateRequest true "Template"
+// @Success 200 {object} domain.RenderedMessage
+// @Failure 400 {object} map[string]string
+// @Router /admin/templates/{channel}/{kind}/preview [post]
+func (h *TemplateHandler) PreviewTemplate(c *gin.Context) {
+	t, ok := bindTemplate(c)
+	if !ok {
+		return
+	}
+
+	rendered, err := h.uc.Preview(t)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rendered, "variables": h.uc.Sample(t.Channel, t.Kind)})
+}
+
+func bindTemplate(c *gin.Context) (domain.MessageTemplate, bool) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return domain.MessageTemplate{}, false
+	}
+	return domain.MessageTemplate{
+		Tenant:  c.Query("tenant"),
+		Channel: c.Param("channel"),
+		Kind:    c.Param("kind"),
+		Subject: req.Subject,
+		Text:    req.Text,
+		HTML:    req.HTML,
+	}, true
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrendingHandler struct {
+	uc     *usecase.PopularityUsecase
+	policy *usecase.ContentPolicyUsecase
+}
+
+func NewTrendingHandler(uc *usecase.PopularityUsecase, policy *usecase.ContentPolicyUsecase) *TrendingHandler {
+	return &TrendingHandler{uc: uc, policy: policy}
+}
+
+// GetTrending godoc
+// @Summary Get trending books
+// @Description Get the most viewed and borrowed books, with older activity decaying by the configured half-life. The ranking is recomputed every minute.
+// @Tags Library
+// @Produce json
+// @Param limit query int false "Maximum books (default 10, max 100)"
+// @Param audience query string false "all or children"
+// @Success 200 {object} domain.TrendingReport
+// @Failure 400 {object} map[string]string
+// @Router /books/trending [get]
+func (h *TrendingHandler) GetTrending(c *gin.Context) {
+	limit := 10
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		limit = n
+	}
+
+	// The ranking holds at most 100 books, so filtering it whole before
+	// cutting to limit still fills the page where possible.
+	report := h.uc.Trending(100)
+	audience := audienceOf(c)
+	report.Books = slices.DeleteFunc(report.Books, func(t domain.TrendingBook) bool {
+		return !h.policy.Allows(t.Book, audience)
+	})
+	report.Books = report.Books[:min(limit, len(report.Books))]
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
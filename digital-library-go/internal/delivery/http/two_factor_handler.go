@@ -0,0 +1,146 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorCodeRequest is the body accepted when confirming enrollment.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorVerifyRequest answers a login challenge.
+type TwoFactorVerifyRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+type TwoFactorHandler struct {
+	uc   *usecase.TwoFactorUsecase
+	auth *usecase.AuthUsecase
+}
+
+func NewTwoFactorHandler(uc *usecase.TwoFactorUsecase, auth *usecase.AuthUsecase) *TwoFactorHandler {
+	return &TwoFactorHandler{uc: uc, auth: auth}
+}
+
+// Enroll godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and provisioning URI for a librarian or admin account
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	secret, uri, err := h.uc.Enroll(currentMemberID(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"secret": secret, "provisioning_uri": uri})
+}
+
+// Confirm godoc
+// @Summary Confirm two-factor enrollment
+// @Description Verify a code from the authenticator app, enable two-factor login and return single-use recovery codes
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code body TwoFactorCodeRequest true "Current TOTP code"
+// @Success 200 {object} map[string][]string
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	codes, err := h.uc.Confirm(currentMemberID(c), req.Code)
+	if errors.Is(err, usecase.ErrTwoFactorEnabled) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		// A wrong code while enrolling is a bad request, not a failed
+		// login.
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// Verify godoc
+// @Summary Complete a two-factor login
+// @Description Answer the login challenge with a TOTP code or a recovery code to receive a bearer token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param verification body TwoFactorVerifyRequest true "Challenge and code"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/2fa/verify [post]
+func (h *TwoFactorHandler) Verify(c *gin.Context) {
+	var req TwoFactorVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	token, err := h.auth.VerifyLogin(req.Challenge, req.Code, c.ClientIP())
+	var throttled *usecase.ThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttled.Error()})
+		return
+	}
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// Reset godoc
+// @Summary Reset a member's two-factor authentication
+// @Description Remove the second factor of a locked-out user so they can log in with their password and enroll again
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Member ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/members/{id}/2fa/reset [post]
+func (h *TwoFactorHandler) Reset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.uc.Reset(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication reset"})
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VendorHandler struct {
+	uc *usecase.PurchasingUsecase
+}
+
+func NewVendorHandler(uc *usecase.PurchasingUsecase) *VendorHandler {
+	return &VendorHandler{uc: uc}
+}
+
+// GetVendors godoc
+// @Summary Get all vendors
+// @Description Get list of all vendors. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Vendor
+// @Router /vendors [get]
+func (h *VendorHandler) GetVendors(c *gin.Context) {
+	vendors := h.uc.GetVendors()
+	c.JSON(http.StatusOK, gin.H{"data": vendors})
+}
+
+// GetVendorByID godoc
+// @Summary Get a vendor by ID
+// @Description Get vendor details by ID. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Vendor ID"
+// @Success 200 {object} domain.Vendor
+// @Failure 404 {object} map[string]string
+// @Router /vendors/{id} [get]
+func (h *VendorHandler) GetVendorByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	vendor, err := h.uc.GetVendorByID(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": vendor})
+}
+
+// CreateVendor godoc
+// @Summary Create a vendor
+// @Description Add a supplier to order books from. Librarians only.
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param vendor body domain.Vendor true "Vendor data"
+// @Success 201 {object} domain.Vendor
+// @Failure 400 {object} map[string]string
+// @Router /vendors [post]
+func (h *VendorHandler) CreateVendor(c *gin.Context) {
+	var vendor domain.Vendor
+
+	if err := c.ShouldBindJSON(&vendor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := vendor.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created := h.uc.CreateVendor(vendor)
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
+
+// UpdateVendor godoc
+// @Summary Update a vendor
+// @Description Update vendor details by ID. Librarians only.
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Vendor ID"
+// @Param vendor body domain.Vendor true "Updated vendor data"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /vendors/{id} [put]
+func (h *VendorHandler) UpdateVendor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var vendor domain.Vendor
+	if err := c.ShouldBindJSON(&vendor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+
+	if err := vendor.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.uc.UpdateVendor(id, vendor); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "vendor not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "vendor updated"})
+}
+
+// DeleteVendor godoc
+// @Summary Delete a vendor
+// @Description Delete a vendor no purchase order was placed with. Librarians only.
+// @Tags Purchasing
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Vendor ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /vendors/{id} [delete]
+func (h *VendorHandler) DeleteVendor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	err = h.uc.DeleteVendor(id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "vendor deleted"})
+}
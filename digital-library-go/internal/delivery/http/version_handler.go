@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VersionHandler struct {
+	build domain.BuildInfo
+	flags *featureflag.Store
+}
+
+func NewVersionHandler(build domain.BuildInfo, flags *featureflag.Store) *VersionHandler {
+	return &VersionHandler{build: build, flags: flags}
+}
+
+// GetVersion godoc
+// @Summary Get the version of the server
+// @Description Get the semantic version of the server, the git commit and date it was built from, the Go version and platform it runs on, and the feature flags that are on for the host the request was sent to. For fleet management and bug reports.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /version [get]
+func (h *VersionHandler) GetVersion(c *gin.Context) {
+	features := []string{}
+	for name, on := range h.flags.For(c.Request.Host) {
+		if on {
+			features = append(features, name)
+		}
+	}
+	sort.Strings(features)
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"version":    h.build.Version,
+		"commit":     h.build.Commit,
+		"build_date": h.build.BuildDate,
+		"go_version": h.build.GoVersion,
+		"platform":   h.build.Platform,
+		"features":   features,
+	}})
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/pdf"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorklistHandler struct {
+	uc *usecase.WorklistUsecase
+}
+
+func NewWorklistHandler(uc *usecase.WorklistUsecase) *WorklistHandler {
+	return &WorklistHandler{uc: uc}
+}
+
+// GetPullList godoc
+// @Summary Get the pull list
+// @Description Get the copies to fetch from the shelves for holds, sorted by location. Librarians only.
+// @Tags Worklists
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.PullItem
+// @Router /worklists/pulls [get]
+func (h *WorklistHandler) GetPullList(c *gin.Context) {
+	items := h.uc.PullList()
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// GetPullListPDF godoc
+// @Summary Print the pull list
+// @Description Get the pull list as a PDF grouped by shelf location, ready to print. Librarians only.
+// @Tags Worklists
+// @Produce application/pdf
+// @Security BearerAuth
+// @Success 200 {file} file
+// @Router /worklists/pulls.pdf [get]
+func (h *WorklistHandler) GetPullListPDF(c *gin.Context) {
+	today := time.Now().Format(time.DateOnly)
+	doc := pdf.New("Pull list " + today)
+
+	items := h.uc.PullList()
+	if len(items) == 0 {
+		doc.Text("Nothing to pull.")
+	}
+	for i, item := range items {
+		if i == 0 || item.Location != items[i-1].Location {
+			location := item.Location
+			if location == "" {
+				location = "No copy on record"
+			}
+			doc.Heading(location)
+		}
+		doc.Text(fmt.Sprintf("[ ]  %-20s %-14s %s", item.CallNumber, item.Barcode, item.Title))
+		doc.Text(fmt.Sprintf("       Hold %d for %s (member %d), placed %s",
+			item.HoldID, item.MemberName, item.MemberID, item.PlacedAt.Format(time.DateOnly)))
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="pulls-%s.pdf"`, today))
+	c.Data(http.StatusOK, "application/pdf", doc.Bytes())
+}
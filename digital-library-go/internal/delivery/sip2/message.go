@@ -0,0 +1,137 @@
+package sip2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errMalformed = errors.New("sip2: malformed message")
+
+// fixedLength is the length of the fixed fields after the command code of
+// each message the server understands.
+var fixedLength = map[string]int{
+	"09": 37, // checkin
+	"11": 38, // checkout
+	"23": 21, // patron status
+	"29": 38, // renew
+	"35": 18, // end patron session
+	"93": 2,  // login
+	"97": 0,  // resend
+	"99": 8,  // SC status
+}
+
+// message is a request from a self-check machine: a two-digit command
+// code, fixed-length fields and variable fields such as AA<patron>|.
+// seq is the AY sequence number when the sender uses error detection.
+type message struct {
+	code   string
+	fixed  string
+	fields map[string]string
+	seq    string
+}
+
+func (m message) field(id string) string {
+	return m.fields[id]
+}
+
+// parse reads one message, without its terminating carriage return, and
+// checks its checksum if it has one.
+func parse(line string) (message, error) {
+	if len(line) < 2 {
+		return message{}, errMalformed
+	}
+	m := message{code: line[:2], fields: map[string]string{}}
+	n, ok := fixedLength[m.code]
+	if !ok {
+		return m, fmt.Errorf("sip2: unsupported message %s", m.code)
+	}
+	if len(line) < 2+n {
+		return m, errMalformed
+	}
+	m.fixed = line[2 : 2+n]
+	rest := line[2+n:]
+
+	// Error detection appends AY<seq>AZ<checksum>, the checksum covering
+	// everything before it.
+	if i := strings.LastIndex(rest, "AY"); i >= 0 && len(rest) == i+9 && rest[i+3:i+5] == "AZ" {
+		body := line[:len(line)-4]
+		if !strings.EqualFold(checksum(body), rest[i+5:]) {
+			return m, errMalformed
+		}
+		m.seq = rest[i+2 : i+3]
+		rest = rest[:i]
+	}
+	for _, f := range strings.Split(rest, "|") {
+		if len(f) >= 2 {
+			m.fields[f[:2]] = f[2:]
+		}
+	}
+	return m, nil
+}
+
+// response is a message to a self-check machine, built field by field.
+type response struct {
+	b strings.Builder
+}
+
+// newResponse starts a response with its command code and fixed fields.
+func newResponse(code string, fixed ...string) *response {
+	r := &response{}
+	r.b.WriteString(code)
+	for _, f := range fixed {
+		r.b.WriteString(f)
+	}
+	return r
+}
+
+// field appends a variable field. Separators in value are dropped, since
+// the protocol cannot escape them.
+func (r *response) field(id, value string) *response {
+	r.b.WriteString(id)
+	r.b.WriteString(strings.NewReplacer("|", "", "\r", "", "\n", "").Replace(value))
+	r.b.WriteByte('|')
+	return r
+}
+
+// bytes finishes the response, echoing the request's sequence number
+// with a checksum if it had one.
+func (r *response) bytes(seq string) []byte {
+	s := r.b.String()
+	if seq != "" {
+		s += "AY" + seq + "AZ"
+		s += checksum(s)
+	}
+	return []byte(s + "\r")
+}
+
+// checksum is the two's complement of the 16-bit sum of the bytes, in
+// four hex digits.
+func checksum(s string) string {
+	var sum uint16
+	for i := 0; i < len(s); i++ {
+		sum += uint16(s[i])
+	}
+	return fmt.Sprintf("%04X", -sum)
+}
+
+// timestamp formats t as YYYYMMDD, four spaces for local time, HHMMSS.
+func timestamp(t time.Time) string {
+	return t.Format("20060102    150405")
+}
+
+// flag is the 1 or 0 of an ok field.
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func yn(b bool) string {
+	if b {
+		return "Y"
+	}
+	return "N"
+}
@@ -0,0 +1,370 @@
+// Package sip2 lets self-check machines borrow, return and renew books
+// over SIP2, the 3M Standard Interchange Protocol version 2.00 most
+// kiosks speak over TCP. It supports login, SC status, patron status,
+// checkout, checkin, renew, end patron session and resend.
+package sip2
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/usecase"
+)
+
+// idleTimeout closes connections that send nothing for this long.
+// Machines send an SC status every few minutes while idle.
+const idleTimeout = 10 * time.Minute
+
+// maxMessageSize bounds a request line.
+const maxMessageSize = 4096
+
+// Config describes the library to the machines. Machines must log in with
+// LoginUser and LoginPassword before anything else.
+type Config struct {
+	Institution   string
+	LibraryName   string
+	LoginUser     string
+	LoginPassword string
+}
+
+type Server struct {
+	cfg     Config
+	members *usecase.MemberUsecase
+	books   *usecase.BookUsecase
+	copies  *usecase.CopyUsecase
+	loans   *usecase.LoanUsecase
+	holds   *usecase.HoldUsecase
+	fines   *usecase.FineUsecase
+	guard   *usecase.LoginGuardUsecase
+}
+
+func NewServer(cfg Config, members *usecase.MemberUsecase, books *usecase.BookUsecase, copies *usecase.CopyUsecase, loans *usecase.LoanUsecase, holds *usecase.HoldUsecase, fines *usecase.FineUsecase, guard *usecase.LoginGuardUsecase) *Server {
+	return &Server{cfg: cfg, members: members, books: books, copies: copies, loans: loans, holds: holds, fines: fines, guard: guard}
+}
+
+// ListenAndServe accepts machines on addr, e.g. ":6001", until the
+// listener fails.
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("SIP2: listening on %s", addr)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		go s.serve(conn)
+	}
+}
+
+// session is the state of one machine's connection.
+type session struct {
+	ip       string
+	loggedIn bool
+	last     []byte
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	sess := &session{ip: conn.RemoteAddr().String()}
+	if host, _, err := net.SplitHostPort(sess.ip); err == nil {
+		sess.ip = host
+	}
+	r := bufio.NewReaderSize(conn, maxMessageSize)
+	for {
+		conn.SetDeadline(time.Now().Add(idleTimeout))
+		line, err := r.ReadSlice('\r')
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Printf("SIP2: %s: %v", sess.ip, err)
+			}
+			return
+		}
+		// Some machines end messages with CR LF.
+		msg := strings.TrimLeft(strings.TrimSuffix(string(line), "\r"), "\n")
+		if msg == "" {
+			continue
+		}
+
+		m, err := parse(msg)
+		if err != nil {
+			// Ask for the message again, as the protocol has no way to
+			// report errors.
+			conn.Write([]byte("96\r"))
+			continue
+		}
+		if m.code == "97" {
+			if sess.last != nil {
+				conn.Write(sess.last)
+			}
+			continue
+		}
+		if !sess.loggedIn && m.code != "93" {
+			log.Printf("SIP2: %s sent %s before logging in", sess.ip, m.code)
+			return
+		}
+
+		out := s.handle(sess, m, time.Now()).bytes(m.seq)
+		sess.last = out
+		if _, err := conn.Write(out); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(sess *session, m message, now time.Time) *response {
+	switch m.code {
+	case "93":
+		return s.login(sess, m)
+	case "99":
+		return s.status(now)
+	case "23":
+		return s.patronStatus(sess, m, now)
+	case "11":
+		return s.checkout(sess, m, now)
+	case "09":
+		return s.checkin(m, now)
+	case "29":
+		return s.renew(sess, m, now)
+	default: // "35"
+		return newResponse("36", "Y", timestamp(now)).
+			field("AO", s.cfg.Institution).
+			field("AA", m.field("AA"))
+	}
+}
+
+// login checks the machine's own credentials, CN and CO.
+func (s *Server) login(sess *session, m message) *response {
+	ok := s.cfg.LoginUser != "" &&
+		subtle.ConstantTimeCompare([]byte(m.field("CN")), []byte(s.cfg.LoginUser)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(m.field("CO")), []byte(s.cfg.LoginPassword)) == 1
+	if ok {
+		sess.loggedIn = true
+	} else {
+		log.Printf("SIP2: failed login from %s as %q", sess.ip, m.field("CN"))
+	}
+	return newResponse("94", flag(ok))
+}
+
+// status answers the machine's SC status with what this server supports.
+func (s *Server) status(now time.Time) *response {
+	// Patron status, checkout, checkin, block patron, SC/ACS status,
+	// resend, login, patron information, end patron session, fee paid,
+	// item information, item status update, patron enable, hold, renew,
+	// renew all.
+	const supported = "YYYNYYYNYNNNNNYN"
+	return newResponse("98", "Y", "Y", "Y", "Y", "N", "N", "050", "003", timestamp(now), "2.00").
+		field("AO", s.cfg.Institution).
+		field("AM", s.cfg.LibraryName).
+		field("BX", supported)
+}
+
+// patron resolves the card in AA and checks the PIN in AD, the member's
+// password. The message is empty when the patron is valid.
+func (s *Server) patron(sess *session, m message) (domain.Member, string) {
+	card := m.field("AA")
+	member, err := s.members.GetMemberByCard(card)
+	if err != nil {
+		return domain.Member{}, "Card not recognised. Please see staff."
+	}
+	password := m.field("AD")
+	if password == "" {
+		return domain.Member{}, "Please enter your PIN."
+	}
+	if err := s.guard.Reserve(card, sess.ip); err != nil {
+		return domain.Member{}, "Too many attempts. Please try again later."
+	}
+	if _, err := s.members.Authenticate(card, password); err != nil {
+		s.guard.RecordFailure(card, sess.ip)
+		return domain.Member{}, "Incorrect PIN."
+	}
+	s.guard.RecordSuccess(card, sess.ip)
+	return member, ""
+}
+
+func (s *Server) patronStatus(sess *session, m message, now time.Time) *response {
+	member, problem := s.patron(sess, m)
+	valid := problem == ""
+	// Charge, renewal, recall and hold privileges denied, card lost, too
+	// many items charged, then eight flags this library does not track.
+	flags := []byte("              ")
+	if !valid {
+		copy(flags, "YYYY")
+	} else if plan, err := s.members.PlanFor(member.ID); err == nil && len(s.loans.ActiveLoansForMember(member.ID)) >= plan.MaxLoans {
+		flags[5] = 'Y'
+	}
+
+	r := newResponse("24", string(flags), "000", timestamp(now)).
+		field("AO", s.cfg.Institution).
+		field("AA", m.field("AA")).
+		field("AE", member.Name).
+		field("BL", yn(valid)).
+		field("CQ", yn(valid))
+	if owed := s.owed(member.ID); valid && owed > 0 {
+		r.field("BV", fmt.Sprintf("%d.%02d", owed/100, owed%100))
+	}
+	if problem != "" {
+		r.field("AF", problem)
+	}
+	return r
+}
+
+// owed sums the member's unpaid fines, in cents.
+func (s *Server) owed(memberID int) int {
+	owed := 0
+	for _, f := range s.fines.FinesForMember(memberID) {
+		if f.PaidAt == nil {
+			owed += f.Amount
+		}
+	}
+	return owed
+}
+
+// item resolves the barcode in AB to its copy and book.
+func (s *Server) item(m message) (domain.Copy, domain.Book, error) {
+	copy, err := s.copies.GetCopyByBarcode(m.field("AB"))
+	if err != nil {
+		return domain.Copy{}, domain.Book{}, err
+	}
+	book, err := s.books.GetBookByID(copy.BookID)
+	return copy, book, err
+}
+
+// checkout lends an item. Checking out an item the patron already has
+// renews it when the machine's renewal policy allows.
+func (s *Server) checkout(sess *session, m message, now time.Time) *response {
+	fail := func(msg, title string) *response {
+		return newResponse("12", "0", "N", "U", "N", timestamp(now)).
+			field("AO", s.cfg.Institution).
+			field("AA", m.field("AA")).
+			field("AB", m.field("AB")).
+			field("AJ", title).
+			field("AH", "").
+			field("AF", msg)
+	}
+
+	member, problem := s.patron(sess, m)
+	if problem != "" {
+		return fail(problem, "")
+	}
+	copy, book, err := s.item(m)
+	if err != nil {
+		return fail("Item not recognised. Please see staff.", "")
+	}
+	if copy.Withdrawn() {
+		return fail("This item cannot be borrowed. Please see staff.", book.Title)
+	}
+
+	renewal := false
+	loan, err := s.loans.Checkout(member.ID, book.ID)
+	if errors.Is(err, usecase.ErrBookOnLoan) && m.fixed[0] == 'Y' {
+		if current, ok := s.loans.ActiveLoanForBook(book.ID); ok && current.MemberID == member.ID {
+			loan, err = s.loans.Renew(current.ID)
+			renewal = true
+		}
+	}
+	if err != nil {
+		return fail(sentence(err), book.Title)
+	}
+	return newResponse("12", "1", yn(renewal), "N", "Y", timestamp(now)).
+		field("AO", s.cfg.Institution).
+		field("AA", m.field("AA")).
+		field("AB", m.field("AB")).
+		field("AJ", book.Title).
+		field("AH", timestamp(loan.DueAt))
+}
+
+// checkin returns an item. The alert asks the machine to set the item
+// aside when it is wanted for a hold.
+func (s *Server) checkin(m message, now time.Time) *response {
+	fail := func(msg, title string) *response {
+		return newResponse("10", "0", "Y", "U", "N", timestamp(now)).
+			field("AO", s.cfg.Institution).
+			field("AB", m.field("AB")).
+			field("AQ", "").
+			field("AJ", title).
+			field("AF", msg)
+	}
+
+	copy, book, err := s.item(m)
+	if err != nil {
+		return fail("Item not recognised. Please see staff.", "")
+	}
+	current, ok := s.loans.ActiveLoanForBook(book.ID)
+	if !ok {
+		return fail("This item is not on loan.", book.Title)
+	}
+	loan, err := s.loans.Return(current.ID)
+	if err != nil {
+		return fail(sentence(err), book.Title)
+	}
+
+	held := s.holds.Wanted(book.ID, 0)
+	r := newResponse("10", "1", "Y", "U", yn(held), timestamp(now)).
+		field("AO", s.cfg.Institution).
+		field("AB", m.field("AB")).
+		field("AQ", copy.Location).
+		field("AJ", book.Title)
+	if member, err := s.members.GetMemberByID(loan.MemberID); err == nil {
+		r.field("AA", member.CardNumber)
+	}
+	if held {
+		r.field("CV", "02").field("AF", "This item is on hold. Please leave it at the desk.")
+	}
+	return r
+}
+
+func (s *Server) renew(sess *session, m message, now time.Time) *response {
+	fail := func(msg, title string) *response {
+		return newResponse("30", "0", "N", "U", "N", timestamp(now)).
+			field("AO", s.cfg.Institution).
+			field("AA", m.field("AA")).
+			field("AB", m.field("AB")).
+			field("AJ", title).
+			field("AH", "").
+			field("AF", msg)
+	}
+
+	member, problem := s.patron(sess, m)
+	if problem != "" {
+		return fail(problem, "")
+	}
+	_, book, err := s.item(m)
+	if err != nil {
+		return fail("Item not recognised. Please see staff.", "")
+	}
+	current, ok := s.loans.ActiveLoanForBook(book.ID)
+	if !ok || current.MemberID != member.ID {
+		return fail("You do not have this item on loan.", book.Title)
+	}
+	loan, err := s.loans.Renew(current.ID)
+	if err != nil {
+		return fail(sentence(err), book.Title)
+	}
+	return newResponse("30", "1", "Y", "U", "N", timestamp(now)).
+		field("AO", s.cfg.Institution).
+		field("AA", m.field("AA")).
+		field("AB", m.field("AB")).
+		field("AJ", book.Title).
+		field("AH", timestamp(loan.DueAt))
+}
+
+// sentence turns a usecase error into a screen message.
+func sentence(err error) string {
+	msg := err.Error()
+	return strings.ToUpper(msg[:1]) + msg[1:] + "."
+}
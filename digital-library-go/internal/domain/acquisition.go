@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Acquisition statuses.
+const (
+	AcquisitionPending   = "pending_cataloging"
+	AcquisitionCataloged = "cataloged"
+)
+
+// MaxScanBatch is the most ISBNs one scan may hold.
+const MaxScanBatch = 100
+
+// Acquisition is a draft book record created from a scanned ISBN. It
+// stays out of the catalog until a librarian checks it and catalogs it
+// as a book. Provider names the metadata source that filled it in, and
+// is empty if none had a record.
+type Acquisition struct {
+	ID        int       `json:"id"`
+	ISBN      string    `json:"isbn"`
+	Title     string    `json:"title,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	Year      int       `json:"year,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Status    string    `json:"status"`
+	ScannedAt time.Time `json:"scanned_at"`
+	// BookID is the catalog book made from the draft, once there is one.
+	BookID int `json:"book_id,omitempty"`
+}
+
+// Fill copies the draft's fields into the empty fields of b.
+func (a Acquisition) Fill(b *Book) {
+	if b.ISBN == "" {
+		b.ISBN = a.ISBN
+	}
+	if b.Title == "" {
+		b.Title = a.Title
+	}
+	if b.Author == "" && len(b.AuthorIDs) == 0 {
+		b.Author = a.Author
+	}
+	if b.Year == 0 {
+		b.Year = a.Year
+	}
+}
+
+// ScanSummary reports what a scan did. Incomplete counts the drafts no
+// metadata source could fill in. Duplicates are ISBNs already in the
+// catalog or awaiting cataloging, and Invalid ones failed the check
+// digit.
+type ScanSummary struct {
+	Created    []Acquisition   `json:"created"`
+	Incomplete int             `json:"incomplete"`
+	Duplicates []ScanDuplicate `json:"duplicates"`
+	Invalid    []string        `json:"invalid"`
+}
+
+// ScanDuplicate names the book or draft a scanned ISBN already belongs
+// to.
+type ScanDuplicate struct {
+	ISBN          string `json:"isbn"`
+	BookID        int    `json:"book_id,omitempty"`
+	AcquisitionID int    `json:"acquisition_id,omitempty"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// ScopeTasksTrigger lets an API key trigger any registered task type;
+// ScopeTasksTrigger+":"+name only the type name.
+const ScopeTasksTrigger = "tasks:trigger"
+
+// APIKey lets a member's integration call the API. Requests made with a
+// key count against its quotas. The secret itself is only shown once, at
+// creation; Prefix identifies the key afterwards. Scopes grant the key
+// what the member's session alone does not allow.
+type APIKey struct {
+	ID         int       `json:"id"`
+	MemberID   int       `json:"member_id"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero"`
+}
+
+// ValidScope reports whether scope is one an API key may have.
+func ValidScope(scope string) bool {
+	name, ok := strings.CutPrefix(scope, ScopeTasksTrigger)
+	return ok && (name == "" || len(name) > 1 && name[0] == ':')
+}
+
+// CanTrigger reports whether the key may trigger tasks of a type.
+func (k *APIKey) CanTrigger(task string) bool {
+	return slices.Contains(k.Scopes, ScopeTasksTrigger) || slices.Contains(k.Scopes, ScopeTasksTrigger+":"+task)
+}
+
+// QuotaUsage reports how much of one quota an API key has used in the
+// current window. Class is "read" or "write" and Window is "day" or
+// "month"; windows reset at midnight UTC.
+type QuotaUsage struct {
+	Class     string    `json:"class"`
+	Window    string    `json:"window"`
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
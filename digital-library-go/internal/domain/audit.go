@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// AuditEvent records a security-relevant action for later review.
+type AuditEvent struct {
+	ID     int       `json:"id"`
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Actor  string    `json:"actor,omitempty"`
+	IP     string    `json:"ip,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type Author struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Biography string `json:"biography,omitempty"`
+	BirthYear int    `json:"birth_year,omitempty"`
+}
+
+func (a *Author) Validate() error {
+	if a.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if a.BirthYear < 0 || a.BirthYear > 2026 {
+		return errors.New("birth_year must be between 0 and 2026")
+	}
+	return nil
+}
+
+// Reasons authors are proposed as duplicates.
+const (
+	DuplicateSameName    = "same_name"
+	DuplicateSimilarName = "similar_name"
+)
+
+// AuthorDuplicates are the groups of authors the last duplicate scan
+// found. ScannedAt is nil until the first scan has run.
+type AuthorDuplicates struct {
+	ScannedAt *time.Time       `json:"scanned_at"`
+	Groups    []DuplicateGroup `json:"groups"`
+}
+
+// DuplicateGroup proposes merging authors that look like one person into
+// TargetID, the one with the most books. Reason is same_name when all the
+// names normalize to the same words, else similar_name.
+type DuplicateGroup struct {
+	TargetID int      `json:"target_id"`
+	Reason   string   `json:"reason"`
+	Authors  []Author `json:"authors"`
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// AuthorName is an author's name split into lower-case words without
+// accents or punctuation, given names first: "Rowling, J. K." and "J.K.
+// Rowling" both become given names "j", "k" and surname "rowling".
+type AuthorName struct {
+	Given   []string
+	Surname string
+}
+
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+func ParseAuthorName(name string) AuthorName {
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = first + " " + last
+	}
+	folded, _, err := transform.String(stripMarks, strings.ToLower(name))
+	if err != nil {
+		folded = strings.ToLower(name)
+	}
+	words := strings.FieldsFunc(folded, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	if len(words) == 0 {
+		return AuthorName{}
+	}
+	return AuthorName{Given: words[:len(words)-1], Surname: words[len(words)-1]}
+}
+
+// Key is the normalized name; authors with equal keys are the same.
+func (n AuthorName) Key() string {
+	return strings.Join(append(append([]string{}, n.Given...), n.Surname), " ")
+}
+
+// Similar reports whether two names probably belong to the same person:
+// the surnames match allowing a typo in longer ones, and the given names
+// agree as far as both spell them out, with an initial matching any name
+// it starts. "J. R. R. Tolkien", "John Ronald Reuel Tolkien" and "J.
+// Tolkein" are all similar.
+func (n AuthorName) Similar(other AuthorName) bool {
+	if n.Surname == "" || !similarWord(n.Surname, other.Surname) {
+		return false
+	}
+	if (len(n.Given) == 0) != (len(other.Given) == 0) {
+		return false
+	}
+	for i := range min(len(n.Given), len(other.Given)) {
+		a, b := n.Given[i], other.Given[i]
+		if len(a) == 1 || len(b) == 1 {
+			if a[0] != b[0] {
+				return false
+			}
+			continue
+		}
+		if !similarWord(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// similarWord allows one edit in words of five letters or more.
+func similarWord(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len([]rune(a)) < 5 || len([]rune(b)) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= 1
+}
+
+// editDistance counts insertions, deletions, substitutions and swaps of
+// adjacent letters, the usual typos in names ("Tolkein").
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	var before []int
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], before[j-2]+1)
+			}
+		}
+		before, prev = prev, cur
+	}
+	return prev[len(rb)]
+}
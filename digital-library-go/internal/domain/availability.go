@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Availability statuses of a book.
+const (
+	AvailabilityAvailable = "available"
+	AvailabilityOnLoan    = "on_loan"
+	AvailabilityHoldShelf = "on_hold_shelf"
+	AvailabilityWithdrawn = "withdrawn"
+	// AvailabilityNotHeld is the status of a book at a branch with no
+	// copy of it in circulation.
+	AvailabilityNotHeld = "not_held"
+	// AvailabilityInTransit is the status of a book at a branch whose
+	// only copies of it are on their way there.
+	AvailabilityInTransit = "in_transit"
+)
+
+// Availability says whether a book can be borrowed now and, if not, when
+// it is expected to be free for someone joining the hold queue. Books
+// with copies on record but none left in circulation are withdrawn.
+type Availability struct {
+	BookID    int        `json:"book_id"`
+	Status    string     `json:"status"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	HoldQueue int        `json:"hold_queue"`
+	// LateReturnRate is the share of past loans kept beyond their due
+	// date, which pushes the prediction back.
+	LateReturnRate float64 `json:"late_return_rate"`
+	// ExpectedRenewals is how many more times the current loan is
+	// expected to be renewed, going by how often past loans were.
+	ExpectedRenewals    float64    `json:"expected_renewals,omitempty"`
+	ExpectedAvailableAt *time.Time `json:"expected_available_at,omitempty"`
+}
@@ -1,24 +1,127 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Record statuses. Only published books are shown to patrons; drafts are
+// being cataloged and withdrawn books have left the collection.
+const (
+	BookDraft     = "draft"
+	BookPublished = "published"
+	BookWithdrawn = "withdrawn"
+)
+
+// Size limits for the long-form Markdown fields of a book.
+const (
+	MaxDescriptionLength     = 20000
+	MaxTableOfContentsLength = 10000
+)
+
+var ErrInvalidLanguage = errors.New("language must be an ISO 639-1 or 639-2 code, e.g. es or spa")
 
 type Book struct {
-	ID     int    `json:"id"`
-	Title  string `json:"title"`
-	Author string `json:"author"`
-	Year   int    `json:"year"`
-	ISBN   string `json:"isbn"`
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// Author is the display name of the book's authors. Books created
+	// before authors became entities only have this string; AuthorIDs
+	// links to the Author records.
+	Author    string `json:"author"`
+	AuthorIDs []int  `json:"author_ids,omitempty"`
+	Year      int    `json:"year"`
+	ISBN      string `json:"isbn"`
+	// Attributes holds values of the custom fields defined under
+	// /admin/fields.
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// AddedAt is when the book entered the catalog and Featured pins it
+	// to the new-arrival shelf. Both are set by the server, not clients.
+	AddedAt  time.Time `json:"added_at"`
+	Featured bool      `json:"featured"`
+	// AgeRating is the minimum recommended reader age in years. Zero
+	// means the book has not been rated.
+	AgeRating int `json:"age_rating,omitempty"`
+	// Language is an ISO 639-1 code such as "es". ISO 639-2 codes are
+	// accepted and stored as their two-letter equivalent where there is
+	// one.
+	Language string `json:"language,omitempty"`
+	// Description and TableOfContents are Markdown. They are returned as
+	// written unless the client asks for sanitized HTML.
+	Description     string `json:"description,omitempty"`
+	TableOfContents string `json:"table_of_contents,omitempty"`
+	// Format is print, audiobook, dvd or magazine; empty means print.
+	// The fields after it only apply to some formats.
+	Format string `json:"format,omitempty"`
+	// DurationMinutes is the running time of an audiobook or DVD.
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+	// Narrator is who reads an audiobook.
+	Narrator string `json:"narrator,omitempty"`
+	// Volume and IssueNumber identify an issue of a magazine; each issue
+	// is cataloged on its own.
+	Volume      int `json:"volume,omitempty"`
+	IssueNumber int `json:"issue_number,omitempty"`
+	// Status is draft, published or withdrawn. New books are published
+	// unless created as drafts.
+	Status string `json:"status"`
+	// Revision and UpdatedAt change with every write to the book and are
+	// set by the server. Sync clients send back the revision they edited.
+	Revision  uint64    `json:"revision"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Validate checks a book. Drafts may still lack a year and ISBN; they
+// are required once the book is published, except that DVDs and
+// magazines never need an ISBN.
 func (b *Book) Validate() error {
+	if b.Status != "" && b.Status != BookDraft && b.Status != BookPublished && b.Status != BookWithdrawn {
+		return errors.New("status must be draft, published or withdrawn")
+	}
+	draft := b.Status == BookDraft
 	if b.Title == "" {
 		return errors.New("title must not be empty")
 	}
-	if b.Year < 1000 || b.Year > 2026 {
+	if (b.Year < 1000 || b.Year > 2026) && !(draft && b.Year == 0) {
 		return errors.New("year must be between 1000 and 2026")
 	}
-	if len(b.ISBN) != 10 && len(b.ISBN) != 13 {
+	if len(b.ISBN) != 10 && len(b.ISBN) != 13 && !(b.ISBN == "" && (draft || !hasISBN(b.MediaFormat()))) {
 		return errors.New("isbn must be 10 or 13 characters")
 	}
+	if err := b.validateMedia(draft); err != nil {
+		return err
+	}
+	if b.AgeRating < 0 || b.AgeRating > MaxAgeRating {
+		return errors.New("age_rating must be between 0 and 18")
+	}
+	if b.Language != "" && !ValidLanguage(b.Language) {
+		return ErrInvalidLanguage
+	}
+	if len(b.Description) > MaxDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", MaxDescriptionLength)
+	}
+	if len(b.TableOfContents) > MaxTableOfContentsLength {
+		return fmt.Errorf("table_of_contents must be at most %d characters", MaxTableOfContentsLength)
+	}
 	return nil
 }
+
+// Published reports whether patrons may see the book.
+func (b *Book) Published() bool {
+	return b.Status == BookPublished
+}
+
+// MissingFields lists the descriptive fields that are still empty and
+// could be filled in from an external catalog.
+func (b *Book) MissingFields() []string {
+	missing := []string{}
+	if b.Title == "" {
+		missing = append(missing, "title")
+	}
+	if b.Author == "" {
+		missing = append(missing, "author")
+	}
+	if b.Year == 0 {
+		missing = append(missing, "year")
+	}
+	return missing
+}
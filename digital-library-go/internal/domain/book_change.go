@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Kinds of BookChange.
+const (
+	BookCreated = "created"
+	BookUpdated = "updated"
+	BookDeleted = "deleted"
+)
+
+// BookChange is an entry in the catalog's change log. Seq numbers the
+// entries in order.
+type BookChange struct {
+	Seq    uint64    `json:"seq"`
+	BookID int       `json:"book_id"`
+	Kind   string    `json:"kind"`
+	At     time.Time `json:"at"`
+}
+
+// BookChanges is what changed in the catalog after a sync cursor: the
+// current state of books created or updated since, and the IDs of books
+// deleted since. A book appears at most once. Cursor is an opaque token
+// passed back to fetch the changes that follow.
+type BookChanges struct {
+	Created []Book `json:"created"`
+	Updated []Book `json:"updated"`
+	Deleted []int  `json:"deleted"`
+	Cursor  string `json:"cursor"`
+}
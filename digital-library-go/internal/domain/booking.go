@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Resource kinds.
+const (
+	ResourceRoom      = "room"
+	ResourceEquipment = "equipment"
+)
+
+const (
+	// BookingSlot is the grid bookings start and end on.
+	BookingSlot = 15 * time.Minute
+	// MaxBookingLength is the longest a single booking may last.
+	MaxBookingLength = 4 * time.Hour
+)
+
+// Resource is a room or device members can book for a time slot.
+type Resource struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Capacity    int    `json:"capacity,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (r *Resource) Validate() error {
+	if r.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if !slices.Contains([]string{ResourceRoom, ResourceEquipment}, r.Kind) {
+		return errors.New("kind must be room or equipment")
+	}
+	if r.Capacity < 0 {
+		return errors.New("capacity must not be negative")
+	}
+	return nil
+}
+
+// Booking reserves a resource for a member from Start to End. Cancelled
+// bookings are kept with CancelledAt set.
+type Booking struct {
+	ID          int        `json:"id"`
+	ResourceID  int        `json:"resource_id"`
+	MemberID    int        `json:"member_id"`
+	Start       time.Time  `json:"start"`
+	End         time.Time  `json:"end"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+func (b *Booking) Validate() error {
+	if b.ResourceID == 0 {
+		return errors.New("resource_id is required")
+	}
+	if !b.Start.Truncate(BookingSlot).Equal(b.Start) || !b.End.Truncate(BookingSlot).Equal(b.End) {
+		return errors.New("start and end must be on the quarter hour")
+	}
+	if !b.End.After(b.Start) {
+		return errors.New("end must be after start")
+	}
+	if b.End.Sub(b.Start) > MaxBookingLength {
+		return errors.New("a booking may last at most 4 hours")
+	}
+	return nil
+}
+
+func (b *Booking) Active() bool {
+	return b.CancelledAt == nil
+}
+
+// Overlaps reports whether the booking shares any time with [start, end).
+func (b *Booking) Overlaps(start, end time.Time) bool {
+	return b.Start.Before(end) && start.Before(b.End)
+}
+
+// TimeRange is a span of time on the availability of a resource.
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ResourceAvailability is a resource's day: the library's hours and the
+// times already booked. Everything else within the hours is free.
+type ResourceAvailability struct {
+	ResourceID int         `json:"resource_id"`
+	Day        CalendarDay `json:"day"`
+	Booked     []TimeRange `json:"booked"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// DefaultNearbyRadius is how far from a member, in kilometres, branches
+// are looked for when no radius is given.
+const DefaultNearbyRadius = 10.0
+
+// earthRadius is the mean radius of the earth in kilometres.
+const earthRadius = 6371.0
+
+// Branch is a library building copies are kept at and lent from.
+// Latitude and Longitude place it on the map, in decimal degrees.
+type Branch struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (b *Branch) Validate() error {
+	if strings.TrimSpace(b.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	if strings.TrimSpace(b.Address) == "" {
+		return errors.New("address must not be empty")
+	}
+	return ValidateCoordinates(b.Latitude, b.Longitude)
+}
+
+// ValidateCoordinates checks that lat and lng are a latitude and a
+// longitude in decimal degrees.
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+// DistanceTo is the great-circle distance from the branch to a point, in
+// kilometres.
+func (b *Branch) DistanceTo(lat, lng float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat - b.Latitude) * rad
+	dLng := (lng - b.Longitude) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(b.Latitude*rad)*math.Cos(lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// BranchAvailability says whether a book can be borrowed at a branch.
+// Copies counts the copies in circulation kept there and InTransit those
+// on their way there from another branch. Status is the book's
+// availability when the branch has copies, AvailabilityInTransit when
+// copies are only on their way, and AvailabilityNotHeld otherwise.
+type BranchAvailability struct {
+	BookID    int    `json:"book_id"`
+	Copies    int    `json:"copies"`
+	InTransit int    `json:"in_transit"`
+	Status    string `json:"status"`
+}
+
+// AvailabilityAtBranch counts the copies of a book at one branch: those
+// in circulation kept there and those on their way there. Loans and
+// holds are for a book rather than a copy, so it does not say whether
+// the branch's copies are on the shelf.
+type AvailabilityAtBranch struct {
+	Branch    Branch `json:"branch"`
+	BookID    int    `json:"book_id"`
+	Copies    int    `json:"copies"`
+	InTransit int    `json:"in_transit"`
+}
+
+// NearbyBranch is a branch found near a point, with its distance from it
+// in kilometres and, when a book was asked about, the book's
+// availability there.
+type NearbyBranch struct {
+	Branch
+	Distance     float64             `json:"distance_km"`
+	Availability *BranchAvailability `json:"availability,omitempty"`
+}
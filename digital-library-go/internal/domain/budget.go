@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Periods the budget summary can group spending by.
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+	PeriodYear    = "year"
+)
+
+// ValidPeriod reports whether p is one of the budget periods.
+func ValidPeriod(p string) bool {
+	return p == PeriodMonth || p == PeriodQuarter || p == PeriodYear
+}
+
+// PeriodKey names the period t falls in: "2024-03" for a month, "2024-Q1"
+// for a quarter and "2024" for a year.
+func PeriodKey(period string, t time.Time) string {
+	switch period {
+	case PeriodMonth:
+		return t.Format("2006-01")
+	case PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())+2)/3)
+	}
+	return t.Format("2006")
+}
+
+// Spend is what was spent on copies in a period, or in total. Amounts
+// are in cents.
+type Spend struct {
+	Period string `json:"period,omitempty"`
+	Amount int    `json:"amount"`
+	Copies int    `json:"copies"`
+}
+
+// FundSpend is the spending charged to one fund. Copies bought without a
+// fund code are summed under an empty fund_code.
+type FundSpend struct {
+	FundCode string  `json:"fund_code"`
+	Amount   int     `json:"amount"`
+	Copies   int     `json:"copies"`
+	Periods  []Spend `json:"periods"`
+}
+
+// BudgetSummary sums the purchase prices of the copies bought from From
+// to To inclusive, per fund and period.
+type BudgetSummary struct {
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Period string      `json:"period"`
+	Amount int         `json:"amount"`
+	Copies int         `json:"copies"`
+	Funds  []FundSpend `json:"funds"`
+}
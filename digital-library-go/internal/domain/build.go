@@ -0,0 +1,12 @@
+package domain
+
+// BuildInfo identifies the build of the server: its semantic version, the
+// git commit it was built from, when it was built and the Go version it
+// runs on.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// OpeningHours are the regular hours of one weekday, as "15:04" times.
+// A weekday without opening hours is a regular closing day.
+type OpeningHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Opens   string       `json:"opens"`
+	Closes  string       `json:"closes"`
+}
+
+func (h *OpeningHours) Validate() error {
+	if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+		return errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	opens, err := time.Parse("15:04", h.Opens)
+	if err != nil {
+		return errors.New("opens must be a time like 09:00")
+	}
+	closes, err := time.Parse("15:04", h.Closes)
+	if err != nil {
+		return errors.New("closes must be a time like 18:00")
+	}
+	if !closes.After(opens) {
+		return errors.New("closes must be after opens")
+	}
+	return nil
+}
+
+// ClosedDay is a date the library is closed although it would normally
+// open, such as a public holiday.
+type ClosedDay struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (d *ClosedDay) Validate() error {
+	if _, err := time.Parse(time.DateOnly, d.Date); err != nil {
+		return errors.New("date must be a date like 2024-12-25")
+	}
+	return nil
+}
+
+// CalendarDay says whether the library is open on a date, and when.
+type CalendarDay struct {
+	Date   string `json:"date"`
+	Open   bool   `json:"open"`
+	Opens  string `json:"opens,omitempty"`
+	Closes string `json:"closes,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Call number schemes.
+const (
+	SchemeDewey = "dewey"
+	SchemeLC    = "lc"
+)
+
+var (
+	// deweyPattern matches e.g. "823.914 ROW" or "005.133 GOL 2015": a
+	// three-digit class with optional decimals, then cutters and a year.
+	deweyPattern = regexp.MustCompile(`^(\d{3}(?:\.\d+)?)((?:\s+[A-Za-z0-9.]+)*)$`)
+	// lcPattern matches Library of Congress numbers such as
+	// "PR6068.O93 H37 1997" or "QA76.73.G63 D66 2015": one to three class
+	// letters, a class number, then cutters and an optional year.
+	lcPattern = regexp.MustCompile(`^([A-Z]{1,3})\s?(\d{1,4}(?:\.\d+)?)((?:\s*\.?[A-Z]\d+[A-Za-z]?)*(?:\s+\d{4}[a-z]?)?)$`)
+)
+
+var ErrInvalidCallNumber = errors.New("call_number must be a Dewey (e.g. 823.914 ROW) or Library of Congress (e.g. PR6068.O93 H37 1997) call number")
+
+// CallNumberScheme tells which classification a call number follows.
+func CallNumberScheme(callNumber string) (string, error) {
+	switch {
+	case deweyPattern.MatchString(callNumber):
+		return SchemeDewey, nil
+	case lcPattern.MatchString(callNumber):
+		return SchemeLC, nil
+	}
+	return "", ErrInvalidCallNumber
+}
+
+// CompareCallNumbers orders call numbers the way they stand on the
+// shelf: Dewey before LC, class numbers by value, and cutters as
+// decimals, so "H37" comes before "H4". Empty and invalid call numbers
+// sort last.
+func CompareCallNumbers(a, b string) int {
+	ka, kb := shelfKey(a), shelfKey(b)
+	return cmp.Or(
+		cmp.Compare(ka.rank, kb.rank),
+		strings.Compare(ka.class, kb.class),
+		cmp.Compare(ka.number, kb.number),
+		strings.Compare(ka.rest, kb.rest),
+		strings.Compare(a, b),
+	)
+}
+
+type callNumberKey struct {
+	rank   int
+	class  string
+	number float64
+	rest   string
+}
+
+func shelfKey(s string) callNumberKey {
+	if m := deweyPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		return callNumberKey{rank: 0, number: n, rest: cutters(m[2])}
+	}
+	if m := lcPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.ParseFloat(m[2], 64)
+		return callNumberKey{rank: 1, class: m[1], number: n, rest: cutters(m[3])}
+	}
+	return callNumberKey{rank: 2}
+}
+
+// cutters normalizes the part after the class number so that comparing
+// it as a string orders cutters as decimals.
+func cutters(s string) string {
+	fields := strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool { return r == ' ' || r == '.' })
+	return strings.Join(fields, " ")
+}
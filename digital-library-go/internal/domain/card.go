@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// CardPrefix marks library card numbers, keeping them apart from ISBNs
+// and other barcodes scanned at the desk.
+const CardPrefix = "2"
+
+const cardLength = 14
+
+// NewCardNumber returns a random card number ending in a Luhn check
+// digit. Uniqueness is the caller's concern.
+func NewCardNumber() string {
+	body := fmt.Sprintf("%s%012d", CardPrefix, rand.Int64N(1e12))
+	return body + string(rune('0'+luhnCheckDigit(body)))
+}
+
+// ValidCardNumber reports whether n is well formed and its check digit
+// matches, so typos can be rejected before hitting the store.
+func ValidCardNumber(n string) bool {
+	if len(n) != cardLength || n[:len(CardPrefix)] != CardPrefix {
+		return false
+	}
+	for _, r := range n {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return int(n[len(n)-1]-'0') == luhnCheckDigit(n[:len(n)-1])
+}
+
+func luhnCheckDigit(body string) int {
+	sum := 0
+	double := true
+	for i := len(body) - 1; i >= 0; i-- {
+		d := int(body[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Kinds of CatalogCommand.
+const (
+	CommandUpsert = "book.upsert"
+	CommandDelete = "book.delete"
+)
+
+// CatalogCommand asks the library to change a book, sent through a
+// message queue by a system that owns the catalog, such as a central
+// cataloging service. An upsert creates the book with BookID or replaces
+// it; a delete removes it if it exists. ID identifies the command, so one
+// delivered twice is applied once.
+type CatalogCommand struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	BookID int    `json:"book_id"`
+	Book   *Book  `json:"book,omitempty"`
+}
+
+func (c *CatalogCommand) Validate() error {
+	if c.ID == "" {
+		return errors.New("id is required")
+	}
+	if c.BookID <= 0 {
+		return errors.New("book_id must be positive")
+	}
+	switch c.Type {
+	case CommandUpsert:
+		if c.Book == nil {
+			return errors.New("book is required to upsert")
+		}
+		if c.Book.ID != 0 && c.Book.ID != c.BookID {
+			return errors.New("book.id does not match book_id")
+		}
+	case CommandDelete:
+	default:
+		return errors.New("type must be book.upsert or book.delete")
+	}
+	return nil
+}
+
+// DeadLetter is a queued message that could not be applied, set aside
+// with the reason so it can be fixed and sent again. Message is the
+// message as received.
+type DeadLetter struct {
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// CatalogEvent tells systems outside the library, such as search indexers
+// and data warehouses, that a book changed. Type is "book." followed by
+// the change kind, e.g. book.updated. Book is the book as it was when
+// the event was sent, and is absent for deletions. Events may be
+// delivered more than once; ID tells repeats apart.
+type CatalogEvent struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	BookID int       `json:"book_id"`
+	At     time.Time `json:"at"`
+	Book   *Book     `json:"book,omitempty"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ImportFields are the book fields a catalog import can read from a
+// column.
+var ImportFields = []string{"id", "title", "author", "isbn", "year", "language", "age_rating", "description", "status"}
+
+// ColumnMapping names the column of a catalog file each book field is
+// read from, e.g. {"title": "Book Title", "year": "Published"}. Fields
+// left out stay empty.
+type ColumnMapping map[string]string
+
+// Validate checks the mapping against the columns of the file: every
+// field must be one of ImportFields, every column must exist, and the
+// title must be mapped.
+func (m ColumnMapping) Validate(columns []string) error {
+	if m["title"] == "" {
+		return errors.New("mapping must name the title column")
+	}
+	fields := make([]string, 0, len(m))
+	for field := range m {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if !slices.Contains(ImportFields, field) {
+			return fmt.Errorf("cannot import into %q; fields are %v", field, ImportFields)
+		}
+		if !slices.Contains(columns, m[field]) {
+			return fmt.Errorf("column %q mapped to %s is not in the file", m[field], field)
+		}
+	}
+	return nil
+}
+
+// ImportPreview describes a catalog file before it is imported: its
+// columns, the first rows and the mapping guessed from the column names.
+type ImportPreview struct {
+	Delimiter string        `json:"delimiter"`
+	Columns   []string      `json:"columns"`
+	Rows      int           `json:"rows"`
+	Samples   [][]string    `json:"samples"`
+	Suggested ColumnMapping `json:"suggested_mapping"`
+}
+
+// CatalogRow is a book read from one row of a catalog file. Error says
+// why the row could not be read, in which case Book is incomplete.
+type CatalogRow struct {
+	Line  int
+	Book  Book
+	Error string
+}
+
+// CatalogImportResult lists the IDs of the books a catalog import
+// created. Books without an id column are numbered after the highest ID
+// in the catalog.
+type CatalogImportResult struct {
+	Created []int `json:"created"`
+}
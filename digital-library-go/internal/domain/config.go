@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Levels of the request log: info logs every request, warn only those
+// answered with a 4xx or 5xx status, and error only 5xx.
+const (
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// RateLimit is how many requests of a class, read or write, an API key
+// may make per UTC day and per calendar month.
+type RateLimit struct {
+	Daily   int `json:"daily"`
+	Monthly int `json:"monthly"`
+}
+
+// RuntimeConfig is the configuration that can be reloaded without a
+// restart. CORSOrigins are the origins browsers may call the API from,
+// "*" for any; FeatureFlags is a spec as FEATURE_FLAGS takes it.
+type RuntimeConfig struct {
+	LogLevel     string               `json:"log_level"`
+	CORSOrigins  []string             `json:"cors_origins"`
+	RateLimits   map[string]RateLimit `json:"rate_limits"`
+	FeatureFlags string               `json:"feature_flags"`
+}
+
+// ConfigChange is a setting that a reload changed, with its values
+// before and after.
+type ConfigChange struct {
+	Setting string `json:"setting"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// Validate checks the settings that can be checked on their own.
+func (c *RuntimeConfig) Validate() error {
+	if !slices.Contains([]string{LogLevelInfo, LogLevelWarn, LogLevelError}, c.LogLevel) {
+		return Invalid("log_level must be info, warn or error")
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") || strings.HasSuffix(origin, "/") {
+			return Invalid(fmt.Sprintf("cors origin %q must be * or a scheme and host, e.g. https://library.example.org", origin))
+		}
+	}
+	for class, limit := range c.RateLimits {
+		if limit.Daily <= 0 || limit.Monthly <= 0 {
+			return Invalid(fmt.Sprintf("rate limit %s must allow a positive number of requests per day and month", class))
+		}
+	}
+	return nil
+}
+
+// Diff lists the settings that differ in next, in a fixed order.
+func (c *RuntimeConfig) Diff(next RuntimeConfig) []ConfigChange {
+	changes := []ConfigChange{}
+	add := func(setting, old, new string) {
+		if old != new {
+			changes = append(changes, ConfigChange{Setting: setting, Old: old, New: new})
+		}
+	}
+	add("log_level", c.LogLevel, next.LogLevel)
+	add("cors_origins", strings.Join(c.CORSOrigins, ","), strings.Join(next.CORSOrigins, ","))
+	classes := map[string]bool{}
+	for class := range c.RateLimits {
+		classes[class] = true
+	}
+	for class := range next.RateLimits {
+		classes[class] = true
+	}
+	for _, class := range slices.Sorted(maps.Keys(classes)) {
+		old, new := c.RateLimits[class], next.RateLimits[class]
+		add("rate_limits."+class+".daily", strconv.Itoa(old.Daily), strconv.Itoa(new.Daily))
+		add("rate_limits."+class+".monthly", strconv.Itoa(old.Monthly), strconv.Itoa(new.Monthly))
+	}
+	add("feature_flags", c.FeatureFlags, next.FeatureFlags)
+	return changes
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "errors"
+
+// Audiences a catalog listing can be restricted to.
+const (
+	AudienceAll      = "all"
+	AudienceChildren = "children"
+)
+
+// MaxAgeRating is the highest age a book can be rated for.
+const MaxAgeRating = 18
+
+// ValidAudience reports whether audience is one listings can be
+// restricted to.
+func ValidAudience(audience string) bool {
+	return audience == AudienceAll || audience == AudienceChildren
+}
+
+// ContentPolicy decides which books children see. Patrons of any age
+// only ever see published books. It is set once for the
+// whole library by an admin.
+type ContentPolicy struct {
+	// ChildrenMaxAge is the highest age rating shown to children.
+	ChildrenMaxAge int `json:"children_max_age"`
+	// HideUnrated also hides books without an age rating from children.
+	HideUnrated bool `json:"hide_unrated"`
+}
+
+func (p *ContentPolicy) Validate() error {
+	if p.ChildrenMaxAge < 0 || p.ChildrenMaxAge > MaxAgeRating {
+		return errors.New("children_max_age must be between 0 and 18")
+	}
+	return nil
+}
+
+// Allows reports whether the book may be shown to the audience. Books
+// that are not published are shown to no one.
+func (p *ContentPolicy) Allows(b Book, audience string) bool {
+	if !b.Published() {
+		return false
+	}
+	if audience != AudienceChildren {
+		return true
+	}
+	if b.AgeRating == 0 {
+		return !p.HideUnrated
+	}
+	return b.AgeRating <= p.ChildrenMaxAge
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Reasons a copy is withdrawn from the collection.
+const (
+	WithdrawnDamaged        = "damaged"
+	WithdrawnLost           = "lost"
+	WithdrawnOutdated       = "outdated"
+	WithdrawnSuperseded     = "superseded"
+	WithdrawnLowCirculation = "low_circulation"
+	WithdrawnDuplicate      = "duplicate"
+)
+
+var withdrawalReasons = []string{
+	WithdrawnDamaged, WithdrawnLost, WithdrawnOutdated,
+	WithdrawnSuperseded, WithdrawnLowCirculation, WithdrawnDuplicate,
+}
+
+// ValidWithdrawalReason reports whether reason is one of the reason codes.
+func ValidWithdrawalReason(reason string) bool {
+	return slices.Contains(withdrawalReasons, reason)
+}
+
+// Copy is one physical item of a book on the shelves, identified by the
+// barcode on its label. BranchID is the branch it belongs to, if the
+// library has branches. Floor, Section and Shelf say where it is kept;
+// Location joins them as "floor/section/shelf" and is set by the server,
+// as is the scheme of the call number. Price, Vendor and FundCode record
+// what the copy cost, where it was bought and which budget paid for it;
+// Price is in cents.
+type Copy struct {
+	ID               int        `json:"id"`
+	BookID           int        `json:"book_id"`
+	Barcode          string     `json:"barcode"`
+	BranchID         int        `json:"branch_id,omitempty"`
+	Floor            string     `json:"floor"`
+	Section          string     `json:"section"`
+	Shelf            string     `json:"shelf,omitempty"`
+	CallNumber       string     `json:"call_number,omitempty"`
+	CallNumberScheme string     `json:"call_number_scheme,omitempty"`
+	Location         string     `json:"location"`
+	Price            int        `json:"price,omitempty"`
+	Vendor           string     `json:"vendor,omitempty"`
+	FundCode         string     `json:"fund_code,omitempty"`
+	PurchasedAt      *time.Time `json:"purchased_at,omitempty"`
+	// Withdrawal is set by the server when the copy is weeded from the
+	// collection. Withdrawn copies stay on record but cannot be lent.
+	Withdrawal *Withdrawal `json:"withdrawal,omitempty"`
+	// Reserve is set by the server while the copy is on reserve for a
+	// course, through /courses/:id/copies.
+	Reserve *CopyReserve `json:"reserve,omitempty"`
+	// Transfer is set by the server while the copy is on its way to
+	// another branch. BranchID stays the branch it left until it is
+	// received.
+	Transfer *CopyTransfer `json:"transfer,omitempty"`
+}
+
+// CopyTransfer records a copy sent from its branch to another one.
+// HoldID is set when it was sent to fill a hold picked up there.
+type CopyTransfer struct {
+	ToBranchID int       `json:"to_branch_id"`
+	HoldID     int       `json:"hold_id,omitempty"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// InTransit reports whether the copy is on its way to another branch.
+func (c *Copy) InTransit() bool {
+	return c.Transfer != nil
+}
+
+// Withdrawal records why and when a copy was taken out of circulation.
+type Withdrawal struct {
+	Reason      string    `json:"reason"`
+	Note        string    `json:"note,omitempty"`
+	WithdrawnAt time.Time `json:"withdrawn_at"`
+}
+
+// Withdrawn reports whether the copy has been taken out of circulation.
+func (c *Copy) Withdrawn() bool {
+	return c.Withdrawal != nil
+}
+
+func (c *Copy) Validate() error {
+	if c.BookID == 0 {
+		return errors.New("book_id is required")
+	}
+	if c.Barcode == "" {
+		return errors.New("barcode must not be empty")
+	}
+	if c.BranchID < 0 {
+		return errors.New("branch_id must not be negative")
+	}
+	if c.Floor == "" || c.Section == "" {
+		return errors.New("floor and section must not be empty")
+	}
+	for _, part := range []string{c.Floor, c.Section, c.Shelf} {
+		if strings.Contains(part, "/") {
+			return errors.New("floor, section and shelf must not contain /")
+		}
+	}
+	if c.CallNumber != "" {
+		if _, err := CallNumberScheme(c.CallNumber); err != nil {
+			return err
+		}
+	}
+	if c.Price < 0 {
+		return errors.New("price must not be negative")
+	}
+	if strings.ContainsAny(c.FundCode, " /") {
+		return errors.New("fund_code must not contain spaces or /")
+	}
+	return nil
+}
+
+// Normalize fills in the fields derived from the others. Call it after
+// Validate.
+func (c *Copy) Normalize() {
+	parts := []string{c.Floor, c.Section}
+	if c.Shelf != "" {
+		parts = append(parts, c.Shelf)
+	}
+	c.Location = strings.Join(parts, "/")
+	c.CallNumberScheme, _ = CallNumberScheme(c.CallNumber)
+}
+
+// InLocation reports whether the copy is kept within location, given as
+// a whole "floor", "floor/section" or "floor/section/shelf" path. An
+// empty location contains every copy.
+func (c *Copy) InLocation(location string) bool {
+	location = strings.Trim(location, "/")
+	return location == "" ||
+		strings.EqualFold(c.Location, location) ||
+		strings.HasPrefix(strings.ToLower(c.Location), strings.ToLower(location)+"/")
+}
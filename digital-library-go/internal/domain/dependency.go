@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Circuit breaker states of an external dependency.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// DependencyHealth describes how calls to one external service have been
+// going. While State is open, calls fail immediately until OpenUntil.
+type DependencyHealth struct {
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	Failures    int       `json:"consecutive_failures"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastFailure time.Time `json:"last_failure,omitzero"`
+	OpenUntil   time.Time `json:"open_until,omitzero"`
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+)
+
+// Edition formats.
+const (
+	FormatHardcover = "hardcover"
+	FormatPaperback = "paperback"
+	FormatEbook     = "ebook"
+)
+
+var editionFormats = []string{FormatHardcover, FormatPaperback, FormatEbook}
+
+type Publisher struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+}
+
+func (p *Publisher) Validate() error {
+	if p.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	return nil
+}
+
+// Edition is one published form of a book, which stands for the work
+// as a whole.
+type Edition struct {
+	ID          int    `json:"id"`
+	BookID      int    `json:"book_id"`
+	PublisherID int    `json:"publisher_id"`
+	Year        int    `json:"year"`
+	Format      string `json:"format"`
+	PageCount   int    `json:"page_count,omitempty"`
+	ISBN        string `json:"isbn,omitempty"`
+}
+
+func (e *Edition) Validate() error {
+	if e.BookID == 0 {
+		return errors.New("book_id is required")
+	}
+	if e.PublisherID == 0 {
+		return errors.New("publisher_id is required")
+	}
+	if e.Year < 1000 || e.Year > 2026 {
+		return errors.New("year must be between 1000 and 2026")
+	}
+	if !slices.Contains(editionFormats, e.Format) {
+		return errors.New("format must be hardcover, paperback or ebook")
+	}
+	if e.PageCount < 0 {
+		return errors.New("page_count must not be negative")
+	}
+	if e.ISBN != "" && len(e.ISBN) != 10 && len(e.ISBN) != 13 {
+		return errors.New("isbn must be 10 or 13 characters")
+	}
+	return nil
+}
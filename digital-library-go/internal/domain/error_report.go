@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Error report levels. A panic is fatal to the request it happened in;
+// other server errors are answered normally and reported as errors.
+const (
+	ReportError = "error"
+	ReportFatal = "fatal"
+)
+
+// ErrorReport describes a request that panicked or failed with a server
+// error, for an error tracker such as Sentry. Type is the Go type of the
+// error or panic value, and Stack, innermost call first, is where it
+// panicked; errors that did not panic have no stack.
+type ErrorReport struct {
+	Level     string       `json:"level"`
+	Message   string       `json:"message"`
+	Type      string       `json:"type"`
+	Stack     []StackFrame `json:"stack,omitempty"`
+	Method    string       `json:"method"`
+	Path      string       `json:"path"`
+	Route     string       `json:"route,omitempty"`
+	Status    int          `json:"status"`
+	ClientIP  string       `json:"client_ip,omitempty"`
+	UserAgent string       `json:"user_agent,omitempty"`
+	MemberID  int          `json:"member_id,omitempty"`
+	Time      time.Time    `json:"time"`
+}
+
+// StackFrame is one call in the stack of a panic.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
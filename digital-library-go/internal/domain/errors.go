@@ -0,0 +1,89 @@
+package domain
+
+import "errors"
+
+// Kinds of error. Errors returned by the usecases match one of these with
+// errors.Is, which is how the HTTP layer picks the status to answer with:
+// ErrNotFound is 404, ErrInvalid 400, ErrConflict 409, ErrGone 410,
+// ErrUnauthorized 401, ErrForbidden 403 and ErrUnavailable 503. Errors of
+// no kind are unexpected and answered with 500.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalid      = errors.New("invalid")
+	ErrConflict     = errors.New("conflict")
+	ErrGone         = errors.New("gone")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnavailable  = errors.New("unavailable")
+)
+
+// Error is an error of a kind, e.g. a member that was not found. It reads
+// as the error it wraps and matches both that error and its kind with
+// errors.Is.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Wrap marks err as being of a kind, keeping its message, e.g. to make a
+// plan that was not found an invalid plan_id. It returns nil when err is
+// nil.
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of err, that of the outermost Error it wraps,
+// or nil for an error of no kind.
+func KindOf(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return nil
+}
+
+// NotFound returns an ErrNotFound error with the given text.
+func NotFound(text string) error {
+	return Wrap(ErrNotFound, errors.New(text))
+}
+
+// Invalid returns an ErrInvalid error with the given text.
+func Invalid(text string) error {
+	return Wrap(ErrInvalid, errors.New(text))
+}
+
+// Conflict returns an ErrConflict error with the given text.
+func Conflict(text string) error {
+	return Wrap(ErrConflict, errors.New(text))
+}
+
+// Gone returns an ErrGone error with the given text.
+func Gone(text string) error {
+	return Wrap(ErrGone, errors.New(text))
+}
+
+// Unauthorized returns an ErrUnauthorized error with the given text.
+func Unauthorized(text string) error {
+	return Wrap(ErrUnauthorized, errors.New(text))
+}
+
+// Forbidden returns an ErrForbidden error with the given text.
+func Forbidden(text string) error {
+	return Wrap(ErrForbidden, errors.New(text))
+}
+
+// Unavailable returns an ErrUnavailable error with the given text.
+func Unavailable(text string) error {
+	return Wrap(ErrUnavailable, errors.New(text))
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Registration statuses.
+const (
+	RegistrationConfirmed  = "registered"
+	RegistrationWaitlisted = "waitlisted"
+)
+
+// EventReminderLead is how long before an event registered members are
+// reminded of it.
+const EventReminderLead = 24 * time.Hour
+
+// Event is a library program such as story time or an author talk.
+// Registered and Waitlisted are counts, set by the server.
+type Event struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Capacity    int       `json:"capacity"`
+	Registered  int       `json:"registered"`
+	Waitlisted  int       `json:"waitlisted"`
+}
+
+func (e *Event) Validate() error {
+	if e.Title == "" {
+		return errors.New("title must not be empty")
+	}
+	if e.Start.IsZero() || !e.End.After(e.Start) {
+		return errors.New("end must be after start")
+	}
+	if e.Capacity < 1 {
+		return errors.New("capacity must be at least 1")
+	}
+	return nil
+}
+
+// Registration is a member's place at an event, or on its waitlist. The
+// waitlist is served in RegisteredAt order.
+type Registration struct {
+	EventID      int        `json:"event_id"`
+	MemberID     int        `json:"member_id"`
+	Status       string     `json:"status"`
+	RegisteredAt time.Time  `json:"registered_at"`
+	RemindedAt   *time.Time `json:"reminded_at,omitempty"`
+}
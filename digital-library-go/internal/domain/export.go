@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Catalog export formats.
+const (
+	ExportCSV  = "csv"
+	ExportJSON = "json"
+	ExportMARC = "marc"
+)
+
+// Export destinations.
+const (
+	DestinationSFTP = "sftp"
+	DestinationS3   = "s3"
+)
+
+// ExportJob pushes the published catalog to an SFTP server or S3 bucket
+// once a night, at Hour UTC. Secrets in the destination are write-only:
+// they are never returned, and an update that leaves one empty keeps the
+// old value.
+type ExportJob struct {
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	Format        string            `json:"format"`
+	Destination   ExportDestination `json:"destination"`
+	Hour          int               `json:"hour"`
+	Enabled       bool              `json:"enabled"`
+	NotifySuccess bool              `json:"notify_success"`
+	// NextRun is set by the server from Hour.
+	NextRun time.Time  `json:"next_run"`
+	LastRun *ExportRun `json:"last_run,omitempty"`
+}
+
+// ExportDestination says where an export is uploaded. Path is the
+// directory on an SFTP server or the key prefix in a bucket; the file is
+// named catalog-YYYYMMDD with the format's extension.
+type ExportDestination struct {
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"`
+
+	// SFTP. HostKey is the server's public key in authorized_keys format,
+	// which the server must present; there is no trust on first use.
+	// Port defaults to 22.
+	Host       string `json:"host,omitempty"`
+	Port       int    `json:"port,omitempty"`
+	User       string `json:"user,omitempty"`
+	Password   string `json:"password,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	HostKey    string `json:"host_key,omitempty"`
+
+	// S3. Endpoint is only needed for S3-compatible stores such as MinIO.
+	Bucket          string `json:"bucket,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+}
+
+// ExportRun records the outcome of one run of a job.
+type ExportRun struct {
+	At    time.Time `json:"at"`
+	File  string    `json:"file"`
+	Books int       `json:"books"`
+	Bytes int       `json:"bytes"`
+	Error string    `json:"error,omitempty"`
+}
+
+// ExportExtension is the file extension for an export format.
+func ExportExtension(format string) string {
+	if format == ExportMARC {
+		return "mrc"
+	}
+	return format
+}
+
+func (j *ExportJob) Validate() error {
+	if strings.TrimSpace(j.Name) == "" {
+		return errors.New("name is required")
+	}
+	switch j.Format {
+	case ExportCSV, ExportJSON, ExportMARC:
+	default:
+		return errors.New("format must be csv, json or marc")
+	}
+	if j.Hour < 0 || j.Hour > 23 {
+		return errors.New("hour must be between 0 and 23")
+	}
+	return j.Destination.Validate()
+}
+
+func (d *ExportDestination) Validate() error {
+	switch d.Type {
+	case DestinationSFTP:
+		if d.Host == "" || d.User == "" {
+			return errors.New("sftp destinations need a host and user")
+		}
+		if d.Port < 0 || d.Port > 65535 {
+			return errors.New("port must be between 1 and 65535")
+		}
+		if d.HostKey == "" {
+			return errors.New("sftp destinations need the server's host_key")
+		}
+		if d.Password == "" && d.PrivateKey == "" {
+			return errors.New("sftp destinations need a password or private_key")
+		}
+	case DestinationS3:
+		if d.Bucket == "" || d.Region == "" {
+			return errors.New("s3 destinations need a bucket and region")
+		}
+		if d.AccessKeyID == "" || d.SecretAccessKey == "" {
+			return errors.New("s3 destinations need an access_key_id and secret_access_key")
+		}
+		if d.Endpoint != "" {
+			u, err := url.Parse(d.Endpoint)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return errors.New("endpoint must be an http or https URL")
+			}
+		}
+	default:
+		return errors.New("destination type must be sftp or s3")
+	}
+	return nil
+}
+
+// Redacted returns the job without its destination's secrets.
+func (j ExportJob) Redacted() ExportJob {
+	j.Destination.Password = ""
+	j.Destination.PrivateKey = ""
+	j.Destination.SecretAccessKey = ""
+	if j.LastRun != nil {
+		run := *j.LastRun
+		j.LastRun = &run
+	}
+	return j
+}
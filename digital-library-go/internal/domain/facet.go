@@ -0,0 +1,38 @@
+package domain
+
+// Facet names returned by the faceted browse endpoint.
+const (
+	FacetAuthor       = "author"
+	FacetGenre        = "genre"
+	FacetDecade       = "decade"
+	FacetLanguage     = "language"
+	FacetFormat       = "format"
+	FacetAvailability = "availability"
+)
+
+// FacetCount is how many matching books share one facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// BrowseResult is a page of search results together with facet counts
+// over all matching books.
+type BrowseResult struct {
+	Total  int                     `json:"total"`
+	Books  []Book                  `json:"books"`
+	Facets map[string][]FacetCount `json:"facets"`
+	// DidYouMean holds corrected queries when nothing matched.
+	DidYouMean []string `json:"did_you_mean,omitempty"`
+	// Remote holds the titles found in consortium partners' catalogs
+	// that this library does not have.
+	Remote []RemoteBook `json:"remote,omitempty"`
+}
+
+// Suggestion is one autocomplete completion. Kind is "title" or
+// "author"; Books is how many books it would find.
+type Suggestion struct {
+	Text  string `json:"text"`
+	Kind  string `json:"kind"`
+	Books int    `json:"books"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// RemoteBook is a book found in the catalog of a consortium partner.
+// Source names the partner and ID is the book's ID there.
+type RemoteBook struct {
+	Source string `json:"source"`
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Year   int    `json:"year,omitempty"`
+	ISBN   string `json:"isbn,omitempty"`
+}
+
+// ILLRequest is an interlibrary loan: a member's request for a partner's
+// book, placed as a hold at the partner under this library's account
+// there. RemoteRequestID is the partner's ID for the hold.
+type ILLRequest struct {
+	ID              int       `json:"id"`
+	MemberID        int       `json:"member_id"`
+	Partner         string    `json:"partner"`
+	RemoteBookID    int       `json:"remote_book_id"`
+	Title           string    `json:"title"`
+	Author          string    `json:"author"`
+	ISBN            string    `json:"isbn,omitempty"`
+	RemoteRequestID string    `json:"remote_request_id,omitempty"`
+	RequestedAt     time.Time `json:"requested_at"`
+}
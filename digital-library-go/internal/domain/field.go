@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Custom field types.
+const (
+	FieldText   = "text"
+	FieldNumber = "number"
+	FieldEnum   = "enum"
+)
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// FieldDefinition describes a custom metadata field that books may carry
+// in their attributes. Tenant is the host name it is defined for, or
+// empty for a field every tenant has.
+type FieldDefinition struct {
+	Tenant   string   `json:"tenant"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required"`
+}
+
+func (f *FieldDefinition) Validate() error {
+	if !fieldNamePattern.MatchString(f.Name) {
+		return errors.New("name must be lowercase letters, digits and underscores, starting with a letter")
+	}
+	switch f.Type {
+	case FieldText, FieldNumber:
+		if len(f.Options) > 0 {
+			return errors.New("options are only allowed for enum fields")
+		}
+	case FieldEnum:
+		if len(f.Options) == 0 {
+			return errors.New("enum fields need at least one option")
+		}
+	default:
+		return errors.New("type must be text, number or enum")
+	}
+	return nil
+}
+
+// For reports whether a tenant has the field.
+func (f *FieldDefinition) For(tenant string) bool {
+	return f.Tenant == "" || f.Tenant == tenant
+}
+
+// Check validates one attribute value as decoded from JSON.
+func (f *FieldDefinition) Check(value any) error {
+	switch f.Type {
+	case FieldNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("attribute %s must be a number", f.Name)
+		}
+	case FieldEnum:
+		s, ok := value.(string)
+		if !ok || !slices.Contains(f.Options, s) {
+			return fmt.Errorf("attribute %s must be one of %s", f.Name, strings.Join(f.Options, ", "))
+		}
+	default:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("attribute %s must be a string", f.Name)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether an attribute value equals a filter given as a
+// query string. Text compares case-insensitively, numbers numerically.
+func (f *FieldDefinition) Matches(value any, filter string) bool {
+	switch v := value.(type) {
+	case float64:
+		n, err := strconv.ParseFloat(filter, 64)
+		return err == nil && n == v
+	case string:
+		if f.Type == FieldText {
+			return strings.EqualFold(v, filter)
+		}
+		return v == filter
+	}
+	return false
+}
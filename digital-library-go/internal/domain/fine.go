@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Fine is a charge against a member's account. Amounts are in cents.
+type Fine struct {
+	ID         int        `json:"id"`
+	MemberID   int        `json:"member_id"`
+	LoanID     int        `json:"loan_id"`
+	Amount     int        `json:"amount"`
+	Reason     string     `json:"reason"`
+	AssessedAt time.Time  `json:"assessed_at"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+	// PaymentID is the online payment that paid the fine.
+	PaymentID int `json:"payment_id,omitempty"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Hold statuses. A hold waits in the queue until the book comes back to
+// the member first in line, then stays ready for pickup until it expires
+// and the next member's turn comes. A hold whose copy is sent to its
+// pickup branch first is in transit until the copy arrives.
+const (
+	HoldWaiting   = "waiting"
+	HoldInTransit = "in_transit"
+	HoldReady     = "ready"
+)
+
+// DefaultPickupWindow is how long a ready hold is kept on the shelf.
+const DefaultPickupWindow = 7 * 24 * time.Hour
+
+// Hold is a member's place in the queue for a book. PickupBranchID is
+// the branch they collect it from, if the library has branches.
+type Hold struct {
+	ID             int        `json:"id"`
+	BookID         int        `json:"book_id"`
+	MemberID       int        `json:"member_id"`
+	PickupBranchID int        `json:"pickup_branch_id,omitempty"`
+	PlacedAt       time.Time  `json:"placed_at"`
+	Status         string     `json:"status"`
+	ReadyAt        *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+}
+
+// Promoted reports whether the book is set aside for the hold, on the
+// hold shelf or on its way there.
+func (h *Hold) Promoted() bool {
+	return h.Status == HoldReady || h.Status == HoldInTransit
+}
+
+// QueuedHold is one of a member's holds with its place in the book's
+// queue. Position 1 is first in line, which is where ready holds are.
+// The estimates are left out when there is no loan history to base them
+// on.
+type QueuedHold struct {
+	Hold
+	Position          int        `json:"position"`
+	EstimatedReadyAt  *time.Time `json:"estimated_ready_at,omitempty"`
+	EstimatedWaitDays *int       `json:"estimated_wait_days,omitempty"`
+}
+
+// HoldExpiryResult is the result of a hold expiry task: how many ready
+// holds were left uncollected past their pickup window.
+type HoldExpiryResult struct {
+	Expired int `json:"expired"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+// Sources members can import their reading history from.
+const (
+	ImportGoodreads    = "goodreads"
+	ImportLibraryThing = "librarything"
+)
+
+// ImportRow is one book from a reading-site export, reduced to what the
+// catalog uses. Line is its line in the file, for the report. Rating is
+// 0 if the member did not rate the book.
+type ImportRow struct {
+	Line    int
+	Title   string
+	Author  string
+	ISBNs   []string
+	Rating  int
+	Review  string
+	Shelves []string
+}
+
+// ImportReport says what an import did. Rows that matched no catalog
+// book are listed in Unmatched so the member can add them by hand.
+type ImportReport struct {
+	Source         string         `json:"source"`
+	Rows           int            `json:"rows"`
+	Matched        int            `json:"matched"`
+	ListsCreated   []string       `json:"lists_created"`
+	BooksListed    int            `json:"books_listed"`
+	ReviewsCreated int            `json:"reviews_created"`
+	ReviewsSkipped int            `json:"reviews_skipped"`
+	Unmatched      []UnmatchedRow `json:"unmatched"`
+}
+
+type UnmatchedRow struct {
+	Line   int    `json:"line"`
+	Title  string `json:"title"`
+	Author string `json:"author,omitempty"`
+	ISBN   string `json:"isbn,omitempty"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "errors"
+
+// Kinds of KioskMessage.
+const (
+	KioskCheckout = "checkout"
+	KioskCheckin  = "checkin"
+)
+
+// KioskMessage is a circulation transaction reported by a self-service
+// kiosk or another external loan system. The item is named by the barcode
+// on a copy's label or, failing that, by BookID. ID is chosen by the
+// sender and must be unique per integration, so a message sent again
+// after a timeout is not applied twice.
+type KioskMessage struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	CardNumber  string `json:"card_number,omitempty"`
+	ItemBarcode string `json:"item_barcode,omitempty"`
+	BookID      int    `json:"book_id,omitempty"`
+}
+
+func (m *KioskMessage) Validate() error {
+	if m.ID == "" {
+		return errors.New("id is required")
+	}
+	switch m.Type {
+	case KioskCheckout:
+		if m.CardNumber == "" {
+			return errors.New("card_number is required to check out")
+		}
+	case KioskCheckin:
+	default:
+		return errors.New("type must be checkout or checkin")
+	}
+	if m.ItemBarcode == "" && m.BookID <= 0 {
+		return errors.New("item_barcode or book_id is required")
+	}
+	return nil
+}
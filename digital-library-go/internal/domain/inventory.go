@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// InventoryAudit is a stocktaking session: staff scan the barcodes of the
+// copies they find on the shelves, then compare them with the catalog.
+// Scope limits the audit to the copies kept within a location, such as a
+// floor or "floor/section"; empty means the whole library.
+type InventoryAudit struct {
+	ID        int             `json:"id"`
+	Scope     string          `json:"scope,omitempty"`
+	StartedAt time.Time       `json:"started_at"`
+	ClosedAt  *time.Time      `json:"closed_at,omitempty"`
+	Scanned   int             `json:"scanned"`
+	Scans     []InventoryScan `json:"-"`
+}
+
+// InventoryScan is one barcode read at a location.
+type InventoryScan struct {
+	Barcode   string    `json:"barcode"`
+	Location  string    `json:"location"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// ScanBatch is a batch of barcodes read at the same location, given as
+// "floor/section/shelf" like Copy.Location.
+type ScanBatch struct {
+	Location string   `json:"location"`
+	Barcodes []string `json:"barcodes"`
+}
+
+func (b *ScanBatch) Validate() error {
+	if b.Location == "" {
+		return errors.New("location must not be empty")
+	}
+	if len(b.Barcodes) == 0 {
+		return errors.New("barcodes must not be empty")
+	}
+	return nil
+}
+
+// MisplacedCopy is a copy that was scanned somewhere other than its
+// recorded location.
+type MisplacedCopy struct {
+	Copy    Copy   `json:"copy"`
+	FoundAt string `json:"found_at"`
+}
+
+// InventoryReport reconciles an audit's scans with the catalog:
+//   - Missing copies are in scope but were not scanned and are not on loan.
+//   - OnLoan copies were not scanned, but their book is lent out.
+//   - Misplaced copies were scanned away from their recorded location.
+//   - Withdrawn copies were scanned although they have been weeded.
+//   - Unknown barcodes match no copy at all.
+//
+// Valuation puts a price on the copies in scope and those missing.
+type InventoryReport struct {
+	AuditID   int             `json:"audit_id"`
+	Scope     string          `json:"scope,omitempty"`
+	Expected  int             `json:"expected"`
+	Found     int             `json:"found"`
+	Missing   []Copy          `json:"missing"`
+	OnLoan    []Copy          `json:"on_loan"`
+	Misplaced []MisplacedCopy `json:"misplaced"`
+	Withdrawn []Copy          `json:"withdrawn"`
+	Unknown   []string        `json:"unknown"`
+	Valuation Valuation       `json:"valuation"`
+}
+
+// Valuation sums the purchase prices of the copies in an inventory's
+// scope and of those that went missing, in cents. Copies with no price on
+// record are counted in Unpriced instead.
+type Valuation struct {
+	Expected int `json:"expected"`
+	Missing  int `json:"missing"`
+	Unpriced int `json:"unpriced"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "strings"
+
+// NormalizeISBN strips the hyphens, spaces and other decoration that
+// exports and scanners add around an ISBN, keeping digits and a check
+// character X. It does not validate the result.
+func NormalizeISBN(isbn string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == 'x' || r == 'X':
+			return 'X'
+		}
+		return -1
+	}, isbn)
+}
+
+// ISBN13 returns the 13-digit form of an ISBN-10 or ISBN-13, so the two
+// forms of one book compare equal. ok is false for anything else.
+func ISBN13(isbn string) (string, bool) {
+	isbn = NormalizeISBN(isbn)
+	switch {
+	case len(isbn) == 13 && !strings.Contains(isbn, "X"):
+		return isbn, true
+	case len(isbn) == 10 && !strings.Contains(isbn[:9], "X"):
+		body := "978" + isbn[:9]
+		return body + string(checkDigit13(body)), true
+	}
+	return "", false
+}
+
+// checkDigit13 computes the last digit of an ISBN-13 from the first 12.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i, d := range body {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += int(d-'0') * w
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// ValidISBN reports whether isbn is an ISBN-10 or ISBN-13 with a correct
+// check digit, which catches most misreads by a barcode scanner.
+func ValidISBN(isbn string) bool {
+	isbn = NormalizeISBN(isbn)
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, d := range isbn {
+			v := int(d - '0')
+			if d == 'X' {
+				if i != 9 {
+					return false
+				}
+				v = 10
+			}
+			sum += v * (10 - i)
+		}
+		return sum%11 == 0
+	case 13:
+		return !strings.Contains(isbn, "X") && checkDigit13(isbn[:12]) == isbn[12]
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package domain
+
+import "strings"
+
+// iso639 maps the ISO 639-2 codes of every language with an ISO 639-1
+// code to that code. Both the terminology (deu) and bibliographic (ger)
+// forms are listed.
+var iso639 = map[string]string{
+	"aar": "aa", "abk": "ab", "ave": "ae", "afr": "af", "aka": "ak",
+	"amh": "am", "arg": "an", "ara": "ar", "asm": "as", "ava": "av",
+	"aym": "ay", "aze": "az", "bak": "ba", "bel": "be", "bul": "bg",
+	"bih": "bh", "bis": "bi", "bam": "bm", "ben": "bn", "bod": "bo",
+	"tib": "bo", "bre": "br", "bos": "bs", "cat": "ca", "che": "ce",
+	"cha": "ch", "cos": "co", "cre": "cr", "ces": "cs", "cze": "cs",
+	"chu": "cu", "chv": "cv", "cym": "cy", "wel": "cy", "dan": "da",
+	"deu": "de", "ger": "de", "div": "dv", "dzo": "dz", "ewe": "ee",
+	"ell": "el", "gre": "el", "eng": "en", "epo": "eo", "spa": "es",
+	"est": "et", "eus": "eu", "baq": "eu", "fas": "fa", "per": "fa",
+	"ful": "ff", "fin": "fi", "fij": "fj", "fao": "fo", "fra": "fr",
+	"fre": "fr", "fry": "fy", "gle": "ga", "gla": "gd", "glg": "gl",
+	"grn": "gn", "guj": "gu", "glv": "gv", "hau": "ha", "heb": "he",
+	"hin": "hi", "hmo": "ho", "hrv": "hr", "hat": "ht", "hun": "hu",
+	"hye": "hy", "arm": "hy", "her": "hz", "ina": "ia", "ind": "id",
+	"ile": "ie", "ibo": "ig", "iii": "ii", "ipk": "ik", "ido": "io",
+	"isl": "is", "ice": "is", "ita": "it", "iku": "iu", "jpn": "ja",
+	"jav": "jv", "kat": "ka", "geo": "ka", "kon": "kg", "kik": "ki",
+	"kua": "kj", "kaz": "kk", "kal": "kl", "khm": "km", "kan": "kn",
+	"kor": "ko", "kau": "kr", "kas": "ks", "kur": "ku", "kom": "kv",
+	"cor": "kw", "kir": "ky", "lat": "la", "ltz": "lb", "lug": "lg",
+	"lim": "li", "lin": "ln", "lao": "lo", "lit": "lt", "lub": "lu",
+	"lav": "lv", "mlg": "mg", "mah": "mh", "mri": "mi", "mao": "mi",
+	"mkd": "mk", "mac": "mk", "mal": "ml", "mon": "mn", "mar": "mr",
+	"msa": "ms", "may": "ms", "mlt": "mt", "mya": "my", "bur": "my",
+	"nau": "na", "nob": "nb", "nde": "nd", "nep": "ne", "ndo": "ng",
+	"nld": "nl", "dut": "nl", "nno": "nn", "nor": "no", "nbl": "nr",
+	"nav": "nv", "nya": "ny", "oci": "oc", "oji": "oj", "orm": "om",
+	"ori": "or", "oss": "os", "pan": "pa", "pli": "pi", "pol": "pl",
+	"pus": "ps", "por": "pt", "que": "qu", "roh": "rm", "run": "rn",
+	"ron": "ro", "rum": "ro", "rus": "ru", "kin": "rw", "san": "sa",
+	"srd": "sc", "snd": "sd", "sme": "se", "sag": "sg", "sin": "si",
+	"slk": "sk", "slo": "sk", "slv": "sl", "smo": "sm", "sna": "sn",
+	"som": "so", "sqi": "sq", "alb": "sq", "srp": "sr", "ssw": "ss",
+	"sot": "st", "sun": "su", "swe": "sv", "swa": "sw", "tam": "ta",
+	"tel": "te", "tgk": "tg", "tha": "th", "tir": "ti", "tuk": "tk",
+	"tgl": "tl", "tsn": "tn", "ton": "to", "tur": "tr", "tso": "ts",
+	"tat": "tt", "twi": "tw", "tah": "ty", "uig": "ug", "ukr": "uk",
+	"urd": "ur", "uzb": "uz", "ven": "ve", "vie": "vi", "vol": "vo",
+	"wln": "wa", "wol": "wo", "xho": "xh", "yid": "yi", "yor": "yo",
+	"zha": "za", "zho": "zh", "chi": "zh", "zul": "zu",
+}
+
+// languageCodes are the two-letter ISO 639-1 codes, plus the ISO 639-2
+// special codes for several languages (mul), an undetermined language
+// (und) and no linguistic content (zxx), and historical languages common
+// in catalogs that have no two-letter code.
+var languageCodes = map[string]bool{
+	"mul": true, "und": true, "zxx": true,
+	"grc": true, "ang": true, "enm": true, "fro": true, "frm": true,
+	"goh": true, "gmh": true, "non": true, "cop": true, "egy": true,
+	"akk": true, "sux": true, "syr": true, "haw": true, "chr": true,
+}
+
+// terminologyCodes are the ISO 639-2 codes that have a separate
+// bibliographic form, which MARC records use instead.
+var terminologyCodes = map[string]bool{
+	"bod": true, "ces": true, "cym": true, "deu": true, "ell": true,
+	"eus": true, "fas": true, "fra": true, "hye": true, "isl": true,
+	"kat": true, "mri": true, "mkd": true, "msa": true, "mya": true,
+	"nld": true, "ron": true, "slk": true, "sqi": true, "zho": true,
+}
+
+// marcLanguages maps ISO 639-1 codes to ISO 639-2/B.
+var marcLanguages = map[string]string{}
+
+func init() {
+	for three, code := range iso639 {
+		languageCodes[code] = true
+		if !terminologyCodes[three] {
+			marcLanguages[code] = three
+		}
+	}
+}
+
+// NormalizeLanguage lower-cases a language code and replaces an ISO
+// 639-2 code by its ISO 639-1 equivalent, so "SPA" and "es" are both
+// "es". Unknown codes are returned lower-cased.
+func NormalizeLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if two, ok := iso639[code]; ok {
+		return two
+	}
+	return code
+}
+
+// MARCLanguage returns the ISO 639-2 bibliographic code MARC records use
+// for a language, such as "ger" for "de". Codes with no two-letter form
+// are returned as they are.
+func MARCLanguage(code string) string {
+	code = NormalizeLanguage(code)
+	if three, ok := marcLanguages[code]; ok {
+		return three
+	}
+	return code
+}
+
+// ValidLanguage reports whether code is a known ISO 639-1 or 639-2 code.
+func ValidLanguage(code string) bool {
+	return languageCodes[NormalizeLanguage(code)]
+}
+
+// LanguageMigration reports how many books got their language from the
+// old custom field and how many the default, and which are still unset.
+type LanguageMigration struct {
+	FromAttributes int   `json:"from_attributes"`
+	Defaulted      int   `json:"defaulted"`
+	Unset          []int `json:"unset"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Leadership is this instance's standing in the leader election that
+// decides which instance runs the scheduled jobs. Elected and Lost
+// count how often it became leader and lost leadership since it
+// started; a failover shows as a loss here and an election elsewhere.
+type Leadership struct {
+	Instance string    `json:"instance"`
+	Leader   bool      `json:"leader"`
+	Since    time.Time `json:"since,omitzero"`
+	Elected  int       `json:"elected"`
+	Lost     int       `json:"lost"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// DueReminderLead is how long before a loan falls due the borrower is
+// reminded of it.
+const DueReminderLead = 48 * time.Hour
+
+// MaxRenewals is how often a loan may be renewed.
+const MaxRenewals = 2
+
+type Loan struct {
+	ID       int       `json:"id"`
+	BookID   int       `json:"book_id"`
+	MemberID int       `json:"member_id"`
+	LoanedAt time.Time `json:"loaned_at"`
+	DueAt    time.Time `json:"due_at"`
+	Renewals int       `json:"renewals,omitempty"`
+	// CourseID is set on short loans of a book on reserve for a course.
+	CourseID   int        `json:"course_id,omitempty"`
+	ReturnedAt *time.Time `json:"returned_at,omitempty"`
+}
+
+func (l *Loan) Active() bool {
+	return l.ReturnedAt == nil
+}
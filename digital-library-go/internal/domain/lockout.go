@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Lockout describes failed login attempts tracked for an account (by card
+// number) or a client IP.
+type Lockout struct {
+	Kind        string    `json:"kind"`
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until,omitzero"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// DefaultRetryAfter is how long clients are told to wait, in seconds,
+// when maintenance mode is turned on without saying.
+const DefaultRetryAfter = 300
+
+// Maintenance is the state of maintenance mode. While it is on, requests
+// that change data are refused and reads carry on.
+type Maintenance struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+	// RetryAfter is sent to refused clients in the Retry-After header,
+	// in seconds.
+	RetryAfter int       `json:"retry_after,omitempty"`
+	Since      time.Time `json:"since,omitzero"`
+	// By is the card number of the admin who turned it on, or "task"
+	// for a background task.
+	By string `json:"by,omitempty"`
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Formats of items in the catalog. Books without a format are print.
+const (
+	FormatPrint     = "print"
+	FormatAudiobook = "audiobook"
+	FormatDVD       = "dvd"
+	FormatMagazine  = "magazine"
+)
+
+// Formats lists every format.
+var Formats = []string{FormatPrint, FormatAudiobook, FormatDVD, FormatMagazine}
+
+// MaxDurationMinutes bounds the running time of audiobooks and DVDs, at
+// the 99 hours MARC records can hold.
+const MaxDurationMinutes = 99 * 60
+
+// MediaFormat is the book's format, print when none is set.
+func (b *Book) MediaFormat() string {
+	if b.Format == "" {
+		return FormatPrint
+	}
+	return b.Format
+}
+
+// hasISBN reports whether items of a format are published with an ISBN.
+// DVDs and magazines are not, so they may be cataloged without one.
+func hasISBN(format string) bool {
+	return format == FormatPrint || format == FormatAudiobook
+}
+
+// validateMedia checks the fields that only some formats have: the
+// running time of audiobooks and DVDs, the narrator of audiobooks and
+// the volume and issue number of magazines. Drafts may still lack the
+// running time and issue number.
+func (b *Book) validateMedia(draft bool) error {
+	format := b.MediaFormat()
+	if !slices.Contains(Formats, format) {
+		return errors.New("format must be print, audiobook, dvd or magazine")
+	}
+
+	timed := format == FormatAudiobook || format == FormatDVD
+	switch {
+	case !timed && b.DurationMinutes != 0:
+		return fmt.Errorf("duration_minutes is only for audiobooks and DVDs, not %s", format)
+	case timed && b.DurationMinutes == 0 && !draft:
+		return errors.New("duration_minutes is required for audiobooks and DVDs")
+	case b.DurationMinutes < 0 || b.DurationMinutes > MaxDurationMinutes:
+		return fmt.Errorf("duration_minutes must be between 1 and %d", MaxDurationMinutes)
+	}
+
+	if b.Narrator != "" && format != FormatAudiobook {
+		return errors.New("narrator is only for audiobooks")
+	}
+
+	if format != FormatMagazine && (b.Volume != 0 || b.IssueNumber != 0) {
+		return errors.New("volume and issue_number are only for magazines")
+	}
+	if b.Volume < 0 || b.IssueNumber < 0 {
+		return errors.New("volume and issue_number must not be negative")
+	}
+	if format == FormatMagazine && b.IssueNumber == 0 && !draft {
+		return errors.New("issue_number is required for a magazine")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type Member struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Phone is where SMS notifications go, in E.164 form, e.g. +441632960961.
+	Phone         string     `json:"phone,omitempty"`
+	PlanID        int        `json:"plan_id"`
+	Role          string     `json:"role"`
+	CardNumber    string     `json:"card_number"`
+	PreviousCards []string   `json:"previous_cards,omitempty"`
+	Privacy       Privacy    `json:"privacy"`
+	Identities    []Identity `json:"identities,omitempty"`
+	// Audience is AudienceChildren for child accounts, which only ever
+	// see books suitable for children.
+	Audience string `json:"audience,omitempty"`
+	// Tenant is the host name of the site the member belongs to. Their
+	// emails and text messages are worded with its templates, and texts
+	// sent with its SMS settings.
+	Tenant string `json:"tenant,omitempty"`
+
+	// DeletionScheduledAt is set while an account deletion request is in
+	// its grace period.
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+
+	// Password is only read from requests; the store keeps the hash.
+	Password     string `json:"password,omitempty"`
+	PasswordHash []byte `json:"-"`
+	// FeedKey is part of the member's calendar feed token, so replacing
+	// it revokes the token.
+	FeedKey string `json:"-"`
+}
+
+const (
+	RoleMember    = "member"
+	RoleLibrarian = "librarian"
+	RoleAdmin     = "admin"
+)
+
+// ValidRole reports whether role is one of the known member roles.
+func ValidRole(role string) bool {
+	return role == RoleMember || role == RoleLibrarian || role == RoleAdmin
+}
+
+// IsStaff reports whether the member works at the library.
+func (m *Member) IsStaff() bool {
+	return m.Role == RoleLibrarian || m.Role == RoleAdmin
+}
+
+// Identity links a member to an account at an external identity
+// provider.
+type Identity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+// ExternalIdentity is what an identity provider asserts about a user
+// after a successful login.
+type ExternalIdentity struct {
+	Identity
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Privacy lists the profile fields a member has chosen to share on their
+// public profile. Everything is private by default.
+type Privacy struct {
+	ShowEmail        bool `json:"show_email"`
+	ShowReadingLists bool `json:"show_reading_lists"`
+}
+
+// PublicProfile is the view of a member other patrons may see.
+type PublicProfile struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	Email        string        `json:"email,omitempty"`
+	ReadingLists []ReadingList `json:"reading_lists,omitempty"`
+}
+
+func (m *Member) Validate() error {
+	if m.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if m.PlanID == 0 {
+		return errors.New("plan_id is required")
+	}
+	if m.Password != "" && len(m.Password) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
+	if m.Phone != "" && !ValidPhone(m.Phone) {
+		return ErrInvalidPhone
+	}
+	if m.Audience != "" && m.Audience != AudienceChildren {
+		return errors.New("audience must be empty or children")
+	}
+	return nil
+}
+
+// Public returns the member's public profile, honouring their privacy
+// settings.
+func (m *Member) Public(lists []ReadingList) PublicProfile {
+	p := PublicProfile{ID: m.ID, Name: m.Name}
+	if m.Privacy.ShowEmail {
+		p.Email = m.Email
+	}
+	if m.Privacy.ShowReadingLists {
+		p.ReadingLists = lists
+	}
+	return p
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMetadataNotFound is returned by metadata providers that have no
+// record for an ISBN.
+var ErrMetadataNotFound = errors.New("no metadata found")
+
+// BookMetadata is what an external catalog knows about an ISBN. Zero
+// fields are unknown.
+type BookMetadata struct {
+	Title  string
+	Author string
+	Year   int
+}
+
+// Outcomes of refreshing one book's metadata.
+const (
+	RefreshUpdated  = "updated"
+	RefreshNotFound = "not_found"
+	RefreshFailed   = "failed"
+)
+
+type BookRefreshResult struct {
+	BookID   int      `json:"book_id"`
+	ISBN     string   `json:"isbn"`
+	Outcome  string   `json:"outcome"`
+	Provider string   `json:"provider,omitempty"`
+	Fields   []string `json:"fields,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// MetadataRefreshReport records one run of the metadata refresh job. Only
+// books with missing fields are checked.
+type MetadataRefreshReport struct {
+	Status     string              `json:"status"`
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt time.Time           `json:"finished_at,omitzero"`
+	Checked    int                 `json:"checked"`
+	Updated    int                 `json:"updated"`
+	NotFound   int                 `json:"not_found"`
+	Failed     int                 `json:"failed"`
+	Results    []BookRefreshResult `json:"results"`
+}
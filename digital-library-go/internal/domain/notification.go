@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Kinds of notification, which members choose channels for.
+const (
+	NotifyNewBook       = "new_book"
+	NotifyBooking       = "booking"
+	NotifyEvent         = "event"
+	NotifyEventReminder = "event_reminder"
+	NotifyReview        = "review"
+	NotifyDueSoon       = "due_soon"
+	NotifyHoldReady     = "hold_ready"
+	NotifyHoldExpired   = "hold_expired"
+	NotifyExport        = "export"
+)
+
+// NotificationKinds lists every kind of notification.
+var NotificationKinds = []string{NotifyNewBook, NotifyBooking, NotifyEvent, NotifyEventReminder, NotifyReview, NotifyDueSoon, NotifyHoldReady, NotifyHoldExpired, NotifyExport}
+
+// Delivery channels.
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+// Notification is a message for one member, read through /me/notifications.
+type Notification struct {
+	ID        int       `json:"id"`
+	MemberID  int       `json:"member_id"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	BookID    int       `json:"book_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Read      bool      `json:"read"`
+}
+
+// Title is a short heading for the notification, used as the email
+// subject and push notification title.
+func (n Notification) Title() string {
+	switch n.Kind {
+	case NotifyNewBook:
+		return "New book for your saved search"
+	case NotifyBooking:
+		return "Your booking"
+	case NotifyEvent, NotifyEventReminder:
+		return "Library event"
+	case NotifyReview:
+		return "Your review"
+	case NotifyDueSoon:
+		return "Your loan is due soon"
+	case NotifyHoldReady:
+		return "Your hold is ready"
+	case NotifyHoldExpired:
+		return "Your hold has expired"
+	case NotifyExport:
+		return "Scheduled export"
+	}
+	return "Library notification"
+}
+
+// ChannelSet says which channels a kind of notification goes to.
+type ChannelSet struct {
+	InApp bool `json:"in_app"`
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	Push  bool `json:"push"`
+}
+
+// DefaultChannels sends notifications to the in-app inbox only.
+var DefaultChannels = ChannelSet{InApp: true}
+
+// Enabled lists the channels in the set.
+func (s ChannelSet) Enabled() []string {
+	channels := []string{}
+	for name, on := range map[string]bool{ChannelInApp: s.InApp, ChannelEmail: s.Email, ChannelSMS: s.SMS, ChannelPush: s.Push} {
+		if on {
+			channels = append(channels, name)
+		}
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+// NotificationPreferences maps each kind of notification to the channels
+// a member wants it on. Kinds left out use DefaultChannels.
+type NotificationPreferences map[string]ChannelSet
+
+func (p NotificationPreferences) Validate() error {
+	for kind := range p {
+		if !slices.Contains(NotificationKinds, kind) {
+			return fmt.Errorf("unknown notification kind %s", kind)
+		}
+	}
+	return nil
+}
+
+// Channels returns the channels for a kind of notification.
+func (p NotificationPreferences) Channels(kind string) ChannelSet {
+	if s, ok := p[kind]; ok {
+		return s
+	}
+	return DefaultChannels
+}
+
+// WithDefaults returns the preferences with every kind filled in.
+func (p NotificationPreferences) WithDefaults() NotificationPreferences {
+	all := NotificationPreferences{}
+	for _, kind := range NotificationKinds {
+		all[kind] = p.Channels(kind)
+	}
+	return all
+}
+
+// What the new book notification queue does with a notification when it
+// is full.
+const (
+	OverflowDrop       = "drop"        // drop the new notification
+	OverflowDropOldest = "drop_oldest" // drop the longest queued one
+	OverflowBlock      = "block"       // make the request wait for room
+)
+
+// NotificationQueueStats reports on the new book notification queue.
+// Depth is how many notifications wait for a worker, Busy how many
+// workers are sending one.
+type NotificationQueueStats struct {
+	Workers  int    `json:"workers"`
+	Capacity int    `json:"capacity"`
+	Overflow string `json:"overflow"`
+	Depth    int    `json:"depth"`
+	Busy     int64  `json:"busy"`
+	Queued   int64  `json:"queued"`
+	Sent     int64  `json:"sent"`
+	Dropped  int64  `json:"dropped"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// RouteStats counts the requests to a route, e.g. "GET /books/:id" as gin
+// names it, since the server started: those answered with a server error,
+// and how long they took.
+type RouteStats struct {
+	Route        string  `json:"route"`
+	Requests     int     `json:"requests"`
+	Errors       int     `json:"errors"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// RecentError is a request answered with a server error.
+type RecentError struct {
+	At      time.Time `json:"at"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"`
+	Route   string    `json:"route,omitempty"`
+	Status  int       `json:"status"`
+	Message string    `json:"message,omitempty"`
+}
+
+// CacheStats reports on a cache: how many entries it holds, and how many
+// lookups it answered or missed since the server started.
+type CacheStats struct {
+	Entries  int     `json:"entries"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// TaskCounts counts the tasks the queue remembers by status.
+type TaskCounts struct {
+	Queued    int `json:"queued"`
+	Running   int `json:"running"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// Overview is the state of the server at a glance, for an operations
+// dashboard.
+type Overview struct {
+	Version       string                 `json:"version"`
+	Instance      string                 `json:"instance"`
+	StartedAt     time.Time              `json:"started_at"`
+	UptimeSeconds int64                  `json:"uptime_seconds"`
+	Maintenance   bool                   `json:"maintenance"`
+	Tasks         TaskCounts             `json:"tasks"`
+	TaskQueues    []TaskQueueStatus      `json:"task_queues"`
+	Notifications NotificationQueueStats `json:"notifications"`
+	Caches        map[string]CacheStats  `json:"caches"`
+	Requests      int                    `json:"requests"`
+	ServerErrors  int                    `json:"server_errors"`
+	TopEndpoints  []RouteStats           `json:"top_endpoints"`
+	RecentErrors  []RecentError          `json:"recent_errors"`
+}
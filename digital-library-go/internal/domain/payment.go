@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// Statuses of a payment of fines.
+const (
+	PaymentPending   = "pending"
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+	PaymentCanceled  = "canceled"
+)
+
+// Statuses of a payment intent at the provider that the library acts on.
+// Other statuses mean the member is still paying.
+const (
+	IntentRequiresPaymentMethod = "requires_payment_method"
+	IntentSucceeded             = "succeeded"
+	IntentCanceled              = "canceled"
+)
+
+// Payment event types.
+const (
+	EventIntentSucceeded = "payment_intent.succeeded"
+	EventIntentFailed    = "payment_intent.payment_failed"
+	EventIntentCanceled  = "payment_intent.canceled"
+)
+
+// Payment is a member paying some of their fines online. The member's
+// browser completes the payment with the provider using ClientSecret;
+// the provider's webhook then settles it. Amounts are in cents.
+type Payment struct {
+	ID       int    `json:"id"`
+	MemberID int    `json:"member_id"`
+	FineIDs  []int  `json:"fine_ids"`
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+	IntentID string `json:"intent_id"`
+	// ClientSecret is only shown to the member who pays.
+	ClientSecret  string     `json:"client_secret,omitempty"`
+	Status        string     `json:"status"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SettledAt     *time.Time `json:"settled_at,omitempty"`
+}
+
+// PaymentIntent is a payment as the provider sees it.
+type PaymentIntent struct {
+	ID             string
+	ClientSecret   string
+	Status         string
+	Amount         int
+	AmountReceived int
+	Currency       string
+	FailureMessage string
+}
+
+// PaymentEvent is a webhook event from the provider. Intent is set for
+// events about payment intents.
+type PaymentEvent struct {
+	ID     string
+	Type   string
+	Intent PaymentIntent
+}
+
+// PaymentReconciliation reports a pass that brought pending payments in
+// line with the provider, for webhooks that never arrived.
+type PaymentReconciliation struct {
+	Checked   int `json:"checked"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Canceled  int `json:"canceled"`
+	// Errors are payments the provider could not be asked about.
+	Errors int `json:"errors"`
+}
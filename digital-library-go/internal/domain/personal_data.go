@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// PersonalData is the machine-readable archive of everything the library
+// holds about a member.
+type PersonalData struct {
+	ExportedAt    time.Time               `json:"exported_at"`
+	Profile       Member                  `json:"profile"`
+	Loans         []Loan                  `json:"loans"`
+	Holds         []Hold                  `json:"holds"`
+	Fines         []Fine                  `json:"fines"`
+	ReadingLists  []ReadingList           `json:"reading_lists"`
+	SavedSearches []SavedSearch           `json:"saved_searches"`
+	Notifications []Notification          `json:"notifications"`
+	Preferences   NotificationPreferences `json:"notification_preferences"`
+	Bookings      []Booking               `json:"bookings"`
+	Registrations []Registration          `json:"event_registrations"`
+	Reviews       []Review                `json:"reviews"`
+	Push          []PushSubscription      `json:"push_subscriptions"`
+}
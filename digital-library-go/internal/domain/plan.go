@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type Plan struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	MaxLoans int    `json:"max_loans"`
+	LoanDays int    `json:"loan_days"`
+	MaxHolds int    `json:"max_holds"`
+}
+
+func (p *Plan) Validate() error {
+	if p.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if p.MaxLoans < 1 {
+		return errors.New("max_loans must be at least 1")
+	}
+	if p.LoanDays < 1 {
+		return errors.New("loan_days must be at least 1")
+	}
+	if p.MaxHolds < 0 {
+		return errors.New("max_holds must not be negative")
+	}
+	return nil
+}
+
+// DueDate returns the due date of a loan taken out at the given time.
+func (p *Plan) DueDate(from time.Time) time.Time {
+	return from.AddDate(0, 0, p.LoanDays)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// Kinds of BookEvent.
+const (
+	BookViewed   = "viewed"
+	BookBorrowed = "borrowed"
+)
+
+// BookEvent records that a book was looked at or lent out, for the
+// popularity ranking.
+type BookEvent struct {
+	BookID int
+	Kind   string
+	At     time.Time
+}
+
+// TrendingBook is a book with its decayed view and borrow counts. Score
+// combines the two, with borrows weighing more.
+type TrendingBook struct {
+	Book    Book    `json:"book"`
+	Views   float64 `json:"views"`
+	Borrows float64 `json:"borrows"`
+	Score   float64 `json:"score"`
+}
+
+// TrendingReport is the ranking as of its last computation.
+type TrendingReport struct {
+	ComputedAt time.Time      `json:"computed_at"`
+	HalfLife   string         `json:"half_life"`
+	Books      []TrendingBook `json:"books"`
+}
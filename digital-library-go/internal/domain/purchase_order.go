@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Purchase order statuses. An order is drafted, sent to its vendor and
+// received, in that order; only drafts may be changed.
+const (
+	OrderDraft    = "draft"
+	OrderSent     = "sent"
+	OrderReceived = "received"
+)
+
+// MaxItemQuantity bounds the copies one order line may ask for, and
+// MaxOrderCopies those of a whole order, since receiving it creates a
+// copy record for each.
+const (
+	MaxItemQuantity = 500
+	MaxOrderCopies  = 2000
+)
+
+// Vendor is a supplier the library orders books from.
+type Vendor struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	AccountNumber string `json:"account_number,omitempty"`
+}
+
+func (v *Vendor) Validate() error {
+	if strings.TrimSpace(v.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	return nil
+}
+
+// PurchaseOrder is an order of books from one vendor. FundCode is the
+// budget line the order is charged to, unless a line item names its own.
+// Status, the timestamps and Total are set by the server.
+type PurchaseOrder struct {
+	ID         int         `json:"id"`
+	VendorID   int         `json:"vendor_id"`
+	FundCode   string      `json:"fund_code,omitempty"`
+	Items      []OrderItem `json:"items"`
+	Status     string      `json:"status"`
+	Total      int         `json:"total"`
+	CreatedAt  time.Time   `json:"created_at"`
+	SentAt     *time.Time  `json:"sent_at,omitempty"`
+	ReceivedAt *time.Time  `json:"received_at,omitempty"`
+}
+
+// OrderItem orders copies of a title. The title must be in the catalog;
+// one not yet cataloged is requested by creating it as a draft book.
+// UnitPrice is in cents. CopyIDs lists the copies created on receipt.
+type OrderItem struct {
+	BookID    int    `json:"book_id"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unit_price"`
+	FundCode  string `json:"fund_code,omitempty"`
+	CopyIDs   []int  `json:"copy_ids,omitempty"`
+}
+
+func (o *PurchaseOrder) Validate() error {
+	if o.VendorID == 0 {
+		return errors.New("vendor_id is required")
+	}
+	if strings.ContainsAny(o.FundCode, " /") {
+		return errors.New("fund_code must not contain spaces or /")
+	}
+	copies := 0
+	for _, item := range o.Items {
+		if item.BookID == 0 {
+			return errors.New("every item needs a book_id")
+		}
+		if item.Quantity < 1 || item.Quantity > MaxItemQuantity {
+			return fmt.Errorf("quantity must be between 1 and %d", MaxItemQuantity)
+		}
+		if copies += item.Quantity; copies > MaxOrderCopies {
+			return fmt.Errorf("an order may have at most %d copies", MaxOrderCopies)
+		}
+		if item.UnitPrice < 0 {
+			return errors.New("unit_price must not be negative")
+		}
+		if strings.ContainsAny(item.FundCode, " /") {
+			return errors.New("fund_code must not contain spaces or /")
+		}
+	}
+	return nil
+}
+
+// Sum adds up the order's line items.
+func (o *PurchaseOrder) Sum() int {
+	total := 0
+	for _, item := range o.Items {
+		total += item.Quantity * item.UnitPrice
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrSubscriptionGone means the browser has unsubscribed, so the push
+// service no longer accepts messages for the subscription.
+var ErrSubscriptionGone = errors.New("push subscription has expired")
+
+// pushServices are the hosts of the push services browsers subscribe
+// through: Chrome's, Firefox's, Safari's and Edge's. Messages are only
+// sent there, so a subscription cannot make the server post to other
+// hosts, such as those on its own network. An entry starting with a dot
+// matches the hosts under it.
+var pushServices = []string{
+	"fcm.googleapis.com",
+	"updates.push.services.mozilla.com",
+	".push.apple.com",
+	".notify.windows.com",
+}
+
+// PushSubscription is a browser's Web Push subscription, in the shape of
+// the browser's PushSubscription.toJSON().
+type PushSubscription struct {
+	ID        int       `json:"id"`
+	MemberID  int       `json:"member_id"`
+	Endpoint  string    `json:"endpoint"`
+	Keys      PushKeys  `json:"keys"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PushKeys are the subscription's encryption keys, in base64url.
+type PushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+func (s *PushSubscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return errors.New("endpoint must be an https URL")
+	}
+	if port := u.Port(); (port != "" && port != "443") || !knownPushService(u.Hostname()) {
+		return errors.New("endpoint must be on a browser push service")
+	}
+	if key, err := decodeKey(s.Keys.P256dh); err != nil || len(key) != 65 {
+		return errors.New("keys.p256dh must be an uncompressed P-256 public key in base64url")
+	}
+	if auth, err := decodeKey(s.Keys.Auth); err != nil || len(auth) != 16 {
+		return errors.New("keys.auth must be 16 bytes in base64url")
+	}
+	return nil
+}
+
+func knownPushService(host string) bool {
+	host = strings.ToLower(host)
+	for _, s := range pushServices {
+		if host == s || (strings.HasPrefix(s, ".") && strings.HasSuffix(host, s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeKey reads base64url with or without padding, since browsers
+// differ.
+func decodeKey(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// PushMessage is the JSON payload of a push notification, read by the
+// front-end's service worker.
+type PushMessage struct {
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	Kind           string `json:"kind"`
+	BookID         int    `json:"book_id,omitempty"`
+	NotificationID int    `json:"notification_id,omitempty"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+type ReadingList struct {
+	ID       int    `json:"id"`
+	MemberID int    `json:"member_id"`
+	Name     string `json:"name"`
+	BookIDs  []int  `json:"book_ids"`
+}
+
+func (l *ReadingList) Validate() error {
+	if l.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	return nil
+}
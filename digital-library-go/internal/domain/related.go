@@ -0,0 +1,8 @@
+package domain
+
+// RelatedBook is a book borrowed by readers of another book. Readers
+// counts the members who borrowed both.
+type RelatedBook struct {
+	Book    Book `json:"book"`
+	Readers int  `json:"readers"`
+}
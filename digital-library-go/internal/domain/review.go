@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Review statuses. Only approved reviews are shown on a book.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review is a member's rating and opinion of a book. Flags lists why
+// moderation held it back, if it did.
+type Review struct {
+	ID        int       `json:"id"`
+	BookID    int       `json:"book_id"`
+	MemberID  int       `json:"member_id"`
+	Rating    int       `json:"rating"`
+	Text      string    `json:"text"`
+	Status    string    `json:"status"`
+	Flags     []string  `json:"flags,omitempty"`
+	Reports   int       `json:"reports"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *Review) Validate() error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return errors.New("rating must be between 1 and 5")
+	}
+	if strings.TrimSpace(r.Text) == "" {
+		return errors.New("text must not be empty")
+	}
+	if len(r.Text) > 5000 {
+		return errors.New("text must be at most 5000 characters")
+	}
+	return nil
+}
+
+// Words returns the lower-cased words of the review text.
+func (r *Review) Words() []string {
+	return strings.FieldsFunc(strings.ToLower(r.Text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
+
+// ReviewReport is a member's complaint about a review.
+type ReviewReport struct {
+	ReviewID  int       `json:"review_id"`
+	MemberID  int       `json:"member_id"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModerationSettings control which new reviews wait for approval.
+type ModerationSettings struct {
+	// BannedWords hold back reviews containing any of them as a whole
+	// word, case-insensitively.
+	BannedWords []string `json:"banned_words"`
+	// HoldLinks holds back reviews containing a URL, a common sign of spam.
+	HoldLinks bool `json:"hold_links"`
+	// RequireApproval holds back every review.
+	RequireApproval bool `json:"require_approval"`
+	// ReportThreshold is how many reports send a published review back
+	// to the queue.
+	ReportThreshold int `json:"report_threshold"`
+}
+
+func (s *ModerationSettings) Validate() error {
+	if s.ReportThreshold < 1 {
+		return errors.New("report_threshold must be at least 1")
+	}
+	for _, w := range s.BannedWords {
+		if strings.TrimSpace(w) == "" {
+			return errors.New("banned_words must not contain empty words")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// SavedSearch is a query a member wants to hear about: whenever a book
+// matching it is added, they get a notification.
+type SavedSearch struct {
+	ID        int       `json:"id"`
+	MemberID  int       `json:"member_id"`
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *SavedSearch) Validate() error {
+	if strings.TrimSpace(s.Query) == "" {
+		return errors.New("query must not be empty")
+	}
+	return nil
+}
+
+// Matches reports whether the book's title or author contains the query,
+// case-insensitively, as in search.
+func (s *SavedSearch) Matches(b Book) bool {
+	q := strings.ToLower(strings.TrimSpace(s.Query))
+	return strings.Contains(strings.ToLower(b.Title), q) || strings.Contains(strings.ToLower(b.Author), q)
+}
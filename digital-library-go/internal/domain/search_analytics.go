@@ -0,0 +1,32 @@
+package domain
+
+// SearchTerm is how often a search term was searched for over a date
+// range, and how many of those searches found nothing.
+type SearchTerm struct {
+	Term        string `json:"term"`
+	Searches    int    `json:"searches"`
+	ZeroResults int    `json:"zero_results"`
+}
+
+// SearchFilter is how often searches narrowed the results to a facet
+// value, e.g. genre fantasy.
+type SearchFilter struct {
+	Facet    string `json:"facet"`
+	Value    string `json:"value"`
+	Searches int    `json:"searches"`
+}
+
+// SearchAnalytics sums up the catalog searches over a date range (UTC,
+// formatted 2006-01-02), for collection development. Terms lists the
+// most searched terms and ZeroResultTerms those that most often found
+// nothing; both leave out terms searched fewer than MinSearches times.
+type SearchAnalytics struct {
+	From            string         `json:"from"`
+	To              string         `json:"to"`
+	Searches        int            `json:"searches"`
+	ZeroResults     int            `json:"zero_results"`
+	MinSearches     int            `json:"min_searches"`
+	Terms           []SearchTerm   `json:"terms"`
+	ZeroResultTerms []SearchTerm   `json:"zero_result_terms"`
+	Filters         []SearchFilter `json:"filters"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "errors"
+
+// Ranking factors, as named in explanations.
+const (
+	RankingTitleMatch  = "title_match"
+	RankingAuthorMatch = "author_match"
+	RankingRecency     = "recency"
+	RankingPopularity  = "popularity"
+)
+
+// MaxRankingWeight is the highest weight a ranking factor can be given.
+const MaxRankingWeight = 100
+
+// RankingWeights say how much each factor counts towards a search
+// result's score. Each factor is worth between 0 and 1 before it is
+// weighted.
+type RankingWeights struct {
+	TitleMatch  float64 `json:"title_match"`
+	AuthorMatch float64 `json:"author_match"`
+	Recency     float64 `json:"recency"`
+	Popularity  float64 `json:"popularity"`
+}
+
+func (w *RankingWeights) Validate() error {
+	for _, weight := range []float64{w.TitleMatch, w.AuthorMatch, w.Recency, w.Popularity} {
+		if weight < 0 || weight > MaxRankingWeight {
+			return errors.New("weights must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// RankingSettings are the weights search results are ranked by: Default
+// for every tenant, unless Tenants overrides them for its host name.
+type RankingSettings struct {
+	Default RankingWeights            `json:"default"`
+	Tenants map[string]RankingWeights `json:"tenants"`
+}
+
+// RankingFactor is how one factor counted towards a result's score:
+// Value, between 0 and 1, times Weight gives Score. Reason says where
+// Value came from, e.g. "title starts with the query".
+type RankingFactor struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// RankedBook is a search result with the score it was ranked by and how
+// each factor made it up.
+type RankedBook struct {
+	Rank    int             `json:"rank"`
+	Book    Book            `json:"book"`
+	Score   float64         `json:"score"`
+	Factors []RankingFactor `json:"factors"`
+}
+
+// SearchExplanation shows how a search read its query, with its grouping
+// made explicit in Parsed, how it expanded each run of plain words, and
+// how it ranked its results for a tenant, best first.
+type SearchExplanation struct {
+	Query      string           `json:"query"`
+	Parsed     string           `json:"parsed"`
+	Expansions []QueryExpansion `json:"expansions"`
+	Tenant     string           `json:"tenant"`
+	Weights    RankingWeights   `json:"weights"`
+	Total      int              `json:"total"`
+	Results    []RankedBook     `json:"results"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// SynonymSet is a group of words or phrases a search treats as the same,
+// e.g. "sci-fi" and "science fiction": a query with one of them also
+// finds books with the others. A set with a Language only applies to
+// searches in that language.
+type SynonymSet struct {
+	ID       int      `json:"id"`
+	Language string   `json:"language,omitempty"`
+	Terms    []string `json:"terms"`
+}
+
+// Validate checks the set and normalizes it: terms are trimmed and
+// lowercased, duplicates dropped, and the language code normalized.
+func (s *SynonymSet) Validate() error {
+	if s.Language != "" {
+		if !ValidLanguage(s.Language) {
+			return ErrInvalidLanguage
+		}
+		s.Language = NormalizeLanguage(s.Language)
+	}
+	terms := []string{}
+	for _, t := range s.Terms {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+		if t == "" {
+			return errors.New("terms must not be empty")
+		}
+		if !slices.Contains(terms, t) {
+			terms = append(terms, t)
+		}
+	}
+	if len(terms) < 2 {
+		return errors.New("a synonym set needs at least two different terms")
+	}
+	s.Terms = terms
+	return nil
+}
+
+// StopWords are the words of a language a search ignores, such as "the"
+// and "of".
+type StopWords struct {
+	Language string   `json:"language"`
+	Words    []string `json:"words"`
+}
+
+// QueryExpansion shows how a query is searched for: the stop words
+// dropped from it, and the alternatives it expands into with synonyms.
+// A book matches when it has every word of one alternative.
+type QueryExpansion struct {
+	Query            string   `json:"query"`
+	Language         string   `json:"language"`
+	StopWordsRemoved []string `json:"stop_words_removed"`
+	Alternatives     []string `json:"alternatives"`
+}
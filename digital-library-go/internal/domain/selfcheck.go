@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Outcomes of a self-check. A skipped check does not apply to how the
+// server is configured.
+const (
+	CheckOK      = "ok"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+// CheckResult is the outcome of one self-check, with what it found or
+// why it failed or was skipped.
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Detail     string  `json:"detail,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// SelfCheckReport is the outcome of every self-check, failed if any of
+// them failed.
+type SelfCheckReport struct {
+	Status    string        `json:"status"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// Failed lists the checks that failed.
+func (r *SelfCheckReport) Failed() []CheckResult {
+	failed := []CheckResult{}
+	for _, c := range r.Checks {
+		if c.Status == CheckFailed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
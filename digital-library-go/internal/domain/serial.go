@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Publication frequencies of a serial subscription.
+const (
+	FrequencyWeekly    = "weekly"
+	FrequencyBiweekly  = "biweekly"
+	FrequencyMonthly   = "monthly"
+	FrequencyBimonthly = "bimonthly"
+	FrequencyQuarterly = "quarterly"
+	FrequencyAnnual    = "annual"
+)
+
+// Frequencies lists every publication frequency.
+var Frequencies = []string{FrequencyWeekly, FrequencyBiweekly, FrequencyMonthly, FrequencyBimonthly, FrequencyQuarterly, FrequencyAnnual}
+
+// Subscription statuses. Canceled subscriptions keep their issues but no
+// more are predicted.
+const (
+	SubscriptionActive   = "active"
+	SubscriptionCanceled = "canceled"
+)
+
+// Serial issue statuses. An issue is predicted as expected, then either
+// received or, when it is late, claimed from the vendor until it arrives.
+const (
+	IssueExpected = "expected"
+	IssueReceived = "received"
+	IssueClaimed  = "claimed"
+)
+
+// DefaultClaimAfterDays is how long past its expected date an issue is
+// late, unless the subscription says otherwise.
+const DefaultClaimAfterDays = 14
+
+var issnPattern = regexp.MustCompile(`^\d{4}-\d{3}[\dX]$`)
+
+// Serial is a periodical title, such as a magazine or journal. Unlike a
+// book it is received issue by issue, through subscriptions, rather than
+// as copies.
+type Serial struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	ISSN      string    `json:"issn,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Serial) Validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return errors.New("title must not be empty")
+	}
+	if s.ISSN != "" && !ValidISSN(s.ISSN) {
+		return errors.New("issn must look like 1234-567X and have a valid check digit")
+	}
+	return nil
+}
+
+// ValidISSN reports whether issn is written as 1234-567X with a correct
+// check digit.
+func ValidISSN(issn string) bool {
+	if !issnPattern.MatchString(issn) {
+		return false
+	}
+	digits := strings.ReplaceAll(issn, "-", "")
+	sum := 0
+	for i := 0; i < 7; i++ {
+		sum += int(digits[i]-'0') * (8 - i)
+	}
+	check := (11 - sum%11) % 11
+	want := byte('0' + check)
+	if check == 10 {
+		want = 'X'
+	}
+	return digits[7] == want
+}
+
+// Subscription is the library's subscription to a serial through a
+// vendor, from which its issues are predicted. The first issue, numbered
+// FirstVolume and FirstIssue, is expected on StartsOn and each next one
+// a Frequency later, until EndsOn. Issue numbers restart at 1 in a new
+// volume after IssuesPerVolume issues, or never when that is 0. Location
+// is where received issues are shelved. Status and Predicted, the number
+// of issues predicted so far, are set by the server.
+type Subscription struct {
+	ID              int        `json:"id"`
+	SerialID        int        `json:"serial_id"`
+	VendorID        int        `json:"vendor_id,omitempty"`
+	Frequency       string     `json:"frequency"`
+	StartsOn        time.Time  `json:"starts_on"`
+	EndsOn          *time.Time `json:"ends_on,omitempty"`
+	FirstVolume     int        `json:"first_volume"`
+	FirstIssue      int        `json:"first_issue"`
+	IssuesPerVolume int        `json:"issues_per_volume"`
+	ClaimAfterDays  int        `json:"claim_after_days"`
+	Location        string     `json:"location,omitempty"`
+	Status          string     `json:"status"`
+	Predicted       int        `json:"predicted"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func (s *Subscription) Validate() error {
+	if !slices.Contains(Frequencies, s.Frequency) {
+		return fmt.Errorf("frequency must be one of %s", strings.Join(Frequencies, ", "))
+	}
+	if s.StartsOn.IsZero() {
+		return errors.New("starts_on is required")
+	}
+	if s.EndsOn != nil && s.EndsOn.Before(s.StartsOn) {
+		return errors.New("ends_on must not be before starts_on")
+	}
+	if s.FirstVolume < 0 || s.FirstIssue < 0 || s.IssuesPerVolume < 0 || s.ClaimAfterDays < 0 {
+		return errors.New("first_volume, first_issue, issues_per_volume and claim_after_days must not be negative")
+	}
+	if s.IssuesPerVolume > 0 && s.FirstIssue > s.IssuesPerVolume {
+		return errors.New("first_issue must not be past issues_per_volume")
+	}
+	return nil
+}
+
+// Defaults fills in the numbering and claim period a subscription was
+// created without: volume 1, issue 1 and DefaultClaimAfterDays.
+func (s *Subscription) Defaults() {
+	if s.FirstVolume == 0 {
+		s.FirstVolume = 1
+	}
+	if s.FirstIssue == 0 {
+		s.FirstIssue = 1
+	}
+	if s.ClaimAfterDays == 0 {
+		s.ClaimAfterDays = DefaultClaimAfterDays
+	}
+}
+
+// Expected returns the volume, number and expected date of the n-th
+// issue of the subscription, counting from 0.
+func (s *Subscription) Expected(n int) (volume, number int, on time.Time) {
+	switch s.Frequency {
+	case FrequencyWeekly:
+		on = s.StartsOn.AddDate(0, 0, 7*n)
+	case FrequencyBiweekly:
+		on = s.StartsOn.AddDate(0, 0, 14*n)
+	case FrequencyMonthly:
+		on = s.StartsOn.AddDate(0, n, 0)
+	case FrequencyBimonthly:
+		on = s.StartsOn.AddDate(0, 2*n, 0)
+	case FrequencyQuarterly:
+		on = s.StartsOn.AddDate(0, 3*n, 0)
+	default:
+		on = s.StartsOn.AddDate(n, 0, 0)
+	}
+
+	if s.IssuesPerVolume == 0 {
+		return s.FirstVolume, s.FirstIssue + n, on
+	}
+	i := s.FirstIssue - 1 + n
+	return s.FirstVolume + i/s.IssuesPerVolume, i%s.IssuesPerVolume + 1, on
+}
+
+// SerialIssue is one issue of a serial, predicted from a subscription.
+// Claims counts how often it was claimed from the vendor.
+type SerialIssue struct {
+	ID             int        `json:"id"`
+	SubscriptionID int        `json:"subscription_id"`
+	SerialID       int        `json:"serial_id"`
+	Volume         int        `json:"volume"`
+	Number         int        `json:"number"`
+	ExpectedOn     time.Time  `json:"expected_on"`
+	Status         string     `json:"status"`
+	ReceivedAt     *time.Time `json:"received_at,omitempty"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty"`
+	Claims         int        `json:"claims"`
+	Note           string     `json:"note,omitempty"`
+}
+
+// Label names the issue as in holdings, e.g. "v.12:no.3".
+func (i *SerialIssue) Label() string {
+	return fmt.Sprintf("v.%d:no.%d", i.Volume, i.Number)
+}
+
+// Late reports whether an issue not yet received is due to be claimed:
+// claimAfter past its expected date, or past its last claim.
+func (i *SerialIssue) Late(now time.Time, claimAfter time.Duration) bool {
+	switch i.Status {
+	case IssueExpected:
+		return now.Sub(i.ExpectedOn) >= claimAfter
+	case IssueClaimed:
+		return i.ClaimedAt != nil && now.Sub(*i.ClaimedAt) >= claimAfter
+	}
+	return false
+}
+
+// Holdings summarises the issues of a serial the library has. Statement
+// lists the received issues by volume in compressed form, e.g.
+// "v.11:no.1-12; v.12:no.1-3,5", and Missing those late or claimed.
+type Holdings struct {
+	SerialID  int      `json:"serial_id"`
+	Title     string   `json:"title"`
+	ISSN      string   `json:"issn,omitempty"`
+	Locations []string `json:"locations"`
+	Statement string   `json:"statement"`
+	Received  int      `json:"received"`
+	Expected  int      `json:"expected"`
+	Claimed   int      `json:"claimed"`
+	Missing   []string `json:"missing"`
+}
+
+// HoldingsStatement compresses received issues, sorted by volume and
+// number without duplicates, into a holdings statement.
+func HoldingsStatement(issues []SerialIssue) string {
+	var volumes []string
+	for start := 0; start < len(issues); {
+		volume := issues[start].Volume
+		end := start
+		for end < len(issues) && issues[end].Volume == volume {
+			end++
+		}
+
+		var ranges []string
+		for i := start; i < end; {
+			j := i
+			for j+1 < end && issues[j+1].Number == issues[j].Number+1 {
+				j++
+			}
+			if j == i {
+				ranges = append(ranges, fmt.Sprint(issues[i].Number))
+			} else {
+				ranges = append(ranges, fmt.Sprintf("%d-%d", issues[i].Number, issues[j].Number))
+			}
+			i = j + 1
+		}
+		volumes = append(volumes, fmt.Sprintf("v.%d:no.%s", volume, strings.Join(ranges, ",")))
+		start = end
+	}
+	return strings.Join(volumes, "; ")
+}
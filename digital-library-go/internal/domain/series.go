@@ -0,0 +1,31 @@
+package domain
+
+import "errors"
+
+// Series groups titles in reading order, e.g. "The Expanse #3".
+type Series struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Entries     []SeriesEntry `json:"entries"`
+}
+
+func (s *Series) Validate() error {
+	if s.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	return nil
+}
+
+// SeriesEntry places a book in a series. Positions need not be whole
+// numbers, so a novella can sit at 2.5 between books 2 and 3.
+type SeriesEntry struct {
+	BookID   int     `json:"book_id"`
+	Position float64 `json:"position"`
+}
+
+// SeriesBook is a book listed in its series' reading order.
+type SeriesBook struct {
+	Position float64 `json:"position"`
+	Book     Book    `json:"book"`
+}
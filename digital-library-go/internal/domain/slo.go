@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Alerts raised by SLO burn rates, following the multiwindow,
+// multi-burn-rate alerts of the Google SRE workbook: a page when the
+// error budget of a 30-day window would be gone within days, a ticket
+// when it is being spent faster than it lasts.
+const (
+	SLOAlertNone   = ""
+	SLOAlertTicket = "ticket"
+	SLOAlertPage   = "page"
+)
+
+// BurnRateWindows are the windows burn rates are given over, shortest
+// first.
+var BurnRateWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"3d", 72 * time.Hour},
+}
+
+// SLO is a latency objective of a route, e.g. "GET /books/:id" as gin
+// names it: Objective percent of its requests answered within Latency,
+// and without a server error.
+type SLO struct {
+	Route     string
+	Latency   time.Duration
+	Objective float64
+}
+
+// ParseSLOs reads objectives from JSON of the form
+// {"GET /books/:id": {"latency": "100ms", "objective": 99.5}}.
+func ParseSLOs(data []byte) ([]SLO, error) {
+	var raw map[string]struct {
+		Latency   string  `json:"latency"`
+		Objective float64 `json:"objective"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	slos := []SLO{}
+	for route, r := range raw {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("%s: route must be a method and a path, e.g. GET /books/:id", route)
+		}
+		latency, err := time.ParseDuration(r.Latency)
+		if err != nil || latency <= 0 {
+			return nil, fmt.Errorf("%s: latency must be a duration such as 250ms", route)
+		}
+		if r.Objective <= 0 || r.Objective >= 100 {
+			return nil, fmt.Errorf("%s: objective must be a percentage above 0 and below 100", route)
+		}
+		slos = append(slos, SLO{Route: route, Latency: latency, Objective: r.Objective})
+	}
+	sort.Slice(slos, func(i, j int) bool { return slos[i].Route < slos[j].Route })
+	return slos, nil
+}
+
+// ErrorBudget is the share of requests allowed to miss the objective.
+func (s *SLO) ErrorBudget() float64 {
+	return 1 - s.Objective/100
+}
+
+// SLOStatus is how a route is doing against its objective over the SLO
+// window. Compliance is the percentage of good requests, BudgetRemaining
+// the share of the error budget left, negative once it is overspent, and
+// BurnRates how many times faster than the budget allows it is being
+// spent over each of BurnRateWindows.
+type SLOStatus struct {
+	Route           string             `json:"route"`
+	Latency         string             `json:"latency"`
+	Objective       float64            `json:"objective"`
+	Window          string             `json:"window"`
+	Requests        int                `json:"requests"`
+	Good            int                `json:"good"`
+	Compliance      float64            `json:"compliance"`
+	BudgetRemaining float64            `json:"budget_remaining"`
+	BurnRates       map[string]float64 `json:"burn_rates"`
+	Alert           string             `json:"alert,omitempty"`
+}
+
+// BurnRateAlert picks the alert for a route's burn rates: a page when
+// both the 1h and 5m rates are above 14.4, or the 6h and 30m rates above
+// 6, and a ticket when the 3d and 6h rates are above 1. The short window
+// of each pair stops the alert soon after the burning does.
+func BurnRateAlert(rates map[string]float64) string {
+	switch {
+	case rates["1h"] > 14.4 && rates["5m"] > 14.4, rates["6h"] > 6 && rates["30m"] > 6:
+		return SLOAlertPage
+	case rates["3d"] > 1 && rates["6h"] > 1:
+		return SLOAlertTicket
+	}
+	return SLOAlertNone
+}
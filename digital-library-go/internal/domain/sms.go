@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var ErrInvalidPhone = errors.New("phone must be in international format, e.g. +441632960961")
+
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// ValidPhone reports whether phone is an E.164 number.
+func ValidPhone(phone string) bool {
+	return phonePattern.MatchString(phone)
+}
+
+// MaxSMSLength is the longest text sent; longer ones are cut short.
+const MaxSMSLength = 640
+
+// SMSSettings configure the library's text messages. From is the number
+// or messaging service they are sent from. Templates map a notification
+// kind to a text/template over SMSTemplateData; kinds without one send
+// the plain message.
+type SMSSettings struct {
+	From      string            `json:"from"`
+	Templates map[string]string `json:"templates"`
+}
+
+func (s *SMSSettings) Validate() error {
+	if !ValidPhone(s.From) && !strings.HasPrefix(s.From, "MG") {
+		return errors.New("from must be a phone number in international format or a messaging service ID")
+	}
+	for kind, text := range s.Templates {
+		if !slices.Contains(NotificationKinds, kind) {
+			return fmt.Errorf("unknown notification kind %s", kind)
+		}
+		if _, err := template.New(kind).Parse(text); err != nil {
+			return fmt.Errorf("template for %s: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// SMSTemplateData is what SMS templates can refer to, e.g.
+// "Hi {{.Name}}, {{.Message}}".
+type SMSTemplateData struct {
+	Name    string
+	Kind    string
+	Message string
+}
+
+// SMS delivery statuses, as reported by the provider.
+const (
+	SMSQueued      = "queued"
+	SMSSent        = "sent"
+	SMSDelivered   = "delivered"
+	SMSUndelivered = "undelivered"
+	SMSFailed      = "failed"
+)
+
+// SMSMessage is a text sent to a member and its latest delivery status.
+// ID is the provider's message ID.
+type SMSMessage struct {
+	ID        string    `json:"id"`
+	MemberID  int       `json:"member_id"`
+	Kind      string    `json:"kind"`
+	To        string    `json:"to"`
+	Status    string    `json:"status"`
+	ErrorCode string    `json:"error_code,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Strategies for changes a client made to a book that has also changed
+// on the server since.
+const (
+	// SyncMerge keeps the edits of both sides when they touch different
+	// fields, and reports a conflict when they touch the same one.
+	SyncMerge = "merge"
+	// SyncLastWriterWins keeps whichever side wrote last.
+	SyncLastWriterWins = "last_writer_wins"
+)
+
+// Operations of a ClientChange.
+const (
+	SyncCreate = "create"
+	SyncUpdate = "update"
+	SyncDelete = "delete"
+)
+
+// Outcomes of a ClientChange.
+const (
+	// SyncApplied means the change was saved as sent.
+	SyncApplied = "applied"
+	// SyncMerged means the change was saved together with the server's
+	// own changes to other fields.
+	SyncMerged = "merged"
+	// SyncDiscarded means the server's copy was written later and was
+	// kept.
+	SyncDiscarded = "discarded"
+	// SyncConflict means the change was not saved and needs resolving
+	// by hand.
+	SyncConflict = "conflict"
+	// SyncRejected means the change was invalid.
+	SyncRejected = "rejected"
+)
+
+// ClientChange is a change a client made to the catalog while offline.
+type ClientChange struct {
+	// Ref is the client's own name for the change, echoed in its result
+	// so the client can match them up, e.g. to learn a new book's ID.
+	Ref    string `json:"ref"`
+	Op     string `json:"op"`
+	BookID int    `json:"book_id"`
+	// BaseRevision is the revision of the book the client edited.
+	BaseRevision uint64 `json:"base_revision"`
+	// Base is the book as the client last synced it, which merging
+	// compares both sides against.
+	Base *Book `json:"base,omitempty"`
+	// Book is the book as the client left it, for creates and updates.
+	Book      *Book     `json:"book,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (c *ClientChange) Validate() error {
+	switch c.Op {
+	case SyncCreate, SyncUpdate:
+		if c.Book == nil {
+			return errors.New("book is required to " + c.Op)
+		}
+	case SyncDelete:
+	default:
+		return errors.New("op must be create, update or delete")
+	}
+	return nil
+}
+
+// SyncResult is what became of a ClientChange. Server is the server's
+// copy of the book afterwards, if it still exists. Fields lists the
+// fields both sides changed when there is a conflict.
+type SyncResult struct {
+	Ref     string   `json:"ref"`
+	BookID  int      `json:"book_id"`
+	Outcome string   `json:"outcome"`
+	Fields  []string `json:"fields,omitempty"`
+	Server  *Book    `json:"server,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// serverFields are set by the server and never merged.
+var serverFields = map[string]bool{
+	"id":         true,
+	"added_at":   true,
+	"featured":   true,
+	"revision":   true,
+	"updated_at": true,
+}
+
+// MergeBooks applies the edits a client made to base onto the server's
+// copy, field by field as they appear in JSON. It returns the fields
+// that both sides changed to different values; the merged book is only
+// meant to be saved when there are none.
+func MergeBooks(base, server, client Book) (Book, []string) {
+	b, s, c := jsonFields(base), jsonFields(server), jsonFields(client)
+	merged := maps.Clone(s)
+	conflicts := []string{}
+	names := map[string]bool{}
+	for _, m := range []map[string]json.RawMessage{b, s, c} {
+		for name := range m {
+			names[name] = true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		if serverFields[name] || bytes.Equal(c[name], b[name]) || bytes.Equal(c[name], s[name]) {
+			continue
+		}
+		if !bytes.Equal(s[name], b[name]) {
+			conflicts = append(conflicts, name)
+			continue
+		}
+		if c[name] == nil {
+			delete(merged, name)
+		} else {
+			merged[name] = c[name]
+		}
+	}
+
+	var book Book
+	data, _ := json.Marshal(merged)
+	json.Unmarshal(data, &book)
+	return book, conflicts
+}
+
+// jsonFields splits a book into its JSON fields. Fields left out by
+// omitempty are absent.
+func jsonFields(b Book) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	data, _ := json.Marshal(b)
+	json.Unmarshal(data, &fields)
+	return fields
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Task statuses.
+const (
+	TaskQueued    = "queued"
+	TaskRunning   = "running"
+	TaskCompleted = "completed"
+	TaskFailed    = "failed"
+)
+
+// Kinds of task. Each kind has its own queue.
+const (
+	TaskCatalogImport       = "catalog_import"
+	TaskHoldExpiry          = "hold_expiry"
+	TaskSerialPrediction    = "serial_prediction"
+	TaskAuthorDuplicateScan = "author_duplicate_scan"
+	TaskCriticalUpdate      = "critical_update"
+)
+
+// TaskKinds lists every kind of task.
+var TaskKinds = []string{TaskCatalogImport, TaskHoldExpiry, TaskSerialPrediction, TaskAuthorDuplicateScan, TaskCriticalUpdate}
+
+// Task priorities. Tasks a user started go before scheduled ones, unless
+// a scheduled task has waited too long.
+const (
+	TaskPriorityUser      = "user"
+	TaskPriorityScheduled = "scheduled"
+)
+
+// Task is a job run in the background by the task queue. Processed counts
+// the items done out of Total, and ErrorCount those that failed; the
+// errors themselves are downloaded separately once the task completes.
+// Result is set on completion and depends on the kind of task. Tasks of
+// a registered task type keep the payload they were queued with. A task
+// that runs past its timeout fails with Error, unless it has retries
+// left; Attempts counts its runs. Logs holds what the task logged, read
+// separately.
+type Task struct {
+	ID         int             `json:"id"`
+	Kind       string          `json:"kind"`
+	Priority   string          `json:"priority"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Status     string          `json:"status"`
+	QueuedAt   time.Time       `json:"queued_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Total      int             `json:"total"`
+	Processed  int             `json:"processed"`
+	ErrorCount int             `json:"errors"`
+	Attempts   int             `json:"attempts"`
+	Error      string          `json:"error,omitempty"`
+	Result     any             `json:"result,omitempty"`
+	Errors     []RowError      `json:"-"`
+	Logs       []TaskLogLine   `json:"-"`
+}
+
+// TaskLogLine is a line a task logged. Lines are numbered from 1 in the
+// order they were logged, across every attempt of the task.
+type TaskLogLine struct {
+	Line    int       `json:"line"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message"`
+}
+
+// Finished reports whether the task has completed or failed.
+func (t *Task) Finished() bool {
+	return t.Status == TaskCompleted || t.Status == TaskFailed
+}
+
+// RowError says why one line of an input file could not be processed.
+type RowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// TaskQueueStatus reports on the queue of one kind of task: how many of
+// its tasks may run at once, how many do, and how many wait.
+type TaskQueueStatus struct {
+	Kind        string `json:"kind"`
+	Concurrency int    `json:"concurrency"`
+	Running     int    `json:"running"`
+	Queued      int    `json:"queued"`
+}
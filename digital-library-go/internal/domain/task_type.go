@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types of payload field.
+const (
+	FieldTypeString   = "string"
+	FieldTypeInteger  = "integer"
+	FieldTypeNumber   = "number"
+	FieldTypeBoolean  = "boolean"
+	FieldTypeDateTime = "date-time"
+	FieldTypeArray    = "array"
+	FieldTypeObject   = "object"
+)
+
+// PayloadField is one field of the JSON payload of a task type. Minimum
+// and Maximum bound integer and number fields.
+type PayloadField struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// TaskType is a named kind of task that can be queued with a JSON
+// payload, an object with the fields of Payload and no others.
+type TaskType struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Payload     []PayloadField `json:"payload"`
+}
+
+// TaskRunAt is the payload of tasks that act as of a time, now unless At
+// is given.
+type TaskRunAt struct {
+	At *time.Time `json:"at"`
+}
+
+// Time is when the task acts as of.
+func (p TaskRunAt) Time() time.Time {
+	if p.At == nil {
+		return time.Now()
+	}
+	return *p.At
+}
+
+// Validate checks a payload against the fields of the task type and
+// reports every field that does not fit. An empty payload is taken as an
+// empty object.
+func (t *TaskType) Validate(payload json.RawMessage) error {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		payload = json.RawMessage("{}")
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &values); err != nil || values == nil {
+		return Invalid("payload must be a JSON object")
+	}
+
+	problems := []string{}
+	known := map[string]bool{}
+	for _, f := range t.Payload {
+		known[f.Name] = true
+		raw, ok := values[f.Name]
+		if !ok || string(raw) == "null" {
+			if f.Required {
+				problems = append(problems, f.Name+" is required")
+			}
+			continue
+		}
+		if problem := f.check(raw); problem != "" {
+			problems = append(problems, f.Name+" "+problem)
+		}
+	}
+	unknown := []string{}
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		problems = append(problems, name+" is not a field of "+t.Name)
+	}
+	if len(problems) > 0 {
+		return Invalid("invalid payload: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// check returns what is wrong with a value of the field, if anything.
+func (f *PayloadField) check(raw json.RawMessage) string {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "is not valid JSON"
+	}
+
+	switch f.Type {
+	case FieldTypeString:
+		if _, ok := v.(string); !ok {
+			return "must be a string"
+		}
+	case FieldTypeDateTime:
+		s, ok := v.(string)
+		if _, err := time.Parse(time.RFC3339, s); !ok || err != nil {
+			return "must be a date and time such as 2024-05-01T02:00:00Z"
+		}
+	case FieldTypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return "must be true or false"
+		}
+	case FieldTypeArray:
+		if _, ok := v.([]any); !ok {
+			return "must be an array"
+		}
+	case FieldTypeObject:
+		if _, ok := v.(map[string]any); !ok {
+			return "must be an object"
+		}
+	case FieldTypeInteger, FieldTypeNumber:
+		n, ok := v.(json.Number)
+		if !ok {
+			return "must be a number"
+		}
+		x, err := n.Float64()
+		if err != nil {
+			return "must be a number"
+		}
+		if f.Type == FieldTypeInteger && x != math.Trunc(x) {
+			return "must be a whole number"
+		}
+		if f.Minimum != nil && x < *f.Minimum {
+			return fmt.Sprintf("must be at least %g", *f.Minimum)
+		}
+		if f.Maximum != nil && x > *f.Maximum {
+			return fmt.Sprintf("must be at most %g", *f.Maximum)
+		}
+	}
+	return ""
+}
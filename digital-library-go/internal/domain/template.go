@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template channels: where a rendered template ends up.
+const (
+	TemplateEmail   = "email"
+	TemplateSMS     = "sms"
+	TemplateReceipt = "receipt"
+)
+
+// Receipt kinds, printed at the desk or by self-check machines.
+const (
+	ReceiptCheckout = "checkout"
+	ReceiptHoldSlip = "hold_slip"
+)
+
+// ReceiptKinds lists every kind of printed receipt.
+var ReceiptKinds = []string{ReceiptCheckout, ReceiptHoldSlip}
+
+// TemplateKinds returns the kinds a channel has templates for: the
+// notification kinds for email and SMS, the receipt kinds for receipts.
+func TemplateKinds(channel string) []string {
+	switch channel {
+	case TemplateEmail, TemplateSMS:
+		return NotificationKinds
+	case TemplateReceipt:
+		return ReceiptKinds
+	}
+	return nil
+}
+
+// MessageTemplate is the wording of one kind of message on one channel.
+// Text is a Go text/template; emails may also have a Subject and an HTML
+// version, and receipts an HTML version for printing from a browser, in
+// html/template syntax. Tenant is the host name it applies to, or empty
+// for the default every tenant without its own uses.
+type MessageTemplate struct {
+	Tenant  string `json:"tenant"`
+	Channel string `json:"channel"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject,omitempty"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+	// UpdatedAt is when an admin last set the template; built-in
+	// templates have none.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// TemplateFuncs are the functions templates may call besides the
+// built-in ones, e.g. {{date .DueAt}}.
+var TemplateFuncs = map[string]any{
+	"date":     func(t time.Time) string { return t.Format("2 Jan 2006") },
+	"datetime": func(t time.Time) string { return t.Format("2 Jan 2006 15:04") },
+	"upper":    strings.ToUpper,
+}
+
+// Validate checks the template parses and only refers to variables its
+// kind provides, by rendering it against TemplateSample. Branches the
+// sample does not take are not checked.
+func (t *MessageTemplate) Validate() error {
+	if !slices.Contains(TemplateKinds(t.Channel), t.Kind) {
+		return fmt.Errorf("unknown %s template kind %s", t.Channel, t.Kind)
+	}
+	if strings.TrimSpace(t.Text) == "" {
+		return errors.New("text must not be empty")
+	}
+	if t.Subject != "" && t.Channel != TemplateEmail {
+		return errors.New("only email templates have a subject")
+	}
+	if t.HTML != "" && t.Channel == TemplateSMS {
+		return errors.New("SMS templates have no HTML version")
+	}
+	_, err := t.Render(TemplateSample(t.Channel, t.Kind, "Digital Library"))
+	return err
+}
+
+// RenderedMessage is a template filled in. Subject and HTML are empty
+// when the template has none.
+type RenderedMessage struct {
+	Subject string `json:"subject,omitempty"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// Render fills in the template with data. Referring to a variable data
+// does not have is an error.
+func (t *MessageTemplate) Render(data any) (RenderedMessage, error) {
+	var msg RenderedMessage
+	var err error
+	if msg.Subject, err = renderText("subject", t.Subject, data); err != nil {
+		return msg, err
+	}
+	if msg.Text, err = renderText("text", t.Text, data); err != nil {
+		return msg, err
+	}
+	if t.HTML == "" {
+		return msg, nil
+	}
+	tmpl, err := htmltemplate.New("html").Funcs(TemplateFuncs).Parse(t.HTML)
+	if err != nil {
+		return msg, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return msg, err
+	}
+	msg.HTML = buf.String()
+	return msg, nil
+}
+
+func renderText(name, text string, data any) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(name).Funcs(TemplateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// NotificationTemplateData is what email and SMS templates can refer to,
+// e.g. "Hi {{.Name}}, {{.Message}}".
+type NotificationTemplateData struct {
+	Library string
+	Name    string
+	Kind    string
+	Title   string
+	Message string
+}
+
+// ReceiptTemplateData is what checkout receipt templates can refer to.
+// Card is the last four digits of the member's card number.
+type ReceiptTemplateData struct {
+	Library   string
+	Name      string
+	Card      string
+	Items     []ReceiptItem
+	PrintedAt time.Time
+}
+
+// ReceiptItem is a loan listed on a checkout receipt.
+type ReceiptItem struct {
+	Title  string
+	Author string
+	DueAt  time.Time
+}
+
+// HoldSlipTemplateData is what hold slip templates can refer to. The
+// slip goes in a book put on the hold shelf for the member.
+type HoldSlipTemplateData struct {
+	Library   string
+	Name      string
+	Card      string
+	Title     string
+	Author    string
+	PickupBy  time.Time
+	PrintedAt time.Time
+}
+
+// TemplateSample returns example data for a kind of template at a
+// library, to validate and preview templates with.
+func TemplateSample(channel, kind, library string) any {
+	now := time.Date(2024, 9, 2, 14, 30, 0, 0, time.UTC)
+	switch {
+	case channel == TemplateReceipt && kind == ReceiptCheckout:
+		return ReceiptTemplateData{
+			Library: library,
+			Name:    "Ada Lovelace",
+			Card:    "4821",
+			Items: []ReceiptItem{
+				{Title: "Dune", Author: "Frank Herbert", DueAt: now.AddDate(0, 0, 14)},
+				{Title: "Emma", Author: "Jane Austen", DueAt: now.AddDate(0, 0, 14)},
+			},
+			PrintedAt: now,
+		}
+	case channel == TemplateReceipt && kind == ReceiptHoldSlip:
+		return HoldSlipTemplateData{
+			Library:   library,
+			Name:      "Ada Lovelace",
+			Card:      "4821",
+			Title:     "Dune",
+			Author:    "Frank Herbert",
+			PickupBy:  now.Add(DefaultPickupWindow),
+			PrintedAt: now,
+		}
+	}
+	n := Notification{Kind: kind, Message: "Dune is ready for pickup until 9 Sep 2024."}
+	return NotificationTemplateData{Library: library, Name: "Ada Lovelace", Kind: kind, Title: n.Title(), Message: n.Message}
+}
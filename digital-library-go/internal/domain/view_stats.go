@@ -0,0 +1,15 @@
+package domain
+
+// DailyViews is the number of detail-page views of a book on one day
+// (UTC, formatted 2006-01-02).
+type DailyViews struct {
+	Date  string `json:"date"`
+	Views int    `json:"views"`
+}
+
+// BookViews is a book's total views over a date range.
+type BookViews struct {
+	BookID int    `json:"book_id"`
+	Title  string `json:"title"`
+	Views  int    `json:"views"`
+}
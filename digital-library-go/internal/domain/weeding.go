@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Defaults for the weeding report: books published at least ten years
+// ago that have not been borrowed in the last three years.
+const (
+	DefaultWeedingMinAge   = 10
+	DefaultWeedingMonths   = 36
+	DefaultWeedingMaxLoans = 0
+)
+
+// WeedingCriteria selects the books to consider weeding. A book is a
+// candidate when it was published at least MinAge years ago and was
+// borrowed at most MaxLoans times in the last Months months.
+type WeedingCriteria struct {
+	MinAge   int `json:"min_age"`
+	Months   int `json:"months"`
+	MaxLoans int `json:"max_loans"`
+}
+
+func (c *WeedingCriteria) Validate() error {
+	if c.MinAge < 0 {
+		return errors.New("min_age must not be negative")
+	}
+	if c.Months < 1 {
+		return errors.New("months must be at least 1")
+	}
+	if c.MaxLoans < 0 {
+		return errors.New("max_loans must not be negative")
+	}
+	return nil
+}
+
+// WeedingCandidate is a book that meets the weeding criteria, with the
+// copies still in circulation that could be withdrawn.
+type WeedingCandidate struct {
+	BookID       int        `json:"book_id"`
+	Title        string     `json:"title"`
+	Author       string     `json:"author"`
+	Year         int        `json:"year"`
+	Age          int        `json:"age"`
+	Loans        int        `json:"loans"`
+	LastLoanedAt *time.Time `json:"last_loaned_at,omitempty"`
+	Copies       []Copy     `json:"copies"`
+}
+
+// WeedingReport lists the weeding candidates, least borrowed and oldest
+// first.
+type WeedingReport struct {
+	Criteria    WeedingCriteria    `json:"criteria"`
+	Since       time.Time          `json:"since"`
+	Candidates  []WeedingCandidate `json:"candidates"`
+	CopiesTotal int                `json:"copies_total"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// PullItem is a copy staff should take off the shelf to fill the oldest
+// hold on its book. Barcode and Location are empty if the book has no
+// copy on record.
+type PullItem struct {
+	BookID     int       `json:"book_id"`
+	Title      string    `json:"title"`
+	Barcode    string    `json:"barcode,omitempty"`
+	Location   string    `json:"location,omitempty"`
+	CallNumber string    `json:"call_number,omitempty"`
+	HoldID     int       `json:"hold_id"`
+	MemberID   int       `json:"member_id"`
+	MemberName string    `json:"member_name"`
+	PlacedAt   time.Time `json:"placed_at"`
+}
@@ -0,0 +1,147 @@
+// Package email sends notifications to members by email over SMTP.
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// Renderer words notifications with templates, reporting false for kinds
+// without one.
+type Renderer interface {
+	RenderNotification(channel string, member domain.Member, n domain.Notification) (domain.RenderedMessage, bool, error)
+}
+
+// Sender delivers notifications through an SMTP relay.
+type Sender struct {
+	addr      string
+	from      string
+	auth      smtp.Auth
+	templates Renderer
+}
+
+// NewSender returns a sender for the relay at addr ("host:port"). With an
+// empty username, mail is sent without authentication. Notifications
+// whose kind has an email template in templates are worded with it; the
+// others are sent as plain text under their title.
+func NewSender(addr, from, username, password string, templates Renderer) *Sender {
+	s := &Sender{addr: addr, from: from, templates: templates}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+func (s *Sender) Name() string {
+	return domain.ChannelEmail
+}
+
+// Send emails the notification to the member. Members without an email
+// address are skipped.
+func (s *Sender) Send(ctx context.Context, member domain.Member, n domain.Notification) error {
+	if member.Email == "" {
+		return nil
+	}
+	if strings.ContainsAny(member.Email, "\r\n") {
+		return fmt.Errorf("invalid email address for member %d", member.ID)
+	}
+	content := domain.RenderedMessage{Subject: n.Title(), Text: n.Message}
+	rendered, ok, err := s.templates.RenderNotification(domain.TemplateEmail, member, n)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if rendered.Subject == "" {
+			rendered.Subject = content.Subject
+		}
+		content = rendered
+	}
+	subject := strings.Join(strings.Fields(content.Subject), " ")
+	headers := []string{
+		"From: " + s.from,
+		"To: " + member.Email,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"Date: " + n.CreatedAt.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+	}
+	msg, err := body(headers, content)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.addr, s.auth, s.from, []string{member.Email}, msg)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("sending email: %w", ctx.Err())
+	}
+}
+
+// Check connects to the relay and greets it, without sending mail, to
+// tell whether it is reachable.
+func (s *Sender) Check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(s.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if err := c.Hello("localhost"); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// body appends the message content to the headers: plain text, or plain
+// text and HTML as alternatives when there is an HTML version.
+func body(headers []string, content domain.RenderedMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	if content.HTML == "" {
+		headers = append(headers, "Content-Type: text/plain; charset=UTF-8", "", content.Text)
+		buf.WriteString(strings.Join(headers, "\r\n"))
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	w := multipart.NewWriter(&parts)
+	for _, part := range []struct{ contentType, text string }{
+		{"text/plain; charset=UTF-8", content.Text},
+		{"text/html; charset=UTF-8", content.HTML},
+	} {
+		p, err := w.CreatePart(map[string][]string{"Content-Type": {part.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		p.Write([]byte(part.text))
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	headers = append(headers, "Content-Type: multipart/alternative; boundary="+w.Boundary(), "")
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n")
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
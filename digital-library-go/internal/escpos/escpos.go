@@ -0,0 +1,66 @@
+// Package escpos writes receipts for thermal printers in ESC/POS, the
+// command set of Epson receipt printers that most others understand too.
+// Text is sent in code page 437, the printers' default; characters it
+// lacks are replaced by '?'.
+package escpos
+
+import (
+	"bytes"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	esc = 0x1b
+	gs  = 0x1d
+)
+
+// feedLines is how far the paper is fed before cutting, so the last line
+// clears the cutter.
+const feedLines = 4
+
+// Document collects lines of a receipt.
+type Document struct {
+	buf bytes.Buffer
+}
+
+// New starts a receipt headed by title, printed bold and centred.
+func New(title string) *Document {
+	d := &Document{}
+	d.buf.Write([]byte{esc, '@'})    // initialize
+	d.buf.Write([]byte{esc, 't', 0}) // code page 437
+	if title != "" {
+		d.buf.Write([]byte{esc, 'a', 1})
+		d.Heading(title)
+		d.buf.Write([]byte{esc, 'a', 0})
+		d.buf.WriteByte('\n')
+	}
+	return d
+}
+
+// Heading adds a bold line.
+func (d *Document) Heading(text string) {
+	d.buf.Write([]byte{esc, 'E', 1})
+	d.Text(text)
+	d.buf.Write([]byte{esc, 'E', 0})
+}
+
+// Text adds a regular line. Long lines wrap on the printer.
+func (d *Document) Text(text string) {
+	encoded, _ := encoding.ReplaceUnsupported(charmap.CodePage437.NewEncoder()).Bytes([]byte(text))
+	for _, c := range encoded {
+		// Control characters would be taken as commands.
+		if c < 0x20 {
+			c = ' '
+		}
+		d.buf.WriteByte(c)
+	}
+	d.buf.WriteByte('\n')
+}
+
+// Bytes returns the receipt, ending with a feed and a partial cut.
+func (d *Document) Bytes() []byte {
+	out := bytes.Clone(d.buf.Bytes())
+	return append(out, gs, 'V', 66, feedLines)
+}
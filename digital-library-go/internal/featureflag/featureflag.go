@@ -0,0 +1,183 @@
+// Package featureflag turns optional features on and off at runtime,
+// for every tenant or for one. A tenant is a host name the library is
+// served on, so one server can run several sites that offer different
+// features.
+package featureflag
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Flags.
+const (
+	Reservations = "reservations"
+	Reviews      = "reviews"
+	Bookings     = "bookings"
+)
+
+// Known describes every flag. Flags are on unless configured off.
+var Known = map[string]string{
+	Reservations: "Members can place holds on books",
+	Reviews:      "Members can read, write and report book reviews",
+	Bookings:     "Members can book rooms and equipment",
+}
+
+var ErrUnknownFlag = errors.New("unknown feature flag")
+
+// Flag is the state of one flag: its default and the tenants that
+// override it.
+type Flag struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Enabled     bool            `json:"enabled"`
+	Tenants     map[string]bool `json:"tenants"`
+}
+
+// Store holds the flags. It is safe for concurrent use.
+type Store struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+	tenants map[string]map[string]bool
+}
+
+// New returns a store with every flag on.
+func New() *Store {
+	s := &Store{enabled: map[string]bool{}, tenants: map[string]map[string]bool{}}
+	for name := range Known {
+		s.enabled[name] = true
+		s.tenants[name] = map[string]bool{}
+	}
+	return s
+}
+
+// Parse builds a store from a comma-separated list of "name=on" or
+// "name=off", with "name@tenant=off" overriding one tenant, e.g.
+// "reviews=off,reviews@kids.example.org=on". Flags not listed are on.
+func Parse(spec string) (*Store, error) {
+	s := New()
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || (value != "on" && value != "off") {
+			return nil, fmt.Errorf("%q must be name=on or name=off", item)
+		}
+		name, tenant, scoped := strings.Cut(key, "@")
+		var err error
+		if scoped {
+			err = s.SetTenant(name, tenant, value == "on")
+		} else {
+			err = s.Set(name, value == "on")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, name)
+		}
+	}
+	return s, nil
+}
+
+// Replace sets every flag as spec says, as Parse reads it, dropping the
+// changes made since. The flags are left as they were if spec is
+// invalid.
+func (s *Store) Replace(spec string) error {
+	next, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enabled, s.tenants = next.enabled, next.tenants
+	return nil
+}
+
+// Enabled reports whether a flag is on for a tenant. Unknown flags are
+// off.
+func (s *Store) Enabled(name, tenant string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if on, ok := s.tenants[name][Tenant(tenant)]; ok {
+		return on
+	}
+	return s.enabled[name]
+}
+
+// For returns every flag's state for a tenant.
+func (s *Store) For(tenant string) map[string]bool {
+	flags := map[string]bool{}
+	for name := range Known {
+		flags[name] = s.Enabled(name, tenant)
+	}
+	return flags
+}
+
+// Flags lists the flags by name.
+func (s *Store) Flags() []Flag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	flags := make([]Flag, 0, len(Known))
+	for name, description := range Known {
+		tenants := make(map[string]bool, len(s.tenants[name]))
+		for t, on := range s.tenants[name] {
+			tenants[t] = on
+		}
+		flags = append(flags, Flag{Name: name, Description: description, Enabled: s.enabled[name], Tenants: tenants})
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// Set changes a flag's default, which tenant overrides still take
+// precedence over.
+func (s *Store) Set(name string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := Known[name]; !ok {
+		return ErrUnknownFlag
+	}
+	s.enabled[name] = enabled
+	return nil
+}
+
+// SetTenant turns a flag on or off for one tenant.
+func (s *Store) SetTenant(name, tenant string, enabled bool) error {
+	tenant = Tenant(tenant)
+	if tenant == "" {
+		return errors.New("tenant must be a host name")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := Known[name]; !ok {
+		return ErrUnknownFlag
+	}
+	s.tenants[name][tenant] = enabled
+	return nil
+}
+
+// ClearTenant removes a tenant's override, so the default applies to it
+// again.
+func (s *Store) ClearTenant(name, tenant string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := Known[name]; !ok {
+		return ErrUnknownFlag
+	}
+	delete(s.tenants[name], Tenant(tenant))
+	return nil
+}
+
+// Tenant normalizes a request's Host into a tenant: lower-cased and
+// without a port.
+func Tenant(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,180 @@
+// Package federation talks to consortium partners running this server:
+// it searches their catalogs over the JSON API and requests their books
+// for this library's members over NCIP.
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/ncip"
+)
+
+// maxResponseSize bounds what is read from a partner.
+const maxResponseSize = 4 << 20
+
+type Config struct {
+	// Name labels the partner's books in search results.
+	Name    string
+	BaseURL string
+	// AgencyID is this library's NCIP agency ID. PartnerAgencyID is the
+	// partner's, and Secret what the partner expects this library to
+	// authenticate with.
+	AgencyID        string
+	PartnerAgencyID string
+	Secret          string
+	// Account is the card number of this library's account at the
+	// partner, which interlibrary loans are requested under.
+	Account string
+	Client  *http.Client
+}
+
+type Partner struct {
+	cfg Config
+}
+
+// NewPartner returns a client for the partner. Pass a client from a
+// circuit breaker so outages fail fast.
+func NewPartner(cfg Config) *Partner {
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &Partner{cfg: cfg}
+}
+
+func (p *Partner) Name() string {
+	return p.cfg.Name
+}
+
+type book struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Year   int    `json:"year"`
+	ISBN   string `json:"isbn"`
+}
+
+func (p *Partner) remote(b book) domain.RemoteBook {
+	return domain.RemoteBook{Source: p.cfg.Name, ID: b.ID, Title: b.Title, Author: b.Author, Year: b.Year, ISBN: b.ISBN}
+}
+
+// Search runs a query against the partner's faceted browse, for the
+// given audience, and returns the books found in its ranking order. The
+// partner is asked not to search its own partners, so searches do not
+// go round the consortium.
+func (p *Partner) Search(ctx context.Context, q, audience string) ([]domain.RemoteBook, error) {
+	query := url.Values{"q": {q}, "partners": {"false"}}
+	if audience != "" {
+		query.Set("audience", audience)
+	}
+	var result struct {
+		Data struct {
+			Books []book `json:"books"`
+		} `json:"data"`
+	}
+	if err := p.get(ctx, "/books/facets?"+query.Encode(), &result); err != nil {
+		return nil, err
+	}
+	books := make([]domain.RemoteBook, len(result.Data.Books))
+	for i, b := range result.Data.Books {
+		books[i] = p.remote(b)
+	}
+	return books, nil
+}
+
+// Book returns one of the partner's books.
+func (p *Partner) Book(ctx context.Context, id int) (domain.RemoteBook, error) {
+	var result struct {
+		Data book `json:"data"`
+	}
+	if err := p.get(ctx, "/books/"+strconv.Itoa(id), &result); err != nil {
+		return domain.RemoteBook{}, err
+	}
+	return p.remote(result.Data), nil
+}
+
+func (p *Partner) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return domain.NotFound("book not found at " + p.cfg.Name)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("federation: %s returned %s", p.cfg.Name, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
+
+// Request places a hold on one of the partner's books for this
+// library's account there with an NCIP RequestItem, and returns the
+// partner's request ID. A problem the partner reports is a conflict.
+func (p *Partner) Request(ctx context.Context, bookID int) (string, error) {
+	msg := ncip.Message{Version: ncip.Version, RequestItem: &ncip.RequestItem{
+		InitiationHeader: ncip.InitiationHeader{
+			FromAgencyID:             ncip.AgencyRef{AgencyID: p.cfg.AgencyID},
+			ToAgencyID:               ncip.AgencyRef{AgencyID: p.cfg.PartnerAgencyID},
+			FromAgencyAuthentication: p.cfg.Secret,
+		},
+		UserID: &ncip.UserID{AgencyID: p.cfg.PartnerAgencyID, UserIdentifierValue: p.cfg.Account},
+		BibliographicID: &ncip.BibliographicID{BibliographicRecordID: &ncip.BibliographicRecordID{
+			BibliographicRecordIdentifier: strconv.Itoa(bookID),
+			AgencyID:                      p.cfg.PartnerAgencyID,
+		}},
+		RequestType:      ncip.RequestTypeHold,
+		RequestScopeType: ncip.RequestScopeBibliographic,
+	}}
+	body, err := xml.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+"/ncip", bytes.NewReader(append([]byte(xml.Header), body...)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("federation: %s returned %s", p.cfg.Name, resp.Status)
+	}
+	var answer ncip.Message
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&answer); err != nil {
+		return "", err
+	}
+
+	problems := answer.Problem
+	if r := answer.RequestItemResponse; r != nil {
+		problems = append(problems, r.Problem...)
+		if len(problems) == 0 && r.RequestID != nil {
+			return r.RequestID.RequestIdentifierValue, nil
+		}
+	}
+	if len(problems) == 0 {
+		return "", fmt.Errorf("federation: %s sent no RequestItemResponse", p.cfg.Name)
+	}
+	detail := problems[0].ProblemType
+	if problems[0].ProblemDetail != "" {
+		detail += ": " + problems[0].ProblemDetail
+	}
+	return "", domain.Conflict(p.cfg.Name + " refused the request: " + detail)
+}
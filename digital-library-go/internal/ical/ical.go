@@ -0,0 +1,102 @@
+// Package ical writes iCalendar (RFC 5545) files, so members can add
+// library events and due dates to their calendar apps.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// Event is one VEVENT. Events with AllDay set cover the date of Start.
+// Updated defaults to the time the calendar is rendered.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Updated     time.Time
+}
+
+// Calendar is a named list of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Bytes renders the calendar with CRLF line endings and folded lines, as
+// the format requires.
+func (c Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	line := func(s string) {
+		buf.WriteString(fold(s))
+		buf.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Digital Library//EN")
+	line("CALSCALE:GREGORIAN")
+	if c.Name != "" {
+		line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	now := time.Now()
+	for _, e := range c.Events {
+		if e.Updated.IsZero() {
+			e.Updated = now
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + utc(e.Updated))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + e.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			line("DTSTART:" + utc(e.Start))
+			line("DTEND:" + utc(e.End))
+		}
+		line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION:" + escape(e.Location))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// fold breaks lines longer than 75 octets, continuing them with a space,
+// without splitting a UTF-8 character.
+func fold(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
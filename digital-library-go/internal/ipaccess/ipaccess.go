@@ -0,0 +1,177 @@
+// Package ipaccess holds CIDR allow and deny lists per route group,
+// loaded from a JSON file and reloaded when the file changes.
+package ipaccess
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Rule is the access list of one route group. Deny entries win over
+// allow entries; an empty allow list admits every address not denied.
+type Rule struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+type group struct {
+	prefix string
+	allow  []netip.Prefix
+	deny   []netip.Prefix
+}
+
+// Rules maps path prefixes such as "/admin" to their access lists.
+type Rules struct {
+	groups []group
+}
+
+// Parse reads rules from JSON of the form
+// {"/admin": {"allow": ["10.0.0.0/8"], "deny": ["10.0.0.13"]}}.
+func Parse(data []byte) (*Rules, error) {
+	var raw map[string]Rule
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	rules := &Rules{}
+	for prefix, r := range raw {
+		g := group{prefix: strings.TrimSuffix(prefix, "/")}
+		var err error
+		if g.allow, err = parsePrefixes(r.Allow); err != nil {
+			return nil, fmt.Errorf("%s: %w", prefix, err)
+		}
+		if g.deny, err = parsePrefixes(r.Deny); err != nil {
+			return nil, fmt.Errorf("%s: %w", prefix, err)
+		}
+		rules.groups = append(rules.groups, g)
+	}
+	// Longest prefix first so the most specific group wins.
+	sort.Slice(rules.groups, func(i, j int) bool {
+		return len(rules.groups[i].prefix) > len(rules.groups[j].prefix)
+	})
+	return rules, nil
+}
+
+// Allowed reports whether a client at addr may request path.
+func (r *Rules) Allowed(path, addr string) bool {
+	g, ok := r.match(path)
+	if !ok {
+		return true
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	if contains(g.deny, ip) {
+		return false
+	}
+	return len(g.allow) == 0 || contains(g.allow, ip)
+}
+
+func (r *Rules) match(path string) (group, bool) {
+	for _, g := range r.groups {
+		if path == g.prefix || strings.HasPrefix(path, g.prefix+"/") {
+			return g, true
+		}
+	}
+	return group{}, false
+}
+
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefixes accepts CIDR blocks and bare addresses.
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		if !strings.Contains(e, "/") {
+			ip, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
+
+// Store holds the current rules and swaps them atomically on reload.
+type Store struct {
+	rules atomic.Pointer[Rules]
+	path  string
+}
+
+// Load reads the rules file at path. An empty path yields a store that
+// allows everything.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path}
+	s.rules.Store(&Rules{})
+	if path == "" {
+		return s, nil
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) Rules() *Rules {
+	return s.rules.Load()
+}
+
+// Watch polls the rules file and reloads it whenever it changes. A file
+// that fails to parse is logged and the previous rules stay in force.
+func (s *Store) Watch(interval time.Duration) {
+	if s.path == "" {
+		return
+	}
+	var last time.Time
+	if fi, err := os.Stat(s.path); err == nil {
+		last = fi.ModTime()
+	}
+	for range time.Tick(interval) {
+		fi, err := os.Stat(s.path)
+		if err != nil || !fi.ModTime().After(last) {
+			continue
+		}
+		last = fi.ModTime()
+		if err := s.reload(); err != nil {
+			log.Println("IP access rules not reloaded:", err)
+			continue
+		}
+		log.Println("IP access rules reloaded from", s.path)
+	}
+}
+
+func (s *Store) reload() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	rules, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	s.rules.Store(rules)
+	return nil
+}
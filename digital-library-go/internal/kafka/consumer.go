@@ -0,0 +1,135 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// pollTimeout is how long, in milliseconds, the proxy waits for records
+// before answering a poll with none. It stays below the breaker timeout.
+const pollTimeout = 1000
+
+// Message is a record read from a topic. Key and Value are the raw bytes
+// the producer sent.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// Consumer reads topics through a consumer instance on the proxy. Offsets
+// are committed only by Commit, so messages read but not committed are
+// read again by the next consumer in the group.
+type Consumer struct {
+	client  *Client
+	baseURI string
+}
+
+// Subscribe creates the consumer instance name in group and subscribes it
+// to topic. A group with no committed offsets starts at the earliest
+// message.
+func (c *Client) Subscribe(ctx context.Context, group, name, topic string) (*Consumer, error) {
+	var created struct {
+		BaseURI string `json:"base_uri"`
+	}
+	status, err := c.call(ctx, http.MethodPost, c.baseURL+"/consumers/"+url.PathEscape(group), map[string]string{
+		"name":               name,
+		"format":             "binary",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}, &created, http.StatusOK, http.StatusConflict)
+	if err != nil {
+		return nil, err
+	}
+	consumer := &Consumer{client: c, baseURI: created.BaseURI}
+	if status == http.StatusConflict {
+		// The instance outlived an earlier run; pick it up again.
+		consumer.baseURI = c.baseURL + "/consumers/" + url.PathEscape(group) + "/instances/" + url.PathEscape(name)
+	}
+	if _, err := c.call(ctx, http.MethodPost, consumer.baseURI+"/subscription", map[string][]string{"topics": {topic}}, nil, http.StatusNoContent); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// Poll returns the next messages, or none if nothing arrived for a
+// second.
+func (c *Consumer) Poll(ctx context.Context) ([]Message, error) {
+	var records []struct {
+		Topic     string `json:"topic"`
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		Key       []byte `json:"key"`
+		Value     []byte `json:"value"`
+	}
+	if _, err := c.client.call(ctx, http.MethodGet, fmt.Sprintf("%s/records?timeout=%d", c.baseURI, pollTimeout), nil, &records, http.StatusOK); err != nil {
+		return nil, err
+	}
+	messages := make([]Message, len(records))
+	for i, r := range records {
+		messages[i] = Message{Topic: r.Topic, Partition: r.Partition, Offset: r.Offset, Key: r.Key, Value: r.Value}
+	}
+	return messages, nil
+}
+
+// Commit marks m and everything before it on its partition as consumed.
+func (c *Consumer) Commit(ctx context.Context, m Message) error {
+	body := map[string]any{"offsets": []map[string]any{{"topic": m.Topic, "partition": m.Partition, "offset": m.Offset}}}
+	_, err := c.client.call(ctx, http.MethodPost, c.baseURI+"/offsets", body, nil, http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+// Close removes the consumer instance so the group rebalances at once
+// instead of waiting for the proxy to time it out.
+func (c *Consumer) Close(ctx context.Context) error {
+	_, err := c.client.call(ctx, http.MethodDelete, c.baseURI, nil, nil, http.StatusNoContent)
+	return err
+}
+
+// call sends body as JSON and decodes the reply into result. It fails
+// unless the status is one of ok.
+func (c *Client) call(ctx context.Context, method, target string, body, result any, ok ...int) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.kafka.v2+json")
+	}
+	req.Header.Set("Accept", "application/vnd.kafka.binary.v2+json, application/vnd.kafka.v2+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	for _, status := range ok {
+		if resp.StatusCode != status {
+			continue
+		}
+		if result != nil && status == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+				return status, fmt.Errorf("kafka: %w", err)
+			}
+		}
+		return status, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode, fmt.Errorf("kafka: %s %s returned %s: %s", method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+}
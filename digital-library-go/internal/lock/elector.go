@@ -0,0 +1,75 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// Elector makes one of the instances sharing a Locker the leader: each
+// keeps trying to take the same lock, and whoever holds it leads until
+// it is lost, e.g. because the instance stopped renewing it.
+type Elector struct {
+	locker   Locker
+	name     string
+	instance string
+	ttl      time.Duration
+
+	mu     sync.RWMutex
+	status domain.Leadership
+}
+
+// NewElector returns an elector for the named lock. instance identifies
+// this instance in logs and status, e.g. its host name.
+func NewElector(locker Locker, name, instance string, ttl time.Duration) *Elector {
+	return &Elector{locker: locker, name: name, instance: instance, ttl: ttl, status: domain.Leadership{Instance: instance}}
+}
+
+// Run campaigns for leadership forever, retrying every third of the TTL
+// while another instance leads.
+func (e *Elector) Run() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), e.ttl/3)
+		l, err := e.locker.TryLock(ctx, e.name, e.ttl)
+		cancel()
+		if err == nil {
+			e.set(true)
+			log.Printf("Leader election: %s is now the leader", e.instance)
+			<-l.Lost()
+			e.set(false)
+			log.Printf("Leader election: %s lost leadership", e.instance)
+		} else if !errors.Is(err, ErrHeld) {
+			log.Printf("Leader election: %v", err)
+		}
+		time.Sleep(e.ttl / 3)
+	}
+}
+
+// Leader reports whether this instance currently leads.
+func (e *Elector) Leader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status.Leader
+}
+
+func (e *Elector) Status() domain.Leadership {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
+
+func (e *Elector) set(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status.Leader = leader
+	e.status.Since = time.Now()
+	if leader {
+		e.status.Elected++
+	} else {
+		e.status.Lost++
+	}
+}
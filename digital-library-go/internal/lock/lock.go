@@ -0,0 +1,97 @@
+// Package lock provides named locks that expire unless renewed, so a job
+// runs on one instance at a time and a crashed holder does not keep its
+// lock forever. Memory serves a single instance; Redis shares locks
+// between instances.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var ErrHeld = errors.New("lock is held by someone else")
+
+// Locker takes locks. TryLock returns ErrHeld if the lock is taken. A
+// lock it returns is renewed every third of ttl until Unlock, so ttl
+// only bounds how long a crashed holder blocks others.
+type Locker interface {
+	TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error)
+}
+
+// store is what a Locker keeps locks in. The token tells holders apart,
+// so only the holder can renew or release a lock.
+type store interface {
+	acquire(ctx context.Context, name, token string, ttl time.Duration) (bool, error)
+	renew(ctx context.Context, name, token string, ttl time.Duration) (bool, error)
+	release(ctx context.Context, name, token string) error
+}
+
+// Lock is a held lock.
+type Lock struct {
+	name  string
+	token string
+	store store
+	stop  chan struct{}
+	lost  chan struct{}
+	once  sync.Once
+}
+
+func tryLock(ctx context.Context, s store, name string, ttl time.Duration) (*Lock, error) {
+	token := make([]byte, 16)
+	rand.Read(token)
+	l := &Lock{name: name, token: hex.EncodeToString(token), store: s, stop: make(chan struct{}), lost: make(chan struct{})}
+	ok, err := s.acquire(ctx, name, l.token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrHeld
+	}
+	go l.keep(ttl)
+	return l, nil
+}
+
+// keep renews the lock until it is released or a renewal fails.
+func (l *Lock) keep(ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), ttl/3)
+			ok, err := l.store.renew(ctx, l.name, l.token, ttl)
+			cancel()
+			if err != nil || !ok {
+				log.Printf("Lock %s lost: renewal failed (%v)", l.name, err)
+				close(l.lost)
+				return
+			}
+		}
+	}
+}
+
+// Lost is closed if the lock could not be renewed, after which another
+// holder may take it. Long jobs should stop when it is.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Unlock stops renewing the lock and releases it. It is safe to call
+// more than once.
+func (l *Lock) Unlock() {
+	l.once.Do(func() {
+		close(l.stop)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := l.store.release(ctx, l.name, l.token); err != nil {
+			log.Printf("Lock %s: release failed, it expires on its own: %v", l.name, err)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps locks in this process.
+type Memory struct {
+	mu    sync.Mutex
+	locks map[string]held
+	now   func() time.Time
+}
+
+type held struct {
+	token   string
+	expires time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{locks: map[string]held{}, now: time.Now}
+}
+
+func (m *Memory) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	return tryLock(ctx, m, name, ttl)
+}
+
+func (m *Memory) acquire(_ context.Context, name, token string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if h, ok := m.locks[name]; ok && now.Before(h.expires) {
+		return false, nil
+	}
+	m.locks[name] = held{token: token, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (m *Memory) renew(_ context.Context, name, token string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	h, ok := m.locks[name]
+	if !ok || h.token != token || !now.Before(h.expires) {
+		return false, nil
+	}
+	m.locks[name] = held{token: token, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (m *Memory) release(_ context.Context, name, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok := m.locks[name]; ok && h.token == token {
+		delete(m.locks, name)
+	}
+	return nil
+}
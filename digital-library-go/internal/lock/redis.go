@@ -0,0 +1,58 @@
+package lock
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/redis"
+)
+
+// keyPrefix namespaces the lock keys in a shared Redis.
+const keyPrefix = "digital-library:lock:"
+
+// Renewing and releasing check the token and act in one script, so a
+// holder whose lock expired cannot touch the next holder's lock.
+const (
+	renewScript   = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+	releaseScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+)
+
+// Redis keeps locks in Redis, shared by every instance using the same
+// server.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (r *Redis) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	return tryLock(ctx, r, name, ttl)
+}
+
+// Ping checks that the Redis server answers.
+func (r *Redis) Ping(ctx context.Context) error {
+	_, err := r.client.Do(ctx, "PING")
+	return err
+}
+
+func (r *Redis) acquire(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	reply, err := r.client.Do(ctx, "SET", keyPrefix+name, token, "NX", "PX", milliseconds(ttl))
+	return reply == "OK", err
+}
+
+func (r *Redis) renew(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	reply, err := r.client.Do(ctx, "EVAL", renewScript, "1", keyPrefix+name, token, milliseconds(ttl))
+	return reply == int64(1), err
+}
+
+func (r *Redis) release(ctx context.Context, name, token string) error {
+	_, err := r.client.Do(ctx, "EVAL", releaseScript, "1", keyPrefix+name, token)
+	return err
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
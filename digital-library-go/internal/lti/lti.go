@@ -0,0 +1,149 @@
+// Package lti is the tool side of an LTI 1.3 resource link launch: the
+// OpenID Connect third-party login a learning management system such as
+// Moodle or Canvas starts, and verification of the signed launch it
+// posts back.
+package lti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/oidc"
+)
+
+// Config describes one platform as registered with the tool. AuthURL and
+// JWKSURL come from the platform's tool registration page.
+type Config struct {
+	Name     string
+	Issuer   string
+	ClientID string
+	AuthURL  string
+	JWKSURL  string
+	// DeploymentIDs, when set, are the only deployments accepted.
+	DeploymentIDs []string
+	// Client is used to fetch the platform's keys; nil means a plain
+	// client with a 10 second timeout.
+	Client *http.Client
+}
+
+// Platform is one learning management system the library's reserve
+// lists are installed in.
+type Platform struct {
+	cfg  Config
+	keys *oidc.KeySet
+}
+
+func NewPlatform(cfg Config) *Platform {
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Platform{cfg: cfg, keys: oidc.NewKeySet(cfg.JWKSURL, client)}
+}
+
+func (p *Platform) Name() string {
+	return p.cfg.Name
+}
+
+func (p *Platform) Issuer() string {
+	return p.cfg.Issuer
+}
+
+func (p *Platform) ClientID() string {
+	return p.cfg.ClientID
+}
+
+// AuthRequestURL returns the platform URL the browser is sent to in
+// answer to a login initiation. The platform posts the launch to
+// redirectURI.
+func (p *Platform) AuthRequestURL(redirectURI, loginHint, messageHint, state, nonce string) string {
+	q := url.Values{
+		"scope":         {"openid"},
+		"response_type": {"id_token"},
+		"response_mode": {"form_post"},
+		"prompt":        {"none"},
+		"client_id":     {p.cfg.ClientID},
+		"redirect_uri":  {redirectURI},
+		"login_hint":    {loginHint},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	if messageHint != "" {
+		q.Set("lti_message_hint", messageHint)
+	}
+	sep := "?"
+	if strings.Contains(p.cfg.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.cfg.AuthURL + sep + q.Encode()
+}
+
+type launchClaims struct {
+	Issuer          string        `json:"iss"`
+	Subject         string        `json:"sub"`
+	Audience        oidc.Audience `json:"aud"`
+	AuthorizedParty string        `json:"azp"`
+	Expiry          int64         `json:"exp"`
+	Nonce           string        `json:"nonce"`
+	Name            string        `json:"name"`
+
+	MessageType  string   `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version      string   `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID string   `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	Roles        []string `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	Context      struct {
+		ID    string `json:"id"`
+		Label string `json:"label"`
+		Title string `json:"title"`
+	} `json:"https://purl.imsglobal.org/spec/lti/claim/context"`
+}
+
+// Verify checks a launch's id_token: its signature against the
+// platform's keys, that it was issued by the platform for this tool and
+// has not expired, and that it is an LTI 1.3 resource link launch from
+// an accepted deployment. Checking the nonce is up to the caller.
+func (p *Platform) Verify(ctx context.Context, idToken string) (domain.LTILaunch, error) {
+	var c launchClaims
+	if err := p.keys.Verify(ctx, idToken, &c); err != nil {
+		return domain.LTILaunch{}, err
+	}
+	if c.Issuer != p.cfg.Issuer {
+		return domain.LTILaunch{}, errors.New("lti: id_token issuer mismatch")
+	}
+	if !slices.Contains(c.Audience, p.cfg.ClientID) || (len(c.Audience) > 1 && c.AuthorizedParty != p.cfg.ClientID) {
+		return domain.LTILaunch{}, errors.New("lti: id_token not issued for this tool")
+	}
+	if time.Now().Unix() >= c.Expiry {
+		return domain.LTILaunch{}, errors.New("lti: id_token expired")
+	}
+	if c.Version != "1.3.0" {
+		return domain.LTILaunch{}, fmt.Errorf("lti: unsupported version %q", c.Version)
+	}
+	if c.MessageType != "LtiResourceLinkRequest" {
+		return domain.LTILaunch{}, fmt.Errorf("lti: unsupported message type %q", c.MessageType)
+	}
+	if len(p.cfg.DeploymentIDs) > 0 && !slices.Contains(p.cfg.DeploymentIDs, c.DeploymentID) {
+		return domain.LTILaunch{}, fmt.Errorf("lti: unknown deployment %q", c.DeploymentID)
+	}
+	if c.Context.ID == "" {
+		return domain.LTILaunch{}, errors.New("lti: launch is not from a course")
+	}
+
+	return domain.LTILaunch{
+		Issuer:       c.Issuer,
+		Subject:      c.Subject,
+		Name:         c.Name,
+		Roles:        c.Roles,
+		ContextID:    c.Context.ID,
+		ContextLabel: c.Context.Label,
+		ContextTitle: c.Context.Title,
+		Nonce:        c.Nonce,
+	}, nil
+}
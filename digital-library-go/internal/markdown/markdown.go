@@ -0,0 +1,400 @@
+// Package markdown renders the Markdown used in book descriptions and
+// tables of contents to HTML that is safe to embed in a page.
+//
+// It supports a subset of CommonMark: paragraphs, ATX headings, block
+// quotes, nested bullet and numbered lists, fenced code, horizontal rules,
+// emphasis, code spans and links. Output is safe by construction rather
+// than by filtering: all text is escaped, raw HTML is shown as text, and
+// links only keep http, https, mailto and relative URLs.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ToHTML renders src as HTML.
+func ToHTML(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	var b strings.Builder
+	blocks(&b, strings.Split(src, "\n"), false)
+	return b.String()
+}
+
+// blocks renders lines as a sequence of blocks. In a tight list item,
+// paragraphs are written without <p> tags.
+func blocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			i++
+		case strings.HasPrefix(trimmed, "```"):
+			i = fence(b, lines, i)
+		case headingLevel(trimmed) > 0:
+			level := headingLevel(trimmed)
+			tag := "h" + strconv.Itoa(level)
+			text := strings.TrimSpace(trimmed[level:])
+			if t := strings.TrimRight(text, "#"); t != text && (t == "" || strings.HasSuffix(t, " ")) {
+				text = strings.TrimSpace(t)
+			}
+			b.WriteString("<" + tag + ">")
+			inline(b, text)
+			b.WriteString("</" + tag + ">\n")
+			i++
+		case isRule(trimmed):
+			b.WriteString("<hr>\n")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			i = quote(b, lines, i)
+		case isItem(lines[i]):
+			i = list(b, lines, i)
+		default:
+			i = paragraph(b, lines, i, tight)
+		}
+	}
+}
+
+// fence renders a fenced code block starting at lines[i] and returns the
+// index of the line after it. An unclosed fence runs to the end.
+func fence(b *strings.Builder, lines []string, i int) int {
+	b.WriteString("<pre><code>")
+	i++
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			i++
+			break
+		}
+		b.WriteString(html.EscapeString(lines[i]) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func quote(b *strings.Builder, lines []string, i int) int {
+	inner := []string{}
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = trimmed[1:]
+		inner = append(inner, strings.TrimPrefix(trimmed, " "))
+	}
+	b.WriteString("<blockquote>\n")
+	blocks(b, inner, false)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+// list renders the list starting at lines[i]. An item runs on over any
+// lines indented past its marker, which hold its nested blocks; a blank
+// line inside an item makes it loose, so its paragraphs get <p> tags.
+func list(b *strings.Builder, lines []string, i int) int {
+	first := parseItem(lines[i])
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	if first.ordered && first.start != 1 {
+		b.WriteString(`<ol start="` + strconv.Itoa(first.start) + `">` + "\n")
+	} else {
+		b.WriteString("<" + tag + ">\n")
+	}
+
+	for i < len(lines) {
+		it := parseItem(lines[i])
+		if !isItem(lines[i]) || it.indent != first.indent || it.ordered != first.ordered {
+			break
+		}
+		content := []string{lines[i][it.width:]}
+		loose := false
+		i++
+		for i < len(lines) {
+			if strings.TrimSpace(lines[i]) == "" {
+				next := i + 1
+				for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+					next++
+				}
+				if next == len(lines) || indentOf(lines[next]) <= first.indent {
+					break
+				}
+				loose = true
+				content = append(content, "")
+				i++
+				continue
+			}
+			indent := indentOf(lines[i])
+			if indent <= first.indent {
+				break
+			}
+			content = append(content, lines[i][min(indent, it.width):])
+			i++
+		}
+
+		var inner strings.Builder
+		blocks(&inner, content, !loose)
+		b.WriteString("<li>" + strings.TrimSuffix(inner.String(), "\n") + "</li>\n")
+
+		// A blank line between items ends the list only if no item follows.
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			next := i + 1
+			if next < len(lines) && parseItem(lines[next]).ok && indentOf(lines[next]) == first.indent {
+				i = next
+				break
+			}
+			return closeList(b, tag, next)
+		}
+	}
+	return closeList(b, tag, i)
+}
+
+func closeList(b *strings.Builder, tag string, i int) int {
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// paragraph renders the lines up to the next blank line or block. A line
+// ending in two spaces becomes a line break.
+func paragraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	if !tight {
+		b.WriteString("<p>")
+	}
+	for start := i; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if i > start && startsBlock(lines[i], trimmed) {
+			break
+		}
+		if i > start {
+			b.WriteString("\n")
+		}
+		inline(b, trimmed)
+		if strings.HasSuffix(lines[i], "  ") && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			b.WriteString("<br>")
+		}
+	}
+	if !tight {
+		b.WriteString("</p>")
+	}
+	b.WriteString("\n")
+	return i
+}
+
+func startsBlock(line, trimmed string) bool {
+	return trimmed == "" || strings.HasPrefix(trimmed, "```") || headingLevel(trimmed) > 0 ||
+		isRule(trimmed) || strings.HasPrefix(trimmed, ">") || isItem(line)
+}
+
+// headingLevel returns the level of an ATX heading such as "## Part 2",
+// or 0 if s is not one.
+func headingLevel(s string) int {
+	n := 0
+	for n < len(s) && s[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || (n < len(s) && s[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+// isRule reports whether s is a horizontal rule: three or more of the
+// same '-', '*' or '_', optionally separated by spaces.
+func isRule(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 3 || !strings.ContainsRune("-*_", rune(s[0])) {
+		return false
+	}
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+type item struct {
+	ok      bool
+	ordered bool
+	start   int
+	indent  int
+	// width is where the item's text starts, past the marker.
+	width int
+}
+
+// parseItem reads a list marker: "-", "*" or "+", or a number followed by
+// "." or ")", then a space.
+func parseItem(line string) item {
+	indent := indentOf(line)
+	rest := line[indent:]
+	if len(rest) >= 2 && strings.ContainsRune("-*+", rune(rest[0])) && rest[1] == ' ' {
+		return item{ok: true, indent: indent, width: indent + 2}
+	}
+	digits := 0
+	for digits < len(rest) && digits < 9 && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || len(rest) < digits+2 || (rest[digits] != '.' && rest[digits] != ')') || rest[digits+1] != ' ' {
+		return item{}
+	}
+	start, _ := strconv.Atoi(rest[:digits])
+	return item{ok: true, ordered: true, start: start, indent: indent, width: indent + digits + 2}
+}
+
+func isItem(line string) bool {
+	return parseItem(line).ok && !isRule(strings.TrimSpace(line))
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// inline renders the text of a paragraph, heading or list item.
+func inline(b *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(punctuation, s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			n := run(s[i:], '`')
+			end := strings.Index(s[i+n:], s[i:i+n])
+			if end < 0 {
+				b.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			b.WriteString("<code>" + html.EscapeString(strings.TrimSpace(s[i+n:i+n+end])) + "</code>")
+			i += n + end + n
+		case c == '*' || c == '_':
+			n := min(run(s[i:], c), 2)
+			delim := s[i : i+n]
+			opens := i+n < len(s) && s[i+n] != ' ' && (c == '*' || i == 0 || !isWordByte(s[i-1]))
+			if end := closing(s[i+n:], delim); opens && end > 0 {
+				tag := "em"
+				if n == 2 {
+					tag = "strong"
+				}
+				b.WriteString("<" + tag + ">")
+				inline(b, s[i+n:i+n+end])
+				b.WriteString("</" + tag + ">")
+				i += n + end + n
+				continue
+			}
+			b.WriteString(delim)
+			i += n
+		case c == '[':
+			text, dest, n, ok := link(s[i:])
+			if !ok {
+				b.WriteByte('[')
+				i++
+				continue
+			}
+			if safeURL(dest) {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `" rel="nofollow noopener">`)
+				inline(b, text)
+				b.WriteString("</a>")
+			} else {
+				inline(b, text)
+			}
+			i += n
+		default:
+			next := strings.IndexAny(s[i+1:], "\\`*_[")
+			if next < 0 {
+				next = len(s) - i - 1
+			}
+			b.WriteString(html.EscapeString(s[i : i+1+next]))
+			i += 1 + next
+		}
+	}
+}
+
+const punctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+func run(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// closing finds the delimiter that closes an emphasis span over s. It
+// must follow a non-space, and a single delimiter skips doubled ones so
+// that "*a **b** c*" nests.
+func closing(s, delim string) int {
+	for j := 1; j < len(s); {
+		if s[j] == '\\' {
+			j += 2
+			continue
+		}
+		n := run(s[j:], delim[0])
+		if n == 0 {
+			j++
+			continue
+		}
+		closes := n >= len(delim) && s[j-1] != ' '
+		if delim[0] == '_' && j+n < len(s) && isWordByte(s[j+n]) {
+			closes = false
+		}
+		if closes && len(delim) == 2 {
+			// Close at the end of the run, so "***a***" is <strong><em>.
+			return j + n - 2
+		}
+		if closes && n == 1 {
+			return j
+		}
+		j += n
+	}
+	return -1
+}
+
+// link parses "[text](dest)" at the start of s and returns its length.
+// A title after the destination is ignored.
+func link(s string) (text, dest string, n int, ok bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if i+1 >= len(s) || s[i+1] != '(' {
+				return "", "", 0, false
+			}
+			end := strings.IndexByte(s[i+2:], ')')
+			if end < 0 {
+				return "", "", 0, false
+			}
+			dest, _, _ = strings.Cut(strings.TrimSpace(s[i+2:i+2+end]), " ")
+			return s[1:i], dest, i + 2 + end + 1, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// safeURL allows http, https and mailto links and relative ones. Anything
+// else, such as javascript: or data: URLs, is dropped. url.Parse rejects
+// control characters, so "java\tscript:" cannot slip through.
+func safeURL(dest string) bool {
+	if dest == "" {
+		return false
+	}
+	u, err := url.Parse(dest)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
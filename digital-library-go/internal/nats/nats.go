@@ -0,0 +1,228 @@
+// Package nats publishes and subscribes to messages on a NATS server using
+// the client protocol
+// (https://docs.nats.io/reference/reference-protocols/nats-protocol),
+// over plain TCP connections. Each published message is confirmed with a
+// PING so server errors are not missed.
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrProtocol = errors.New("nats: unexpected reply from server")
+
+// idleTimeout bounds how long a subscription waits for the server, which
+// pings idle clients every two minutes by default.
+const idleTimeout = 5 * time.Minute
+
+// Error is an -ERR reply from the server, e.g. an authorization
+// violation.
+type Error string
+
+func (e Error) Error() string { return "nats: " + string(e) }
+
+type Client struct {
+	addr    string
+	connect []byte
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewClient parses a URL of the form nats://[user:password@]host[:port]
+// or nats://token@host[:port].
+func NewClient(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("nats: %q must look like nats://[user:password@]host[:port]", rawURL)
+	}
+	c := &Client{addr: u.Host}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	options := map[string]any{"verbose": false, "pedantic": false, "name": "digital-library", "lang": "go", "version": "1"}
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			options["user"], options["pass"] = u.User.Username(), password
+		} else {
+			options["auth_token"] = u.User.Username()
+		}
+	}
+	data, _ := json.Marshal(options)
+	c.connect = fmt.Appendf(nil, "CONNECT %s\r\n", data)
+	return c, nil
+}
+
+// Publish sends data on subject and waits until the server has accepted
+// it. The connection is dialed again on the next call after a failure.
+func (c *Client) Publish(ctx context.Context, subject string, data []byte) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("nats: invalid subject %q", subject)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		conn, r, err := c.dial(ctx)
+		if err != nil {
+			return err
+		}
+		c.conn, c.r = conn, r
+	}
+	msg := fmt.Appendf(nil, "PUB %s %d\r\n", subject, len(data))
+	msg = append(msg, data...)
+	msg = append(msg, "\r\n"...)
+	if err := flush(ctx, c.conn, c.r, msg); err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Subscribe receives the messages on subject, which may contain
+// wildcards, over a connection of its own and passes each to handle in
+// order. It returns when ctx ends or the connection fails. The server
+// does not keep messages, so those sent while no one is subscribed are
+// lost.
+func (c *Client) Subscribe(ctx context.Context, subject string, handle func(data []byte)) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("nats: invalid subject %q", subject)
+	}
+	conn, r, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	if err := flush(ctx, conn, r, fmt.Appendf(nil, "SUB %s 1\r\n", subject)); err != nil {
+		return err
+	}
+
+	for {
+		conn.SetDeadline(time.Now().Add(idleTimeout))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			args := strings.Fields(line)
+			if len(args) < 4 {
+				return ErrProtocol
+			}
+			n, err := strconv.Atoi(args[len(args)-1])
+			if err != nil || n < 0 {
+				return ErrProtocol
+			}
+			data := make([]byte, n+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return err
+			}
+			handle(data[:n])
+		case line == "PING":
+			if _, err := conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return replyError(line)
+		case line == "PONG", line == "+OK", strings.HasPrefix(line, "INFO "):
+		default:
+			return ErrProtocol
+		}
+	}
+}
+
+// dial connects and introduces the client.
+func (c *Client) dial(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	setDeadline(ctx, conn)
+	// The server speaks first, with INFO.
+	line, err := r.ReadString('\n')
+	if err == nil && !strings.HasPrefix(line, "INFO ") {
+		err = ErrProtocol
+	}
+	if err == nil {
+		err = flush(ctx, conn, r, c.connect)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, r, nil
+}
+
+// flush writes msg followed by a PING and reads until the PONG, which
+// the server sends only after handling everything before it.
+func flush(ctx context.Context, conn net.Conn, r *bufio.Reader, msg []byte) error {
+	setDeadline(ctx, conn)
+	if _, err := conn.Write(append(msg, "PING\r\n"...)); err != nil {
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return replyError(line)
+		case line == "+OK", strings.HasPrefix(line, "INFO "):
+			// Cluster updates and acknowledgements need no answer.
+		default:
+			return ErrProtocol
+		}
+	}
+}
+
+func replyError(line string) Error {
+	return Error(strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'"))
+}
+
+func setDeadline(ctx context.Context, conn net.Conn) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+}
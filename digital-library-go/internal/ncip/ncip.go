@@ -0,0 +1,214 @@
+// Package ncip holds the messages of NCIP 2.02, the NISO Circulation
+// Interchange Protocol (ANSI/NISO Z39.83-1), that consortium partners use
+// to look up users, place requests and check items out at each other's
+// libraries. Only the services this server offers are modelled, and of
+// those only the elements it reads or writes.
+package ncip
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Namespace and Version identify NCIP 2.02 messages.
+const (
+	Namespace = "http://www.niso.org/2008/ncip"
+	Version   = "http://www.niso.org/schemas/ncip/v2_02/ncip_v2_02.xsd"
+)
+
+// Problem types from the NCIP problem type scheme.
+const (
+	AgencyAuthenticationFailed  = "Agency Authentication Failed"
+	UnknownAgency               = "Unknown Agency"
+	InvalidMessageSyntaxError   = "Invalid Message Syntax Error"
+	UnsupportedService          = "Unsupported Service"
+	NeededDataMissing           = "Needed Data Missing"
+	UnknownValueFromKnownScheme = "Unknown Value From Known Scheme"
+	UnknownUser                 = "Unknown User"
+	UserAuthenticationFailed    = "User Authentication Failed"
+	UnknownItem                 = "Unknown Item"
+	ItemDoesNotCirculate        = "Item Does Not Circulate"
+	DuplicateRequest            = "Duplicate Request"
+	UserIneligibleToRequest     = "User Ineligible To Request This Item"
+	MaximumCheckOutsExceeded    = "Maximum Check Outs Exceeded"
+	ResourceCannotBeProvided    = "Resource Cannot Be Provided"
+)
+
+// Values from the other schemes the server reads or writes.
+const (
+	// UserElementType
+	NameInformation        = "Name Information"
+	UserAddressInformation = "User Address Information"
+	UserPrivilege          = "User Privilege"
+
+	// AuthenticationInputType
+	BarcodeID = "Barcode Id"
+	UserIDKey = "User Id"
+	PIN       = "PIN"
+	Password  = "Password"
+
+	RequestTypeHold           = "Hold"
+	RequestScopeBibliographic = "Bibliographic Item"
+	ItemIdentifierCodeISBN    = "ISBN"
+	ElectronicAddressMailto   = "mailto"
+	MembershipPlan            = "Membership Plan"
+)
+
+// Message is the NCIPMessage envelope. A request carries one service; a
+// response carries its answer, or a Problem when the message itself
+// could not be handled.
+type Message struct {
+	XMLName xml.Name `xml:"http://www.niso.org/2008/ncip NCIPMessage"`
+	Version string   `xml:"version,attr,omitempty"`
+
+	LookupUser   *LookupUser   `xml:"LookupUser,omitempty"`
+	RequestItem  *RequestItem  `xml:"RequestItem,omitempty"`
+	CheckOutItem *CheckOutItem `xml:"CheckOutItem,omitempty"`
+
+	LookupUserResponse   *LookupUserResponse   `xml:"LookupUserResponse,omitempty"`
+	RequestItemResponse  *RequestItemResponse  `xml:"RequestItemResponse,omitempty"`
+	CheckOutItemResponse *CheckOutItemResponse `xml:"CheckOutItemResponse,omitempty"`
+
+	Problem []Problem `xml:"Problem,omitempty"`
+}
+
+type AgencyRef struct {
+	AgencyID string `xml:"AgencyId"`
+}
+
+// InitiationHeader opens every request. FromAgencyAuthentication is the
+// secret the partner shares with this library.
+type InitiationHeader struct {
+	FromAgencyID             AgencyRef `xml:"FromAgencyId"`
+	ToAgencyID               AgencyRef `xml:"ToAgencyId"`
+	FromAgencyAuthentication string    `xml:"FromAgencyAuthentication,omitempty"`
+}
+
+// ResponseHeader opens every response, addressed back to the requester.
+type ResponseHeader struct {
+	FromAgencyID AgencyRef `xml:"FromAgencyId"`
+	ToAgencyID   AgencyRef `xml:"ToAgencyId"`
+}
+
+type Problem struct {
+	ProblemType    string `xml:"ProblemType"`
+	ProblemDetail  string `xml:"ProblemDetail,omitempty"`
+	ProblemElement string `xml:"ProblemElement,omitempty"`
+	ProblemValue   string `xml:"ProblemValue,omitempty"`
+}
+
+// UserID names a user by library card number.
+type UserID struct {
+	AgencyID            string `xml:"AgencyId,omitempty"`
+	UserIdentifierType  string `xml:"UserIdentifierType,omitempty"`
+	UserIdentifierValue string `xml:"UserIdentifierValue"`
+}
+
+// ItemID names an item by the barcode on its label.
+type ItemID struct {
+	AgencyID            string `xml:"AgencyId,omitempty"`
+	ItemIdentifierType  string `xml:"ItemIdentifierType,omitempty"`
+	ItemIdentifierValue string `xml:"ItemIdentifierValue"`
+}
+
+// BibliographicID names a title rather than an item: by a standard
+// number such as an ISBN, or by this library's record ID.
+type BibliographicID struct {
+	BibliographicItemID   *BibliographicItemID   `xml:"BibliographicItemId,omitempty"`
+	BibliographicRecordID *BibliographicRecordID `xml:"BibliographicRecordId,omitempty"`
+}
+
+type BibliographicItemID struct {
+	BibliographicItemIdentifier     string `xml:"BibliographicItemIdentifier"`
+	BibliographicItemIdentifierCode string `xml:"BibliographicItemIdentifierCode,omitempty"`
+}
+
+type BibliographicRecordID struct {
+	BibliographicRecordIdentifier string `xml:"BibliographicRecordIdentifier"`
+	AgencyID                      string `xml:"AgencyId,omitempty"`
+}
+
+// AuthenticationInput carries a credential for the user, here the
+// password of the library account.
+type AuthenticationInput struct {
+	AuthenticationInputData string `xml:"AuthenticationInputData"`
+	AuthenticationInputType string `xml:"AuthenticationInputType"`
+}
+
+type LookupUser struct {
+	InitiationHeader    InitiationHeader      `xml:"InitiationHeader"`
+	UserID              *UserID               `xml:"UserId,omitempty"`
+	AuthenticationInput []AuthenticationInput `xml:"AuthenticationInput,omitempty"`
+	UserElementType     []string              `xml:"UserElementType,omitempty"`
+}
+
+type LookupUserResponse struct {
+	ResponseHeader     ResponseHeader      `xml:"ResponseHeader"`
+	Problem            []Problem           `xml:"Problem,omitempty"`
+	UserID             *UserID             `xml:"UserId,omitempty"`
+	UserOptionalFields *UserOptionalFields `xml:"UserOptionalFields,omitempty"`
+}
+
+type UserOptionalFields struct {
+	NameInformation        *NameInfo     `xml:"NameInformation,omitempty"`
+	UserAddressInformation []UserAddress `xml:"UserAddressInformation,omitempty"`
+	UserPrivilege          []Privilege   `xml:"UserPrivilege,omitempty"`
+}
+
+type NameInfo struct {
+	PersonalNameInformation struct {
+		UnstructuredPersonalUserName string `xml:"UnstructuredPersonalUserName"`
+	} `xml:"PersonalNameInformation"`
+}
+
+type UserAddress struct {
+	UserAddressRoleType string `xml:"UserAddressRoleType"`
+	ElectronicAddress   struct {
+		ElectronicAddressType string `xml:"ElectronicAddressType"`
+		ElectronicAddressData string `xml:"ElectronicAddressData"`
+	} `xml:"ElectronicAddress"`
+}
+
+type Privilege struct {
+	AgencyID                 string `xml:"AgencyId"`
+	AgencyUserPrivilegeType  string `xml:"AgencyUserPrivilegeType"`
+	UserPrivilegeDescription string `xml:"UserPrivilegeDescription,omitempty"`
+}
+
+type RequestItem struct {
+	InitiationHeader InitiationHeader `xml:"InitiationHeader"`
+	UserID           *UserID          `xml:"UserId,omitempty"`
+	ItemID           *ItemID          `xml:"ItemId,omitempty"`
+	BibliographicID  *BibliographicID `xml:"BibliographicId,omitempty"`
+	RequestType      string           `xml:"RequestType"`
+	RequestScopeType string           `xml:"RequestScopeType"`
+}
+
+type RequestItemResponse struct {
+	ResponseHeader   ResponseHeader `xml:"ResponseHeader"`
+	Problem          []Problem      `xml:"Problem,omitempty"`
+	RequestID        *RequestID     `xml:"RequestId,omitempty"`
+	ItemID           *ItemID        `xml:"ItemId,omitempty"`
+	UserID           *UserID        `xml:"UserId,omitempty"`
+	RequestType      string         `xml:"RequestType,omitempty"`
+	RequestScopeType string         `xml:"RequestScopeType,omitempty"`
+}
+
+type RequestID struct {
+	AgencyID               string `xml:"AgencyId,omitempty"`
+	RequestIdentifierValue string `xml:"RequestIdentifierValue"`
+}
+
+type CheckOutItem struct {
+	InitiationHeader InitiationHeader `xml:"InitiationHeader"`
+	UserID           *UserID          `xml:"UserId,omitempty"`
+	ItemID           *ItemID          `xml:"ItemId,omitempty"`
+}
+
+type CheckOutItemResponse struct {
+	ResponseHeader ResponseHeader `xml:"ResponseHeader"`
+	Problem        []Problem      `xml:"Problem,omitempty"`
+	ItemID         *ItemID        `xml:"ItemId,omitempty"`
+	UserID         *UserID        `xml:"UserId,omitempty"`
+	DateDue        *time.Time     `xml:"DateDue,omitempty"`
+}
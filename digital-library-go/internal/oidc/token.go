@@ -0,0 +1,186 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type claims struct {
+	Issuer        string       `json:"iss"`
+	Subject       string       `json:"sub"`
+	Audience      Audience     `json:"aud"`
+	Expiry        int64        `json:"exp"`
+	Nonce         string       `json:"nonce"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+}
+
+// Audience accepts both the single-string and array forms of "aud".
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = Audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// flexibleBool accepts "true" as a string, which some providers send for
+// email_verified.
+type flexibleBool bool
+
+func (f *flexibleBool) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	*f = flexibleBool(s == "true")
+	return nil
+}
+
+const keySetTTL = time.Hour
+
+// KeySet verifies RS256 JSON Web Tokens against the keys published at a
+// JWKS URL. The keys are fetched on first use and again when they are
+// stale or a token names an unknown key (key rotation).
+type KeySet struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	fetched time.Time
+	keys    map[string]*rsa.PublicKey
+}
+
+func NewKeySet(url string, client *http.Client) *KeySet {
+	return &KeySet{url: url, client: client}
+}
+
+// Verify checks the token's signature and decodes its payload into
+// claims. Checking the claims themselves is up to the caller.
+func (s *KeySet) Verify(ctx context.Context, raw string, claims any) error {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return errors.New("oidc: malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return err
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("oidc: unsupported signing algorithm %q", header.Alg)
+	}
+
+	key, err := s.publicKey(ctx, header.Kid)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return errors.New("oidc: invalid token signature")
+	}
+	return decodeSegment(parts[1], claims)
+}
+
+// verify checks the ID token signature against the provider's published
+// keys and validates issuer, audience and expiry.
+func (p *Provider) verify(ctx context.Context, raw, issuer string) (claims, error) {
+	var c claims
+	if err := p.keys.Verify(ctx, raw, &c); err != nil {
+		return claims{}, err
+	}
+	if c.Issuer != issuer {
+		return claims{}, errors.New("oidc: id_token issuer mismatch")
+	}
+	if !slices.Contains(c.Audience, p.cfg.ClientID) {
+		return claims{}, errors.New("oidc: id_token not issued for this client")
+	}
+	if time.Now().Unix() >= c.Expiry {
+		return claims{}, errors.New("oidc: id_token expired")
+	}
+	if c.Subject == "" {
+		return claims{}, errors.New("oidc: id_token has no subject")
+	}
+	return c, nil
+}
+
+// publicKey returns the signing key with the given ID, refreshing the
+// cached keys when they are stale or the key is unknown.
+func (s *KeySet) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys != nil && time.Since(s.fetched) < keySetTTL {
+		if key, ok := s.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.url, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	s.fetched, s.keys = time.Now(), keys
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,90 @@
+// Package openlibrary looks up book metadata by ISBN in the Open Library
+// catalog (https://openlibrary.org/dev/docs/api/books).
+package openlibrary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const DefaultBaseURL = "https://openlibrary.org"
+
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient returns a client for the Open Library instance at baseURL.
+// Pass a client from a circuit breaker so outages fail fast.
+func NewClient(baseURL string, client *http.Client) *Client {
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (c *Client) Name() string {
+	return "openlibrary"
+}
+
+type record struct {
+	Title       string `json:"title"`
+	PublishDate string `json:"publish_date"`
+	Authors     []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+}
+
+// Lookup returns the metadata Open Library holds for an ISBN, or
+// domain.ErrMetadataNotFound.
+func (c *Client) Lookup(ctx context.Context, isbn string) (domain.BookMetadata, error) {
+	key := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {key}, "format": {"json"}, "jscmd": {"data"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return domain.BookMetadata{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return domain.BookMetadata{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return domain.BookMetadata{}, fmt.Errorf("openlibrary: lookup returned %s", resp.Status)
+	}
+
+	var records map[string]record
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return domain.BookMetadata{}, err
+	}
+	r, ok := records[key]
+	if !ok {
+		return domain.BookMetadata{}, domain.ErrMetadataNotFound
+	}
+
+	names := []string{}
+	for _, a := range r.Authors {
+		names = append(names, a.Name)
+	}
+	return domain.BookMetadata{
+		Title:  r.Title,
+		Author: strings.Join(names, ", "),
+		Year:   publishYear(r.PublishDate),
+	}, nil
+}
+
+var yearPattern = regexp.MustCompile(`\b\d{4}\b`)
+
+// publishYear extracts the year from free-form dates such as "2004",
+// "June 2004" or "Jun 04, 2004".
+func publishYear(date string) int {
+	year, _ := strconv.Atoi(yearPattern.FindString(date))
+	return year
+}
@@ -0,0 +1,144 @@
+// Package pdf writes simple text-only PDF documents, enough for the
+// printable worklists staff take to the shelves. It supports two fonts,
+// regular and bold Helvetica, and breaks pages automatically.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait, in points.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+	leading    = 14
+	fontSize   = 10
+)
+
+type line struct {
+	text string
+	bold bool
+	// gap adds blank space above the line.
+	gap int
+}
+
+// Document collects lines of text and lays them out on pages.
+type Document struct {
+	title string
+	lines []line
+}
+
+// New starts a document whose title is printed at the top of every page.
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+// Heading adds a bold line set off from the text above it.
+func (d *Document) Heading(text string) {
+	d.lines = append(d.lines, line{text: text, bold: true, gap: leading / 2})
+}
+
+// Text adds a regular line.
+func (d *Document) Text(text string) {
+	d.lines = append(d.lines, line{text: text})
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() []byte {
+	var objects []string
+	add := func(obj string) int {
+		objects = append(objects, obj)
+		return len(objects)
+	}
+
+	catalog := add("") // filled in once the page tree exists
+	pages := add("")
+	regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	kids := []string{}
+	layout := d.layout()
+	for n, page := range layout {
+		var content strings.Builder
+		y := pageHeight - margin
+		fmt.Fprintf(&content, "BT /F2 12 Tf %d %d Td (%s) Tj ET\n", margin, y, escape(d.title))
+		fmt.Fprintf(&content, "BT /F1 8 Tf %d %d Td (Page %d of %d) Tj ET\n", pageWidth-margin-60, y, n+1, len(layout))
+		y -= 2 * leading
+		for _, l := range page {
+			y -= l.gap
+			font := "/F1"
+			if l.bold {
+				font = "/F2"
+			}
+			fmt.Fprintf(&content, "BT %s %d Tf %d %d Td (%s) Tj ET\n", font, fontSize, margin, y, escape(l.text))
+			y -= leading
+		}
+		stream := add(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+		page := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			pages, pageWidth, pageHeight, regular, bold, stream))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages)
+	objects[pages-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, xref)
+	return buf.Bytes()
+}
+
+// layout splits the lines into pages. A heading is never left alone at
+// the bottom of a page. There is always at least one page.
+func (d *Document) layout() [][]line {
+	room := pageHeight - 2*margin - 2*leading
+	pages := [][]line{{}}
+	used := 0
+	for i, l := range d.lines {
+		need := l.gap + leading
+		if l.bold && i+1 < len(d.lines) {
+			need += leading
+		}
+		if used+need > room && len(pages[len(pages)-1]) > 0 {
+			pages = append(pages, []line{})
+			used = 0
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], l)
+		used += l.gap + leading
+	}
+	return pages
+}
+
+// escape makes text safe inside a PDF string. Characters outside Latin-1
+// cannot be shown with the standard fonts and are replaced by '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,192 @@
+// Package resilience guards calls to external services with per-attempt
+// timeouts, retries and a circuit breaker, so a slow or failing third party
+// fails fast instead of tying up request handlers.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// ErrOpen is returned without calling the dependency while its breaker is
+// open.
+var ErrOpen = errors.New("circuit breaker open")
+
+type Policy struct {
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// Retries is the number of attempts after the first. Backoff is the
+	// wait before the first retry and doubles after each one.
+	Retries int
+	Backoff time.Duration
+	// FailureThreshold consecutive failures open the breaker for OpenFor,
+	// after which a single trial call decides whether it closes again.
+	FailureThreshold int
+	OpenFor          time.Duration
+}
+
+var DefaultPolicy = Policy{
+	Timeout:          5 * time.Second,
+	Retries:          2,
+	Backoff:          200 * time.Millisecond,
+	FailureThreshold: 5,
+	OpenFor:          30 * time.Second,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, such as a rejected
+// request. It is returned as is and does not count against the breaker,
+// since the dependency did answer.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Breaker guards one dependency.
+type Breaker struct {
+	name   string
+	policy Policy
+
+	mu          sync.Mutex
+	state       string
+	failures    int
+	lastErr     error
+	lastFailure time.Time
+	openUntil   time.Time
+}
+
+func NewBreaker(name string, policy Policy) *Breaker {
+	return &Breaker{name: name, policy: policy, state: domain.BreakerClosed}
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Do calls fn, retrying failures with backoff, unless the breaker is open.
+func (b *Breaker) Do(ctx context.Context, fn func(context.Context) error) error {
+	return b.do(ctx, b.policy.Retries, fn)
+}
+
+func (b *Breaker) do(ctx context.Context, retries int, fn func(context.Context) error) error {
+	backoff := b.policy.Backoff
+	for attempt := 0; ; attempt++ {
+		if err := b.allow(); err != nil {
+			return err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, b.policy.Timeout)
+		err := fn(attemptCtx)
+		cancel()
+
+		var permanent permanentError
+		if err == nil || errors.As(err, &permanent) {
+			b.record(nil)
+			return err
+		}
+		b.record(err)
+
+		if attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// allow refuses calls while the breaker is open. Once OpenFor has passed
+// it lets one trial call through and refuses the rest until it completes.
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case domain.BreakerOpen:
+		if time.Now().Before(b.openUntil) {
+			return ErrOpen
+		}
+		b.state = domain.BreakerHalfOpen
+		return nil
+	case domain.BreakerHalfOpen:
+		return ErrOpen
+	}
+	return nil
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.state = domain.BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+	b.lastFailure = time.Now()
+	if b.state == domain.BreakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.state = domain.BreakerOpen
+		b.openUntil = b.lastFailure.Add(b.policy.OpenFor)
+	}
+}
+
+func (b *Breaker) Health() domain.DependencyHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := domain.DependencyHealth{
+		Name:        b.name,
+		State:       b.state,
+		Failures:    b.failures,
+		LastFailure: b.lastFailure,
+	}
+	if b.lastErr != nil {
+		h.LastError = b.lastErr.Error()
+	}
+	if b.state == domain.BreakerOpen {
+		h.OpenUntil = b.openUntil
+	}
+	return h
+}
+
+// Registry keeps the breakers of all dependencies so their health can be
+// reported together.
+type Registry struct {
+	mu       sync.Mutex
+	breakers []*Breaker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{breakers: []*Breaker{}}
+}
+
+// Breaker creates and registers a breaker for the named dependency.
+func (r *Registry) Breaker(name string, policy Policy) *Breaker {
+	b := NewBreaker(name, policy)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakers = append(r.breakers, b)
+	return b
+}
+
+func (r *Registry) Health() []domain.DependencyHealth {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	health := make([]domain.DependencyHealth, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		health = append(health, b.Health())
+	}
+	return health
+}
@@ -0,0 +1,74 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Transport returns an http.RoundTripper that sends requests through the
+// breaker. Server errors (5xx) count as failures; other responses are
+// passed back to the caller. Only idempotent requests are retried.
+func (b *Breaker) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{breaker: b, base: base}
+}
+
+// Client returns an HTTP client whose requests go through the breaker.
+func (b *Breaker) Client() *http.Client {
+	return &http.Client{Transport: b.Transport(nil)}
+}
+
+type transport struct {
+	breaker *Breaker
+	base    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := t.breaker.policy.Retries
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		retries = 0
+	}
+
+	var resp *http.Response
+	err := t.breaker.do(req.Context(), retries, func(ctx context.Context) error {
+		// The attempt's context must outlive RoundTrip so the body can
+		// still be read; it is released when the body is closed.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.breaker.policy.Timeout)
+		stop := context.AfterFunc(req.Context(), cancel)
+
+		r, err := t.base.RoundTrip(req.Clone(ctx))
+		if err != nil {
+			stop()
+			cancel()
+			return err
+		}
+		if r.StatusCode >= 500 {
+			r.Body.Close()
+			stop()
+			cancel()
+			return fmt.Errorf("%s %s returned %s", req.Method, req.URL.Redacted(), r.Status)
+		}
+		r.Body = &cancelBody{ReadCloser: r.Body, cancel: func() { stop(); cancel() }}
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxExpansions bounds the alternatives a query expands into, so a query
+// full of synonyms does not multiply the work of every search.
+const maxExpansions = 16
+
+// Words splits text into lowercase words, the way queries and synonyms
+// are compared: "Sci-Fi" is "sci" and "fi".
+func Words(text string) []string {
+	return words(text)
+}
+
+// Expand rewrites a query into the alternatives it stands for: the query
+// itself and, for each phrase of it found in a synonym set, the query
+// with that phrase replaced by each other phrase of the set. Stop words
+// are then dropped from every alternative, unless it has nothing else.
+// Each alternative is a list of lowercase words; removed lists the stop
+// words dropped.
+func Expand(q string, synonyms [][]string, stopWords map[string]bool) (alternatives [][]string, removed []string) {
+	sets := make([][][]string, 0, len(synonyms))
+	for _, set := range synonyms {
+		phrases := [][]string{}
+		for _, phrase := range set {
+			if w := words(phrase); len(w) > 0 {
+				phrases = append(phrases, w)
+			}
+		}
+		sets = append(sets, phrases)
+	}
+
+	first := words(q)
+	if len(first) == 0 {
+		return [][]string{}, []string{}
+	}
+	expanded := [][]string{first}
+	seen := map[string]bool{strings.Join(first, " "): true}
+	for i := 0; i < len(expanded) && len(expanded) < maxExpansions; i++ {
+		for _, phrases := range sets {
+			for _, from := range phrases {
+				for at := range occurrences(expanded[i], from) {
+					for _, to := range phrases {
+						alt := slices.Concat(expanded[i][:at], to, expanded[i][at+len(from):])
+						if key := strings.Join(alt, " "); !seen[key] && len(expanded) < maxExpansions {
+							seen[key] = true
+							expanded = append(expanded, alt)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	alternatives, removed = [][]string{}, []string{}
+	kept := map[string]bool{}
+	for _, alt := range expanded {
+		content := slices.DeleteFunc(slices.Clone(alt), func(w string) bool { return stopWords[w] })
+		if len(content) == 0 {
+			content = alt
+		}
+		for _, w := range alt {
+			if stopWords[w] && !slices.Contains(content, w) && !slices.Contains(removed, w) {
+				removed = append(removed, w)
+			}
+		}
+		if key := strings.Join(content, " "); !kept[key] {
+			kept[key] = true
+			alternatives = append(alternatives, content)
+		}
+	}
+	return alternatives, removed
+}
+
+// occurrences yields where phrase starts in ws.
+func occurrences(ws, phrase []string) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for at := 0; at+len(phrase) <= len(ws); at++ {
+			if slices.Equal(ws[at:at+len(phrase)], phrase) && !yield(at) {
+				return
+			}
+		}
+	}
+}
+
+// MatchesAll reports whether every word is found in one of texts,
+// case-insensitively. A word may be part of a longer one, so "hobb"
+// finds "The Hobbit".
+func MatchesAll(ws []string, texts ...string) bool {
+	lower := make([]string, len(texts))
+	for i, text := range texts {
+		lower[i] = strings.ToLower(text)
+	}
+	for _, w := range ws {
+		if !slices.ContainsFunc(lower, func(text string) bool { return strings.Contains(text, w) }) {
+			return false
+		}
+	}
+	return true
+}
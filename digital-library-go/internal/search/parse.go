@@ -0,0 +1,357 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// Fields a term can be scoped to, as in author:tolkien.
+var Fields = []string{"title", "author", "year", "isbn", "language"}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenField
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+// token is one token of a query. A field token has the field in field
+// and its value in text, which may be a phrase.
+type token struct {
+	kind   tokenKind
+	text   string
+	field  string
+	phrase bool
+}
+
+// Parse reads a query into a tree of nodes, or nil for an empty query,
+// which matches everything:
+//
+//   - Words are matched in the title or author, all of them by default:
+//     lord rings.
+//   - "Quoted words" are matched as a phrase.
+//   - AND, OR and NOT, in capitals, combine terms; NOT binds tightest,
+//     then AND, then OR. Parentheses group terms.
+//   - field:value scopes a term to a field: title:, author:, isbn:,
+//     language:, or year:, which takes a year, a comparison such as
+//     year:>1950, or a range such as year:1950..1960. A value may be a
+//     phrase: author:"le guin". Words before a colon that are not a
+//     field, as in "Star Wars: A New Hope", are plain words.
+//
+// Consecutive plain words make one term, so that synonyms of several
+// words can be expanded.
+func Parse(q string) (Node, error) {
+	tokens, err := tokenize(q)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New("unexpected ) in query")
+	}
+	return n, nil
+}
+
+func tokenize(q string) ([]token, error) {
+	tokens := []token{}
+	rs := []rune(q)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+			i++
+		case r == '"':
+			text, next, err := quoted(rs, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenPhrase, text: text})
+			i = next
+		default:
+			start := i
+			for i < len(rs) && !unicode.IsSpace(rs[i]) && rs[i] != '(' && rs[i] != ')' && rs[i] != '"' && rs[i] != ':' {
+				i++
+			}
+			word := string(rs[start:i])
+			field := strings.ToLower(word)
+			if i < len(rs) && rs[i] == ':' && slices.Contains(Fields, field) {
+				i++
+				t := token{kind: tokenField, field: field}
+				if i < len(rs) && rs[i] == '"' {
+					text, next, err := quoted(rs, i)
+					if err != nil {
+						return nil, err
+					}
+					t.text, t.phrase, i = text, true, next
+				} else {
+					start := i
+					for i < len(rs) && !unicode.IsSpace(rs[i]) && rs[i] != '(' && rs[i] != ')' {
+						i++
+					}
+					t.text = string(rs[start:i])
+				}
+				if strings.TrimSpace(t.text) == "" {
+					return nil, fmt.Errorf("%s: needs a value", field)
+				}
+				tokens = append(tokens, t)
+				continue
+			}
+			if i < len(rs) && rs[i] == ':' {
+				// Not a field: the colon is punctuation.
+				i++
+			}
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd})
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot})
+			default:
+				if word != "" {
+					tokens = append(tokens, token{kind: tokenWord, text: word})
+				}
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// quoted reads the phrase starting at the quote at rs[i] and returns it
+// with the position after its closing quote.
+func quoted(rs []rune, i int) (string, int, error) {
+	end := i + 1
+	for end < len(rs) && rs[end] != '"' {
+		end++
+	}
+	if end == len(rs) {
+		return "", 0, errors.New("unclosed quote in query")
+	}
+	return string(rs[i+1 : end]), end + 1, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) or() (Node, error) {
+	nodes := []Node{}
+	for {
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokenOr {
+			break
+		}
+		p.pos++
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+	return &Or{Nodes: nodes}, nil
+}
+
+func (p *parser) and() (Node, error) {
+	// Terms of no words, such as !!!, leave no node but still count as
+	// terms.
+	nodes := []Node{}
+	terms := 0
+	explicit := false
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokenOr || t.kind == tokenClose {
+			if explicit || terms == 0 {
+				return nil, errors.New("missing term in query")
+			}
+			break
+		}
+		if t.kind == tokenAnd {
+			if terms == 0 || explicit {
+				return nil, errors.New("missing term before AND in query")
+			}
+			explicit = true
+			p.pos++
+			continue
+		}
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		terms++
+		// Plain words next to each other make one term.
+		if term, ok := n.(*Term); ok && !explicit && len(nodes) > 0 && plain(term) {
+			if last, ok := nodes[len(nodes)-1].(*Term); ok && plain(last) {
+				last.Words = append(last.Words, term.Words...)
+				continue
+			}
+		}
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+		explicit = false
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+	return &And{Nodes: nodes}, nil
+}
+
+func plain(t *Term) bool {
+	return t.Field == "" && !t.Phrase
+}
+
+func (p *parser) unary() (Node, error) {
+	t, _ := p.peek()
+	if t.kind != tokenNot {
+		return p.primary()
+	}
+	p.pos++
+	if next, ok := p.peek(); !ok || next.kind == tokenAnd || next.kind == tokenOr || next.kind == tokenClose {
+		return nil, errors.New("missing term after NOT in query")
+	}
+	n, err := p.unary()
+	if n == nil || err != nil {
+		return nil, err
+	}
+	return &Not{Node: n}, nil
+}
+
+func (p *parser) primary() (Node, error) {
+	t, _ := p.peek()
+	p.pos++
+	switch t.kind {
+	case tokenOpen:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != tokenClose {
+			return nil, errors.New("missing ) in query")
+		}
+		p.pos++
+		return n, nil
+	case tokenClose:
+		return nil, errors.New("unexpected ) in query")
+	case tokenField:
+		return field(t)
+	}
+	return term("", t.text, t.kind == tokenPhrase), nil
+}
+
+// term builds the term of the words of text, or nil if it has none.
+func term(field, text string, phrase bool) Node {
+	ws := words(text)
+	if len(ws) == 0 {
+		return nil
+	}
+	return &Term{Field: field, Words: ws, Phrase: phrase}
+}
+
+// field builds the node of a field-scoped term.
+func field(t token) (Node, error) {
+	switch t.field {
+	case "year":
+		return yearRange(t.text)
+	case "isbn":
+		isbn := normalizeISBN(t.text)
+		if isbn == "" {
+			return nil, errors.New("isbn: must be an ISBN")
+		}
+		return &Exact{Field: "isbn", Value: isbn}, nil
+	case "language":
+		if !domain.ValidLanguage(t.text) {
+			return nil, fmt.Errorf("language: %w", domain.ErrInvalidLanguage)
+		}
+		return &Exact{Field: "language", Value: domain.NormalizeLanguage(t.text)}, nil
+	}
+	if n := term(t.field, t.text, t.phrase); n != nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("%s: needs a value", t.field)
+}
+
+// yearRange reads the value of year: 1950, >1950, >=1950, <1950, <=1950
+// or 1950..1960.
+func yearRange(v string) (Node, error) {
+	bad := errors.New("year: must be a year, a comparison such as >1950, or a range such as 1950..1960")
+	year := func(s string) (int, bool) {
+		n, err := strconv.Atoi(s)
+		return n, err == nil
+	}
+	if from, to, ok := strings.Cut(v, ".."); ok {
+		min, ok1 := year(from)
+		max, ok2 := year(to)
+		if !ok1 || !ok2 || min > max {
+			return nil, bad
+		}
+		return &Range{Min: min, Max: max}, nil
+	}
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		rest, ok := strings.CutPrefix(v, op)
+		if !ok {
+			continue
+		}
+		n, ok := year(rest)
+		if !ok {
+			return nil, bad
+		}
+		switch op {
+		case ">=":
+			return &Range{Min: n, Max: math.MaxInt}, nil
+		case "<=":
+			return &Range{Min: math.MinInt, Max: n}, nil
+		case ">":
+			return &Range{Min: n + 1, Max: math.MaxInt}, nil
+		}
+		return &Range{Min: math.MinInt, Max: n - 1}, nil
+	}
+	n, ok := year(v)
+	if !ok {
+		return nil, bad
+	}
+	return &Range{Min: n, Max: n}, nil
+}
@@ -0,0 +1,213 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// Doc is what a query is matched against: the searchable fields of a
+// book.
+type Doc struct {
+	Title    string
+	Author   string
+	Year     int
+	ISBN     string
+	Language string
+}
+
+// Node is a node of a parsed query.
+type Node interface {
+	// Match reports whether the document matches the node.
+	Match(d Doc) bool
+	// String writes the node back as a query, with its grouping made
+	// explicit.
+	String() string
+}
+
+// Term matches words in the title or author, or in the one Field names.
+// A phrase matches its words next to each other and in order; other
+// terms match when every word is found, in any order, in either field,
+// or every word of one of Alternatives, when the term was expanded.
+type Term struct {
+	Field        string
+	Words        []string
+	Phrase       bool
+	Alternatives [][]string
+}
+
+// Range matches books published from Min to Max, both included.
+type Range struct {
+	Min, Max int
+}
+
+// Exact matches a field with exactly one value: an ISBN, digits only, or
+// a language code.
+type Exact struct {
+	Field string
+	Value string
+}
+
+type And struct{ Nodes []Node }
+type Or struct{ Nodes []Node }
+type Not struct{ Node Node }
+
+func (t *Term) Match(d Doc) bool {
+	texts := []string{d.Title, d.Author}
+	switch t.Field {
+	case "title":
+		texts = []string{d.Title}
+	case "author":
+		texts = []string{d.Author}
+	}
+	if t.Phrase {
+		return slices.ContainsFunc(texts, func(text string) bool { return hasPhrase(words(text), t.Words) })
+	}
+	if len(t.Alternatives) == 0 {
+		return MatchesAll(t.Words, texts...)
+	}
+	return slices.ContainsFunc(t.Alternatives, func(ws []string) bool { return MatchesAll(ws, texts...) })
+}
+
+func (r *Range) Match(d Doc) bool {
+	return d.Year != 0 && d.Year >= r.Min && d.Year <= r.Max
+}
+
+func (e *Exact) Match(d Doc) bool {
+	if e.Field == "isbn" {
+		return normalizeISBN(d.ISBN) == e.Value
+	}
+	return domain.NormalizeLanguage(d.Language) == e.Value
+}
+
+func (a *And) Match(d Doc) bool {
+	for _, n := range a.Nodes {
+		if !n.Match(d) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *Or) Match(d Doc) bool {
+	return slices.ContainsFunc(o.Nodes, func(n Node) bool { return n.Match(d) })
+}
+
+func (n *Not) Match(d Doc) bool {
+	return !n.Node.Match(d)
+}
+
+func (t *Term) String() string {
+	s := strings.Join(t.Words, " ")
+	if t.Phrase {
+		s = `"` + s + `"`
+	}
+	if t.Field != "" {
+		s = t.Field + ":" + s
+	}
+	return s
+}
+
+func (r *Range) String() string {
+	switch {
+	case r.Min == r.Max:
+		return fmt.Sprintf("year:%d", r.Min)
+	case r.Max == math.MaxInt:
+		return fmt.Sprintf("year:>=%d", r.Min)
+	case r.Min == math.MinInt:
+		return fmt.Sprintf("year:<=%d", r.Max)
+	}
+	return fmt.Sprintf("year:%d..%d", r.Min, r.Max)
+}
+
+func (e *Exact) String() string { return e.Field + ":" + e.Value }
+func (a *And) String() string   { return join(a.Nodes, " AND ") }
+func (o *Or) String() string    { return join(o.Nodes, " OR ") }
+func (n *Not) String() string   { return "NOT " + n.Node.String() }
+
+func join(nodes []Node, op string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return "(" + strings.Join(parts, op) + ")"
+}
+
+// Terms lists the terms of a query that may be expanded with synonyms and
+// stop words: runs of plain words, not phrases nor field-scoped words.
+func Terms(n Node) []*Term {
+	terms := []*Term{}
+	walk(n, false, func(n Node, negated bool) {
+		if t, ok := n.(*Term); ok && t.Field == "" && !t.Phrase {
+			terms = append(terms, t)
+		}
+	})
+	return terms
+}
+
+// Texts lists what a query looks for in titles and authors, for ranking:
+// the words of each term, or of each of its alternatives, that is not
+// under a NOT.
+func Texts(n Node) []string {
+	texts := []string{}
+	walk(n, false, func(n Node, negated bool) {
+		t, ok := n.(*Term)
+		if !ok || negated {
+			return
+		}
+		if len(t.Alternatives) == 0 {
+			texts = append(texts, strings.Join(t.Words, " "))
+		}
+		for _, alt := range t.Alternatives {
+			texts = append(texts, strings.Join(alt, " "))
+		}
+	})
+	return texts
+}
+
+// walk calls fn for n and every node below it, saying whether it is
+// under a NOT.
+func walk(n Node, negated bool, fn func(n Node, negated bool)) {
+	if n == nil {
+		return
+	}
+	fn(n, negated)
+	switch n := n.(type) {
+	case *And:
+		for _, c := range n.Nodes {
+			walk(c, negated, fn)
+		}
+	case *Or:
+		for _, c := range n.Nodes {
+			walk(c, negated, fn)
+		}
+	case *Not:
+		walk(n.Node, !negated, fn)
+	}
+}
+
+// hasPhrase reports whether phrase is found in ws, its words next to
+// each other and in order. The last word may be the start of a longer
+// one, so "lord of the ring" finds "The Lord of the Rings".
+func hasPhrase(ws, phrase []string) bool {
+	for at := 0; at+len(phrase) <= len(ws); at++ {
+		last := len(phrase) - 1
+		if slices.Equal(ws[at:at+last], phrase[:last]) && strings.HasPrefix(ws[at+last], phrase[last]) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeISBN(isbn string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) || r == 'X' || r == 'x' {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, isbn)
+}
@@ -0,0 +1,142 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// didYouMeanBeam is how many corrected queries DidYouMean returns at most.
+const didYouMeanBeam = 3
+
+// words splits text into lowercase words.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+type candidate struct {
+	word string
+	dist int
+	freq int
+}
+
+// DidYouMean proposes corrected versions of a query that found nothing.
+// Each word not in the index is replaced by the closest indexed words
+// within a small edit distance; the best combinations come first, by
+// total edits and then by how common their words are. It returns nothing
+// if no word needed or allowed a correction.
+func (ix *Index) DidYouMean(q string) []string {
+	type option struct {
+		words []string
+		dist  int
+		freq  int
+	}
+	beam := []option{{}}
+	corrected := false
+
+	for _, w := range words(q) {
+		cands := ix.corrections(w)
+		if len(cands) == 0 {
+			cands = []candidate{{word: w}}
+		} else if cands[0].dist > 0 {
+			corrected = true
+		}
+
+		next := []option{}
+		for _, o := range beam {
+			for _, c := range cands {
+				next = append(next, option{
+					words: append(append([]string(nil), o.words...), c.word),
+					dist:  o.dist + c.dist,
+					freq:  o.freq + c.freq,
+				})
+			}
+		}
+		sort.SliceStable(next, func(i, j int) bool {
+			if next[i].dist != next[j].dist {
+				return next[i].dist < next[j].dist
+			}
+			return next[i].freq > next[j].freq
+		})
+		beam = next[:min(len(next), didYouMeanBeam)]
+	}
+
+	if !corrected {
+		return []string{}
+	}
+	suggestions := []string{}
+	for _, o := range beam {
+		if o.dist > 0 {
+			suggestions = append(suggestions, strings.Join(o.words, " "))
+		}
+	}
+	return suggestions
+}
+
+// corrections returns the word itself if it is indexed, or else the
+// closest indexed words within maxEdits, best first.
+func (ix *Index) corrections(w string) []candidate {
+	if freq, ok := ix.words[w]; ok {
+		return []candidate{{word: w, freq: freq}}
+	}
+	limit := maxEdits(len([]rune(w)))
+	if limit == 0 {
+		return nil
+	}
+
+	cands := []candidate{}
+	for word, freq := range ix.words {
+		if d := distance([]rune(w), []rune(word), limit); d <= limit {
+			cands = append(cands, candidate{word: word, dist: d, freq: freq})
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].dist != cands[j].dist {
+			return cands[i].dist < cands[j].dist
+		}
+		if cands[i].freq != cands[j].freq {
+			return cands[i].freq > cands[j].freq
+		}
+		return cands[i].word < cands[j].word
+	})
+	return cands[:min(len(cands), didYouMeanBeam)]
+}
+
+// distance is the Levenshtein distance between a and b, or max+1 as soon
+// as it is known to exceed max.
+func distance(a, b []rune, max int) int {
+	if abs(len(a)-len(b)) > max {
+		return max + 1
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		best := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			best = min(best, cur[j])
+		}
+		if best > max {
+			return max + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
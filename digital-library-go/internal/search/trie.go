@@ -0,0 +1,210 @@
+// Package search holds in-memory indexes over the catalog used by the
+// search endpoints.
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+type node struct {
+	children map[rune]*node
+	// entries lists every term reachable below this node, so a prefix
+	// lookup does not need to walk the subtree.
+	entries []int
+}
+
+type entry struct {
+	suggestion domain.Suggestion
+	key        string
+}
+
+// Index is a trie of titles and author names for prefix and fuzzy
+// completion. Every word of a term is indexed, so "hob" finds "The
+// Hobbit". It is not safe for concurrent writes; build it once, then
+// query it from any number of goroutines.
+type Index struct {
+	root    *node
+	entries []entry
+	byKey   map[string]int
+	// words counts how often each word occurs, for spelling correction.
+	words map[string]int
+}
+
+func NewIndex() *Index {
+	return &Index{root: &node{}, byKey: map[string]int{}, words: map[string]int{}}
+}
+
+// Add indexes a term. Adding the same text and kind again counts one
+// more book for it.
+func (ix *Index) Add(text, kind string) {
+	for _, w := range words(text) {
+		ix.words[w]++
+	}
+
+	key := kind + "\x00" + strings.ToLower(text)
+	if i, ok := ix.byKey[key]; ok {
+		ix.entries[i].suggestion.Books++
+		return
+	}
+
+	i := len(ix.entries)
+	ix.entries = append(ix.entries, entry{suggestion: domain.Suggestion{Text: text, Kind: kind, Books: 1}, key: strings.ToLower(text)})
+	ix.byKey[key] = i
+
+	lower := []rune(strings.ToLower(text))
+	for start := range lower {
+		if start > 0 && !isWordStart(lower, start) {
+			continue
+		}
+		n := ix.root
+		for _, r := range lower[start:] {
+			if n.children == nil {
+				n.children = map[rune]*node{}
+			}
+			child := n.children[r]
+			if child == nil {
+				child = &node{}
+				n.children[r] = child
+			}
+			if len(child.entries) == 0 || child.entries[len(child.entries)-1] != i {
+				child.entries = append(child.entries, i)
+			}
+			n = child
+		}
+	}
+}
+
+func isWordStart(s []rune, i int) bool {
+	return !unicode.IsLetter(s[i-1]) && !unicode.IsDigit(s[i-1]) && (unicode.IsLetter(s[i]) || unicode.IsDigit(s[i]))
+}
+
+// Suggest returns up to limit completions for q. Exact prefix matches
+// rank first, by how many books they find; if there are not enough,
+// prefixes within a small edit distance of q fill the rest.
+func (ix *Index) Suggest(q string, limit int) []domain.Suggestion {
+	query := []rune(strings.ToLower(strings.TrimSpace(q)))
+	if len(query) == 0 || limit <= 0 {
+		return []domain.Suggestion{}
+	}
+
+	exact := ix.lookup(query)
+	ranked := ix.top(exact, query, limit)
+	if len(ranked) < limit {
+		seen := map[int]bool{}
+		for _, i := range exact {
+			seen[i] = true
+		}
+		fuzzy := []int{}
+		for _, i := range ix.fuzzy(query, maxEdits(len(query))) {
+			if !seen[i] {
+				seen[i] = true
+				fuzzy = append(fuzzy, i)
+			}
+		}
+		ranked = append(ranked, ix.top(fuzzy, query, limit-len(ranked))...)
+	}
+
+	suggestions := make([]domain.Suggestion, 0, len(ranked))
+	for _, i := range ranked {
+		suggestions = append(suggestions, ix.entries[i].suggestion)
+	}
+	return suggestions
+}
+
+func (ix *Index) lookup(query []rune) []int {
+	n := ix.root
+	for _, r := range query {
+		n = n.children[r]
+		if n == nil {
+			return nil
+		}
+	}
+	return n.entries
+}
+
+// top returns the best k entries: terms that start with the query before
+// those where a later word does, then by books found, then shorter
+// first. It keeps a small sorted slice rather than sorting every match,
+// since short prefixes can match most of the catalog.
+func (ix *Index) top(ids []int, query []rune, k int) []int {
+	prefix := string(query)
+	better := func(a, b int) bool {
+		ea, eb := ix.entries[a], ix.entries[b]
+		pa, pb := strings.HasPrefix(ea.key, prefix), strings.HasPrefix(eb.key, prefix)
+		if pa != pb {
+			return pa
+		}
+		if ea.suggestion.Books != eb.suggestion.Books {
+			return ea.suggestion.Books > eb.suggestion.Books
+		}
+		return len(ea.key) < len(eb.key)
+	}
+
+	best := make([]int, 0, k)
+	for _, id := range ids {
+		if len(best) == k && !better(id, best[k-1]) {
+			continue
+		}
+		pos := sort.Search(len(best), func(i int) bool { return better(id, best[i]) })
+		if len(best) < k {
+			best = append(best, 0)
+		}
+		copy(best[pos+1:], best[pos:len(best)-1])
+		best[pos] = id
+	}
+	return best
+}
+
+// maxEdits allows one typo in short queries and two in longer ones. Very
+// short queries are not matched fuzzily at all.
+func maxEdits(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	}
+	return 2
+}
+
+// fuzzy walks the trie with a Levenshtein row per node and collects the
+// terms below every node whose path is within maxDist edits of query.
+func (ix *Index) fuzzy(query []rune, maxDist int) []int {
+	if maxDist == 0 {
+		return nil
+	}
+	found := []int{}
+	row := make([]int, len(query)+1)
+	for i := range row {
+		row[i] = i
+	}
+	var walk func(n *node, prev []int)
+	walk = func(n *node, prev []int) {
+		for r, child := range n.children {
+			cur := make([]int, len(prev))
+			cur[0] = prev[0] + 1
+			best := cur[0]
+			for i := 1; i < len(cur); i++ {
+				cost := 1
+				if query[i-1] == r {
+					cost = 0
+				}
+				cur[i] = min(prev[i]+1, cur[i-1]+1, prev[i-1]+cost)
+				best = min(best, cur[i])
+			}
+			if cur[len(cur)-1] <= maxDist {
+				found = append(found, child.entries...)
+				continue
+			}
+			if best <= maxDist {
+				walk(child, cur)
+			}
+		}
+	}
+	walk(ix.root, row)
+	return found
+}
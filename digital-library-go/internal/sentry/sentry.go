@@ -0,0 +1,213 @@
+// Package sentry reports errors to Sentry (https://sentry.io) as events
+// posted to the envelope endpoint of a project
+// (https://develop.sentry.dev/sdk/data-model/envelopes/).
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const clientName = "digital-library-go/1.0"
+
+var ErrInvalidDSN = errors.New("sentry: DSN must look like https://key@host/project")
+
+type Client struct {
+	dsn         string
+	endpoint    string
+	key         string
+	release     string
+	environment string
+	serverName  string
+	module      string
+	client      *http.Client
+}
+
+// NewClient returns a client for the project of dsn, the client key shown
+// in the project's settings. Events are tagged with release, the version
+// of the server, environment and serverName, the instance. Pass a client
+// from a circuit breaker so outages fail fast.
+func NewClient(dsn, release, environment, serverName string, client *http.Client) (*Client, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, ErrInvalidDSN
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	project := path[i+1:]
+	if _, err := strconv.Atoi(project); err != nil {
+		return nil, ErrInvalidDSN
+	}
+
+	c := &Client{
+		dsn:         dsn,
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path[:i], project),
+		key:         u.User.Username(),
+		release:     release,
+		environment: environment,
+		serverName:  serverName,
+		client:      client,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		c.module = info.Main.Path
+	}
+	return c, nil
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type exception struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Mechanism struct {
+		Type    string `json:"type"`
+		Handled bool   `json:"handled"`
+	} `json:"mechanism"`
+	Stacktrace *stacktrace `json:"stacktrace,omitempty"`
+}
+
+type user struct {
+	ID        string `json:"id,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	Tags        map[string]string `json:"tags"`
+	Exception   struct {
+		Values []exception `json:"values"`
+	} `json:"exception"`
+	Request struct {
+		Method  string            `json:"method"`
+		URL     string            `json:"url"`
+		Headers map[string]string `json:"headers,omitempty"`
+	} `json:"request"`
+	User *user `json:"user,omitempty"`
+}
+
+// Report sends a report to Sentry as an event of the exception it
+// describes, with the request it happened in. It returns the event ID.
+func (c *Client) Report(ctx context.Context, r domain.ErrorReport) (string, error) {
+	id, err := eventID()
+	if err != nil {
+		return "", err
+	}
+	ev := c.event(id, r)
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return "", err
+	}
+	header, _ := json.Marshal(map[string]string{"event_id": id, "dsn": c.dsn, "sent_at": time.Now().UTC().Format(time.RFC3339)})
+	item, _ := json.Marshal(map[string]any{"type": "event", "length": len(body)})
+
+	var envelope bytes.Buffer
+	for _, line := range [][]byte{header, item, body} {
+		envelope.Write(line)
+		envelope.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, &envelope)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", clientName, c.key))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("sentry: envelope returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return id, nil
+}
+
+func (c *Client) event(id string, r domain.ErrorReport) event {
+	ev := event{
+		EventID:     id,
+		Timestamp:   r.Time.UTC(),
+		Platform:    "go",
+		Level:       r.Level,
+		Release:     c.release,
+		Environment: c.environment,
+		ServerName:  c.serverName,
+		Tags:        map[string]string{"status": strconv.Itoa(r.Status)},
+	}
+	if r.Route != "" {
+		ev.Transaction = r.Method + " " + r.Route
+	}
+
+	exc := exception{Type: r.Type, Value: r.Message}
+	exc.Mechanism.Type = "gin"
+	exc.Mechanism.Handled = r.Level != domain.ReportFatal
+	if len(r.Stack) > 0 {
+		exc.Stacktrace = &stacktrace{}
+		// Sentry lists frames outermost first.
+		for i := len(r.Stack) - 1; i >= 0; i-- {
+			f := r.Stack[i]
+			exc.Stacktrace.Frames = append(exc.Stacktrace.Frames, frame{
+				Function: f.Function,
+				Filename: f.File,
+				Lineno:   f.Line,
+				InApp:    strings.HasPrefix(f.Function, "main.") || c.module != "" && strings.HasPrefix(f.Function, c.module),
+			})
+		}
+	}
+	ev.Exception.Values = []exception{exc}
+
+	ev.Request.Method = r.Method
+	ev.Request.URL = r.Path
+	if r.UserAgent != "" {
+		ev.Request.Headers = map[string]string{"User-Agent": r.UserAgent}
+	}
+	if r.MemberID != 0 || r.ClientIP != "" {
+		ev.User = &user{IPAddress: r.ClientIP}
+		if r.MemberID != 0 {
+			ev.User.ID = strconv.Itoa(r.MemberID)
+		}
+	}
+	return ev
+}
+
+// eventID returns a random event ID: 32 hex digits.
+func eventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,296 @@
+// Package sftp uploads files over SFTP version 3
+// (draft-ietf-secsh-filexfer-02), the version OpenSSH serves. It only
+// implements what an upload needs: opening a file, writing it and
+// closing it.
+package sftp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Packet types.
+const (
+	fxpInit    = 1
+	fxpVersion = 2
+	fxpOpen    = 3
+	fxpClose   = 4
+	fxpWrite   = 6
+	fxpStatus  = 101
+	fxpHandle  = 102
+)
+
+// Open flags.
+const (
+	fxfWrite = 0x02
+	fxfCreat = 0x08
+	fxfTrunc = 0x10
+)
+
+const (
+	// chunkSize is the most data sent in one write; servers must accept
+	// packets of at least 32768 bytes.
+	chunkSize = 32 * 1024
+	// maxPacket bounds the replies read, which for an upload are small.
+	maxPacket = 256 * 1024
+)
+
+var ErrUnexpectedReply = errors.New("sftp: unexpected reply from server")
+
+// StatusError is a failure reported by the server, such as permission
+// denied or no such file.
+type StatusError struct {
+	Code    uint32
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("sftp: %s (status %d)", e.Message, e.Code)
+}
+
+// Config says how to reach and log in to a server. HostKey is the
+// server's public key in authorized_keys format; connections to a server
+// presenting any other key are refused. PrivateKey is PEM and takes
+// precedence over Password.
+type Config struct {
+	Addr       string
+	User       string
+	Password   string
+	PrivateKey string
+	HostKey    string
+}
+
+// Upload writes data to path on the server, replacing any file there.
+// The context's deadline covers the whole upload.
+func Upload(ctx context.Context, cfg Config, path string, data []byte) error {
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+	if err != nil {
+		return fmt.Errorf("sftp: invalid host key: %w", err)
+	}
+	auth := ssh.Password(cfg.Password)
+	if cfg.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("sftp: invalid private key: %w", err)
+		}
+		auth = ssh.PublicKeys(signer)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Cancelling ctx unblocks any read or write in progress.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	sc, chans, reqs, err := ssh.NewClientConn(conn, cfg.Addr, &ssh.ClientConfig{
+		User:            cfg.User,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	})
+	if err != nil {
+		return err
+	}
+	client := ssh.NewClient(sc, chans, reqs)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	w, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		return err
+	}
+
+	s := &conversation{w: w, r: r}
+	if err := s.init(); err != nil {
+		return err
+	}
+	handle, err := s.open(path)
+	if err != nil {
+		return err
+	}
+	for offset := 0; offset < len(data); offset += chunkSize {
+		chunk := data[offset:min(offset+chunkSize, len(data))]
+		if err := s.write(handle, uint64(offset), chunk); err != nil {
+			s.close(handle)
+			return err
+		}
+	}
+	// The server may only report a failed write, e.g. a full disk, when
+	// the file is closed.
+	return s.close(handle)
+}
+
+// conversation sends one request at a time and waits for its reply.
+type conversation struct {
+	w      io.Writer
+	r      io.Reader
+	nextID uint32
+}
+
+func (s *conversation) init() error {
+	if err := s.send(fxpInit, binary.BigEndian.AppendUint32(nil, 3)); err != nil {
+		return err
+	}
+	typ, _, err := s.receive()
+	if err != nil {
+		return err
+	}
+	if typ != fxpVersion {
+		return ErrUnexpectedReply
+	}
+	return nil
+}
+
+func (s *conversation) open(path string) ([]byte, error) {
+	id, payload := s.request()
+	payload = appendString(payload, []byte(path))
+	payload = binary.BigEndian.AppendUint32(payload, fxfWrite|fxfCreat|fxfTrunc)
+	payload = binary.BigEndian.AppendUint32(payload, 0) // no attributes
+	if err := s.send(fxpOpen, payload); err != nil {
+		return nil, err
+	}
+
+	typ, body, err := s.reply(id)
+	if err != nil {
+		return nil, err
+	}
+	if typ == fxpStatus {
+		return nil, status(body)
+	}
+	handle, _, ok := readString(body)
+	if typ != fxpHandle || !ok {
+		return nil, ErrUnexpectedReply
+	}
+	return handle, nil
+}
+
+func (s *conversation) write(handle []byte, offset uint64, data []byte) error {
+	id, payload := s.request()
+	payload = appendString(payload, handle)
+	payload = binary.BigEndian.AppendUint64(payload, offset)
+	payload = appendString(payload, data)
+	if err := s.send(fxpWrite, payload); err != nil {
+		return err
+	}
+	return s.status(id)
+}
+
+func (s *conversation) close(handle []byte) error {
+	id, payload := s.request()
+	if err := s.send(fxpClose, appendString(payload, handle)); err != nil {
+		return err
+	}
+	return s.status(id)
+}
+
+// request starts the payload of a request with a fresh id.
+func (s *conversation) request() (uint32, []byte) {
+	s.nextID++
+	return s.nextID, binary.BigEndian.AppendUint32(nil, s.nextID)
+}
+
+// status waits for the reply to a request answered by a status, and
+// returns nil if it reports success.
+func (s *conversation) status(id uint32) error {
+	typ, body, err := s.reply(id)
+	if err != nil {
+		return err
+	}
+	if typ != fxpStatus {
+		return ErrUnexpectedReply
+	}
+	return status(body)
+}
+
+// reply reads the next packet, which must answer request id, and returns
+// its type and the body after the id.
+func (s *conversation) reply(id uint32) (byte, []byte, error) {
+	typ, body, err := s.receive()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(body) < 4 || binary.BigEndian.Uint32(body) != id {
+		return 0, nil, ErrUnexpectedReply
+	}
+	return typ, body[4:], nil
+}
+
+func (s *conversation) send(typ byte, payload []byte) error {
+	packet := binary.BigEndian.AppendUint32(nil, uint32(1+len(payload)))
+	packet = append(packet, typ)
+	_, err := s.w.Write(append(packet, payload...))
+	return err
+}
+
+func (s *conversation) receive() (byte, []byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(s.r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(header[:])
+	if n == 0 || n > maxPacket {
+		return 0, nil, ErrUnexpectedReply
+	}
+	packet := make([]byte, n)
+	if _, err := io.ReadFull(s.r, packet); err != nil {
+		return 0, nil, err
+	}
+	return packet[0], packet[1:], nil
+}
+
+// status turns the body of a status reply into an error, or nil for
+// SSH_FX_OK.
+func status(body []byte) error {
+	if len(body) < 4 {
+		return ErrUnexpectedReply
+	}
+	code := binary.BigEndian.Uint32(body)
+	if code == 0 {
+		return nil
+	}
+	msg, _, ok := readString(body[4:])
+	if !ok || len(msg) == 0 {
+		msg = []byte("request failed")
+	}
+	return &StatusError{Code: code, Message: string(msg)}
+}
+
+func appendString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func readString(b []byte) ([]byte, []byte, bool) {
+	if len(b) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(b)
+	if uint64(n) > uint64(len(b)-4) {
+		return nil, nil, false
+	}
+	return b[4 : 4+n], b[4+n:], true
+}
@@ -0,0 +1,228 @@
+// Package shadow mirrors requests to a second upstream, such as the
+// FastAPI service this server replaces, and reports where its answers
+// differ from the server's own. The upstream's answers are only
+// compared, never returned to clients.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxBody bounds the responses compared; larger ones are skipped.
+const MaxBody = 1 << 20
+
+// maxDifferences bounds the differences listed for one response.
+const maxDifferences = 10
+
+// maxInFlight bounds the mirrored requests waiting on the upstream, so a
+// slow upstream cannot pile them up. Requests past it are not mirrored.
+const maxInFlight = 32
+
+var ErrInvalidUpstream = errors.New("shadow: upstream must be an http or https URL")
+
+// hopHeaders are not passed on to the upstream. Accept-Encoding is left
+// for the client to set, so the upstream's body arrives decompressed.
+var hopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade", "Accept-Encoding"}
+
+// Request is a request to mirror: its method, its path with the query
+// string and its headers.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header
+}
+
+// Response is the server's own answer to a mirrored request.
+type Response struct {
+	Status int
+	Body   []byte
+}
+
+type Mirror struct {
+	upstream string
+	percent  float64
+	ignore   map[string]bool
+	client   *http.Client
+	inFlight chan struct{}
+}
+
+// NewMirror returns a mirror of percent of the requests offered to it to
+// upstream. Object fields named in ignore, e.g. timestamps, are left out
+// of the comparison wherever they occur.
+func NewMirror(upstream string, percent float64, ignore []string, client *http.Client) (*Mirror, error) {
+	u, err := url.Parse(upstream)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidUpstream
+	}
+	if percent < 0 || percent > 100 {
+		return nil, errors.New("shadow: percent must be between 0 and 100")
+	}
+	m := &Mirror{
+		upstream: strings.TrimSuffix(upstream, "/"),
+		percent:  percent,
+		ignore:   map[string]bool{},
+		client:   client,
+		inFlight: make(chan struct{}, maxInFlight),
+	}
+	for _, field := range ignore {
+		m.ignore[field] = true
+	}
+	return m, nil
+}
+
+// Sample picks the requests to mirror, percent of them at random.
+func (m *Mirror) Sample() bool {
+	return rand.Float64()*100 < m.percent
+}
+
+// Go mirrors a request in the background and logs the differences
+// between the answers, if any. It reports false when too many mirrored
+// requests are still waiting on the upstream to take another one.
+func (m *Mirror) Go(req Request, own Response) bool {
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		return false
+	}
+	go func() {
+		defer func() { <-m.inFlight }()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		diffs, err := m.Compare(ctx, req, own)
+		switch {
+		case err != nil:
+			log.Printf("[SHADOW] %s %s: %v", req.Method, req.URL, err)
+		case len(diffs) > 0:
+			log.Printf("[SHADOW] %s %s differs from upstream: %s", req.Method, req.URL, strings.Join(diffs, "; "))
+		}
+	}()
+	return true
+}
+
+// Compare sends a request to the upstream and lists how its answer
+// differs from own: in status, and in body. JSON bodies are compared
+// field by field, others byte by byte.
+func (m *Mirror) Compare(ctx context.Context, req Request, own Response) ([]string, error) {
+	r, err := http.NewRequestWithContext(ctx, req.Method, m.upstream+req.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Header = req.Header.Clone()
+	for _, h := range hopHeaders {
+		r.Header.Del(h)
+	}
+	r.Header.Set("X-Shadow-Request", "1")
+
+	resp, err := m.client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBody+1))
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	if own.Status != resp.StatusCode {
+		diffs = append(diffs, fmt.Sprintf("status %d, upstream %d", own.Status, resp.StatusCode))
+	}
+	if len(body) > MaxBody || len(own.Body) > MaxBody {
+		return diffs, nil
+	}
+	return append(diffs, m.Diff(own.Body, body)...), nil
+}
+
+// Diff lists how body, an answer of this server, differs from upstream,
+// the upstream's answer to the same request, up to maxDifferences of
+// them. Differences in JSON bodies are given by path, e.g.
+// "$.data[0].title".
+func (m *Mirror) Diff(body, upstream []byte) []string {
+	var a, b any
+	if json.Unmarshal(body, &a) != nil || json.Unmarshal(upstream, &b) != nil {
+		if bytes.Equal(body, upstream) {
+			return nil
+		}
+		return []string{fmt.Sprintf("body of %d bytes, upstream %d bytes", len(body), len(upstream))}
+	}
+	var diffs []string
+	m.diff("$", a, b, &diffs)
+	if len(diffs) > maxDifferences {
+		diffs = append(diffs[:maxDifferences], fmt.Sprintf("and %d more", len(diffs)-maxDifferences))
+	}
+	return diffs
+}
+
+func (m *Mirror) diff(path string, a, b any, diffs *[]string) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := []string{}
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if m.ignore[k] {
+				continue
+			}
+			ka, inA := av[k]
+			kb, inB := bv[k]
+			switch {
+			case !inA:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s missing, upstream %s", path, k, show(kb)))
+			case !inB:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s %s, missing upstream", path, k, show(ka)))
+			default:
+				m.diff(path+"."+k, ka, kb, diffs)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		if len(av) != len(bv) {
+			*diffs = append(*diffs, fmt.Sprintf("%s has %d items, upstream %d", path, len(av), len(bv)))
+		}
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			m.diff(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, fmt.Sprintf("%s %s, upstream %s", path, show(a), show(b)))
+	}
+}
+
+// show writes a JSON value for the log, shortened if it is long.
+func show(v any) string {
+	b, _ := json.Marshal(v)
+	if len(b) > 60 {
+		return string(b[:57]) + "..."
+	}
+	return string(b)
+}
@@ -0,0 +1,101 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var errNoSuchIntent = errors.New("stripe: no such payment intent")
+
+// Mock is an in-memory stand-in for Stripe, for development and demos.
+// Payment intents it creates stay open until a webhook event signed with
+// its secret reports them paid, failed or canceled, as Stripe would once
+// the member paid. Events change the mock's intents too, so later lookups
+// agree with them.
+type Mock struct {
+	webhookSecret string
+
+	mu      sync.Mutex
+	intents map[string]domain.PaymentIntent
+	byRef   map[string]string
+	nextID  int
+}
+
+func NewMock(webhookSecret string) *Mock {
+	return &Mock{
+		webhookSecret: webhookSecret,
+		intents:       map[string]domain.PaymentIntent{},
+		byRef:         map[string]string{},
+		nextID:        1,
+	}
+}
+
+func (m *Mock) CreatePaymentIntent(ctx context.Context, amount int, currency, description, reference string) (domain.PaymentIntent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id, ok := m.byRef[reference]; ok {
+		return m.intents[id], nil
+	}
+	id := fmt.Sprintf("pi_mock_%d", m.nextID)
+	m.nextID++
+	intent := domain.PaymentIntent{
+		ID:           id,
+		ClientSecret: id + "_secret_mock",
+		Status:       domain.IntentRequiresPaymentMethod,
+		Amount:       amount,
+		Currency:     currency,
+	}
+	m.intents[id] = intent
+	m.byRef[reference] = id
+	return intent, nil
+}
+
+func (m *Mock) GetPaymentIntent(ctx context.Context, id string) (domain.PaymentIntent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	intent, ok := m.intents[id]
+	if !ok {
+		return domain.PaymentIntent{}, errNoSuchIntent
+	}
+	return intent, nil
+}
+
+func (m *Mock) CancelPaymentIntent(ctx context.Context, id string) (domain.PaymentIntent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	intent, ok := m.intents[id]
+	if !ok {
+		return domain.PaymentIntent{}, errNoSuchIntent
+	}
+	if intent.Status == domain.IntentSucceeded {
+		return domain.PaymentIntent{}, errors.New("stripe: a succeeded payment intent cannot be canceled")
+	}
+	intent.Status = domain.IntentCanceled
+	m.intents[id] = intent
+	return intent, nil
+}
+
+// ParseEvent reads a webhook event signed like Stripe's, applying it to
+// the intent it is about.
+func (m *Mock) ParseEvent(r *http.Request) (domain.PaymentEvent, error) {
+	event, err := parseEvent(r, m.webhookSecret, time.Now())
+	if err != nil {
+		return event, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if intent, ok := m.intents[event.Intent.ID]; ok {
+		intent.Status = event.Intent.Status
+		intent.AmountReceived = event.Intent.AmountReceived
+		intent.FailureMessage = event.Intent.FailureMessage
+		m.intents[intent.ID] = intent
+	}
+	return event, nil
+}
@@ -0,0 +1,208 @@
+// Package stripe takes card payments through Stripe Payment Intents
+// (https://docs.stripe.com/api/payment_intents) and checks the signatures
+// on its webhook events. Mock stands in for Stripe where no account is
+// configured.
+package stripe
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const DefaultBaseURL = "https://api.stripe.com"
+
+// SignatureTolerance is how old a webhook event's timestamp may be,
+// against replays.
+const SignatureTolerance = 5 * time.Minute
+
+// maxEventSize bounds a webhook event body.
+const maxEventSize = 1 << 20
+
+var ErrInvalidSignature = errors.New("stripe: invalid webhook signature")
+
+type Client struct {
+	baseURL       string
+	secretKey     string
+	webhookSecret string
+	client        *http.Client
+}
+
+// NewClient returns a client for the account with secretKey. Webhook
+// events are checked against webhookSecret, the signing secret of the
+// webhook endpoint. Pass a client from a circuit breaker so outages fail
+// fast.
+func NewClient(baseURL, secretKey, webhookSecret string, client *http.Client) *Client {
+	return &Client{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		client:        client,
+	}
+}
+
+type paymentIntent struct {
+	ID               string `json:"id"`
+	ClientSecret     string `json:"client_secret"`
+	Status           string `json:"status"`
+	Amount           int    `json:"amount"`
+	AmountReceived   int    `json:"amount_received"`
+	Currency         string `json:"currency"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+func (pi paymentIntent) domain() domain.PaymentIntent {
+	intent := domain.PaymentIntent{
+		ID:             pi.ID,
+		ClientSecret:   pi.ClientSecret,
+		Status:         pi.Status,
+		Amount:         pi.Amount,
+		AmountReceived: pi.AmountReceived,
+		Currency:       pi.Currency,
+	}
+	if pi.LastPaymentError != nil {
+		intent.FailureMessage = pi.LastPaymentError.Message
+	}
+	return intent
+}
+
+// CreatePaymentIntent starts a payment of amount in the smallest unit of
+// currency. reference is sent as the idempotency key, so retrying with
+// the same reference never charges twice, and is kept in the intent's
+// metadata.
+func (c *Client) CreatePaymentIntent(ctx context.Context, amount int, currency, description, reference string) (domain.PaymentIntent, error) {
+	form := url.Values{
+		"amount":                             {strconv.Itoa(amount)},
+		"currency":                           {currency},
+		"description":                        {description},
+		"metadata[reference]":                {reference},
+		"automatic_payment_methods[enabled]": {"true"},
+	}
+	return c.call(ctx, http.MethodPost, "/v1/payment_intents", form, reference)
+}
+
+func (c *Client) GetPaymentIntent(ctx context.Context, id string) (domain.PaymentIntent, error) {
+	return c.call(ctx, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(id), nil, "")
+}
+
+func (c *Client) CancelPaymentIntent(ctx context.Context, id string) (domain.PaymentIntent, error) {
+	return c.call(ctx, http.MethodPost, "/v1/payment_intents/"+url.PathEscape(id)+"/cancel", url.Values{}, "")
+}
+
+// ParseEvent reads a webhook event, checking its Stripe-Signature.
+func (c *Client) ParseEvent(r *http.Request) (domain.PaymentEvent, error) {
+	return parseEvent(r, c.webhookSecret, time.Now())
+}
+
+func (c *Client) call(ctx context.Context, method, path string, form url.Values, idempotencyKey string) (domain.PaymentIntent, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		return domain.PaymentIntent{}, fmt.Errorf("stripe: %s %s returned %s: %s", method, path, resp.Status, e.Error.Message)
+	}
+	var pi paymentIntent
+	if err := json.NewDecoder(resp.Body).Decode(&pi); err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	return pi.domain(), nil
+}
+
+// parseEvent reads a webhook event signed with secret. The
+// Stripe-Signature header holds the timestamp t and one or more v1
+// signatures, each the hex HMAC-SHA256 of the timestamp, a dot and the
+// body; several are sent while a secret is being rolled.
+func parseEvent(r *http.Request, secret string, now time.Time) (domain.PaymentEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize))
+	if err != nil {
+		return domain.PaymentEvent{}, err
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(r.Header.Get("Stripe-Signature"), ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return domain.PaymentEvent{}, ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > SignatureTolerance || d < -SignatureTolerance {
+		return domain.PaymentEvent{}, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	want := hex.EncodeToString(mac.Sum(nil))
+	valid := false
+	for _, sig := range signatures {
+		valid = valid || hmac.Equal([]byte(want), []byte(sig))
+	}
+	if !valid {
+		return domain.PaymentEvent{}, ErrInvalidSignature
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return domain.PaymentEvent{}, err
+	}
+	e := domain.PaymentEvent{ID: event.ID, Type: event.Type}
+	if strings.HasPrefix(event.Type, "payment_intent.") {
+		var pi paymentIntent
+		if err := json.Unmarshal(event.Data.Object, &pi); err != nil {
+			return domain.PaymentEvent{}, err
+		}
+		e.Intent = pi.domain()
+	}
+	return e, nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// DeletionGracePeriod is how long a member can undo an account deletion
+// request before their data is anonymized.
+const DeletionGracePeriod = 30 * 24 * time.Hour
+
+var (
+	ErrActiveLoans        = domain.Conflict("return all loans before deleting the account")
+	ErrDeletionNotPending = domain.Conflict("no account deletion is pending")
+)
+
+// AccountUsecase implements the data subject rights of members: exporting
+// their data and erasing it.
+type AccountUsecase struct {
+	members *MemberUsecase
+	auth    *AuthUsecase
+	loans   *LoanUsecase
+	holds   *HoldUsecase
+	fines   *FineUsecase
+	lists   *ReadingListUsecase
+	// searches and notifications belong to the member too, so they are
+	// exported and deleted along with the lists.
+	searches      *SavedSearchUsecase
+	notifications *NotificationUsecase
+	bookings      *BookingUsecase
+	events        *EventUsecase
+	reviews       *ReviewUsecase
+	push          *PushUsecase
+}
+
+func NewAccountUsecase(
+	members *MemberUsecase,
+	auth *AuthUsecase,
+	loans *LoanUsecase,
+	holds *HoldUsecase,
+	fines *FineUsecase,
+	lists *ReadingListUsecase,
+	searches *SavedSearchUsecase,
+	notifications *NotificationUsecase,
+	bookings *BookingUsecase,
+	events *EventUsecase,
+	reviews *ReviewUsecase,
+	push *PushUsecase,
+) *AccountUsecase {
+	return &AccountUsecase{
+		members:       members,
+		auth:          auth,
+		loans:         loans,
+		holds:         holds,
+		fines:         fines,
+		lists:         lists,
+		searches:      searches,
+		notifications: notifications,
+		bookings:      bookings,
+		events:        events,
+		reviews:       reviews,
+		push:          push,
+	}
+}
+
+func (u *AccountUsecase) Export(memberID int) (domain.PersonalData, error) {
+	member, err := u.members.GetMemberByID(memberID)
+	if err != nil {
+		return domain.PersonalData{}, err
+	}
+	return domain.PersonalData{
+		ExportedAt:    time.Now(),
+		Profile:       member,
+		Loans:         u.loans.LoansForMember(memberID),
+		Holds:         u.holds.HoldsForMember(memberID),
+		Fines:         u.fines.FinesForMember(memberID),
+		ReadingLists:  u.lists.ListsForMember(memberID),
+		SavedSearches: u.searches.SearchesForMember(memberID),
+		Notifications: u.notifications.NotificationsForMember(memberID),
+		Preferences:   u.notifications.PreferencesForMember(memberID),
+		Bookings:      u.bookings.BookingsForMember(memberID),
+		Registrations: u.events.RegistrationsForMember(memberID),
+		Reviews:       u.reviews.ReviewsForMember(memberID),
+		Push:          u.push.SubscriptionsForMember(memberID),
+	}, nil
+}
+
+// RequestDeletion schedules the account for erasure once the grace period
+// has passed and ends the member's sessions. Members with books still out
+// must return them first. Until the account is restored, the member
+// cannot borrow or place holds.
+func (u *AccountUsecase) RequestDeletion(memberID int) (domain.Member, error) {
+	if len(u.loans.ActiveLoansForMember(memberID)) > 0 {
+		return domain.Member{}, ErrActiveLoans
+	}
+	member, err := u.members.ScheduleDeletion(memberID, time.Now().Add(DeletionGracePeriod))
+	if err != nil {
+		return domain.Member{}, err
+	}
+	u.auth.RevokeSessions(memberID)
+	return member, nil
+}
+
+func (u *AccountUsecase) CancelDeletion(memberID int) (domain.Member, error) {
+	member, err := u.members.GetMemberByID(memberID)
+	if err != nil {
+		return domain.Member{}, err
+	}
+	if member.DeletionScheduledAt == nil {
+		return domain.Member{}, ErrDeletionNotPending
+	}
+	return u.members.ClearDeletion(memberID)
+}
+
+// PurgeExpired erases every account whose grace period has ended. Loans
+// and fines are anonymized rather than removed so aggregate statistics
+// survive; holds, reading lists, saved searches, notifications, push
+// subscriptions, bookings, event registrations, reviews and the member
+// record are deleted. Accounts that still have a book out, such as one
+// lent just as deletion was requested, are kept until the next sweep.
+func (u *AccountUsecase) PurgeExpired(now time.Time) {
+	for _, id := range u.members.DueForDeletion(now) {
+		if len(u.loans.ActiveLoansForMember(id)) > 0 {
+			log.Println("Account purge deferred for member", id, "with active loans")
+			continue
+		}
+		u.loans.AnonymizeMember(id)
+		u.fines.AnonymizeMember(id)
+		u.holds.CancelHoldsForMember(id)
+		u.lists.DeleteListsForMember(id)
+		u.searches.DeleteSearchesForMember(id)
+		u.notifications.DeleteForMember(id)
+		u.push.DeleteSubscriptionsForMember(id)
+		u.bookings.DeleteBookingsForMember(id)
+		u.events.DeleteRegistrationsForMember(id)
+		u.reviews.DeleteReviewsForMember(id)
+		if err := u.members.DeleteMember(id); err != nil {
+			log.Println("Account purge failed for member", id, err)
+			continue
+		}
+		u.auth.RevokeSessions(id)
+		log.Println("Account purged for member", id)
+	}
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var ErrNotPendingCataloging = domain.Conflict("acquisition has already been cataloged")
+
+// scanLookups is how many metadata lookups a scan runs at once.
+const scanLookups = 8
+
+// AcquisitionUsecase turns batches of scanned ISBNs into draft records
+// for librarians to review and catalog. Drafts are looked up in the same
+// metadata providers as the metadata refresh.
+type AcquisitionUsecase struct {
+	mu           sync.RWMutex
+	acquisitions []domain.Acquisition
+	nextID       int
+	books        *BookUsecase
+	providers    []MetadataProvider
+}
+
+func NewAcquisitionUsecase(books *BookUsecase, providers ...MetadataProvider) *AcquisitionUsecase {
+	return &AcquisitionUsecase{
+		acquisitions: []domain.Acquisition{},
+		nextID:       1,
+		books:        books,
+		providers:    providers,
+	}
+}
+
+// Acquisitions returns the drafts with the given status, or all of them
+// if status is empty, oldest first.
+func (u *AcquisitionUsecase) Acquisitions(status string) []domain.Acquisition {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	found := []domain.Acquisition{}
+	for _, a := range u.acquisitions {
+		if status == "" || a.Status == status {
+			found = append(found, a)
+		}
+	}
+	return found
+}
+
+func (u *AcquisitionUsecase) GetAcquisitionByID(id int) (domain.Acquisition, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Acquisition{}, err
+	}
+	return u.acquisitions[i], nil
+}
+
+// Scan creates a draft for each new ISBN, filled in from the metadata
+// providers. ISBNs already in the catalog or awaiting cataloging, and
+// repeats within the batch, are reported as duplicates instead.
+func (u *AcquisitionUsecase) Scan(ctx context.Context, isbns []string, now time.Time) domain.ScanSummary {
+	summary := domain.ScanSummary{Created: []domain.Acquisition{}, Duplicates: []domain.ScanDuplicate{}, Invalid: []string{}}
+
+	u.mu.RLock()
+	queued := map[string]int{}
+	for _, a := range u.acquisitions {
+		if key, _ := domain.ISBN13(a.ISBN); a.Status == domain.AcquisitionPending {
+			queued[key] = a.ID
+		}
+	}
+	u.mu.RUnlock()
+
+	drafts := []domain.Acquisition{}
+	seen := map[string]bool{}
+	for _, raw := range isbns {
+		isbn := domain.NormalizeISBN(raw)
+		if !domain.ValidISBN(isbn) {
+			summary.Invalid = append(summary.Invalid, raw)
+			continue
+		}
+		key, _ := domain.ISBN13(isbn)
+		if book, ok := u.books.BookByISBN(key); ok {
+			summary.Duplicates = append(summary.Duplicates, domain.ScanDuplicate{ISBN: isbn, BookID: book.ID})
+			continue
+		}
+		if acquisitionID, ok := queued[key]; ok {
+			summary.Duplicates = append(summary.Duplicates, domain.ScanDuplicate{ISBN: isbn, AcquisitionID: acquisitionID})
+			continue
+		}
+		if seen[key] {
+			summary.Duplicates = append(summary.Duplicates, domain.ScanDuplicate{ISBN: isbn})
+			continue
+		}
+		seen[key] = true
+		drafts = append(drafts, domain.Acquisition{ISBN: isbn, Status: domain.AcquisitionPending, ScannedAt: now})
+	}
+
+	u.lookup(ctx, drafts)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, d := range drafts {
+		// Another scan may have queued the same ISBN during the lookups.
+		if existing, ok := u.pending(d.ISBN); ok {
+			summary.Duplicates = append(summary.Duplicates, domain.ScanDuplicate{ISBN: d.ISBN, AcquisitionID: existing.ID})
+			continue
+		}
+		d.ID = u.nextID
+		u.nextID++
+		u.acquisitions = append(u.acquisitions, d)
+		summary.Created = append(summary.Created, d)
+		if d.Provider == "" {
+			summary.Incomplete++
+		}
+	}
+	return summary
+}
+
+// lookup fills in the drafts from the first provider with a record for
+// each, a few ISBNs at a time. Failed lookups leave a draft blank.
+func (u *AcquisitionUsecase) lookup(ctx context.Context, drafts []domain.Acquisition) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, scanLookups)
+	for i := range drafts {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(d *domain.Acquisition) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			for _, p := range u.providers {
+				meta, err := p.Lookup(ctx, d.ISBN)
+				if err != nil {
+					continue
+				}
+				d.Title, d.Author, d.Year, d.Provider = meta.Title, meta.Author, meta.Year, p.Name()
+				return
+			}
+		}(&drafts[i])
+	}
+	wg.Wait()
+}
+
+// Catalog adds book, already completed from the draft and validated, to
+// the catalog and marks the draft as cataloged. It returns the book as
+// stored.
+func (u *AcquisitionUsecase) Catalog(id int, book domain.Book) (domain.Book, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if u.acquisitions[i].Status != domain.AcquisitionPending {
+		return domain.Book{}, ErrNotPendingCataloging
+	}
+	if err := u.books.CreateBook(book); err != nil {
+		return domain.Book{}, err
+	}
+	u.acquisitions[i].Status = domain.AcquisitionCataloged
+	u.acquisitions[i].BookID = book.ID
+	return u.books.GetBookByID(book.ID)
+}
+
+// Discard drops a draft that will not be cataloged, such as a book that
+// was scanned by mistake.
+func (u *AcquisitionUsecase) Discard(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return err
+	}
+	if u.acquisitions[i].Status != domain.AcquisitionPending {
+		return ErrNotPendingCataloging
+	}
+	u.acquisitions = append(u.acquisitions[:i], u.acquisitions[i+1:]...)
+	return nil
+}
+
+// pending expects the caller to hold the lock.
+func (u *AcquisitionUsecase) pending(isbn string) (domain.Acquisition, bool) {
+	key, _ := domain.ISBN13(isbn)
+	for _, a := range u.acquisitions {
+		if other, _ := domain.ISBN13(a.ISBN); a.Status == domain.AcquisitionPending && other == key {
+			return a, true
+		}
+	}
+	return domain.Acquisition{}, false
+}
+
+// index expects the caller to hold the lock.
+func (u *AcquisitionUsecase) index(id int) (int, error) {
+	for i, a := range u.acquisitions {
+		if a.ID == id {
+			return i, nil
+		}
+	}
+	return 0, domain.NotFound("acquisition not found")
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const (
+	QuotaRead  = "read"
+	QuotaWrite = "write"
+
+	apiKeyPrefix = "dlk_"
+)
+
+var ErrInvalidAPIKey = domain.Unauthorized("invalid API key")
+
+// Quota is the number of requests of one class an API key may make per
+// UTC day and per calendar month.
+type Quota struct {
+	Daily   int
+	Monthly int
+}
+
+// DefaultQuotas apply to every API key.
+var DefaultQuotas = map[string]Quota{
+	QuotaRead:  {Daily: 10000, Monthly: 200000},
+	QuotaWrite: {Daily: 1000, Monthly: 20000},
+}
+
+// QuotaExceededError is returned when a request would go over one of the
+// key's quotas. Usage describes the quota that ran out.
+type QuotaExceededError struct {
+	Usage domain.QuotaUsage
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota of %d per %s exceeded", e.Usage.Class, e.Usage.Limit, e.Usage.Window)
+}
+
+// counter holds the requests of one class made in the current day and
+// month, identified by their start.
+type counter struct {
+	day, month     time.Time
+	daily, monthly int
+}
+
+type storedKey struct {
+	key  domain.APIKey
+	hash [sha256.Size]byte
+}
+
+// APIKeyUsecase issues API keys and meters their requests against daily
+// and monthly quotas. Requests without a key are metered against the same
+// quotas per client IP.
+type APIKeyUsecase struct {
+	mu   sync.Mutex
+	keys []storedKey
+	// counters are keyed by what is metered: a key or a client IP, see
+	// keyMeter and ipMeter.
+	counters map[string]map[string]*counter
+	quotas   map[string]Quota
+	nextID   int
+}
+
+func NewAPIKeyUsecase(quotas map[string]Quota) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		keys:     []storedKey{},
+		counters: map[string]map[string]*counter{},
+		quotas:   quotas,
+		nextID:   1,
+	}
+}
+
+// SetQuotas replaces the quotas of the classes given; the counts made so
+// far are kept.
+func (u *APIKeyUsecase) SetQuotas(quotas map[string]Quota) error {
+	for class := range quotas {
+		if class != QuotaRead && class != QuotaWrite {
+			return fmt.Errorf("unknown rate limit class %s, must be %s or %s", class, QuotaRead, QuotaWrite)
+		}
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	next := maps.Clone(u.quotas)
+	maps.Copy(next, quotas)
+	u.quotas = next
+	return nil
+}
+
+// CreateKey issues a new key for the member and returns it together with
+// its secret, which is not stored and cannot be shown again.
+func (u *APIKeyUsecase) CreateKey(memberID int, name string, scopes []string) (domain.APIKey, string, error) {
+	for _, s := range scopes {
+		if !domain.ValidScope(s) {
+			return domain.APIKey{}, "", domain.Invalid("unknown scope " + s)
+		}
+	}
+	token, err := randomToken()
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	secret := apiKeyPrefix + token
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	key := domain.APIKey{
+		ID:        u.nextID,
+		MemberID:  memberID,
+		Name:      name,
+		Prefix:    secret[:len(apiKeyPrefix)+8],
+		Scopes:    append([]string{}, slices.Compact(slices.Sorted(slices.Values(scopes)))...),
+		CreatedAt: time.Now(),
+	}
+	u.nextID++
+	u.keys = append(u.keys, storedKey{key: key, hash: sha256.Sum256([]byte(secret))})
+	return key, secret, nil
+}
+
+func (u *APIKeyUsecase) KeysForMember(memberID int) []domain.APIKey {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	keys := []domain.APIKey{}
+	for _, k := range u.keys {
+		if k.key.MemberID == memberID {
+			keys = append(keys, k.key)
+		}
+	}
+	return keys
+}
+
+// RevokeKey deletes one of the member's keys. Keys belonging to other
+// members are reported as not found.
+func (u *APIKeyUsecase) RevokeKey(memberID, id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, k := range u.keys {
+		if k.key.ID == id && k.key.MemberID == memberID {
+			u.keys = append(u.keys[:i], u.keys[i+1:]...)
+			delete(u.counters, keyMeter(id))
+			return nil
+		}
+	}
+	return domain.NotFound("API key not found")
+}
+
+// Authenticate returns the key a secret belongs to.
+func (u *APIKeyUsecase) Authenticate(secret string) (domain.APIKey, error) {
+	hash := sha256.Sum256([]byte(secret))
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, k := range u.keys {
+		if k.hash == hash {
+			return k.key, nil
+		}
+	}
+	return domain.APIKey{}, ErrInvalidAPIKey
+}
+
+// Consume counts one request of the given class against the key. It
+// returns the usage of whichever quota has the least remaining, or a
+// QuotaExceededError without counting the request if any quota is used up.
+func (u *APIKeyUsecase) Consume(keyID int, class string, now time.Time) (domain.QuotaUsage, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	usage, err := u.consume(keyMeter(keyID), class, now)
+	if err != nil {
+		return domain.QuotaUsage{}, err
+	}
+	for i, k := range u.keys {
+		if k.key.ID == keyID {
+			u.keys[i].key.LastUsedAt = now
+		}
+	}
+	return usage, nil
+}
+
+// ConsumeIP is like Consume for a request without a key, counted against
+// the client IP it came from.
+func (u *APIKeyUsecase) ConsumeIP(ip, class string, now time.Time) (domain.QuotaUsage, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.consume(ipMeter(ip), class, now)
+}
+
+// Expire drops the counters of client IPs that made no request this
+// month. Key counters go when the key is revoked.
+func (u *APIKeyUsecase) Expire(now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now = now.UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for meter, classes := range u.counters {
+		if !strings.HasPrefix(meter, ipMeterPrefix) {
+			continue
+		}
+		stale := true
+		for _, c := range classes {
+			if !c.month.Before(month) {
+				stale = false
+			}
+		}
+		if stale {
+			delete(u.counters, meter)
+		}
+	}
+}
+
+// consume counts a request against a meter. It expects the caller to
+// hold the lock.
+func (u *APIKeyUsecase) consume(meter, class string, now time.Time) (domain.QuotaUsage, error) {
+	c := u.counter(meter, class, now)
+	usage := u.usage(class, c)
+	tightest := usage[0]
+	for _, q := range usage {
+		if q.Remaining <= 0 {
+			return domain.QuotaUsage{}, &QuotaExceededError{Usage: q}
+		}
+		if q.Remaining < tightest.Remaining {
+			tightest = q
+		}
+	}
+
+	c.daily++
+	c.monthly++
+	tightest.Used++
+	tightest.Remaining--
+	return tightest, nil
+}
+
+// Usage reports every quota of the key, or an error if the member does
+// not own it.
+func (u *APIKeyUsecase) Usage(memberID, keyID int, now time.Time) ([]domain.QuotaUsage, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	found := false
+	for _, k := range u.keys {
+		if k.key.ID == keyID && k.key.MemberID == memberID {
+			found = true
+		}
+	}
+	if !found {
+		return nil, domain.NotFound("API key not found")
+	}
+
+	usage := []domain.QuotaUsage{}
+	for _, class := range []string{QuotaRead, QuotaWrite} {
+		usage = append(usage, u.usage(class, u.counter(keyMeter(keyID), class, now))...)
+	}
+	return usage, nil
+}
+
+const ipMeterPrefix = "ip/"
+
+func keyMeter(keyID int) string {
+	return "key/" + strconv.Itoa(keyID)
+}
+
+func ipMeter(ip string) string {
+	return ipMeterPrefix + ip
+}
+
+// counter returns the meter's counter for a class, resetting windows that
+// have ended. The caller must hold the lock.
+func (u *APIKeyUsecase) counter(meter, class string, now time.Time) *counter {
+	if u.counters[meter] == nil {
+		u.counters[meter] = map[string]*counter{}
+	}
+	c := u.counters[meter][class]
+	if c == nil {
+		c = &counter{}
+		u.counters[meter][class] = c
+	}
+
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !c.day.Equal(day) {
+		c.day, c.daily = day, 0
+	}
+	if !c.month.Equal(month) {
+		c.month, c.monthly = month, 0
+	}
+	return c
+}
+
+func (u *APIKeyUsecase) usage(class string, c *counter) []domain.QuotaUsage {
+	quota := u.quotas[class]
+	return []domain.QuotaUsage{
+		{
+			Class:     class,
+			Window:    "day",
+			Limit:     quota.Daily,
+			Used:      c.daily,
+			Remaining: max(quota.Daily-c.daily, 0),
+			ResetsAt:  c.day.AddDate(0, 0, 1),
+		},
+		{
+			Class:     class,
+			Window:    "month",
+			Limit:     quota.Monthly,
+			Used:      c.monthly,
+			Remaining: max(quota.Monthly-c.monthly, 0),
+			ResetsAt:  c.month.AddDate(0, 1, 0),
+		},
+	}
+}
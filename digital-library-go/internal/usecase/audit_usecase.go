@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// auditCapacity bounds the in-memory log; the oldest events are dropped
+// first.
+const auditCapacity = 10000
+
+type AuditUsecase struct {
+	mu     sync.RWMutex
+	events []domain.AuditEvent
+	nextID int
+}
+
+func NewAuditUsecase() *AuditUsecase {
+	return &AuditUsecase{
+		events: []domain.AuditEvent{},
+		nextID: 1,
+	}
+}
+
+func (u *AuditUsecase) Record(event domain.AuditEvent) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	event.ID = u.nextID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	u.nextID++
+	u.events = append(u.events, event)
+	if len(u.events) > auditCapacity {
+		u.events = u.events[len(u.events)-auditCapacity:]
+	}
+}
+
+// GetEvents returns events, newest first, optionally filtered by type.
+func (u *AuditUsecase) GetEvents(eventType string) []domain.AuditEvent {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	events := []domain.AuditEvent{}
+	for i := len(u.events) - 1; i >= 0; i-- {
+		if eventType == "" || u.events[i].Type == eventType {
+			events = append(events, u.events[i])
+		}
+	}
+	return events
+}
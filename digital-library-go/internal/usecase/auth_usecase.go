@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrInvalidSession   = domain.Unauthorized("invalid or expired session")
+	ErrInvalidChallenge = domain.Unauthorized("invalid or expired two-factor challenge")
+)
+
+const (
+	sessionTTL   = 12 * time.Hour
+	challengeTTL = 5 * time.Minute
+	// maxChallengeAttempts bounds guessing of the six-digit code.
+	maxChallengeAttempts = 5
+)
+
+type session struct {
+	memberID int
+	expires  time.Time
+}
+
+type challenge struct {
+	memberID int
+	expires  time.Time
+	attempts int
+}
+
+// LoginResult carries either a session token or, for members with
+// two-factor authentication, a challenge to answer with a code.
+type LoginResult struct {
+	Token     string
+	Challenge string
+}
+
+// AuthUsecase issues opaque bearer tokens to members who log in with
+// their card number and password.
+type AuthUsecase struct {
+	mu         sync.Mutex
+	sessions   map[string]session
+	challenges map[string]challenge
+	members    *MemberUsecase
+	twoFactor  *TwoFactorUsecase
+	guard      *LoginGuardUsecase
+}
+
+func NewAuthUsecase(members *MemberUsecase, twoFactor *TwoFactorUsecase, guard *LoginGuardUsecase) *AuthUsecase {
+	return &AuthUsecase{
+		sessions:   map[string]session{},
+		challenges: map[string]challenge{},
+		members:    members,
+		twoFactor:  twoFactor,
+		guard:      guard,
+	}
+}
+
+// Login checks a card number and password from the given client IP.
+// Repeated failures are throttled by the login guard.
+func (u *AuthUsecase) Login(card, password, ip string) (LoginResult, error) {
+	if err := u.guard.Reserve(card, ip); err != nil {
+		return LoginResult{}, err
+	}
+
+	member, err := u.members.Authenticate(card, password)
+	if err != nil {
+		u.guard.RecordFailure(card, ip)
+		return LoginResult{}, err
+	}
+	u.guard.RecordSuccess(card, ip)
+	return u.SignIn(member.ID)
+}
+
+// SignIn finishes a login once the member passed the first factor: it
+// issues a session, or a challenge for members with two-factor
+// authentication.
+func (u *AuthUsecase) SignIn(memberID int) (LoginResult, error) {
+	if u.twoFactor.Enabled(memberID) {
+		token, err := randomToken()
+		if err != nil {
+			return LoginResult{}, err
+		}
+		u.mu.Lock()
+		u.challenges[token] = challenge{memberID: memberID, expires: time.Now().Add(challengeTTL)}
+		u.mu.Unlock()
+		return LoginResult{Challenge: token}, nil
+	}
+
+	token, err := u.IssueSession(memberID)
+	return LoginResult{Token: token}, err
+}
+
+// VerifyLogin completes a two-factor login by answering the challenge
+// with a TOTP or recovery code, from the given client IP. Wrong codes
+// are throttled by the login guard like wrong passwords. Each answer
+// counts against the challenge before the code is checked, so answers
+// sent at once cannot guess more than maxChallengeAttempts codes.
+func (u *AuthUsecase) VerifyLogin(token, code, ip string) (string, error) {
+	ch, ok := u.challenge(token)
+	if !ok {
+		return "", ErrInvalidChallenge
+	}
+	member, err := u.members.GetMemberByID(ch.memberID)
+	if err != nil {
+		return "", ErrInvalidChallenge
+	}
+	if err := u.guard.Reserve(member.CardNumber, ip); err != nil {
+		return "", err
+	}
+
+	u.mu.Lock()
+	ch, ok = u.challenges[token]
+	if ok {
+		ch.attempts++
+		if ch.attempts >= maxChallengeAttempts {
+			delete(u.challenges, token)
+		} else {
+			u.challenges[token] = ch
+		}
+	}
+	u.mu.Unlock()
+	if !ok {
+		// Another answer used the challenge up meanwhile.
+		u.guard.RecordFailure(member.CardNumber, ip)
+		return "", ErrInvalidChallenge
+	}
+
+	if !u.twoFactor.Verify(ch.memberID, code) {
+		u.guard.RecordFailure(member.CardNumber, ip)
+		return "", ErrInvalidTwoFactorCode
+	}
+	u.guard.RecordSuccess(member.CardNumber, ip)
+	u.mu.Lock()
+	delete(u.challenges, token)
+	u.mu.Unlock()
+	return u.IssueSession(ch.memberID)
+}
+
+// challenge returns an unexpired challenge, dropping an expired one.
+func (u *AuthUsecase) challenge(token string) (challenge, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	ch, ok := u.challenges[token]
+	if !ok || time.Now().After(ch.expires) {
+		delete(u.challenges, token)
+		return challenge{}, false
+	}
+	return ch, true
+}
+
+// IssueSession creates a bearer token for a member whose identity has
+// already been established.
+func (u *AuthUsecase) IssueSession(memberID int) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.sessions[token] = session{memberID: memberID, expires: time.Now().Add(sessionTTL)}
+	return token, nil
+}
+
+// MemberForToken resolves a bearer token to the member it was issued to.
+func (u *AuthUsecase) MemberForToken(token string) (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.sessions[token]
+	if !ok {
+		return 0, ErrInvalidSession
+	}
+	if time.Now().After(s.expires) {
+		delete(u.sessions, token)
+		return 0, ErrInvalidSession
+	}
+	return s.memberID, nil
+}
+
+// Member resolves a bearer token to the full member record.
+func (u *AuthUsecase) Member(token string) (domain.Member, error) {
+	id, err := u.MemberForToken(token)
+	if err != nil {
+		return domain.Member{}, err
+	}
+	return u.members.GetMemberByID(id)
+}
+
+func (u *AuthUsecase) Logout(token string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.sessions, token)
+}
+
+// RevokeSessions logs the member out everywhere.
+func (u *AuthUsecase) RevokeSessions(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for token, s := range u.sessions {
+		if s.memberID == memberID {
+			delete(u.sessions, token)
+		}
+	}
+	for token, ch := range u.challenges {
+		if ch.memberID == memberID {
+			delete(u.challenges, token)
+		}
+	}
+}
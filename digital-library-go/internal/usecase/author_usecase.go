@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrAuthorNotFound = domain.NotFound("author not found")
+	ErrAuthorHasBooks = domain.Conflict("author still has books")
+	ErrUnknownAuthor  = domain.Invalid("author_ids refers to an unknown author")
+	ErrMergeSelf      = domain.Invalid("an author cannot be merged into itself")
+)
+
+// AuthorUsecase manages authors and their many-to-many links to books.
+// The links live on the books; a book's Author string is kept as the
+// display name of its linked authors so existing clients keep working.
+type AuthorUsecase struct {
+	mu      sync.RWMutex
+	authors []domain.Author
+	nextID  int
+	books   *BookUsecase
+	// duplicates is the result of the last ScanDuplicates.
+	duplicates domain.AuthorDuplicates
+}
+
+func NewAuthorUsecase(books *BookUsecase) *AuthorUsecase {
+	return &AuthorUsecase{
+		authors:    []domain.Author{},
+		nextID:     1,
+		books:      books,
+		duplicates: domain.AuthorDuplicates{Groups: []domain.DuplicateGroup{}},
+	}
+}
+
+func (u *AuthorUsecase) GetAuthors() []domain.Author {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Author(nil), u.authors...)
+}
+
+func (u *AuthorUsecase) GetAuthorByID(id int) (domain.Author, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if a, ok := u.find(id); ok {
+		return a, nil
+	}
+	return domain.Author{}, ErrAuthorNotFound
+}
+
+func (u *AuthorUsecase) CreateAuthor(author domain.Author) domain.Author {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.create(author)
+}
+
+// UpdateAuthor replaces the author's details and refreshes the display
+// name of their books.
+func (u *AuthorUsecase) UpdateAuthor(id int, updated domain.Author) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, a := range u.authors {
+		if a.ID == id {
+			updated.ID = id
+			u.authors[i] = updated
+			for _, b := range u.books.BooksByAuthor(id) {
+				u.books.SetAuthors(b.ID, b.AuthorIDs, u.displayName(b.AuthorIDs))
+			}
+			return nil
+		}
+	}
+	return ErrAuthorNotFound
+}
+
+// DeleteAuthor removes an author that no book links to any more.
+func (u *AuthorUsecase) DeleteAuthor(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, a := range u.authors {
+		if a.ID == id {
+			if len(u.books.BooksByAuthor(id)) > 0 {
+				return ErrAuthorHasBooks
+			}
+			u.authors = append(u.authors[:i], u.authors[i+1:]...)
+			return nil
+		}
+	}
+	return ErrAuthorNotFound
+}
+
+// BooksByAuthor returns the published books linked to an author.
+func (u *AuthorUsecase) BooksByAuthor(id int) ([]domain.Book, error) {
+	if _, err := u.GetAuthorByID(id); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(u.books.BooksByAuthor(id), func(b domain.Book) bool { return !b.Published() }), nil
+}
+
+// SaveBook links a book to its authors and saves it with store, holding
+// the lock throughout. Books that name AuthorIDs get their display name
+// from those authors. Books that only carry an author string are linked
+// to the author of that name, who is created if needed and removed again
+// if store fails, so a failed save leaves no orphan author behind.
+func (u *AuthorUsecase) SaveBook(book *domain.Book, store func(domain.Book) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	created, err := u.resolve(book)
+	if err != nil {
+		return err
+	}
+	if err := store(*book); err != nil {
+		if created != 0 {
+			u.authors = slices.DeleteFunc(u.authors, func(a domain.Author) bool { return a.ID == created })
+		}
+		return err
+	}
+	return nil
+}
+
+// LinkBook links a stored book that only has an author string to the
+// author of that name.
+func (u *AuthorUsecase) LinkBook(id int) error {
+	book, err := u.books.GetBookByID(id)
+	if err != nil {
+		return err
+	}
+	if len(book.AuthorIDs) > 0 || book.Author == "" {
+		return nil
+	}
+	return u.SaveBook(&book, func(b domain.Book) error {
+		return u.books.SetAuthors(id, b.AuthorIDs, b.Author)
+	})
+}
+
+// Migrate links every book that still only has an author string and
+// returns how many were linked.
+func (u *AuthorUsecase) Migrate() int {
+	linked := 0
+	for _, b := range u.books.GetBooks() {
+		if len(b.AuthorIDs) > 0 || b.Author == "" {
+			continue
+		}
+		if u.LinkBook(b.ID) == nil {
+			linked++
+		}
+	}
+	return linked
+}
+
+// ScanDuplicates looks for authors whose names probably belong to the
+// same person and keeps the groups found for Duplicates. Every pair of
+// authors is compared, so it runs as a background job rather than per
+// request.
+func (u *AuthorUsecase) ScanDuplicates(now time.Time) domain.AuthorDuplicates {
+	authors := u.GetAuthors()
+	names := make([]domain.AuthorName, len(authors))
+	for i, a := range authors {
+		names[i] = domain.ParseAuthorName(a.Name)
+	}
+
+	// Union-find over similar pairs, so chains of similar names end up
+	// in one group.
+	parent := make([]int, len(authors))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range authors {
+		for j := i + 1; j < len(authors); j++ {
+			if names[i].Similar(names[j]) {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	books := map[int]int{}
+	for _, b := range u.books.GetBooks() {
+		for _, id := range b.AuthorIDs {
+			books[id]++
+		}
+	}
+	members := map[int][]int{}
+	for i := range authors {
+		members[root(i)] = append(members[root(i)], i)
+	}
+	result := domain.AuthorDuplicates{ScannedAt: &now, Groups: []domain.DuplicateGroup{}}
+	for i := range authors {
+		group := members[i]
+		if len(group) < 2 {
+			continue
+		}
+		g := domain.DuplicateGroup{Reason: domain.DuplicateSameName}
+		target := group[0]
+		for _, m := range group {
+			g.Authors = append(g.Authors, authors[m])
+			if names[m].Key() != names[group[0]].Key() {
+				g.Reason = domain.DuplicateSimilarName
+			}
+			if books[authors[m].ID] > books[authors[target].ID] {
+				target = m
+			}
+		}
+		g.TargetID = authors[target].ID
+		result.Groups = append(result.Groups, g)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.duplicates = result
+	return result
+}
+
+// Duplicates returns the groups found by the last scan, less authors
+// merged or deleted since.
+func (u *AuthorUsecase) Duplicates() domain.AuthorDuplicates {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	result := domain.AuthorDuplicates{ScannedAt: u.duplicates.ScannedAt, Groups: []domain.DuplicateGroup{}}
+	for _, g := range u.duplicates.Groups {
+		authors := slices.DeleteFunc(slices.Clone(g.Authors), func(a domain.Author) bool {
+			_, ok := u.find(a.ID)
+			return !ok
+		})
+		if len(authors) < 2 {
+			continue
+		}
+		if !slices.ContainsFunc(authors, func(a domain.Author) bool { return a.ID == g.TargetID }) {
+			g.TargetID = authors[0].ID
+		}
+		g.Authors = authors
+		result.Groups = append(result.Groups, g)
+	}
+	return result
+}
+
+// Merge folds the given authors into the target: their books are linked
+// to the target instead, with display names refreshed, and the merged
+// authors are deleted.
+func (u *AuthorUsecase) Merge(targetID int, ids []int) (domain.Author, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	target, ok := u.find(targetID)
+	if !ok {
+		return domain.Author{}, ErrAuthorNotFound
+	}
+	for _, id := range ids {
+		if id == targetID {
+			return domain.Author{}, ErrMergeSelf
+		}
+		if _, ok := u.find(id); !ok {
+			return domain.Author{}, fmt.Errorf("%w: %d", ErrUnknownAuthor, id)
+		}
+	}
+
+	for _, id := range ids {
+		for _, b := range u.books.BooksByAuthor(id) {
+			linked := []int{}
+			for _, a := range b.AuthorIDs {
+				if slices.Contains(ids, a) {
+					a = targetID
+				}
+				if !slices.Contains(linked, a) {
+					linked = append(linked, a)
+				}
+			}
+			u.books.SetAuthors(b.ID, linked, u.displayName(linked))
+		}
+	}
+	u.authors = slices.DeleteFunc(u.authors, func(a domain.Author) bool { return slices.Contains(ids, a.ID) })
+	return target, nil
+}
+
+// The helpers below expect the caller to hold the lock.
+
+func (u *AuthorUsecase) find(id int) (domain.Author, bool) {
+	for _, a := range u.authors {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return domain.Author{}, false
+}
+
+// resolve fills in the book's authors for SaveBook and returns the ID of
+// the author it created, or 0. It expects the caller to hold the lock.
+func (u *AuthorUsecase) resolve(book *domain.Book) (int, error) {
+	if len(book.AuthorIDs) > 0 {
+		for _, id := range book.AuthorIDs {
+			if _, ok := u.find(id); !ok {
+				return 0, ErrUnknownAuthor
+			}
+		}
+		book.Author = u.displayName(book.AuthorIDs)
+		return 0, nil
+	}
+
+	name := strings.TrimSpace(book.Author)
+	if name == "" {
+		book.AuthorIDs = nil
+		return 0, nil
+	}
+	for _, a := range u.authors {
+		if strings.EqualFold(a.Name, name) {
+			book.AuthorIDs = []int{a.ID}
+			return 0, nil
+		}
+	}
+	a := u.create(domain.Author{Name: name})
+	book.AuthorIDs = []int{a.ID}
+	return a.ID, nil
+}
+
+func (u *AuthorUsecase) create(author domain.Author) domain.Author {
+	author.ID = u.nextID
+	u.nextID++
+	u.authors = append(u.authors, author)
+	return author
+}
+
+func (u *AuthorUsecase) displayName(ids []int) string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := u.find(id); ok {
+			names = append(names, a.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
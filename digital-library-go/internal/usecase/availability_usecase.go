@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// AvailabilityUsecase predicts when a book will be free to borrow from
+// its current loan, the hold queue and how past loans went.
+type AvailabilityUsecase struct {
+	books    *BookUsecase
+	branches *BranchUsecase
+	copies   *CopyUsecase
+	loans    *LoanUsecase
+	holds    *HoldUsecase
+}
+
+func NewAvailabilityUsecase(books *BookUsecase, branches *BranchUsecase, copies *CopyUsecase, loans *LoanUsecase, holds *HoldUsecase) *AvailabilityUsecase {
+	return &AvailabilityUsecase{books: books, branches: branches, copies: copies, loans: loans, holds: holds}
+}
+
+// loanHistory summarizes returned loans.
+type loanHistory struct {
+	// duration is the average time a book was kept.
+	duration time.Duration
+	// lateRate is the share of loans returned after their due date, and
+	// lateness how long after it they came back on average.
+	lateRate float64
+	lateness time.Duration
+	// renewals is the average number of times a loan was renewed.
+	renewals float64
+}
+
+// Predict says whether a book can be borrowed now and, if not, when a
+// member joining its hold queue can expect it. The current loan is
+// expected back on its due date, pushed back by the average lateness
+// weighted by how often loans come back late. While nobody holds the
+// book its borrower may still renew it, so the due date is first pushed
+// back by the renewals past loans averaged, up to those left; a hold
+// stops further renewals. Each hold ahead then keeps the book for an
+// average loan. Without returned loans to learn from, loans are expected
+// back on time and last as long as the current one.
+// Books on the shelf without a ready or in transit hold are available,
+// even if members have holds on them; with one they are on the hold
+// shelf. A book whose copies have all been withdrawn is not
+// expected back at all.
+func (u *AvailabilityUsecase) Predict(bookID int, now time.Time) (domain.Availability, error) {
+	copies, err := u.copies.CopiesForBook(bookID)
+	if err != nil {
+		return domain.Availability{}, err
+	}
+	if len(copies) > 0 && !slices.ContainsFunc(copies, func(c domain.Copy) bool { return !c.Withdrawn() }) {
+		return domain.Availability{BookID: bookID, Status: domain.AvailabilityWithdrawn}, nil
+	}
+
+	var current *domain.Loan
+	returned := []domain.Loan{}
+	for _, l := range u.loans.GetLoans() {
+		switch {
+		case l.Active() && l.BookID == bookID:
+			current = &l
+		case !l.Active():
+			returned = append(returned, l)
+		}
+	}
+	queue, ready := 0, false
+	for _, h := range u.holds.GetHolds() {
+		if h.BookID == bookID {
+			queue++
+			ready = ready || h.Promoted()
+		}
+	}
+
+	history := summarize(returned)
+	availability := domain.Availability{
+		BookID:         bookID,
+		Status:         domain.AvailabilityAvailable,
+		HoldQueue:      queue,
+		LateReturnRate: history.lateRate,
+	}
+	if current == nil && !ready {
+		return availability, nil
+	}
+
+	loanLength := history.duration
+	free := now
+	switch {
+	case current != nil:
+		availability.Status = domain.AvailabilityOnLoan
+		availability.DueAt = &current.DueAt
+		if loanLength == 0 {
+			loanLength = current.DueAt.Sub(current.LoanedAt)
+		}
+		due := current.DueAt
+		if queue == 0 && current.CourseID == 0 && !due.Before(now) {
+			// Each renewal lends the book for another loan period, about
+			// as long as the loan's periods so far.
+			left := float64(domain.MaxRenewals - current.Renewals)
+			renewals := min(max(history.renewals-float64(current.Renewals), 0), left)
+			period := due.Sub(current.LoanedAt) / time.Duration(current.Renewals+1)
+			availability.ExpectedRenewals = math.Round(renewals*100) / 100
+			due = due.Add(time.Duration(renewals * float64(period)))
+		}
+		free = due.Add(time.Duration(history.lateRate * float64(history.lateness)))
+		if current.DueAt.Before(now) {
+			// Already late: expect the usual lateness from the due date.
+			free = current.DueAt.Add(history.lateness)
+		}
+		free = later(free, now)
+	case ready:
+		availability.Status = domain.AvailabilityHoldShelf
+		if loanLength == 0 {
+			// Nothing to tell how long the member will keep it.
+			return availability, nil
+		}
+	}
+	free = free.Add(time.Duration(queue) * loanLength)
+	availability.ExpectedAvailableAt = &free
+	return availability, nil
+}
+
+// NearbyBranches returns the branches within radius kilometres of a
+// point, nearest first. When bookID is set each comes with whether the
+// book can be borrowed there: a branch holding copies of it in
+// circulation shares the book's availability, as loans and holds are
+// for the book rather than one of its copies.
+func (u *AvailabilityUsecase) NearbyBranches(lat, lng, radius float64, bookID int, now time.Time) ([]domain.NearbyBranch, error) {
+	nearby := u.branches.Nearby(lat, lng, radius)
+	if bookID == 0 {
+		return nearby, nil
+	}
+	copies, availability, err := u.branchInputs(bookID, now)
+	if err != nil {
+		return nil, err
+	}
+	for i, b := range nearby {
+		at := atBranch(bookID, b.ID, copies, availability.Status)
+		nearby[i].Availability = &at
+	}
+	return nearby, nil
+}
+
+// ByBranch says for every branch how many copies of a book it has in
+// circulation and how many are on their way there, so a member can
+// choose where to pick up a hold. It gives no status per branch: loans
+// are for the book, so which branch's copy is out is not known.
+func (u *AvailabilityUsecase) ByBranch(bookID int) ([]domain.AvailabilityAtBranch, error) {
+	copies, err := u.copies.CopiesForBook(bookID)
+	if err != nil {
+		return nil, err
+	}
+	branches := u.branches.GetBranches()
+	result := make([]domain.AvailabilityAtBranch, len(branches))
+	for i, b := range branches {
+		held, inTransit := countAtBranch(b.ID, copies)
+		result[i] = domain.AvailabilityAtBranch{Branch: b, BookID: bookID, Copies: held, InTransit: inTransit}
+	}
+	return result, nil
+}
+
+// branchInputs returns the copies of a book and its availability, which
+// the nearby branches' availability is worked out from.
+func (u *AvailabilityUsecase) branchInputs(bookID int, now time.Time) ([]domain.Copy, domain.Availability, error) {
+	availability, err := u.Predict(bookID, now)
+	if err != nil {
+		return nil, domain.Availability{}, err
+	}
+	copies, err := u.copies.CopiesForBook(bookID)
+	if err != nil {
+		return nil, domain.Availability{}, err
+	}
+	return copies, availability, nil
+}
+
+// atBranch is the availability of a book at a branch, which shares the
+// book's status when it holds copies of it.
+func atBranch(bookID, branchID int, copies []domain.Copy, status string) domain.BranchAvailability {
+	at := domain.BranchAvailability{BookID: bookID, Status: domain.AvailabilityNotHeld}
+	at.Copies, at.InTransit = countAtBranch(branchID, copies)
+	switch {
+	case at.Copies > 0:
+		at.Status = status
+	case at.InTransit > 0:
+		at.Status = domain.AvailabilityInTransit
+	}
+	return at
+}
+
+// countAtBranch counts the copies in circulation at a branch and those
+// on their way there.
+func countAtBranch(branchID int, copies []domain.Copy) (held, inTransit int) {
+	for _, c := range copies {
+		switch {
+		case c.Withdrawn():
+		case c.InTransit():
+			if c.Transfer.ToBranchID == branchID {
+				inTransit++
+			}
+		case c.BranchID == branchID:
+			held++
+		}
+	}
+	return held, inTransit
+}
+
+func summarize(returned []domain.Loan) loanHistory {
+	if len(returned) == 0 {
+		return loanHistory{}
+	}
+	var kept, lateness time.Duration
+	late, renewals := 0, 0
+	for _, l := range returned {
+		kept += l.ReturnedAt.Sub(l.LoanedAt)
+		renewals += l.Renewals
+		if l.ReturnedAt.After(l.DueAt) {
+			late++
+			lateness += l.ReturnedAt.Sub(l.DueAt)
+		}
+	}
+	history := loanHistory{
+		duration: kept / time.Duration(len(returned)),
+		lateRate: float64(late) / float64(len(returned)),
+		renewals: float64(renewals) / float64(len(returned)),
+	}
+	if late > 0 {
+		history.lateness = lateness / time.Duration(late)
+	}
+	return history
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// BookCache is a read-through cache for single-book lookups. Identical
+// lookups that miss at the same time share one call to the catalog, and
+// any write to the catalog empties the cache, so it never serves a stale
+// book.
+type BookCache struct {
+	books *BookUsecase
+	group singleflight.Group
+
+	mu      sync.RWMutex
+	entries map[int]domain.Book
+	version uint64
+
+	hits, misses atomic.Int64
+}
+
+func NewBookCache(books *BookUsecase) *BookCache {
+	return &BookCache{books: books, entries: map[int]domain.Book{}, version: books.Version()}
+}
+
+// GetBookByID answers from the cache, or looks the book up once for all
+// concurrent callers. Missing books are not cached, so probing unknown
+// ids cannot grow the cache.
+func (c *BookCache) GetBookByID(id int) (domain.Book, error) {
+	version := c.books.Version()
+	c.mu.RLock()
+	book, ok := c.entries[id]
+	fresh := c.version == version
+	c.mu.RUnlock()
+	if ok && fresh {
+		c.hits.Add(1)
+		return book, nil
+	}
+	c.misses.Add(1)
+
+	// The version is part of the key so a lookup started before a write
+	// is not shared with callers that arrive after it.
+	key := strconv.Itoa(id) + "@" + strconv.FormatUint(version, 10)
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		book, err := c.books.GetBookByID(id)
+		if err != nil {
+			return nil, err
+		}
+		c.store(version, book)
+		return book, nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	return v.(domain.Book), nil
+}
+
+// store caches book as read at version, dropping entries from older
+// versions. A book read before a newer write is discarded, since the
+// write may have changed it.
+func (c *BookCache) store(version uint64, book domain.Book) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version < c.version {
+		return
+	}
+	if version > c.version {
+		c.entries = map[int]domain.Book{}
+		c.version = version
+	}
+	c.entries[book.ID] = book
+}
+
+// Stats reports how many books the cache holds and how many lookups it
+// answered or missed.
+func (c *BookCache) Stats() domain.CacheStats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+	s := domain.CacheStats{Entries: entries, Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRatio = round3(float64(s.Hits) / float64(total))
+	}
+	return s
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// BookNotifier sends the notification for each new book on a fixed number
+// of workers, from a queue of bounded size. Overflow says what happens to
+// a notification that finds the queue full.
+type BookNotifier struct {
+	queue    chan domain.Book
+	workers  int
+	overflow string
+	wg       sync.WaitGroup
+
+	// mu keeps Notify from queueing once Drain has closed the queue.
+	mu     sync.RWMutex
+	closed bool
+
+	busy, queued, sent, dropped atomic.Int64
+}
+
+func NewBookNotifier(workers, size int, overflow string) *BookNotifier {
+	n := &BookNotifier{queue: make(chan domain.Book, size), workers: workers, overflow: overflow}
+	n.wg.Add(workers)
+	for range workers {
+		go n.work()
+	}
+	return n
+}
+
+// Notify queues the notification for a new book. It reports whether the
+// book was queued; notifications that are not are logged. With
+// OverflowBlock it waits for room until ctx is done.
+func (n *BookNotifier) Notify(ctx context.Context, b domain.Book) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		n.drop(b, "shutting down")
+		return false
+	}
+
+	for {
+		select {
+		case n.queue <- b:
+			n.queued.Add(1)
+			return true
+		default:
+		}
+
+		switch n.overflow {
+		case domain.OverflowDropOldest:
+			select {
+			case old := <-n.queue:
+				n.drop(old, "queue full")
+			default:
+			}
+		case domain.OverflowBlock:
+			select {
+			case n.queue <- b:
+				n.queued.Add(1)
+				return true
+			case <-ctx.Done():
+				n.drop(b, "queue full")
+				return false
+			}
+		default:
+			n.drop(b, "queue full")
+			return false
+		}
+	}
+}
+
+// Drain stops taking notifications and waits until the queued ones are
+// sent, or until ctx is done.
+func (n *BookNotifier) Drain(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *BookNotifier) Stats() domain.NotificationQueueStats {
+	return domain.NotificationQueueStats{
+		Workers:  n.workers,
+		Capacity: cap(n.queue),
+		Overflow: n.overflow,
+		Depth:    len(n.queue),
+		Busy:     n.busy.Load(),
+		Queued:   n.queued.Load(),
+		Sent:     n.sent.Load(),
+		Dropped:  n.dropped.Load(),
+	}
+}
+
+func (n *BookNotifier) work() {
+	defer n.wg.Done()
+	for b := range n.queue {
+		n.busy.Add(1)
+		sendNewBookNotification(b)
+		n.busy.Add(-1)
+		n.sent.Add(1)
+	}
+}
+
+func (n *BookNotifier) drop(b domain.Book, reason string) {
+	n.dropped.Add(1)
+	log.Printf("Notification dropped for new book: %s (%s)", b.Title, reason)
+}
+
+func sendNewBookNotification(b domain.Book) {
+	time.Sleep(2 * time.Second)
+	log.Println("Notification sent for new book:", b.Title)
+}
@@ -1,70 +1,565 @@
 package usecase
 
 import (
-	"errors"
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
 )
 
+// bookChangeCapacity bounds the change log; the oldest changes are
+// dropped first, and cursors from before them expire.
+const bookChangeCapacity = 100000
+
+var (
+	ErrBookNotFound = domain.NotFound("book not found")
+	// ErrRevisionChanged means a book was written to since the revision a
+	// conditional write was based on.
+	ErrRevisionChanged = domain.Conflict("book changed since the given revision")
+	ErrInvalidCursor   = domain.Invalid("invalid sync cursor")
+	// ErrCursorExpired means a cursor is older than the change log or was
+	// issued before the server restarted. The client has to fetch the
+	// whole catalog again.
+	ErrCursorExpired = domain.Gone("sync cursor expired, fetch the catalog again")
+)
+
 type BookUsecase struct {
-	books []domain.Book
+	mu sync.RWMutex
+	// books holds the catalog by ID. order lists the IDs in the order
+	// they were added; an entry is live only while slot maps its ID back
+	// to it, so deleting a book leaves a hole instead of shifting the
+	// rest. isbns indexes the books by ISBN-13.
+	books map[int]domain.Book
+	order []int
+	slot  map[int]int
+	holes int
+	isbns map[string][]int
+	// maxID is the highest ID in the catalog, valid unless maxStale.
+	maxID    int
+	maxStale bool
+	// version counts writes so derived indexes know when to rebuild.
+	version uint64
+	// changes logs creates, updates and deletes for incremental sync.
+	// Cursors carry the epoch, so those from before a restart expire.
+	changes []domain.BookChange
+	seq     uint64
+	epoch   string
 }
 
 func NewBookUsecase() *BookUsecase {
 	return &BookUsecase{
-		books: []domain.Book{},
+		books: map[int]domain.Book{},
+		slot:  map[int]int{},
+		isbns: map[string][]int{},
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
 	}
 }
 
 func (u *BookUsecase) GetBooks() []domain.Book {
-	return u.books
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	books := make([]domain.Book, 0, len(u.books))
+	u.each(func(b domain.Book) { books = append(books, b) })
+	return books
 }
 
 func (u *BookUsecase) GetBookByID(id int) (domain.Book, error) {
-	for _, b := range u.books {
-		if b.ID == id {
-			return b, nil
-		}
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if b, ok := u.books[id]; ok {
+		return b, nil
+	}
+	return domain.Book{}, ErrBookNotFound
+}
+
+// BookByISBN finds a book by its ISBN, in either its 10 or 13 digit
+// form. Of several books with the same ISBN, the one that has had it
+// longest is returned.
+func (u *BookUsecase) BookByISBN(isbn string) (domain.Book, bool) {
+	key, ok := domain.ISBN13(isbn)
+	if !ok {
+		return domain.Book{}, false
 	}
-	return domain.Book{}, errors.New("book not found")
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if ids := u.isbns[key]; len(ids) > 0 {
+		return u.books[ids[0]], true
+	}
+	return domain.Book{}, false
 }
 
 func (u *BookUsecase) isDuplicateID(id int) bool {
-	for _, b := range u.books {
-		if b.ID == id {
-			return true
+	_, ok := u.books[id]
+	return ok
+}
+
+func (u *BookUsecase) CreateBook(book domain.Book) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+
+	if u.isDuplicateID(book.ID) {
+		return domain.Conflict("book with this ID already exists")
+	}
+	u.add(book)
+	return nil
+}
+
+// CreateBookWithNextID creates a book numbered after the highest ID in
+// the catalog and returns its ID.
+func (u *BookUsecase) CreateBookWithNextID(book domain.Book) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	if u.maxStale {
+		u.maxID = 0
+		for id := range u.books {
+			u.maxID = max(u.maxID, id)
 		}
+		u.maxStale = false
 	}
-	return false
+	book.ID = max(1, u.maxID+1)
+	u.add(book)
+	return book.ID
 }
 
-func (u *BookUsecase) CreateBook(book domain.Book) error {
+// add sets the server-managed fields of a new book and stores it. It
+// expects the caller to hold the lock.
+func (u *BookUsecase) add(book domain.Book) {
+	book.AddedAt = time.Now()
+	book.Featured = false
+	if book.Status == "" {
+		book.Status = domain.BookPublished
+	}
+	book.Language = domain.NormalizeLanguage(book.Language)
+	if len(u.books) == 0 || book.ID > u.maxID {
+		u.maxID = book.ID
+	}
+	u.books[book.ID] = book
+	u.slot[book.ID] = len(u.order)
+	u.order = append(u.order, book.ID)
+	u.indexISBN(book)
+	u.record(book.ID, domain.BookCreated)
+}
+
+func (u *BookUsecase) UpdateBook(id int, updated domain.Book) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	return u.update(id, updated)
+}
+
+// UpdateBookAt updates a book only if it is still at revision.
+func (u *BookUsecase) UpdateBookAt(id int, revision uint64, updated domain.Book) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if b, ok := u.books[id]; ok && b.Revision != revision {
+		return ErrRevisionChanged
+	}
+	u.version++
+	return u.update(id, updated)
+}
 
+// UpsertBook replaces the book with book.ID, or creates it if there is
+// none, and reports whether it was created.
+func (u *BookUsecase) UpsertBook(book domain.Book) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
 	if u.isDuplicateID(book.ID) {
-		return errors.New("book with this ID already exists")
+		u.update(book.ID, book)
+		return false
 	}
+	u.add(book)
+	return true
+}
 
-	u.books = append(u.books, book)
+// update replaces a book, keeping its server-managed fields. The status
+// is one of them: it only changes through SetStatus, which checks the
+// book is complete enough for it. It expects the caller to hold the
+// lock.
+func (u *BookUsecase) update(id int, updated domain.Book) error {
+	b, ok := u.books[id]
+	if !ok {
+		return ErrBookNotFound
+	}
+	updated.ID = id
+	updated.AddedAt = b.AddedAt
+	updated.Featured = b.Featured
+	updated.Status = b.Status
+	updated.Language = domain.NormalizeLanguage(updated.Language)
+	u.books[id] = updated
+	if updated.ISBN != b.ISBN {
+		u.unindexISBN(b)
+		u.indexISBN(updated)
+	}
+	u.record(id, domain.BookUpdated)
 	return nil
 }
 
-func (u *BookUsecase) UpdateBook(id int, updated domain.Book) error {
-	for i, b := range u.books {
-		if b.ID == id {
-			updated.ID = id
-			u.books[i] = updated
-			return nil
+// MigrateLanguage fills in the language of books saved before it was a
+// field of its own: from a "language" custom field where that holds a
+// valid code, else with defaultLanguage if one is given.
+func (u *BookUsecase) MigrateLanguage(defaultLanguage string) domain.LanguageMigration {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	result := domain.LanguageMigration{Unset: []int{}}
+	u.each(func(b domain.Book) {
+		if b.Language != "" {
+			return
+		}
+		if v, ok := b.Attributes[domain.FacetLanguage].(string); ok && domain.ValidLanguage(v) {
+			b.Language = domain.NormalizeLanguage(v)
+			u.books[b.ID] = b
+			u.record(b.ID, domain.BookUpdated)
+			result.FromAttributes++
+			return
+		}
+		if defaultLanguage != "" {
+			b.Language = domain.NormalizeLanguage(defaultLanguage)
+			u.books[b.ID] = b
+			u.record(b.ID, domain.BookUpdated)
+			result.Defaulted++
+			return
+		}
+		result.Unset = append(result.Unset, b.ID)
+	})
+	return result
+}
+
+// FillMissing copies fields from external metadata into the book where
+// they are still empty, leaving anything already set untouched. It
+// returns the names of the fields it filled.
+func (u *BookUsecase) FillMissing(id int, meta domain.BookMetadata) ([]string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	b, ok := u.books[id]
+	if !ok {
+		return nil, ErrBookNotFound
+	}
+	filled := []string{}
+	if b.Title == "" && meta.Title != "" {
+		b.Title = meta.Title
+		filled = append(filled, "title")
+	}
+	if b.Author == "" && meta.Author != "" {
+		b.Author = meta.Author
+		filled = append(filled, "author")
+	}
+	if b.Year == 0 && meta.Year != 0 {
+		b.Year = meta.Year
+		filled = append(filled, "year")
+	}
+	if len(filled) > 0 {
+		u.books[id] = b
+		u.record(id, domain.BookUpdated)
+	}
+	return filled, nil
+}
+
+// SetAuthors links the book to Author records and sets its display name.
+func (u *BookUsecase) SetAuthors(id int, authorIDs []int, display string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	b, ok := u.books[id]
+	if !ok {
+		return ErrBookNotFound
+	}
+	b.AuthorIDs = slices.Clone(authorIDs)
+	b.Author = display
+	u.books[id] = b
+	u.record(id, domain.BookUpdated)
+	return nil
+}
+
+func (u *BookUsecase) SetFeatured(id int, featured bool) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	b, ok := u.books[id]
+	if !ok {
+		return ErrBookNotFound
+	}
+	b.Featured = featured
+	u.books[id] = b
+	u.record(id, domain.BookUpdated)
+	return nil
+}
+
+// SetStatus moves a book between draft, published and withdrawn. A book
+// is only published if it is complete enough to pass validation. A
+// draft enters the catalog, for the new-arrival shelf, when published.
+func (u *BookUsecase) SetStatus(id int, status string) (domain.Book, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	b, ok := u.books[id]
+	if !ok {
+		return domain.Book{}, ErrBookNotFound
+	}
+	previous := b.Status
+	b.Status = status
+	if err := b.Validate(); err != nil {
+		return domain.Book{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.version++
+	if previous == domain.BookDraft && status == domain.BookPublished {
+		b.AddedAt = time.Now()
+	}
+	u.books[id] = b
+	u.record(id, domain.BookUpdated)
+	return u.books[id], nil
+}
+
+// BooksWithStatus returns the books with the given status, in the order
+// they were added, for staff working through the cataloging queue.
+func (u *BookUsecase) BooksWithStatus(status string) []domain.Book {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	books := []domain.Book{}
+	u.each(func(b domain.Book) {
+		if b.Status == status {
+			books = append(books, b)
+		}
+	})
+	return books
+}
+
+// NewArrivals returns the featured books followed by the books added
+// since the given time, each group newest first.
+func (u *BookUsecase) NewArrivals(since time.Time) []domain.Book {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	featured, recent := []domain.Book{}, []domain.Book{}
+	u.each(func(b domain.Book) {
+		switch {
+		case b.Featured:
+			featured = append(featured, b)
+		case !b.AddedAt.Before(since):
+			recent = append(recent, b)
+		}
+	})
+	newestFirst := func(a, b domain.Book) int { return b.AddedAt.Compare(a.AddedAt) }
+	slices.SortStableFunc(featured, newestFirst)
+	slices.SortStableFunc(recent, newestFirst)
+	return append(featured, recent...)
+}
+
+// BooksByAuthor returns the books linked to an Author record.
+func (u *BookUsecase) BooksByAuthor(authorID int) []domain.Book {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	books := []domain.Book{}
+	u.each(func(b domain.Book) {
+		if slices.Contains(b.AuthorIDs, authorID) {
+			books = append(books, b)
+		}
+	})
+	return books
+}
+
+// Version changes whenever the catalog is written to.
+func (u *BookUsecase) Version() uint64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.version
+}
+
+// RemoveAttribute drops a custom field's value from every book.
+func (u *BookUsecase) RemoveAttribute(name string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	for id, b := range u.books {
+		if _, ok := b.Attributes[name]; ok {
+			attrs := maps.Clone(b.Attributes)
+			delete(attrs, name)
+			b.Attributes = attrs
+			u.books[id] = b
+			u.record(id, domain.BookUpdated)
 		}
 	}
-	return errors.New("book not found")
 }
 
 func (u *BookUsecase) DeleteBook(id int) error {
-	for i, b := range u.books {
-		if b.ID == id {
-			u.books = append(u.books[:i], u.books[i+1:]...)
-			return nil
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.version++
+	return u.delete(id)
+}
+
+// DeleteBookAt deletes a book only if it is still at revision.
+func (u *BookUsecase) DeleteBookAt(id int, revision uint64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if b, ok := u.books[id]; ok && b.Revision != revision {
+		return ErrRevisionChanged
+	}
+	u.version++
+	return u.delete(id)
+}
+
+// delete expects the caller to hold the lock.
+func (u *BookUsecase) delete(id int) error {
+	b, ok := u.books[id]
+	if !ok {
+		return ErrBookNotFound
+	}
+	delete(u.books, id)
+	delete(u.slot, id)
+	u.unindexISBN(b)
+	u.record(id, domain.BookDeleted)
+	u.holes++
+	if id == u.maxID {
+		u.maxStale = true
+	}
+	// Compact once holes make up half the order, so listing stays
+	// proportional to the number of books.
+	if u.holes > len(u.order)/2 {
+		order := make([]int, 0, len(u.books))
+		u.each(func(b domain.Book) {
+			u.slot[b.ID] = len(order)
+			order = append(order, b.ID)
+		})
+		u.order, u.holes = order, 0
+	}
+	return nil
+}
+
+// Changes returns what changed after a cursor. Without a cursor it
+// returns the whole catalog as created.
+func (u *BookUsecase) Changes(cursor string) (domain.BookChanges, error) {
+	var since uint64
+	epoch := u.epoch
+	if cursor != "" {
+		var seq string
+		var ok bool
+		epoch, seq, ok = strings.Cut(cursor, ".")
+		n, err := strconv.ParseUint(seq, 10, 64)
+		if !ok || err != nil {
+			return domain.BookChanges{}, ErrInvalidCursor
 		}
+		since = n
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	result := domain.BookChanges{
+		Created: []domain.Book{},
+		Updated: []domain.Book{},
+		Deleted: []int{},
+		Cursor:  u.epoch + "." + strconv.FormatUint(u.seq, 10),
+	}
+	if cursor == "" {
+		u.each(func(b domain.Book) { result.Created = append(result.Created, b) })
+		return result, nil
+	}
+	if epoch != u.epoch || since > u.seq || (len(u.changes) > 0 && since < u.changes[0].Seq-1) {
+		return domain.BookChanges{}, ErrCursorExpired
+	}
+
+	// A book created and deleted since the cursor is left out, and one
+	// deleted and created again is an update to the client.
+	start, _ := slices.BinarySearchFunc(u.changes, since+1, func(c domain.BookChange, seq uint64) int {
+		return cmp.Compare(c.Seq, seq)
+	})
+	first, last := map[int]string{}, map[int]string{}
+	ids := []int{}
+	for _, c := range u.changes[start:] {
+		if _, ok := first[c.BookID]; !ok {
+			first[c.BookID] = c.Kind
+			ids = append(ids, c.BookID)
+		}
+		last[c.BookID] = c.Kind
+	}
+	for _, id := range ids {
+		switch {
+		case last[id] == domain.BookDeleted:
+			if first[id] != domain.BookCreated {
+				result.Deleted = append(result.Deleted, id)
+			}
+		case first[id] == domain.BookCreated:
+			result.Created = append(result.Created, u.books[id])
+		default:
+			result.Updated = append(result.Updated, u.books[id])
+		}
+	}
+	return result, nil
+}
+
+// ChangesAfter returns up to limit change log entries after seq, oldest
+// first. It returns ErrCursorExpired if entries after seq have already
+// been dropped from the log.
+func (u *BookUsecase) ChangesAfter(seq uint64, limit int) ([]domain.BookChange, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if len(u.changes) > 0 && seq < u.changes[0].Seq-1 {
+		return nil, ErrCursorExpired
+	}
+	start, _ := slices.BinarySearchFunc(u.changes, seq+1, func(c domain.BookChange, seq uint64) int {
+		return cmp.Compare(c.Seq, seq)
+	})
+	return slices.Clone(u.changes[start:min(start+limit, len(u.changes))]), nil
+}
+
+// Epoch identifies this run of the server. Change sequence numbers are
+// only unique together with it.
+func (u *BookUsecase) Epoch() string {
+	return u.epoch
+}
+
+// record appends a change to the log and, unless the book was deleted,
+// stamps it with the change's revision. It expects the caller to hold
+// the lock.
+func (u *BookUsecase) record(id int, kind string) {
+	u.seq++
+	now := time.Now()
+	if b, ok := u.books[id]; ok && kind != domain.BookDeleted {
+		b.Revision, b.UpdatedAt = u.seq, now
+		u.books[id] = b
+	}
+	u.changes = append(u.changes, domain.BookChange{Seq: u.seq, BookID: id, Kind: kind, At: now})
+	if len(u.changes) > bookChangeCapacity {
+		u.changes = u.changes[len(u.changes)-bookChangeCapacity:]
+	}
+}
+
+// each calls fn for every book in the order they were added. It expects
+// the caller to hold the lock.
+func (u *BookUsecase) each(fn func(domain.Book)) {
+	for i, id := range u.order {
+		if u.slot[id] == i {
+			if b, ok := u.books[id]; ok {
+				fn(b)
+			}
+		}
+	}
+}
+
+// indexISBN and unindexISBN keep the ISBN index in step with a book's
+// ISBN. They expect the caller to hold the lock.
+func (u *BookUsecase) indexISBN(b domain.Book) {
+	if key, ok := domain.ISBN13(b.ISBN); ok {
+		u.isbns[key] = append(u.isbns[key], b.ID)
+	}
+}
+
+func (u *BookUsecase) unindexISBN(b domain.Book) {
+	key, ok := domain.ISBN13(b.ISBN)
+	if !ok {
+		return
+	}
+	ids := slices.DeleteFunc(u.isbns[key], func(id int) bool { return id == b.ID })
+	if len(ids) == 0 {
+		delete(u.isbns, key)
+	} else {
+		u.isbns[key] = ids
 	}
-	return errors.New("book not found")
 }
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// benchmarkBooks is the size of the catalog the benchmarks run against.
+const benchmarkBooks = 1_000_000
+
+func newBenchmarkCatalog(b *testing.B) *BookUsecase {
+	b.Helper()
+	u := NewBookUsecase()
+	for id := 1; id <= benchmarkBooks; id++ {
+		if err := u.CreateBook(domain.Book{ID: id, Title: "Book", Author: "Author", Year: 2000}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return u
+}
+
+func BenchmarkGetBookByID(b *testing.B) {
+	u := newBenchmarkCatalog(b)
+	for i := 0; b.Loop(); i++ {
+		if _, err := u.GetBookByID(i%benchmarkBooks + 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIsDuplicateID(b *testing.B) {
+	u := newBenchmarkCatalog(b)
+	for i := 0; b.Loop(); i++ {
+		if !u.isDuplicateID(i%benchmarkBooks + 1) {
+			b.Fatal("book not found")
+		}
+	}
+}
+
+// BenchmarkDeleteBook deletes books in turn, creating each again outside
+// the timer so the catalog stays at its size.
+func BenchmarkDeleteBook(b *testing.B) {
+	u := newBenchmarkCatalog(b)
+	for i := 0; b.Loop(); i++ {
+		id := i%benchmarkBooks + 1
+		if err := u.DeleteBook(id); err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		if err := u.CreateBook(domain.Book{ID: id, Title: "Book", Author: "Author", Year: 2000}); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+	}
+}
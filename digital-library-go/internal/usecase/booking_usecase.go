@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrBookingConflict     = domain.Conflict("the resource is already booked at that time")
+	ErrBookingClosed       = domain.Invalid("bookings must fall within the library's opening hours on one day")
+	ErrBookingInPast       = domain.Invalid("bookings must start in the future")
+	ErrBookingCancelled    = domain.Conflict("booking is already cancelled")
+	ErrResourceHasBookings = domain.Conflict("resource still has upcoming bookings")
+)
+
+// BookingUsecase manages bookable rooms and equipment and members'
+// bookings of them. Members are notified when a booking is confirmed or
+// cancelled.
+type BookingUsecase struct {
+	mu           sync.RWMutex
+	resources    []domain.Resource
+	bookings     []domain.Booking
+	nextResource int
+	nextBooking  int
+	members      *MemberUsecase
+	calendar     *CalendarUsecase
+	notify       *NotificationUsecase
+}
+
+func NewBookingUsecase(members *MemberUsecase, calendar *CalendarUsecase, notify *NotificationUsecase) *BookingUsecase {
+	return &BookingUsecase{
+		resources:    []domain.Resource{},
+		bookings:     []domain.Booking{},
+		nextResource: 1,
+		nextBooking:  1,
+		members:      members,
+		calendar:     calendar,
+		notify:       notify,
+	}
+}
+
+func (u *BookingUsecase) GetResources() []domain.Resource {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Resource(nil), u.resources...)
+}
+
+func (u *BookingUsecase) GetResourceByID(id int) (domain.Resource, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.resourceIndex(id)
+	if err != nil {
+		return domain.Resource{}, err
+	}
+	return u.resources[i], nil
+}
+
+func (u *BookingUsecase) CreateResource(resource domain.Resource) domain.Resource {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	resource.ID = u.nextResource
+	u.nextResource++
+	u.resources = append(u.resources, resource)
+	return resource
+}
+
+func (u *BookingUsecase) UpdateResource(id int, updated domain.Resource) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.resourceIndex(id)
+	if err != nil {
+		return err
+	}
+	updated.ID = id
+	u.resources[i] = updated
+	return nil
+}
+
+// DeleteResource removes a resource that has no upcoming bookings.
+func (u *BookingUsecase) DeleteResource(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.resourceIndex(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, b := range u.bookings {
+		if b.ResourceID == id && b.Active() && b.End.After(now) {
+			return ErrResourceHasBookings
+		}
+	}
+	u.resources = append(u.resources[:i], u.resources[i+1:]...)
+	return nil
+}
+
+// Availability returns the library's hours on the date of day and the
+// times the resource is booked then.
+func (u *BookingUsecase) Availability(resourceID int, day time.Time) (domain.ResourceAvailability, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if _, err := u.resourceIndex(resourceID); err != nil {
+		return domain.ResourceAvailability{}, err
+	}
+	start := dateOf(day)
+	end := start.AddDate(0, 0, 1)
+	booked := []domain.TimeRange{}
+	for _, b := range u.bookings {
+		if b.ResourceID == resourceID && b.Active() && b.Overlaps(start, end) {
+			booked = append(booked, domain.TimeRange{Start: b.Start, End: b.End})
+		}
+	}
+	slices.SortFunc(booked, func(a, b domain.TimeRange) int { return a.Start.Compare(b.Start) })
+	return domain.ResourceAvailability{ResourceID: resourceID, Day: u.calendar.Day(start), Booked: booked}, nil
+}
+
+func (u *BookingUsecase) BookingsForMember(memberID int) []domain.Booking {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	bookings := []domain.Booking{}
+	for _, b := range u.bookings {
+		if b.MemberID == memberID {
+			bookings = append(bookings, b)
+		}
+	}
+	return bookings
+}
+
+func (u *BookingUsecase) GetBookingByID(id int) (domain.Booking, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.bookingIndex(id)
+	if err != nil {
+		return domain.Booking{}, err
+	}
+	return u.bookings[i], nil
+}
+
+// Book reserves a resource for a member. The slot must lie in the future,
+// within the opening hours of a single day, and not overlap another
+// booking of the resource.
+func (u *BookingUsecase) Book(memberID int, booking domain.Booking) (domain.Booking, error) {
+	if _, err := u.members.GetMemberByID(memberID); err != nil {
+		return domain.Booking{}, err
+	}
+	now := time.Now()
+	if !booking.Start.After(now) {
+		return domain.Booking{}, ErrBookingInPast
+	}
+	if !u.calendar.IsOpenBetween(booking.Start, booking.End) {
+		return domain.Booking{}, ErrBookingClosed
+	}
+
+	u.mu.Lock()
+	i, err := u.resourceIndex(booking.ResourceID)
+	if err != nil {
+		u.mu.Unlock()
+		return domain.Booking{}, err
+	}
+	resource := u.resources[i]
+	for _, b := range u.bookings {
+		if b.ResourceID == booking.ResourceID && b.Active() && b.Overlaps(booking.Start, booking.End) {
+			u.mu.Unlock()
+			return domain.Booking{}, ErrBookingConflict
+		}
+	}
+	booking.ID = u.nextBooking
+	booking.MemberID = memberID
+	booking.CreatedAt = now
+	booking.CancelledAt = nil
+	u.nextBooking++
+	u.bookings = append(u.bookings, booking)
+	u.mu.Unlock()
+
+	u.notify.Notify(memberID, domain.NotifyBooking, "Booking confirmed: "+describeBooking(resource, booking), 0)
+	return booking, nil
+}
+
+// Cancel cancels a booking. The member is notified, which matters when
+// staff cancel on their behalf.
+func (u *BookingUsecase) Cancel(id int) (domain.Booking, error) {
+	u.mu.Lock()
+	i, err := u.bookingIndex(id)
+	if err != nil {
+		u.mu.Unlock()
+		return domain.Booking{}, err
+	}
+	if !u.bookings[i].Active() {
+		u.mu.Unlock()
+		return domain.Booking{}, ErrBookingCancelled
+	}
+	now := time.Now()
+	u.bookings[i].CancelledAt = &now
+	booking := u.bookings[i]
+	var resource domain.Resource
+	if r, err := u.resourceIndex(booking.ResourceID); err == nil {
+		resource = u.resources[r]
+	}
+	u.mu.Unlock()
+
+	u.notify.Notify(booking.MemberID, domain.NotifyBooking, "Booking cancelled: "+describeBooking(resource, booking), 0)
+	return booking, nil
+}
+
+// DeleteBookingsForMember removes every booking of a member, for account
+// erasure.
+func (u *BookingUsecase) DeleteBookingsForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.bookings = slices.DeleteFunc(u.bookings, func(b domain.Booking) bool {
+		return b.MemberID == memberID
+	})
+}
+
+func describeBooking(r domain.Resource, b domain.Booking) string {
+	start, end := b.Start.Local(), b.End.Local()
+	return fmt.Sprintf("%s on %s from %s to %s", r.Name, start.Format(time.DateOnly), start.Format("15:04"), end.Format("15:04"))
+}
+
+// resourceIndex expects the caller to hold the lock.
+func (u *BookingUsecase) resourceIndex(id int) (int, error) {
+	for i, r := range u.resources {
+		if r.ID == id {
+			return i, nil
+		}
+	}
+	return 0, domain.NotFound("resource not found")
+}
+
+// bookingIndex expects the caller to hold the lock.
+func (u *BookingUsecase) bookingIndex(id int) (int, error) {
+	for i, b := range u.bookings {
+		if b.ID == id {
+			return i, nil
+		}
+	}
+	return 0, domain.NotFound("booking not found")
+}
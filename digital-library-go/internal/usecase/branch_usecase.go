@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrBranchNotFound  = domain.NotFound("branch not found")
+	ErrBranchHasCopies = domain.Conflict("copies are still kept at or on their way to this branch")
+)
+
+// BranchUsecase manages the library's branches and where they are.
+type BranchUsecase struct {
+	mu       sync.RWMutex
+	branches []domain.Branch
+	nextID   int
+}
+
+func NewBranchUsecase() *BranchUsecase {
+	return &BranchUsecase{
+		branches: []domain.Branch{},
+		nextID:   1,
+	}
+}
+
+func (u *BranchUsecase) GetBranches() []domain.Branch {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Branch(nil), u.branches...)
+}
+
+func (u *BranchUsecase) GetBranchByID(id int) (domain.Branch, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, b := range u.branches {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return domain.Branch{}, ErrBranchNotFound
+}
+
+func (u *BranchUsecase) CreateBranch(branch domain.Branch) domain.Branch {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	branch.ID = u.nextID
+	u.nextID++
+	u.branches = append(u.branches, branch)
+	return branch
+}
+
+func (u *BranchUsecase) UpdateBranch(id int, updated domain.Branch) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, b := range u.branches {
+		if b.ID == id {
+			updated.ID = id
+			u.branches[i] = updated
+			return nil
+		}
+	}
+	return ErrBranchNotFound
+}
+
+// Nearby returns the branches within radius kilometres of a point,
+// nearest first. Distances are rounded to ten metres.
+func (u *BranchUsecase) Nearby(lat, lng, radius float64) []domain.NearbyBranch {
+	u.mu.RLock()
+	nearby := []domain.NearbyBranch{}
+	for _, b := range u.branches {
+		if d := b.DistanceTo(lat, lng); d <= radius {
+			nearby = append(nearby, domain.NearbyBranch{Branch: b, Distance: math.Round(d*100) / 100})
+		}
+	}
+	u.mu.RUnlock()
+
+	slices.SortFunc(nearby, func(a, b domain.NearbyBranch) int {
+		return cmp.Or(cmp.Compare(a.Distance, b.Distance), cmp.Compare(a.ID, b.ID))
+	})
+	return nearby
+}
+
+// remove deletes a branch. CopyUsecase.DeleteBranch calls it once no
+// copy is kept there.
+func (u *BranchUsecase) remove(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, b := range u.branches {
+		if b.ID == id {
+			u.branches = append(u.branches[:i], u.branches[i+1:]...)
+			return nil
+		}
+	}
+	return ErrBranchNotFound
+}
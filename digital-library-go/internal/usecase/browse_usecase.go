@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/search"
+)
+
+// BrowseUsecase searches the catalog and counts the matches by facet so
+// front-ends can build faceted navigation from a single request. Every
+// search is counted in the search analytics.
+type BrowseUsecase struct {
+	books     *BookUsecase
+	authors   *AuthorUsecase
+	loans     *LoanUsecase
+	suggest   *SuggestUsecase
+	policy    *ContentPolicyUsecase
+	analytics *SearchAnalyticsUsecase
+	ranking   *SearchRankingUsecase
+	vocab     *SearchVocabularyUsecase
+}
+
+func NewBrowseUsecase(books *BookUsecase, authors *AuthorUsecase, loans *LoanUsecase, suggest *SuggestUsecase, policy *ContentPolicyUsecase, analytics *SearchAnalyticsUsecase, ranking *SearchRankingUsecase, vocab *SearchVocabularyUsecase) *BrowseUsecase {
+	return &BrowseUsecase{books: books, authors: authors, loans: loans, suggest: suggest, policy: policy, analytics: analytics, ranking: ranking, vocab: vocab}
+}
+
+// Browse returns the books the audience may see that match q and every
+// selected facet value, with facet counts over those books. q is parsed
+// as search.Parse reads it, with phrases, AND, OR, NOT and fields, and
+// its plain words are expanded with synonyms, leaving out stop words.
+// Books found by q are ranked by the weights of the tenant searching,
+// best first. When the text query finds nothing, the result suggests
+// corrections. A query that does not parse is invalid.
+func (u *BrowseUsecase) Browse(q string, selected map[string]string, audience, tenant string) (domain.BrowseResult, error) {
+	now := time.Now()
+	query, _, err := u.parse(q, selected[domain.FacetLanguage])
+	if err != nil {
+		return domain.BrowseResult{}, err
+	}
+	books, counts := u.search(query, selected, audience)
+	u.analytics.Record(q, selected, len(books), now)
+	result := domain.BrowseResult{Total: len(books), Books: books, Facets: map[string][]domain.FacetCount{}}
+	if texts := search.Texts(query); len(texts) > 0 {
+		for i, r := range u.ranking.Rank(books, texts, tenant, now) {
+			result.Books[i] = r.Book
+		}
+	}
+
+	if result.Total == 0 {
+		plain := []string{}
+		for _, t := range search.Terms(query) {
+			plain = append(plain, t.Words...)
+		}
+		if len(plain) > 0 {
+			result.DidYouMean = u.suggest.DidYouMean(strings.Join(plain, " "))
+		}
+	}
+	for _, facet := range []string{domain.FacetAuthor, domain.FacetGenre, domain.FacetDecade, domain.FacetLanguage, domain.FacetFormat, domain.FacetAvailability} {
+		result.Facets[facet] = sortedCounts(counts[facet])
+	}
+	return result, nil
+}
+
+// Explain runs a search as Browse does, without counting it, and shows
+// how its query was read and expanded, and how each book found was
+// scored by the tenant's weights.
+func (u *BrowseUsecase) Explain(q string, selected map[string]string, audience, tenant string) (domain.SearchExplanation, error) {
+	query, expansions, err := u.parse(q, selected[domain.FacetLanguage])
+	if err != nil {
+		return domain.SearchExplanation{}, err
+	}
+	books, _ := u.search(query, selected, audience)
+	explanation := domain.SearchExplanation{
+		Query:      q,
+		Expansions: expansions,
+		Tenant:     featureflag.Tenant(tenant),
+		Weights:    u.ranking.Weights(tenant),
+		Total:      len(books),
+		Results:    u.ranking.Rank(books, search.Texts(query), tenant, time.Now()),
+	}
+	if query != nil {
+		explanation.Parsed = query.String()
+	}
+	return explanation, nil
+}
+
+// parse parses q and expands each run of its plain words with the
+// synonyms and stop words of the language, returning how each was
+// expanded. The query is nil when q has no terms.
+func (u *BrowseUsecase) parse(q, language string) (search.Node, []domain.QueryExpansion, error) {
+	query, err := search.Parse(q)
+	if err != nil {
+		return nil, nil, domain.Wrap(domain.ErrInvalid, err)
+	}
+	expansions := []domain.QueryExpansion{}
+	for _, t := range search.Terms(query) {
+		expansion := u.vocab.Expand(strings.Join(t.Words, " "), language)
+		for _, alt := range expansion.Alternatives {
+			t.Alternatives = append(t.Alternatives, strings.Fields(alt))
+		}
+		expansions = append(expansions, expansion)
+	}
+	return query, expansions, nil
+}
+
+// search finds the books the audience may see that match the query, or
+// every book if it is nil, and the selected facet values. It returns
+// them in catalog order, counted by facet.
+func (u *BrowseUsecase) search(query search.Node, selected map[string]string, audience string) ([]domain.Book, map[string]map[string]int) {
+	names := map[int]string{}
+	for _, a := range u.authors.GetAuthors() {
+		names[a.ID] = a.Name
+	}
+	onLoan := u.loans.BooksOnLoan()
+
+	books := []domain.Book{}
+	counts := map[string]map[string]int{}
+	for _, b := range u.policy.Filter(u.books.GetBooks(), audience) {
+		doc := search.Doc{Title: b.Title, Author: b.Author, Year: b.Year, ISBN: b.ISBN, Language: b.Language}
+		if query != nil && !query.Match(doc) {
+			continue
+		}
+
+		values := facetValues(b, names, onLoan)
+		if !matchesFacets(values, selected) {
+			continue
+		}
+
+		books = append(books, b)
+		for facet, vs := range values {
+			if counts[facet] == nil {
+				counts[facet] = map[string]int{}
+			}
+			for _, v := range vs {
+				counts[facet][v]++
+			}
+		}
+	}
+	return books, counts
+}
+
+// Suggest completes q for the audience. Children are not offered titles
+// they could not find; author names are offered to everyone.
+func (u *BrowseUsecase) Suggest(q string, limit int, audience string) []domain.Suggestion {
+	if audience != domain.AudienceChildren {
+		return u.suggest.Suggest(q, limit)
+	}
+	titles := map[string]bool{}
+	for _, b := range u.policy.Filter(u.books.GetBooks(), audience) {
+		titles[strings.ToLower(b.Title)] = true
+	}
+	// Ask for more than limit, since some titles are dropped.
+	suggestions := slices.DeleteFunc(u.suggest.Suggest(q, limit*4), func(s domain.Suggestion) bool {
+		return s.Kind == "title" && !titles[strings.ToLower(s.Text)]
+	})
+	return suggestions[:min(limit, len(suggestions))]
+}
+
+// Languages counts the books the audience may see by language. Books with
+// no language are left out.
+func (u *BrowseUsecase) Languages(audience string) []domain.FacetCount {
+	counts := map[string]int{}
+	for _, b := range u.policy.Filter(u.books.GetBooks(), audience) {
+		if b.Language != "" {
+			counts[b.Language]++
+		}
+	}
+	return sortedCounts(counts)
+}
+
+// facetValues lists the facet values of one book. A book with several
+// authors counts towards each of them. Genre comes from the custom field
+// of that name.
+func facetValues(b domain.Book, authorNames map[int]string, onLoan map[int]bool) map[string][]string {
+	values := map[string][]string{}
+
+	for _, id := range b.AuthorIDs {
+		if name, ok := authorNames[id]; ok {
+			values[domain.FacetAuthor] = append(values[domain.FacetAuthor], name)
+		}
+	}
+	if len(b.AuthorIDs) == 0 && b.Author != "" {
+		values[domain.FacetAuthor] = []string{b.Author}
+	}
+
+	if v, ok := b.Attributes[domain.FacetGenre].(string); ok && v != "" {
+		values[domain.FacetGenre] = []string{v}
+	}
+	if b.Language != "" {
+		values[domain.FacetLanguage] = []string{b.Language}
+	}
+	values[domain.FacetFormat] = []string{b.MediaFormat()}
+
+	if b.Year > 0 {
+		values[domain.FacetDecade] = []string{fmt.Sprintf("%ds", b.Year/10*10)}
+	}
+
+	if onLoan[b.ID] {
+		values[domain.FacetAvailability] = []string{"on_loan"}
+	} else {
+		values[domain.FacetAvailability] = []string{"available"}
+	}
+	return values
+}
+
+func matchesFacets(values map[string][]string, selected map[string]string) bool {
+	for facet, want := range selected {
+		if !slices.ContainsFunc(values[facet], func(v string) bool { return strings.EqualFold(v, want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedCounts orders facet values by count, most common first.
+func sortedCounts(counts map[string]int) []domain.FacetCount {
+	facets := make([]domain.FacetCount, 0, len(counts))
+	for v, n := range counts {
+		facets = append(facets, domain.FacetCount{Value: v, Count: n})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	// ErrInvalidPeriod is returned for a budget period other than month,
+	// quarter or year.
+	ErrInvalidPeriod      = domain.Invalid("period must be month, quarter or year")
+	ErrInvalidBudgetRange = domain.Invalid("from must not be after to")
+)
+
+// BudgetUsecase reports acquisition spending from the purchase records of
+// the copies.
+type BudgetUsecase struct {
+	copies *CopyUsecase
+}
+
+func NewBudgetUsecase(copies *CopyUsecase) *BudgetUsecase {
+	return &BudgetUsecase{copies: copies}
+}
+
+// Summary sums the spending on copies purchased from from to to inclusive
+// (both dates, UTC) per fund, and per period within each fund. Withdrawn
+// copies still count: the money was spent. Copies without a purchase date
+// are left out.
+func (u *BudgetUsecase) Summary(from, to time.Time, period string) (domain.BudgetSummary, error) {
+	if !domain.ValidPeriod(period) {
+		return domain.BudgetSummary{}, ErrInvalidPeriod
+	}
+	if from.After(to) {
+		return domain.BudgetSummary{}, ErrInvalidBudgetRange
+	}
+
+	summary := domain.BudgetSummary{
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Period: period,
+		Funds:  []domain.FundSpend{},
+	}
+	funds := map[string]*domain.FundSpend{}
+	periods := map[string]map[string]*domain.Spend{}
+	end := to.AddDate(0, 0, 1)
+	for _, c := range u.copies.GetCopies() {
+		if c.PurchasedAt == nil || c.PurchasedAt.Before(from) || !c.PurchasedAt.Before(end) {
+			continue
+		}
+		f := funds[c.FundCode]
+		if f == nil {
+			f = &domain.FundSpend{FundCode: c.FundCode}
+			funds[c.FundCode] = f
+			periods[c.FundCode] = map[string]*domain.Spend{}
+		}
+		key := domain.PeriodKey(period, c.PurchasedAt.UTC())
+		p := periods[c.FundCode][key]
+		if p == nil {
+			p = &domain.Spend{Period: key}
+			periods[c.FundCode][key] = p
+		}
+		p.Amount += c.Price
+		p.Copies++
+		f.Amount += c.Price
+		f.Copies++
+		summary.Amount += c.Price
+		summary.Copies++
+	}
+
+	for code, f := range funds {
+		for _, p := range periods[code] {
+			f.Periods = append(f.Periods, *p)
+		}
+		slices.SortFunc(f.Periods, func(a, b domain.Spend) int { return cmp.Compare(a.Period, b.Period) })
+		summary.Funds = append(summary.Funds, *f)
+	}
+	slices.SortFunc(summary.Funds, func(a, b domain.FundSpend) int { return cmp.Compare(a.FundCode, b.FundCode) })
+	return summary, nil
+}
+
+// FundCopies returns the copies charged to a fund, most recently bought
+// first.
+func (u *BudgetUsecase) FundCopies(fund string) []domain.Copy {
+	copies := slices.DeleteFunc(u.copies.GetCopies(), func(c domain.Copy) bool {
+		return c.FundCode != fund
+	})
+	slices.SortStableFunc(copies, func(a, b domain.Copy) int {
+		return purchased(b).Compare(purchased(a))
+	})
+	return copies
+}
+
+func purchased(c domain.Copy) time.Time {
+	if c.PurchasedAt == nil {
+		return time.Time{}
+	}
+	return *c.PurchasedAt
+}
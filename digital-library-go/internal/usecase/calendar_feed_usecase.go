@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var ErrInvalidFeedToken = domain.Unauthorized("invalid calendar feed token")
+
+// CalendarFeedUsecase issues the signed tokens that let calendar apps,
+// which cannot send an Authorization header, fetch a member's feed. A
+// token is "<member id>.<feed key>.<signature>", the feed key being a
+// random value kept on the member's record. Rotating replaces the key
+// so older URLs stop working, and a token never outlives the record: a
+// member who later gets the same ID has a different key.
+type CalendarFeedUsecase struct {
+	members *MemberUsecase
+	secret  []byte
+}
+
+// NewCalendarFeedUsecase signs tokens with secret. Without one, a random
+// secret is used and feed URLs stop working when the server restarts.
+func NewCalendarFeedUsecase(members *MemberUsecase, secret []byte) (*CalendarFeedUsecase, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &CalendarFeedUsecase{members: members, secret: secret}, nil
+}
+
+// Token returns the member's current feed token.
+func (u *CalendarFeedUsecase) Token(memberID int) (string, error) {
+	key, err := u.members.FeedKey(memberID, false)
+	if err != nil {
+		return "", err
+	}
+	return u.sign(memberID, key), nil
+}
+
+// RotateToken invalidates the member's feed token and returns a new one.
+func (u *CalendarFeedUsecase) RotateToken(memberID int) (string, error) {
+	key, err := u.members.FeedKey(memberID, true)
+	if err != nil {
+		return "", err
+	}
+	return u.sign(memberID, key), nil
+}
+
+// MemberForToken checks a feed token and returns the member it was
+// issued to.
+func (u *CalendarFeedUsecase) MemberForToken(token string) (int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, ErrInvalidFeedToken
+	}
+	memberID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, ErrInvalidFeedToken
+	}
+
+	member, err := u.members.GetMemberByID(memberID)
+	if err != nil || member.FeedKey == "" || !hmac.Equal([]byte(token), []byte(u.sign(memberID, member.FeedKey))) {
+		return 0, ErrInvalidFeedToken
+	}
+	return memberID, nil
+}
+
+func (u *CalendarFeedUsecase) sign(memberID int, key string) string {
+	payload := fmt.Sprintf("%d.%s", memberID, key)
+	mac := hmac.New(sha256.New, u.secret)
+	mac.Write([]byte("calendar-feed:" + payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
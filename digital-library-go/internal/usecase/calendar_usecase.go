@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrNoOpeningDays  = domain.Invalid("the library must open on at least one weekday")
+	ErrDuplicateHours = domain.Invalid("each weekday may appear only once")
+)
+
+// CalendarUsecase keeps the library's weekly opening hours and the dates
+// it is closed on, which loans use to avoid falling due on a closed day.
+// Dates are in the server's local time zone.
+type CalendarUsecase struct {
+	mu     sync.RWMutex
+	hours  []domain.OpeningHours
+	closed []domain.ClosedDay
+}
+
+// NewCalendarUsecase opens the library 09:00 to 18:00 every day until an
+// admin sets the real hours.
+func NewCalendarUsecase() *CalendarUsecase {
+	hours := []domain.OpeningHours{}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		hours = append(hours, domain.OpeningHours{Weekday: d, Opens: "09:00", Closes: "18:00"})
+	}
+	return &CalendarUsecase{hours: hours, closed: []domain.ClosedDay{}}
+}
+
+func (u *CalendarUsecase) GetHours() []domain.OpeningHours {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.OpeningHours(nil), u.hours...)
+}
+
+// SetHours replaces the weekly schedule. Weekdays left out are closed.
+func (u *CalendarUsecase) SetHours(hours []domain.OpeningHours) error {
+	if len(hours) == 0 {
+		return ErrNoOpeningDays
+	}
+	seen := map[time.Weekday]bool{}
+	for _, h := range hours {
+		if seen[h.Weekday] {
+			return ErrDuplicateHours
+		}
+		seen[h.Weekday] = true
+	}
+
+	hours = slices.Clone(hours)
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Weekday < hours[j].Weekday })
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.hours = hours
+	return nil
+}
+
+func (u *CalendarUsecase) GetClosedDays() []domain.ClosedDay {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.ClosedDay(nil), u.closed...)
+}
+
+// AddClosedDay closes the library on a date, replacing the reason if the
+// date is already closed.
+func (u *CalendarUsecase) AddClosedDay(day domain.ClosedDay) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.closed = slices.DeleteFunc(u.closed, func(d domain.ClosedDay) bool { return d.Date == day.Date })
+	u.closed = append(u.closed, day)
+	sort.Slice(u.closed, func(i, j int) bool { return u.closed[i].Date < u.closed[j].Date })
+}
+
+func (u *CalendarUsecase) RemoveClosedDay(date string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	n := len(u.closed)
+	u.closed = slices.DeleteFunc(u.closed, func(d domain.ClosedDay) bool { return d.Date == date })
+	if len(u.closed) == n {
+		return domain.NotFound("closed day not found")
+	}
+	return nil
+}
+
+// Days describes every date from from to to inclusive.
+func (u *CalendarUsecase) Days(from, to time.Time) []domain.CalendarDay {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	days := []domain.CalendarDay{}
+	for d := dateOf(from); !d.After(dateOf(to)); d = d.AddDate(0, 0, 1) {
+		days = append(days, u.day(d))
+	}
+	return days
+}
+
+// NextOpenDay returns t if the library is open on its date, or else the
+// same time of day on the next date it is open.
+func (u *CalendarUsecase) NextOpenDay(t time.Time) time.Time {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	// Every weekday has hours or not, so within a year of closed days
+	// there is an open one unless an admin closed the whole year.
+	for i := 0; i < 366; i++ {
+		if u.day(t).Open {
+			return t
+		}
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// IsOpenBetween reports whether start and end fall on the same date and
+// within the library's hours on it.
+func (u *CalendarUsecase) IsOpenBetween(start, end time.Time) bool {
+	start, end = start.Local(), end.Local()
+	if !dateOf(start).Equal(dateOf(end)) {
+		return false
+	}
+	u.mu.RLock()
+	day := u.day(start)
+	u.mu.RUnlock()
+	if !day.Open {
+		return false
+	}
+	from, to := start.Format("15:04"), end.Format("15:04")
+	return from >= day.Opens && to <= day.Closes
+}
+
+// ClosingTime returns when the library closes on the date of t, or false
+// if it is closed that day.
+func (u *CalendarUsecase) ClosingTime(t time.Time) (time.Time, bool) {
+	t = t.Local()
+	u.mu.RLock()
+	day := u.day(t)
+	u.mu.RUnlock()
+	if !day.Open {
+		return time.Time{}, false
+	}
+	return atTime(t, day.Closes), true
+}
+
+// NextOpening returns when the library next opens after the date of t,
+// or false if it does not open within a year.
+func (u *CalendarUsecase) NextOpening(t time.Time) (time.Time, bool) {
+	t = t.Local()
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for d := dateOf(t).AddDate(0, 0, 1); d.Before(t.AddDate(1, 0, 1)); d = d.AddDate(0, 0, 1) {
+		if day := u.day(d); day.Open {
+			return atTime(d, day.Opens), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Day describes one date.
+func (u *CalendarUsecase) Day(t time.Time) domain.CalendarDay {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.day(t)
+}
+
+// day expects the caller to hold the lock.
+func (u *CalendarUsecase) day(t time.Time) domain.CalendarDay {
+	day := domain.CalendarDay{Date: t.Format(time.DateOnly)}
+	for _, c := range u.closed {
+		if c.Date == day.Date {
+			day.Reason = c.Reason
+			return day
+		}
+	}
+	for _, h := range u.hours {
+		if h.Weekday == t.Weekday() {
+			day.Open, day.Opens, day.Closes = true, h.Opens, h.Closes
+			return day
+		}
+	}
+	return day
+}
+
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// atTime returns the date of t at a "15:04" time of day.
+func atTime(t time.Time, clock string) time.Time {
+	c, _ := time.Parse("15:04", clock)
+	y, m, d := t.Date()
+	return time.Date(y, m, d, c.Hour(), c.Minute(), 0, 0, t.Location())
+}
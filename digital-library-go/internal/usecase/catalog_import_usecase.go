@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// CatalogImportUsecase adds the books read from a catalog file, as a task
+// on the background queue.
+type CatalogImportUsecase struct {
+	books *BookUsecase
+	tasks *TaskUsecase
+}
+
+func NewCatalogImportUsecase(books *BookUsecase, tasks *TaskUsecase) *CatalogImportUsecase {
+	return &CatalogImportUsecase{books: books, tasks: tasks}
+}
+
+// Start queues the import of rows and returns its task.
+func (u *CatalogImportUsecase) Start(rows []domain.CatalogRow) (domain.Task, error) {
+	return u.tasks.Enqueue(domain.TaskCatalogImport, domain.TaskPriorityUser, len(rows), func(t *TaskTracker) any {
+		return u.importRows(rows, t)
+	})
+}
+
+// importRows creates a book from every row that holds a valid one, and
+// reports the others by line. Rows without an ID are numbered after the
+// highest ID in the catalog.
+func (u *CatalogImportUsecase) importRows(rows []domain.CatalogRow, t *TaskTracker) domain.CatalogImportResult {
+	t.Logf("Importing %d rows", len(rows))
+	result := domain.CatalogImportResult{Created: []int{}}
+	failed := 0
+	for _, row := range rows {
+		if row.Error == "" {
+			if err := row.Book.Validate(); err != nil {
+				row.Error = err.Error()
+			}
+		}
+		if row.Error == "" && row.Book.ID != 0 {
+			if err := u.books.CreateBook(row.Book); err != nil {
+				row.Error = err.Error()
+			}
+		}
+		if row.Error != "" {
+			t.Failed(domain.RowError{Line: row.Line, Error: row.Error})
+			failed++
+			continue
+		}
+
+		id := row.Book.ID
+		if id == 0 {
+			id = u.books.CreateBookWithNextID(row.Book)
+		}
+		result.Created = append(result.Created, id)
+		t.Done()
+	}
+	t.Logf("Created %d books, %d rows failed", len(result.Created), failed)
+	return result
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/kafka"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/nats"
+)
+
+const (
+	// commandMemory is how many command IDs are remembered to skip
+	// repeated deliveries.
+	commandMemory = 10000
+	// consumeRetry is the wait before reconnecting to the queue.
+	consumeRetry = 5 * time.Second
+)
+
+// CommandQueue delivers catalog commands from a message broker and takes
+// the ones that cannot be applied.
+type CommandQueue interface {
+	// Consume passes each message to handle, in order, until ctx ends or
+	// the connection fails. When handle returns an error the message is
+	// delivered again if the broker keeps messages.
+	Consume(ctx context.Context, handle func(data []byte) error) error
+	DeadLetter(ctx context.Context, letter domain.DeadLetter) error
+}
+
+// CommandUsecase applies book commands from a queue to the catalog.
+// Commands are remembered by ID so a repeated delivery changes nothing,
+// and messages that can never be applied go to a dead-letter queue
+// rather than blocking the ones behind them.
+type CommandUsecase struct {
+	books   *BookUsecase
+	authors *AuthorUsecase
+	fields  *FieldUsecase
+	queue   CommandQueue
+
+	mu     sync.Mutex
+	seen   map[string]bool
+	recent []string
+	next   int
+}
+
+func NewCommandUsecase(books *BookUsecase, authors *AuthorUsecase, fields *FieldUsecase, queue CommandQueue) *CommandUsecase {
+	return &CommandUsecase{
+		books:   books,
+		authors: authors,
+		fields:  fields,
+		queue:   queue,
+		seen:    map[string]bool{},
+		recent:  make([]string, commandMemory),
+	}
+}
+
+// Run consumes the queue for as long as the server runs, reconnecting
+// after failures. It is started from main.
+func (u *CommandUsecase) Run() {
+	for {
+		err := u.queue.Consume(context.Background(), u.Handle)
+		log.Printf("Commands: consuming stopped, reconnecting in %s: %v", consumeRetry, err)
+		time.Sleep(consumeRetry)
+	}
+}
+
+// Handle applies one message. It returns an error only when the message
+// could neither be applied nor dead-lettered.
+func (u *CommandUsecase) Handle(data []byte) error {
+	var cmd domain.CatalogCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return u.deadLetter(data, "invalid JSON: "+err.Error())
+	}
+	if err := cmd.Validate(); err != nil {
+		return u.deadLetter(data, err.Error())
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.seen[cmd.ID] {
+		return nil
+	}
+	// A dead-lettered command is not remembered, so it can be fixed and
+	// sent again with the same ID.
+	if err := u.apply(cmd); err != nil {
+		return u.deadLetter(data, err.Error())
+	}
+	u.remember(cmd.ID)
+	return nil
+}
+
+func (u *CommandUsecase) apply(cmd domain.CatalogCommand) error {
+	if cmd.Type == domain.CommandDelete {
+		if err := u.books.DeleteBook(cmd.BookID); err != nil && !errors.Is(err, ErrBookNotFound) {
+			return err
+		}
+		return nil
+	}
+	book := *cmd.Book
+	book.ID = cmd.BookID
+	// A book that exists keeps its status, so it is checked against it.
+	current, err := u.books.GetBookByID(cmd.BookID)
+	if err == nil {
+		book.Status = current.Status
+	}
+	if err := book.Validate(); err != nil {
+		return err
+	}
+	// Commands carry no host, so only the fields every tenant has apply.
+	attrs, err := u.fields.ValidateAttributes("", book.Attributes, current.Attributes)
+	if err != nil {
+		return err
+	}
+	book.Attributes = attrs
+	return u.authors.SaveBook(&book, func(b domain.Book) error {
+		u.books.UpsertBook(b)
+		return nil
+	})
+}
+
+func (u *CommandUsecase) deadLetter(data []byte, reason string) error {
+	log.Printf("Commands: setting a message aside: %s", reason)
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	err := u.queue.DeadLetter(ctx, domain.DeadLetter{Reason: reason, Message: string(data), At: time.Now()})
+	if err != nil {
+		log.Printf("Commands: dead-lettering failed: %v", err)
+	}
+	return err
+}
+
+// remember records a handled command, forgetting the oldest once
+// commandMemory are kept. It expects the caller to hold the lock.
+func (u *CommandUsecase) remember(id string) {
+	delete(u.seen, u.recent[u.next])
+	u.recent[u.next] = id
+	u.seen[id] = true
+	u.next = (u.next + 1) % len(u.recent)
+}
+
+// natsCommandQueue subscribes to a subject. NATS does not redeliver, so a
+// message that could not be handled is only logged.
+type natsCommandQueue struct {
+	client      *nats.Client
+	subject     string
+	deadLetters string
+}
+
+func NewNATSCommandQueue(client *nats.Client, subject, deadLetters string) CommandQueue {
+	return &natsCommandQueue{client: client, subject: subject, deadLetters: deadLetters}
+}
+
+func (q *natsCommandQueue) Consume(ctx context.Context, handle func(data []byte) error) error {
+	return q.client.Subscribe(ctx, q.subject, func(data []byte) {
+		if err := handle(data); err != nil {
+			log.Printf("Commands: dropping a message: %v", err)
+		}
+	})
+}
+
+func (q *natsCommandQueue) DeadLetter(ctx context.Context, letter domain.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	return q.client.Publish(ctx, q.deadLetters, data)
+}
+
+// kafkaCommandQueue reads a topic as a member of a consumer group,
+// committing each message once it is handled, so a restart resumes after
+// the last one.
+type kafkaCommandQueue struct {
+	client      *kafka.Client
+	group, name string
+	topic       string
+	deadLetters string
+}
+
+func NewKafkaCommandQueue(client *kafka.Client, group, name, topic, deadLetters string) CommandQueue {
+	return &kafkaCommandQueue{client: client, group: group, name: name, topic: topic, deadLetters: deadLetters}
+}
+
+func (q *kafkaCommandQueue) Consume(ctx context.Context, handle func(data []byte) error) error {
+	consumer, err := q.client.Subscribe(ctx, q.group, q.name, q.topic)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		consumer.Close(ctx)
+	}()
+	for {
+		messages, err := consumer.Poll(ctx)
+		if err != nil {
+			return err
+		}
+		// Committing per message keeps every partition's offset exact.
+		for _, m := range messages {
+			if err := handle(m.Value); err != nil {
+				return err
+			}
+			if err := consumer.Commit(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (q *kafkaCommandQueue) DeadLetter(ctx context.Context, letter domain.DeadLetter) error {
+	return q.client.Produce(ctx, q.deadLetters, "", letter)
+}
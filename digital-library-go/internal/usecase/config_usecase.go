@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// ConfigUsecase reloads the runtime configuration without a restart: it
+// loads it, checks it, applies the settings that changed and writes each
+// change to the audit log. A configuration that fails to load or apply
+// leaves the current one in force.
+type ConfigUsecase struct {
+	mu      sync.Mutex
+	load    func() (domain.RuntimeConfig, error)
+	apply   func(old, next domain.RuntimeConfig) error
+	audit   *AuditUsecase
+	current domain.RuntimeConfig
+}
+
+// NewConfigUsecase loads the configuration and applies all of it. Apply
+// is given the configuration in force and the new one, so it can leave
+// alone what did not change; it must apply nothing if it fails.
+func NewConfigUsecase(load func() (domain.RuntimeConfig, error), apply func(old, next domain.RuntimeConfig) error, audit *AuditUsecase) (*ConfigUsecase, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := apply(domain.RuntimeConfig{}, cfg); err != nil {
+		return nil, err
+	}
+	return &ConfigUsecase{load: load, apply: apply, audit: audit, current: cfg}, nil
+}
+
+// Current returns the configuration in force.
+func (u *ConfigUsecase) Current() domain.RuntimeConfig {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.current
+}
+
+// Reload loads the configuration again and applies what changed, on
+// behalf of by, and returns the changes.
+func (u *ConfigUsecase) Reload(by string, now time.Time) ([]domain.ConfigChange, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	cfg, err := u.load()
+	if err != nil {
+		return nil, domain.Wrap(domain.ErrInvalid, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	changes := u.current.Diff(cfg)
+	if len(changes) == 0 {
+		return changes, nil
+	}
+	if err := u.apply(u.current, cfg); err != nil {
+		return nil, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.current = cfg
+	for _, c := range changes {
+		u.audit.Record(domain.AuditEvent{
+			Time:   now,
+			Type:   "config_changed",
+			Actor:  by,
+			Detail: fmt.Sprintf("%s: %q -> %q", c.Setting, c.Old, c.New),
+		})
+	}
+	return changes, nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+)
+
+// DefaultContentPolicy shows children books rated up to 12 and unrated
+// books.
+var DefaultContentPolicy = domain.ContentPolicy{ChildrenMaxAge: 12}
+
+// ContentPolicyUsecase holds the library's content policy and applies it
+// to catalog listings. Tenants, the host names sites are served on, may
+// have a default audience for requests that do not ask for one, so a
+// children's site lists only books for children.
+type ContentPolicyUsecase struct {
+	mu        sync.RWMutex
+	policy    domain.ContentPolicy
+	audiences map[string]string
+}
+
+func NewContentPolicyUsecase(policy domain.ContentPolicy) *ContentPolicyUsecase {
+	return &ContentPolicyUsecase{policy: policy, audiences: map[string]string{}}
+}
+
+func (u *ContentPolicyUsecase) GetPolicy() domain.ContentPolicy {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.policy
+}
+
+func (u *ContentPolicyUsecase) SetPolicy(policy domain.ContentPolicy) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.policy = policy
+}
+
+// TenantAudiences returns the default audience of each tenant that has
+// one.
+func (u *ContentPolicyUsecase) TenantAudiences() map[string]string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return maps.Clone(u.audiences)
+}
+
+// DefaultAudience returns the audience of a tenant's listings when a
+// request does not ask for one, or empty for everyone.
+func (u *ContentPolicyUsecase) DefaultAudience(tenant string) string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.audiences[featureflag.Tenant(tenant)]
+}
+
+// SetTenantAudience gives a tenant a default audience.
+func (u *ContentPolicyUsecase) SetTenantAudience(tenant, audience string) error {
+	tenant = featureflag.Tenant(tenant)
+	if tenant == "" {
+		return domain.Invalid("tenant must be a host name")
+	}
+	if !domain.ValidAudience(audience) {
+		return domain.Invalid("default_audience must be all or children")
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.audiences[tenant] = audience
+	return nil
+}
+
+// ClearTenantAudience lets a tenant's listings be for everyone again
+// unless a request asks otherwise.
+func (u *ContentPolicyUsecase) ClearTenantAudience(tenant string) error {
+	tenant = featureflag.Tenant(tenant)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.audiences[tenant]; !ok {
+		return domain.NotFound("tenant has no default audience")
+	}
+	delete(u.audiences, tenant)
+	return nil
+}
+
+func (u *ContentPolicyUsecase) Allows(book domain.Book, audience string) bool {
+	policy := u.GetPolicy()
+	return policy.Allows(book, audience)
+}
+
+// Filter keeps the books the audience may see.
+func (u *ContentPolicyUsecase) Filter(books []domain.Book, audience string) []domain.Book {
+	policy := u.GetPolicy()
+	allowed := []domain.Book{}
+	for _, b := range books {
+		if policy.Allows(b, audience) {
+			allowed = append(allowed, b)
+		}
+	}
+	return allowed
+}
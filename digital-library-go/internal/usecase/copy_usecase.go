@@ -0,0 +1,415 @@
+package usecase
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrCopyNotFound     = domain.NotFound("copy not found")
+	ErrDuplicateBarcode = domain.Conflict("a copy with this barcode already exists")
+	ErrCopyWithdrawn    = domain.Conflict("copy is already withdrawn")
+	ErrCopyNotWithdrawn = domain.Conflict("copy is not withdrawn")
+	ErrCopyInTransit    = domain.Conflict("copy is already in transit")
+	ErrCopyNotInTransit = domain.Conflict("copy is not in transit")
+	ErrCopyNoBranch     = domain.Conflict("copy belongs to no branch")
+	ErrCopyAtBranch     = domain.Invalid("copy is already at that branch")
+)
+
+// CopyUsecase manages the physical copies of the books in the catalog.
+type CopyUsecase struct {
+	mu       sync.RWMutex
+	copies   []domain.Copy
+	nextID   int
+	books    *BookUsecase
+	branches *BranchUsecase
+}
+
+func NewCopyUsecase(books *BookUsecase, branches *BranchUsecase) *CopyUsecase {
+	return &CopyUsecase{
+		copies:   []domain.Copy{},
+		nextID:   1,
+		books:    books,
+		branches: branches,
+	}
+}
+
+func (u *CopyUsecase) GetCopies() []domain.Copy {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Copy(nil), u.copies...)
+}
+
+// FindCopies returns the copies kept within location (all copies if it
+// is empty), in call-number order when byCallNumber is set and in order
+// of creation otherwise.
+func (u *CopyUsecase) FindCopies(location string, byCallNumber bool) []domain.Copy {
+	u.mu.RLock()
+	copies := []domain.Copy{}
+	for _, c := range u.copies {
+		if c.InLocation(location) {
+			copies = append(copies, c)
+		}
+	}
+	u.mu.RUnlock()
+
+	if byCallNumber {
+		slices.SortStableFunc(copies, func(a, b domain.Copy) int {
+			return cmp.Or(domain.CompareCallNumbers(a.CallNumber, b.CallNumber), strings.Compare(a.Barcode, b.Barcode))
+		})
+	}
+	return copies
+}
+
+func (u *CopyUsecase) GetCopyByID(id int) (domain.Copy, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, c := range u.copies {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return domain.Copy{}, ErrCopyNotFound
+}
+
+// GetCopyByBarcode looks up the copy with a scanned barcode.
+func (u *CopyUsecase) GetCopyByBarcode(barcode string) (domain.Copy, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, c := range u.copies {
+		if c.Barcode == barcode {
+			return c, nil
+		}
+	}
+	return domain.Copy{}, ErrCopyNotFound
+}
+
+// CopiesForBook returns every copy of a book.
+func (u *CopyUsecase) CopiesForBook(bookID int) ([]domain.Copy, error) {
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return nil, err
+	}
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	copies := []domain.Copy{}
+	for _, c := range u.copies {
+		if c.BookID == bookID {
+			copies = append(copies, c)
+		}
+	}
+	return copies, nil
+}
+
+// AllWithdrawn reports whether a book has copies on record and every one
+// of them has been withdrawn, leaving none to lend.
+func (u *CopyUsecase) AllWithdrawn(bookID int) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	found := false
+	for _, c := range u.copies {
+		if c.BookID == bookID {
+			if !c.Withdrawn() {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// CreateCopy adds a copy. A copy with a price but no purchase date is
+// taken to have been bought today.
+func (u *CopyUsecase) CreateCopy(copy domain.Copy) (domain.Copy, error) {
+	if _, err := u.books.GetBookByID(copy.BookID); err != nil {
+		return domain.Copy{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err := u.checkBranch(copy.BranchID); err != nil {
+		return domain.Copy{}, err
+	}
+	if u.barcodeTaken(copy.Barcode, 0) {
+		return domain.Copy{}, ErrDuplicateBarcode
+	}
+	copy.Normalize()
+	copy.Withdrawal = nil
+	copy.Reserve = nil
+	copy.Transfer = nil
+	if copy.Price > 0 && copy.PurchasedAt == nil {
+		now := time.Now()
+		copy.PurchasedAt = &now
+	}
+	copy.ID = u.nextID
+	u.nextID++
+	u.copies = append(u.copies, copy)
+	return copy, nil
+}
+
+// addCopies adds copies as CreateCopy does, either all of them or, if
+// any cannot be added, none.
+func (u *CopyUsecase) addCopies(copies []domain.Copy) ([]domain.Copy, error) {
+	for _, c := range copies {
+		if _, err := u.books.GetBookByID(c.BookID); err != nil {
+			return nil, err
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	barcodes := map[string]bool{}
+	for _, c := range copies {
+		if err := u.checkBranch(c.BranchID); err != nil {
+			return nil, err
+		}
+		if u.barcodeTaken(c.Barcode, 0) || barcodes[c.Barcode] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateBarcode, c.Barcode)
+		}
+		barcodes[c.Barcode] = true
+	}
+	added := make([]domain.Copy, len(copies))
+	for i, c := range copies {
+		c.Normalize()
+		c.Withdrawal, c.Reserve, c.Transfer = nil, nil, nil
+		c.ID = u.nextID
+		u.nextID++
+		added[i] = c
+	}
+	u.copies = append(u.copies, added...)
+	return added, nil
+}
+
+func (u *CopyUsecase) UpdateCopy(id int, updated domain.Copy) error {
+	if _, err := u.books.GetBookByID(updated.BookID); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err := u.checkBranch(updated.BranchID); err != nil {
+		return err
+	}
+	if u.barcodeTaken(updated.Barcode, id) {
+		return ErrDuplicateBarcode
+	}
+	for i, c := range u.copies {
+		if c.ID == id {
+			updated.ID = id
+			updated.Normalize()
+			updated.Withdrawal = c.Withdrawal
+			updated.Reserve = c.Reserve
+			updated.Transfer = c.Transfer
+			if updated.PurchasedAt == nil {
+				updated.PurchasedAt = c.PurchasedAt
+			}
+			u.copies[i] = updated
+			return nil
+		}
+	}
+	return ErrCopyNotFound
+}
+
+func (u *CopyUsecase) DeleteCopy(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.ID == id {
+			u.copies = append(u.copies[:i], u.copies[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCopyNotFound
+}
+
+// DeleteBranch removes a branch no copy is kept at or on its way to.
+func (u *CopyUsecase) DeleteBranch(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if slices.ContainsFunc(u.copies, func(c domain.Copy) bool {
+		return c.BranchID == id || c.InTransit() && c.Transfer.ToBranchID == id
+	}) {
+		if _, err := u.branches.GetBranchByID(id); err != nil {
+			return err
+		}
+		return ErrBranchHasCopies
+	}
+	return u.branches.remove(id)
+}
+
+// Withdraw takes a copy out of circulation for one of the reason codes.
+// The copy stays on record so the withdrawal can be reported and undone.
+func (u *CopyUsecase) Withdraw(id int, reason, note string, now time.Time) (domain.Copy, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.ID == id {
+			if c.Withdrawn() {
+				return domain.Copy{}, ErrCopyWithdrawn
+			}
+			u.copies[i].Withdrawal = &domain.Withdrawal{Reason: reason, Note: note, WithdrawnAt: now}
+			return u.copies[i], nil
+		}
+	}
+	return domain.Copy{}, ErrCopyNotFound
+}
+
+// Reinstate puts a withdrawn copy back into circulation, e.g. when a lost
+// copy turns up.
+func (u *CopyUsecase) Reinstate(id int) (domain.Copy, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.ID == id {
+			if !c.Withdrawn() {
+				return domain.Copy{}, ErrCopyNotWithdrawn
+			}
+			u.copies[i].Withdrawal = nil
+			return u.copies[i], nil
+		}
+	}
+	return domain.Copy{}, ErrCopyNotFound
+}
+
+// Transfer sends a copy from its branch to another one. It stays at its
+// branch until ReceiveTransfer.
+func (u *CopyUsecase) Transfer(id, toBranchID int, now time.Time) (domain.Copy, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.ID == id {
+			switch {
+			case c.Withdrawn():
+				return domain.Copy{}, ErrCopyWithdrawn
+			case c.InTransit():
+				return domain.Copy{}, ErrCopyInTransit
+			case c.BranchID == 0:
+				return domain.Copy{}, ErrCopyNoBranch
+			case c.BranchID == toBranchID:
+				return domain.Copy{}, ErrCopyAtBranch
+			}
+			if _, err := u.branches.GetBranchByID(toBranchID); err != nil {
+				return domain.Copy{}, err
+			}
+			u.copies[i].Transfer = &domain.CopyTransfer{ToBranchID: toBranchID, SentAt: now}
+			return u.copies[i], nil
+		}
+	}
+	return domain.Copy{}, ErrCopyNotFound
+}
+
+// ReceiveTransfer records that a copy in transit arrived at the branch it
+// was sent to, which it then belongs to. It returns the copy and the
+// transfer that ended.
+func (u *CopyUsecase) ReceiveTransfer(id int) (domain.Copy, domain.CopyTransfer, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.ID == id {
+			if !c.InTransit() {
+				return domain.Copy{}, domain.CopyTransfer{}, ErrCopyNotInTransit
+			}
+			transfer := *c.Transfer
+			u.copies[i].BranchID = transfer.ToBranchID
+			u.copies[i].Transfer = nil
+			return u.copies[i], transfer, nil
+		}
+	}
+	return domain.Copy{}, domain.CopyTransfer{}, ErrCopyNotFound
+}
+
+// SendForHold makes sure a copy of a book gets to the branch a hold is
+// picked up from. A copy in circulation kept there will do. Otherwise a
+// copy already on its way there for no other hold is given the hold, or
+// failing that a copy is sent from another branch. It reports whether
+// the hold has to wait for a copy to arrive, which it does not when the
+// branch has a copy or there is no copy to send.
+func (u *CopyUsecase) SendForHold(bookID, branchID, holdID int, now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, err := u.branches.GetBranchByID(branchID); err != nil {
+		return false
+	}
+	incoming, spare := -1, -1
+	for i, c := range u.copies {
+		if c.BookID != bookID || c.Withdrawn() {
+			continue
+		}
+		switch {
+		case c.InTransit():
+			if c.Transfer.ToBranchID == branchID && c.Transfer.HoldID == 0 && incoming < 0 {
+				incoming = i
+			}
+		case c.BranchID == branchID:
+			return false
+		case c.BranchID != 0 && spare < 0:
+			spare = i
+		}
+	}
+	switch {
+	case incoming >= 0:
+		transfer := *u.copies[incoming].Transfer
+		transfer.HoldID = holdID
+		u.copies[incoming].Transfer = &transfer
+	case spare >= 0:
+		u.copies[spare].Transfer = &domain.CopyTransfer{ToBranchID: branchID, HoldID: holdID, SentAt: now}
+	default:
+		return false
+	}
+	return true
+}
+
+// ReleaseHold frees a copy on its way to fill a hold that ended. The
+// copy still goes where it was sent.
+func (u *CopyUsecase) ReleaseHold(holdID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.InTransit() && c.Transfer.HoldID == holdID {
+			transfer := *c.Transfer
+			transfer.HoldID = 0
+			u.copies[i].Transfer = &transfer
+		}
+	}
+}
+
+// SetReserve puts a copy on reserve for a course, or takes it off reserve
+// when reserve is nil.
+func (u *CopyUsecase) SetReserve(id int, reserve *domain.CopyReserve) (domain.Copy, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, c := range u.copies {
+		if c.ID == id {
+			u.copies[i].Reserve = reserve
+			return u.copies[i], nil
+		}
+	}
+	return domain.Copy{}, ErrCopyNotFound
+}
+
+// checkBranch makes sure a copy's branch exists, if it names one. It
+// expects the caller to hold the lock, so the branch cannot be deleted in
+// between.
+func (u *CopyUsecase) checkBranch(id int) error {
+	if id == 0 {
+		return nil
+	}
+	_, err := u.branches.GetBranchByID(id)
+	return err
+}
+
+// barcodeTaken reports whether a copy other than except has the barcode.
+// It expects the caller to hold the lock.
+func (u *CopyUsecase) barcodeTaken(barcode string, except int) bool {
+	for _, c := range u.copies {
+		if c.Barcode == barcode && c.ID != except {
+			return true
+		}
+	}
+	return false
+}
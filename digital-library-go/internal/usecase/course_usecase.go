@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrCourseNotFound  = domain.NotFound("course not found")
+	ErrDuplicateCourse = domain.Conflict("a course with this code already exists")
+	ErrCourseLinked    = domain.Conflict("another course is already linked to this LMS course")
+	ErrCopyOnReserve   = domain.Conflict("copy is on reserve for another course")
+	ErrCopyNotReserved = domain.Conflict("copy is not on reserve for this course")
+)
+
+// CourseUsecase keeps the courses books are put on reserve for, and which
+// copies are set aside for them on short-loan rules.
+type CourseUsecase struct {
+	mu      sync.RWMutex
+	courses []domain.Course
+	nextID  int
+	books   *BookUsecase
+	copies  *CopyUsecase
+}
+
+func NewCourseUsecase(books *BookUsecase, copies *CopyUsecase) *CourseUsecase {
+	return &CourseUsecase{
+		courses: []domain.Course{},
+		nextID:  1,
+		books:   books,
+		copies:  copies,
+	}
+}
+
+func (u *CourseUsecase) GetCourses() []domain.Course {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return slices.Clone(u.courses)
+}
+
+func (u *CourseUsecase) GetCourseByID(id int) (domain.Course, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i := u.index(id)
+	if i < 0 {
+		return domain.Course{}, ErrCourseNotFound
+	}
+	return u.courses[i], nil
+}
+
+// CourseForContext finds the course linked to a course in a learning
+// management system.
+func (u *CourseUsecase) CourseForContext(ctx domain.LTIContext) (domain.Course, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, c := range u.courses {
+		if c.LTI != nil && *c.LTI == ctx {
+			return c, true
+		}
+	}
+	return domain.Course{}, false
+}
+
+func (u *CourseUsecase) CreateCourse(course domain.Course) (domain.Course, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err := u.checkUnique(0, course); err != nil {
+		return domain.Course{}, err
+	}
+	course.ID = u.nextID
+	course.BookIDs = []int{}
+	course.CreatedAt = time.Now()
+	u.nextID++
+	u.courses = append(u.courses, course)
+	return course, nil
+}
+
+// UpdateCourse changes a course's code, title, dates and LMS link,
+// keeping its reserves.
+func (u *CourseUsecase) UpdateCourse(id int, updated domain.Course) (domain.Course, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.index(id)
+	if i < 0 {
+		return domain.Course{}, ErrCourseNotFound
+	}
+	if err := u.checkUnique(id, updated); err != nil {
+		return domain.Course{}, err
+	}
+	c := &u.courses[i]
+	c.Code, c.Title, c.LTI = updated.Code, updated.Title, updated.LTI
+	c.StartsOn, c.EndsOn = updated.StartsOn, updated.EndsOn
+	return *c, nil
+}
+
+// LinkContext provisions the course for a course in a learning management
+// system the first time an instructor launches from it. Only a course
+// librarians linked to it is used; anyone can name an LMS course after a
+// library course, so a new course is created otherwise. If its code is
+// taken, it is numbered, e.g. HIST101-2.
+func (u *CourseUsecase) LinkContext(ctx domain.LTIContext, code, title string) domain.Course {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, c := range u.courses {
+		if c.LTI != nil && *c.LTI == ctx {
+			return c
+		}
+	}
+
+	unique := code
+	for n := 2; slices.ContainsFunc(u.courses, func(c domain.Course) bool { return strings.EqualFold(c.Code, unique) }); n++ {
+		unique = fmt.Sprintf("%s-%d", code, n)
+	}
+	course := domain.Course{ID: u.nextID, Code: unique, Title: title, BookIDs: []int{}, LTI: &ctx, CreatedAt: time.Now()}
+	u.nextID++
+	u.courses = append(u.courses, course)
+	return course
+}
+
+// DeleteCourse deletes a course and takes its copies off reserve.
+func (u *CourseUsecase) DeleteCourse(id int) error {
+	u.mu.Lock()
+	i := u.index(id)
+	if i < 0 {
+		u.mu.Unlock()
+		return ErrCourseNotFound
+	}
+	u.courses = slices.Delete(u.courses, i, i+1)
+	u.mu.Unlock()
+
+	for _, c := range u.ReserveCopies(id) {
+		u.copies.SetReserve(c.ID, nil)
+	}
+	return nil
+}
+
+// AddReserve puts a book on reserve for a course. Adding a book already
+// on reserve changes nothing.
+func (u *CourseUsecase) AddReserve(id, bookID int) (domain.Course, error) {
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return domain.Course{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.index(id)
+	if i < 0 {
+		return domain.Course{}, ErrCourseNotFound
+	}
+	if !slices.Contains(u.courses[i].BookIDs, bookID) {
+		u.courses[i].BookIDs = append(slices.Clone(u.courses[i].BookIDs), bookID)
+	}
+	return u.courses[i], nil
+}
+
+func (u *CourseUsecase) RemoveReserve(id, bookID int) (domain.Course, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.index(id)
+	if i < 0 {
+		return domain.Course{}, ErrCourseNotFound
+	}
+	u.courses[i].BookIDs = slices.DeleteFunc(slices.Clone(u.courses[i].BookIDs), func(b int) bool { return b == bookID })
+	return u.courses[i], nil
+}
+
+// Reserves returns the books on reserve for a course, skipping any that
+// have since left the catalog.
+func (u *CourseUsecase) Reserves(id int) ([]domain.Book, error) {
+	course, err := u.GetCourseByID(id)
+	if err != nil {
+		return nil, err
+	}
+	books := []domain.Book{}
+	for _, bookID := range course.BookIDs {
+		if b, err := u.books.GetBookByID(bookID); err == nil {
+			books = append(books, b)
+		}
+	}
+	return books, nil
+}
+
+// ReserveCopies returns the copies on reserve for a course.
+func (u *CourseUsecase) ReserveCopies(id int) []domain.Copy {
+	copies := []domain.Copy{}
+	for _, c := range u.copies.GetCopies() {
+		if c.Reserve != nil && c.Reserve.CourseID == id {
+			copies = append(copies, c)
+		}
+	}
+	return copies
+}
+
+// PlaceOnReserve sets a copy aside for a course, to be lent on a reserve
+// loan rule while the course runs. Its book joins the course's reserve
+// list. A copy already on reserve for the course just changes rule.
+func (u *CourseUsecase) PlaceOnReserve(id, copyID int, rule string) (domain.Copy, error) {
+	copy, err := u.copies.GetCopyByID(copyID)
+	if err != nil {
+		return domain.Copy{}, err
+	}
+	if copy.Withdrawn() {
+		return domain.Copy{}, ErrCopyWithdrawn
+	}
+	if copy.Reserve != nil && copy.Reserve.CourseID != id {
+		return domain.Copy{}, ErrCopyOnReserve
+	}
+	if _, err := u.AddReserve(id, copy.BookID); err != nil {
+		return domain.Copy{}, err
+	}
+	return u.copies.SetReserve(copyID, &domain.CopyReserve{CourseID: id, LoanRule: rule})
+}
+
+// TakeOffReserve returns a copy to normal loans. Its book stays on the
+// course's reserve list.
+func (u *CourseUsecase) TakeOffReserve(id, copyID int) (domain.Copy, error) {
+	copy, err := u.copies.GetCopyByID(copyID)
+	if err != nil {
+		return domain.Copy{}, err
+	}
+	if copy.Reserve == nil || copy.Reserve.CourseID != id {
+		return domain.Copy{}, ErrCopyNotReserved
+	}
+	return u.copies.SetReserve(copyID, nil)
+}
+
+// ActiveReserve returns the reserve a book is lent on at t: that of a copy
+// on reserve for a course running then. Loans are of books rather than
+// copies, so one such copy puts the whole title on reserve rules.
+func (u *CourseUsecase) ActiveReserve(bookID int, t time.Time) (domain.CopyReserve, bool) {
+	copies, err := u.copies.CopiesForBook(bookID)
+	if err != nil {
+		return domain.CopyReserve{}, false
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, c := range copies {
+		if c.Reserve == nil || c.Withdrawn() {
+			continue
+		}
+		if i := u.index(c.Reserve.CourseID); i >= 0 && u.courses[i].Active(t) {
+			return *c.Reserve, true
+		}
+	}
+	return domain.CopyReserve{}, false
+}
+
+// index expects the caller to hold the lock.
+func (u *CourseUsecase) index(id int) int {
+	return slices.IndexFunc(u.courses, func(c domain.Course) bool { return c.ID == id })
+}
+
+// checkUnique expects the caller to hold the lock.
+func (u *CourseUsecase) checkUnique(id int, course domain.Course) error {
+	for _, c := range u.courses {
+		if c.ID == id {
+			continue
+		}
+		if strings.EqualFold(c.Code, course.Code) {
+			return ErrDuplicateCourse
+		}
+		if c.LTI != nil && course.LTI != nil && *c.LTI == *course.LTI {
+			return ErrCourseLinked
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrPublisherNotFound    = domain.NotFound("publisher not found")
+	ErrEditionNotFound      = domain.NotFound("edition not found")
+	ErrPublisherHasEditions = domain.Conflict("publisher still has editions")
+)
+
+// EditionUsecase manages publishers and the editions they publish of the
+// books in the catalog.
+type EditionUsecase struct {
+	mu            sync.RWMutex
+	publishers    []domain.Publisher
+	editions      []domain.Edition
+	nextPublisher int
+	nextEdition   int
+	books         *BookUsecase
+}
+
+func NewEditionUsecase(books *BookUsecase) *EditionUsecase {
+	return &EditionUsecase{
+		publishers:    []domain.Publisher{},
+		editions:      []domain.Edition{},
+		nextPublisher: 1,
+		nextEdition:   1,
+		books:         books,
+	}
+}
+
+func (u *EditionUsecase) GetPublishers() []domain.Publisher {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Publisher(nil), u.publishers...)
+}
+
+func (u *EditionUsecase) GetPublisherByID(id int) (domain.Publisher, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, p := range u.publishers {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Publisher{}, ErrPublisherNotFound
+}
+
+func (u *EditionUsecase) CreatePublisher(publisher domain.Publisher) domain.Publisher {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	publisher.ID = u.nextPublisher
+	u.nextPublisher++
+	u.publishers = append(u.publishers, publisher)
+	return publisher
+}
+
+func (u *EditionUsecase) UpdatePublisher(id int, updated domain.Publisher) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, p := range u.publishers {
+		if p.ID == id {
+			updated.ID = id
+			u.publishers[i] = updated
+			return nil
+		}
+	}
+	return ErrPublisherNotFound
+}
+
+// DeletePublisher removes a publisher that has no editions left.
+func (u *EditionUsecase) DeletePublisher(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, p := range u.publishers {
+		if p.ID == id {
+			for _, e := range u.editions {
+				if e.PublisherID == id {
+					return ErrPublisherHasEditions
+				}
+			}
+			u.publishers = append(u.publishers[:i], u.publishers[i+1:]...)
+			return nil
+		}
+	}
+	return ErrPublisherNotFound
+}
+
+func (u *EditionUsecase) GetEditions() []domain.Edition {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Edition(nil), u.editions...)
+}
+
+func (u *EditionUsecase) GetEditionByID(id int) (domain.Edition, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, e := range u.editions {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return domain.Edition{}, ErrEditionNotFound
+}
+
+func (u *EditionUsecase) CreateEdition(edition domain.Edition) (domain.Edition, error) {
+	if _, err := u.books.GetBookByID(edition.BookID); err != nil {
+		return domain.Edition{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.publisherExists(edition.PublisherID) {
+		return domain.Edition{}, ErrPublisherNotFound
+	}
+	edition.ID = u.nextEdition
+	u.nextEdition++
+	u.editions = append(u.editions, edition)
+	return edition, nil
+}
+
+func (u *EditionUsecase) UpdateEdition(id int, updated domain.Edition) error {
+	if _, err := u.books.GetBookByID(updated.BookID); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.publisherExists(updated.PublisherID) {
+		return ErrPublisherNotFound
+	}
+	for i, e := range u.editions {
+		if e.ID == id {
+			updated.ID = id
+			u.editions[i] = updated
+			return nil
+		}
+	}
+	return ErrEditionNotFound
+}
+
+func (u *EditionUsecase) DeleteEdition(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, e := range u.editions {
+		if e.ID == id {
+			u.editions = append(u.editions[:i], u.editions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrEditionNotFound
+}
+
+// EditionsForBook returns all editions of a title, oldest first.
+func (u *EditionUsecase) EditionsForBook(bookID int) ([]domain.Edition, error) {
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return nil, err
+	}
+	return u.filter(func(e domain.Edition) bool { return e.BookID == bookID }), nil
+}
+
+// EditionsByPublisher returns everything a publisher has published,
+// oldest first.
+func (u *EditionUsecase) EditionsByPublisher(publisherID int) ([]domain.Edition, error) {
+	if _, err := u.GetPublisherByID(publisherID); err != nil {
+		return nil, err
+	}
+	return u.filter(func(e domain.Edition) bool { return e.PublisherID == publisherID }), nil
+}
+
+func (u *EditionUsecase) filter(keep func(domain.Edition) bool) []domain.Edition {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	editions := []domain.Edition{}
+	for _, e := range u.editions {
+		if keep(e) {
+			editions = append(editions, e)
+		}
+	}
+	sort.SliceStable(editions, func(i, j int) bool { return editions[i].Year < editions[j].Year })
+	return editions
+}
+
+// publisherExists expects the caller to hold the lock.
+func (u *EditionUsecase) publisherExists(id int) bool {
+	for _, p := range u.publishers {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
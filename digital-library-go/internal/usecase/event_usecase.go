@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrEventStarted        = domain.Conflict("event has already started")
+	ErrAlreadyRegistered   = domain.Conflict("member is already registered for this event")
+	ErrRegistrationMissing = domain.NotFound("member is not registered for this event")
+)
+
+// EventUsecase manages library programs and members' registrations for
+// them. Registrations past an event's capacity go on a waitlist, which is
+// promoted in order as places free up.
+type EventUsecase struct {
+	mu            sync.RWMutex
+	events        []domain.Event
+	registrations []domain.Registration
+	nextID        int
+	members       *MemberUsecase
+	notify        *NotificationUsecase
+}
+
+func NewEventUsecase(members *MemberUsecase, notify *NotificationUsecase) *EventUsecase {
+	return &EventUsecase{
+		events:        []domain.Event{},
+		registrations: []domain.Registration{},
+		nextID:        1,
+		members:       members,
+		notify:        notify,
+	}
+}
+
+// GetEvents returns the events in start order. Unless past is set, events
+// that have ended are left out.
+func (u *EventUsecase) GetEvents(past bool) []domain.Event {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	now := time.Now()
+	events := []domain.Event{}
+	for _, e := range u.events {
+		if past || e.End.After(now) {
+			events = append(events, u.withCounts(e))
+		}
+	}
+	slices.SortFunc(events, func(a, b domain.Event) int { return a.Start.Compare(b.Start) })
+	return events
+}
+
+func (u *EventUsecase) GetEventByID(id int) (domain.Event, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Event{}, err
+	}
+	return u.withCounts(u.events[i]), nil
+}
+
+func (u *EventUsecase) CreateEvent(event domain.Event) domain.Event {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	event.ID = u.nextID
+	u.nextID++
+	u.events = append(u.events, event)
+	return u.withCounts(event)
+}
+
+// UpdateEvent replaces an event's details. Raising the capacity promotes
+// members from the waitlist; lowering it keeps everyone already
+// registered.
+func (u *EventUsecase) UpdateEvent(id int, updated domain.Event) error {
+	u.mu.Lock()
+	i, err := u.index(id)
+	if err != nil {
+		u.mu.Unlock()
+		return err
+	}
+	updated.ID = id
+	u.events[i] = updated
+	promoted := u.promote(id)
+	u.mu.Unlock()
+
+	for _, memberID := range promoted {
+		u.notify.Notify(memberID, domain.NotifyEvent, "You have a place at "+describeEvent(updated), 0)
+	}
+	return nil
+}
+
+// DeleteEvent cancels an event. Everyone registered or waitlisted is
+// notified.
+func (u *EventUsecase) DeleteEvent(id int) error {
+	u.mu.Lock()
+	i, err := u.index(id)
+	if err != nil {
+		u.mu.Unlock()
+		return err
+	}
+	event := u.events[i]
+	u.events = append(u.events[:i], u.events[i+1:]...)
+	members := []int{}
+	u.registrations = slices.DeleteFunc(u.registrations, func(r domain.Registration) bool {
+		if r.EventID != id {
+			return false
+		}
+		members = append(members, r.MemberID)
+		return true
+	})
+	u.mu.Unlock()
+
+	for _, memberID := range members {
+		u.notify.Notify(memberID, domain.NotifyEvent, "Event cancelled: "+describeEvent(event), 0)
+	}
+	return nil
+}
+
+// Register signs a member up for an event that has not started yet. The
+// registration is confirmed while there is room, and waitlisted after.
+func (u *EventUsecase) Register(eventID, memberID int) (domain.Registration, error) {
+	if _, err := u.members.GetMemberByID(memberID); err != nil {
+		return domain.Registration{}, err
+	}
+
+	u.mu.Lock()
+	i, err := u.index(eventID)
+	if err != nil {
+		u.mu.Unlock()
+		return domain.Registration{}, err
+	}
+	event := u.events[i]
+	now := time.Now()
+	if !event.Start.After(now) {
+		u.mu.Unlock()
+		return domain.Registration{}, ErrEventStarted
+	}
+	if _, ok := u.registration(eventID, memberID); ok {
+		u.mu.Unlock()
+		return domain.Registration{}, ErrAlreadyRegistered
+	}
+	reg := domain.Registration{EventID: eventID, MemberID: memberID, Status: domain.RegistrationConfirmed, RegisteredAt: now}
+	if u.withCounts(event).Registered >= event.Capacity {
+		reg.Status = domain.RegistrationWaitlisted
+	}
+	u.registrations = append(u.registrations, reg)
+	u.mu.Unlock()
+
+	if reg.Status == domain.RegistrationConfirmed {
+		u.notify.Notify(memberID, domain.NotifyEvent, "Registered for "+describeEvent(event), 0)
+	} else {
+		u.notify.Notify(memberID, domain.NotifyEvent, "Waitlisted for "+describeEvent(event), 0)
+	}
+	return reg, nil
+}
+
+// Unregister withdraws a member from an event or its waitlist. A freed
+// place goes to the first member on the waitlist, who is notified.
+func (u *EventUsecase) Unregister(eventID, memberID int) error {
+	u.mu.Lock()
+	i, err := u.index(eventID)
+	if err != nil {
+		u.mu.Unlock()
+		return err
+	}
+	event := u.events[i]
+	r, ok := u.registration(eventID, memberID)
+	if !ok {
+		u.mu.Unlock()
+		return ErrRegistrationMissing
+	}
+	u.registrations = slices.Delete(u.registrations, r, r+1)
+	promoted := u.promote(eventID)
+	u.mu.Unlock()
+
+	for _, id := range promoted {
+		u.notify.Notify(id, domain.NotifyEvent, "A place opened up; you are now registered for "+describeEvent(event), 0)
+	}
+	return nil
+}
+
+// RegistrationsForEvent returns the registrations and waitlist of an
+// event in the order they were made.
+func (u *EventUsecase) RegistrationsForEvent(eventID int) ([]domain.Registration, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if _, err := u.index(eventID); err != nil {
+		return nil, err
+	}
+	regs := []domain.Registration{}
+	for _, r := range u.registrations {
+		if r.EventID == eventID {
+			regs = append(regs, r)
+		}
+	}
+	return regs, nil
+}
+
+func (u *EventUsecase) RegistrationsForMember(memberID int) []domain.Registration {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	regs := []domain.Registration{}
+	for _, r := range u.registrations {
+		if r.MemberID == memberID {
+			regs = append(regs, r)
+		}
+	}
+	return regs
+}
+
+// SendReminders notifies registered members of events starting within
+// EventReminderLead of now, once per registration. It returns how many
+// reminders were sent.
+func (u *EventUsecase) SendReminders(now time.Time) int {
+	type reminder struct {
+		memberID int
+		event    domain.Event
+	}
+	due := []reminder{}
+
+	u.mu.Lock()
+	for i, r := range u.registrations {
+		if r.Status != domain.RegistrationConfirmed || r.RemindedAt != nil {
+			continue
+		}
+		e, err := u.index(r.EventID)
+		if err != nil {
+			continue
+		}
+		event := u.events[e]
+		if event.Start.After(now) && !event.Start.After(now.Add(domain.EventReminderLead)) {
+			u.registrations[i].RemindedAt = &now
+			due = append(due, reminder{memberID: r.MemberID, event: event})
+		}
+	}
+	u.mu.Unlock()
+
+	for _, d := range due {
+		u.notify.Notify(d.memberID, domain.NotifyEventReminder, "Reminder: "+describeEvent(d.event), 0)
+	}
+	return len(due)
+}
+
+// DeleteRegistrationsForMember removes every registration of a member,
+// for account erasure. Freed places are not offered to the waitlist.
+func (u *EventUsecase) DeleteRegistrationsForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.registrations = slices.DeleteFunc(u.registrations, func(r domain.Registration) bool {
+		return r.MemberID == memberID
+	})
+}
+
+func describeEvent(e domain.Event) string {
+	start := e.Start.Local()
+	s := fmt.Sprintf("%s on %s at %s", e.Title, start.Format(time.DateOnly), start.Format("15:04"))
+	if e.Location != "" {
+		s += " in " + e.Location
+	}
+	return s
+}
+
+// promote confirms waitlisted registrations of an event while it has
+// room, and returns the promoted members. It expects the caller to hold
+// the lock.
+func (u *EventUsecase) promote(eventID int) []int {
+	i, err := u.index(eventID)
+	if err != nil {
+		return nil
+	}
+	free := u.events[i].Capacity - u.withCounts(u.events[i]).Registered
+	promoted := []int{}
+	for r := range u.registrations {
+		if free <= 0 {
+			break
+		}
+		if u.registrations[r].EventID == eventID && u.registrations[r].Status == domain.RegistrationWaitlisted {
+			u.registrations[r].Status = domain.RegistrationConfirmed
+			promoted = append(promoted, u.registrations[r].MemberID)
+			free--
+		}
+	}
+	return promoted
+}
+
+// withCounts fills in the registration counts of an event. It expects
+// the caller to hold the lock.
+func (u *EventUsecase) withCounts(e domain.Event) domain.Event {
+	e.Registered, e.Waitlisted = 0, 0
+	for _, r := range u.registrations {
+		if r.EventID != e.ID {
+			continue
+		}
+		if r.Status == domain.RegistrationConfirmed {
+			e.Registered++
+		} else {
+			e.Waitlisted++
+		}
+	}
+	return e
+}
+
+// registration expects the caller to hold the lock.
+func (u *EventUsecase) registration(eventID, memberID int) (int, bool) {
+	for i, r := range u.registrations {
+		if r.EventID == eventID && r.MemberID == memberID {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// index expects the caller to hold the lock.
+func (u *EventUsecase) index(id int) (int, error) {
+	for i, e := range u.events {
+		if e.ID == id {
+			return i, nil
+		}
+	}
+	return 0, domain.NotFound("event not found")
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/bookexport"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/s3"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/sftp"
+)
+
+var (
+	ErrExportJobNotFound = domain.NotFound("export job not found")
+	ErrExportRunning     = domain.Conflict("export job is already running")
+)
+
+// exportTimeout bounds one upload.
+const exportTimeout = 10 * time.Minute
+
+var exportContentTypes = map[string]string{
+	domain.ExportCSV:  "text/csv; charset=utf-8",
+	domain.ExportJSON: "application/json",
+	domain.ExportMARC: "application/marc",
+}
+
+// ExportUsecase keeps the scheduled catalog exports and runs them. Every
+// admin is alerted when a run fails, and when one succeeds if the job
+// asks for it.
+type ExportUsecase struct {
+	mu            sync.RWMutex
+	jobs          []domain.ExportJob
+	nextID        int
+	running       map[int]bool
+	books         *BookUsecase
+	members       *MemberUsecase
+	notifications *NotificationUsecase
+	s3            *s3.Client
+}
+
+func NewExportUsecase(books *BookUsecase, members *MemberUsecase, notifications *NotificationUsecase, s3Client *s3.Client) *ExportUsecase {
+	return &ExportUsecase{
+		jobs:          []domain.ExportJob{},
+		nextID:        1,
+		running:       map[int]bool{},
+		books:         books,
+		members:       members,
+		notifications: notifications,
+		s3:            s3Client,
+	}
+}
+
+// GetJobs lists the jobs without their secrets.
+func (u *ExportUsecase) GetJobs() []domain.ExportJob {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	jobs := make([]domain.ExportJob, 0, len(u.jobs))
+	for _, j := range u.jobs {
+		jobs = append(jobs, j.Redacted())
+	}
+	return jobs
+}
+
+// GetJobByID returns a job without its secrets.
+func (u *ExportUsecase) GetJobByID(id int) (domain.ExportJob, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i := u.find(id)
+	if i < 0 {
+		return domain.ExportJob{}, ErrExportJobNotFound
+	}
+	return u.jobs[i].Redacted(), nil
+}
+
+// CreateJob adds a job, which first runs at its hour after now.
+func (u *ExportUsecase) CreateJob(job domain.ExportJob, now time.Time) (domain.ExportJob, error) {
+	if err := job.Validate(); err != nil {
+		return domain.ExportJob{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	job.ID = u.nextID
+	u.nextID++
+	job.LastRun = nil
+	job.NextRun = nextExport(job.Hour, now)
+	u.jobs = append(u.jobs, job)
+	return job.Redacted(), nil
+}
+
+// UpdateJob replaces a job's settings. Secrets left empty keep their
+// old values as long as the destination type is unchanged, so clients
+// can edit a job they only ever see redacted.
+func (u *ExportUsecase) UpdateJob(id int, job domain.ExportJob, now time.Time) (domain.ExportJob, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.find(id)
+	if i < 0 {
+		return domain.ExportJob{}, ErrExportJobNotFound
+	}
+	old := u.jobs[i]
+	if job.Destination.Type == old.Destination.Type {
+		d := &job.Destination
+		if d.Password == "" && d.PrivateKey == "" {
+			d.Password, d.PrivateKey = old.Destination.Password, old.Destination.PrivateKey
+		}
+		if d.SecretAccessKey == "" {
+			d.SecretAccessKey = old.Destination.SecretAccessKey
+		}
+	}
+	if err := job.Validate(); err != nil {
+		return domain.ExportJob{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	job.ID = id
+	job.LastRun = old.LastRun
+	job.NextRun = nextExport(job.Hour, now)
+	u.jobs[i] = job
+	return job.Redacted(), nil
+}
+
+func (u *ExportUsecase) DeleteJob(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.find(id)
+	if i < 0 {
+		return ErrExportJobNotFound
+	}
+	u.jobs = append(u.jobs[:i], u.jobs[i+1:]...)
+	return nil
+}
+
+// Run exports and uploads a job's catalog now, whether or not it is
+// enabled, without moving its next scheduled run. A failed upload is
+// reported in the run rather than as an error.
+func (u *ExportUsecase) Run(id int, now time.Time) (domain.ExportRun, error) {
+	u.mu.Lock()
+	i := u.find(id)
+	if i < 0 {
+		u.mu.Unlock()
+		return domain.ExportRun{}, ErrExportJobNotFound
+	}
+	if u.running[id] {
+		u.mu.Unlock()
+		return domain.ExportRun{}, ErrExportRunning
+	}
+	u.running[id] = true
+	job := u.jobs[i]
+	u.mu.Unlock()
+
+	return u.run(job, now), nil
+}
+
+// RunDue runs every enabled job whose scheduled time has passed, one
+// after another, and schedules each for the following night. It is
+// called periodically from main.
+func (u *ExportUsecase) RunDue(now time.Time) {
+	u.mu.Lock()
+	due := []domain.ExportJob{}
+	for i, j := range u.jobs {
+		if j.Enabled && !now.Before(j.NextRun) && !u.running[j.ID] {
+			u.jobs[i].NextRun = nextExport(j.Hour, now)
+			u.running[j.ID] = true
+			due = append(due, j)
+		}
+	}
+	u.mu.Unlock()
+
+	for _, j := range due {
+		u.run(j, time.Now())
+	}
+}
+
+// run exports the catalog for job, uploads it, records the outcome and
+// sends the alerts. The caller must have marked the job running.
+func (u *ExportUsecase) run(job domain.ExportJob, now time.Time) domain.ExportRun {
+	run := domain.ExportRun{At: now, File: fmt.Sprintf("catalog-%s.%s", now.UTC().Format("20060102"), domain.ExportExtension(job.Format))}
+
+	books := []domain.Book{}
+	for _, b := range u.books.GetBooks() {
+		if b.Published() {
+			books = append(books, b)
+		}
+	}
+	run.Books = len(books)
+
+	var buf bytes.Buffer
+	err := bookexport.Write(&buf, job.Format, books)
+	if err == nil {
+		run.Bytes = buf.Len()
+		err = u.upload(job, run.File, buf.Bytes())
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	u.mu.Lock()
+	delete(u.running, job.ID)
+	if i := u.find(job.ID); i >= 0 {
+		u.jobs[i].LastRun = &run
+	}
+	u.mu.Unlock()
+
+	u.alert(job, run)
+	return run
+}
+
+func (u *ExportUsecase) upload(job domain.ExportJob, file string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	d := job.Destination
+	key := path.Join(d.Path, file)
+
+	if d.Type == domain.DestinationS3 {
+		bucket := s3.Bucket{
+			Name:            d.Bucket,
+			Region:          d.Region,
+			Endpoint:        d.Endpoint,
+			AccessKeyID:     d.AccessKeyID,
+			SecretAccessKey: d.SecretAccessKey,
+		}
+		return u.s3.Put(ctx, bucket, key, exportContentTypes[job.Format], data)
+	}
+
+	port := d.Port
+	if port == 0 {
+		port = 22
+	}
+	cfg := sftp.Config{
+		Addr:       net.JoinHostPort(d.Host, strconv.Itoa(port)),
+		User:       d.User,
+		Password:   d.Password,
+		PrivateKey: d.PrivateKey,
+		HostKey:    d.HostKey,
+	}
+	return sftp.Upload(ctx, cfg, key, data)
+}
+
+// alert logs the outcome of a run and notifies the admins.
+func (u *ExportUsecase) alert(job domain.ExportJob, run domain.ExportRun) {
+	var message string
+	if run.Error != "" {
+		message = fmt.Sprintf("Export %q failed: %s", job.Name, run.Error)
+	} else {
+		message = fmt.Sprintf("Export %q uploaded %s with %d books.", job.Name, run.File, run.Books)
+	}
+	log.Println(message)
+	if run.Error == "" && !job.NotifySuccess {
+		return
+	}
+	for _, m := range u.members.GetMembers() {
+		if m.Role == domain.RoleAdmin {
+			u.notifications.Notify(m.ID, domain.NotifyExport, message, 0)
+		}
+	}
+}
+
+// find expects the caller to hold the lock.
+func (u *ExportUsecase) find(id int) int {
+	for i, j := range u.jobs {
+		if j.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextExport is the first time after now that the clock reads hour:00
+// UTC.
+func nextExport(hour int, now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// DefaultFederationTimeout is how long a search waits for partners.
+const DefaultFederationTimeout = 3 * time.Second
+
+// maxRemoteResults is how many books of each partner a search lists.
+const maxRemoteResults = 10
+
+var (
+	ErrPartnerNotFound    = domain.NotFound("partner not found")
+	ErrDuplicateILL       = domain.Conflict("member already requested this book from the partner")
+	ErrTitleHeldLocally   = domain.Conflict("the library has this title; place a hold on it instead")
+	ErrPartnerUnreachable = domain.Unavailable("partner could not be reached")
+)
+
+// FederationPartner is the catalog of a consortium partner running this
+// server.
+type FederationPartner interface {
+	Name() string
+	Search(ctx context.Context, q, audience string) ([]domain.RemoteBook, error)
+	Book(ctx context.Context, id int) (domain.RemoteBook, error)
+	// Request places a hold on a book for this library and returns the
+	// partner's ID for it.
+	Request(ctx context.Context, bookID int) (string, error)
+}
+
+// FederationUsecase searches the catalogs of consortium partners for
+// titles this library lacks and keeps the interlibrary loan requests
+// members make for them.
+type FederationUsecase struct {
+	mu       sync.RWMutex
+	requests []domain.ILLRequest
+	// pending are the requests being sent to partners.
+	pending  map[illKey]bool
+	nextID   int
+	partners []FederationPartner
+	books    *BookUsecase
+	members  *MemberUsecase
+	// timeout bounds a search across the partners.
+	timeout time.Duration
+}
+
+func NewFederationUsecase(partners []FederationPartner, books *BookUsecase, members *MemberUsecase, timeout time.Duration) *FederationUsecase {
+	return &FederationUsecase{
+		requests: []domain.ILLRequest{},
+		pending:  map[illKey]bool{},
+		nextID:   1,
+		partners: partners,
+		books:    books,
+		members:  members,
+		timeout:  timeout,
+	}
+}
+
+// Search asks every partner at once and returns, in the order the
+// partners are configured, up to maxRemoteResults of each partner's
+// books that this library does not have. A book several partners have
+// is listed once, from the first. Partners that fail or do not answer
+// in time are left out.
+func (u *FederationUsecase) Search(ctx context.Context, q, audience string) []domain.RemoteBook {
+	if len(u.partners) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	found := make([][]domain.RemoteBook, len(u.partners))
+	var wg sync.WaitGroup
+	for i, p := range u.partners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			books, err := p.Search(ctx, q, audience)
+			if err != nil {
+				log.Printf("Federation: searching %s failed: %v", p.Name(), err)
+				return
+			}
+			found[i] = books
+		}()
+	}
+	wg.Wait()
+
+	remote := []domain.RemoteBook{}
+	seen := map[string]bool{}
+	for _, books := range found {
+		listed := 0
+		for _, b := range books {
+			if listed == maxRemoteResults {
+				break
+			}
+			key, ok := domain.ISBN13(b.ISBN)
+			if ok {
+				if _, held := u.books.BookByISBN(key); held || seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			remote = append(remote, b)
+			listed++
+		}
+	}
+	return remote
+}
+
+// illKey is a member's request for a book of a partner.
+type illKey struct {
+	memberID int
+	partner  string
+	bookID   int
+}
+
+// Request asks a partner to lend one of its books to a member, through
+// this library. Titles the library has are refused, as is asking twice.
+// The request is reserved before the partner is asked, so that asking
+// twice at once sends it only once.
+func (u *FederationUsecase) Request(ctx context.Context, memberID int, partner string, bookID int, now time.Time) (domain.ILLRequest, error) {
+	if _, err := u.members.GetMemberByID(memberID); err != nil {
+		return domain.ILLRequest{}, err
+	}
+	i := slices.IndexFunc(u.partners, func(p FederationPartner) bool { return p.Name() == partner })
+	if i < 0 {
+		return domain.ILLRequest{}, ErrPartnerNotFound
+	}
+	p := u.partners[i]
+
+	key := illKey{memberID, partner, bookID}
+	u.mu.Lock()
+	duplicate := u.pending[key] || slices.ContainsFunc(u.requests, func(r domain.ILLRequest) bool {
+		return r.MemberID == memberID && r.Partner == partner && r.RemoteBookID == bookID
+	})
+	if duplicate {
+		u.mu.Unlock()
+		return domain.ILLRequest{}, ErrDuplicateILL
+	}
+	u.pending[key] = true
+	u.mu.Unlock()
+	defer func() {
+		u.mu.Lock()
+		delete(u.pending, key)
+		u.mu.Unlock()
+	}()
+
+	book, err := p.Book(ctx, bookID)
+	if err != nil {
+		return domain.ILLRequest{}, unreachable(partner, err)
+	}
+	if _, held := u.books.BookByISBN(book.ISBN); held {
+		return domain.ILLRequest{}, ErrTitleHeldLocally
+	}
+	remoteID, err := p.Request(ctx, bookID)
+	if err != nil {
+		return domain.ILLRequest{}, unreachable(partner, err)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	request := domain.ILLRequest{
+		ID:              u.nextID,
+		MemberID:        memberID,
+		Partner:         partner,
+		RemoteBookID:    bookID,
+		Title:           book.Title,
+		Author:          book.Author,
+		ISBN:            book.ISBN,
+		RemoteRequestID: remoteID,
+		RequestedAt:     now,
+	}
+	u.nextID++
+	u.requests = append(u.requests, request)
+	return request, nil
+}
+
+// GetRequests returns the interlibrary loan requests of a member, or of
+// everyone when memberID is 0.
+func (u *FederationUsecase) GetRequests(memberID int) []domain.ILLRequest {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	requests := []domain.ILLRequest{}
+	for _, r := range u.requests {
+		if memberID == 0 || r.MemberID == memberID {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// unreachable passes on the errors a partner answered with and reports
+// any other failure as the partner being unreachable.
+func unreachable(partner string, err error) error {
+	if domain.KindOf(err) != nil {
+		return err
+	}
+	log.Printf("Federation: %s failed: %v", partner, err)
+	return ErrPartnerUnreachable
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+)
+
+var (
+	ErrFieldNotFound    = domain.NotFound("field not found")
+	ErrUnknownField     = domain.Invalid("unknown custom field")
+	ErrOtherTenantField = domain.Invalid("custom field belongs to another tenant")
+)
+
+// FieldUsecase manages the custom metadata fields books may carry in
+// their attributes, and validates and filters attributes against them.
+// Fields are defined for one tenant, the host name a site is served on,
+// or for every tenant. The catalog is shared, so a book may carry values
+// of several tenants' fields, and field names are unique across tenants.
+type FieldUsecase struct {
+	mu     sync.RWMutex
+	fields []domain.FieldDefinition
+	books  *BookUsecase
+}
+
+func NewFieldUsecase(books *BookUsecase) *FieldUsecase {
+	return &FieldUsecase{
+		fields: []domain.FieldDefinition{},
+		books:  books,
+	}
+}
+
+// GetFields returns the fields of every tenant.
+func (u *FieldUsecase) GetFields() []domain.FieldDefinition {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.FieldDefinition(nil), u.fields...)
+}
+
+// GetField returns a field defined for tenant, or for every tenant when
+// tenant is empty.
+func (u *FieldUsecase) GetField(tenant, name string) (domain.FieldDefinition, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i := u.index(featureflag.Tenant(tenant), name)
+	if i < 0 {
+		return domain.FieldDefinition{}, ErrFieldNotFound
+	}
+	return u.fields[i], nil
+}
+
+func (u *FieldUsecase) CreateField(field domain.FieldDefinition) error {
+	field.Tenant = featureflag.Tenant(field.Tenant)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.find(field.Name); ok {
+		return domain.Conflict("field with this name already exists")
+	}
+	u.fields = append(u.fields, field)
+	return nil
+}
+
+// UpdateField replaces a field definition. Existing values are not
+// revalidated; books are checked against the new definition the next
+// time they are saved.
+func (u *FieldUsecase) UpdateField(name string, updated domain.FieldDefinition) error {
+	updated.Tenant = featureflag.Tenant(updated.Tenant)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.index(updated.Tenant, name)
+	if i < 0 {
+		return ErrFieldNotFound
+	}
+	updated.Name = name
+	u.fields[i] = updated
+	return nil
+}
+
+// DeleteField removes a field definition along with its values on all
+// books.
+func (u *FieldUsecase) DeleteField(tenant, name string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.index(featureflag.Tenant(tenant), name)
+	if i < 0 {
+		return ErrFieldNotFound
+	}
+	u.fields = append(u.fields[:i], u.fields[i+1:]...)
+	u.books.RemoveAttribute(name)
+	return nil
+}
+
+// ValidateAttributes checks the attributes a tenant saves on a book and
+// returns those to store. Every value must belong to a field the tenant
+// has and have its type, and the tenant's required fields must be set.
+// Values of other tenants' fields are kept from current, the book's
+// attributes before the save; attrs may repeat them but not change them.
+func (u *FieldUsecase) ValidateAttributes(tenant string, attrs, current map[string]any) (map[string]any, error) {
+	tenant = featureflag.Tenant(tenant)
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	saved := map[string]any{}
+	for name, value := range attrs {
+		f, ok := u.find(name)
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		case !f.For(tenant) && !reflect.DeepEqual(value, current[name]):
+			return nil, fmt.Errorf("%w: %s", ErrOtherTenantField, name)
+		case f.For(tenant):
+			if err := f.Check(value); err != nil {
+				return nil, domain.Wrap(domain.ErrInvalid, err)
+			}
+		}
+		saved[name] = value
+	}
+	for _, f := range u.fields {
+		if _, ok := attrs[f.Name]; f.Required && f.For(tenant) && !ok {
+			return nil, domain.Invalid(fmt.Sprintf("attribute %s is required", f.Name))
+		}
+		if value, ok := current[f.Name]; ok && !f.For(tenant) {
+			saved[f.Name] = value
+		}
+	}
+	if len(saved) == 0 {
+		return nil, nil
+	}
+	return saved, nil
+}
+
+// Filter keeps the books whose attributes match every filter, given as
+// field name to query value. Only the tenant's fields can be filtered on.
+func (u *FieldUsecase) Filter(tenant string, books []domain.Book, filters map[string]string) ([]domain.Book, error) {
+	tenant = featureflag.Tenant(tenant)
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	defs := make(map[string]domain.FieldDefinition, len(filters))
+	for name := range filters {
+		f, ok := u.find(name)
+		if !ok || !f.For(tenant) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+		defs[name] = f
+	}
+
+	matched := []domain.Book{}
+	for _, b := range books {
+		keep := true
+		for name, filter := range filters {
+			f := defs[name]
+			if !f.Matches(b.Attributes[name], filter) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			matched = append(matched, b)
+		}
+	}
+	return matched, nil
+}
+
+// find looks a field up by name, whichever tenant it is defined for. It
+// expects the caller to hold the lock.
+func (u *FieldUsecase) find(name string) (domain.FieldDefinition, bool) {
+	for _, f := range u.fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return domain.FieldDefinition{}, false
+}
+
+// index finds the field defined for exactly tenant, or -1. It expects
+// the caller to hold the lock.
+func (u *FieldUsecase) index(tenant, name string) int {
+	for i, f := range u.fields {
+		if f.Name == name && f.Tenant == tenant {
+			return i
+		}
+	}
+	return -1
+}
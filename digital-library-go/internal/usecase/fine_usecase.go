@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// FinePerDay is charged for every day a loan is returned late, in cents.
+const FinePerDay = 25
+
+var (
+	ErrFineNotFound = domain.NotFound("fine not found")
+	ErrFinePaid     = domain.Conflict("fine is already paid")
+)
+
+type FineUsecase struct {
+	mu     sync.RWMutex
+	fines  []domain.Fine
+	nextID int
+}
+
+func NewFineUsecase() *FineUsecase {
+	return &FineUsecase{
+		fines:  []domain.Fine{},
+		nextID: 1,
+	}
+}
+
+func (u *FineUsecase) GetFines() []domain.Fine {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Fine(nil), u.fines...)
+}
+
+func (u *FineUsecase) FinesForMember(memberID int) []domain.Fine {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	fines := []domain.Fine{}
+	for _, f := range u.fines {
+		if f.MemberID == memberID {
+			fines = append(fines, f)
+		}
+	}
+	return fines
+}
+
+// AnonymizeMember detaches the member from their fines, keeping the
+// amounts for reporting.
+func (u *FineUsecase) AnonymizeMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, f := range u.fines {
+		if f.MemberID == memberID {
+			u.fines[i].MemberID = 0
+		}
+	}
+}
+
+// AssessOverdue charges the member for a loan returned after its due
+// date. Loans returned on time are ignored.
+func (u *FineUsecase) AssessOverdue(loan domain.Loan) {
+	if loan.ReturnedAt == nil || !loan.ReturnedAt.After(loan.DueAt) {
+		return
+	}
+	days := int(loan.ReturnedAt.Sub(loan.DueAt)/(24*time.Hour)) + 1
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.fines = append(u.fines, domain.Fine{
+		ID:         u.nextID,
+		MemberID:   loan.MemberID,
+		LoanID:     loan.ID,
+		Amount:     days * FinePerDay,
+		Reason:     "overdue",
+		AssessedAt: *loan.ReturnedAt,
+	})
+	u.nextID++
+}
+
+// UnpaidFines returns the member's fines with the given IDs, or all of
+// their unpaid fines when ids is empty. Every listed fine must be the
+// member's and unpaid.
+func (u *FineUsecase) UnpaidFines(memberID int, ids []int) ([]domain.Fine, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if len(ids) == 0 {
+		fines := []domain.Fine{}
+		for _, f := range u.fines {
+			if f.MemberID == memberID && f.PaidAt == nil {
+				fines = append(fines, f)
+			}
+		}
+		return fines, nil
+	}
+
+	fines := []domain.Fine{}
+	for _, id := range ids {
+		i := u.index(id)
+		if i < 0 || u.fines[i].MemberID != memberID {
+			return nil, ErrFineNotFound
+		}
+		if u.fines[i].PaidAt != nil {
+			return nil, ErrFinePaid
+		}
+		fines = append(fines, u.fines[i])
+	}
+	return fines, nil
+}
+
+// MarkPaid records fines as paid by an online payment. Fines paid in the
+// meantime keep their earlier payment.
+func (u *FineUsecase) MarkPaid(ids []int, paymentID int, at time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, id := range ids {
+		if i := u.index(id); i >= 0 && u.fines[i].PaidAt == nil {
+			u.fines[i].PaidAt = &at
+			u.fines[i].PaymentID = paymentID
+		}
+	}
+}
+
+// index expects the caller to hold the lock.
+func (u *FineUsecase) index(id int) int {
+	for i, f := range u.fines {
+		if f.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrHoldNotFound     = domain.NotFound("hold not found")
+	ErrHoldLimitReached = domain.Conflict("hold limit reached for membership plan")
+	ErrDuplicateHold    = domain.Conflict("member already holds this book")
+	ErrBookOnHold       = domain.Conflict("book is waiting on the hold shelf for another member")
+)
+
+// HoldUsecase keeps the hold queue of each book. When a book comes back,
+// the first waiting hold becomes ready for pickup; if it is not picked up
+// within the pickup window, it expires and the next member is promoted.
+// A hold picked up from a branch without a copy waits in transit for
+// one to be sent there.
+type HoldUsecase struct {
+	mu       sync.RWMutex
+	holds    []domain.Hold
+	nextID   int
+	books    *BookUsecase
+	members  *MemberUsecase
+	branches *BranchUsecase
+	copies   *CopyUsecase
+	calendar *CalendarUsecase
+	notify   *NotificationUsecase
+	// pickupWindow is how long a ready hold waits on the shelf.
+	pickupWindow time.Duration
+}
+
+// holdChange is a notification to send once the lock is released.
+type holdChange struct {
+	hold domain.Hold
+	kind string
+}
+
+func NewHoldUsecase(books *BookUsecase, members *MemberUsecase, branches *BranchUsecase, copies *CopyUsecase, calendar *CalendarUsecase, notify *NotificationUsecase, pickupWindow time.Duration) *HoldUsecase {
+	return &HoldUsecase{
+		holds:        []domain.Hold{},
+		nextID:       1,
+		books:        books,
+		members:      members,
+		branches:     branches,
+		copies:       copies,
+		calendar:     calendar,
+		notify:       notify,
+		pickupWindow: pickupWindow,
+	}
+}
+
+func (u *HoldUsecase) GetHolds() []domain.Hold {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Hold(nil), u.holds...)
+}
+
+func (u *HoldUsecase) GetHoldByID(id int) (domain.Hold, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i := slices.IndexFunc(u.holds, func(h domain.Hold) bool { return h.ID == id })
+	if i < 0 {
+		return domain.Hold{}, ErrHoldNotFound
+	}
+	return u.holds[i], nil
+}
+
+func (u *HoldUsecase) HoldsForMember(memberID int) []domain.Hold {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	holds := []domain.Hold{}
+	for _, h := range u.holds {
+		if h.MemberID == memberID {
+			holds = append(holds, h)
+		}
+	}
+	return holds
+}
+
+// QueueForMember returns the member's holds with their positions, all
+// taken from the same state of the queues.
+func (u *HoldUsecase) QueueForMember(memberID int) []domain.QueuedHold {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	queue := []domain.QueuedHold{}
+	for _, h := range u.holds {
+		if h.MemberID != memberID {
+			continue
+		}
+		position := 1
+		for _, other := range u.holds {
+			if other.BookID == h.BookID && other.PlacedAt.Before(h.PlacedAt) {
+				position++
+			}
+		}
+		queue = append(queue, domain.QueuedHold{Hold: h, Position: position})
+	}
+	return queue
+}
+
+// PlaceHold queues a member for a book, enforcing the hold limit of the
+// member's plan. pickupBranchID is the branch they collect it from, or 0
+// for none. Members whose account is scheduled for deletion cannot place
+// holds.
+func (u *HoldUsecase) PlaceHold(memberID, bookID, pickupBranchID int) (domain.Hold, error) {
+	plan, err := u.members.BorrowingPlan(memberID)
+	if err != nil {
+		return domain.Hold{}, err
+	}
+	if book, err := u.books.GetBookByID(bookID); err != nil || !book.Published() {
+		return domain.Hold{}, ErrBookNotFound
+	}
+	if pickupBranchID != 0 {
+		if _, err := u.branches.GetBranchByID(pickupBranchID); err != nil {
+			return domain.Hold{}, err
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	count := 0
+	for _, h := range u.holds {
+		if h.MemberID != memberID {
+			continue
+		}
+		if h.BookID == bookID {
+			return domain.Hold{}, ErrDuplicateHold
+		}
+		count++
+	}
+	if count >= plan.MaxHolds {
+		return domain.Hold{}, ErrHoldLimitReached
+	}
+
+	hold := domain.Hold{
+		ID:             u.nextID,
+		BookID:         bookID,
+		MemberID:       memberID,
+		PickupBranchID: pickupBranchID,
+		PlacedAt:       time.Now(),
+		Status:         domain.HoldWaiting,
+	}
+	u.nextID++
+	u.holds = append(u.holds, hold)
+	return hold, nil
+}
+
+// CancelHold removes a hold. Cancelling a ready or in transit hold
+// passes the book on to the next member in the queue.
+func (u *HoldUsecase) CancelHold(id int) error {
+	return u.cancel(func(h domain.Hold) bool { return h.ID == id })
+}
+
+// CancelMemberHold removes one of the member's own holds. Holds of other
+// members are reported as not found.
+func (u *HoldUsecase) CancelMemberHold(memberID, id int) error {
+	return u.cancel(func(h domain.Hold) bool { return h.ID == id && h.MemberID == memberID })
+}
+
+func (u *HoldUsecase) cancel(match func(domain.Hold) bool) error {
+	u.mu.Lock()
+	i := slices.IndexFunc(u.holds, match)
+	if i < 0 {
+		u.mu.Unlock()
+		return ErrHoldNotFound
+	}
+	changes := u.remove([]int{u.holds[i].ID}, time.Now())
+	u.mu.Unlock()
+
+	u.send(changes)
+	return nil
+}
+
+// CancelHoldsForMember removes all of a member's holds, for account
+// erasure. Books they had ready go to the next member in line.
+func (u *HoldUsecase) CancelHoldsForMember(memberID int) {
+	u.mu.Lock()
+	ids := []int{}
+	for _, h := range u.holds {
+		if h.MemberID == memberID {
+			ids = append(ids, h.ID)
+		}
+	}
+	changes := u.remove(ids, time.Now())
+	u.mu.Unlock()
+
+	u.send(changes)
+}
+
+// BookReturned makes the first waiting hold on a returned book ready for
+// pickup and tells its member, or sends a copy to its pickup branch.
+// Nothing changes if the book is set aside for a hold already.
+func (u *HoldUsecase) BookReturned(bookID int, now time.Time) {
+	u.mu.Lock()
+	var changes []holdChange
+	if !slices.ContainsFunc(u.holds, func(h domain.Hold) bool { return h.BookID == bookID && h.Promoted() }) {
+		if hold, ok := u.promote(bookID, now); ok {
+			changes = append(changes, holdChange{hold: hold, kind: domain.NotifyHoldReady})
+		}
+	}
+	u.mu.Unlock()
+
+	u.send(changes)
+}
+
+// ExpireReady removes ready holds whose pickup window ended before now
+// and promotes the next member in each queue. It returns how many holds
+// expired.
+func (u *HoldUsecase) ExpireReady(now time.Time) int {
+	u.mu.Lock()
+	expired := []int{}
+	for _, h := range u.holds {
+		if h.Status == domain.HoldReady && h.ExpiresAt.Before(now) {
+			expired = append(expired, h.ID)
+		}
+	}
+	changes := u.remove(expired, now)
+	u.mu.Unlock()
+
+	u.send(changes)
+	return len(expired)
+}
+
+// Fulfil is called when a member borrows a book. Their hold on it is
+// done with, and nobody else may borrow a book that is set aside for
+// another member.
+func (u *HoldUsecase) Fulfil(memberID, bookID int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, h := range u.holds {
+		if h.BookID == bookID && h.Promoted() && h.MemberID != memberID {
+			return ErrBookOnHold
+		}
+	}
+	u.holds = slices.DeleteFunc(u.holds, func(h domain.Hold) bool {
+		if h.BookID != bookID || h.MemberID != memberID {
+			return false
+		}
+		if h.Status == domain.HoldInTransit {
+			u.copies.ReleaseHold(h.ID)
+		}
+		return true
+	})
+	return nil
+}
+
+// ReceiveTransfer records that a copy in transit arrived at its branch.
+// If it was sent for a hold, the hold becomes ready for pickup there and
+// its member is told.
+func (u *HoldUsecase) ReceiveTransfer(copyID int, now time.Time) (domain.Copy, error) {
+	copy, transfer, err := u.copies.ReceiveTransfer(copyID)
+	if err != nil || transfer.HoldID == 0 {
+		return copy, err
+	}
+
+	u.mu.Lock()
+	var changes []holdChange
+	i := slices.IndexFunc(u.holds, func(h domain.Hold) bool { return h.ID == transfer.HoldID })
+	if i >= 0 && u.holds[i].Status == domain.HoldInTransit {
+		u.ready(i, now)
+		changes = append(changes, holdChange{hold: u.holds[i], kind: domain.NotifyHoldReady})
+	}
+	u.mu.Unlock()
+
+	u.send(changes)
+	return copy, nil
+}
+
+// Wanted reports whether a member other than memberID holds the book,
+// waiting or ready.
+func (u *HoldUsecase) Wanted(bookID, memberID int) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, h := range u.holds {
+		if h.BookID == bookID && h.MemberID != memberID {
+			return true
+		}
+	}
+	return false
+}
+
+// remove deletes the given holds and promotes the next member for each
+// ready or in transit hold among them. Expired holds are reported to
+// their members. It expects the caller to hold the lock.
+func (u *HoldUsecase) remove(ids []int, now time.Time) []holdChange {
+	changes := []holdChange{}
+	freed := []int{}
+	u.holds = slices.DeleteFunc(u.holds, func(h domain.Hold) bool {
+		if !slices.Contains(ids, h.ID) {
+			return false
+		}
+		if h.Promoted() {
+			freed = append(freed, h.BookID)
+		}
+		switch {
+		case h.Status == domain.HoldInTransit:
+			u.copies.ReleaseHold(h.ID)
+		case h.Status == domain.HoldReady && !h.ExpiresAt.After(now):
+			changes = append(changes, holdChange{hold: h, kind: domain.NotifyHoldExpired})
+		}
+		return true
+	})
+	for _, bookID := range freed {
+		if hold, ok := u.promote(bookID, now); ok {
+			changes = append(changes, holdChange{hold: hold, kind: domain.NotifyHoldReady})
+		}
+	}
+	return changes
+}
+
+// promote makes the oldest waiting hold on a book ready for pickup. If a
+// copy has to be sent to its pickup branch first, the hold is in transit
+// instead and promote reports false, as there is nothing to tell the
+// member yet. It expects the caller to hold the lock.
+func (u *HoldUsecase) promote(bookID int, now time.Time) (domain.Hold, bool) {
+	next := -1
+	for i, h := range u.holds {
+		if h.BookID == bookID && h.Status == domain.HoldWaiting && (next < 0 || h.PlacedAt.Before(u.holds[next].PlacedAt)) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return domain.Hold{}, false
+	}
+	if h := u.holds[next]; h.PickupBranchID != 0 && u.copies.SendForHold(bookID, h.PickupBranchID, h.ID, now) {
+		u.holds[next].Status = domain.HoldInTransit
+		return u.holds[next], false
+	}
+	u.ready(next, now)
+	return u.holds[next], true
+}
+
+// ready puts the i-th hold on the hold shelf for its pickup window. It
+// expects the caller to hold the lock.
+func (u *HoldUsecase) ready(i int, now time.Time) {
+	expires := u.calendar.NextOpenDay(now.Add(u.pickupWindow))
+	u.holds[i].Status = domain.HoldReady
+	u.holds[i].ReadyAt = &now
+	u.holds[i].ExpiresAt = &expires
+}
+
+func (u *HoldUsecase) send(changes []holdChange) {
+	for _, c := range changes {
+		title := fmt.Sprintf("book %d", c.hold.BookID)
+		if book, err := u.books.GetBookByID(c.hold.BookID); err == nil {
+			title = book.Title
+		}
+		where := ""
+		if branch, err := u.branches.GetBranchByID(c.hold.PickupBranchID); err == nil {
+			where = " at " + branch.Name
+		}
+		msg := fmt.Sprintf("Your hold is ready for pickup%s until %s: %s", where, c.hold.ExpiresAt.Format("Mon 2 Jan"), title)
+		if c.kind == domain.NotifyHoldExpired {
+			msg = "Your hold was not picked up in time and has expired: " + title
+		}
+		u.notify.Notify(c.hold.MemberID, c.kind, msg, c.hold.BookID)
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+)
+
+// ImportUsecase brings a member's reading history from another site into
+// their reading lists and reviews. Each shelf becomes a reading list of
+// the same name, reusing a list the member already has.
+type ImportUsecase struct {
+	books   *BookUsecase
+	lists   *ReadingListUsecase
+	reviews *ReviewUsecase
+	flags   *featureflag.Store
+}
+
+func NewImportUsecase(books *BookUsecase, lists *ReadingListUsecase, reviews *ReviewUsecase, flags *featureflag.Store) *ImportUsecase {
+	return &ImportUsecase{books: books, lists: lists, reviews: reviews, flags: flags}
+}
+
+// Import files the rows for a member. Rows with both a rating and review
+// text become reviews, which go through moderation as usual; a rating on
+// its own is not imported, since reviews need text. Reviews of books the
+// member has already reviewed, or all reviews if the tenant has them
+// turned off, are skipped.
+func (u *ImportUsecase) Import(memberID int, tenant, source string, rows []domain.ImportRow) domain.ImportReport {
+	reviews := u.flags.Enabled(featureflag.Reviews, tenant)
+	report := domain.ImportReport{Source: source, Rows: len(rows), ListsCreated: []string{}, Unmatched: []domain.UnmatchedRow{}}
+	catalog := newCatalogMatcher(u.books.GetBooks())
+	lists := map[string]int{}
+	for _, l := range u.lists.ListsForMember(memberID) {
+		lists[strings.ToLower(l.Name)] = l.ID
+	}
+
+	for _, row := range rows {
+		book, ok := catalog.find(row)
+		if !ok {
+			unmatched := domain.UnmatchedRow{Line: row.Line, Title: row.Title, Author: row.Author}
+			if len(row.ISBNs) > 0 {
+				unmatched.ISBN = row.ISBNs[0]
+			}
+			report.Unmatched = append(report.Unmatched, unmatched)
+			continue
+		}
+		report.Matched++
+
+		for _, shelf := range row.Shelves {
+			id, ok := lists[strings.ToLower(shelf)]
+			if !ok {
+				id = u.lists.CreateList(memberID, domain.ReadingList{Name: shelf}).ID
+				lists[strings.ToLower(shelf)] = id
+				report.ListsCreated = append(report.ListsCreated, shelf)
+			}
+			if _, err := u.lists.AddBook(memberID, id, book.ID); err == nil {
+				report.BooksListed++
+			}
+		}
+
+		if row.Review == "" {
+			continue
+		}
+		review := domain.Review{Rating: row.Rating, Text: row.Review}
+		if !reviews || review.Validate() != nil {
+			report.ReviewsSkipped++
+			continue
+		}
+		if _, err := u.reviews.CreateReview(memberID, book.ID, review); err != nil {
+			report.ReviewsSkipped++
+			continue
+		}
+		report.ReviewsCreated++
+	}
+	return report
+}
+
+// catalogMatcher finds the catalog book for an import row: by ISBN
+// first, in either its 10 or 13 digit form, then by title where that
+// picks out a single book. The author decides between books of the same
+// title and rules out a book whose author is someone else.
+type catalogMatcher struct {
+	byISBN  map[string]domain.Book
+	byTitle map[string][]domain.Book
+}
+
+func newCatalogMatcher(books []domain.Book) *catalogMatcher {
+	m := &catalogMatcher{byISBN: map[string]domain.Book{}, byTitle: map[string][]domain.Book{}}
+	for _, b := range books {
+		if !b.Published() {
+			continue
+		}
+		if isbn, ok := domain.ISBN13(b.ISBN); ok {
+			m.byISBN[isbn] = b
+		}
+		key := titleKey(b.Title)
+		m.byTitle[key] = append(m.byTitle[key], b)
+	}
+	return m
+}
+
+func (m *catalogMatcher) find(row domain.ImportRow) (domain.Book, bool) {
+	for _, isbn := range row.ISBNs {
+		if isbn13, ok := domain.ISBN13(isbn); ok {
+			if b, ok := m.byISBN[isbn13]; ok {
+				return b, true
+			}
+		}
+	}
+
+	candidates := m.byTitle[titleKey(row.Title)]
+	if row.Author != "" {
+		surname := domain.ParseAuthorName(row.Author).Surname
+		byAuthor := []domain.Book{}
+		for _, b := range candidates {
+			if surname != "" && containsWord(b.Author, surname) {
+				byAuthor = append(byAuthor, b)
+			}
+		}
+		candidates = byAuthor
+	}
+	if len(candidates) != 1 {
+		return domain.Book{}, false
+	}
+	return candidates[0], true
+}
+
+// titleKey reduces a title to its lower-case words, dropping a subtitle
+// and a trailing series note such as "(The Hunger Games, #1)", which
+// Goodreads adds.
+func titleKey(title string) string {
+	title, _, _ = strings.Cut(title, ":")
+	if i := strings.LastIndex(title, "("); i > 0 && strings.HasSuffix(strings.TrimSpace(title), ")") {
+		title = title[:i]
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func containsWord(s, word string) bool {
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if domain.ParseAuthorName(w).Surname == word {
+			return true
+		}
+	}
+	return false
+}
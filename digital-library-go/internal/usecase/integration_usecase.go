@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrUnknownIntegration = domain.NotFound("unknown integration")
+	ErrInvalidSignature   = domain.Forbidden("invalid signature")
+	ErrNotOnLoan          = domain.Conflict("book is not on loan")
+)
+
+// IntegrationClockSkew bounds how far a signed message's timestamp may
+// be from the server's clock. Older messages are refused, so a captured
+// request cannot be replayed later.
+const IntegrationClockSkew = 5 * time.Minute
+
+// IntegrationUsecase turns circulation messages from external loan
+// systems, such as self-service kiosks, into loans and returns. Each
+// integration signs its messages with a secret shared with the library.
+type IntegrationUsecase struct {
+	secrets map[string]string
+	members *MemberUsecase
+	copies  *CopyUsecase
+	loans   *LoanUsecase
+
+	mu sync.Mutex
+	// answered holds the loan each recent message led to, by integration
+	// and message ID, so a repeated message gets the same answer.
+	answered map[string]answeredMessage
+}
+
+type answeredMessage struct {
+	loan domain.Loan
+	at   time.Time
+}
+
+// NewIntegrationUsecase accepts messages from the integrations in
+// secrets, which maps each name to its shared secret.
+func NewIntegrationUsecase(secrets map[string]string, members *MemberUsecase, copies *CopyUsecase, loans *LoanUsecase) *IntegrationUsecase {
+	return &IntegrationUsecase{
+		secrets:  secrets,
+		members:  members,
+		copies:   copies,
+		loans:    loans,
+		answered: map[string]answeredMessage{},
+	}
+}
+
+// Verify checks that body was signed by the integration: signature must
+// be the hex HMAC-SHA256, keyed with its secret, of the Unix timestamp, a
+// dot and the body.
+func (u *IntegrationUsecase) Verify(name, timestamp, signature string, body []byte, now time.Time) error {
+	secret, ok := u.secrets[name]
+	if !ok {
+		return ErrUnknownIntegration
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > IntegrationClockSkew || skew < -IntegrationClockSkew {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Handle applies a verified message and returns the loan it opened or
+// closed. A message the integration already sent successfully returns
+// the same loan again without changing anything.
+func (u *IntegrationUsecase) Handle(name string, msg domain.KioskMessage, now time.Time) (domain.Loan, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	// Repeats arrive within the clock skew, so older answers can go.
+	for key, a := range u.answered {
+		if now.Sub(a.at) > 2*IntegrationClockSkew {
+			delete(u.answered, key)
+		}
+	}
+	key := name + "/" + msg.ID
+	if a, ok := u.answered[key]; ok {
+		return a.loan, nil
+	}
+
+	bookID := msg.BookID
+	if msg.ItemBarcode != "" {
+		copy, err := u.copies.GetCopyByBarcode(msg.ItemBarcode)
+		if err != nil {
+			return domain.Loan{}, err
+		}
+		if msg.Type == domain.KioskCheckout && copy.Withdrawn() {
+			return domain.Loan{}, ErrCopyWithdrawn
+		}
+		bookID = copy.BookID
+	}
+
+	var loan domain.Loan
+	var err error
+	if msg.Type == domain.KioskCheckout {
+		loan, err = u.checkout(msg.CardNumber, bookID)
+	} else {
+		loan, err = u.checkin(bookID)
+	}
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	u.answered[key] = answeredMessage{loan: loan, at: now}
+	return loan, nil
+}
+
+func (u *IntegrationUsecase) checkout(card string, bookID int) (domain.Loan, error) {
+	member, err := u.members.GetMemberByCard(card)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	return u.loans.Checkout(member.ID, bookID)
+}
+
+func (u *IntegrationUsecase) checkin(bookID int) (domain.Loan, error) {
+	loan, ok := u.loans.ActiveLoanForBook(bookID)
+	if !ok {
+		return domain.Loan{}, ErrNotOnLoan
+	}
+	return u.loans.Return(loan.ID)
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var ErrInventoryClosed = domain.Conflict("inventory audit is closed")
+
+// InventoryUsecase runs inventory audits. Scans are kept per session and
+// reconciled against the copies and loans whenever a report is asked for,
+// so the report of a running audit shows progress so far.
+type InventoryUsecase struct {
+	mu     sync.RWMutex
+	audits []domain.InventoryAudit
+	nextID int
+	copies *CopyUsecase
+	loans  *LoanUsecase
+}
+
+func NewInventoryUsecase(copies *CopyUsecase, loans *LoanUsecase) *InventoryUsecase {
+	return &InventoryUsecase{
+		audits: []domain.InventoryAudit{},
+		nextID: 1,
+		copies: copies,
+		loans:  loans,
+	}
+}
+
+func (u *InventoryUsecase) GetAudits() []domain.InventoryAudit {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.InventoryAudit(nil), u.audits...)
+}
+
+func (u *InventoryUsecase) GetAuditByID(id int) (domain.InventoryAudit, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.InventoryAudit{}, err
+	}
+	return u.audits[i], nil
+}
+
+// StartAudit opens a session for the copies kept within the scope
+// location.
+func (u *InventoryUsecase) StartAudit(scope string) domain.InventoryAudit {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	audit := domain.InventoryAudit{
+		ID:        u.nextID,
+		Scope:     scope,
+		StartedAt: time.Now(),
+		Scans:     []domain.InventoryScan{},
+	}
+	u.nextID++
+	u.audits = append(u.audits, audit)
+	return audit
+}
+
+// AddScans records a batch of barcodes read at one location.
+func (u *InventoryUsecase) AddScans(id int, batch domain.ScanBatch) (domain.InventoryAudit, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.InventoryAudit{}, err
+	}
+	if u.audits[i].ClosedAt != nil {
+		return domain.InventoryAudit{}, ErrInventoryClosed
+	}
+	now := time.Now()
+	for _, barcode := range batch.Barcodes {
+		u.audits[i].Scans = append(u.audits[i].Scans, domain.InventoryScan{
+			Barcode:   strings.TrimSpace(barcode),
+			Location:  batch.Location,
+			ScannedAt: now,
+		})
+	}
+	u.audits[i].Scanned = len(u.audits[i].Scans)
+	return u.audits[i], nil
+}
+
+// CloseAudit ends a session; no more scans are accepted.
+func (u *InventoryUsecase) CloseAudit(id int) (domain.InventoryAudit, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.InventoryAudit{}, err
+	}
+	if u.audits[i].ClosedAt != nil {
+		return domain.InventoryAudit{}, ErrInventoryClosed
+	}
+	now := time.Now()
+	u.audits[i].ClosedAt = &now
+	return u.audits[i], nil
+}
+
+// Report reconciles the audit's scans with the copies in its scope. If a
+// copy was scanned more than once, the last scan counts.
+func (u *InventoryUsecase) Report(id int) (domain.InventoryReport, error) {
+	audit, err := u.GetAuditByID(id)
+	if err != nil {
+		return domain.InventoryReport{}, err
+	}
+
+	lastSeen := map[string]string{}
+	for _, s := range audit.Scans {
+		lastSeen[s.Barcode] = s.Location
+	}
+
+	report := domain.InventoryReport{
+		AuditID:   audit.ID,
+		Scope:     audit.Scope,
+		Missing:   []domain.Copy{},
+		OnLoan:    []domain.Copy{},
+		Misplaced: []domain.MisplacedCopy{},
+		Withdrawn: []domain.Copy{},
+		Unknown:   []string{},
+	}
+	onLoan := u.loans.BooksOnLoan()
+	known := map[string]bool{}
+	for _, c := range u.copies.GetCopies() {
+		known[c.Barcode] = true
+		location, scanned := lastSeen[c.Barcode]
+		if c.Withdrawn() {
+			// Withdrawn copies are not expected, but one still on the
+			// shelves should be taken off them.
+			if scanned {
+				report.Withdrawn = append(report.Withdrawn, c)
+			}
+			continue
+		}
+		inScope := c.InLocation(audit.Scope)
+		if inScope {
+			report.Expected++
+			report.Valuation.Expected += c.Price
+			if c.Price == 0 {
+				report.Valuation.Unpriced++
+			}
+		}
+
+		switch {
+		case scanned:
+			report.Found++
+			if !strings.EqualFold(strings.Trim(location, "/"), c.Location) {
+				report.Misplaced = append(report.Misplaced, domain.MisplacedCopy{Copy: c, FoundAt: location})
+			}
+		case !inScope:
+		case onLoan[c.BookID]:
+			// Loans are per book, so one unscanned copy accounts for it.
+			report.OnLoan = append(report.OnLoan, c)
+			delete(onLoan, c.BookID)
+		default:
+			report.Missing = append(report.Missing, c)
+			report.Valuation.Missing += c.Price
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, s := range audit.Scans {
+		if !known[s.Barcode] && !seen[s.Barcode] {
+			seen[s.Barcode] = true
+			report.Unknown = append(report.Unknown, s.Barcode)
+		}
+	}
+	return report, nil
+}
+
+// index expects the caller to hold the lock.
+func (u *InventoryUsecase) index(id int) (int, error) {
+	for i, a := range u.audits {
+		if a.ID == id {
+			return i, nil
+		}
+	}
+	return 0, domain.NotFound("inventory audit not found")
+}
@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrLoanNotFound     = domain.NotFound("loan not found")
+	ErrLoanLimitReached = domain.Conflict("loan limit reached for membership plan")
+	ErrBookOnLoan       = domain.Conflict("book is already on loan")
+	ErrLoanReturned     = domain.Conflict("loan already returned")
+	ErrRenewalLimit     = domain.Conflict("loan has been renewed too often")
+	ErrBookWanted       = domain.Conflict("book is on hold for another member")
+	ErrReserveLoan      = domain.Conflict("loans of books on course reserve cannot be renewed")
+	ErrNoCopyToLend     = domain.Conflict("every copy of this book has been withdrawn")
+	ErrBookNotLendable  = domain.Invalid("book is not published, so it cannot be lent")
+)
+
+type LoanUsecase struct {
+	mu      sync.RWMutex
+	loans   []domain.Loan
+	nextID  int
+	books   *BookUsecase
+	members *MemberUsecase
+	fines   *FineUsecase
+	// popularity and related are told about every checkout.
+	popularity *PopularityUsecase
+	related    *RelatedUsecase
+	calendar   *CalendarUsecase
+	holds      *HoldUsecase
+	copies     *CopyUsecase
+	notify     *NotificationUsecase
+	courses    *CourseUsecase
+	// reminded holds the IDs of loans whose borrower has been told they
+	// are due soon.
+	reminded map[int]bool
+}
+
+func NewLoanUsecase(books *BookUsecase, members *MemberUsecase, fines *FineUsecase, popularity *PopularityUsecase, related *RelatedUsecase, calendar *CalendarUsecase, holds *HoldUsecase, copies *CopyUsecase, notify *NotificationUsecase, courses *CourseUsecase) *LoanUsecase {
+	return &LoanUsecase{
+		loans:      []domain.Loan{},
+		nextID:     1,
+		books:      books,
+		members:    members,
+		fines:      fines,
+		popularity: popularity,
+		related:    related,
+		calendar:   calendar,
+		holds:      holds,
+		copies:     copies,
+		notify:     notify,
+		courses:    courses,
+		reminded:   map[int]bool{},
+	}
+}
+
+func (u *LoanUsecase) GetLoans() []domain.Loan {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Loan(nil), u.loans...)
+}
+
+func (u *LoanUsecase) GetLoanByID(id int) (domain.Loan, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, l := range u.loans {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return domain.Loan{}, ErrLoanNotFound
+}
+
+// CheckoutVisit is how close together loans must be checked out to count
+// as one visit, printed on one receipt.
+const CheckoutVisit = time.Hour
+
+// VisitLoans returns the loan with the member's other loans checked out
+// on the same visit, i.e. within CheckoutVisit of the one before or after
+// them, in the order they were checked out.
+func (u *LoanUsecase) VisitLoans(id int) ([]domain.Loan, error) {
+	loan, err := u.GetLoanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if loan.MemberID == 0 {
+		// Anonymized loans cannot be told apart.
+		return []domain.Loan{loan}, nil
+	}
+	loans := u.LoansForMember(loan.MemberID)
+	sort.Slice(loans, func(i, j int) bool { return loans[i].LoanedAt.Before(loans[j].LoanedAt) })
+	i := slices.IndexFunc(loans, func(l domain.Loan) bool { return l.ID == id })
+	first, last := i, i
+	for first > 0 && loans[first].LoanedAt.Sub(loans[first-1].LoanedAt) <= CheckoutVisit {
+		first--
+	}
+	for last < len(loans)-1 && loans[last+1].LoanedAt.Sub(loans[last].LoanedAt) <= CheckoutVisit {
+		last++
+	}
+	return loans[first : last+1], nil
+}
+
+// ActiveLoansForMember returns the member's loans that have not been
+// returned yet.
+func (u *LoanUsecase) ActiveLoansForMember(memberID int) []domain.Loan {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	active := []domain.Loan{}
+	for _, l := range u.loans {
+		if l.MemberID == memberID && l.Active() {
+			active = append(active, l)
+		}
+	}
+	return active
+}
+
+// ActiveLoanForBook returns the loan the book is currently out on, if
+// any.
+func (u *LoanUsecase) ActiveLoanForBook(bookID int) (domain.Loan, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, l := range u.loans {
+		if l.BookID == bookID && l.Active() {
+			return l, true
+		}
+	}
+	return domain.Loan{}, false
+}
+
+// BooksOnLoan returns the IDs of books that are currently lent out.
+func (u *LoanUsecase) BooksOnLoan() map[int]bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	onLoan := map[int]bool{}
+	for _, l := range u.loans {
+		if l.Active() {
+			onLoan[l.BookID] = true
+		}
+	}
+	return onLoan
+}
+
+// LoansForMember returns all of the member's loans, including returned
+// ones.
+func (u *LoanUsecase) LoansForMember(memberID int) []domain.Loan {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	loans := []domain.Loan{}
+	for _, l := range u.loans {
+		if l.MemberID == memberID {
+			loans = append(loans, l)
+		}
+	}
+	return loans
+}
+
+// AnonymizeMember detaches the member from their loan history. The loans
+// themselves are kept so circulation statistics stay intact.
+func (u *LoanUsecase) AnonymizeMember(memberID int) {
+	u.related.ForgetMember(memberID)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, l := range u.loans {
+		if l.MemberID == memberID {
+			u.loans[i].MemberID = 0
+		}
+	}
+}
+
+// Checkout lends a book to a member, enforcing the loan limit and loan
+// duration of the member's plan. A book on the hold shelf can only go to
+// the member it is waiting for, and borrowing a book ends the member's
+// hold on it. A loan that would fall due on a day the
+// library is closed is due on the next open day instead. A book on
+// reserve for a running course is lent on the reserve's loan rule
+// instead of the plan's duration. Members whose account is scheduled for
+// deletion cannot borrow. Draft and withdrawn books, and books whose
+// copies have all been withdrawn, cannot be lent.
+func (u *LoanUsecase) Checkout(memberID, bookID int) (domain.Loan, error) {
+	plan, err := u.members.BorrowingPlan(memberID)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	book, err := u.books.GetBookByID(bookID)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	if !book.Published() {
+		return domain.Loan{}, ErrBookNotLendable
+	}
+	if u.copies.AllWithdrawn(bookID) {
+		return domain.Loan{}, ErrNoCopyToLend
+	}
+	now := time.Now()
+	reserve, onReserve := u.courses.ActiveReserve(bookID, now)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	active := 0
+	for _, l := range u.loans {
+		if !l.Active() {
+			continue
+		}
+		if l.BookID == bookID {
+			return domain.Loan{}, ErrBookOnLoan
+		}
+		if l.MemberID == memberID {
+			active++
+		}
+	}
+	if active >= plan.MaxLoans {
+		return domain.Loan{}, ErrLoanLimitReached
+	}
+	if err := u.holds.Fulfil(memberID, bookID); err != nil {
+		return domain.Loan{}, err
+	}
+
+	loan := domain.Loan{
+		ID:       u.nextID,
+		BookID:   bookID,
+		MemberID: memberID,
+		LoanedAt: now,
+		DueAt:    u.calendar.NextOpenDay(plan.DueDate(now)),
+	}
+	if onReserve {
+		loan.DueAt = u.reserveDue(reserve.LoanRule, now)
+		loan.CourseID = reserve.CourseID
+	}
+	u.nextID++
+	u.loans = append(u.loans, loan)
+	u.popularity.Record(domain.BookEvent{BookID: bookID, Kind: domain.BookBorrowed, At: now})
+	u.related.Borrowed(memberID, bookID)
+	return loan, nil
+}
+
+// Return closes a loan and assesses an overdue fine if it is late. The
+// book goes on the hold shelf for the first member waiting for it.
+func (u *LoanUsecase) Return(id int) (domain.Loan, error) {
+	loan, err := u.markReturned(id)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	u.fines.AssessOverdue(loan)
+	u.holds.BookReturned(loan.BookID, *loan.ReturnedAt)
+	return loan, nil
+}
+
+// Renew extends a loan by another loan period of the borrower's plan,
+// counted from now. It is refused once the loan reached MaxRenewals,
+// while another member holds the book, and for reserve loans.
+func (u *LoanUsecase) Renew(id int) (domain.Loan, error) {
+	loan, err := u.GetLoanByID(id)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	plan, err := u.members.PlanFor(loan.MemberID)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, l := range u.loans {
+		if l.ID != id {
+			continue
+		}
+		if !l.Active() {
+			return domain.Loan{}, ErrLoanReturned
+		}
+		if l.CourseID != 0 {
+			return domain.Loan{}, ErrReserveLoan
+		}
+		if l.Renewals >= domain.MaxRenewals {
+			return domain.Loan{}, ErrRenewalLimit
+		}
+		if u.holds.Wanted(l.BookID, l.MemberID) {
+			return domain.Loan{}, ErrBookWanted
+		}
+		due := u.calendar.NextOpenDay(plan.DueDate(time.Now()))
+		if due.After(l.DueAt) {
+			u.loans[i].DueAt = due
+		}
+		u.loans[i].Renewals++
+		delete(u.reminded, id)
+		return u.loans[i], nil
+	}
+	return domain.Loan{}, ErrLoanNotFound
+}
+
+// SendDueReminders notifies borrowers of active loans falling due within
+// DueReminderLead of now, once per loan. It returns how many reminders
+// were sent.
+func (u *LoanUsecase) SendDueReminders(now time.Time) int {
+	due := []domain.Loan{}
+	u.mu.Lock()
+	for _, l := range u.loans {
+		if !l.Active() || l.MemberID == 0 || u.reminded[l.ID] {
+			continue
+		}
+		if l.DueAt.After(now) && !l.DueAt.After(now.Add(domain.DueReminderLead)) {
+			u.reminded[l.ID] = true
+			due = append(due, l)
+		}
+	}
+	u.mu.Unlock()
+
+	for _, l := range due {
+		msg := fmt.Sprintf("Due %s: %s", l.DueAt.Format("Mon 2 Jan"), u.bookTitle(l.BookID))
+		u.notify.Notify(l.MemberID, domain.NotifyDueSoon, msg, l.BookID)
+	}
+	return len(due)
+}
+
+// EstimateHolds fills in when each queued hold should be ready. Every
+// member ahead in the queue is expected to keep the book for the average
+// duration of past loans, counted from when the current loan started.
+// Without returned loans to average, no estimates are made.
+func (u *LoanUsecase) EstimateHolds(queue []domain.QueuedHold, now time.Time) []domain.QueuedHold {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	var total time.Duration
+	returned := 0
+	current := map[int]domain.Loan{}
+	for _, l := range u.loans {
+		if l.Active() {
+			current[l.BookID] = l
+			continue
+		}
+		total += l.ReturnedAt.Sub(l.LoanedAt)
+		returned++
+	}
+	if returned == 0 {
+		return queue
+	}
+	average := total / time.Duration(returned)
+
+	for i, q := range queue {
+		ready := now
+		if q.Status != domain.HoldReady {
+			if loan, ok := current[q.BookID]; ok {
+				ready = loan.LoanedAt.Add(average)
+			}
+			ready = ready.Add(time.Duration(q.Position-1) * average)
+			if ready.Before(now) {
+				ready = now
+			}
+		}
+		days := int(math.Ceil(ready.Sub(now).Hours() / 24))
+		queue[i].EstimatedReadyAt = &ready
+		queue[i].EstimatedWaitDays = &days
+	}
+	return queue
+}
+
+// reserveDue returns when a reserve loan taken out at now falls due. With
+// no opening hours to go by, an overnight loan lasts a day.
+func (u *LoanUsecase) reserveDue(rule string, now time.Time) time.Time {
+	if rule == domain.ReserveOvernight {
+		if opens, ok := u.calendar.NextOpening(now); ok {
+			return opens
+		}
+		return now.AddDate(0, 0, 1)
+	}
+	due := now.Add(domain.ReserveTwoHourPeriod)
+	if closes, ok := u.calendar.ClosingTime(now); ok && closes.After(now) && closes.Before(due) {
+		due = closes
+	}
+	return due
+}
+
+func (u *LoanUsecase) bookTitle(bookID int) string {
+	book, err := u.books.GetBookByID(bookID)
+	if err != nil {
+		return fmt.Sprintf("book %d", bookID)
+	}
+	return book.Title
+}
+
+func (u *LoanUsecase) markReturned(id int) (domain.Loan, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, l := range u.loans {
+		if l.ID == id {
+			if !l.Active() {
+				return domain.Loan{}, ErrLoanReturned
+			}
+			now := time.Now()
+			u.loans[i].ReturnedAt = &now
+			delete(u.reminded, id)
+			return u.loans[i], nil
+		}
+	}
+	return domain.Loan{}, ErrLoanNotFound
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const (
+	// Failures before each further attempt is delayed, doubling from one
+	// second up to maxLoginDelay.
+	loginDelayAfter = 3
+	maxLoginDelay   = time.Minute
+
+	accountLockAfter = 10
+	ipLockAfter      = 30
+	lockoutDuration  = 15 * time.Minute
+
+	// failureWindow is how long failures are remembered without new ones.
+	failureWindow = 15 * time.Minute
+
+	LockoutAccount = "account"
+	LockoutIP      = "ip"
+)
+
+// ThrottledError is returned when a login attempt is refused before the
+// password is even checked.
+type ThrottledError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+type failures struct {
+	count int
+	// pending counts attempts reserved but not yet settled as a failure
+	// or success. They are throttled as if they had failed, so parallel
+	// guesses cannot all slip past the delay.
+	pending     int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// LoginGuardUsecase slows down and temporarily locks out repeated failed
+// logins, per account and per client IP.
+type LoginGuardUsecase struct {
+	mu       sync.Mutex
+	accounts map[string]*failures
+	ips      map[string]*failures
+	audit    *AuditUsecase
+}
+
+func NewLoginGuardUsecase(audit *AuditUsecase) *LoginGuardUsecase {
+	return &LoginGuardUsecase{
+		accounts: map[string]*failures{},
+		ips:      map[string]*failures{},
+		audit:    audit,
+	}
+}
+
+// Reserve refuses an attempt while the account or IP is locked or still
+// inside its progressive delay. Otherwise it counts the attempt as
+// pending until RecordFailure or RecordSuccess settles it, in the same
+// critical section, so every attempt is throttled against the ones
+// still in flight.
+func (u *LoginGuardUsecase) Reserve(card, ip string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+
+	for _, f := range []*failures{u.accounts[card], u.ips[ip]} {
+		if f == nil || stale(f, now) {
+			continue
+		}
+		if now.Before(f.lockedUntil) {
+			return &ThrottledError{Reason: "too many failed logins, temporarily locked", RetryAfter: f.lockedUntil.Sub(now)}
+		}
+		if wait := f.last.Add(loginDelay(f.count + f.pending)).Sub(now); wait > 0 {
+			return &ThrottledError{Reason: "too many failed logins, slow down", RetryAfter: wait}
+		}
+	}
+
+	for _, f := range []*failures{entry(u.accounts, card, now), entry(u.ips, ip, now)} {
+		f.pending++
+		f.last = now
+	}
+	return nil
+}
+
+// RecordFailure settles an attempt reserved with Reserve as failed.
+func (u *LoginGuardUsecase) RecordFailure(card, ip string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	settle(u.accounts[card])
+	settle(u.ips[ip])
+
+	u.audit.Record(domain.AuditEvent{Type: "login_failed", Actor: card, IP: ip})
+	if u.fail(u.accounts, card, accountLockAfter, now) {
+		u.audit.Record(domain.AuditEvent{Type: "account_locked", Actor: card, IP: ip,
+			Detail: fmt.Sprintf("locked for %s after %d failed logins", lockoutDuration, accountLockAfter)})
+	}
+	if u.fail(u.ips, ip, ipLockAfter, now) {
+		u.audit.Record(domain.AuditEvent{Type: "ip_locked", IP: ip,
+			Detail: fmt.Sprintf("locked for %s after %d failed logins", lockoutDuration, ipLockAfter)})
+	}
+}
+
+// RecordSuccess settles an attempt reserved with Reserve as successful
+// and forgets the account's failures. The IP's are kept so a valid login
+// cannot be used to reset guessing against other accounts.
+func (u *LoginGuardUsecase) RecordSuccess(card, ip string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.accounts, card)
+	settle(u.ips[ip])
+}
+
+// Expire drops the entries of accounts and IPs that have been quiet for
+// longer than the failure window and are not locked. It runs on a timer
+// so the maps stay small without scanning them on every login.
+func (u *LoginGuardUsecase) Expire(now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.expire(now)
+}
+
+// Lockouts lists every account and IP with recent failures, most recent
+// first.
+func (u *LoginGuardUsecase) Lockouts() []domain.Lockout {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.expire(time.Now())
+
+	lockouts := []domain.Lockout{}
+	for kind, m := range map[string]map[string]*failures{LockoutAccount: u.accounts, LockoutIP: u.ips} {
+		for key, f := range m {
+			if f.count == 0 {
+				continue
+			}
+			lockouts = append(lockouts, domain.Lockout{
+				Kind: kind, Key: key, Failures: f.count, LastFailure: f.last, LockedUntil: f.lockedUntil,
+			})
+		}
+	}
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].LastFailure.After(lockouts[j].LastFailure)
+	})
+	return lockouts
+}
+
+// Unlock clears the failures recorded for an account or IP.
+func (u *LoginGuardUsecase) Unlock(kind, key, actor string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	m := u.accounts
+	if kind == LockoutIP {
+		m = u.ips
+	}
+	if _, ok := m[key]; !ok {
+		return domain.NotFound("lockout not found")
+	}
+	delete(m, key)
+	u.audit.Record(domain.AuditEvent{Type: kind + "_unlocked", Actor: actor, Detail: key})
+	return nil
+}
+
+// fail counts a failure and reports whether it just caused a lockout.
+func (u *LoginGuardUsecase) fail(m map[string]*failures, key string, lockAfter int, now time.Time) bool {
+	f := entry(m, key, now)
+	f.count++
+	f.last = now
+	if f.count%lockAfter == 0 {
+		f.lockedUntil = now.Add(lockoutDuration)
+		return true
+	}
+	return false
+}
+
+// expire drops stale entries. It expects the caller to hold the lock.
+func (u *LoginGuardUsecase) expire(now time.Time) {
+	for _, m := range []map[string]*failures{u.accounts, u.ips} {
+		for key, f := range m {
+			if stale(f, now) {
+				delete(m, key)
+			}
+		}
+	}
+}
+
+// entry returns the failures recorded for key, starting afresh when
+// there are none or they are stale.
+func entry(m map[string]*failures, key string, now time.Time) *failures {
+	f, ok := m[key]
+	if !ok || stale(f, now) {
+		f = &failures{}
+		m[key] = f
+	}
+	return f
+}
+
+// stale reports whether failures have been quiet for longer than the
+// failure window, are not locked and have no attempt in flight.
+func stale(f *failures, now time.Time) bool {
+	return f.pending == 0 && now.Sub(f.last) > failureWindow && now.After(f.lockedUntil)
+}
+
+func settle(f *failures) {
+	if f != nil && f.pending > 0 {
+		f.pending--
+	}
+}
+
+func loginDelay(count int) time.Duration {
+	if count < loginDelayAfter {
+		return 0
+	}
+	shift := count - loginDelayAfter
+	if shift > 6 {
+		return maxLoginDelay
+	}
+	return min(time.Second<<shift, maxLoginDelay)
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrUnknownPlatform     = domain.NotFound("unknown LTI platform")
+	ErrLTISessionExpired   = domain.Unauthorized("this page has expired; open the reserve list from your course again")
+	ErrNotCourseInstructor = domain.Forbidden("only the course's instructors can change its reserves")
+	ErrCourseNotSetUp      = domain.NotFound("no reserves have been set up for this course yet")
+	ErrISBNNotInCatalog    = domain.NotFound("no book in the catalog has this ISBN")
+)
+
+// ltiSessionTTL is how long a reserve list page launched from a learning
+// management system keeps working.
+const ltiSessionTTL = 2 * time.Hour
+
+// LTIPlatform is a learning management system such as Moodle or Canvas
+// that embeds course reserve lists through LTI 1.3.
+type LTIPlatform interface {
+	Name() string
+	Issuer() string
+	ClientID() string
+	AuthRequestURL(redirectURI, loginHint, messageHint, state, nonce string) string
+	Verify(ctx context.Context, idToken string) (domain.LTILaunch, error)
+}
+
+// LTISession is a reserve list page opened by an LTI launch. Pages are
+// shown inside the platform's frame, where cookies are unreliable, so
+// the session travels as a token in the page's links and forms.
+type LTISession struct {
+	CourseID int
+	Role     string
+	Name     string
+	expires  time.Time
+}
+
+// LTIUsecase launches course reserve lists from learning management
+// systems. Instructors launching from a course the library does not know
+// yet set it up; learners see the reserves read-only.
+type LTIUsecase struct {
+	mu          sync.Mutex
+	platforms   []LTIPlatform
+	redirectURI string
+	pending     map[string]pendingLogin
+	sessions    map[string]LTISession
+	courses     *CourseUsecase
+	books       *BookUsecase
+}
+
+func NewLTIUsecase(courses *CourseUsecase, books *BookUsecase, redirectURI string, platforms ...LTIPlatform) *LTIUsecase {
+	return &LTIUsecase{
+		platforms:   platforms,
+		redirectURI: redirectURI,
+		pending:     map[string]pendingLogin{},
+		sessions:    map[string]LTISession{},
+		courses:     courses,
+		books:       books,
+	}
+}
+
+// BeginLaunch answers a platform's login initiation with the URL to send
+// the browser to. clientID may be empty when the platform has only one
+// registration.
+func (u *LTIUsecase) BeginLaunch(issuer, clientID, loginHint, messageHint string) (string, error) {
+	var platform LTIPlatform
+	for _, p := range u.platforms {
+		if p.Issuer() == issuer && (clientID == "" || p.ClientID() == clientID) {
+			platform = p
+			break
+		}
+	}
+	if platform == nil || loginHint == "" {
+		return "", ErrUnknownPlatform
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	u.prune(now)
+	u.pending[state] = pendingLogin{provider: platform.Name(), nonce: nonce, expires: now.Add(loginStateTTL)}
+	return platform.AuthRequestURL(u.redirectURI, loginHint, messageHint, state, nonce), nil
+}
+
+// CompleteLaunch verifies the launch a platform posts back and opens a
+// session on the course's reserve list.
+func (u *LTIUsecase) CompleteLaunch(ctx context.Context, state, idToken string) (string, LTISession, error) {
+	u.mu.Lock()
+	pl, ok := u.pending[state]
+	delete(u.pending, state)
+	u.mu.Unlock()
+	if !ok || time.Now().After(pl.expires) {
+		return "", LTISession{}, ErrInvalidState
+	}
+
+	var platform LTIPlatform
+	for _, p := range u.platforms {
+		if p.Name() == pl.provider {
+			platform = p
+		}
+	}
+	launch, err := platform.Verify(ctx, idToken)
+	if err != nil {
+		return "", LTISession{}, err
+	}
+	if launch.Nonce != pl.nonce {
+		return "", LTISession{}, domain.Unauthorized("lti: nonce mismatch")
+	}
+
+	role := launch.CourseRole()
+	lms := domain.LTIContext{Issuer: launch.Issuer, ContextID: launch.ContextID}
+	course, ok := u.courses.CourseForContext(lms)
+	if !ok {
+		if role != domain.CourseInstructor {
+			return "", LTISession{}, ErrCourseNotSetUp
+		}
+		code, title := launch.ContextLabel, launch.ContextTitle
+		if code == "" {
+			code = launch.ContextID
+		}
+		if title == "" {
+			title = code
+		}
+		course = u.courses.LinkContext(lms, code, title)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return "", LTISession{}, err
+	}
+	now := time.Now()
+	session := LTISession{CourseID: course.ID, Role: role, Name: launch.Name, expires: now.Add(ltiSessionTTL)}
+	u.mu.Lock()
+	u.prune(now)
+	u.sessions[token] = session
+	u.mu.Unlock()
+	return token, session, nil
+}
+
+func (u *LTIUsecase) Session(token string) (LTISession, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.sessions[token]
+	if !ok || time.Now().After(s.expires) {
+		return LTISession{}, ErrLTISessionExpired
+	}
+	return s, nil
+}
+
+// AddReserve puts the book with an ISBN on reserve for the session's
+// course. Instructors only.
+func (u *LTIUsecase) AddReserve(token, isbn string) error {
+	s, err := u.instructorSession(token)
+	if err != nil {
+		return err
+	}
+	book, ok := u.books.BookByISBN(isbn)
+	if !ok {
+		return ErrISBNNotInCatalog
+	}
+	_, err = u.courses.AddReserve(s.CourseID, book.ID)
+	return err
+}
+
+// RemoveReserve takes a book off reserve for the session's course.
+// Instructors only.
+func (u *LTIUsecase) RemoveReserve(token string, bookID int) error {
+	s, err := u.instructorSession(token)
+	if err != nil {
+		return err
+	}
+	_, err = u.courses.RemoveReserve(s.CourseID, bookID)
+	return err
+}
+
+func (u *LTIUsecase) instructorSession(token string) (LTISession, error) {
+	s, err := u.Session(token)
+	if err != nil {
+		return LTISession{}, err
+	}
+	if s.Role != domain.CourseInstructor {
+		return LTISession{}, ErrNotCourseInstructor
+	}
+	return s, nil
+}
+
+// prune expects the caller to hold the lock.
+func (u *LTIUsecase) prune(now time.Time) {
+	for s, pl := range u.pending {
+		if now.After(pl.expires) {
+			delete(u.pending, s)
+		}
+	}
+	for t, s := range u.sessions {
+		if now.After(s.expires) {
+			delete(u.sessions, t)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// MaintenanceUsecase holds the maintenance mode switch. Turning it on or
+// off is logged and recorded in the audit log.
+type MaintenanceUsecase struct {
+	mu    sync.RWMutex
+	state domain.Maintenance
+	audit *AuditUsecase
+}
+
+func NewMaintenanceUsecase(audit *AuditUsecase) *MaintenanceUsecase {
+	return &MaintenanceUsecase{audit: audit}
+}
+
+func (u *MaintenanceUsecase) Status() domain.Maintenance {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.state
+}
+
+// Enable turns maintenance mode on, or updates its message and retry
+// delay if it is on already. It reports whether this call turned it on.
+func (u *MaintenanceUsecase) Enable(by, message string, retryAfter int, now time.Time) (domain.Maintenance, bool) {
+	if retryAfter <= 0 {
+		retryAfter = domain.DefaultRetryAfter
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	started := !u.state.Enabled
+	if started {
+		u.state = domain.Maintenance{Enabled: true, Since: now, By: by}
+		log.Printf("Maintenance mode on (by %s): %s", by, message)
+		u.audit.Record(domain.AuditEvent{Time: now, Type: "maintenance_on", Actor: by, Detail: message})
+	}
+	u.state.Message = message
+	u.state.RetryAfter = retryAfter
+	return u.state, started
+}
+
+// Disable turns maintenance mode off.
+func (u *MaintenanceUsecase) Disable(by string, now time.Time) domain.Maintenance {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.state.Enabled {
+		log.Printf("Maintenance mode off (by %s)", by)
+		u.audit.Record(domain.AuditEvent{Time: now, Type: "maintenance_off", Actor: by})
+	}
+	u.state = domain.Maintenance{}
+	return u.state
+}
@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrMemberNotFound     = domain.NotFound("member not found")
+	ErrInvalidCardNumber  = domain.Invalid("invalid card number")
+	ErrCardReplaced       = domain.Gone("card has been replaced")
+	ErrInvalidCredentials = domain.Unauthorized("invalid card number or password")
+	ErrInvalidRole        = domain.Invalid("role must be member, librarian or admin")
+	ErrDeletionPending    = domain.Conflict("account is scheduled for deletion; restore it to borrow")
+)
+
+type MemberUsecase struct {
+	mu      sync.RWMutex
+	members []domain.Member
+	nextID  int
+	plans   *PlanUsecase
+}
+
+func NewMemberUsecase(plans *PlanUsecase) *MemberUsecase {
+	return &MemberUsecase{
+		members: []domain.Member{},
+		nextID:  1,
+		plans:   plans,
+	}
+}
+
+func (u *MemberUsecase) GetMembers() []domain.Member {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Member(nil), u.members...)
+}
+
+func (u *MemberUsecase) GetMemberByID(id int) (domain.Member, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, m := range u.members {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+func (u *MemberUsecase) CreateMember(member domain.Member) (domain.Member, error) {
+	if err := hashPassword(&member); err != nil {
+		return domain.Member{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	// The plan is checked under the lock so DeletePlan cannot remove it
+	// in between.
+	if _, err := u.plans.GetPlanByID(member.PlanID); err != nil {
+		return domain.Member{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	member.ID = u.nextID
+	member.Tenant = featureflag.Tenant(member.Tenant)
+	member.Role = domain.RoleMember
+	member.CardNumber = u.newCardNumber()
+	member.PreviousCards = nil
+	member.Identities = nil
+	member.DeletionScheduledAt = nil
+	u.nextID++
+	u.members = append(u.members, member)
+	return member, nil
+}
+
+func (u *MemberUsecase) UpdateMember(id int, updated domain.Member) error {
+	if err := hashPassword(&updated); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, err := u.plans.GetPlanByID(updated.PlanID); err != nil {
+		return domain.Wrap(domain.ErrInvalid, err)
+	}
+	for i, m := range u.members {
+		if m.ID == id {
+			m.Name = updated.Name
+			m.Email = updated.Email
+			m.Phone = updated.Phone
+			m.PlanID = updated.PlanID
+			m.Audience = updated.Audience
+			// A member keeps their tenant unless the update names another.
+			if updated.Tenant != "" {
+				m.Tenant = featureflag.Tenant(updated.Tenant)
+			}
+			if updated.PasswordHash != nil {
+				m.PasswordHash = updated.PasswordHash
+			}
+			u.members[i] = m
+			return nil
+		}
+	}
+	return ErrMemberNotFound
+}
+
+func (u *MemberUsecase) DeleteMember(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members = append(u.members[:i], u.members[i+1:]...)
+			return nil
+		}
+	}
+	return ErrMemberNotFound
+}
+
+// UpdateProfile changes the contact details a member may edit on their
+// own account.
+func (u *MemberUsecase) UpdateProfile(id int, name, email, phone string) (domain.Member, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members[i].Name = name
+			u.members[i].Email = email
+			u.members[i].Phone = phone
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+// SetRole changes what the member is allowed to do. Roles can only be
+// granted through this method, never through the member's own data.
+func (u *MemberUsecase) SetRole(id int, role string) (domain.Member, error) {
+	if !domain.ValidRole(role) {
+		return domain.Member{}, ErrInvalidRole
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members[i].Role = role
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+func (u *MemberUsecase) SetPrivacy(id int, privacy domain.Privacy) (domain.Member, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members[i].Privacy = privacy
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+// ScheduleDeletion marks the member's account for deletion at the given
+// time. ClearDeletion undoes it while the grace period lasts.
+func (u *MemberUsecase) ScheduleDeletion(id int, at time.Time) (domain.Member, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members[i].DeletionScheduledAt = &at
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+func (u *MemberUsecase) ClearDeletion(id int) (domain.Member, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members[i].DeletionScheduledAt = nil
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+// DueForDeletion returns the IDs of members whose grace period ended
+// before now.
+func (u *MemberUsecase) DueForDeletion(now time.Time) []int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	ids := []int{}
+	for _, m := range u.members {
+		if m.DeletionScheduledAt != nil && m.DeletionScheduledAt.Before(now) {
+			ids = append(ids, m.ID)
+		}
+	}
+	return ids
+}
+
+// GetMemberByIdentity returns the member linked to an external identity.
+func (u *MemberUsecase) GetMemberByIdentity(id domain.Identity) (domain.Member, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, m := range u.members {
+		if slices.Contains(m.Identities, id) {
+			return m, nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+func (u *MemberUsecase) GetMemberByEmail(email string) (domain.Member, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, m := range u.members {
+		if email != "" && strings.EqualFold(m.Email, email) {
+			return m, nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+func (u *MemberUsecase) LinkIdentity(memberID int, id domain.Identity) (domain.Member, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == memberID {
+			if !slices.Contains(m.Identities, id) {
+				u.members[i].Identities = append(m.Identities, id)
+			}
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+// Authenticate checks a card number and password pair and returns the
+// member they belong to.
+func (u *MemberUsecase) Authenticate(card, password string) (domain.Member, error) {
+	member, err := u.GetMemberByCard(card)
+	if err != nil {
+		return domain.Member{}, ErrInvalidCredentials
+	}
+	if member.PasswordHash == nil ||
+		bcrypt.CompareHashAndPassword(member.PasswordHash, []byte(password)) != nil {
+		return domain.Member{}, ErrInvalidCredentials
+	}
+	return member, nil
+}
+
+// GetMemberByCard looks a member up by library card number. Numbers
+// retired by ReplaceCard are reported with ErrCardReplaced rather than
+// as unknown.
+func (u *MemberUsecase) GetMemberByCard(number string) (domain.Member, error) {
+	if !domain.ValidCardNumber(number) {
+		return domain.Member{}, ErrInvalidCardNumber
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, m := range u.members {
+		if m.CardNumber == number {
+			return m, nil
+		}
+		if slices.Contains(m.PreviousCards, number) {
+			return domain.Member{}, ErrCardReplaced
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+// ReplaceCard issues the member a new card number. The old number stops
+// resolving but is kept in the member's card history.
+func (u *MemberUsecase) ReplaceCard(id int) (domain.Member, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			u.members[i].PreviousCards = append(m.PreviousCards, m.CardNumber)
+			u.members[i].CardNumber = u.newCardNumber()
+			return u.members[i], nil
+		}
+	}
+	return domain.Member{}, ErrMemberNotFound
+}
+
+// FeedKey returns the key the member's calendar feed token is made from,
+// creating one if the member has none yet. Rotating replaces it.
+func (u *MemberUsecase) FeedKey(id int, rotate bool) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, m := range u.members {
+		if m.ID == id {
+			if m.FeedKey == "" || rotate {
+				u.members[i].FeedKey = rand.Text()
+			}
+			return u.members[i].FeedKey, nil
+		}
+	}
+	return "", ErrMemberNotFound
+}
+
+// newCardNumber returns a card number that has never been issued. The
+// caller must hold the write lock.
+func (u *MemberUsecase) newCardNumber() string {
+	for {
+		number := domain.NewCardNumber()
+		if !u.cardIssued(number) {
+			return number
+		}
+	}
+}
+
+func (u *MemberUsecase) cardIssued(number string) bool {
+	for _, m := range u.members {
+		if m.CardNumber == number || slices.Contains(m.PreviousCards, number) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashPassword replaces a plaintext password from a request with its
+// bcrypt hash so it is never stored.
+func hashPassword(m *domain.Member) error {
+	if m.Password == "" {
+		m.PasswordHash = nil
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(m.Password), bcrypt.DefaultCost)
+	if err != nil {
+		// Passwords longer than 72 bytes are refused.
+		return domain.Wrap(domain.ErrInvalid, err)
+	}
+	m.Password = ""
+	m.PasswordHash = hash
+	return nil
+}
+
+// DeletePlan deletes a membership plan no member is on.
+func (u *MemberUsecase) DeletePlan(id int) error {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if slices.ContainsFunc(u.members, func(m domain.Member) bool { return m.PlanID == id }) {
+		return ErrPlanInUse
+	}
+	return u.plans.remove(id)
+}
+
+// BorrowingPlan returns the plan of a member about to borrow or place a
+// hold. Members whose account is scheduled for deletion may do neither.
+func (u *MemberUsecase) BorrowingPlan(memberID int) (domain.Plan, error) {
+	member, err := u.GetMemberByID(memberID)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	if member.DeletionScheduledAt != nil {
+		return domain.Plan{}, ErrDeletionPending
+	}
+	return u.plans.GetPlanByID(member.PlanID)
+}
+
+// PlanFor returns the membership plan the member is currently on.
+func (u *MemberUsecase) PlanFor(memberID int) (domain.Plan, error) {
+	member, err := u.GetMemberByID(memberID)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	return u.plans.GetPlanByID(member.PlanID)
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrRefreshRunning = domain.Conflict("metadata refresh already running")
+	ErrNoRefreshYet   = domain.NotFound("metadata refresh has not run yet")
+)
+
+// MetadataProvider looks up book metadata by ISBN in an external catalog.
+// It returns domain.ErrMetadataNotFound when it has no record.
+type MetadataProvider interface {
+	Name() string
+	Lookup(ctx context.Context, isbn string) (domain.BookMetadata, error)
+}
+
+// MetadataRefreshUsecase fills in missing book fields from external
+// providers as a background job. Only one run happens at a time and the
+// report of the latest run is kept.
+type MetadataRefreshUsecase struct {
+	books     *BookUsecase
+	authors   *AuthorUsecase
+	providers []MetadataProvider
+
+	mu      sync.Mutex
+	running bool
+	report  *domain.MetadataRefreshReport
+}
+
+func NewMetadataRefreshUsecase(books *BookUsecase, authors *AuthorUsecase, providers ...MetadataProvider) *MetadataRefreshUsecase {
+	return &MetadataRefreshUsecase{books: books, authors: authors, providers: providers}
+}
+
+// Start begins a refresh in the background and returns its initial
+// report.
+func (u *MetadataRefreshUsecase) Start() (domain.MetadataRefreshReport, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.running {
+		return domain.MetadataRefreshReport{}, ErrRefreshRunning
+	}
+	u.running = true
+	u.report = &domain.MetadataRefreshReport{
+		Status:    "running",
+		StartedAt: time.Now(),
+		Results:   []domain.BookRefreshResult{},
+	}
+	go u.run()
+	return u.copyReport(), nil
+}
+
+// Report returns the report of the current or latest run.
+func (u *MetadataRefreshUsecase) Report() (domain.MetadataRefreshReport, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.report == nil {
+		return domain.MetadataRefreshReport{}, ErrNoRefreshYet
+	}
+	return u.copyReport(), nil
+}
+
+func (u *MetadataRefreshUsecase) run() {
+	for _, book := range u.books.GetBooks() {
+		if len(book.MissingFields()) == 0 {
+			continue
+		}
+		result := u.refresh(book)
+
+		u.mu.Lock()
+		u.report.Checked++
+		switch result.Outcome {
+		case domain.RefreshUpdated:
+			u.report.Updated++
+		case domain.RefreshNotFound:
+			u.report.NotFound++
+		default:
+			u.report.Failed++
+		}
+		u.report.Results = append(u.report.Results, result)
+		u.mu.Unlock()
+	}
+
+	u.mu.Lock()
+	u.report.Status = "completed"
+	u.report.FinishedAt = time.Now()
+	u.running = false
+	u.mu.Unlock()
+}
+
+// refresh asks each provider in turn until one has a record for the
+// book. A provider failure is reported only if no later provider helps.
+func (u *MetadataRefreshUsecase) refresh(book domain.Book) domain.BookRefreshResult {
+	result := domain.BookRefreshResult{BookID: book.ID, ISBN: book.ISBN, Outcome: domain.RefreshNotFound}
+	for _, p := range u.providers {
+		meta, err := p.Lookup(context.Background(), book.ISBN)
+		if errors.Is(err, domain.ErrMetadataNotFound) {
+			continue
+		}
+		if err != nil {
+			result.Outcome = domain.RefreshFailed
+			result.Provider = p.Name()
+			result.Error = err.Error()
+			continue
+		}
+
+		filled, err := u.books.FillMissing(book.ID, meta)
+		if err != nil {
+			// Deleted while the job was running.
+			return domain.BookRefreshResult{BookID: book.ID, ISBN: book.ISBN, Outcome: domain.RefreshFailed, Error: err.Error()}
+		}
+		if len(filled) == 0 {
+			continue
+		}
+		if slices.Contains(filled, "author") {
+			if err := u.authors.LinkBook(book.ID); err != nil {
+				return domain.BookRefreshResult{BookID: book.ID, ISBN: book.ISBN, Outcome: domain.RefreshFailed, Provider: p.Name(), Fields: filled, Error: "linking the author: " + err.Error()}
+			}
+		}
+		return domain.BookRefreshResult{BookID: book.ID, ISBN: book.ISBN, Outcome: domain.RefreshUpdated, Provider: p.Name(), Fields: filled}
+	}
+	return result
+}
+
+// copyReport returns a snapshot of the report. The caller must hold the
+// lock.
+func (u *MetadataRefreshUsecase) copyReport() domain.MetadataRefreshReport {
+	report := *u.report
+	report.Results = slices.Clone(u.report.Results)
+	return report
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// channelTimeout bounds one delivery attempt to an external channel.
+const channelTimeout = 30 * time.Second
+
+// Channel delivers notifications outside the in-app inbox, such as by
+// email. Name is the channel it serves, e.g. domain.ChannelEmail.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, member domain.Member, n domain.Notification) error
+}
+
+// NotificationUsecase dispatches notifications to the channels each
+// member chose for that kind of notification. Other usecases call
+// Notify; the in-app inbox is kept here and read through /me.
+type NotificationUsecase struct {
+	mu            sync.RWMutex
+	notifications []domain.Notification
+	preferences   map[int]domain.NotificationPreferences
+	nextID        int
+	members       *MemberUsecase
+	channels      map[string]Channel
+}
+
+// NewNotificationUsecase dispatches to the given channels. Members who
+// pick a channel that is not configured only get the other ones.
+func NewNotificationUsecase(members *MemberUsecase, channels ...Channel) *NotificationUsecase {
+	u := &NotificationUsecase{
+		notifications: []domain.Notification{},
+		preferences:   map[int]domain.NotificationPreferences{},
+		nextID:        1,
+		members:       members,
+		channels:      map[string]Channel{},
+	}
+	for _, c := range channels {
+		u.channels[c.Name()] = c
+	}
+	return u
+}
+
+// Notify sends a notification of the given kind to a member on each
+// channel they chose. External channels are sent to in the background.
+func (u *NotificationUsecase) Notify(memberID int, kind, message string, bookID int) domain.Notification {
+	n := domain.Notification{
+		MemberID:  memberID,
+		Kind:      kind,
+		Message:   message,
+		BookID:    bookID,
+		CreatedAt: time.Now(),
+	}
+
+	u.mu.Lock()
+	channels := u.preferences[memberID].Channels(kind)
+	if channels.InApp {
+		n.ID = u.nextID
+		u.nextID++
+		u.notifications = append(u.notifications, n)
+	}
+	u.mu.Unlock()
+
+	for _, name := range channels.Enabled() {
+		if c, ok := u.channels[name]; ok {
+			go u.deliver(c, n)
+		}
+	}
+	return n
+}
+
+func (u *NotificationUsecase) deliver(c Channel, n domain.Notification) {
+	member, err := u.members.GetMemberByID(n.MemberID)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), channelTimeout)
+	defer cancel()
+	if err := c.Send(ctx, member, n); err != nil {
+		log.Printf("Notification to member %d over %s failed: %v", n.MemberID, c.Name(), err)
+	}
+}
+
+// Channels lists the configured channels besides the in-app inbox.
+func (u *NotificationUsecase) Channels() []string {
+	return slices.Sorted(maps.Keys(u.channels))
+}
+
+// PreferencesForMember returns a member's channel choices for every kind
+// of notification.
+func (u *NotificationUsecase) PreferencesForMember(memberID int) domain.NotificationPreferences {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.preferences[memberID].WithDefaults()
+}
+
+// SetPreferences updates the channels for the kinds given; other kinds
+// keep their current choice.
+func (u *NotificationUsecase) SetPreferences(memberID int, prefs domain.NotificationPreferences) domain.NotificationPreferences {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	current := u.preferences[memberID].WithDefaults()
+	maps.Copy(current, prefs)
+	u.preferences[memberID] = current
+	return maps.Clone(current)
+}
+
+// NotificationsForMember returns a member's notifications, newest first.
+func (u *NotificationUsecase) NotificationsForMember(memberID int) []domain.Notification {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	found := []domain.Notification{}
+	for i := len(u.notifications) - 1; i >= 0; i-- {
+		if u.notifications[i].MemberID == memberID {
+			found = append(found, u.notifications[i])
+		}
+	}
+	return found
+}
+
+func (u *NotificationUsecase) MarkRead(memberID, id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, n := range u.notifications {
+		if n.ID == id && n.MemberID == memberID {
+			u.notifications[i].Read = true
+			return nil
+		}
+	}
+	return domain.NotFound("notification not found")
+}
+
+// DeleteForMember removes a member's notifications and preferences.
+func (u *NotificationUsecase) DeleteForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.notifications = slices.DeleteFunc(u.notifications, func(n domain.Notification) bool {
+		return n.MemberID == memberID
+	})
+	delete(u.preferences, memberID)
+}
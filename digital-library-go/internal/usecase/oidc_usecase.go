@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrUnknownProvider = domain.NotFound("unknown identity provider")
+	ErrInvalidState    = domain.Invalid("invalid or expired login state")
+	// Staff accounts hold more than a member's data, so an external
+	// login alone is not enough for them.
+	ErrStaffNeedsTwoFactor = domain.Forbidden("staff accounts must enable two-factor authentication to log in with an external provider")
+	ErrStaffEmailLink      = domain.Forbidden("external logins are not linked to staff accounts by email")
+)
+
+const loginStateTTL = 10 * time.Minute
+
+// IdentityProvider is an external login provider such as Google or an
+// institutional single sign-on.
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce string) (string, error)
+	Exchange(ctx context.Context, code, nonce string) (domain.ExternalIdentity, error)
+}
+
+type pendingLogin struct {
+	provider string
+	nonce    string
+	expires  time.Time
+}
+
+// OIDCUsecase logs members in through external identity providers,
+// linking identities to existing members or provisioning new members on
+// first login.
+type OIDCUsecase struct {
+	mu          sync.Mutex
+	providers   map[string]IdentityProvider
+	pending     map[string]pendingLogin
+	members     *MemberUsecase
+	plans       *PlanUsecase
+	auth        *AuthUsecase
+	twoFactor   *TwoFactorUsecase
+	defaultPlan string
+}
+
+func NewOIDCUsecase(members *MemberUsecase, plans *PlanUsecase, auth *AuthUsecase, twoFactor *TwoFactorUsecase, defaultPlan string, providers ...IdentityProvider) *OIDCUsecase {
+	u := &OIDCUsecase{
+		providers:   map[string]IdentityProvider{},
+		pending:     map[string]pendingLogin{},
+		members:     members,
+		plans:       plans,
+		auth:        auth,
+		twoFactor:   twoFactor,
+		defaultPlan: defaultPlan,
+	}
+	for _, p := range providers {
+		u.providers[p.Name()] = p
+	}
+	return u
+}
+
+func (u *OIDCUsecase) Providers() []string {
+	names := []string{}
+	for name := range u.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// BeginLogin returns the provider URL to redirect the user to, remembering
+// the state and nonce needed to complete the login.
+func (u *OIDCUsecase) BeginLogin(ctx context.Context, provider string) (string, error) {
+	p, ok := u.providers[provider]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	url, err := p.AuthCodeURL(ctx, state, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	for s, pl := range u.pending {
+		if now.After(pl.expires) {
+			delete(u.pending, s)
+		}
+	}
+	u.pending[state] = pendingLogin{provider: provider, nonce: nonce, expires: now.Add(loginStateTTL)}
+	return url, nil
+}
+
+// CompleteLogin handles the provider callback and signs in the member the
+// external identity belongs to. Staff must answer a two-factor challenge
+// as with a password login, and without two-factor authentication they
+// cannot log in this way at all. Members it creates belong to tenant.
+func (u *OIDCUsecase) CompleteLogin(ctx context.Context, tenant, provider, state, code string) (LoginResult, domain.Member, error) {
+	p, ok := u.providers[provider]
+	if !ok {
+		return LoginResult{}, domain.Member{}, ErrUnknownProvider
+	}
+
+	u.mu.Lock()
+	pl, ok := u.pending[state]
+	delete(u.pending, state)
+	u.mu.Unlock()
+	if !ok || pl.provider != provider || time.Now().After(pl.expires) {
+		return LoginResult{}, domain.Member{}, ErrInvalidState
+	}
+
+	ext, err := p.Exchange(ctx, code, pl.nonce)
+	if err != nil {
+		return LoginResult{}, domain.Member{}, err
+	}
+
+	member, err := u.resolveMember(ext, tenant)
+	if err != nil {
+		return LoginResult{}, domain.Member{}, err
+	}
+	if member.IsStaff() && !u.twoFactor.Enabled(member.ID) {
+		return LoginResult{}, domain.Member{}, ErrStaffNeedsTwoFactor
+	}
+
+	res, err := u.auth.SignIn(member.ID)
+	if err != nil {
+		return LoginResult{}, domain.Member{}, err
+	}
+	return res, member, nil
+}
+
+// resolveMember finds the member for an external identity: an already
+// linked member, else a member with the same verified email, else a newly
+// provisioned member of tenant on the default plan. Staff accounts are
+// never linked by email.
+func (u *OIDCUsecase) resolveMember(ext domain.ExternalIdentity, tenant string) (domain.Member, error) {
+	if m, err := u.members.GetMemberByIdentity(ext.Identity); err == nil {
+		return m, nil
+	}
+
+	if ext.EmailVerified {
+		if m, err := u.members.GetMemberByEmail(ext.Email); err == nil {
+			if m.IsStaff() {
+				return domain.Member{}, ErrStaffEmailLink
+			}
+			return u.members.LinkIdentity(m.ID, ext.Identity)
+		}
+	}
+
+	plan, err := u.plans.GetPlanByName(u.defaultPlan)
+	if err != nil {
+		return domain.Member{}, err
+	}
+	name := ext.Name
+	if name == "" {
+		name = ext.Email
+	}
+	m, err := u.members.CreateMember(domain.Member{Name: name, Email: ext.Email, PlanID: plan.ID, Tenant: tenant})
+	if err != nil {
+		return domain.Member{}, err
+	}
+	return u.members.LinkIdentity(m.ID, ext.Identity)
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// OverviewUsecase puts together the state of the server for an
+// operations dashboard, from the request statistics, the task queue, the
+// notification queue and the caches.
+type OverviewUsecase struct {
+	version     string
+	instance    string
+	startedAt   time.Time
+	requests    *RequestStatsUsecase
+	tasks       *TaskUsecase
+	notifier    *BookNotifier
+	books       *BookCache
+	maintenance *MaintenanceUsecase
+}
+
+func NewOverviewUsecase(version, instance string, startedAt time.Time, requests *RequestStatsUsecase, tasks *TaskUsecase, notifier *BookNotifier, books *BookCache, maintenance *MaintenanceUsecase) *OverviewUsecase {
+	return &OverviewUsecase{
+		version:     version,
+		instance:    instance,
+		startedAt:   startedAt,
+		requests:    requests,
+		tasks:       tasks,
+		notifier:    notifier,
+		books:       books,
+		maintenance: maintenance,
+	}
+}
+
+// Overview reports the state of the server as of now, with the top
+// busiest routes.
+func (u *OverviewUsecase) Overview(now time.Time, top int) domain.Overview {
+	endpoints, requests, errors := u.requests.Top(top)
+	return domain.Overview{
+		Version:       u.version,
+		Instance:      u.instance,
+		StartedAt:     u.startedAt,
+		UptimeSeconds: int64(now.Sub(u.startedAt).Seconds()),
+		Maintenance:   u.maintenance.Status().Enabled,
+		Tasks:         u.tasks.Counts(),
+		TaskQueues:    u.tasks.Queues(),
+		Notifications: u.notifier.Stats(),
+		Caches:        map[string]domain.CacheStats{"books": u.books.Stats()},
+		Requests:      requests,
+		ServerErrors:  errors,
+		TopEndpoints:  endpoints,
+		RecentErrors:  u.requests.Recent(),
+	}
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// PaymentExpiry is how long a member has to complete a payment before
+// reconciliation cancels it, freeing its fines for another payment.
+const PaymentExpiry = 24 * time.Hour
+
+var (
+	ErrPaymentNotFound    = domain.NotFound("payment not found")
+	ErrNothingToPay       = domain.Invalid("no unpaid fines to pay")
+	ErrFinePaymentPending = domain.Conflict("fine is already in a pending payment")
+)
+
+// PaymentGateway takes card payments, e.g. through Stripe. reference
+// identifies the payment to the provider, which must not create a second
+// intent when the same reference is sent again.
+type PaymentGateway interface {
+	CreatePaymentIntent(ctx context.Context, amount int, currency, description, reference string) (domain.PaymentIntent, error)
+	GetPaymentIntent(ctx context.Context, id string) (domain.PaymentIntent, error)
+	CancelPaymentIntent(ctx context.Context, id string) (domain.PaymentIntent, error)
+}
+
+// PaymentUsecase lets members pay their fines online. A payment is
+// pending until the provider reports it settled, through a webhook event
+// or through reconciliation; only then are its fines marked paid.
+type PaymentUsecase struct {
+	mu       sync.RWMutex
+	gateway  PaymentGateway
+	fines    *FineUsecase
+	currency string
+	payments []domain.Payment
+	// events are the webhook events already handled, since providers
+	// deliver them at least once.
+	events map[string]bool
+	nextID int
+}
+
+func NewPaymentUsecase(gateway PaymentGateway, fines *FineUsecase, currency string) *PaymentUsecase {
+	return &PaymentUsecase{
+		gateway:  gateway,
+		fines:    fines,
+		currency: currency,
+		payments: []domain.Payment{},
+		events:   map[string]bool{},
+		nextID:   1,
+	}
+}
+
+// GetPayments returns every payment, without client secrets.
+func (u *PaymentUsecase) GetPayments() []domain.Payment {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	payments := make([]domain.Payment, len(u.payments))
+	for i, p := range u.payments {
+		p.ClientSecret = ""
+		payments[i] = p
+	}
+	return payments
+}
+
+// PaymentsForMember returns the member's payments. Pending ones keep
+// their client secret so the member can finish paying.
+func (u *PaymentUsecase) PaymentsForMember(memberID int) []domain.Payment {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	payments := []domain.Payment{}
+	for _, p := range u.payments {
+		if p.MemberID == memberID {
+			if p.Status != domain.PaymentPending {
+				p.ClientSecret = ""
+			}
+			payments = append(payments, p)
+		}
+	}
+	return payments
+}
+
+// PayFines starts a payment of the member's fines with the given IDs, or
+// of all their unpaid fines when fineIDs is empty. The returned payment's
+// client secret completes it with the provider.
+func (u *PaymentUsecase) PayFines(ctx context.Context, memberID int, fineIDs []int) (domain.Payment, error) {
+	fines, err := u.fines.UnpaidFines(memberID, fineIDs)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	payment := domain.Payment{MemberID: memberID, FineIDs: []int{}, Currency: u.currency, Status: domain.PaymentPending}
+	for _, f := range fines {
+		payment.FineIDs = append(payment.FineIDs, f.ID)
+		payment.Amount += f.Amount
+	}
+	if payment.Amount == 0 {
+		return domain.Payment{}, ErrNothingToPay
+	}
+
+	// The payment is recorded before the provider is called, so that its
+	// fines cannot go into a second payment meanwhile.
+	u.mu.Lock()
+	for _, id := range payment.FineIDs {
+		if u.pendingFine(id) {
+			u.mu.Unlock()
+			return domain.Payment{}, ErrFinePaymentPending
+		}
+	}
+	payment.ID = u.nextID
+	payment.CreatedAt = time.Now()
+	u.nextID++
+	u.payments = append(u.payments, payment)
+	u.mu.Unlock()
+
+	// IDs start over when the server restarts, so the creation time keeps
+	// references unique.
+	reference := fmt.Sprintf("library-payment-%d-%d", payment.ID, payment.CreatedAt.Unix())
+	description := fmt.Sprintf("Library fines (%d)", len(payment.FineIDs))
+	intent, err := u.gateway.CreatePaymentIntent(ctx, payment.Amount, u.currency, description, reference)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := u.index(payment.ID)
+	if err != nil {
+		u.payments = append(u.payments[:i], u.payments[i+1:]...)
+		return domain.Payment{}, err
+	}
+	u.payments[i].IntentID = intent.ID
+	u.payments[i].ClientSecret = intent.ClientSecret
+	return u.payments[i], nil
+}
+
+// HandleEvent applies a webhook event from the provider. Events seen
+// before are ignored. It returns ErrPaymentNotFound for events about
+// intents the library did not create.
+func (u *PaymentUsecase) HandleEvent(event domain.PaymentEvent) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.events[event.ID] {
+		return nil
+	}
+	switch event.Type {
+	case domain.EventIntentSucceeded, domain.EventIntentFailed, domain.EventIntentCanceled:
+	default:
+		u.events[event.ID] = true
+		return nil
+	}
+
+	i := u.byIntent(event.Intent.ID)
+	if i < 0 {
+		return ErrPaymentNotFound
+	}
+	u.settle(i, event.Intent, time.Now())
+	u.events[event.ID] = true
+	return nil
+}
+
+// Reconcile asks the provider about every pending payment, settling those
+// whose webhook events never arrived, and cancels payments left
+// unfinished for longer than PaymentExpiry.
+func (u *PaymentUsecase) Reconcile(ctx context.Context, now time.Time) domain.PaymentReconciliation {
+	u.mu.RLock()
+	pending := []domain.Payment{}
+	for _, p := range u.payments {
+		if p.Status == domain.PaymentPending && p.IntentID != "" {
+			pending = append(pending, p)
+		}
+	}
+	u.mu.RUnlock()
+
+	var result domain.PaymentReconciliation
+	for _, p := range pending {
+		result.Checked++
+		intent, err := u.gateway.GetPaymentIntent(ctx, p.IntentID)
+		if err == nil && now.Sub(p.CreatedAt) > PaymentExpiry &&
+			intent.Status != domain.IntentSucceeded && intent.Status != domain.IntentCanceled {
+			intent, err = u.gateway.CancelPaymentIntent(ctx, p.IntentID)
+		}
+		if err != nil {
+			result.Errors++
+			continue
+		}
+
+		u.mu.Lock()
+		if i := u.index(p.ID); i >= 0 {
+			switch u.settle(i, intent, now) {
+			case domain.PaymentSucceeded:
+				result.Succeeded++
+			case domain.PaymentFailed:
+				result.Failed++
+			case domain.PaymentCanceled:
+				result.Canceled++
+			}
+		}
+		u.mu.Unlock()
+	}
+	return result
+}
+
+// settle brings a pending payment in line with its intent and returns
+// its new status. A payment succeeds only once the full amount is
+// received; the fines are then marked paid. Failed attempts are noted
+// but leave the payment pending, since the member may try another card.
+// It expects the caller to hold the lock.
+func (u *PaymentUsecase) settle(i int, intent domain.PaymentIntent, now time.Time) string {
+	p := &u.payments[i]
+	if p.Status != domain.PaymentPending {
+		return ""
+	}
+	switch intent.Status {
+	case domain.IntentSucceeded:
+		if intent.AmountReceived < p.Amount || intent.Currency != p.Currency {
+			p.Status = domain.PaymentFailed
+			p.FailureReason = fmt.Sprintf("received %d %s of %d %s", intent.AmountReceived, intent.Currency, p.Amount, p.Currency)
+		} else {
+			p.Status = domain.PaymentSucceeded
+			u.fines.MarkPaid(p.FineIDs, p.ID, now)
+		}
+	case domain.IntentCanceled:
+		p.Status = domain.PaymentCanceled
+	default:
+		if intent.FailureMessage != "" {
+			p.FailureReason = intent.FailureMessage
+		}
+		return ""
+	}
+	p.ClientSecret = ""
+	p.SettledAt = &now
+	return p.Status
+}
+
+// pendingFine expects the caller to hold the lock.
+func (u *PaymentUsecase) pendingFine(fineID int) bool {
+	for _, p := range u.payments {
+		if p.Status != domain.PaymentPending {
+			continue
+		}
+		for _, id := range p.FineIDs {
+			if id == fineID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// index expects the caller to hold the lock.
+func (u *PaymentUsecase) index(id int) int {
+	for i, p := range u.payments {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// byIntent expects the caller to hold the lock.
+func (u *PaymentUsecase) byIntent(intentID string) int {
+	for i, p := range u.payments {
+		if p.IntentID != "" && p.IntentID == intentID {
+			return i
+		}
+	}
+	return -1
+}
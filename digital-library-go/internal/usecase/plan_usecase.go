@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrPlanNotFound = domain.NotFound("plan not found")
+	ErrPlanExists   = domain.Conflict("plan with this name already exists")
+	ErrPlanInUse    = domain.Conflict("members are still on this plan")
+)
+
+type PlanUsecase struct {
+	mu     sync.RWMutex
+	plans  []domain.Plan
+	nextID int
+}
+
+// NewPlanUsecase seeds the store with the default student, adult and
+// premium plans.
+func NewPlanUsecase() *PlanUsecase {
+	return &PlanUsecase{
+		plans: []domain.Plan{
+			{ID: 1, Name: "student", MaxLoans: 3, LoanDays: 14, MaxHolds: 2},
+			{ID: 2, Name: "adult", MaxLoans: 5, LoanDays: 21, MaxHolds: 5},
+			{ID: 3, Name: "premium", MaxLoans: 10, LoanDays: 28, MaxHolds: 10},
+		},
+		nextID: 4,
+	}
+}
+
+func (u *PlanUsecase) GetPlans() []domain.Plan {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Plan(nil), u.plans...)
+}
+
+func (u *PlanUsecase) GetPlanByID(id int) (domain.Plan, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, p := range u.plans {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Plan{}, ErrPlanNotFound
+}
+
+func (u *PlanUsecase) GetPlanByName(name string) (domain.Plan, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, p := range u.plans {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return domain.Plan{}, ErrPlanNotFound
+}
+
+func (u *PlanUsecase) CreatePlan(plan domain.Plan) (domain.Plan, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, p := range u.plans {
+		if p.Name == plan.Name {
+			return domain.Plan{}, ErrPlanExists
+		}
+	}
+	plan.ID = u.nextID
+	u.nextID++
+	u.plans = append(u.plans, plan)
+	return plan, nil
+}
+
+func (u *PlanUsecase) UpdatePlan(id int, updated domain.Plan) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := slices.IndexFunc(u.plans, func(p domain.Plan) bool { return p.ID == id })
+	if i < 0 {
+		return ErrPlanNotFound
+	}
+	if slices.ContainsFunc(u.plans, func(p domain.Plan) bool { return p.ID != id && p.Name == updated.Name }) {
+		return ErrPlanExists
+	}
+	updated.ID = id
+	u.plans[i] = updated
+	return nil
+}
+
+// remove deletes a plan. MemberUsecase.DeletePlan calls it once no
+// member is on it.
+func (u *PlanUsecase) remove(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, p := range u.plans {
+		if p.ID == id {
+			u.plans = append(u.plans[:i], u.plans[i+1:]...)
+			return nil
+		}
+	}
+	return ErrPlanNotFound
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const (
+	// DefaultHalfLife is how long it takes a view or borrow to count for
+	// half as much.
+	DefaultHalfLife = 7 * 24 * time.Hour
+	// BorrowWeight is how many views one borrow is worth in the score.
+	BorrowWeight = 5
+	// trendingSize is how many books the ranking keeps.
+	trendingSize = 100
+)
+
+// bookActivity holds a book's decayed counts as of at.
+type bookActivity struct {
+	views, borrows float64
+	at             time.Time
+}
+
+// PopularityUsecase counts book views and borrows with exponential decay
+// and ranks the trending books. Events arrive through Record and are
+// applied by ProcessEvents in the background; Rank recomputes the cached
+// ranking that Trending serves.
+type PopularityUsecase struct {
+	books    *BookUsecase
+	halfLife time.Duration
+	events   chan domain.BookEvent
+
+	mu       sync.Mutex
+	activity map[int]*bookActivity
+
+	reportMu sync.RWMutex
+	report   domain.TrendingReport
+}
+
+func NewPopularityUsecase(books *BookUsecase, halfLife time.Duration) *PopularityUsecase {
+	return &PopularityUsecase{
+		books:    books,
+		halfLife: halfLife,
+		events:   make(chan domain.BookEvent, 1024),
+		activity: map[int]*bookActivity{},
+		report:   domain.TrendingReport{HalfLife: halfLife.String(), Books: []domain.TrendingBook{}},
+	}
+}
+
+// Record queues an event without blocking. If the queue is full the event
+// is dropped: popularity is approximate anyway, and a request must never
+// wait on it.
+func (u *PopularityUsecase) Record(e domain.BookEvent) {
+	select {
+	case u.events <- e:
+	default:
+		log.Println("Popularity event dropped for book", e.BookID)
+	}
+}
+
+// ProcessEvents applies queued events until the queue is closed.
+func (u *PopularityUsecase) ProcessEvents() {
+	for e := range u.events {
+		u.apply(e)
+	}
+}
+
+func (u *PopularityUsecase) apply(e domain.BookEvent) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	c := u.activity[e.BookID]
+	if c == nil {
+		c = &bookActivity{at: e.At}
+		u.activity[e.BookID] = c
+	}
+	u.decay(c, e.At)
+	switch e.Kind {
+	case domain.BookViewed:
+		c.views++
+	case domain.BookBorrowed:
+		c.borrows++
+	}
+}
+
+// decay brings a book's counts forward to now. Events that arrive out of order
+// are counted as if they happened at its last update.
+func (u *PopularityUsecase) decay(c *bookActivity, now time.Time) {
+	if !now.After(c.at) {
+		return
+	}
+	f := math.Exp2(-float64(now.Sub(c.at)) / float64(u.halfLife))
+	c.views *= f
+	c.borrows *= f
+	c.at = now
+}
+
+// Rank recomputes the trending books as of now and caches the result.
+// Books whose counts have decayed to almost nothing, or that were
+// deleted, are dropped.
+func (u *PopularityUsecase) Rank(now time.Time) {
+	u.mu.Lock()
+	ranked := []domain.TrendingBook{}
+	for id, c := range u.activity {
+		u.decay(c, now)
+		book, err := u.books.GetBookByID(id)
+		score := c.views + BorrowWeight*c.borrows
+		if err != nil || score < 0.01 {
+			delete(u.activity, id)
+			continue
+		}
+		ranked = append(ranked, domain.TrendingBook{Book: book, Views: c.views, Borrows: c.borrows, Score: score})
+	}
+	u.mu.Unlock()
+
+	slices.SortFunc(ranked, func(a, b domain.TrendingBook) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return a.Book.ID - b.Book.ID
+	})
+	if len(ranked) > trendingSize {
+		ranked = ranked[:trendingSize]
+	}
+
+	u.reportMu.Lock()
+	defer u.reportMu.Unlock()
+	u.report = domain.TrendingReport{ComputedAt: now, HalfLife: u.halfLife.String(), Books: ranked}
+}
+
+// Trending returns the cached ranking, cut to limit books.
+func (u *PopularityUsecase) Trending(limit int) domain.TrendingReport {
+	u.reportMu.RLock()
+	defer u.reportMu.RUnlock()
+	report := u.report
+	report.Books = slices.Clone(report.Books[:min(limit, len(report.Books))])
+	return report
+}
+
+// Scores returns the score of each trending book in the cached ranking,
+// by book ID. Books that are not trending have no score.
+func (u *PopularityUsecase) Scores() map[int]float64 {
+	u.reportMu.RLock()
+	defer u.reportMu.RUnlock()
+	scores := make(map[int]float64, len(u.report.Books))
+	for _, b := range u.report.Books {
+		scores[b.Book.ID] = b.Score
+	}
+	return scores
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/kafka"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/nats"
+)
+
+const (
+	// publishBatch bounds the events sent per Flush.
+	publishBatch = 500
+	// publishTimeout bounds sending one event.
+	publishTimeout = 10 * time.Second
+)
+
+// EventPublisher sends catalog events to a message broker.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.CatalogEvent) error
+}
+
+// PublisherUsecase relays the catalog's change log to a broker, in
+// order. An event that cannot be sent is retried on the next Flush, and
+// the ones after it wait, so consumers never see a book's events out of
+// order.
+type PublisherUsecase struct {
+	books     *BookUsecase
+	publisher EventPublisher
+
+	mu      sync.Mutex
+	seq     uint64
+	lastErr string
+}
+
+func NewPublisherUsecase(books *BookUsecase, publisher EventPublisher) *PublisherUsecase {
+	return &PublisherUsecase{books: books, publisher: publisher}
+}
+
+// Flush sends the events for every change since the last one sent. It is
+// called periodically from main.
+func (u *PublisherUsecase) Flush() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	changes, err := u.books.ChangesAfter(u.seq, publishBatch)
+	if errors.Is(err, ErrCursorExpired) {
+		// The broker was down for longer than the log reaches back.
+		changes, _ = u.books.ChangesAfter(0, publishBatch)
+		if len(changes) > 0 {
+			log.Printf("Events: changes %d to %d were dropped from the log before they could be published", u.seq+1, changes[0].Seq-1)
+		}
+	}
+
+	for _, c := range changes {
+		event := domain.CatalogEvent{
+			ID:     u.books.Epoch() + "." + strconv.FormatUint(c.Seq, 10),
+			Type:   "book." + c.Kind,
+			BookID: c.BookID,
+			At:     c.At,
+		}
+		if c.Kind != domain.BookDeleted {
+			if b, err := u.books.GetBookByID(c.BookID); err == nil {
+				event.Book = &b
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		err := u.publisher.Publish(ctx, event)
+		cancel()
+		if err != nil {
+			// Log each distinct failure once rather than every second.
+			if err.Error() != u.lastErr {
+				log.Printf("Events: publishing failed, will retry: %v", err)
+				u.lastErr = err.Error()
+			}
+			return
+		}
+		if u.lastErr != "" {
+			log.Println("Events: publishing recovered")
+			u.lastErr = ""
+		}
+		u.seq = c.Seq
+	}
+}
+
+// natsPublisher sends each event on <prefix>.<type>, e.g.
+// library.book.updated, so consumers can subscribe to the kinds they
+// need.
+type natsPublisher struct {
+	client *nats.Client
+	prefix string
+}
+
+func NewNATSPublisher(client *nats.Client, prefix string) EventPublisher {
+	return &natsPublisher{client: client, prefix: prefix}
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, event domain.CatalogEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return p.client.Publish(ctx, p.prefix+"."+event.Type, data)
+}
+
+// kafkaPublisher sends every event to one topic, keyed by book ID so
+// each book's events stay in order on one partition.
+type kafkaPublisher struct {
+	client *kafka.Client
+	topic  string
+}
+
+func NewKafkaPublisher(client *kafka.Client, topic string) EventPublisher {
+	return &kafkaPublisher{client: client, topic: topic}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, event domain.CatalogEvent) error {
+	return p.client.Produce(ctx, p.topic, strconv.Itoa(event.BookID), event)
+}
@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrOrderNotFound   = domain.NotFound("purchase order not found")
+	ErrVendorNotFound  = domain.NotFound("vendor not found")
+	ErrVendorHasOrders = domain.Conflict("vendor still has purchase orders")
+	ErrOrderNotDraft   = domain.Conflict("only draft orders can be changed or sent")
+	ErrOrderNotSent    = domain.Conflict("only sent orders can be received")
+	ErrEmptyOrder      = domain.Conflict("order has no items")
+	ErrReceiveLocation = domain.Invalid("floor and section must be set, and floor, section and shelf must not contain /")
+)
+
+// PurchasingUsecase manages vendors and the purchase orders placed with
+// them. Receiving an order adds its copies to the collection.
+type PurchasingUsecase struct {
+	mu         sync.RWMutex
+	vendors    []domain.Vendor
+	orders     []domain.PurchaseOrder
+	nextVendor int
+	nextOrder  int
+	books      *BookUsecase
+	copies     *CopyUsecase
+}
+
+func NewPurchasingUsecase(books *BookUsecase, copies *CopyUsecase) *PurchasingUsecase {
+	return &PurchasingUsecase{
+		vendors:    []domain.Vendor{},
+		orders:     []domain.PurchaseOrder{},
+		nextVendor: 1,
+		nextOrder:  1,
+		books:      books,
+		copies:     copies,
+	}
+}
+
+func (u *PurchasingUsecase) GetVendors() []domain.Vendor {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Vendor(nil), u.vendors...)
+}
+
+func (u *PurchasingUsecase) GetVendorByID(id int) (domain.Vendor, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.vendor(id)
+}
+
+func (u *PurchasingUsecase) CreateVendor(vendor domain.Vendor) domain.Vendor {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	vendor.ID = u.nextVendor
+	u.nextVendor++
+	u.vendors = append(u.vendors, vendor)
+	return vendor
+}
+
+func (u *PurchasingUsecase) UpdateVendor(id int, updated domain.Vendor) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, v := range u.vendors {
+		if v.ID == id {
+			updated.ID = id
+			u.vendors[i] = updated
+			return nil
+		}
+	}
+	return ErrVendorNotFound
+}
+
+// DeleteVendor removes a vendor no order was placed with.
+func (u *PurchasingUsecase) DeleteVendor(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, v := range u.vendors {
+		if v.ID == id {
+			for _, o := range u.orders {
+				if o.VendorID == id {
+					return ErrVendorHasOrders
+				}
+			}
+			u.vendors = append(u.vendors[:i], u.vendors[i+1:]...)
+			return nil
+		}
+	}
+	return ErrVendorNotFound
+}
+
+// GetOrders returns the purchase orders with the given status, or all of
+// them if status is empty.
+func (u *PurchasingUsecase) GetOrders(status string) []domain.PurchaseOrder {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	orders := []domain.PurchaseOrder{}
+	for _, o := range u.orders {
+		if status == "" || o.Status == status {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+func (u *PurchasingUsecase) GetOrderByID(id int) (domain.PurchaseOrder, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	return u.orders[i], nil
+}
+
+// CreateOrder drafts a purchase order. Its vendor and the books it
+// orders must exist.
+func (u *PurchasingUsecase) CreateOrder(order domain.PurchaseOrder, now time.Time) (domain.PurchaseOrder, error) {
+	if err := u.checkBooks(order.Items); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, err := u.vendor(order.VendorID); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	order.ID = u.nextOrder
+	u.nextOrder++
+	order.Status = domain.OrderDraft
+	order.CreatedAt = now
+	order.SentAt, order.ReceivedAt = nil, nil
+	order.Items = clearCopies(order.Items)
+	order.Total = order.Sum()
+	u.orders = append(u.orders, order)
+	return order, nil
+}
+
+// UpdateOrder replaces the vendor, fund and items of a draft order.
+func (u *PurchasingUsecase) UpdateOrder(id int, updated domain.PurchaseOrder) (domain.PurchaseOrder, error) {
+	if err := u.checkBooks(updated.Items); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	if u.orders[i].Status != domain.OrderDraft {
+		return domain.PurchaseOrder{}, ErrOrderNotDraft
+	}
+	if _, err := u.vendor(updated.VendorID); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	o := &u.orders[i]
+	o.VendorID = updated.VendorID
+	o.FundCode = updated.FundCode
+	o.Items = clearCopies(updated.Items)
+	o.Total = o.Sum()
+	return *o, nil
+}
+
+// DeleteOrder discards a draft order. Orders that were sent stay on
+// record.
+func (u *PurchasingUsecase) DeleteOrder(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return err
+	}
+	if u.orders[i].Status != domain.OrderDraft {
+		return ErrOrderNotDraft
+	}
+	u.orders = append(u.orders[:i], u.orders[i+1:]...)
+	return nil
+}
+
+// Send marks a draft order as sent to its vendor.
+func (u *PurchasingUsecase) Send(id int, now time.Time) (domain.PurchaseOrder, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	o := &u.orders[i]
+	if o.Status != domain.OrderDraft {
+		return domain.PurchaseOrder{}, ErrOrderNotDraft
+	}
+	if len(o.Items) == 0 {
+		return domain.PurchaseOrder{}, ErrEmptyOrder
+	}
+	o.Status = domain.OrderSent
+	o.SentAt = &now
+	return *o, nil
+}
+
+// Receive marks a sent order as received and adds a copy for every item
+// ordered, shelved where shelf says. Each copy records its price, the
+// vendor and the fund, and gets a barcode "PO<order>-<item>-<n>" until
+// it is labeled. Everything is checked before any copy is made; the
+// copies are then added together, and the order is only marked received
+// if they all were.
+func (u *PurchasingUsecase) Receive(id int, shelf domain.Copy, now time.Time) (domain.PurchaseOrder, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	o := &u.orders[i]
+	if o.Status != domain.OrderSent {
+		return domain.PurchaseOrder{}, ErrOrderNotSent
+	}
+	if err := o.Validate(); err != nil {
+		return domain.PurchaseOrder{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	if err := u.checkBooks(o.Items); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	vendor, _ := u.vendor(o.VendorID)
+
+	copies := []domain.Copy{}
+	for n, item := range o.Items {
+		fund := item.FundCode
+		if fund == "" {
+			fund = o.FundCode
+		}
+		for k := 1; k <= item.Quantity; k++ {
+			c := shelf
+			c.BookID = item.BookID
+			c.Barcode = fmt.Sprintf("PO%d-%d-%d", o.ID, n+1, k)
+			c.Price = item.UnitPrice
+			c.Vendor = vendor.Name
+			c.FundCode = fund
+			c.PurchasedAt = &now
+			if err := c.Validate(); err != nil {
+				return domain.PurchaseOrder{}, ErrReceiveLocation
+			}
+			copies = append(copies, c)
+		}
+	}
+
+	added, err := u.copies.addCopies(copies)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	for n, item := range o.Items {
+		o.Items[n].CopyIDs = []int{}
+		for _, c := range added[:item.Quantity] {
+			o.Items[n].CopyIDs = append(o.Items[n].CopyIDs, c.ID)
+		}
+		added = added[item.Quantity:]
+	}
+	o.Status = domain.OrderReceived
+	o.ReceivedAt = &now
+	return *o, nil
+}
+
+func (u *PurchasingUsecase) checkBooks(items []domain.OrderItem) error {
+	for _, item := range items {
+		if _, err := u.books.GetBookByID(item.BookID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func clearCopies(items []domain.OrderItem) []domain.OrderItem {
+	items = append([]domain.OrderItem{}, items...)
+	for i := range items {
+		items[i].CopyIDs = nil
+	}
+	return items
+}
+
+// vendor expects the caller to hold the lock.
+func (u *PurchasingUsecase) vendor(id int) (domain.Vendor, error) {
+	for _, v := range u.vendors {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return domain.Vendor{}, ErrVendorNotFound
+}
+
+// index expects the caller to hold the lock.
+func (u *PurchasingUsecase) index(id int) (int, error) {
+	for i, o := range u.orders {
+		if o.ID == id {
+			return i, nil
+		}
+	}
+	return 0, ErrOrderNotFound
+}
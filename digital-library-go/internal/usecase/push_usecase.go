@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// maxPushSubscriptions bounds the browsers one member can subscribe.
+const maxPushSubscriptions = 10
+
+var ErrPushSubscriptionLimit = domain.Conflict("too many push subscriptions; remove one first")
+
+// PushSender delivers an encrypted message to one subscription, e.g.
+// through Web Push. It returns domain.ErrSubscriptionGone when the
+// browser has unsubscribed.
+type PushSender interface {
+	Push(ctx context.Context, sub domain.PushSubscription, payload []byte) error
+}
+
+// PushUsecase keeps members' browser push subscriptions and is the push
+// notification channel: a notification goes to every browser the member
+// subscribed.
+type PushUsecase struct {
+	mu            sync.RWMutex
+	subscriptions []domain.PushSubscription
+	nextID        int
+	sender        PushSender
+}
+
+func NewPushUsecase(sender PushSender) *PushUsecase {
+	return &PushUsecase{
+		subscriptions: []domain.PushSubscription{},
+		nextID:        1,
+		sender:        sender,
+	}
+}
+
+func (u *PushUsecase) Name() string {
+	return domain.ChannelPush
+}
+
+// Send pushes a notification to each of the member's browsers.
+// Subscriptions the push service reports as gone are removed.
+func (u *PushUsecase) Send(ctx context.Context, member domain.Member, n domain.Notification) error {
+	payload, err := json.Marshal(domain.PushMessage{
+		Title:          n.Title(),
+		Body:           n.Message,
+		Kind:           n.Kind,
+		BookID:         n.BookID,
+		NotificationID: n.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sub := range u.SubscriptionsForMember(member.ID) {
+		err := u.sender.Push(ctx, sub, payload)
+		if errors.Is(err, domain.ErrSubscriptionGone) {
+			u.Unsubscribe(member.ID, sub.ID)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (u *PushUsecase) SubscriptionsForMember(memberID int) []domain.PushSubscription {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	subs := []domain.PushSubscription{}
+	for _, s := range u.subscriptions {
+		if s.MemberID == memberID {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+// Subscribe adds a browser subscription for the member. Subscribing the
+// same endpoint again replaces its keys, since browsers resubscribe with
+// the endpoint they already have. An endpoint belongs to one member at a
+// time.
+func (u *PushUsecase) Subscribe(memberID int, sub domain.PushSubscription) (domain.PushSubscription, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.subscriptions = slices.DeleteFunc(u.subscriptions, func(s domain.PushSubscription) bool {
+		return s.Endpoint == sub.Endpoint && s.MemberID != memberID
+	})
+
+	count := 0
+	for i, s := range u.subscriptions {
+		if s.MemberID != memberID {
+			continue
+		}
+		if s.Endpoint == sub.Endpoint {
+			u.subscriptions[i].Keys = sub.Keys
+			return u.subscriptions[i], nil
+		}
+		count++
+	}
+	if count >= maxPushSubscriptions {
+		return domain.PushSubscription{}, ErrPushSubscriptionLimit
+	}
+
+	sub.ID = u.nextID
+	sub.MemberID = memberID
+	sub.CreatedAt = time.Now()
+	u.nextID++
+	u.subscriptions = append(u.subscriptions, sub)
+	return sub, nil
+}
+
+func (u *PushUsecase) Unsubscribe(memberID, id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, s := range u.subscriptions {
+		if s.ID == id && s.MemberID == memberID {
+			u.subscriptions = append(u.subscriptions[:i], u.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NotFound("push subscription not found")
+}
+
+func (u *PushUsecase) DeleteSubscriptionsForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.subscriptions = slices.DeleteFunc(u.subscriptions, func(s domain.PushSubscription) bool {
+		return s.MemberID == memberID
+	})
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var ErrReadingListNotFound = domain.NotFound("reading list not found")
+
+// ReadingListUsecase stores members' reading lists. Every method takes the
+// owning member so one member can never reach another's lists.
+type ReadingListUsecase struct {
+	mu     sync.RWMutex
+	lists  []domain.ReadingList
+	nextID int
+	books  *BookUsecase
+}
+
+func NewReadingListUsecase(books *BookUsecase) *ReadingListUsecase {
+	return &ReadingListUsecase{
+		lists:  []domain.ReadingList{},
+		nextID: 1,
+		books:  books,
+	}
+}
+
+func (u *ReadingListUsecase) ListsForMember(memberID int) []domain.ReadingList {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	lists := []domain.ReadingList{}
+	for _, l := range u.lists {
+		if l.MemberID == memberID {
+			lists = append(lists, l)
+		}
+	}
+	return lists
+}
+
+func (u *ReadingListUsecase) CreateList(memberID int, list domain.ReadingList) domain.ReadingList {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	list.ID = u.nextID
+	list.MemberID = memberID
+	list.BookIDs = []int{}
+	u.nextID++
+	u.lists = append(u.lists, list)
+	return list
+}
+
+func (u *ReadingListUsecase) DeleteList(memberID, id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, l := range u.lists {
+		if l.ID == id && l.MemberID == memberID {
+			u.lists = append(u.lists[:i], u.lists[i+1:]...)
+			return nil
+		}
+	}
+	return ErrReadingListNotFound
+}
+
+func (u *ReadingListUsecase) AddBook(memberID, id, bookID int) (domain.ReadingList, error) {
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return domain.ReadingList{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, l := range u.lists {
+		if l.ID == id && l.MemberID == memberID {
+			if !slices.Contains(l.BookIDs, bookID) {
+				u.lists[i].BookIDs = append(l.BookIDs, bookID)
+			}
+			return u.lists[i], nil
+		}
+	}
+	return domain.ReadingList{}, ErrReadingListNotFound
+}
+
+func (u *ReadingListUsecase) DeleteListsForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.lists = slices.DeleteFunc(u.lists, func(l domain.ReadingList) bool {
+		return l.MemberID == memberID
+	})
+}
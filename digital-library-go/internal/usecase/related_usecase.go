@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// DefaultMinCoBorrowers is how many members must have borrowed two books
+// before one is shown as related to the other, so that a single reader's
+// history cannot be read off the graph.
+const DefaultMinCoBorrowers = 2
+
+// RelatedUsecase keeps a graph of books borrowed by the same members,
+// for "readers also borrowed". It is updated on every checkout, so
+// queries only read one book's neighbours and never scan the loans.
+type RelatedUsecase struct {
+	books      *BookUsecase
+	minReaders int
+
+	mu sync.RWMutex
+	// borrowed is the set of books each member has ever borrowed.
+	borrowed map[int]map[int]bool
+	// edges counts, for each pair of books, the members who borrowed
+	// both. It is symmetric.
+	edges map[int]map[int]int
+}
+
+func NewRelatedUsecase(books *BookUsecase, minReaders int) *RelatedUsecase {
+	return &RelatedUsecase{
+		books:      books,
+		minReaders: minReaders,
+		borrowed:   map[int]map[int]bool{},
+		edges:      map[int]map[int]int{},
+	}
+}
+
+// Borrowed links a book to every book the member borrowed before. A
+// member borrowing the same book again changes nothing.
+func (u *RelatedUsecase) Borrowed(memberID, bookID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	seen := u.borrowed[memberID]
+	if seen == nil {
+		seen = map[int]bool{}
+		u.borrowed[memberID] = seen
+	}
+	if seen[bookID] {
+		return
+	}
+	for other := range seen {
+		u.link(bookID, other)
+		u.link(other, bookID)
+	}
+	seen[bookID] = true
+}
+
+// link expects the caller to hold the lock.
+func (u *RelatedUsecase) link(from, to int) {
+	if u.edges[from] == nil {
+		u.edges[from] = map[int]int{}
+	}
+	u.edges[from][to]++
+}
+
+// ForgetMember drops which books a member borrowed. The counts they
+// contributed stay, as they no longer say who borrowed what, but later
+// loans by the same member are no longer linked to earlier ones.
+func (u *RelatedUsecase) ForgetMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.borrowed, memberID)
+}
+
+// Related returns the books borrowed by at least the minimum number of
+// readers of bookID, those shared by the most readers first. Deleted
+// books are skipped.
+func (u *RelatedUsecase) Related(bookID int) []domain.RelatedBook {
+	u.mu.RLock()
+	counts := make(map[int]int, len(u.edges[bookID]))
+	for id, n := range u.edges[bookID] {
+		if n >= u.minReaders {
+			counts[id] = n
+		}
+	}
+	u.mu.RUnlock()
+
+	related := make([]domain.RelatedBook, 0, len(counts))
+	for id, n := range counts {
+		book, err := u.books.GetBookByID(id)
+		if err != nil {
+			continue
+		}
+		related = append(related, domain.RelatedBook{Book: book, Readers: n})
+	}
+	slices.SortFunc(related, func(a, b domain.RelatedBook) int {
+		if a.Readers != b.Readers {
+			return b.Readers - a.Readers
+		}
+		return a.Book.ID - b.Book.ID
+	})
+	return related
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// keptRecentErrors is how many server errors are remembered. The oldest
+// are forgotten first.
+const keptRecentErrors = 50
+
+type routeCounter struct {
+	requests, errors int
+	total, longest   time.Duration
+}
+
+// RequestStatsUsecase counts the requests to each route since the server
+// started, and remembers the latest server errors.
+type RequestStatsUsecase struct {
+	mu     sync.Mutex
+	routes map[string]*routeCounter
+	recent []domain.RecentError
+}
+
+func NewRequestStatsUsecase() *RequestStatsUsecase {
+	return &RequestStatsUsecase{routes: map[string]*routeCounter{}}
+}
+
+// Record counts a request answered with status after latency. Requests
+// that matched no route are only kept as errors, so that probing unknown
+// paths cannot grow the counters.
+func (u *RequestStatsUsecase) Record(e domain.RecentError, latency time.Duration) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	serverError := e.Status >= 500
+	if e.Route != "" {
+		key := e.Method + " " + e.Route
+		r, ok := u.routes[key]
+		if !ok {
+			r = &routeCounter{}
+			u.routes[key] = r
+		}
+		r.requests++
+		r.total += latency
+		r.longest = max(r.longest, latency)
+		if serverError {
+			r.errors++
+		}
+	}
+	if serverError {
+		if len(u.recent) >= keptRecentErrors {
+			u.recent = slices.Delete(u.recent, 0, 1)
+		}
+		u.recent = append(u.recent, e)
+	}
+}
+
+// Top lists the n routes with the most requests, busiest first, with the
+// total of requests and server errors over every route.
+func (u *RequestStatsUsecase) Top(n int) (top []domain.RouteStats, requests, errors int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	top = []domain.RouteStats{}
+	for route, r := range u.routes {
+		requests += r.requests
+		errors += r.errors
+		top = append(top, domain.RouteStats{
+			Route:        route,
+			Requests:     r.requests,
+			Errors:       r.errors,
+			AvgLatencyMs: round3(float64(r.total) / float64(r.requests) / float64(time.Millisecond)),
+			MaxLatencyMs: round3(float64(r.longest) / float64(time.Millisecond)),
+		})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Requests != top[j].Requests {
+			return top[i].Requests > top[j].Requests
+		}
+		return top[i].Route < top[j].Route
+	})
+	return top[:min(n, len(top))], requests, errors
+}
+
+// Recent lists the latest server errors, the latest first.
+func (u *RequestStatsUsecase) Recent() []domain.RecentError {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	recent := slices.Clone(u.recent)
+	slices.Reverse(recent)
+	if recent == nil {
+		recent = []domain.RecentError{}
+	}
+	return recent
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrReviewNotFound  = domain.NotFound("review not found")
+	ErrAlreadyReviewed = domain.Conflict("member has already reviewed this book")
+	ErrAlreadyReported = domain.Conflict("member has already reported this review")
+	ErrNotPending      = domain.Conflict("review is not awaiting moderation")
+)
+
+// DefaultModeration publishes reviews straight away unless they contain
+// a link, and re-queues them after three reports.
+var DefaultModeration = domain.ModerationSettings{BannedWords: []string{}, HoldLinks: true, ReportThreshold: 3}
+
+// ReviewUsecase manages book reviews and their moderation. New reviews
+// are checked against the moderation settings and either published or
+// queued for an admin; members' reports can send a published review back
+// to the queue.
+type ReviewUsecase struct {
+	mu       sync.RWMutex
+	reviews  []domain.Review
+	reports  []domain.ReviewReport
+	settings domain.ModerationSettings
+	nextID   int
+	books    *BookUsecase
+	notify   *NotificationUsecase
+}
+
+func NewReviewUsecase(books *BookUsecase, notify *NotificationUsecase, settings domain.ModerationSettings) *ReviewUsecase {
+	u := &ReviewUsecase{
+		reviews: []domain.Review{},
+		reports: []domain.ReviewReport{},
+		nextID:  1,
+		books:   books,
+		notify:  notify,
+	}
+	u.SetSettings(settings)
+	return u
+}
+
+func (u *ReviewUsecase) GetSettings() domain.ModerationSettings {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	s := u.settings
+	s.BannedWords = slices.Clone(s.BannedWords)
+	return s
+}
+
+// SetSettings replaces the moderation settings. Reviews already
+// published or queued are not checked again.
+func (u *ReviewUsecase) SetSettings(settings domain.ModerationSettings) {
+	words := make([]string, 0, len(settings.BannedWords))
+	for _, w := range settings.BannedWords {
+		words = append(words, strings.ToLower(strings.TrimSpace(w)))
+	}
+	settings.BannedWords = words
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.settings = settings
+}
+
+// ReviewsForBook returns the book's published reviews, newest first.
+func (u *ReviewUsecase) ReviewsForBook(bookID int) []domain.Review {
+	return u.find(func(r domain.Review) bool {
+		return r.BookID == bookID && r.Status == domain.ReviewApproved
+	})
+}
+
+// ReviewsForMember returns every review a member wrote, whatever its
+// status, newest first.
+func (u *ReviewUsecase) ReviewsForMember(memberID int) []domain.Review {
+	return u.find(func(r domain.Review) bool { return r.MemberID == memberID })
+}
+
+// Queue returns the reviews awaiting moderation, oldest first.
+func (u *ReviewUsecase) Queue() []domain.Review {
+	queue := u.find(func(r domain.Review) bool { return r.Status == domain.ReviewPending })
+	slices.Reverse(queue)
+	return queue
+}
+
+func (u *ReviewUsecase) GetReviewByID(id int) (domain.Review, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return copyReview(u.reviews[i]), nil
+}
+
+// CreateReview adds a member's review of a book. It is published unless
+// the moderation settings hold it back, in which case Flags says why.
+func (u *ReviewUsecase) CreateReview(memberID, bookID int, review domain.Review) (domain.Review, error) {
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return domain.Review{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, r := range u.reviews {
+		if r.BookID == bookID && r.MemberID == memberID {
+			return domain.Review{}, ErrAlreadyReviewed
+		}
+	}
+	review.ID = u.nextID
+	review.BookID = bookID
+	review.MemberID = memberID
+	review.Reports = 0
+	review.CreatedAt = time.Now()
+	review.Flags = u.check(review)
+	review.Status = domain.ReviewApproved
+	if len(review.Flags) > 0 || u.settings.RequireApproval {
+		review.Status = domain.ReviewPending
+	}
+	u.nextID++
+	u.reviews = append(u.reviews, review)
+	return copyReview(review), nil
+}
+
+func (u *ReviewUsecase) DeleteReview(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return err
+	}
+	u.reviews = append(u.reviews[:i], u.reviews[i+1:]...)
+	u.reports = slices.DeleteFunc(u.reports, func(r domain.ReviewReport) bool { return r.ReviewID == id })
+	return nil
+}
+
+// Report records a member's complaint about a published review. Once
+// ReportThreshold members have reported it, it goes back to the queue.
+func (u *ReviewUsecase) Report(id, memberID int, reason string) (domain.Review, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil || u.reviews[i].Status != domain.ReviewApproved {
+		return domain.Review{}, ErrReviewNotFound
+	}
+	for _, r := range u.reports {
+		if r.ReviewID == id && r.MemberID == memberID {
+			return domain.Review{}, ErrAlreadyReported
+		}
+	}
+	u.reports = append(u.reports, domain.ReviewReport{ReviewID: id, MemberID: memberID, Reason: reason, CreatedAt: time.Now()})
+	u.reviews[i].Reports++
+	if u.reviews[i].Reports >= u.settings.ReportThreshold {
+		u.reviews[i].Status = domain.ReviewPending
+		u.reviews[i].Flags = append(u.reviews[i].Flags, fmt.Sprintf("reported by %d members", u.reviews[i].Reports))
+	}
+	return copyReview(u.reviews[i]), nil
+}
+
+// ReportsForReview returns the reports made about a review, for
+// moderators.
+func (u *ReviewUsecase) ReportsForReview(id int) ([]domain.ReviewReport, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if _, err := u.index(id); err != nil {
+		return nil, err
+	}
+	reports := []domain.ReviewReport{}
+	for _, r := range u.reports {
+		if r.ReviewID == id {
+			reports = append(reports, r)
+		}
+	}
+	return reports, nil
+}
+
+// Approve publishes a queued review and clears its reports.
+func (u *ReviewUsecase) Approve(id int) (domain.Review, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.pending(id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	u.reviews[i].Status = domain.ReviewApproved
+	u.reviews[i].Flags = nil
+	u.reviews[i].Reports = 0
+	u.reports = slices.DeleteFunc(u.reports, func(r domain.ReviewReport) bool { return r.ReviewID == id })
+	return copyReview(u.reviews[i]), nil
+}
+
+// Reject keeps a queued review off the book for good. Its author is
+// notified.
+func (u *ReviewUsecase) Reject(id int) (domain.Review, error) {
+	u.mu.Lock()
+	i, err := u.pending(id)
+	if err != nil {
+		u.mu.Unlock()
+		return domain.Review{}, err
+	}
+	u.reviews[i].Status = domain.ReviewRejected
+	review := copyReview(u.reviews[i])
+	u.mu.Unlock()
+
+	title := fmt.Sprintf("book #%d", review.BookID)
+	if book, err := u.books.GetBookByID(review.BookID); err == nil {
+		title = book.Title
+	}
+	u.notify.Notify(review.MemberID, domain.NotifyReview, "Your review of "+title+" was not published", review.BookID)
+	return review, nil
+}
+
+// DeleteReviewsForMember removes every review and report of a member,
+// for account erasure.
+func (u *ReviewUsecase) DeleteReviewsForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.reviews = slices.DeleteFunc(u.reviews, func(r domain.Review) bool { return r.MemberID == memberID })
+	u.reports = slices.DeleteFunc(u.reports, func(r domain.ReviewReport) bool { return r.MemberID == memberID })
+}
+
+// check lists the reasons the settings hold a review back. It expects the
+// caller to hold the lock.
+func (u *ReviewUsecase) check(review domain.Review) []string {
+	flags := []string{}
+	words := review.Words()
+	for _, banned := range u.settings.BannedWords {
+		if slices.Contains(words, banned) {
+			flags = append(flags, "banned word: "+banned)
+		}
+	}
+	text := strings.ToLower(review.Text)
+	if u.settings.HoldLinks && (strings.Contains(text, "http://") || strings.Contains(text, "https://") || strings.Contains(text, "www.")) {
+		flags = append(flags, "contains a link")
+	}
+	return flags
+}
+
+// find returns the matching reviews, newest first.
+func (u *ReviewUsecase) find(match func(domain.Review) bool) []domain.Review {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	reviews := []domain.Review{}
+	for i := len(u.reviews) - 1; i >= 0; i-- {
+		if match(u.reviews[i]) {
+			reviews = append(reviews, copyReview(u.reviews[i]))
+		}
+	}
+	return reviews
+}
+
+// pending expects the caller to hold the lock.
+func (u *ReviewUsecase) pending(id int) (int, error) {
+	i, err := u.index(id)
+	if err != nil {
+		return 0, err
+	}
+	if u.reviews[i].Status != domain.ReviewPending {
+		return 0, ErrNotPending
+	}
+	return i, nil
+}
+
+// index expects the caller to hold the lock.
+func (u *ReviewUsecase) index(id int) (int, error) {
+	for i, r := range u.reviews {
+		if r.ID == id {
+			return i, nil
+		}
+	}
+	return 0, ErrReviewNotFound
+}
+
+func copyReview(r domain.Review) domain.Review {
+	r.Flags = slices.Clone(r.Flags)
+	return r
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// SavedSearchUsecase stores members' saved searches and notifies them of
+// new books that match. Like reading lists, every method takes the owning
+// member.
+type SavedSearchUsecase struct {
+	mu       sync.Mutex
+	searches []domain.SavedSearch
+	nextID   int
+	books    *BookUsecase
+	notify   *NotificationUsecase
+	// seen holds the IDs of books already checked against the searches,
+	// and version the catalog version they were read at.
+	seen    map[int]bool
+	version uint64
+}
+
+// NewSavedSearchUsecase treats the books already in the catalog as seen,
+// so only books added afterwards trigger notifications.
+func NewSavedSearchUsecase(books *BookUsecase, notify *NotificationUsecase) *SavedSearchUsecase {
+	u := &SavedSearchUsecase{
+		searches: []domain.SavedSearch{},
+		nextID:   1,
+		books:    books,
+		notify:   notify,
+		seen:     map[int]bool{},
+		version:  books.Version(),
+	}
+	for _, b := range books.GetBooks() {
+		u.seen[b.ID] = true
+	}
+	return u
+}
+
+func (u *SavedSearchUsecase) SearchesForMember(memberID int) []domain.SavedSearch {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	searches := []domain.SavedSearch{}
+	for _, s := range u.searches {
+		if s.MemberID == memberID {
+			searches = append(searches, s)
+		}
+	}
+	return searches
+}
+
+func (u *SavedSearchUsecase) SaveSearch(memberID int, search domain.SavedSearch) domain.SavedSearch {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	search.ID = u.nextID
+	search.MemberID = memberID
+	search.CreatedAt = time.Now()
+	u.nextID++
+	u.searches = append(u.searches, search)
+	return search
+}
+
+func (u *SavedSearchUsecase) DeleteSearch(memberID, id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i, s := range u.searches {
+		if s.ID == id && s.MemberID == memberID {
+			u.searches = append(u.searches[:i], u.searches[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NotFound("saved search not found")
+}
+
+func (u *SavedSearchUsecase) DeleteSearchesForMember(memberID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.searches = slices.DeleteFunc(u.searches, func(s domain.SavedSearch) bool {
+		return s.MemberID == memberID
+	})
+}
+
+// CheckNewBooks matches the books added since the last check against
+// every saved search and notifies the members whose searches they match.
+// A member with several matching searches hears about a book once. It
+// returns the number of notifications sent.
+func (u *SavedSearchUsecase) CheckNewBooks() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	version := u.books.Version()
+	if version == u.version {
+		return 0
+	}
+	u.version = version
+
+	sent := 0
+	current := map[int]bool{}
+	for _, b := range u.books.GetBooks() {
+		// Drafts count as new once they are published.
+		if !b.Published() {
+			continue
+		}
+		current[b.ID] = true
+		if u.seen[b.ID] {
+			continue
+		}
+
+		notified := map[int]bool{}
+		for _, s := range u.searches {
+			if notified[s.MemberID] || !s.Matches(b) {
+				continue
+			}
+			notified[s.MemberID] = true
+			u.notify.Notify(s.MemberID, domain.NotifyNewBook, fmt.Sprintf("New book matching %q: %s by %s", s.Query, b.Title, b.Author), b.ID)
+			sent++
+		}
+	}
+	u.seen = current
+	return sent
+}
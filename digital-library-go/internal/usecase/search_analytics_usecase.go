@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const (
+	// DefaultMinTermSearches is how many times a term must be searched for
+	// before it is reported.
+	DefaultMinTermSearches = 3
+	// maxSearchTermsPerDay bounds the distinct terms kept per day; once a
+	// day has that many, searches for new terms are only counted in the
+	// totals.
+	maxSearchTermsPerDay = 10000
+)
+
+type termCount struct {
+	searches, zero int
+}
+
+// searchDay holds the search counts of one day. Filters are keyed by
+// facet and value, separated by a NUL.
+type searchDay struct {
+	searches, zero int
+	terms          map[string]*termCount
+	filters        map[string]int
+}
+
+// SearchAnalyticsUsecase counts catalog searches per day: the terms
+// searched for, those that found nothing and the facet filters used.
+// Only the counts are kept, with nothing about who searched, and terms
+// that look like a card, phone number or email address are not kept at
+// all. Terms searched for rarely are held back from reports, since they
+// could point at the one member who searched for them.
+type SearchAnalyticsUsecase struct {
+	minSearches int
+
+	mu   sync.Mutex
+	days map[string]*searchDay
+}
+
+func NewSearchAnalyticsUsecase(minSearches int) *SearchAnalyticsUsecase {
+	return &SearchAnalyticsUsecase{minSearches: minSearches, days: map[string]*searchDay{}}
+}
+
+// Record counts a search for q narrowed by the facet values in filters,
+// which found results books. Browsing with neither is not a search.
+func (u *SearchAnalyticsUsecase) Record(q string, filters map[string]string, results int, at time.Time) {
+	term, keep := searchTerm(q)
+	if term == "" && len(filters) == 0 {
+		return
+	}
+	zero := 0
+	if results == 0 {
+		zero = 1
+	}
+
+	day := at.UTC().Format(dayFormat)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	d, ok := u.days[day]
+	if !ok {
+		d = &searchDay{terms: map[string]*termCount{}, filters: map[string]int{}}
+		u.days[day] = d
+	}
+	d.searches++
+	d.zero += zero
+	if keep {
+		t, ok := d.terms[term]
+		if !ok && len(d.terms) < maxSearchTermsPerDay {
+			t = &termCount{}
+			d.terms[term] = t
+		}
+		if t != nil {
+			t.searches++
+			t.zero += zero
+		}
+	}
+	for facet, value := range filters {
+		d.filters[facet+"\x00"+strings.ToLower(value)]++
+	}
+}
+
+// Report sums up the searches from from to to inclusive, listing at most
+// limit terms, zero-result terms and filters, the most searched first.
+func (u *SearchAnalyticsUsecase) Report(from, to time.Time, limit int) (domain.SearchAnalytics, error) {
+	if from.After(to) || to.Sub(from) > ViewRetention {
+		return domain.SearchAnalytics{}, ErrInvalidRange
+	}
+	report := domain.SearchAnalytics{
+		From:        from.UTC().Format(dayFormat),
+		To:          to.UTC().Format(dayFormat),
+		MinSearches: u.minSearches,
+	}
+
+	terms := map[string]*termCount{}
+	filters := map[string]int{}
+	u.mu.Lock()
+	for day, d := range u.days {
+		if day < report.From || day > report.To {
+			continue
+		}
+		report.Searches += d.searches
+		report.ZeroResults += d.zero
+		for term, n := range d.terms {
+			t, ok := terms[term]
+			if !ok {
+				t = &termCount{}
+				terms[term] = t
+			}
+			t.searches += n.searches
+			t.zero += n.zero
+		}
+		for key, n := range d.filters {
+			filters[key] += n
+		}
+	}
+	u.mu.Unlock()
+
+	report.Terms, report.ZeroResultTerms = []domain.SearchTerm{}, []domain.SearchTerm{}
+	for term, t := range terms {
+		if t.searches < u.minSearches {
+			continue
+		}
+		st := domain.SearchTerm{Term: term, Searches: t.searches, ZeroResults: t.zero}
+		report.Terms = append(report.Terms, st)
+		if t.zero > 0 {
+			report.ZeroResultTerms = append(report.ZeroResultTerms, st)
+		}
+	}
+	slices.SortFunc(report.Terms, func(a, b domain.SearchTerm) int {
+		if a.Searches != b.Searches {
+			return b.Searches - a.Searches
+		}
+		return strings.Compare(a.Term, b.Term)
+	})
+	slices.SortFunc(report.ZeroResultTerms, func(a, b domain.SearchTerm) int {
+		if a.ZeroResults != b.ZeroResults {
+			return b.ZeroResults - a.ZeroResults
+		}
+		return strings.Compare(a.Term, b.Term)
+	})
+
+	report.Filters = []domain.SearchFilter{}
+	for key, n := range filters {
+		facet, value, _ := strings.Cut(key, "\x00")
+		report.Filters = append(report.Filters, domain.SearchFilter{Facet: facet, Value: value, Searches: n})
+	}
+	slices.SortFunc(report.Filters, func(a, b domain.SearchFilter) int {
+		if a.Searches != b.Searches {
+			return b.Searches - a.Searches
+		}
+		return strings.Compare(a.Facet+"\x00"+a.Value, b.Facet+"\x00"+b.Value)
+	})
+
+	report.Terms = report.Terms[:min(limit, len(report.Terms))]
+	report.ZeroResultTerms = report.ZeroResultTerms[:min(limit, len(report.ZeroResultTerms))]
+	report.Filters = report.Filters[:min(limit, len(report.Filters))]
+	return report, nil
+}
+
+// Prune drops the counts of days older than ViewRetention.
+func (u *SearchAnalyticsUsecase) Prune(now time.Time) {
+	cutoff := now.Add(-ViewRetention).UTC().Format(dayFormat)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for day := range u.days {
+		if day < cutoff {
+			delete(u.days, day)
+		}
+	}
+}
+
+// searchTerm normalizes a query into the term it is counted under:
+// lowercase, with single spaces. keep is false for terms that may
+// identify someone: an email address, or a run of five or more digits,
+// such as a card or phone number.
+func searchTerm(q string) (term string, keep bool) {
+	term = strings.Join(strings.Fields(strings.ToLower(q)), " ")
+	if term == "" || strings.Contains(term, "@") {
+		return term, false
+	}
+	digits := 0
+	for _, r := range term {
+		if !unicode.IsDigit(r) {
+			digits = 0
+		} else if digits++; digits >= 5 {
+			return term, false
+		}
+	}
+	return term, true
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/search"
+)
+
+// DefaultRankingWeights favour title matches over author matches, with
+// recency and popularity breaking near ties.
+var DefaultRankingWeights = domain.RankingWeights{TitleMatch: 3, AuthorMatch: 2, Recency: 1, Popularity: 1}
+
+// recencyHalfAge is how many years old a book is when its recency is
+// worth half that of one published this year.
+const recencyHalfAge = 10
+
+// SearchRankingUsecase ranks search results by the weights set for the
+// tenant searching, the host name a site is served on, or the default
+// weights.
+type SearchRankingUsecase struct {
+	popularity *PopularityUsecase
+
+	mu      sync.RWMutex
+	weights domain.RankingWeights
+	tenants map[string]domain.RankingWeights
+}
+
+func NewSearchRankingUsecase(popularity *PopularityUsecase, weights domain.RankingWeights) *SearchRankingUsecase {
+	return &SearchRankingUsecase{popularity: popularity, weights: weights, tenants: map[string]domain.RankingWeights{}}
+}
+
+func (u *SearchRankingUsecase) Settings() domain.RankingSettings {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return domain.RankingSettings{Default: u.weights, Tenants: maps.Clone(u.tenants)}
+}
+
+// Weights returns the weights a tenant's searches are ranked by.
+func (u *SearchRankingUsecase) Weights(tenant string) domain.RankingWeights {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if w, ok := u.tenants[featureflag.Tenant(tenant)]; ok {
+		return w
+	}
+	return u.weights
+}
+
+// SetDefault changes the weights of tenants without their own.
+func (u *SearchRankingUsecase) SetDefault(w domain.RankingWeights) error {
+	if err := w.Validate(); err != nil {
+		return domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.weights = w
+	return nil
+}
+
+// SetTenant gives a tenant its own weights.
+func (u *SearchRankingUsecase) SetTenant(tenant string, w domain.RankingWeights) error {
+	tenant = featureflag.Tenant(tenant)
+	if tenant == "" {
+		return domain.Invalid("tenant must be a host name")
+	}
+	if err := w.Validate(); err != nil {
+		return domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.tenants[tenant] = w
+	return nil
+}
+
+// ClearTenant makes a tenant's searches follow the default weights again.
+func (u *SearchRankingUsecase) ClearTenant(tenant string) error {
+	tenant = featureflag.Tenant(tenant)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.tenants[tenant]; !ok {
+		return domain.NotFound("tenant has no ranking weights of its own")
+	}
+	delete(u.tenants, tenant)
+	return nil
+}
+
+// Rank scores books found by a query by the tenant's weights and orders
+// them best first. Books with the same score keep their order. Title and
+// author are matched against the query's alternatives, and the best
+// match counts.
+func (u *SearchRankingUsecase) Rank(books []domain.Book, alternatives []string, tenant string, now time.Time) []domain.RankedBook {
+	w := u.Weights(tenant)
+	popularity := u.popularity.Scores()
+	top := 0.0
+	for _, score := range popularity {
+		top = max(top, score)
+	}
+
+	ranked := make([]domain.RankedBook, len(books))
+	for i, b := range books {
+		titleMatch, titleReason := bestTextMatch(b.Title, alternatives, "title")
+		authorMatch, authorReason := bestTextMatch(b.Author, alternatives, "author")
+		recency, recencyReason := recencyOf(b, now)
+		popular, popularReason := 0.0, "not trending"
+		if score, ok := popularity[b.ID]; ok && top > 0 {
+			popular = score / top
+			popularReason = fmt.Sprintf("trending score %.2f of the top %.2f", score, top)
+		}
+
+		factors := []domain.RankingFactor{
+			rankingFactor(domain.RankingTitleMatch, titleMatch, w.TitleMatch, titleReason),
+			rankingFactor(domain.RankingAuthorMatch, authorMatch, w.AuthorMatch, authorReason),
+			rankingFactor(domain.RankingRecency, recency, w.Recency, recencyReason),
+			rankingFactor(domain.RankingPopularity, popular, w.Popularity, popularReason),
+		}
+		score := 0.0
+		for _, f := range factors {
+			score += f.Score
+		}
+		ranked[i] = domain.RankedBook{Book: b, Score: round3(score), Factors: factors}
+	}
+	slices.SortStableFunc(ranked, func(a, b domain.RankedBook) int { return cmp.Compare(b.Score, a.Score) })
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
+
+func rankingFactor(name string, value, weight float64, reason string) domain.RankingFactor {
+	value = round3(value)
+	return domain.RankingFactor{Name: name, Value: value, Weight: weight, Score: round3(value * weight), Reason: reason}
+}
+
+// bestTextMatch rates how well text matches the best of alternatives.
+func bestTextMatch(text string, alternatives []string, field string) (float64, string) {
+	best, reason := 0.0, field+" does not contain the query"
+	for _, q := range alternatives {
+		if value, why := textMatch(text, q, field); value > best {
+			best, reason = value, why
+		}
+	}
+	return best, reason
+}
+
+// textMatch rates how well text matches q, both compared as words: 1
+// when it is q, 0.75 when it starts with q, 0.5 when a word of it does
+// and 0.25 when q is found elsewhere in it.
+func textMatch(text, q, field string) (float64, string) {
+	text = strings.Join(search.Words(text), " ")
+	switch {
+	case q == "" || !strings.Contains(text, q):
+		return 0, fmt.Sprintf("%s does not contain %q", field, q)
+	case text == q:
+		return 1, fmt.Sprintf("%s is %q", field, q)
+	case strings.HasPrefix(text, q):
+		return 0.75, fmt.Sprintf("%s starts with %q", field, q)
+	case strings.Contains(" "+text, " "+q):
+		return 0.5, fmt.Sprintf("a word of the %s starts with %q", field, q)
+	}
+	return 0.25, fmt.Sprintf("%s contains %q", field, q)
+}
+
+// recencyOf rates how recent a book is: 1 for one published this year,
+// halving every recencyHalfAge years. Books without a year rate 0.
+func recencyOf(b domain.Book, now time.Time) (float64, string) {
+	if b.Year == 0 {
+		return 0, "no publication year"
+	}
+	age := max(0, now.Year()-b.Year)
+	return math.Exp2(-float64(age) / recencyHalfAge), fmt.Sprintf("published %d, %d years ago", b.Year, age)
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/search"
+)
+
+// DefaultStopWords are the stop words of each language until an admin
+// changes them: articles, prepositions and conjunctions, which say
+// little about what a reader is looking for.
+var DefaultStopWords = map[string][]string{
+	"en": {"a", "an", "and", "at", "by", "for", "from", "in", "of", "on", "or", "the", "to", "with"},
+	"es": {"a", "con", "de", "del", "el", "en", "la", "las", "los", "o", "para", "por", "un", "una", "y"},
+	"fr": {"au", "aux", "de", "des", "du", "en", "et", "la", "le", "les", "ou", "pour", "un", "une"},
+	"de": {"am", "das", "der", "die", "ein", "eine", "im", "in", "mit", "oder", "und", "von", "zu", "zum"},
+	"it": {"a", "con", "del", "della", "di", "e", "il", "in", "la", "le", "lo", "o", "per", "un", "una"},
+}
+
+// DefaultSynonymSets are the synonym sets a library starts with.
+var DefaultSynonymSets = []domain.SynonymSet{
+	{Language: "en", Terms: []string{"sci-fi", "science fiction"}},
+	{Language: "en", Terms: []string{"ww2", "world war ii", "second world war"}},
+}
+
+// SearchVocabularyUsecase holds the synonym sets and stop words searches
+// are expanded with, and expands queries with them. Searches are in the
+// language selected with the language facet, or the default language.
+type SearchVocabularyUsecase struct {
+	language string
+
+	mu        sync.RWMutex
+	synonyms  []domain.SynonymSet
+	nextID    int
+	stopWords map[string][]string
+}
+
+func NewSearchVocabularyUsecase(language string) *SearchVocabularyUsecase {
+	u := &SearchVocabularyUsecase{language: domain.NormalizeLanguage(language), nextID: 1, stopWords: map[string][]string{}}
+	for _, s := range DefaultSynonymSets {
+		s.ID = u.nextID
+		s.Terms = slices.Clone(s.Terms)
+		u.synonyms = append(u.synonyms, s)
+		u.nextID++
+	}
+	for language, words := range DefaultStopWords {
+		u.stopWords[language] = slices.Clone(words)
+	}
+	return u
+}
+
+func (u *SearchVocabularyUsecase) Synonyms() []domain.SynonymSet {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	sets := make([]domain.SynonymSet, len(u.synonyms))
+	for i, s := range u.synonyms {
+		s.Terms = slices.Clone(s.Terms)
+		sets[i] = s
+	}
+	return sets
+}
+
+func (u *SearchVocabularyUsecase) AddSynonyms(s domain.SynonymSet) (domain.SynonymSet, error) {
+	if err := s.Validate(); err != nil {
+		return domain.SynonymSet{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s.ID = u.nextID
+	u.nextID++
+	u.synonyms = append(u.synonyms, s)
+	return s, nil
+}
+
+func (u *SearchVocabularyUsecase) UpdateSynonyms(id int, s domain.SynonymSet) (domain.SynonymSet, error) {
+	if err := s.Validate(); err != nil {
+		return domain.SynonymSet{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := slices.IndexFunc(u.synonyms, func(s domain.SynonymSet) bool { return s.ID == id })
+	if i < 0 {
+		return domain.SynonymSet{}, domain.NotFound("synonym set not found")
+	}
+	s.ID = id
+	u.synonyms[i] = s
+	return s, nil
+}
+
+func (u *SearchVocabularyUsecase) DeleteSynonyms(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i := slices.IndexFunc(u.synonyms, func(s domain.SynonymSet) bool { return s.ID == id })
+	if i < 0 {
+		return domain.NotFound("synonym set not found")
+	}
+	u.synonyms = slices.Delete(u.synonyms, i, i+1)
+	return nil
+}
+
+// StopWords lists the stop words of every language that has some, by
+// language.
+func (u *SearchVocabularyUsecase) StopWords() []domain.StopWords {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	lists := []domain.StopWords{}
+	for _, language := range slices.Sorted(maps.Keys(u.stopWords)) {
+		lists = append(lists, domain.StopWords{Language: language, Words: slices.Clone(u.stopWords[language])})
+	}
+	return lists
+}
+
+// SetStopWords replaces the stop words of a language. Words are
+// lowercased, and sorted.
+func (u *SearchVocabularyUsecase) SetStopWords(language string, words []string) (domain.StopWords, error) {
+	if !domain.ValidLanguage(language) {
+		return domain.StopWords{}, domain.Wrap(domain.ErrInvalid, domain.ErrInvalidLanguage)
+	}
+	language = domain.NormalizeLanguage(language)
+	list := []string{}
+	for _, w := range words {
+		ws := search.Words(w)
+		if len(ws) != 1 {
+			return domain.StopWords{}, domain.Invalid("stop words must be single words: " + strings.TrimSpace(w))
+		}
+		if !slices.Contains(list, ws[0]) {
+			list = append(list, ws[0])
+		}
+	}
+	slices.Sort(list)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.stopWords[language] = list
+	return domain.StopWords{Language: language, Words: slices.Clone(list)}, nil
+}
+
+// ResetStopWords gives a language its default stop words back, or none
+// if it has no defaults.
+func (u *SearchVocabularyUsecase) ResetStopWords(language string) (domain.StopWords, error) {
+	if !domain.ValidLanguage(language) {
+		return domain.StopWords{}, domain.Wrap(domain.ErrInvalid, domain.ErrInvalidLanguage)
+	}
+	language = domain.NormalizeLanguage(language)
+	words := slices.Clone(DefaultStopWords[language])
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if words == nil {
+		delete(u.stopWords, language)
+		words = []string{}
+	} else {
+		u.stopWords[language] = words
+	}
+	return domain.StopWords{Language: language, Words: slices.Clone(words)}, nil
+}
+
+// Expand expands a query in a language, or the default language if it
+// is empty, with the synonym sets of that language or of none, and drops
+// its stop words.
+func (u *SearchVocabularyUsecase) Expand(q, language string) domain.QueryExpansion {
+	if language == "" {
+		language = u.language
+	}
+	language = domain.NormalizeLanguage(language)
+
+	u.mu.RLock()
+	synonyms := [][]string{}
+	for _, s := range u.synonyms {
+		if s.Language == "" || s.Language == language {
+			synonyms = append(synonyms, s.Terms)
+		}
+	}
+	stop := map[string]bool{}
+	for _, w := range u.stopWords[language] {
+		stop[w] = true
+	}
+	u.mu.RUnlock()
+
+	alternatives, removed := search.Expand(q, synonyms, stop)
+	expansion := domain.QueryExpansion{Query: q, Language: language, StopWordsRemoved: removed, Alternatives: []string{}}
+	for _, alt := range alternatives {
+		expansion.Alternatives = append(expansion.Alternatives, strings.Join(alt, " "))
+	}
+	return expansion
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// selfCheckTimeout is how long each self-check may take.
+const selfCheckTimeout = 5 * time.Second
+
+// SelfCheck checks something the server depends on, e.g. that the SMTP
+// relay is reachable. Run returns what it found, or why it failed. A
+// check with Skip set does not apply and is reported as skipped with it.
+type SelfCheck struct {
+	Name string
+	Skip string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// SelfCheckUsecase runs the self-checks and keeps the last report.
+type SelfCheckUsecase struct {
+	checks []SelfCheck
+
+	mu   sync.RWMutex
+	last domain.SelfCheckReport
+}
+
+func NewSelfCheckUsecase(checks []SelfCheck) *SelfCheckUsecase {
+	return &SelfCheckUsecase{checks: checks}
+}
+
+// Run runs every check at once, each within selfCheckTimeout, and
+// returns the report, in the order the checks were given.
+func (u *SelfCheckUsecase) Run(ctx context.Context) domain.SelfCheckReport {
+	report := domain.SelfCheckReport{Status: domain.CheckOK, CheckedAt: time.Now(), Checks: make([]domain.CheckResult, len(u.checks))}
+	var wg sync.WaitGroup
+	for i, check := range u.checks {
+		if check.Skip != "" {
+			report.Checks[i] = domain.CheckResult{Name: check.Name, Status: domain.CheckSkipped, Detail: check.Skip}
+			continue
+		}
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, selfCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			detail, err := check.Run(ctx)
+			result := domain.CheckResult{Name: check.Name, Status: domain.CheckOK, Detail: detail}
+			if err != nil {
+				result.Status, result.Detail = domain.CheckFailed, err.Error()
+			}
+			result.DurationMs = round3(float64(time.Since(start)) / float64(time.Millisecond))
+			report.Checks[i] = result
+		})
+	}
+	wg.Wait()
+	if len(report.Failed()) > 0 {
+		report.Status = domain.CheckFailed
+	}
+
+	u.mu.Lock()
+	u.last = report
+	u.mu.Unlock()
+	return report
+}
+
+// Last returns the report of the last run.
+func (u *SelfCheckUsecase) Last() domain.SelfCheckReport {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.last
+}
@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrSerialNotFound         = domain.NotFound("serial not found")
+	ErrSubscriptionNotFound   = domain.NotFound("subscription not found")
+	ErrIssueNotFound          = domain.NotFound("issue not found")
+	ErrSerialHasSubscriptions = domain.Conflict("serial still has subscriptions")
+	ErrSubscriptionCanceled   = domain.Conflict("subscription is already canceled")
+	ErrIssueReceived          = domain.Conflict("issue was already received")
+)
+
+// PredictionHorizon is how far ahead issues are predicted, so they can be
+// checked in when they arrive a little early.
+const PredictionHorizon = 60 * 24 * time.Hour
+
+// SerialUsecase manages serials, the subscriptions they are received
+// through and the issues predicted from those. Issues are checked in as
+// they arrive and claimed from the vendor when they are late.
+type SerialUsecase struct {
+	mu               sync.RWMutex
+	serials          []domain.Serial
+	subscriptions    []domain.Subscription
+	issues           []domain.SerialIssue
+	nextSerial       int
+	nextSubscription int
+	nextIssue        int
+	purchasing       *PurchasingUsecase
+}
+
+func NewSerialUsecase(purchasing *PurchasingUsecase) *SerialUsecase {
+	return &SerialUsecase{
+		serials:          []domain.Serial{},
+		subscriptions:    []domain.Subscription{},
+		issues:           []domain.SerialIssue{},
+		nextSerial:       1,
+		nextSubscription: 1,
+		nextIssue:        1,
+		purchasing:       purchasing,
+	}
+}
+
+func (u *SerialUsecase) GetSerials() []domain.Serial {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return append([]domain.Serial(nil), u.serials...)
+}
+
+func (u *SerialUsecase) GetSerialByID(id int) (domain.Serial, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.serial(id)
+	if err != nil {
+		return domain.Serial{}, err
+	}
+	return u.serials[i], nil
+}
+
+func (u *SerialUsecase) CreateSerial(serial domain.Serial) (domain.Serial, error) {
+	if err := serial.Validate(); err != nil {
+		return domain.Serial{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	serial.ID = u.nextSerial
+	serial.CreatedAt = time.Now()
+	u.nextSerial++
+	u.serials = append(u.serials, serial)
+	return serial, nil
+}
+
+func (u *SerialUsecase) UpdateSerial(id int, updated domain.Serial) (domain.Serial, error) {
+	if err := updated.Validate(); err != nil {
+		return domain.Serial{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.serial(id)
+	if err != nil {
+		return domain.Serial{}, err
+	}
+	updated.ID = id
+	updated.CreatedAt = u.serials[i].CreatedAt
+	u.serials[i] = updated
+	return updated, nil
+}
+
+// DeleteSerial removes a serial the library never subscribed to.
+func (u *SerialUsecase) DeleteSerial(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.serial(id)
+	if err != nil {
+		return err
+	}
+	for _, s := range u.subscriptions {
+		if s.SerialID == id {
+			return ErrSerialHasSubscriptions
+		}
+	}
+	u.serials = append(u.serials[:i], u.serials[i+1:]...)
+	return nil
+}
+
+// GetSubscriptions returns the subscriptions to a serial.
+func (u *SerialUsecase) GetSubscriptions(serialID int) ([]domain.Subscription, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if _, err := u.serial(serialID); err != nil {
+		return nil, err
+	}
+	subs := []domain.Subscription{}
+	for _, s := range u.subscriptions {
+		if s.SerialID == serialID {
+			subs = append(subs, s)
+		}
+	}
+	return subs, nil
+}
+
+// Subscribe starts a subscription to a serial and predicts its first
+// issues. The vendor, if one is named, must exist.
+func (u *SerialUsecase) Subscribe(serialID int, sub domain.Subscription, now time.Time) (domain.Subscription, error) {
+	sub.Defaults()
+	if err := sub.Validate(); err != nil {
+		return domain.Subscription{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	if sub.VendorID != 0 {
+		if _, err := u.purchasing.GetVendorByID(sub.VendorID); err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, err := u.serial(serialID); err != nil {
+		return domain.Subscription{}, err
+	}
+	sub.ID = u.nextSubscription
+	u.nextSubscription++
+	sub.SerialID = serialID
+	sub.Status = domain.SubscriptionActive
+	sub.Predicted = 0
+	sub.CreatedAt = now
+	u.subscriptions = append(u.subscriptions, sub)
+	u.predict(len(u.subscriptions)-1, now)
+	return u.subscriptions[len(u.subscriptions)-1], nil
+}
+
+// CancelSubscription stops predicting issues of a subscription and drops
+// those expected after now. Issues already due stay, so late ones can
+// still be claimed.
+func (u *SerialUsecase) CancelSubscription(id int, now time.Time) (domain.Subscription, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.subscription(id)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	sub := &u.subscriptions[i]
+	if sub.Status == domain.SubscriptionCanceled {
+		return domain.Subscription{}, ErrSubscriptionCanceled
+	}
+	sub.Status = domain.SubscriptionCanceled
+	sub.EndsOn = &now
+
+	kept := u.issues[:0]
+	for _, issue := range u.issues {
+		if issue.SubscriptionID == id && issue.Status == domain.IssueExpected && issue.ExpectedOn.After(now) {
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	u.issues = kept
+	return *sub, nil
+}
+
+// Predict adds the issues of active subscriptions expected within
+// PredictionHorizon of now, returning how many it added.
+func (u *SerialUsecase) Predict(now time.Time) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	n := 0
+	for i := range u.subscriptions {
+		n += u.predict(i, now)
+	}
+	return n
+}
+
+// GetIssues returns the issues of a serial with the given status, or all
+// of them if status is empty, by expected date.
+func (u *SerialUsecase) GetIssues(serialID int, status string) ([]domain.SerialIssue, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if _, err := u.serial(serialID); err != nil {
+		return nil, err
+	}
+	issues := []domain.SerialIssue{}
+	for _, issue := range u.issues {
+		if issue.SerialID == serialID && (status == "" || issue.Status == status) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].ExpectedOn.Before(issues[j].ExpectedOn) })
+	return issues, nil
+}
+
+// ReceiveIssue checks in an issue that arrived, late, early or claimed.
+func (u *SerialUsecase) ReceiveIssue(id int, note string, now time.Time) (domain.SerialIssue, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.issue(id)
+	if err != nil {
+		return domain.SerialIssue{}, err
+	}
+	issue := &u.issues[i]
+	if issue.Status == domain.IssueReceived {
+		return domain.SerialIssue{}, ErrIssueReceived
+	}
+	issue.Status = domain.IssueReceived
+	issue.ReceivedAt = &now
+	if note != "" {
+		issue.Note = note
+	}
+	return *issue, nil
+}
+
+// ClaimIssue records that an issue not yet received was claimed from the
+// vendor. An issue may be claimed again when the claim goes unanswered.
+func (u *SerialUsecase) ClaimIssue(id int, note string, now time.Time) (domain.SerialIssue, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.issue(id)
+	if err != nil {
+		return domain.SerialIssue{}, err
+	}
+	issue := &u.issues[i]
+	if issue.Status == domain.IssueReceived {
+		return domain.SerialIssue{}, ErrIssueReceived
+	}
+	issue.Status = domain.IssueClaimed
+	issue.ClaimedAt = &now
+	issue.Claims++
+	if note != "" {
+		issue.Note = note
+	}
+	return *issue, nil
+}
+
+// LateIssues returns the issues of every serial that are due to be
+// claimed: not received within their subscription's claim period of
+// being expected, or of being claimed last.
+func (u *SerialUsecase) LateIssues(now time.Time) []domain.SerialIssue {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	late := []domain.SerialIssue{}
+	for _, issue := range u.issues {
+		if u.late(issue, now) {
+			late = append(late, issue)
+		}
+	}
+	sort.SliceStable(late, func(i, j int) bool { return late[i].ExpectedOn.Before(late[j].ExpectedOn) })
+	return late
+}
+
+// Holdings summarises the issues of a serial received through all its
+// subscriptions, listing those late or claimed as missing.
+func (u *SerialUsecase) Holdings(serialID int, now time.Time) (domain.Holdings, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.serial(serialID)
+	if err != nil {
+		return domain.Holdings{}, err
+	}
+	serial := u.serials[i]
+	h := domain.Holdings{SerialID: serial.ID, Title: serial.Title, ISSN: serial.ISSN, Locations: []string{}, Missing: []string{}}
+
+	for _, s := range u.subscriptions {
+		if s.SerialID == serialID && s.Location != "" && !slices.Contains(h.Locations, s.Location) {
+			h.Locations = append(h.Locations, s.Location)
+		}
+	}
+
+	issues := []domain.SerialIssue{}
+	for _, issue := range u.issues {
+		if issue.SerialID == serialID {
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Volume != issues[j].Volume {
+			return issues[i].Volume < issues[j].Volume
+		}
+		return issues[i].Number < issues[j].Number
+	})
+
+	received := []domain.SerialIssue{}
+	for _, issue := range issues {
+		switch issue.Status {
+		case domain.IssueReceived:
+			h.Received++
+			// Two subscriptions may bring the same issue; holdings list
+			// it once.
+			if n := len(received); n == 0 || received[n-1].Volume != issue.Volume || received[n-1].Number != issue.Number {
+				received = append(received, issue)
+			}
+		case domain.IssueExpected:
+			h.Expected++
+		case domain.IssueClaimed:
+			h.Claimed++
+		}
+		if issue.Status == domain.IssueClaimed || u.late(issue, now) {
+			h.Missing = append(h.Missing, issue.Label())
+		}
+	}
+	h.Statement = domain.HoldingsStatement(received)
+	return h, nil
+}
+
+// predict adds the issues of the i-th subscription expected within
+// PredictionHorizon of now. It expects the caller to hold the lock.
+func (u *SerialUsecase) predict(i int, now time.Time) int {
+	sub := &u.subscriptions[i]
+	if sub.Status != domain.SubscriptionActive {
+		return 0
+	}
+	n := 0
+	for {
+		volume, number, on := sub.Expected(sub.Predicted)
+		if on.After(now.Add(PredictionHorizon)) || (sub.EndsOn != nil && on.After(*sub.EndsOn)) {
+			return n
+		}
+		u.issues = append(u.issues, domain.SerialIssue{
+			ID:             u.nextIssue,
+			SubscriptionID: sub.ID,
+			SerialID:       sub.SerialID,
+			Volume:         volume,
+			Number:         number,
+			ExpectedOn:     on,
+			Status:         domain.IssueExpected,
+		})
+		u.nextIssue++
+		sub.Predicted++
+		n++
+	}
+}
+
+// late expects the caller to hold the lock.
+func (u *SerialUsecase) late(issue domain.SerialIssue, now time.Time) bool {
+	i, err := u.subscription(issue.SubscriptionID)
+	if err != nil {
+		return false
+	}
+	claimAfter := time.Duration(u.subscriptions[i].ClaimAfterDays) * 24 * time.Hour
+	return issue.Late(now, claimAfter)
+}
+
+// serial expects the caller to hold the lock.
+func (u *SerialUsecase) serial(id int) (int, error) {
+	for i, s := range u.serials {
+		if s.ID == id {
+			return i, nil
+		}
+	}
+	return 0, ErrSerialNotFound
+}
+
+// subscription expects the caller to hold the lock.
+func (u *SerialUsecase) subscription(id int) (int, error) {
+	for i, s := range u.subscriptions {
+		if s.ID == id {
+			return i, nil
+		}
+	}
+	return 0, ErrSubscriptionNotFound
+}
+
+// issue expects the caller to hold the lock.
+func (u *SerialUsecase) issue(id int) (int, error) {
+	for i, issue := range u.issues {
+		if issue.ID == id {
+			return i, nil
+		}
+	}
+	return 0, ErrIssueNotFound
+}
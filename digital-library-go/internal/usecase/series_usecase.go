@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var (
+	ErrInvalidPosition = domain.Invalid("position must be greater than 0")
+	ErrSeriesOrder     = domain.Invalid("book_ids must list every book in the series exactly once")
+)
+
+// SeriesUsecase manages series and the reading order of their books.
+// Entries are kept sorted by position.
+type SeriesUsecase struct {
+	mu     sync.RWMutex
+	series []domain.Series
+	nextID int
+	books  *BookUsecase
+}
+
+func NewSeriesUsecase(books *BookUsecase) *SeriesUsecase {
+	return &SeriesUsecase{
+		series: []domain.Series{},
+		nextID: 1,
+		books:  books,
+	}
+}
+
+func (u *SeriesUsecase) GetSeries() []domain.Series {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	all := make([]domain.Series, 0, len(u.series))
+	for _, s := range u.series {
+		all = append(all, copySeries(s))
+	}
+	return all
+}
+
+func (u *SeriesUsecase) GetSeriesByID(id int) (domain.Series, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	return copySeries(u.series[i]), nil
+}
+
+// CreateSeries adds an empty series; books are added with SetPosition.
+func (u *SeriesUsecase) CreateSeries(series domain.Series) domain.Series {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	series.ID = u.nextID
+	series.Entries = []domain.SeriesEntry{}
+	u.nextID++
+	u.series = append(u.series, series)
+	return copySeries(series)
+}
+
+// UpdateSeries changes the name and description, keeping the entries.
+func (u *SeriesUsecase) UpdateSeries(id int, updated domain.Series) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return err
+	}
+	u.series[i].Name = updated.Name
+	u.series[i].Description = updated.Description
+	return nil
+}
+
+func (u *SeriesUsecase) DeleteSeries(id int) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return err
+	}
+	u.series = append(u.series[:i], u.series[i+1:]...)
+	return nil
+}
+
+// SetPosition adds a book to the series at the given position, or moves
+// it there if it is already part of it.
+func (u *SeriesUsecase) SetPosition(id, bookID int, position float64) (domain.Series, error) {
+	if position <= 0 {
+		return domain.Series{}, ErrInvalidPosition
+	}
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return domain.Series{}, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	entries := slices.DeleteFunc(u.series[i].Entries, func(e domain.SeriesEntry) bool { return e.BookID == bookID })
+	entries = append(entries, domain.SeriesEntry{BookID: bookID, Position: position})
+	sortEntries(entries)
+	u.series[i].Entries = entries
+	return copySeries(u.series[i]), nil
+}
+
+func (u *SeriesUsecase) RemoveBook(id, bookID int) (domain.Series, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+	n := len(u.series[i].Entries)
+	u.series[i].Entries = slices.DeleteFunc(u.series[i].Entries, func(e domain.SeriesEntry) bool { return e.BookID == bookID })
+	if len(u.series[i].Entries) == n {
+		return domain.Series{}, domain.Invalid("book is not part of this series")
+	}
+	return copySeries(u.series[i]), nil
+}
+
+// Reorder renumbers the series 1, 2, 3... in the order of bookIDs, which
+// must name each of its books once.
+func (u *SeriesUsecase) Reorder(id int, bookIDs []int) (domain.Series, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	i, err := u.index(id)
+	if err != nil {
+		return domain.Series{}, err
+	}
+
+	current := u.series[i].Entries
+	if len(bookIDs) != len(current) {
+		return domain.Series{}, ErrSeriesOrder
+	}
+	entries := make([]domain.SeriesEntry, 0, len(bookIDs))
+	for n, bookID := range bookIDs {
+		inSeries := slices.ContainsFunc(current, func(e domain.SeriesEntry) bool { return e.BookID == bookID })
+		seen := slices.ContainsFunc(entries, func(e domain.SeriesEntry) bool { return e.BookID == bookID })
+		if !inSeries || seen {
+			return domain.Series{}, ErrSeriesOrder
+		}
+		entries = append(entries, domain.SeriesEntry{BookID: bookID, Position: float64(n + 1)})
+	}
+	u.series[i].Entries = entries
+	return copySeries(u.series[i]), nil
+}
+
+// BooksInOrder returns the series' books in reading order. Books deleted
+// from the catalog or not published are skipped.
+func (u *SeriesUsecase) BooksInOrder(id int) ([]domain.SeriesBook, error) {
+	series, err := u.GetSeriesByID(id)
+	if err != nil {
+		return nil, err
+	}
+	books := []domain.SeriesBook{}
+	for _, e := range series.Entries {
+		book, err := u.books.GetBookByID(e.BookID)
+		if err != nil || !book.Published() {
+			continue
+		}
+		books = append(books, domain.SeriesBook{Position: e.Position, Book: book})
+	}
+	return books, nil
+}
+
+// index expects the caller to hold the lock.
+func (u *SeriesUsecase) index(id int) (int, error) {
+	for i, s := range u.series {
+		if s.ID == id {
+			return i, nil
+		}
+	}
+	return 0, domain.NotFound("series not found")
+}
+
+func copySeries(s domain.Series) domain.Series {
+	s.Entries = append([]domain.SeriesEntry{}, s.Entries...)
+	return s
+}
+
+func sortEntries(entries []domain.SeriesEntry) {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Position < entries[j].Position })
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// sloBucket counts the requests to a route in one minute, and those of
+// them that were good.
+type sloBucket struct {
+	minute int64
+	total  int
+	good   int
+}
+
+// sloTracker keeps a minute bucket per minute of the SLO window, reused
+// round-robin.
+type sloTracker struct {
+	slo     domain.SLO
+	buckets []sloBucket
+}
+
+// SLOUsecase tracks the requests to routes with a latency objective, and
+// how they do against it over a rolling window, typically 30 days.
+type SLOUsecase struct {
+	mu     sync.Mutex
+	window time.Duration
+	routes map[string]*sloTracker
+}
+
+func NewSLOUsecase(slos []domain.SLO, window time.Duration) *SLOUsecase {
+	u := &SLOUsecase{window: window, routes: map[string]*sloTracker{}}
+	minutes := max(1, int(window/time.Minute))
+	for _, s := range slos {
+		u.routes[s.Route] = &sloTracker{slo: s, buckets: make([]sloBucket, minutes)}
+	}
+	return u
+}
+
+// Record counts a request to a route, e.g. "GET /books/:id", answered
+// with status after latency. Routes without an objective are ignored.
+func (u *SLOUsecase) Record(route string, latency time.Duration, status int, at time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	t, ok := u.routes[route]
+	if !ok {
+		return
+	}
+	minute := at.Unix() / 60
+	b := &t.buckets[minute%int64(len(t.buckets))]
+	if b.minute != minute {
+		*b = sloBucket{minute: minute}
+	}
+	b.total++
+	if status < 500 && latency <= t.slo.Latency {
+		b.good++
+	}
+}
+
+// Status reports how each route with an objective is doing, by route.
+func (u *SLOUsecase) Status(now time.Time) []domain.SLOStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	statuses := []domain.SLOStatus{}
+	for _, t := range u.routes {
+		budget := t.slo.ErrorBudget()
+		total, good := t.sum(now, u.window)
+		s := domain.SLOStatus{
+			Route:           t.slo.Route,
+			Latency:         t.slo.Latency.String(),
+			Objective:       t.slo.Objective,
+			Window:          u.window.String(),
+			Requests:        total,
+			Good:            good,
+			Compliance:      100,
+			BudgetRemaining: 1,
+			BurnRates:       map[string]float64{},
+		}
+		if total > 0 {
+			s.Compliance = round3(100 * float64(good) / float64(total))
+			s.BudgetRemaining = round3(1 - float64(total-good)/(budget*float64(total)))
+		}
+		for _, w := range domain.BurnRateWindows {
+			s.BurnRates[w.Name] = 0
+			if total, good := t.sum(now, min(w.Duration, u.window)); total > 0 {
+				s.BurnRates[w.Name] = round3(float64(total-good) / float64(total) / budget)
+			}
+		}
+		s.Alert = domain.BurnRateAlert(s.BurnRates)
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Route < statuses[j].Route })
+	return statuses
+}
+
+// sum counts the requests of the last d before now, in whole minutes,
+// and the good ones. It expects the caller to hold the lock.
+func (t *sloTracker) sum(now time.Time, d time.Duration) (total, good int) {
+	last := now.Unix() / 60
+	first := last - int64(max(1, int(d/time.Minute))) + 1
+	for _, b := range t.buckets {
+		if b.minute >= first && b.minute <= last {
+			total += b.total
+			good += b.good
+		}
+	}
+	return total, good
+}
+
+func round3(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+)
+
+// maxSMSMessages bounds the delivery log; the oldest entries go first.
+const maxSMSMessages = 10000
+
+// SMSProvider sends text messages, e.g. through Twilio. Send returns the
+// provider's message ID and initial status; later statuses arrive
+// through UpdateStatus.
+type SMSProvider interface {
+	Send(ctx context.Context, from, to, body string) (id, status string, err error)
+}
+
+// SMSUsecase is the SMS notification channel. It renders each
+// notification through the template for its kind, sends it through the
+// provider and keeps a log of delivery statuses. Texts are sent with the
+// settings of the member's tenant, or the default settings.
+type SMSUsecase struct {
+	mu        sync.RWMutex
+	provider  SMSProvider
+	templates *TemplateUsecase
+	settings  domain.SMSSettings
+	tenants   map[string]domain.SMSSettings
+	messages  []domain.SMSMessage
+}
+
+// NewSMSUsecase returns the SMS channel. Kinds with an SMS template in
+// templates are worded with it, and the others with the template in the
+// settings, if any.
+func NewSMSUsecase(provider SMSProvider, templates *TemplateUsecase, settings domain.SMSSettings) *SMSUsecase {
+	if settings.Templates == nil {
+		settings.Templates = map[string]string{}
+	}
+	return &SMSUsecase{
+		provider:  provider,
+		templates: templates,
+		settings:  settings,
+		tenants:   map[string]domain.SMSSettings{},
+		messages:  []domain.SMSMessage{},
+	}
+}
+
+func (u *SMSUsecase) Name() string {
+	return domain.ChannelSMS
+}
+
+// Send texts a notification to a member. Members without a phone number
+// are skipped.
+func (u *SMSUsecase) Send(ctx context.Context, member domain.Member, n domain.Notification) error {
+	if member.Phone == "" {
+		return nil
+	}
+	settings := u.GetSettings(member.Tenant)
+	msg, ok, err := u.templates.RenderNotification(domain.TemplateSMS, member, n)
+	body := msg.Text
+	if !ok && err == nil {
+		body, err = render(settings.Templates[n.Kind], domain.SMSTemplateData{Name: member.Name, Kind: n.Kind, Message: n.Message})
+	}
+	if err != nil {
+		return err
+	}
+	if b := []rune(body); len(b) > domain.MaxSMSLength {
+		body = string(b[:domain.MaxSMSLength])
+	}
+
+	id, status, err := u.provider.Send(ctx, settings.From, member.Phone, body)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.messages = append(u.messages, domain.SMSMessage{
+		ID:        id,
+		MemberID:  member.ID,
+		Kind:      n.Kind,
+		To:        member.Phone,
+		Status:    status,
+		SentAt:    now,
+		UpdatedAt: now,
+	})
+	if len(u.messages) > maxSMSMessages {
+		u.messages = u.messages[len(u.messages)-maxSMSMessages:]
+	}
+	return nil
+}
+
+// GetSettings returns the settings texts to a tenant's members are sent
+// with: its own, or the default ones.
+func (u *SMSUsecase) GetSettings(tenant string) domain.SMSSettings {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	s, ok := u.tenants[featureflag.Tenant(tenant)]
+	if !ok {
+		s = u.settings
+	}
+	s.Templates = maps.Clone(s.Templates)
+	return s
+}
+
+// SetSettings replaces a tenant's settings, or the default ones when
+// tenant is empty.
+func (u *SMSUsecase) SetSettings(tenant string, settings domain.SMSSettings) {
+	if settings.Templates == nil {
+		settings.Templates = map[string]string{}
+	}
+	tenant = featureflag.Tenant(tenant)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if tenant == "" {
+		u.settings = settings
+		return
+	}
+	u.tenants[tenant] = settings
+}
+
+// ClearSettings makes texts to a tenant's members use the default
+// settings again.
+func (u *SMSUsecase) ClearSettings(tenant string) error {
+	tenant = featureflag.Tenant(tenant)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.tenants[tenant]; !ok {
+		return domain.NotFound("tenant has no SMS settings of its own")
+	}
+	delete(u.tenants, tenant)
+	return nil
+}
+
+// UpdateStatus records a delivery status reported by the provider.
+func (u *SMSUsecase) UpdateStatus(id, status, errorCode string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i := len(u.messages) - 1; i >= 0; i-- {
+		if u.messages[i].ID == id {
+			u.messages[i].Status = status
+			u.messages[i].ErrorCode = errorCode
+			u.messages[i].UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return domain.NotFound("message not found")
+}
+
+// Messages returns the most recent texts, newest first.
+func (u *SMSUsecase) Messages(limit int) []domain.SMSMessage {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	messages := []domain.SMSMessage{}
+	for i := len(u.messages) - 1; i >= 0 && len(messages) < limit; i-- {
+		messages = append(messages, u.messages[i])
+	}
+	return messages
+}
+
+// render fills in an SMS template; an empty one sends the message as is.
+func render(text string, data domain.SMSTemplateData) (string, error) {
+	if text == "" {
+		text = "{{.Message}}"
+	}
+	tmpl, err := template.New("sms").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/search"
+)
+
+// SuggestUsecase answers autocomplete queries from a trie of titles and
+// author names. The trie is rebuilt on the first query after the catalog
+// changes.
+type SuggestUsecase struct {
+	books   *BookUsecase
+	authors *AuthorUsecase
+
+	mu      sync.RWMutex
+	index   *search.Index
+	version uint64
+}
+
+func NewSuggestUsecase(books *BookUsecase, authors *AuthorUsecase) *SuggestUsecase {
+	return &SuggestUsecase{books: books, authors: authors}
+}
+
+func (u *SuggestUsecase) Suggest(q string, limit int) []domain.Suggestion {
+	return u.currentIndex().Suggest(q, limit)
+}
+
+// DidYouMean proposes corrected queries for a search that found nothing.
+func (u *SuggestUsecase) DidYouMean(q string) []string {
+	return u.currentIndex().DidYouMean(q)
+}
+
+func (u *SuggestUsecase) currentIndex() *search.Index {
+	version := u.books.Version()
+	u.mu.RLock()
+	index := u.index
+	fresh := index != nil && u.version == version
+	u.mu.RUnlock()
+	if fresh {
+		return index
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.index == nil || u.version != version {
+		u.index = u.build()
+		u.version = version
+	}
+	return u.index
+}
+
+func (u *SuggestUsecase) build() *search.Index {
+	names := map[int]string{}
+	for _, a := range u.authors.GetAuthors() {
+		names[a.ID] = a.Name
+	}
+
+	index := search.NewIndex()
+	for _, b := range u.books.GetBooks() {
+		if !b.Published() {
+			continue
+		}
+		index.Add(b.Title, "title")
+		for _, id := range b.AuthorIDs {
+			if name, ok := names[id]; ok {
+				index.Add(name, "author")
+			}
+		}
+		if len(b.AuthorIDs) == 0 && b.Author != "" {
+			index.Add(b.Author, "author")
+		}
+	}
+	return index
+}
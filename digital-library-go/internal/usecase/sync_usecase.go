@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+var ErrUnknownStrategy = domain.Invalid("strategy must be merge or last_writer_wins")
+
+// syncAttempts bounds how often a change is retried when the book is
+// written to between reading and saving it.
+const syncAttempts = 3
+
+// SyncUsecase applies changes that offline clients made to the catalog,
+// resolving those to books that changed on the server in the meantime.
+type SyncUsecase struct {
+	books   *BookUsecase
+	authors *AuthorUsecase
+	fields  *FieldUsecase
+}
+
+func NewSyncUsecase(books *BookUsecase, authors *AuthorUsecase, fields *FieldUsecase) *SyncUsecase {
+	return &SyncUsecase{books: books, authors: authors, fields: fields}
+}
+
+// Apply saves the changes a tenant's client made in order and reports
+// what became of each.
+func (u *SyncUsecase) Apply(tenant, strategy string, changes []domain.ClientChange) ([]domain.SyncResult, error) {
+	if strategy == "" {
+		strategy = domain.SyncMerge
+	}
+	if strategy != domain.SyncMerge && strategy != domain.SyncLastWriterWins {
+		return nil, ErrUnknownStrategy
+	}
+	results := make([]domain.SyncResult, 0, len(changes))
+	for _, c := range changes {
+		result := domain.SyncResult{Ref: c.Ref, BookID: c.BookID}
+		if err := c.Validate(); err != nil {
+			result.Outcome, result.Error = domain.SyncRejected, err.Error()
+			results = append(results, result)
+			continue
+		}
+		switch c.Op {
+		case domain.SyncCreate:
+			result = u.create(tenant, c)
+		case domain.SyncUpdate:
+			result = u.retry(c, strategy, func(c domain.ClientChange, strategy string) (domain.SyncResult, bool) {
+				return u.update(tenant, c, strategy)
+			})
+		case domain.SyncDelete:
+			result = u.retry(c, strategy, u.delete)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (u *SyncUsecase) create(tenant string, c domain.ClientChange) domain.SyncResult {
+	book := *c.Book
+	if err := u.prepare(tenant, &book, nil); err != nil {
+		return domain.SyncResult{Ref: c.Ref, Outcome: domain.SyncRejected, Error: err.Error()}
+	}
+	var id int
+	err := u.authors.SaveBook(&book, func(b domain.Book) error {
+		id = u.books.CreateBookWithNextID(b)
+		return nil
+	})
+	if err != nil {
+		return domain.SyncResult{Ref: c.Ref, Outcome: domain.SyncRejected, Error: err.Error()}
+	}
+	return u.saved(c.Ref, id, domain.SyncApplied)
+}
+
+// retry runs apply until the book stays unchanged between reading and
+// saving it.
+func (u *SyncUsecase) retry(c domain.ClientChange, strategy string, apply func(domain.ClientChange, string) (domain.SyncResult, bool)) domain.SyncResult {
+	for range syncAttempts {
+		if result, done := apply(c, strategy); done {
+			return result
+		}
+	}
+	return domain.SyncResult{Ref: c.Ref, BookID: c.BookID, Outcome: domain.SyncConflict, Error: "book is being edited, try again"}
+}
+
+// update saves an edited book. It returns false if the book changed
+// while it was being saved.
+func (u *SyncUsecase) update(tenant string, c domain.ClientChange, strategy string) (domain.SyncResult, bool) {
+	result := domain.SyncResult{Ref: c.Ref, BookID: c.BookID}
+	server, err := u.books.GetBookByID(c.BookID)
+	if err != nil {
+		result.Outcome, result.Error = domain.SyncConflict, "book was deleted on the server"
+		return result, true
+	}
+
+	book, outcome := *c.Book, domain.SyncApplied
+	if server.Revision != c.BaseRevision {
+		switch {
+		case strategy == domain.SyncLastWriterWins && !c.ChangedAt.After(server.UpdatedAt):
+			result.Outcome, result.Server = domain.SyncDiscarded, &server
+			return result, true
+		case strategy == domain.SyncMerge && c.Base == nil:
+			result.Outcome, result.Server = domain.SyncConflict, &server
+			result.Error = "base is required to merge"
+			return result, true
+		case strategy == domain.SyncMerge:
+			merged, conflicts := domain.MergeBooks(*c.Base, server, book)
+			if len(conflicts) > 0 {
+				result.Outcome, result.Fields, result.Server = domain.SyncConflict, conflicts, &server
+				return result, true
+			}
+			book, outcome = merged, domain.SyncMerged
+		}
+	}
+
+	// The book keeps its status; only publishing or withdrawing it
+	// changes that.
+	book.Status = server.Status
+	if err := u.prepare(tenant, &book, server.Attributes); err != nil {
+		result.Outcome, result.Error = domain.SyncRejected, err.Error()
+		return result, true
+	}
+	err = u.authors.SaveBook(&book, func(b domain.Book) error {
+		return u.books.UpdateBookAt(c.BookID, server.Revision, b)
+	})
+	switch {
+	case errors.Is(err, ErrRevisionChanged):
+		return result, false
+	case errors.Is(err, ErrUnknownAuthor):
+		result.Outcome, result.Error = domain.SyncRejected, err.Error()
+		return result, true
+	case err != nil:
+		result.Outcome, result.Error = domain.SyncConflict, "book was deleted on the server"
+		return result, true
+	}
+	return u.saved(c.Ref, c.BookID, outcome), true
+}
+
+// delete removes a book. A book that is already gone counts as deleted.
+// It returns false if the book changed while it was being deleted.
+func (u *SyncUsecase) delete(c domain.ClientChange, strategy string) (domain.SyncResult, bool) {
+	result := domain.SyncResult{Ref: c.Ref, BookID: c.BookID, Outcome: domain.SyncApplied}
+	server, err := u.books.GetBookByID(c.BookID)
+	if err != nil {
+		return result, true
+	}
+	if server.Revision != c.BaseRevision {
+		if strategy == domain.SyncMerge {
+			result.Outcome, result.Server = domain.SyncConflict, &server
+			result.Error = "book was edited on the server"
+			return result, true
+		}
+		if !c.ChangedAt.After(server.UpdatedAt) {
+			result.Outcome, result.Server = domain.SyncDiscarded, &server
+			return result, true
+		}
+	}
+	if errors.Is(u.books.DeleteBookAt(c.BookID, server.Revision), ErrRevisionChanged) {
+		return result, false
+	}
+	return result, true
+}
+
+// prepare checks a book the way the book endpoints do, current being
+// the attributes it had on the server. Its authors are linked as it is
+// saved.
+func (u *SyncUsecase) prepare(tenant string, book *domain.Book, current map[string]any) error {
+	if err := book.Validate(); err != nil {
+		return domain.Wrap(domain.ErrInvalid, err)
+	}
+	attrs, err := u.fields.ValidateAttributes(tenant, book.Attributes, current)
+	if err != nil {
+		return err
+	}
+	book.Attributes = attrs
+	return nil
+}
+
+// saved reports a saved book with its state afterwards.
+func (u *SyncUsecase) saved(ref string, id int, outcome string) domain.SyncResult {
+	result := domain.SyncResult{Ref: ref, BookID: id, Outcome: outcome}
+	if book, err := u.books.GetBookByID(id); err == nil {
+		result.Server = &book
+	}
+	return result
+}
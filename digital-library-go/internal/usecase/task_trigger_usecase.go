@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// TriggerLimit is how many tasks of a type may be triggered within Per.
+type TriggerLimit struct {
+	Count int
+	Per   time.Duration
+}
+
+// TriggerLimitError is returned when a task type has been triggered as
+// often as its limit allows. It may be triggered again after RetryAfter.
+type TriggerLimitError struct {
+	Task       string
+	Limit      TriggerLimit
+	RetryAfter time.Duration
+}
+
+func (e *TriggerLimitError) Error() string {
+	return fmt.Sprintf("%s may be triggered %d times per %s", e.Task, e.Limit.Count, e.Limit.Per)
+}
+
+// TaskTriggerUsecase lets external systems, such as a scheduler kicking
+// off nightly batches, queue registered task types with an API key. Each
+// type may only be triggered so often, whichever key triggers it.
+type TaskTriggerUsecase struct {
+	tasks   *TaskUsecase
+	members *MemberUsecase
+	limit   TriggerLimit
+	limits  map[string]TriggerLimit
+
+	mu     sync.Mutex
+	recent map[string][]time.Time
+}
+
+// NewTaskTriggerUsecase limits every task type to limit, except those
+// given their own in limits.
+func NewTaskTriggerUsecase(tasks *TaskUsecase, members *MemberUsecase, limit TriggerLimit, limits map[string]TriggerLimit) *TaskTriggerUsecase {
+	return &TaskTriggerUsecase{tasks: tasks, members: members, limit: limit, limits: limits, recent: map[string][]time.Time{}}
+}
+
+// Trigger queues a task of a registered type for the holder of key, which
+// needs a tasks:trigger scope for the type and must belong to a librarian
+// or admin. The task runs as scheduled work.
+func (u *TaskTriggerUsecase) Trigger(key domain.APIKey, name string, payload json.RawMessage, now time.Time) (domain.Task, error) {
+	if !key.CanTrigger(name) {
+		return domain.Task{}, domain.Forbidden("API key lacks the " + domain.ScopeTasksTrigger + " scope for " + name)
+	}
+	if m, err := u.members.GetMemberByID(key.MemberID); err != nil || !m.IsStaff() {
+		return domain.Task{}, domain.Forbidden("only API keys of librarians and admins may trigger tasks")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	limit, ok := u.limits[name]
+	if !ok {
+		limit = u.limit
+	}
+	recent := u.recent[name][:0]
+	for _, at := range u.recent[name] {
+		if now.Sub(at) < limit.Per {
+			recent = append(recent, at)
+		}
+	}
+	u.recent[name] = recent
+	if len(recent) >= limit.Count {
+		return domain.Task{}, &TriggerLimitError{Task: name, Limit: limit, RetryAfter: recent[0].Add(limit.Per).Sub(now)}
+	}
+
+	task, err := u.tasks.Submit(name, domain.TaskPriorityScheduled, payload)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	u.recent[name] = append(recent, now)
+	return task, nil
+}
@@ -0,0 +1,598 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+const (
+	// taskQueueSize is how many tasks of a kind may wait for a worker.
+	taskQueueSize = 64
+	// keptTasks is how many tasks are remembered. The oldest finished
+	// ones are forgotten first.
+	keptTasks = 200
+	// keptTaskLogLines is how many lines each task's log holds. The
+	// oldest are dropped first.
+	keptTaskLogLines = 1000
+	// watchdogInterval is how often running tasks are checked against
+	// their timeout.
+	watchdogInterval = time.Second
+)
+
+// DefaultTaskTimeouts are the timeouts of kinds of task that need a
+// shorter one than TaskConfig.Timeout. A critical update holds the
+// server in maintenance mode while it runs.
+var DefaultTaskTimeouts = map[string]time.Duration{
+	domain.TaskCriticalUpdate: 2 * time.Minute,
+}
+
+var (
+	ErrQueueFull       = domain.Unavailable("task queue is full, try again later")
+	ErrTaskNotFinished = domain.Conflict("task has not finished yet")
+)
+
+// TaskFunc does the work of a task, reporting each item it processes to
+// the tracker, and returns the task's result.
+type TaskFunc func(t *TaskTracker) any
+
+// registeredTask is a task type and how to turn a valid payload into the
+// work of a task.
+type registeredTask struct {
+	typ     domain.TaskType
+	prepare func(payload json.RawMessage) (TaskFunc, error)
+}
+
+type queuedTask struct {
+	id       int
+	kind     string
+	priority string
+	queuedAt time.Time
+	run      TaskFunc
+}
+
+// activeTask is a task a worker is running.
+type activeTask struct {
+	queuedTask
+	deadline time.Time
+	cancel   context.CancelFunc
+	releases []func()
+	// over is set once the task returned or the watchdog gave up on it;
+	// abandoned only in the latter case.
+	over, abandoned bool
+}
+
+// TaskConfig configures the task queue. Concurrency, Timeouts and Retries
+// are per kind of task; kinds left out run one at a time, get Timeout and
+// are not retried.
+type TaskConfig struct {
+	Workers     int
+	Concurrency map[string]int
+	MaxWait     time.Duration
+	Timeout     time.Duration
+	Timeouts    map[string]time.Duration
+	Retries     map[string]int
+	// Alert is told of each task the watchdog gives up on, and of each
+	// task that panics.
+	Alert func(task domain.Task)
+}
+
+// TaskUsecase is the background task queue. Each kind of task has its own
+// queue, with a limit on how many of its tasks run at once, 1 unless
+// configured, so that a flood of one kind cannot take every worker.
+// Free workers take tasks started by users before scheduled ones, and
+// otherwise the oldest first; a scheduled task that has waited longer
+// than MaxWait goes before them all.
+//
+// A watchdog gives up on tasks that run past their timeout: it frees
+// what they hold, fails them or queues them again if they have retries
+// left, and starts a new worker in place of the one that is stuck.
+type TaskUsecase struct {
+	mu      sync.RWMutex
+	ready   *sync.Cond
+	tasks   []domain.Task
+	nextID  int
+	queued  []queuedTask
+	active  map[int]*activeTask
+	running map[string]int
+	config  TaskConfig
+	types   map[string]registeredTask
+}
+
+func NewTaskUsecase(config TaskConfig) *TaskUsecase {
+	u := &TaskUsecase{
+		tasks:   []domain.Task{},
+		nextID:  1,
+		active:  map[int]*activeTask{},
+		running: map[string]int{},
+		config:  config,
+		types:   map[string]registeredTask{},
+	}
+	u.ready = sync.NewCond(&u.mu)
+	return u
+}
+
+// RegisterTask registers a task type, to be queued by name with Submit.
+// Its payload is checked against the fields of the type, then decoded
+// into a P for run.
+func RegisterTask[P any](u *TaskUsecase, typ domain.TaskType, run func(p P, t *TaskTracker) any) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.types[typ.Name] = registeredTask{typ: typ, prepare: func(payload json.RawMessage) (TaskFunc, error) {
+		var p P
+		if len(payload) > 0 {
+			if err := json.Unmarshal(payload, &p); err != nil {
+				return nil, domain.Wrap(domain.ErrInvalid, err)
+			}
+		}
+		return func(t *TaskTracker) any { return run(p, t) }, nil
+	}}
+}
+
+// Types lists the registered task types by name.
+func (u *TaskUsecase) Types() []domain.TaskType {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	types := []domain.TaskType{}
+	for _, r := range u.types {
+		types = append(types, r.typ)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Submit queues a task of a registered type with its payload and returns
+// it.
+func (u *TaskUsecase) Submit(name, priority string, payload json.RawMessage) (domain.Task, error) {
+	u.mu.RLock()
+	r, ok := u.types[name]
+	u.mu.RUnlock()
+	if !ok {
+		return domain.Task{}, domain.NotFound("task type not found")
+	}
+	if err := r.typ.Validate(payload); err != nil {
+		return domain.Task{}, err
+	}
+	run, err := r.prepare(payload)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return u.enqueue(name, priority, 0, payload, run)
+}
+
+// Enqueue queues a task of total items and returns it.
+func (u *TaskUsecase) Enqueue(kind, priority string, total int, run TaskFunc) (domain.Task, error) {
+	return u.enqueue(kind, priority, total, nil, run)
+}
+
+func (u *TaskUsecase) enqueue(kind, priority string, total int, payload json.RawMessage, run TaskFunc) (domain.Task, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	waiting := 0
+	for _, q := range u.queued {
+		if q.kind == kind {
+			waiting++
+		}
+	}
+	if waiting >= taskQueueSize {
+		return domain.Task{}, ErrQueueFull
+	}
+
+	now := time.Now()
+	task := domain.Task{ID: u.nextID, Kind: kind, Priority: priority, Payload: payload, Status: domain.TaskQueued, QueuedAt: now, Total: total}
+	u.queued = append(u.queued, queuedTask{id: task.ID, kind: kind, priority: priority, queuedAt: now, run: run})
+	u.nextID++
+	u.tasks = append(u.tasks, task)
+	u.prune()
+	u.ready.Signal()
+	return task, nil
+}
+
+// Queued reports whether a task of kind is waiting for a worker, so that
+// a scheduled task need not be queued again while it waits.
+func (u *TaskUsecase) Queued(kind string) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return slices.ContainsFunc(u.queued, func(q queuedTask) bool { return q.kind == kind })
+}
+
+// Work runs queued tasks on the configured number of workers, and the
+// watchdog. It does not return.
+func (u *TaskUsecase) Work() {
+	for range u.config.Workers {
+		go u.work()
+	}
+	for now := range time.Tick(watchdogInterval) {
+		u.expire(now)
+	}
+}
+
+// Wait waits until a task has finished, or ctx is done, and returns it.
+func (u *TaskUsecase) Wait(ctx context.Context, id int) (domain.Task, error) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		task, err := u.GetTaskByID(id)
+		if err != nil || task.Finished() {
+			return task, err
+		}
+		select {
+		case <-ctx.Done():
+			return task, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// work runs tasks until the watchdog gives up on one it is running.
+func (u *TaskUsecase) work() {
+	for {
+		u.mu.Lock()
+		q, ok := u.next(time.Now())
+		for !ok {
+			u.ready.Wait()
+			q, ok = u.next(time.Now())
+		}
+		now := time.Now()
+		a := &activeTask{queuedTask: q, deadline: now.Add(u.timeout(q.kind))}
+		ctx, cancel := context.WithDeadline(context.Background(), a.deadline)
+		a.cancel = cancel
+		u.active[q.id] = a
+		u.running[q.kind]++
+		t := u.task(q.id)
+		t.Status = domain.TaskRunning
+		t.StartedAt = &now
+		t.Attempts++
+		appendLog(t, now, "Started, attempt %d", t.Attempts)
+		u.mu.Unlock()
+
+		result, panicked := runTask(q, &TaskTracker{u: u, id: q.id, ctx: ctx, active: a})
+		// Only the deadline can have ended the context before cancel.
+		timedOut := ctx.Err() != nil
+		cancel()
+
+		u.mu.Lock()
+		if a.abandoned {
+			// Another worker has taken this one's place.
+			u.mu.Unlock()
+			return
+		}
+		a.over = true
+		releases := a.releases
+		delete(u.active, q.id)
+		u.running[q.kind]--
+		finished := time.Now()
+		var failed *domain.Task
+		if timedOut {
+			// The task stopped when its context was cancelled.
+			t := u.giveUp(a, finished)
+			failed = &t
+		} else if t := u.task(q.id); t != nil && panicked != nil {
+			// A panic is a bug, so the task is not retried.
+			t.Status, t.FinishedAt = domain.TaskFailed, &finished
+			t.Error = fmt.Sprintf("panicked: %v", panicked)
+			appendLog(t, finished, "Panicked: %v", panicked)
+			f := *t
+			failed = &f
+		} else if t != nil {
+			t.Status = domain.TaskCompleted
+			t.FinishedAt = &finished
+			t.Result = result
+			t.Error = ""
+			appendLog(t, finished, "Completed in %s", finished.Sub(now).Round(time.Millisecond))
+		}
+		u.mu.Unlock()
+		for _, release := range releases {
+			release()
+		}
+		if failed != nil {
+			u.alert(*failed)
+		}
+		// A task of the same kind may have been held back by the limit.
+		u.ready.Broadcast()
+	}
+}
+
+// runTask runs a task, recovering a panic in it so that the worker and
+// the task's releases survive it. The panic is logged with its stack.
+func runTask(q queuedTask, t *TaskTracker) (result any, panicked any) {
+	defer func() {
+		if panicked = recover(); panicked != nil {
+			log.Printf("Task %d (%s) panicked: %v\n%s", q.id, q.kind, panicked, debug.Stack())
+		}
+	}()
+	return q.run(t), nil
+}
+
+// expire gives up on the tasks that have run past their timeout.
+func (u *TaskUsecase) expire(now time.Time) {
+	u.mu.Lock()
+	var releases []func()
+	var alerts []domain.Task
+	for id, a := range u.active {
+		if now.Before(a.deadline) {
+			continue
+		}
+		a.over, a.abandoned = true, true
+		a.cancel()
+		releases = append(releases, a.releases...)
+		delete(u.active, id)
+		u.running[a.kind]--
+		alerts = append(alerts, u.giveUp(a, now))
+	}
+	u.mu.Unlock()
+
+	for range alerts {
+		go u.work()
+	}
+	for _, release := range releases {
+		release()
+	}
+	for _, t := range alerts {
+		u.alert(t)
+	}
+	if len(alerts) > 0 {
+		u.ready.Broadcast()
+	}
+}
+
+// giveUp fails a task that ran past its timeout, or queues it again if it
+// has retries left, and returns it. It expects the caller to hold the
+// lock.
+func (u *TaskUsecase) giveUp(a *activeTask, now time.Time) domain.Task {
+	t := u.task(a.id)
+	t.Error = fmt.Sprintf("timed out after %s", u.timeout(a.kind))
+	if t.Attempts <= u.config.Retries[a.kind] {
+		t.Status, t.StartedAt = domain.TaskQueued, nil
+		a.queuedAt = now
+		u.queued = append(u.queued, a.queuedTask)
+		appendLog(t, now, "Timed out after %s, queued again", u.timeout(a.kind))
+	} else {
+		t.Status, t.FinishedAt = domain.TaskFailed, &now
+		appendLog(t, now, "Timed out after %s", u.timeout(a.kind))
+	}
+	return *t
+}
+
+func (u *TaskUsecase) alert(t domain.Task) {
+	if u.config.Alert != nil {
+		u.config.Alert(t)
+	}
+}
+
+// timeout is how long a task of kind may run. It does not need the lock.
+func (u *TaskUsecase) timeout(kind string) time.Duration {
+	if d, ok := u.config.Timeouts[kind]; ok {
+		return d
+	}
+	return u.config.Timeout
+}
+
+// next takes the task to run next off the queue, if any may run. It
+// expects the caller to hold the lock.
+func (u *TaskUsecase) next(now time.Time) (queuedTask, bool) {
+	best, bestRank := -1, 0
+	for i, q := range u.queued {
+		if u.running[q.kind] >= u.limit(q.kind) {
+			continue
+		}
+		rank := 1
+		switch {
+		case q.priority == domain.TaskPriorityUser:
+			rank = 0
+		case now.Sub(q.queuedAt) >= u.config.MaxWait:
+			rank = -1
+		}
+		// The queue is in the order tasks came, so the first of a rank is
+		// the oldest.
+		if best < 0 || rank < bestRank {
+			best, bestRank = i, rank
+		}
+	}
+	if best < 0 {
+		return queuedTask{}, false
+	}
+	q := u.queued[best]
+	u.queued = slices.Delete(u.queued, best, best+1)
+	return q, true
+}
+
+// limit is how many tasks of kind may run at once. It expects the caller
+// to hold the lock.
+func (u *TaskUsecase) limit(kind string) int {
+	if n, ok := u.config.Concurrency[kind]; ok {
+		return n
+	}
+	return 1
+}
+
+// Queues reports on the queue of each kind of task.
+func (u *TaskUsecase) Queues() []domain.TaskQueueStatus {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	kinds := slices.Clone(domain.TaskKinds)
+	for name := range u.types {
+		if !slices.Contains(kinds, name) {
+			kinds = append(kinds, name)
+		}
+	}
+	slices.Sort(kinds)
+
+	queues := []domain.TaskQueueStatus{}
+	for _, kind := range kinds {
+		s := domain.TaskQueueStatus{Kind: kind, Concurrency: u.limit(kind), Running: u.running[kind]}
+		for _, q := range u.queued {
+			if q.kind == kind {
+				s.Queued++
+			}
+		}
+		queues = append(queues, s)
+	}
+	return queues
+}
+
+// Counts counts the tasks remembered by status.
+func (u *TaskUsecase) Counts() domain.TaskCounts {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	var counts domain.TaskCounts
+	for _, t := range u.tasks {
+		switch t.Status {
+		case domain.TaskQueued:
+			counts.Queued++
+		case domain.TaskRunning:
+			counts.Running++
+		case domain.TaskCompleted:
+			counts.Completed++
+		case domain.TaskFailed:
+			counts.Failed++
+		}
+	}
+	return counts
+}
+
+func (u *TaskUsecase) GetTaskByID(id int) (domain.Task, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	for _, t := range u.tasks {
+		if t.ID == id {
+			t.Errors = slices.Clone(t.Errors)
+			t.Logs = slices.Clone(t.Logs)
+			return t, nil
+		}
+	}
+	return domain.Task{}, domain.NotFound("task not found")
+}
+
+// Logs returns the lines a task has logged after line after, with the
+// task, so the caller can tell whether more may follow.
+func (u *TaskUsecase) Logs(id, after int) ([]domain.TaskLogLine, domain.Task, error) {
+	task, err := u.GetTaskByID(id)
+	if err != nil {
+		return nil, task, err
+	}
+	lines := []domain.TaskLogLine{}
+	for _, l := range task.Logs {
+		if l.Line > after {
+			lines = append(lines, l)
+		}
+	}
+	return lines, task, nil
+}
+
+// Errors returns the errors of a completed task.
+func (u *TaskUsecase) Errors(id int) ([]domain.RowError, error) {
+	task, err := u.GetTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !task.Finished() {
+		return nil, ErrTaskNotFinished
+	}
+	return task.Errors, nil
+}
+
+func (u *TaskUsecase) update(id int, fn func(t *domain.Task)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if t := u.task(id); t != nil {
+		fn(t)
+	}
+}
+
+// task finds a task by ID. It expects the caller to hold the lock.
+func (u *TaskUsecase) task(id int) *domain.Task {
+	for i := range u.tasks {
+		if u.tasks[i].ID == id {
+			return &u.tasks[i]
+		}
+	}
+	return nil
+}
+
+// appendLog adds a line to the log of a task, dropping its oldest line
+// once it holds keptTaskLogLines. It expects the caller to hold the lock.
+func appendLog(t *domain.Task, at time.Time, format string, args ...any) {
+	line := 1
+	if n := len(t.Logs); n > 0 {
+		line = t.Logs[n-1].Line + 1
+	}
+	if len(t.Logs) >= keptTaskLogLines {
+		t.Logs = slices.Delete(t.Logs, 0, 1)
+	}
+	t.Logs = append(t.Logs, domain.TaskLogLine{Line: line, At: at, Message: fmt.Sprintf(format, args...)})
+}
+
+// prune forgets the oldest finished tasks beyond keptTasks. It expects
+// the caller to hold the lock.
+func (u *TaskUsecase) prune() {
+	for excess := len(u.tasks) - keptTasks; excess > 0; excess-- {
+		i := slices.IndexFunc(u.tasks, func(t domain.Task) bool { return t.Finished() })
+		if i < 0 {
+			return
+		}
+		u.tasks = slices.Delete(u.tasks, i, i+1)
+	}
+}
+
+// TaskTracker records the progress of a running task.
+type TaskTracker struct {
+	u      *TaskUsecase
+	id     int
+	ctx    context.Context
+	active *activeTask
+}
+
+// Context is cancelled once the task runs past its timeout, for tasks
+// that can stop early.
+func (t *TaskTracker) Context() context.Context {
+	return t.ctx
+}
+
+// OnRelease registers release to free what the task holds, such as a
+// lock. It runs once the task returns, or as soon as the watchdog gives
+// up on it if the task hangs.
+func (t *TaskTracker) OnRelease(release func()) {
+	t.u.mu.Lock()
+	over := t.active.over
+	if !over {
+		t.active.releases = append(t.active.releases, release)
+	}
+	t.u.mu.Unlock()
+	if over {
+		release()
+	}
+}
+
+// Logf adds a line to the task's log, and to the server log.
+func (t *TaskTracker) Logf(format string, args ...any) {
+	now := time.Now()
+	var kind string
+	t.u.update(t.id, func(task *domain.Task) {
+		kind = task.Kind
+		appendLog(task, now, format, args...)
+	})
+	log.Printf("Task %d (%s): %s", t.id, kind, fmt.Sprintf(format, args...))
+}
+
+// Done counts one item as processed.
+func (t *TaskTracker) Done() {
+	t.u.update(t.id, func(task *domain.Task) { task.Processed++ })
+}
+
+// Failed counts one item as processed with an error.
+func (t *TaskTracker) Failed(err domain.RowError) {
+	t.u.update(t.id, func(task *domain.Task) {
+		task.Processed++
+		task.ErrorCount++
+		task.Errors = append(task.Errors, err)
+	})
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/featureflag"
+)
+
+var ErrTemplateNotFound = domain.NotFound("template not found")
+
+// builtinTemplates word receipts until an admin sets templates of their
+// own. Emails and texts have none; without a template they send the
+// plain notification.
+var builtinTemplates = []domain.MessageTemplate{
+	{
+		Channel: domain.TemplateReceipt,
+		Kind:    domain.ReceiptCheckout,
+		Text: `{{.Library}}
+{{datetime .PrintedAt}}
+Card ending {{.Card}}
+
+{{range .Items}}{{.Title}}
+  {{.Author}}
+  Due {{date .DueAt}}
+{{end}}
+{{len .Items}} item(s). Thank you, {{.Name}}!
+`,
+		HTML: `<h1>{{.Library}}</h1>
+<p>{{datetime .PrintedAt}}<br>Card ending {{.Card}}</p>
+<table>{{range .Items}}
+<tr><td>{{.Title}}<br><small>{{.Author}}</small></td><td>Due {{date .DueAt}}</td></tr>{{end}}
+</table>
+<p>{{len .Items}} item(s). Thank you, {{.Name}}!</p>
+`,
+	},
+	{
+		Channel: domain.TemplateReceipt,
+		Kind:    domain.ReceiptHoldSlip,
+		Text: `HOLD FOR {{upper .Name}}
+Card ending {{.Card}}
+
+{{.Title}}
+  {{.Author}}
+
+Pick up by {{date .PickupBy}}
+`,
+		HTML: `<h1>Hold for {{.Name}}</h1>
+<p>Card ending {{.Card}}</p>
+<p><strong>{{.Title}}</strong><br>{{.Author}}</p>
+<p>Pick up by {{date .PickupBy}}</p>
+`,
+	},
+}
+
+// TemplateUsecase keeps the templates messages and receipts are worded
+// with. A tenant's own template wins over the default one, which wins
+// over the built-in one.
+type TemplateUsecase struct {
+	mu        sync.RWMutex
+	library   string
+	templates map[templateKey]domain.MessageTemplate
+}
+
+type templateKey struct {
+	tenant, channel, kind string
+}
+
+// NewTemplateUsecase returns the templates for a library, whose name
+// templates see as .Library.
+func NewTemplateUsecase(library string) *TemplateUsecase {
+	return &TemplateUsecase{library: library, templates: map[templateKey]domain.MessageTemplate{}}
+}
+
+// Library is the library's name.
+func (u *TemplateUsecase) Library() string {
+	return u.library
+}
+
+// GetTemplates returns the templates admins have set, for every tenant.
+func (u *TemplateUsecase) GetTemplates() []domain.MessageTemplate {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	templates := make([]domain.MessageTemplate, 0, len(u.templates))
+	for _, t := range u.templates {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		a, b := templates[i], templates[j]
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		if a.Channel != b.Channel {
+			return a.Channel < b.Channel
+		}
+		return a.Kind < b.Kind
+	})
+	return templates
+}
+
+// Template returns the template a tenant uses for a kind of message. It
+// returns ErrTemplateNotFound when there is none, i.e. the plain message
+// is sent.
+func (u *TemplateUsecase) Template(tenant, channel, kind string) (domain.MessageTemplate, error) {
+	tenant = featureflag.Tenant(tenant)
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	if t, ok := u.templates[templateKey{tenant, channel, kind}]; ok {
+		return t, nil
+	}
+	if t, ok := u.templates[templateKey{"", channel, kind}]; ok {
+		return t, nil
+	}
+	for _, t := range builtinTemplates {
+		if t.Channel == channel && t.Kind == kind {
+			return t, nil
+		}
+	}
+	return domain.MessageTemplate{}, ErrTemplateNotFound
+}
+
+// SetTemplate replaces a template, for the tenant it names or, when that
+// is empty, as the default.
+func (u *TemplateUsecase) SetTemplate(t domain.MessageTemplate) (domain.MessageTemplate, error) {
+	t.Tenant = featureflag.Tenant(t.Tenant)
+	if err := t.Validate(); err != nil {
+		return domain.MessageTemplate{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	now := time.Now()
+	t.UpdatedAt = &now
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.templates[templateKey{t.Tenant, t.Channel, t.Kind}] = t
+	return t, nil
+}
+
+// DeleteTemplate removes a template set by an admin, so the default or
+// built-in one applies again.
+func (u *TemplateUsecase) DeleteTemplate(tenant, channel, kind string) error {
+	key := templateKey{featureflag.Tenant(tenant), channel, kind}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.templates[key]; !ok {
+		return ErrTemplateNotFound
+	}
+	delete(u.templates, key)
+	return nil
+}
+
+// Preview renders a template against sample data without saving it.
+func (u *TemplateUsecase) Preview(t domain.MessageTemplate) (domain.RenderedMessage, error) {
+	if err := t.Validate(); err != nil {
+		return domain.RenderedMessage{}, domain.Wrap(domain.ErrInvalid, err)
+	}
+	return t.Render(u.Sample(t.Channel, t.Kind))
+}
+
+// Sample is the example data templates of a kind are previewed with,
+// showing the variables they can use.
+func (u *TemplateUsecase) Sample(channel, kind string) any {
+	return domain.TemplateSample(channel, kind, u.library)
+}
+
+// Render fills in the template a tenant uses for a kind of message.
+func (u *TemplateUsecase) Render(tenant, channel, kind string, data any) (domain.RenderedMessage, error) {
+	t, err := u.Template(tenant, channel, kind)
+	if err != nil {
+		return domain.RenderedMessage{}, err
+	}
+	return t.Render(data)
+}
+
+// RenderNotification words a notification for email or SMS with the
+// template the member's tenant uses for its kind, reporting false when
+// the kind has none.
+func (u *TemplateUsecase) RenderNotification(channel string, member domain.Member, n domain.Notification) (domain.RenderedMessage, bool, error) {
+	msg, err := u.Render(member.Tenant, channel, n.Kind, domain.NotificationTemplateData{
+		Library: u.library,
+		Name:    member.Name,
+		Kind:    n.Kind,
+		Title:   n.Title(),
+		Message: n.Message,
+	})
+	if errors.Is(err, ErrTemplateNotFound) {
+		return msg, false, nil
+	}
+	return msg, err == nil, err
+}
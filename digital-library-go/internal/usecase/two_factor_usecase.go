@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/totp"
+)
+
+var (
+	ErrTwoFactorStaffOnly   = domain.Forbidden("two-factor authentication is only available to staff accounts")
+	ErrTwoFactorEnabled     = domain.Conflict("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled = domain.Conflict("start two-factor enrollment first")
+	ErrInvalidTwoFactorCode = domain.Unauthorized("invalid two-factor code")
+)
+
+const recoveryCodeCount = 10
+
+type twoFactor struct {
+	secret   string
+	enabled  bool
+	recovery [][sha256.Size]byte
+	// lastStep is the time step of the last code accepted. Codes from it
+	// or earlier steps are refused so a code cannot be replayed.
+	lastStep int64
+}
+
+// TwoFactorUsecase manages TOTP enrollment for librarian and admin
+// accounts. Recovery codes are single use and stored hashed.
+type TwoFactorUsecase struct {
+	mu      sync.Mutex
+	states  map[int]*twoFactor
+	members *MemberUsecase
+	issuer  string
+}
+
+func NewTwoFactorUsecase(members *MemberUsecase, issuer string) *TwoFactorUsecase {
+	return &TwoFactorUsecase{
+		states:  map[int]*twoFactor{},
+		members: members,
+		issuer:  issuer,
+	}
+}
+
+// Enroll starts enrollment and returns the shared secret along with the
+// otpauth:// URI for authenticator apps. The second factor is not
+// enforced until Confirm succeeds.
+func (u *TwoFactorUsecase) Enroll(memberID int) (string, string, error) {
+	member, err := u.members.GetMemberByID(memberID)
+	if err != nil {
+		return "", "", err
+	}
+	if !member.IsStaff() {
+		return "", "", ErrTwoFactorStaffOnly
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if s, ok := u.states[memberID]; ok && s.enabled {
+		return "", "", ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return "", "", err
+	}
+	u.states[memberID] = &twoFactor{secret: secret}
+	return secret, totp.ProvisioningURI(u.issuer, member.CardNumber, secret), nil
+}
+
+// Confirm checks a code from the newly enrolled device, turns the second
+// factor on and returns the recovery codes. They are shown only once.
+func (u *TwoFactorUsecase) Confirm(memberID int, code string) ([]string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.states[memberID]
+	if !ok {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if s.enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	step, ok := totp.Match(s.secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	s.lastStep = step
+
+	codes := make([]string, recoveryCodeCount)
+	s.recovery = make([][sha256.Size]byte, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		codes[i] = hex.EncodeToString(buf)
+		s.recovery[i] = sha256.Sum256([]byte(codes[i]))
+	}
+	s.enabled = true
+	return codes, nil
+}
+
+func (u *TwoFactorUsecase) Enabled(memberID int) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.states[memberID]
+	return ok && s.enabled
+}
+
+// Verify accepts either a current TOTP code not used before or an unused
+// recovery code, which is consumed.
+func (u *TwoFactorUsecase) Verify(memberID int, code string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.states[memberID]
+	if !ok || !s.enabled {
+		return false
+	}
+	if step, ok := totp.Match(s.secret, code, time.Now()); ok {
+		if step <= s.lastStep {
+			return false
+		}
+		s.lastStep = step
+		return true
+	}
+
+	sum := sha256.Sum256([]byte(code))
+	for i, h := range s.recovery {
+		if subtle.ConstantTimeCompare(h[:], sum[:]) == 1 {
+			s.recovery = append(s.recovery[:i], s.recovery[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Reset removes the member's second factor so a locked-out user can log
+// in with their password and enroll again.
+func (u *TwoFactorUsecase) Reset(memberID int) error {
+	if _, err := u.members.GetMemberByID(memberID); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.states, memberID)
+	return nil
+}
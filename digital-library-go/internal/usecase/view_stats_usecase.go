@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// ViewRetention is how long daily view counts are kept.
+const ViewRetention = 400 * 24 * time.Hour
+
+const dayFormat = time.DateOnly
+
+// ErrInvalidRange is returned for a date range that runs backwards or
+// is longer than the retention period.
+var ErrInvalidRange = domain.Invalid("from must not be after to, nor more than 400 days before it")
+
+// ViewStatsUsecase counts detail-page views per book per day. Only the
+// counts are kept: nothing about who viewed a book is recorded, so the
+// series can be shared for collection development without exposing
+// members' reading habits.
+type ViewStatsUsecase struct {
+	mu    sync.RWMutex
+	daily map[int]map[string]int
+	books *BookUsecase
+}
+
+func NewViewStatsUsecase(books *BookUsecase) *ViewStatsUsecase {
+	return &ViewStatsUsecase{
+		daily: map[int]map[string]int{},
+		books: books,
+	}
+}
+
+func (u *ViewStatsUsecase) Record(bookID int, at time.Time) {
+	day := at.UTC().Format(dayFormat)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.daily[bookID] == nil {
+		u.daily[bookID] = map[string]int{}
+	}
+	u.daily[bookID][day]++
+}
+
+// Series returns one entry per day from from to to inclusive, with zero
+// for days without views.
+func (u *ViewStatsUsecase) Series(bookID int, from, to time.Time) ([]domain.DailyViews, error) {
+	if from.After(to) || to.Sub(from) > ViewRetention {
+		return nil, ErrInvalidRange
+	}
+	if _, err := u.books.GetBookByID(bookID); err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	series := []domain.DailyViews{}
+	for d := from.UTC().Truncate(24 * time.Hour); !d.After(to.UTC()); d = d.AddDate(0, 0, 1) {
+		day := d.Format(dayFormat)
+		series = append(series, domain.DailyViews{Date: day, Views: u.daily[bookID][day]})
+	}
+	return series, nil
+}
+
+// MostViewed totals the views of every book from from to to inclusive,
+// most viewed first. Books deleted since are left out.
+func (u *ViewStatsUsecase) MostViewed(from, to time.Time, limit int) ([]domain.BookViews, error) {
+	if from.After(to) {
+		return nil, ErrInvalidRange
+	}
+	first, last := from.UTC().Format(dayFormat), to.UTC().Format(dayFormat)
+
+	u.mu.RLock()
+	totals := map[int]int{}
+	for id, days := range u.daily {
+		for day, n := range days {
+			if day >= first && day <= last {
+				totals[id] += n
+			}
+		}
+	}
+	u.mu.RUnlock()
+
+	ranked := []domain.BookViews{}
+	for id, n := range totals {
+		book, err := u.books.GetBookByID(id)
+		if err != nil {
+			continue
+		}
+		ranked = append(ranked, domain.BookViews{BookID: id, Title: book.Title, Views: n})
+	}
+	slices.SortFunc(ranked, func(a, b domain.BookViews) int {
+		if a.Views != b.Views {
+			return b.Views - a.Views
+		}
+		return a.BookID - b.BookID
+	})
+	return ranked[:min(limit, len(ranked))], nil
+}
+
+// Prune drops the counts of days older than ViewRetention.
+func (u *ViewStatsUsecase) Prune(now time.Time) {
+	cutoff := now.Add(-ViewRetention).UTC().Format(dayFormat)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for id, days := range u.daily {
+		for day := range days {
+			if day < cutoff {
+				delete(days, day)
+			}
+		}
+		if len(days) == 0 {
+			delete(u.daily, id)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// WeedingUsecase finds the copies worth withdrawing from the collection:
+// old books that hardly circulate any more.
+type WeedingUsecase struct {
+	books  *BookUsecase
+	copies *CopyUsecase
+	loans  *LoanUsecase
+}
+
+func NewWeedingUsecase(books *BookUsecase, copies *CopyUsecase, loans *LoanUsecase) *WeedingUsecase {
+	return &WeedingUsecase{books: books, copies: copies, loans: loans}
+}
+
+// Report lists the published books that meet the criteria and still have
+// copies in circulation. Books added to the catalog within the period
+// have not had the chance to circulate and are left out.
+func (u *WeedingUsecase) Report(criteria domain.WeedingCriteria, now time.Time) domain.WeedingReport {
+	since := now.AddDate(0, -criteria.Months, 0)
+
+	loans := map[int]int{}
+	last := map[int]time.Time{}
+	for _, l := range u.loans.GetLoans() {
+		if l.LoanedAt.After(last[l.BookID]) {
+			last[l.BookID] = l.LoanedAt
+		}
+		if !l.LoanedAt.Before(since) {
+			loans[l.BookID]++
+		}
+	}
+
+	active := map[int][]domain.Copy{}
+	for _, c := range u.copies.GetCopies() {
+		if !c.Withdrawn() {
+			active[c.BookID] = append(active[c.BookID], c)
+		}
+	}
+
+	report := domain.WeedingReport{Criteria: criteria, Since: since, Candidates: []domain.WeedingCandidate{}}
+	for _, b := range u.books.GetBooks() {
+		age := now.Year() - b.Year
+		if !b.Published() || len(active[b.ID]) == 0 || age < criteria.MinAge ||
+			b.AddedAt.After(since) || loans[b.ID] > criteria.MaxLoans {
+			continue
+		}
+		candidate := domain.WeedingCandidate{
+			BookID: b.ID,
+			Title:  b.Title,
+			Author: b.Author,
+			Year:   b.Year,
+			Age:    age,
+			Loans:  loans[b.ID],
+			Copies: active[b.ID],
+		}
+		if t, ok := last[b.ID]; ok {
+			candidate.LastLoanedAt = &t
+		}
+		report.Candidates = append(report.Candidates, candidate)
+		report.CopiesTotal += len(candidate.Copies)
+	}
+	slices.SortFunc(report.Candidates, func(a, b domain.WeedingCandidate) int {
+		return cmp.Or(cmp.Compare(a.Loans, b.Loans), cmp.Compare(b.Age, a.Age), cmp.Compare(a.BookID, b.BookID))
+	})
+	return report
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// WorklistUsecase builds the lists of items staff have to fetch from the
+// shelves.
+type WorklistUsecase struct {
+	books   *BookUsecase
+	copies  *CopyUsecase
+	holds   *HoldUsecase
+	loans   *LoanUsecase
+	members *MemberUsecase
+}
+
+func NewWorklistUsecase(books *BookUsecase, copies *CopyUsecase, holds *HoldUsecase, loans *LoanUsecase, members *MemberUsecase) *WorklistUsecase {
+	return &WorklistUsecase{books: books, copies: copies, holds: holds, loans: loans, members: members}
+}
+
+// PullList returns one item per book that has holds and is on the shelf,
+// for the member first in its queue, sorted by location and call number,
+// which is the order they stand on the shelves. Books
+// on loan are left out: their copy is pulled when it comes back.
+func (u *WorklistUsecase) PullList() []domain.PullItem {
+	onLoan := u.loans.BooksOnLoan()
+	first := map[int]domain.Hold{}
+	for _, h := range u.holds.GetHolds() {
+		if onLoan[h.BookID] {
+			continue
+		}
+		if current, ok := first[h.BookID]; !ok || h.PlacedAt.Before(current.PlacedAt) {
+			first[h.BookID] = h
+		}
+	}
+
+	items := []domain.PullItem{}
+	for bookID, hold := range first {
+		book, err := u.books.GetBookByID(bookID)
+		if err != nil {
+			continue
+		}
+		item := domain.PullItem{
+			BookID:   bookID,
+			Title:    book.Title,
+			HoldID:   hold.ID,
+			MemberID: hold.MemberID,
+			PlacedAt: hold.PlacedAt,
+		}
+		if member, err := u.members.GetMemberByID(hold.MemberID); err == nil {
+			item.MemberName = member.Name
+		}
+		if copies, err := u.copies.CopiesForBook(bookID); err == nil {
+			if i := slices.IndexFunc(copies, func(c domain.Copy) bool { return !c.Withdrawn() }); i >= 0 {
+				item.Barcode = copies[i].Barcode
+				item.Location = copies[i].Location
+				item.CallNumber = copies[i].CallNumber
+			}
+		}
+		items = append(items, item)
+	}
+	slices.SortFunc(items, func(a, b domain.PullItem) int {
+		return cmp.Or(
+			cmp.Compare(a.Location, b.Location),
+			domain.CompareCallNumbers(a.CallNumber, b.CallNumber),
+			cmp.Compare(a.Title, b.Title),
+			cmp.Compare(a.BookID, b.BookID),
+		)
+	})
+	return items
+}
@@ -0,0 +1,216 @@
+// Package webpush sends Web Push messages (RFC 8030) to browsers,
+// encrypted for the subscription (RFC 8291) and signed with the server's
+// VAPID key (RFC 8292).
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdebopriya/fastapi-digital-library/digital-library-go/internal/domain"
+)
+
+// recordSize is the aes128gcm record size. Push services accept at most
+// 4096 bytes of payload, so messages always fit in one record.
+const recordSize = 4096
+
+// ttl is how long a push service keeps a message for an offline browser.
+const ttl = 24 * time.Hour
+
+// Sender delivers push messages on behalf of the application server
+// identified by its VAPID key.
+type Sender struct {
+	key     *ecdsa.PrivateKey
+	subject string
+	client  *http.Client
+}
+
+// NewSender signs with the VAPID private key, given as a base64url P-256
+// scalar as printed by most web-push tools. Without one, a key is
+// generated and browsers must subscribe again after a restart. subject
+// is a mailto: or https: contact for push services. Pass a client from a
+// circuit breaker so outages fail fast.
+func NewSender(privateKey, subject string, client *http.Client) (*Sender, error) {
+	var key *ecdsa.PrivateKey
+	var err error
+	if privateKey == "" {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		var raw []byte
+		if raw, err = base64.RawURLEncoding.DecodeString(privateKey); err == nil {
+			key, err = ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid VAPID key: %w", err)
+	}
+	// Push services answer directly; following a redirect would let one
+	// point the server at another host.
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &Sender{key: key, subject: subject, client: &noRedirects}, nil
+}
+
+// PublicKey is the applicationServerKey browsers subscribe with, in
+// base64url.
+func (s *Sender) PublicKey() string {
+	pub, _ := s.key.PublicKey.Bytes()
+	return base64.RawURLEncoding.EncodeToString(pub)
+}
+
+// Push encrypts payload for the subscription and posts it to its push
+// service. It returns domain.ErrSubscriptionGone when the browser has
+// unsubscribed.
+func (s *Sender) Push(ctx context.Context, sub domain.PushSubscription, payload []byte) error {
+	body, err := encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	auth, err := s.authorization(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return domain.ErrSubscriptionGone
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("webpush: push service returned %s", resp.Status)
+	}
+	return nil
+}
+
+// authorization builds the VAPID header: an ES256 JWT for the push
+// service's origin plus the public key it can be checked with.
+func (s *Sender) authorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": s.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sig.FillBytes(signature[32:])
+
+	return fmt.Sprintf("vapid t=%s.%s, k=%s", unsigned, enc.EncodeToString(signature), s.PublicKey()), nil
+}
+
+// encrypt seals payload in a single aes128gcm record with a key agreed
+// between a fresh ephemeral key and the browser's p256dh key.
+func encrypt(sub domain.PushSubscription, payload []byte) ([]byte, error) {
+	browserKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(sub.Keys.P256dh, "="))
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(sub.Keys.Auth, "="))
+	if err != nil {
+		return nil, err
+	}
+	// The whole body must fit in 4096 bytes: an 86-byte header, the
+	// payload, its delimiter and a 16-byte tag.
+	if len(payload) > recordSize-86-1-16 {
+		return nil, errors.New("webpush: payload too large")
+	}
+
+	browser, err := ecdh.P256().NewPublicKey(browserKey)
+	if err != nil {
+		return nil, err
+	}
+	local, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := local.ECDH(browser)
+	if err != nil {
+		return nil, err
+	}
+	localKey := local.PublicKey().Bytes()
+
+	keyInfo := "WebPush: info\x00" + string(browserKey) + string(localKey)
+	ikm, err := hkdf.Key(sha256.New, shared, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt, record size, key ID length and the ephemeral public
+	// key as key ID. The 0x02 delimiter marks the last record.
+	header := make([]byte, 0, 16+4+1+len(localKey))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(localKey)))
+	header = append(header, localKey...)
+	return gcm.Seal(header, nonce, append(slices.Clip(payload), 0x02), nil), nil
+}